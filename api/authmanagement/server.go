@@ -14,8 +14,10 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/ee/integrations/vault"
 	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/encryption/kms"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	"github.com/porter-dev/porter/internal/repository/gorm"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -67,7 +69,12 @@ func NewService() (AuthManagementService, error) {
 	}
 
 	var instanceCredentialBackend credentials.CredentialStorage
-	if envVars.DBEnv.VaultEnabled {
+	if envVars.DBEnv.CredentialStorageBackend != "" {
+		instanceCredentialBackend, err = secretstore.NewCredentialStorage(context.Background(), &envVars.DBEnv)
+		if err != nil {
+			return server, fmt.Errorf("failed to create credential storage backend: %w", err)
+		}
+	} else if envVars.DBEnv.VaultEnabled {
 		instanceCredentialBackend = vault.NewClient(
 			envVars.DBEnv.VaultServerURL,
 			envVars.DBEnv.VaultAPIKey,
@@ -81,6 +88,10 @@ func NewService() (AuthManagementService, error) {
 		key[i] = b
 	}
 
+	if err := kms.SetKeyProviderFromConf(context.Background(), &envVars.DBEnv); err != nil {
+		return server, fmt.Errorf("failed to set encryption key provider: %w", err)
+	}
+
 	repo := gorm.NewRepository(db, &key, instanceCredentialBackend)
 
 	server.Config = Config{
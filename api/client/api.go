@@ -156,7 +156,15 @@ func withRetryCount(retryCount uint) func(*getRequestConfig) {
 // getRequest makes a GET request to the API
 func (c *Client) getRequest(relPath string, data interface{}, response interface{}, opts ...func(*getRequestConfig)) error {
 	vals := make(map[string][]string)
-	_ = schema.NewEncoder().Encode(data, vals)
+
+	encoder := schema.NewEncoder()
+
+	// handle encoding of timestamps, keeping sub-second precision so that paginated requests can continue where they left off
+	encoder.RegisterEncoder(time.Time{}, func(t reflect.Value) string {
+		return t.Interface().(time.Time).Format(time.RFC3339Nano)
+	})
+
+	_ = encoder.Encode(data, vals)
 	var err error
 
 	urlVals := url.Values(vals)
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// ListAuditLogs retrieves a page of the audit logs of a project matching the filters in the request
+func (c *Client) ListAuditLogs(
+	ctx context.Context,
+	projectID uint,
+	req *types.ListAuditLogsRequest,
+) (*types.ListAuditLogsResponse, error) {
+	resp := &types.ListAuditLogsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/audit_logs", projectID),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
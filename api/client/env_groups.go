@@ -33,6 +33,8 @@ type UpdateEnvGroupInput struct {
 	Secrets       map[string]string
 	Deletions     environment_groups.EnvVariableDeletions
 	SkipRedeploys bool
+	// AutoRollout enables or disables the auto rollout of the env group to its linked apps. If nil, the setting is unchanged
+	AutoRollout *bool
 }
 
 // UpdateEnvGroup creates or updates an environment group with the provided variables
@@ -46,6 +48,7 @@ func (c *Client) UpdateEnvGroup(
 		SecretVariables:   inp.Secrets,
 		Deletions:         inp.Deletions,
 		SkipAppAutoDeploy: inp.SkipRedeploys,
+		AutoRollout:       inp.AutoRollout,
 	}
 
 	return c.postRequest(
@@ -54,3 +57,69 @@ func (c *Client) UpdateEnvGroup(
 		nil,
 	)
 }
+
+// GetEnvGroupHistory lists all versions of an environment group
+func (c *Client) GetEnvGroupHistory(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+) (*environment_groups.EnvGroupHistoryResponse, error) {
+	resp := &environment_groups.EnvGroupHistoryResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/versions", projID, clusterID, envGroupName),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetEnvGroupDiff compares two versions of an environment group. If a version is 0, the server picks the latest version and the one before it
+func (c *Client) GetEnvGroupDiff(
+	ctx context.Context,
+	projID, clusterID uint,
+	envGroupName string,
+	fromVersion, toVersion int,
+) (*environment_groups.EnvGroupDiffResponse, error) {
+	resp := &environment_groups.EnvGroupDiffResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/diff", projID, clusterID, envGroupName),
+		&environment_groups.EnvGroupDiffRequest{
+			FromVersion: fromVersion,
+			ToVersion:   toVersion,
+		},
+		resp,
+	)
+
+	return resp, err
+}
+
+// RollbackEnvGroupInput is the input for the RollbackEnvGroup method
+type RollbackEnvGroupInput struct {
+	ProjectID     uint
+	ClusterID     uint
+	EnvGroupName  string
+	Version       int
+	SkipRedeploys bool
+}
+
+// RollbackEnvGroup restores a previous version of an environment group as a new version
+func (c *Client) RollbackEnvGroup(
+	ctx context.Context,
+	inp RollbackEnvGroupInput,
+) (*environment_groups.RollbackEnvGroupResponse, error) {
+	resp := &environment_groups.RollbackEnvGroupResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/environment-groups/%s/rollback", inp.ProjectID, inp.ClusterID, inp.EnvGroupName),
+		&environment_groups.RollbackEnvGroupRequest{
+			Version:           inp.Version,
+			SkipAppAutoDeploy: inp.SkipRedeploys,
+		},
+		resp,
+	)
+
+	return resp, err
+}
@@ -272,6 +272,13 @@ type AppLogsInput struct {
 	ServiceName          string
 	DeploymentTargetName string
 	StartRange           time.Time
+	EndRange             time.Time
+	SearchParam          string
+	AppRevisionID        string
+	PodName              string
+	// Direction is either "forward" or "backward". Defaults to "backward"
+	Direction string
+	Limit     uint
 }
 
 // AppLogs gets logs for an app
@@ -285,6 +292,12 @@ func (c *Client) AppLogs(
 		ServiceName:          inp.ServiceName,
 		DeploymentTargetName: inp.DeploymentTargetName,
 		StartRange:           inp.StartRange,
+		EndRange:             inp.EndRange,
+		SearchParam:          inp.SearchParam,
+		AppRevisionID:        inp.AppRevisionID,
+		PodName:              inp.PodName,
+		Direction:            inp.Direction,
+		Limit:                inp.Limit,
 	}
 
 	err := c.getRequest(
@@ -307,6 +320,9 @@ func (c *Client) AppLogsStream(
 	req := &porter_app.AppLogsRequest{
 		ServiceName:          inp.ServiceName,
 		DeploymentTargetName: inp.DeploymentTargetName,
+		StartRange:           inp.StartRange,
+		SearchParam:          inp.SearchParam,
+		AppRevisionID:        inp.AppRevisionID,
 	}
 
 	conn, err := c.websocketDial(
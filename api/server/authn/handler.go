@@ -3,6 +3,7 @@ package authn
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/models"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // AuthNFactory generates a middleware handler `AuthN`
@@ -189,6 +191,14 @@ func (authn *AuthN) verifyTokenWithNext(w http.ResponseWriter, r *http.Request,
 			return
 		}
 
+		// then ensure that the secret is current, or was replaced by a rotation within its overlap window
+		if !isAPITokenSecretValid(apiToken, tok.Secret) {
+			authn.sendForbiddenError(fmt.Errorf("token with id %s has an invalid secret", tok.TokenID), w, r)
+			return
+		}
+
+		authn.recordAPITokenUse(r, apiToken)
+
 		authn.nextWithAPIToken(w, r, apiToken)
 	} else {
 		// otherwise we just use nextWithUser using the `iby` field for the token
@@ -196,6 +206,61 @@ func (authn *AuthN) verifyTokenWithNext(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// isAPITokenSecretValid checks the secret of a token against its stored hash. Tokens which were issued
+// without a secret, such as the tokens of the porter agent, are only checked against the database.
+func isAPITokenSecretValid(apiToken *models.APIToken, secret string) bool {
+	if len(apiToken.SecretKey) == 0 {
+		return true
+	}
+
+	if bcrypt.CompareHashAndPassword(apiToken.SecretKey, []byte(secret)) == nil {
+		return true
+	}
+
+	return apiToken.IsPreviousSecretValid() && bcrypt.CompareHashAndPassword(apiToken.PreviousSecretKey, []byte(secret)) == nil
+}
+
+// apiTokenLastUsedInterval is how often the last use of a token is written to the database for requests
+// from the same ip
+const apiTokenLastUsedInterval = time.Minute
+
+// recordAPITokenUse stores the time and client ip of the request as the last use of the token. Errors are
+// logged rather than failing the request.
+func (authn *AuthN) recordAPITokenUse(r *http.Request, apiToken *models.APIToken) {
+	now := time.Now().UTC()
+	ip := clientIP(r)
+
+	if apiToken.LastUsedAt != nil && apiToken.LastUsedIP == ip && now.Sub(*apiToken.LastUsedAt) < apiTokenLastUsedInterval {
+		return
+	}
+
+	apiToken.LastUsedAt = &now
+	apiToken.LastUsedIP = ip
+
+	if err := authn.config.Repo.APIToken().UpdateAPITokenLastUsed(apiToken); err != nil {
+		authn.config.Logger.Error().Err(err).Str("token_id", apiToken.UniqueID).Msg("error recording api token use")
+	}
+}
+
+// clientIP returns the ip of the client of the request, as reported by the load balancer in front of the
+// server if there is one. The load balancer appends the address it received the request from to
+// X-Forwarded-For, so only the last hop is used: earlier hops are set by the client and can be spoofed.
+func clientIP(r *http.Request) string {
+	if forwardedFor := r.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		hops := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+		if client := strings.TrimSpace(hops[len(hops)-1]); client != "" {
+			return client
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
 // nextWithAPIToken sets the token in context
 func (authn *AuthN) nextWithAPIToken(w http.ResponseWriter, r *http.Request, tok *models.APIToken) {
 	ctx := r.Context()
@@ -5,13 +5,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/porter-dev/porter/api/server/authn"
 	"github.com/porter-dev/porter/api/server/shared/apitest"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -189,6 +192,86 @@ func TestAuthBadSessionUserIDType(t *testing.T) {
 	assertForbiddenError(t, next, rr)
 }
 
+func TestAuthenticatedAPITokenRecordsLastUse(t *testing.T) {
+	config, handler, next := loadHandlers(t)
+
+	apiToken := createTestAPIToken(t, config, "secret")
+
+	req := newAPITokenRequest(t, config, apiToken, "secret")
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.7")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.True(t, next.WasCalled, "next handler should have been called")
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+
+	stored, err := config.Repo.APIToken().ReadAPIToken(apiToken.ProjectID, apiToken.UniqueID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotNil(t, stored.LastUsedAt, "last use should be recorded")
+	assert.Equal(t, "203.0.113.7", stored.LastUsedIP, "last used ip should be the hop appended by the load balancer")
+}
+
+func TestAuthenticatedAPITokenRecordsRemoteAddr(t *testing.T) {
+	config, handler, next := loadHandlers(t)
+
+	apiToken := createTestAPIToken(t, config, "secret")
+
+	req := newAPITokenRequest(t, config, apiToken, "secret")
+	req.RemoteAddr = "203.0.113.7:41234"
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.True(t, next.WasCalled, "next handler should have been called")
+
+	stored, err := config.Repo.APIToken().ReadAPIToken(apiToken.ProjectID, apiToken.UniqueID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "203.0.113.7", stored.LastUsedIP, "last used ip should be the remote address without a load balancer")
+}
+
+func TestAPITokenWithWrongSecret(t *testing.T) {
+	config, handler, next := loadHandlers(t)
+
+	apiToken := createTestAPIToken(t, config, "secret")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPITokenRequest(t, config, apiToken, "wrong-secret"))
+
+	assertForbiddenError(t, next, rr)
+}
+
+func TestAPITokenPreviousSecretWithinOverlap(t *testing.T) {
+	config, handler, next := loadHandlers(t)
+
+	apiToken := createTestAPIToken(t, config, "new-secret")
+	rotateTestAPIToken(t, config, apiToken, "old-secret", time.Now().Add(time.Hour))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPITokenRequest(t, config, apiToken, "old-secret"))
+
+	assert.True(t, next.WasCalled, "next handler should have been called")
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+}
+
+func TestAPITokenPreviousSecretAfterOverlap(t *testing.T) {
+	config, handler, next := loadHandlers(t)
+
+	apiToken := createTestAPIToken(t, config, "new-secret")
+	rotateTestAPIToken(t, config, apiToken, "old-secret", time.Now().Add(-time.Minute))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPITokenRequest(t, config, apiToken, "old-secret"))
+
+	assertForbiddenError(t, next, rr)
+}
+
 type testHandler struct {
 	WasCalled bool
 	User      *models.User
@@ -246,3 +329,62 @@ func assertNextHandlerCalled(
 	assert.Equal(expUser, next.User, "user should be equal")
 	assert.Equal(http.StatusOK, rr.Result().StatusCode, "status code should be ok")
 }
+
+func createTestAPIToken(t *testing.T, config *config.Config, secret string) *models.APIToken {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiry := time.Now().Add(time.Hour)
+
+	apiToken, err := config.Repo.APIToken().CreateAPIToken(&models.APIToken{
+		UniqueID:        "test-token",
+		ProjectID:       1,
+		CreatedByUserID: 1,
+		Expiry:          &expiry,
+		Name:            "test-token",
+		SecretKey:       hashed,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return apiToken
+}
+
+// rotateTestAPIToken stores the hash of the given secret as the previous secret of the token
+func rotateTestAPIToken(t *testing.T, config *config.Config, apiToken *models.APIToken, previousSecret string, previousSecretExpiry time.Time) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(previousSecret), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiToken.PreviousSecretKey = hashed
+	apiToken.PreviousSecretExpiry = &previousSecretExpiry
+
+	if _, err := config.Repo.APIToken().UpdateAPIToken(apiToken); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newAPITokenRequest(t *testing.T, config *config.Config, apiToken *models.APIToken, secret string) *http.Request {
+	req, err := http.NewRequest("GET", "/auth-endpoint", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := token.GetStoredTokenForAPI(apiToken.CreatedByUserID, apiToken.ProjectID, apiToken.UniqueID, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenStr, err := tok.EncodeToken(config.TokenConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+
+	return req
+}
@@ -0,0 +1,94 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// CheckAPITokenDeploymentTarget returns a forbidden error if the request was authenticated with an api token
+// which is restricted to other deployment targets than the one with the given id or name. Requests which do
+// not name a deployment target are forbidden for restricted tokens, since they act on the default target.
+func CheckAPITokenDeploymentTarget(
+	ctx context.Context,
+	repo repository.Repository,
+	projectID uint,
+	deploymentTargetIdentifier string,
+) apierrors.RequestError {
+	apiToken, _ := ctx.Value("api_token").(*models.APIToken)
+	if apiToken == nil || len(apiToken.DeploymentTargetIDList()) == 0 {
+		return nil
+	}
+
+	if deploymentTargetIdentifier == "" {
+		return apierrors.NewErrForbidden(
+			fmt.Errorf("api token %s is restricted to specific deployment targets, which must be set on the request", apiToken.UniqueID),
+		)
+	}
+
+	deploymentTarget, err := repo.DeploymentTarget().DeploymentTarget(projectID, deploymentTargetIdentifier)
+	if err != nil || deploymentTarget.ID == uuid.Nil {
+		return apierrors.NewErrForbidden(
+			fmt.Errorf("deployment target %s of api token %s not found", deploymentTargetIdentifier, apiToken.UniqueID),
+		)
+	}
+
+	if !apiToken.AllowsDeploymentTarget(deploymentTarget.ID.String()) {
+		return apierrors.NewErrForbidden(
+			fmt.Errorf("api token %s cannot access deployment target %s", apiToken.UniqueID, deploymentTargetIdentifier),
+		)
+	}
+
+	return nil
+}
+
+// CheckAPITokenApp returns a forbidden error if the request was authenticated with an api token which is
+// restricted to other apps than the one with the given name
+func CheckAPITokenApp(ctx context.Context, appName string) apierrors.RequestError {
+	apiToken, _ := ctx.Value("api_token").(*models.APIToken)
+	if apiToken == nil || apiToken.AllowsApp(appName) {
+		return nil
+	}
+
+	return apierrors.NewErrForbidden(
+		fmt.Errorf("api token %s cannot access app %s", apiToken.UniqueID, appName),
+	)
+}
+
+// checkAPITokenRestrictions checks the deployment target and app of the request against the restrictions of
+// its api token. The deployment target scope of deploy routes is read from the request body, and defaults to
+// the default deployment target of the cluster, and requests which act on app instances are checked once for the
+// deployment target of each instance. Handlers which read the deployment target from elsewhere, or
+// the app from the request body, check them with CheckAPITokenDeploymentTarget and CheckAPITokenApp.
+func checkAPITokenRestrictions(
+	ctx context.Context,
+	repo repository.Repository,
+	r *http.Request,
+	projectID uint,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) apierrors.RequestError {
+	if action, ok := reqScopes[types.DeploymentTargetScope]; ok {
+		if reqErr := CheckAPITokenDeploymentTarget(ctx, repo, projectID, action.Resource.Name); reqErr != nil {
+			return reqErr
+		}
+	} else if deploymentTargetID := r.URL.Query().Get("deployment_target_id"); deploymentTargetID != "" {
+		if reqErr := CheckAPITokenDeploymentTarget(ctx, repo, projectID, deploymentTargetID); reqErr != nil {
+			return reqErr
+		}
+	}
+
+	if appName := chi.URLParam(r, string(types.URLParamPorterAppName)); appName != "" {
+		if reqErr := CheckAPITokenApp(ctx, appName); reqErr != nil {
+			return reqErr
+		}
+	}
+
+	return nil
+}
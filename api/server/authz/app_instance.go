@@ -0,0 +1,109 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// getAppInstanceIDs returns the app_instance_ids field of the request body, which routes acting on several apps
+// at once, such as attaching an env group, use instead of naming a single app and deployment target
+func getAppInstanceIDs(r *http.Request) ([]string, apierrors.RequestError) {
+	if r.Method == http.MethodGet {
+		return nil, nil
+	}
+
+	body, reqErr := readRequestBody(r)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	var request struct {
+		AppInstanceIDs []string `json:"app_instance_ids"`
+	}
+
+	// malformed bodies are rejected by the handler when it decodes the request
+	_ = json.Unmarshal(body, &request)
+
+	return request.AppInstanceIDs, nil
+}
+
+// withAppInstanceDeploymentTargets returns the request scopes for each app instance of a request which acts on app
+// instances, with the deployment target of the instance. Instances must belong to the project and cluster of the
+// request, and to the apps of its api token if the token is restricted to specific apps. It returns nil for
+// requests which name a deployment target or don't act on app instances.
+func (h *PolicyHandler) withAppInstanceDeploymentTargets(
+	ctx context.Context,
+	r *http.Request,
+	projectID uint,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) ([]map[types.PermissionScope]*types.RequestAction, apierrors.RequestError) {
+	action, ok := reqScopes[types.DeploymentTargetScope]
+	if !ok || action.Resource.Name != "" {
+		return nil, nil
+	}
+
+	appInstanceIDs, reqErr := getAppInstanceIDs(r)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	if len(appInstanceIDs) == 0 {
+		return nil, nil
+	}
+
+	var clusterID uint
+	if clusterAction, ok := reqScopes[types.ClusterScope]; ok {
+		clusterID = clusterAction.Resource.UInt
+	}
+
+	res := make([]map[types.PermissionScope]*types.RequestAction, 0, len(appInstanceIDs))
+
+	for _, appInstanceID := range appInstanceIDs {
+		if _, err := uuid.Parse(appInstanceID); err != nil {
+			return nil, apierrors.NewErrPassThroughToClient(fmt.Errorf("invalid app instance id %s", appInstanceID), http.StatusBadRequest)
+		}
+
+		appInstance, err := h.config.Repo.AppInstance().Get(ctx, projectID, appInstanceID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apierrors.NewErrForbidden(fmt.Errorf("app instance %s not found in project %d", appInstanceID, projectID))
+			}
+
+			return nil, apierrors.NewErrInternal(err)
+		}
+
+		if reqErr := CheckAPITokenApp(ctx, appInstance.Name); reqErr != nil {
+			return nil, reqErr
+		}
+
+		deploymentTarget, err := h.config.Repo.DeploymentTarget().DeploymentTarget(projectID, appInstance.DeploymentTargetID.String())
+		if err != nil || deploymentTarget.ID == uuid.Nil || uint(deploymentTarget.ClusterID) != clusterID {
+			return nil, apierrors.NewErrForbidden(fmt.Errorf("app instance %s not found in cluster %d", appInstanceID, clusterID))
+		}
+
+		scopes := make(map[types.PermissionScope]*types.RequestAction, len(reqScopes))
+
+		for scope, action := range reqScopes {
+			scopes[scope] = action
+		}
+
+		scopes[types.DeploymentTargetScope] = &types.RequestAction{
+			Verb: action.Verb,
+			Resource: types.NameOrUInt{
+				Name: deploymentTarget.ID.String(),
+			},
+		}
+
+		res = append(res, scopes)
+	}
+
+	return res, nil
+}
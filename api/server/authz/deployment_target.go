@@ -1,13 +1,19 @@
 package authz
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
 	"gorm.io/gorm"
@@ -45,7 +51,17 @@ func (p *DeploymentTargetScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *h
 	reqScopes, _ := r.Context().Value(types.RequestScopeCtxKey).(map[types.PermissionScope]*types.RequestAction)
 	deploymentTargetIdentifier := reqScopes[types.DeploymentTargetScope].Resource.Name
 
+	// requests which don't name a deployment target on a cluster without a default deployment target are left to
+	// the handler, which resolves the default deployment target through the cluster control plane
+	if deploymentTargetIdentifier == "" {
+		p.next.ServeHTTP(w, r)
+		return
+	}
+
 	deploymentTargetDB, err := p.config.Repo.DeploymentTarget().DeploymentTarget(proj.ID, deploymentTargetIdentifier)
+	if err == nil && deploymentTargetDB.ID == uuid.Nil {
+		err = gorm.ErrRecordNotFound
+	}
 	if err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			apierrors.HandleAPIError(p.config.Logger, p.config.Alerter, w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError), true)
@@ -77,3 +93,52 @@ func (p *DeploymentTargetScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *h
 func NewDeploymentTargetContext(ctx context.Context, deploymentTarget types.DeploymentTarget) context.Context {
 	return context.WithValue(ctx, types.DeploymentTargetScope, deploymentTarget)
 }
+
+// getDeploymentTargetIdentifier returns the id or name of the deployment target of a request. Routes under a
+// deployment target read it from the url, while deploy routes read it from the deployment_target_id or
+// deployment_target_name fields of the request body, or from the query of GET requests. An empty identifier
+// means the request acts on the default deployment target of the cluster.
+func getDeploymentTargetIdentifier(r *http.Request) (string, apierrors.RequestError) {
+	if chi.URLParam(r, string(types.URLParamDeploymentTargetIdentifier)) != "" {
+		return requestutils.GetURLParamString(r, types.URLParamDeploymentTargetIdentifier)
+	}
+
+	if r.Method == http.MethodGet {
+		return r.URL.Query().Get("deployment_target_id"), nil
+	}
+
+	body, reqErr := readRequestBody(r)
+	if reqErr != nil {
+		return "", reqErr
+	}
+
+	var request struct {
+		DeploymentTargetID   string `json:"deployment_target_id"`
+		DeploymentTargetName string `json:"deployment_target_name"`
+	}
+
+	// malformed bodies are rejected by the handler when it decodes the request
+	_ = json.Unmarshal(body, &request)
+
+	if request.DeploymentTargetID != "" {
+		return request.DeploymentTargetID, nil
+	}
+
+	return request.DeploymentTargetName, nil
+}
+
+// readRequestBody returns the body of the request, leaving it to be decoded again by the handler
+func readRequestBody(r *http.Request) ([]byte, apierrors.RequestError) {
+	if r.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, apierrors.NewErrPassThroughToClient(fmt.Errorf("error reading request body: %w", err), http.StatusBadRequest)
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
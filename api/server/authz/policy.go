@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/api/server/authz/policy"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
@@ -79,21 +80,44 @@ func (h *PolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// requests which act on app instances are checked against the deployment target of every instance
+	scopesToCheck, reqErr := h.withAppInstanceDeploymentTargets(ctx, r, policyLoaderOpts.ProjectID, reqScopes)
+	if reqErr != nil {
+		telemetry.Error(ctx, span, reqErr, "unable to get deployment targets of app instances")
+		apierrors.HandleAPIError(h.config.Logger, h.config.Alerter, w, r, reqErr, true)
+		return
+	}
+
+	// other requests which don't name a deployment target act on the default deployment target of the cluster
+	if scopesToCheck == nil {
+		h.withDefaultDeploymentTarget(policyLoaderOpts.ProjectID, reqScopes)
+		scopesToCheck = []map[types.PermissionScope]*types.RequestAction{reqScopes}
+	}
+
 	// validate that the policy permits the action
-	hasAccess := policy.HasScopeAccess(policyDocs, reqScopes)
-
-	if !hasAccess {
-		err := telemetry.Error(ctx, span, nil, "insufficient permissions to perform action")
-		apierrors.HandleAPIError(
-			h.config.Logger,
-			h.config.Alerter,
-			w,
-			r,
-			apierrors.NewErrPassThroughToClient(err, http.StatusForbidden),
-			true,
-		)
+	for _, scopes := range scopesToCheck {
+		if !policy.HasScopeAccess(policyDocs, h.withDeploymentTargetName(policyLoaderOpts.ProjectID, scopes)) {
+			err := telemetry.Error(ctx, span, nil, "insufficient permissions to perform action")
+			apierrors.HandleAPIError(
+				h.config.Logger,
+				h.config.Alerter,
+				w,
+				r,
+				apierrors.NewErrPassThroughToClient(err, http.StatusForbidden),
+				true,
+			)
+
+			return
+		}
+	}
 
-		return
+	// api tokens may be further restricted to specific deployment targets and apps
+	for _, scopes := range scopesToCheck {
+		if reqErr := checkAPITokenRestrictions(ctx, h.config.Repo, r, policyLoaderOpts.ProjectID, scopes); reqErr != nil {
+			err := telemetry.Error(ctx, span, reqErr, "api token cannot access resource")
+			apierrors.HandleAPIError(h.config.Logger, h.config.Alerter, w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden), true)
+			return
+		}
 	}
 
 	// add the set of resource ids to the request context
@@ -102,6 +126,74 @@ func (h *PolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.next.ServeHTTP(w, r)
 }
 
+// withDeploymentTargetName returns the request scopes with the deployment target identified by its name. Since
+// deployment targets can be identified by either their id or their name in the url, policies restricted to
+// specific deployment targets always list them by name.
+func (h *PolicyHandler) withDeploymentTargetName(
+	projectID uint,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) map[types.PermissionScope]*types.RequestAction {
+	action, ok := reqScopes[types.DeploymentTargetScope]
+	if !ok {
+		return reqScopes
+	}
+
+	if _, err := uuid.Parse(action.Resource.Name); err != nil {
+		return reqScopes
+	}
+
+	// if the deployment target doesn't exist, the deployment target middleware rejects the request
+	deploymentTarget, err := h.config.Repo.DeploymentTarget().DeploymentTarget(projectID, action.Resource.Name)
+	if err != nil || deploymentTarget == nil || deploymentTarget.VanityName == "" {
+		return reqScopes
+	}
+
+	res := make(map[types.PermissionScope]*types.RequestAction, len(reqScopes))
+
+	for scope, action := range reqScopes {
+		res[scope] = action
+	}
+
+	res[types.DeploymentTargetScope] = &types.RequestAction{
+		Verb: action.Verb,
+		Resource: types.NameOrUInt{
+			Name: deploymentTarget.VanityName,
+		},
+	}
+
+	return res
+}
+
+// withDefaultDeploymentTarget sets the deployment target of a request which doesn't name one to the default
+// deployment target of its cluster. If the cluster has no default deployment target, the request is left without
+// one, so policies restricted to specific deployment targets deny it.
+func (h *PolicyHandler) withDefaultDeploymentTarget(
+	projectID uint,
+	reqScopes map[types.PermissionScope]*types.RequestAction,
+) {
+	action, ok := reqScopes[types.DeploymentTargetScope]
+	if !ok || action.Resource.Name != "" {
+		return
+	}
+
+	clusterAction, ok := reqScopes[types.ClusterScope]
+	if !ok {
+		return
+	}
+
+	deploymentTargets, err := h.config.Repo.DeploymentTarget().ListForCluster(projectID, clusterAction.Resource.UInt, false)
+	if err != nil {
+		return
+	}
+
+	for _, deploymentTarget := range deploymentTargets {
+		if deploymentTarget.IsDefault {
+			action.Resource.Name = deploymentTarget.ID.String()
+			return
+		}
+	}
+}
+
 func NewRequestScopeCtx(ctx context.Context, reqScopes map[types.PermissionScope]*types.RequestAction) context.Context {
 	return context.WithValue(ctx, types.RequestScopeCtxKey, reqScopes)
 }
@@ -123,7 +215,7 @@ func getRequestActionForEndpoint(
 		case types.ClusterScope:
 			resource.UInt, reqErr = requestutils.GetURLParamUint(r, types.URLParamClusterID)
 		case types.DeploymentTargetScope:
-			resource.Name, reqErr = requestutils.GetURLParamString(r, types.URLParamDeploymentTargetIdentifier)
+			resource.Name, reqErr = getDeploymentTargetIdentifier(r)
 		case types.RegistryScope:
 			resource.UInt, reqErr = requestutils.GetURLParamUint(r, types.URLParamRegistryID)
 		case types.HelmRepoScope:
@@ -70,11 +70,16 @@ func (b *RepoPolicyDocumentLoader) LoadPolicyDocuments(
 			return types.DeveloperPolicy, nil
 		case types.RoleViewer:
 			return types.ViewerPolicy, nil
-		default:
-			return nil, apierrors.NewErrForbidden(
-				fmt.Errorf("%s role not supported for user %d, project %d", string(role.Kind), userID, projectID),
-			)
+		case types.RoleCustom:
+			// custom roles grant the project policy which they point to
+			if role.PolicyUID != "" {
+				return b.loadCustomRolePolicy(projectID, userID, role.PolicyUID)
+			}
 		}
+
+		return nil, apierrors.NewErrForbidden(
+			fmt.Errorf("%s role not supported for user %d, project %d", string(role.Kind), userID, projectID),
+		)
 	}
 
 	return nil, apierrors.NewErrForbidden(
@@ -82,6 +87,26 @@ func (b *RepoPolicyDocumentLoader) LoadPolicyDocuments(
 	)
 }
 
+func (b *RepoPolicyDocumentLoader) loadCustomRolePolicy(projectID, userID uint, uid string) ([]*types.PolicyDocument, apierrors.RequestError) {
+	policyModel, err := b.policyRepo.ReadPolicy(projectID, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apierrors.NewErrForbidden(
+				fmt.Errorf("policy %s of custom role not found for user %d, project %d", uid, userID, projectID),
+			)
+		}
+
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	apiPolicy, err := policyModel.ToAPIPolicyType()
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	return apiPolicy.Policy, nil
+}
+
 func GetAPIPolicyFromUID(policyRepo repository.PolicyRepository, projectID uint, uid string) (*types.APIPolicy, apierrors.RequestError) {
 	switch uid {
 	case "admin":
@@ -1,6 +1,7 @@
 package policy_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -198,3 +199,110 @@ func TestErrorCannotQuery(t *testing.T) {
 		"status is not status internal",
 	)
 }
+
+func TestCustomRolePolicyDocumentLoader(t *testing.T) {
+	projRepo := test.NewProjectRepository(true)
+	policyRepo := test.NewPolicyRepository(true)
+	loader := policy.NewBasicPolicyDocumentLoader(projRepo, policyRepo)
+
+	project, err := projRepo.CreateProject(&models.Project{
+		Name: "test-project",
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	policyBytes, err := json.Marshal(testPolicySpecificClusters)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, err = policyRepo.CreatePolicy(&models.Policy{
+		UniqueID:    "cluster-1-deployer",
+		ProjectID:   project.ID,
+		Name:        "cluster-1-deployer",
+		PolicyBytes: policyBytes,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, err = projRepo.CreateProjectRole(project, &models.Role{
+		Role: types.Role{
+			UserID:    1,
+			ProjectID: 1,
+			Kind:      types.RoleCustom,
+			PolicyUID: "cluster-1-deployer",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	docs, reqErr := loader.LoadPolicyDocuments(&policy.PolicyLoaderOpts{
+		ProjectID: 1,
+		UserID:    1,
+	})
+
+	if reqErr != nil {
+		t.Fatalf("%v", reqErr)
+	}
+
+	if diff := deep.Equal(testPolicySpecificClusters, docs); diff != nil {
+		t.Errorf("policy documents not equal:")
+		t.Error(diff)
+	}
+}
+
+func TestErrorForbiddenMissingCustomRolePolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	projRepo := test.NewProjectRepository(true)
+	loader := policy.NewBasicPolicyDocumentLoader(projRepo, test.NewPolicyRepository(true))
+
+	project, err := projRepo.CreateProject(&models.Project{
+		Name: "test-project",
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, err = projRepo.CreateProjectRole(project, &models.Role{
+		Role: types.Role{
+			UserID:    1,
+			ProjectID: 1,
+			Kind:      types.RoleCustom,
+			PolicyUID: "deleted-policy",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, reqErr := loader.LoadPolicyDocuments(&policy.PolicyLoaderOpts{
+		ProjectID: 1,
+		UserID:    1,
+	})
+
+	if reqErr == nil {
+		t.Fatalf("Expected forbidden error for missing custom role policy")
+	}
+
+	assert.Equal(
+		http.StatusForbidden,
+		reqErr.GetStatusCode(),
+		"status is not status forbidden",
+	)
+
+	assert.Equal(
+		"policy deleted-policy of custom role not found for user 1, project 1",
+		reqErr.Error(),
+		"error message is not correct",
+	)
+}
@@ -49,6 +49,26 @@ func HasScopeAccess(
 	return false
 }
 
+// IsValidPolicy checks that every document of a policy follows the scope heirarchy
+func IsValidPolicy(policy []*types.PolicyDocument) bool {
+	for _, policyDoc := range policy {
+		isValid, _ := populateAndVerifyPolicyDocument(
+			policyDoc,
+			types.ScopeHeirarchy,
+			types.ProjectScope,
+			types.ReadWriteVerbGroup(),
+			map[types.PermissionScope]*types.RequestAction{},
+			nil,
+		)
+
+		if policyDoc == nil || !isValid {
+			return false
+		}
+	}
+
+	return true
+}
+
 func isResourceAllowed(
 	matchDoc *types.PolicyDocument,
 	resource types.NameOrUInt,
@@ -138,6 +138,139 @@ var hasScopeAccessTests = []testHasScopeAccess{
 		},
 		expRes: false,
 	},
+	{
+		description: "developer access can write deployment targets",
+		policy:      types.DeveloperPolicy,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					Name: "production",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "viewer access cannot write deployment targets",
+		policy:      types.ViewerPolicy,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					Name: "production",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "staging deployer can write the staging deployment target",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					Name: "staging",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "staging deployer can read the production deployment target",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbGet,
+				Resource: types.NameOrUInt{
+					Name: "production",
+				},
+			},
+		},
+		expRes: true,
+	},
+	{
+		description: "staging deployer cannot write the production deployment target",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.DeploymentTargetScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					Name: "production",
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "staging deployer cannot write the production cluster",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.ClusterScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 2,
+				},
+			},
+		},
+		expRes: false,
+	},
+	{
+		description: "staging deployer cannot write settings",
+		policy:      testPolicyStagingDeployer,
+		reqScopes: map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+			types.SettingsScope: {
+				Verb: types.APIVerbUpdate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+		},
+		expRes: false,
+	},
 	{
 		description: "test invalid policy document",
 		policy:      testInvalidPolicyDocument,
@@ -277,6 +410,56 @@ var testPolicyNamespaceSpecific = []*types.PolicyDocument{
 	},
 }
 
+var testPolicyStagingDeployer = []*types.PolicyDocument{
+	// This document allows a user to deploy to the "staging" deployment target in the
+	// cluster with id 1.
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadWriteVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadWriteVerbGroup(),
+				Resources: []types.NameOrUInt{
+					{
+						UInt: 1,
+					},
+				},
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.DeploymentTargetScope: {
+						Scope: types.DeploymentTargetScope,
+						Verbs: types.ReadWriteVerbGroup(),
+						Resources: []types.NameOrUInt{
+							{
+								Name: "staging",
+							},
+						},
+					},
+				},
+			},
+			types.SettingsScope: {
+				Scope: types.SettingsScope,
+				Verbs: types.ReadVerbGroup(),
+			},
+		},
+	},
+	// This document allows a user to view everything else in the project.
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadVerbGroup(),
+			},
+			types.SettingsScope: {
+				Scope: types.SettingsScope,
+				Verbs: types.ReadVerbGroup(),
+			},
+		},
+	},
+}
+
 // NOTE: these are invalid policy documents that don't follow the accepted heirarchy
 // for scopes. Don't use this as a model for a valid doc.
 var testInvalidPolicyDocument = []*types.PolicyDocument{
@@ -327,3 +510,13 @@ var testInvalidPolicyDocumentNested = []*types.PolicyDocument{
 		},
 	},
 }
+
+func TestIsValidPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(policy.IsValidPolicy(types.AdminPolicy), "admin policy should be valid")
+	assert.True(policy.IsValidPolicy(testPolicyStagingDeployer), "staging deployer policy should be valid")
+	assert.False(policy.IsValidPolicy(testInvalidPolicyDocument), "cluster above project should be invalid")
+	assert.False(policy.IsValidPolicy(testInvalidPolicyDocumentNested), "release below cluster should be invalid")
+	assert.False(policy.IsValidPolicy([]*types.PolicyDocument{nil}), "nil document should be invalid")
+}
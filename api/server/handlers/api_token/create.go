@@ -3,18 +3,19 @@ package api_token
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/porter-dev/porter/api/server/authz/policy"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/encryption"
 	"github.com/porter-dev/porter/internal/models"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type APITokenCreateHandler struct {
@@ -46,47 +47,48 @@ func (p *APITokenCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// if the expiry time is not set, set the expiry to 1 year
-	if req.ExpiresAt.IsZero() {
-		req.ExpiresAt = time.Now().Add(time.Hour * 24 * 365)
+	if reqErr := checkExpiry(req.ExpiresAt, p.Config().ServerConf.APITokenMaxLifetime, time.Now()); reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
 	}
 
-	apiPolicy, reqErr := policy.GetAPIPolicyFromUID(p.Repo().Policy(), proj.ID, req.PolicyUID)
-
+	deploymentTargetIDs, reqErr := resolveDeploymentTargetIDs(p.Repo(), proj.ID, req.DeploymentTargets)
 	if reqErr != nil {
 		p.HandleAPIError(w, r, reqErr)
 		return
 	}
 
-	uid, err := encryption.GenerateRandomBytes(16)
-	if err != nil {
-		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+	apiPolicy, reqErr := policy.GetAPIPolicyFromUID(p.Repo().Policy(), proj.ID, req.PolicyUID)
+
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
 		return
 	}
 
-	secretKey, err := encryption.GenerateRandomBytes(16)
+	uid, err := encryption.GenerateRandomBytes(16)
 	if err != nil {
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
 	}
 
-	// hash the secret key for storage in the db
-	hashedToken, err := bcrypt.GenerateFromPassword([]byte(secretKey), 8)
+	secretKey, hashedToken, err := newSecretKey()
 	if err != nil {
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
 	}
 
 	apiToken := &models.APIToken{
-		UniqueID:        uid,
-		ProjectID:       proj.ID,
-		CreatedByUserID: user.ID,
-		Expiry:          &req.ExpiresAt,
-		Revoked:         false,
-		PolicyUID:       apiPolicy.UID,
-		PolicyName:      apiPolicy.Name,
-		Name:            req.Name,
-		SecretKey:       hashedToken,
+		UniqueID:            uid,
+		ProjectID:           proj.ID,
+		CreatedByUserID:     user.ID,
+		Expiry:              &req.ExpiresAt,
+		Revoked:             false,
+		PolicyUID:           apiPolicy.UID,
+		PolicyName:          apiPolicy.Name,
+		Name:                req.Name,
+		DeploymentTargetIDs: strings.Join(deploymentTargetIDs, ","),
+		AppNames:            strings.Join(uniqueStrings(req.AppNames), ","),
+		SecretKey:           hashedToken,
 	}
 
 	apiToken, err = p.Repo().APIToken().CreateAPIToken(apiToken)
@@ -96,6 +98,8 @@ func (p *APITokenCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	audit.RecordChange(r.Context(), nil, apiToken.ToAPITokenMetaType())
+
 	// generate porter jwt token
 	jwt, err := token.GetStoredTokenForAPI(user.ID, proj.ID, apiToken.UniqueID, secretKey)
 	if err != nil {
@@ -0,0 +1,102 @@
+package api_token_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/api_token"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/features"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type expiryTest struct {
+	description string
+	expiresAt   time.Time
+	expStatus   int
+	expErr      string
+}
+
+func expiryTests() []expiryTest {
+	return []expiryTest{
+		{
+			description: "missing expiry is rejected",
+			expStatus:   http.StatusBadRequest,
+			expErr:      "expiry is required",
+		},
+		{
+			description: "past expiry is rejected",
+			expiresAt:   time.Now().Add(-time.Hour),
+			expStatus:   http.StatusBadRequest,
+			expErr:      "expiry must be in the future",
+		},
+		{
+			description: "expiry beyond the maximum lifetime is rejected",
+			expiresAt:   time.Now().Add(31 * 24 * time.Hour),
+			expStatus:   http.StatusBadRequest,
+			expErr:      "expiry cannot be more than 30 days in the future",
+		},
+		{
+			description: "expiry within the maximum lifetime is accepted",
+			expiresAt:   time.Now().Add(29 * 24 * time.Hour),
+			expStatus:   http.StatusOK,
+		},
+	}
+}
+
+func TestCreateAPITokenExpiry(t *testing.T) {
+	for _, test := range expiryTests() {
+		conf, user, proj := newAPITokenTestConfig(t)
+
+		req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1/api_token", &types.CreateAPIToken{
+			PolicyUID: "admin",
+			Name:      "ci",
+			ExpiresAt: test.expiresAt,
+		})
+		req = apitest.WithAuthenticatedUser(t, req, user)
+		req = apitest.WithProject(t, req, proj)
+
+		api_token.NewAPITokenCreateHandler(
+			conf,
+			shared.NewDefaultRequestDecoderValidator(conf.Logger, conf.Alerter),
+			shared.NewDefaultResultWriter(conf.Logger, conf.Alerter),
+		).ServeHTTP(rr, req)
+
+		assertExpiryResponse(t, test, rr.Code, rr.Body.String())
+	}
+}
+
+// newAPITokenTestConfig returns a config whose api tokens can be issued for at most 30 days, with a user and a
+// project which has api tokens enabled
+func newAPITokenTestConfig(t *testing.T) (*config.Config, *models.User, *models.Project) {
+	conf := apitest.LoadConfig(t)
+	conf.ServerConf.APITokenMaxLifetime = 30 * 24 * time.Hour
+
+	featureClient, err := features.GetClient("database", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.LaunchDarklyClient = featureClient
+
+	user := apitest.CreateTestUser(t, conf, true)
+
+	proj, err := conf.Repo.Project().CreateProject(&models.Project{Name: "test-project", APITokensEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conf, user, proj
+}
+
+func assertExpiryResponse(t *testing.T, test expiryTest, status int, body string) {
+	assert.Equal(t, test.expStatus, status, "[ %s ]: status code not equal", test.description)
+
+	if test.expErr != "" {
+		assert.Contains(t, body, test.expErr, "[ %s ]: error not equal", test.description)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
@@ -59,6 +60,8 @@ func (p *APITokenRevokeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	before := token.ToAPITokenMetaType()
+
 	token.Revoked = true
 
 	token, err = p.Repo().APIToken().UpdateAPIToken(token)
@@ -68,5 +71,7 @@ func (p *APITokenRevokeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	audit.RecordChange(r.Context(), before, token.ToAPITokenMetaType())
+
 	p.WriteResult(w, r, token.ToAPITokenMetaType())
 }
@@ -0,0 +1,158 @@
+package api_token
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz/policy"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/token"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// APITokenRotateHandler issues a new secret for an api token
+type APITokenRotateHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewAPITokenRotateHandler returns a new APITokenRotateHandler
+func NewAPITokenRotateHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *APITokenRotateHandler {
+	return &APITokenRotateHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the secret of the token, keeping the previous secret valid for the requested overlap
+// window, and returns the token with the new secret
+func (p *APITokenRotateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	if !proj.GetFeatureFlag(models.APITokensEnabled, p.Config().LaunchDarklyClient) {
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(fmt.Errorf("api token endpoints are not enabled for this project")))
+		return
+	}
+
+	tokenID, reqErr := requestutils.GetURLParamString(r, types.URLParamTokenID)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	req := &types.RotateAPITokenRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, req); !ok {
+		return
+	}
+
+	now := time.Now()
+
+	if reqErr := checkExpiry(req.ExpiresAt, p.Config().ServerConf.APITokenMaxLifetime, now); reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	apiToken, err := p.Repo().APIToken().ReadAPIToken(proj.ID, tokenID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("token with id %s not found in project", tokenID),
+				http.StatusNotFound,
+			))
+			return
+		}
+
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if apiToken.Revoked || apiToken.IsExpired() {
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("token with id %s is revoked or expired", tokenID),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	// tokens issued without a secret, such as the tokens of the porter agent, are reissued by their clients
+	if len(apiToken.SecretKey) == 0 {
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("token with id %s was not issued with a secret and cannot be rotated", tokenID),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	apiPolicy, reqErr := policy.GetAPIPolicyFromUID(p.Repo().Policy(), proj.ID, apiToken.PolicyUID)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	secretKey, hashedToken, err := newSecretKey()
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	before := apiToken.ToAPITokenMetaType()
+
+	apiToken.PreviousSecretKey = nil
+	apiToken.PreviousSecretExpiry = nil
+
+	if req.OverlapSeconds > 0 {
+		previousSecretExpiry := now.Add(time.Duration(req.OverlapSeconds) * time.Second)
+
+		apiToken.PreviousSecretKey = apiToken.SecretKey
+		apiToken.PreviousSecretExpiry = &previousSecretExpiry
+	}
+
+	apiToken.SecretKey = hashedToken
+	apiToken.RotatedAt = &now
+
+	apiToken.Expiry = &req.ExpiresAt
+	apiToken.ExpiryNotifiedAt = nil
+
+	apiToken, err = p.Repo().APIToken().UpdateAPIToken(apiToken)
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	audit.RecordChange(r.Context(), before, apiToken.ToAPITokenMetaType())
+
+	// the token keeps being issued by its creator, unless it was created by another token
+	issuedBy := apiToken.CreatedByUserID
+	if issuedBy == 0 {
+		issuedBy = user.ID
+	}
+
+	jwt, err := token.GetStoredTokenForAPI(issuedBy, proj.ID, apiToken.UniqueID, secretKey)
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	encoded, err := jwt.EncodeToken(p.Config().TokenConf)
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, apiToken.ToAPITokenType(apiPolicy.Policy, encoded))
+}
@@ -0,0 +1,50 @@
+package api_token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/api_token"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestRotateAPITokenExpiry(t *testing.T) {
+	for _, test := range expiryTests() {
+		conf, user, proj := newAPITokenTestConfig(t)
+
+		expiry := time.Now().Add(time.Hour)
+
+		_, err := conf.Repo.APIToken().CreateAPIToken(&models.APIToken{
+			UniqueID:        "ci-token",
+			ProjectID:       proj.ID,
+			CreatedByUserID: user.ID,
+			Expiry:          &expiry,
+			Name:            "ci",
+			PolicyUID:       "admin",
+			SecretKey:       []byte("hashed"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1/api_token/ci-token/rotate", &types.RotateAPITokenRequest{
+			ExpiresAt: test.expiresAt,
+		})
+		req = apitest.WithURLParams(t, req, map[string]string{
+			string(types.URLParamTokenID): "ci-token",
+		})
+		req = apitest.WithAuthenticatedUser(t, req, user)
+		req = apitest.WithProject(t, req, proj)
+
+		api_token.NewAPITokenRotateHandler(
+			conf,
+			shared.NewDefaultRequestDecoderValidator(conf.Logger, conf.Alerter),
+			shared.NewDefaultResultWriter(conf.Logger, conf.Alerter),
+		).ServeHTTP(rr, req)
+
+		assertExpiryResponse(t, test, rr.Code, rr.Body.String())
+	}
+}
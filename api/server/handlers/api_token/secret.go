@@ -0,0 +1,88 @@
+package api_token
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// newSecretKey generates the secret key of a token, returning the key and its hash for storage in the db
+func newSecretKey() (string, []byte, error) {
+	secretKey, err := encryption.GenerateRandomBytes(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hashedToken, err := bcrypt.GenerateFromPassword([]byte(secretKey), 8)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return secretKey, hashedToken, nil
+}
+
+// checkExpiry checks that the expiry of a token is set and in the future, and that the token is not issued for
+// longer than the maximum lifetime of tokens
+func checkExpiry(expiresAt time.Time, maxLifetime time.Duration, now time.Time) apierrors.RequestError {
+	if expiresAt.IsZero() {
+		return apierrors.NewErrPassThroughToClient(fmt.Errorf("expiry is required"), http.StatusBadRequest)
+	}
+
+	if !expiresAt.After(now) {
+		return apierrors.NewErrPassThroughToClient(fmt.Errorf("expiry must be in the future"), http.StatusBadRequest)
+	}
+
+	if maxLifetime > 0 && expiresAt.After(now.Add(maxLifetime)) {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("expiry cannot be more than %d days in the future", int(maxLifetime.Hours()/24)),
+			http.StatusBadRequest,
+		)
+	}
+
+	return nil
+}
+
+// resolveDeploymentTargetIDs returns the unique ids of the deployment targets with the given ids or names
+func resolveDeploymentTargetIDs(repo repository.Repository, projectID uint, identifiers []string) ([]string, apierrors.RequestError) {
+	ids := make([]string, 0, len(identifiers))
+
+	for _, identifier := range uniqueStrings(identifiers) {
+		deploymentTarget, err := repo.DeploymentTarget().DeploymentTarget(projectID, identifier)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apierrors.NewErrInternal(err)
+		}
+
+		if err != nil || deploymentTarget.ID == uuid.Nil {
+			return nil, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("deployment target %s not found in project", identifier),
+				http.StatusBadRequest,
+			)
+		}
+
+		ids = append(ids, deploymentTarget.ID.String())
+	}
+
+	return uniqueStrings(ids), nil
+}
+
+func uniqueStrings(values []string) []string {
+	res := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			res = append(res, value)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,114 @@
+package audit_log
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// auditLogPageSize is the number of audit logs returned per page
+const auditLogPageSize = 50
+
+// ListAuditLogsHandler lists the audit logs of a project
+type ListAuditLogsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListAuditLogsHandler returns a new ListAuditLogsHandler
+func NewListAuditLogsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListAuditLogsHandler {
+	return &ListAuditLogsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP lists the audit logs of the project matching the filters in the query, newest first
+func (c *ListAuditLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-audit-logs")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.ListAuditLogsRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	var since, until time.Time
+	var err error
+
+	if request.Since != "" {
+		since, err = time.Parse(time.RFC3339, request.Since)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "since must be an RFC 3339 timestamp")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	if request.Until != "" {
+		until, err = time.Parse(time.RFC3339, request.Until)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "until must be an RFC 3339 timestamp")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: project.ID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: request.ClusterID},
+		telemetry.AttributeKV{Key: "action", Value: request.Action},
+		telemetry.AttributeKV{Key: "resource", Value: request.Resource},
+	)
+
+	filters := []repository.AuditLogFilters{
+		repository.WithAuditLogClusterID(request.ClusterID),
+		repository.WithAuditLogActor(request.ActorType, request.ActorID),
+		repository.WithAuditLogAction(request.Action),
+		repository.WithAuditLogResource(request.Resource),
+		repository.WithAuditLogRequestID(request.RequestID),
+		repository.WithAuditLogTimeRange(since, until),
+	}
+
+	var page int64
+	if request.PaginationRequest != nil {
+		page = request.Page
+	}
+
+	auditLogs, paginatedResult, err := c.Repo().AuditLog().ListAuditLogs(
+		ctx,
+		project.ID,
+		filters,
+		helpers.WithPageSize(auditLogPageSize),
+		helpers.WithPage(int(page)),
+	)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing audit logs")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := types.ListAuditLogsResponse{
+		AuditLogs:  make([]types.AuditLog, 0, len(auditLogs)),
+		Pagination: types.PaginationResponse(paginatedResult),
+	}
+
+	for _, auditLog := range auditLogs {
+		res.AuditLogs = append(res.AuditLogs, auditLog.ToAuditLogType())
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/notifier"
+	projectnotifiers "github.com/porter-dev/porter/internal/notifier/notifiers"
 	"github.com/porter-dev/porter/internal/notifier/sendgrid"
 	"github.com/porter-dev/porter/internal/notifier/slack"
 	"github.com/porter-dev/porter/internal/repository"
@@ -51,6 +52,7 @@ func (c *NotifyNewIncidentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	}
 
 	slackInts, _ := c.Repo().SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+	notifierInts, _ := c.Repo().NotifierIntegration().ListNotifierIntegrationsByProjectID(cluster.ProjectID)
 
 	rel, err := c.Repo().Release().ReadRelease(cluster.ID, request.ReleaseName, request.ReleaseNamespace)
 
@@ -94,6 +96,8 @@ func (c *NotifyNewIncidentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		}))
 	}
 
+	notifiers = append(notifiers, projectnotifiers.NewIncidentNotifiers(r.Context(), notifierInts)...)
+
 	multi := notifier.NewMultiIncidentNotifier(
 		notifConf,
 		notifiers...,
@@ -14,6 +14,7 @@ import (
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/notifier"
+	projectnotifiers "github.com/porter-dev/porter/internal/notifier/notifiers"
 	"github.com/porter-dev/porter/internal/notifier/sendgrid"
 	"github.com/porter-dev/porter/internal/notifier/slack"
 	"gorm.io/gorm"
@@ -50,6 +51,7 @@ func (c *NotifyResolvedIncidentHandler) ServeHTTP(w http.ResponseWriter, r *http
 	}
 
 	slackInts, _ := c.Repo().SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+	notifierInts, _ := c.Repo().NotifierIntegration().ListNotifierIntegrationsByProjectID(cluster.ProjectID)
 
 	rel, err := c.Repo().Release().ReadRelease(cluster.ID, request.ReleaseName, request.ReleaseNamespace)
 
@@ -93,6 +95,8 @@ func (c *NotifyResolvedIncidentHandler) ServeHTTP(w http.ResponseWriter, r *http
 		}))
 	}
 
+	notifiers = append(notifiers, projectnotifiers.NewIncidentNotifiers(r.Context(), notifierInts)...)
+
 	multi := notifier.NewMultiIncidentNotifier(
 		notifConf,
 		notifiers...,
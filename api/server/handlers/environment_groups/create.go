@@ -1,6 +1,8 @@
 package environment_groups
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -13,8 +15,12 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
 )
 
 type UpdateEnvironmentGroupHandler struct {
@@ -65,6 +71,16 @@ type UpdateEnvironmentGroupRequest struct {
 
 	// SkipAppAutoDeploy is a flag to determine if the app should be auto deployed
 	SkipAppAutoDeploy bool `json:"skip_app_auto_deploy"`
+
+	// ExternalSecrets are secret variables whose values are read from an external secret store inside the cluster, through the
+	// cluster's secret store for each provider. If set, they replace all existing external secrets of the env group; an empty map removes them.
+	ExternalSecrets map[string]environment_groups.ExternalSecretReference `json:"external_secrets,omitempty"`
+
+	// ExternalSecretsRefreshInterval is how often external secrets which are not pinned to a version are re-read, e.g. 1h
+	ExternalSecretsRefreshInterval string `json:"external_secrets_refresh_interval,omitempty"`
+
+	// AutoRollout enables or disables the redeploy of linked apps one by one when the env group is updated. If unset, the setting is unchanged.
+	AutoRollout *bool `json:"auto_rollout,omitempty"`
 }
 type UpdateEnvironmentGroupResponse struct {
 	// Name of the env group to create or update
@@ -87,6 +103,7 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 	if ok := c.DecodeAndValidate(w, r, request); !ok {
 		return
 	}
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
 	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
 
 	telemetry.WithAttributes(span,
@@ -110,7 +127,55 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 		}
 
 	default:
-		_, err := c.Config().ClusterControlPlaneClient.CreateOrUpdateEnvGroup(ctx, connect.NewRequest(&porterv1.CreateOrUpdateEnvGroupRequest{
+		// the cluster control plane syncs env groups to the namespaces of v2 apps without creating the ExternalSecrets which resolve them
+		if len(request.ExternalSecrets) > 0 && project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
+			err := telemetry.Error(ctx, span, nil, "external secrets are not supported for env groups of v2 apps")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		if request.ExternalSecrets != nil {
+			externalSecrets := environment_groups.ExternalSecrets{
+				References:      request.ExternalSecrets,
+				RefreshInterval: request.ExternalSecretsRefreshInterval,
+			}
+
+			if err := externalSecrets.Validate(); err != nil {
+				err := telemetry.Error(ctx, span, err, "invalid external secrets")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+
+			agent, err := c.GetAgent(r, cluster, "")
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "unable to connect to cluster")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+
+			// external secrets are stored before the env group is updated, so that they are resolved when apps are redeployed
+			err = environment_groups.SetExternalSecrets(ctx, agent, request.Name, externalSecrets)
+			if errors.Is(err, environment_groups.ErrExternalSecretStoreNotFound) {
+				err := telemetry.Error(ctx, span, err, "external secret store not found")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "unable to set external secrets")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+
+		autoRollout, err := c.autoRolloutEnabled(ctx, cluster, request)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to get env group auto rollout setting")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "auto-rollout", Value: autoRollout})
+
+		_, err = c.Config().ClusterControlPlaneClient.CreateOrUpdateEnvGroup(ctx, connect.NewRequest(&porterv1.CreateOrUpdateEnvGroupRequest{
 			ProjectId:            int64(cluster.ProjectID),
 			ClusterId:            int64(cluster.ID),
 			EnvGroupProviderType: porterv1.EnumEnvGroupProviderType_ENUM_ENV_GROUP_PROVIDER_TYPE_PORTER,
@@ -123,14 +188,24 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 				Variables: request.Deletions.Variables,
 				Secrets:   request.Deletions.Secrets,
 			},
-			IsEnvOverride:     request.IsEnvOverride,
-			SkipAppAutoDeploy: request.SkipAppAutoDeploy,
+			IsEnvOverride: request.IsEnvOverride,
+			// apps are redeployed one by one by a worker if auto rollout is enabled
+			SkipAppAutoDeploy: request.SkipAppAutoDeploy || autoRollout,
 		}))
 		if err != nil {
 			err := telemetry.Error(ctx, span, err, "unable to create environment group")
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
+
+		if autoRollout && !request.SkipAppAutoDeploy {
+			_, err = porter_app.EnqueueEnvGroupRollout(ctx, c.Repo().WorkerJobRun(), cluster.ProjectID, cluster.ID, request.Name)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "unable to enqueue env group rollout")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
 	}
 
 	envGroupResponse := &UpdateEnvironmentGroupResponse{
@@ -139,3 +214,35 @@ func (c *UpdateEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 	}
 	c.WriteResult(w, r, envGroupResponse)
 }
+
+// autoRolloutEnabled stores the auto rollout setting of the env group if it is set in the request, and returns whether auto rollout is enabled
+func (c *UpdateEnvironmentGroupHandler) autoRolloutEnabled(ctx context.Context, cluster *models.Cluster, request *UpdateEnvironmentGroupRequest) (bool, error) {
+	if request.AutoRollout != nil {
+		setting, err := c.Repo().EnvironmentGroupSetting().UpsertEnvironmentGroupSetting(ctx, &models.EnvironmentGroupSetting{
+			ProjectID:            cluster.ProjectID,
+			ClusterID:            cluster.ID,
+			EnvironmentGroupName: request.Name,
+			AutoRollout:          *request.AutoRollout,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return setting.AutoRollout, nil
+	}
+
+	return readAutoRollout(ctx, c.Repo(), cluster, request.Name)
+}
+
+// readAutoRollout returns whether auto rollout is enabled for the env group. It is disabled unless it has been set.
+func readAutoRollout(ctx context.Context, repo repository.Repository, cluster *models.Cluster, envGroupName string) (bool, error) {
+	setting, err := repo.EnvironmentGroupSetting().ReadEnvironmentGroupSetting(ctx, cluster.ID, envGroupName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return setting.AutoRollout, nil
+}
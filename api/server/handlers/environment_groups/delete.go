@@ -87,5 +87,12 @@ func (c *DeleteEnvironmentGroupHandler) ServeHTTP(w http.ResponseWriter, r *http
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
+
+		err = c.Repo().EnvironmentGroupSetting().DeleteEnvironmentGroupSetting(ctx, cluster.ID, request.Name)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to delete environment group settings")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
 	}
 }
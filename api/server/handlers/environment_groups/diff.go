@@ -0,0 +1,115 @@
+package environment_groups
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// EnvGroupDiffHandler is the handler for the /environment-groups/{env_group_name}/diff endpoint
+type EnvGroupDiffHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewEnvGroupDiffHandler handles GET requests to /environment-groups/{env_group_name}/diff
+func NewEnvGroupDiffHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *EnvGroupDiffHandler {
+	return &EnvGroupDiffHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// EnvGroupDiffRequest is the request object for the /environment-groups/{env_group_name}/diff endpoint
+type EnvGroupDiffRequest struct {
+	// FromVersion is the older version to compare. If empty, the version before ToVersion is used
+	FromVersion int `schema:"from"`
+	// ToVersion is the newer version to compare. If empty, the latest version is used
+	ToVersion int `schema:"to"`
+}
+
+// EnvGroupDiffResponse is the response object for the /environment-groups/{env_group_name}/diff endpoint
+type EnvGroupDiffResponse struct {
+	environmentgroups.EnvironmentGroupDiff
+}
+
+// ServeHTTP compares two versions of an environment group, with all secret values redacted
+func (c *EnvGroupDiffHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-env-group-diff")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &EnvGroupDiffRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	toVersion := request.ToVersion
+	if toVersion == 0 {
+		latest, err := environmentgroups.LatestBaseEnvironmentGroup(ctx, agent, envGroupName)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to get latest env group version")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		toVersion = latest.Version
+	}
+
+	fromVersion := request.FromVersion
+	if fromVersion == 0 {
+		fromVersion = toVersion - 1
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "from-version", Value: fromVersion},
+		telemetry.AttributeKV{Key: "to-version", Value: toVersion},
+	)
+
+	if fromVersion <= 0 || toVersion <= 0 {
+		err := telemetry.Error(ctx, span, nil, "env group does not have a version to compare")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	diff, err := environmentgroups.DiffEnvironmentGroupVersions(ctx, agent, envGroupName, fromVersion, toVersion)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to diff env group versions")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	res := &EnvGroupDiffResponse{
+		EnvironmentGroupDiff: diff,
+	}
+
+	c.WriteResult(w, r, res)
+}
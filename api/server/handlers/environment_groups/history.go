@@ -0,0 +1,99 @@
+package environment_groups
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// EnvGroupHistoryHandler is the handler for the /environment-groups/{env_group_name}/versions endpoint
+type EnvGroupHistoryHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewEnvGroupHistoryHandler handles GET requests to /environment-groups/{env_group_name}/versions
+func NewEnvGroupHistoryHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *EnvGroupHistoryHandler {
+	return &EnvGroupHistoryHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// EnvGroupVersion is a version of an environment group, with all secret values redacted
+type EnvGroupVersion struct {
+	Version         int               `json:"version"`
+	Variables       map[string]string `json:"variables,omitempty"`
+	SecretVariables map[string]string `json:"secret_variables,omitempty"`
+	CreatedAtUTC    time.Time         `json:"created_at"`
+}
+
+// EnvGroupHistoryResponse is the response object for the /environment-groups/{env_group_name}/versions endpoint
+type EnvGroupHistoryResponse struct {
+	// Versions are the versions of the environment group, from newest to oldest
+	Versions []EnvGroupVersion `json:"versions"`
+}
+
+// ServeHTTP lists all versions of an environment group
+func (c *EnvGroupHistoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-env-group-history")
+	defer span.End()
+
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName})
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	versions, err := environmentgroups.EnvironmentGroupVersions(ctx, agent, envGroupName)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to list env group versions")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if len(versions) == 0 {
+		err := telemetry.Error(ctx, span, nil, "env group not found")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+		return
+	}
+
+	res := &EnvGroupHistoryResponse{
+		Versions: make([]EnvGroupVersion, 0, len(versions)),
+	}
+	for _, version := range versions {
+		res.Versions = append(res.Versions, EnvGroupVersion{
+			Version:         version.Version,
+			Variables:       version.Variables,
+			SecretVariables: version.SecretVariables,
+			CreatedAtUTC:    version.CreatedAtUTC,
+		})
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -54,6 +54,7 @@ type EnvironmentGroupListItem struct {
 	SecretVariables    map[string]string `json:"secret_variables,omitempty"`
 	CreatedAtUTC       time.Time         `json:"created_at"`
 	LinkedApplications []string          `json:"linked_applications,omitempty"`
+	AutoRollout        bool              `json:"auto_rollout"`
 }
 
 func (c *ListEnvironmentGroupsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -72,6 +73,18 @@ func (c *ListEnvironmentGroupsHandler) ServeHTTP(w http.ResponseWriter, r *http.
 
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "env-group-type", Value: request.Type})
 
+	settings, err := c.Repo().EnvironmentGroupSetting().ListEnvironmentGroupSettings(ctx, cluster.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "unable to list environment group settings")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	autoRolloutByName := make(map[string]bool)
+	for _, setting := range settings {
+		autoRolloutByName[setting.EnvironmentGroupName] = setting.AutoRollout
+	}
+
 	if project.GetFeatureFlag(models.ValidateApplyV2, c.Config().LaunchDarklyClient) {
 		listEnvGroupsReq := connect.NewRequest(&porterv1.ListEnvGroupsRequest{
 			ProjectId:      int64(project.ID),
@@ -101,6 +114,7 @@ func (c *ListEnvironmentGroupsHandler) ServeHTTP(w http.ResponseWriter, r *http.
 				SecretVariables:    envGroup.SecretVariables,
 				CreatedAtUTC:       envGroup.CreatedAt.AsTime(),
 				LinkedApplications: envGroup.LinkedApplications,
+				AutoRollout:        autoRolloutByName[envGroup.Name],
 			})
 		}
 
@@ -186,6 +200,7 @@ func (c *ListEnvironmentGroupsHandler) ServeHTTP(w http.ResponseWriter, r *http.
 			SecretVariables:    secrets,
 			CreatedAtUTC:       latestVersion.CreatedAtUTC,
 			LinkedApplications: linkedApplications,
+			AutoRollout:        autoRolloutByName[latestVersion.Name],
 		})
 	}
 
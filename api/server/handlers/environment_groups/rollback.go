@@ -0,0 +1,162 @@
+package environment_groups
+
+import (
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	environmentgroups "github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// RollbackEnvGroupHandler is the handler for the /environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvGroupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+// NewRollbackEnvGroupHandler handles POST requests to /environment-groups/{env_group_name}/rollback
+func NewRollbackEnvGroupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RollbackEnvGroupHandler {
+	return &RollbackEnvGroupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// RollbackEnvGroupRequest is the request object for the /environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvGroupRequest struct {
+	// Version is the version whose variables are restored as a new version
+	Version int `json:"version" form:"required"`
+
+	// SkipAppAutoDeploy is a flag to determine if the apps linked to the env group should not be redeployed
+	SkipAppAutoDeploy bool `json:"skip_app_auto_deploy"`
+}
+
+// RollbackEnvGroupResponse is the response object for the /environment-groups/{env_group_name}/rollback endpoint
+type RollbackEnvGroupResponse struct {
+	// Version is the new version of the env group
+	Version EnvGroupVersion `json:"version"`
+
+	// LinkedApplications are the names of the apps which use the env group
+	LinkedApplications []string `json:"linked_applications"`
+}
+
+// ServeHTTP restores a previous version of an environment group as a new version, and redeploys the apps linked to it. If auto rollout is
+// enabled for the env group, the apps are redeployed one by one by a worker, as when the env group is updated.
+func (c *RollbackEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-rollback-env-group")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
+
+	envGroupName, reqErr := requestutils.GetURLParamString(r, types.URLParamEnvGroupName)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing env group name from url")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	request := &RollbackEnvGroupRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+		telemetry.AttributeKV{Key: "rollback-version", Value: request.Version},
+		telemetry.AttributeKV{Key: "skip-app-auto-deploy", Value: request.SkipAppAutoDeploy},
+	)
+
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to connect to cluster")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	restored, err := environmentgroups.RollbackEnvironmentGroup(ctx, agent, envGroupName, request.Version)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to roll back env group")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	applications, err := environmentgroups.LinkedApplications(ctx, agent, envGroupName, true)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "unable to get linked applications")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	linkedApplications := []string{}
+	applicationSet := make(map[string]struct{})
+	for _, app := range applications {
+		if app.Namespace == "" {
+			continue
+		}
+		if _, ok := applicationSet[app.Namespace]; ok {
+			continue
+		}
+		applicationSet[app.Namespace] = struct{}{}
+		linkedApplications = append(linkedApplications, strings.TrimPrefix(app.Namespace, "porter-stack-"))
+	}
+
+	if !request.SkipAppAutoDeploy && len(linkedApplications) > 0 {
+		autoRollout, err := readAutoRollout(ctx, c.Repo(), cluster, envGroupName)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "unable to get env group auto rollout setting")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "auto-rollout", Value: autoRollout})
+
+		// apps are redeployed the same way as when the env group is updated
+		if autoRollout {
+			_, err = porter_app.EnqueueEnvGroupRollout(ctx, c.Repo().WorkerJobRun(), project.ID, cluster.ID, envGroupName)
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "unable to enqueue env group rollout")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		} else {
+			_, err = c.Config().ClusterControlPlaneClient.UpdateAppsLinkedToEnvGroup(ctx, connect.NewRequest(&porterv1.UpdateAppsLinkedToEnvGroupRequest{
+				ProjectId:    int64(project.ID),
+				ClusterId:    int64(cluster.ID),
+				EnvGroupName: envGroupName,
+			}))
+			if err != nil {
+				err := telemetry.Error(ctx, span, err, "error calling ccp update apps linked to env group")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+		}
+	}
+
+	res := &RollbackEnvGroupResponse{
+		Version: EnvGroupVersion{
+			Version:         restored.Version,
+			Variables:       restored.Variables,
+			SecretVariables: restored.SecretVariables,
+			CreatedAtUTC:    restored.CreatedAtUTC,
+		},
+		LinkedApplications: linkedApplications,
+	}
+
+	c.WriteResult(w, r, res)
+}
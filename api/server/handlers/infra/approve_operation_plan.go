@@ -0,0 +1,79 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type InfraApproveOperationPlanHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewInfraApproveOperationPlanHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *InfraApproveOperationPlanHandler {
+	return &InfraApproveOperationPlanHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP approves the plan of a plan operation, and returns the apply operation which was started
+func (c *InfraApproveOperationPlanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	if operation.Type != ptypes.OperationTypePlan {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is not a plan", operation.UID),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	if operation.Status != ptypes.OperationStatusPlanned {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("plan cannot be approved as its operation has status %s", operation.Status),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	// the plan is stale if the infra was changed after it was computed
+	lastOperation, err := c.Repo().Infra().GetLatestOperation(infra)
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if lastOperation.UID != operation.UID {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("plan is stale as operation %s was started after it, please create a new plan", lastOperation.UID),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	workspaceID := models.GetWorkspaceID(infra, operation)
+
+	// call approve on the provisioner service
+	resp, err := c.Config().ProvisionerClient.ApprovePlan(context.Background(), workspaceID)
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, resp)
+}
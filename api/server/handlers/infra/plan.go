@@ -0,0 +1,55 @@
+package infra
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type InfraPlanHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewInfraPlanHandler(config *config.Config, decoderValidator shared.RequestDecoderValidator, writer shared.ResultWriter) *InfraPlanHandler {
+	return &InfraPlanHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP starts a plan operation, which computes the changes an update of the infra would make
+// without changing any resource. The plan can be reviewed and then approved into an update.
+func (c *InfraPlanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	req := &types.RetryInfraRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, req); !ok {
+		return
+	}
+
+	vals, ok := getUpdateValues(c, w, r, proj, infra, req)
+	if !ok {
+		return
+	}
+
+	// call plan on the provisioner service
+	resp, err := c.Config().ProvisionerClient.Plan(context.Background(), proj.ID, infra.ID, &ptypes.PlanBaseRequest{
+		Kind:          string(infra.Kind),
+		Values:        vals,
+		OperationKind: "update",
+	})
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, resp)
+}
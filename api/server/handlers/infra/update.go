@@ -36,6 +36,36 @@ func (c *InfraUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	vals, ok := getUpdateValues(c, w, r, proj, infra, req)
+	if !ok {
+		return
+	}
+
+	// call apply on the provisioner service
+	resp, err := c.Config().ProvisionerClient.Apply(context.Background(), proj.ID, infra.ID, &ptypes.ApplyBaseRequest{
+		Kind:          string(infra.Kind),
+		Values:        vals,
+		OperationKind: "update",
+	})
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, resp)
+}
+
+// getUpdateValues verifies that the infra can be updated with the request, and returns the values
+// to apply. If no values are passed, the values of the last operation are used. If false is
+// returned, the error was already written to the response.
+func getUpdateValues(
+	c handlers.PorterHandler,
+	w http.ResponseWriter,
+	r *http.Request,
+	proj *models.Project,
+	infra *models.Infra,
+	req *types.RetryInfraRequest,
+) (map[string]interface{}, bool) {
 	var cluster *models.Cluster
 	var err error
 
@@ -51,7 +81,7 @@ func (c *InfraUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 			}
 
-			return
+			return nil, false
 		}
 	}
 
@@ -60,13 +90,13 @@ func (c *InfraUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
-		return
+		return nil, false
 	}
 
 	lastOperation, err := c.Repo().Infra().GetLatestOperation(infra)
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-		return
+		return nil, false
 	}
 
 	// if the last operation is in a "starting" state, block apply
@@ -76,7 +106,7 @@ func (c *InfraUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.StatusBadRequest,
 		))
 
-		return
+		return nil, false
 	}
 
 	// if the values are nil, get the last applied values and marshal them
@@ -87,7 +117,7 @@ func (c *InfraUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		if err != nil {
 			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
+			return nil, false
 		}
 	}
 
@@ -105,20 +135,9 @@ func (c *InfraUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			Cluster: cluster,
 			Values:  vals,
 		}); !ok {
-			return
+			return nil, false
 		}
 	}
 
-	// call apply on the provisioner service
-	resp, err := c.Config().ProvisionerClient.Apply(context.Background(), proj.ID, infra.ID, &ptypes.ApplyBaseRequest{
-		Kind:          string(infra.Kind),
-		Values:        vals,
-		OperationKind: "update",
-	})
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-		return
-	}
-
-	c.WriteResult(w, r, resp)
+	return vals, true
 }
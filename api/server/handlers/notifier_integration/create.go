@@ -0,0 +1,109 @@
+package notifier_integration
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/safehttp"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// CreateNotifierIntegrationHandler creates webhook, Microsoft Teams and PagerDuty notifier integrations
+type CreateNotifierIntegrationHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateNotifierIntegrationHandler returns a new CreateNotifierIntegrationHandler
+func NewCreateNotifierIntegrationHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateNotifierIntegrationHandler {
+	return &CreateNotifierIntegrationHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP validates the settings required by the kind of the integration and stores it in the project
+func (c *CreateNotifierIntegrationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-notifier-integration")
+	defer span.End()
+
+	user, _ := ctx.Value(types.UserScope).(*models.User)
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateNotifierIntegrationRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "integration-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "integration-kind", Value: string(request.Kind)},
+	)
+
+	if !request.Deployments && !request.Incidents {
+		err := telemetry.Error(ctx, span, nil, "integration must receive deployment or incident notifications")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	notifierInt := &integrations.NotifierIntegration{
+		ProjectID:   project.ID,
+		UserID:      user.ID,
+		Name:        request.Name,
+		Kind:        request.Kind,
+		Deployments: request.Deployments,
+		Incidents:   request.Incidents,
+	}
+
+	switch request.Kind {
+	case types.NotifierIntegrationKind_Webhook, types.NotifierIntegrationKind_MSTeams:
+		if request.URL == "" {
+			err := telemetry.Error(ctx, span, nil, "url is required")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		if err := safehttp.ValidateURL(request.URL); err != nil {
+			err := telemetry.Error(ctx, span, err, "invalid url")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		notifierInt.URL = []byte(request.URL)
+
+		if request.Kind == types.NotifierIntegrationKind_Webhook {
+			notifierInt.Secret = []byte(request.SigningSecret)
+		}
+	case types.NotifierIntegrationKind_PagerDuty:
+		if request.RoutingKey == "" {
+			err := telemetry.Error(ctx, span, nil, "routing key is required")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		notifierInt.Secret = []byte(request.RoutingKey)
+	}
+
+	notifierInt, err := c.Repo().NotifierIntegration().CreateNotifierIntegration(notifierInt)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating notifier integration")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	audit.RecordChange(ctx, nil, notifierInt.ToNotifierIntegrationType())
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, notifierInt.ToNotifierIntegrationType())
+}
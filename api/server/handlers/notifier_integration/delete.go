@@ -0,0 +1,71 @@
+package notifier_integration
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// DeleteNotifierIntegrationHandler deletes a notifier integration of a project
+type DeleteNotifierIntegrationHandler struct {
+	handlers.PorterHandler
+}
+
+// NewDeleteNotifierIntegrationHandler returns a new DeleteNotifierIntegrationHandler
+func NewDeleteNotifierIntegrationHandler(
+	config *config.Config,
+) *DeleteNotifierIntegrationHandler {
+	return &DeleteNotifierIntegrationHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+// ServeHTTP deletes the notifier integration if it belongs to the project
+func (c *DeleteNotifierIntegrationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-notifier-integration")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	integrationID, reqErr := requestutils.GetURLParamUint(r, types.URLParamNotifierIntegrationID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing notifier integration id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "integration-id", Value: integrationID})
+
+	notifierInts, err := c.Repo().NotifierIntegration().ListNotifierIntegrationsByProjectID(project.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing notifier integrations")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	for _, notifierInt := range notifierInts {
+		if notifierInt.ID == integrationID {
+			err = c.Repo().NotifierIntegration().DeleteNotifierIntegration(notifierInt.ID)
+			if err != nil {
+				err = telemetry.Error(ctx, span, err, "error deleting notifier integration")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+				return
+			}
+
+			audit.RecordChange(ctx, notifierInt.ToNotifierIntegrationType(), nil)
+
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	err = telemetry.Error(ctx, span, nil, "notifier integration not found")
+	c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+}
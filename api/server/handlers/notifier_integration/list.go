@@ -0,0 +1,51 @@
+package notifier_integration
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListNotifierIntegrationsHandler lists the notifier integrations of a project
+type ListNotifierIntegrationsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListNotifierIntegrationsHandler returns a new ListNotifierIntegrationsHandler
+func NewListNotifierIntegrationsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListNotifierIntegrationsHandler {
+	return &ListNotifierIntegrationsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the notifier integrations of the project, without their secrets
+func (c *ListNotifierIntegrationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-notifier-integrations")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	notifierInts, err := c.Repo().NotifierIntegration().ListNotifierIntegrationsByProjectID(project.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing notifier integrations")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := make(types.ListNotifierIntegrationsResponse, 0)
+
+	for _, notifierInt := range notifierInts {
+		res = append(res, notifierInt.ToNotifierIntegrationType())
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,139 @@
+package opa_policy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/opa"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// CreateOPAPolicyBundleHandler handles uploads of user-supplied OPA policy bundles
+type CreateOPAPolicyBundleHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateOPAPolicyBundleHandler returns a new CreateOPAPolicyBundleHandler
+func NewCreateOPAPolicyBundleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateOPAPolicyBundleHandler {
+	return &CreateOPAPolicyBundleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP validates a policy bundle and stores it in the project, replacing any bundle with the same name
+func (c *CreateOPAPolicyBundleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-opa-policy-bundle")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateOPAPolicyBundleRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "bundle-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "bundle-kind", Value: request.Kind},
+	)
+
+	err := opa.ValidatePolicyBundle(ctx, &types.OPAPolicyBundle{
+		Name:             request.Name,
+		Kind:             request.Kind,
+		Match:            request.Match,
+		MustExist:        request.MustExist,
+		OverrideSeverity: request.OverrideSeverity,
+		Enforcement:      request.Enforcement,
+		Policies:         request.Policies,
+	})
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "invalid policy bundle")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	match, err := json.Marshal(request.Match)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error marshaling match parameters")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	policies, err := json.Marshal(request.Policies)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error marshaling policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	bundle, err := c.Repo().OPAPolicyBundle().ReadOPAPolicyBundleByName(ctx, project.ID, request.Name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err = telemetry.Error(ctx, span, err, "error reading policy bundle")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	var before *types.OPAPolicyBundle
+	if bundle != nil {
+		before, err = bundle.ToOPAPolicyBundleType()
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error converting policy bundle")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	} else {
+		bundle = &models.OPAPolicyBundle{
+			ProjectID: project.ID,
+			Name:      request.Name,
+		}
+	}
+
+	bundle.Kind = request.Kind
+	bundle.Match = match
+	bundle.MustExist = request.MustExist
+	bundle.OverrideSeverity = request.OverrideSeverity
+	bundle.Enforcement = string(request.Enforcement)
+	bundle.Policies = policies
+
+	if bundle.ID == 0 {
+		bundle, err = c.Repo().OPAPolicyBundle().CreateOPAPolicyBundle(ctx, bundle)
+	} else {
+		bundle, err = c.Repo().OPAPolicyBundle().UpdateOPAPolicyBundle(ctx, bundle)
+	}
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving policy bundle")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res, err := bundle.ToOPAPolicyBundleType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error converting policy bundle")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if before != nil {
+		audit.RecordChange(ctx, before, res)
+	} else {
+		audit.RecordChange(ctx, nil, res)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, res)
+}
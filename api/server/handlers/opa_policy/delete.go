@@ -0,0 +1,80 @@
+package opa_policy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// DeleteOPAPolicyBundleHandler deletes a user-supplied OPA policy bundle
+type DeleteOPAPolicyBundleHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewDeleteOPAPolicyBundleHandler returns a new DeleteOPAPolicyBundleHandler
+func NewDeleteOPAPolicyBundleHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteOPAPolicyBundleHandler {
+	return &DeleteOPAPolicyBundleHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP deletes the policy bundle. Results of its policies are archived by the next recommender run.
+func (c *DeleteOPAPolicyBundleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-opa-policy-bundle")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	bundleID, reqErr := requestutils.GetURLParamUint(r, types.URLParamOPAPolicyBundleID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, nil, "error parsing policy bundle id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "bundle-id", Value: bundleID})
+
+	bundle, err := c.Repo().OPAPolicyBundle().ReadOPAPolicyBundle(ctx, project.ID, bundleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "policy bundle not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading policy bundle")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	before, err := bundle.ToOPAPolicyBundleType()
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error converting policy bundle")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	err = c.Repo().OPAPolicyBundle().DeleteOPAPolicyBundle(ctx, bundle)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting policy bundle")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	audit.RecordChange(ctx, before, nil)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,57 @@
+package opa_policy
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListOPAPolicyBundlesHandler lists the user-supplied OPA policy bundles of a project
+type ListOPAPolicyBundlesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListOPAPolicyBundlesHandler returns a new ListOPAPolicyBundlesHandler
+func NewListOPAPolicyBundlesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListOPAPolicyBundlesHandler {
+	return &ListOPAPolicyBundlesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the policy bundles of the project
+func (c *ListOPAPolicyBundlesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-opa-policy-bundles")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	bundles, err := c.Repo().OPAPolicyBundle().ListOPAPolicyBundlesByProjectID(ctx, project.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing policy bundles")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := make([]*types.OPAPolicyBundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		bundleType, err := bundle.ToOPAPolicyBundleType()
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error converting policy bundle")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		res = append(res, bundleType)
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	authzpolicy "github.com/porter-dev/porter/api/server/authz/policy"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
@@ -49,6 +50,15 @@ func (p *PolicyCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !authzpolicy.IsValidPolicy(req.Policy) {
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("policy documents must follow the scope heirarchy, starting at the project scope"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
 	uid, err := encryption.GenerateRandomBytes(16)
 	if err != nil {
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -1,6 +1,8 @@
 package porter_app
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"connectrpc.com/connect"
@@ -11,8 +13,11 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
 	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
 )
 
 // AttachEnvGroupHandler is the handler for the /apps/attach-env-group endpoint
@@ -46,6 +51,7 @@ func (c *AttachEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	defer span.End()
 
 	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+	cluster, _ := ctx.Value(types.ClusterScope).(*models.Cluster)
 
 	request := &AttachEnvGroupRequest{}
 	if ok := c.DecodeAndValidate(w, r, request); !ok {
@@ -62,15 +68,85 @@ func (c *AttachEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	agent, err := c.GetAgent(r, cluster, "")
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting kubernetes agent")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	// the cluster control plane syncs env groups to the namespaces of v2 apps without creating the ExternalSecrets which resolve them
+	externalSecrets, err := environment_groups.ExternalSecretsForEnvironmentGroup(ctx, agent, request.EnvGroupName)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting env group external secrets")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if len(externalSecrets.References) > 0 {
+		err := telemetry.Error(ctx, span, nil, "external secrets are not supported for env groups of v2 apps")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	appInstances := make([]*models.AppInstance, 0, len(request.AppInstanceIDs))
 	for _, appInstanceId := range request.AppInstanceIDs {
-		appInstance, err := c.Repo().AppInstance().Get(ctx, appInstanceId)
+		appInstance, err := c.Repo().AppInstance().Get(ctx, project.ID, appInstanceId)
 		if err != nil {
 			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-instance-id", Value: appInstanceId})
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err := telemetry.Error(ctx, span, err, "app instance not found")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+				return
+			}
 			err := telemetry.Error(ctx, span, err, "error getting app instance")
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
 
+		appInstances = append(appInstances, appInstance)
+	}
+
+	// every app is checked against the project policies before any of them is updated, so that a denied app does not leave the env group partially attached
+	for _, appInstance := range appInstances {
+		deploymentTargetIdentifier := &porterv1.DeploymentTargetIdentifier{
+			Id: appInstance.DeploymentTargetID.String(),
+		}
+
+		current, err := currentApp(ctx, currentAppInput{
+			ProjectID:                  project.ID,
+			ClusterID:                  cluster.ID,
+			AppName:                    appInstance.Name,
+			DeploymentTargetIdentifier: deploymentTargetIdentifier,
+			ClusterControlPlaneClient:  c.Config().ClusterControlPlaneClient,
+			PorterAppRepository:        c.Repo().PorterApp(),
+		})
+		if err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-instance-id", Value: appInstance.ID})
+			err := telemetry.Error(ctx, span, err, "error getting current app")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		policyOutput, err := porter_app.EvaluateAppPolicies(ctx, porter_app.EvaluateAppPoliciesInput{
+			ProjectID: project.ID,
+			App:       appWithEnvGroup(current, appInstance.Name, request.EnvGroupName),
+			Repo:      c.Repo().OPAPolicyBundle(),
+		})
+		if err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-instance-id", Value: appInstance.ID})
+			err := telemetry.Error(ctx, span, err, "error evaluating app policies")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+		if err := policyOutput.DenialsError(); err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-instance-id", Value: appInstance.ID})
+			err := telemetry.Error(ctx, span, err, fmt.Sprintf("app %s violates project policies", appInstance.Name))
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	for _, appInstance := range appInstances {
 		updateReq := connect.NewRequest(&porterv1.UpdateAppRequest{
 			ProjectId: int64(project.ID),
 			DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
@@ -86,15 +162,30 @@ func (c *AttachEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 			},
 		})
 
-		_, err = c.Config().ClusterControlPlaneClient.UpdateApp(ctx, updateReq)
+		_, err := c.Config().ClusterControlPlaneClient.UpdateApp(ctx, updateReq)
 		if err != nil {
-			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-instance-id", Value: appInstanceId})
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-instance-id", Value: appInstance.ID})
 			err := telemetry.Error(ctx, span, err, "error calling ccp update app")
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
 			return
 		}
-
 	}
 
 	c.WriteResult(w, r, nil)
 }
+
+// appWithEnvGroup returns the app as it will be deployed once the env group is attached. The cluster control plane
+// adds the env group to those already attached to the app, rather than replacing them.
+func appWithEnvGroup(current *porterv1.PorterApp, appName string, envGroupName string) *porterv1.PorterApp {
+	app := porter_app.MergeAppUpdate(current, &porterv1.PorterApp{Name: appName}, nil)
+
+	for _, envGroup := range app.EnvGroups {
+		if envGroup.Name == envGroupName {
+			return app
+		}
+	}
+
+	app.EnvGroups = append(app.EnvGroups, &porterv1.EnvGroup{Name: envGroupName})
+
+	return app
+}
@@ -269,6 +269,7 @@ func (c *CreatePorterAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 				dnsClient:     c.Config().DNSClient,
 				appRootDomain: c.Config().ServerConf.AppRootDomain,
 				stackName:     appName,
+				clusterID:     cluster.ID,
 			},
 			InjectLauncherToStartCommand: injectLauncher,
 			ShouldValidateHelmValues:     shouldCreate,
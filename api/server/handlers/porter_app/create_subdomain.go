@@ -108,6 +108,7 @@ func (c *CreateSubdomainHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		ReleaseName: request.ServiceName,
 		RootDomain:  c.Config().ServerConf.AppRootDomain,
 		Endpoint:    endpoint,
+		ClusterID:   cluster.ID,
 	}
 
 	record := createDomain.NewDNSRecordForEndpoint()
@@ -0,0 +1,79 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+type currentAppInput struct {
+	ProjectID                  uint
+	ClusterID                  uint
+	AppName                    string
+	DeploymentTargetIdentifier *porterv1.DeploymentTargetIdentifier
+	ClusterControlPlaneClient  porterv1connect.ClusterControlPlaneServiceClient
+	PorterAppRepository        repository.PorterAppRepository
+}
+
+// currentApp returns the app of the current revision of an app in a deployment target, or nil if the app was never deployed to it.
+// If no deployment target is specified, the default deployment target of the cluster is used.
+func currentApp(ctx context.Context, inp currentAppInput) (*porterv1.PorterApp, error) {
+	ctx, span := telemetry.NewSpan(ctx, "current-app")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: inp.ProjectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: inp.ClusterID},
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+	)
+
+	app, err := inp.PorterAppRepository.ReadPorterAppByName(inp.ClusterID, inp.AppName)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error reading porter app by name")
+	}
+	if app == nil || app.ID == 0 {
+		return nil, nil
+	}
+
+	deploymentTargetIdentifier := inp.DeploymentTargetIdentifier
+	if deploymentTargetIdentifier == nil {
+		defaultDeploymentTarget, err := defaultDeploymentTarget(ctx, defaultDeploymentTargetInput{
+			ProjectID:                 inp.ProjectID,
+			ClusterID:                 inp.ClusterID,
+			ClusterControlPlaneClient: inp.ClusterControlPlaneClient,
+		})
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error getting default deployment target")
+		}
+
+		deploymentTargetIdentifier = &porterv1.DeploymentTargetIdentifier{
+			Id: defaultDeploymentTarget.ID.String(),
+		}
+	}
+
+	currentAppRevisionResp, err := inp.ClusterControlPlaneClient.CurrentAppRevision(ctx, connect.NewRequest(&porterv1.CurrentAppRevisionRequest{
+		ProjectId:                  int64(inp.ProjectID),
+		AppId:                      int64(app.ID),
+		AppName:                    inp.AppName,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+	}))
+	if err != nil {
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) && connectErr.Code() == connect.CodeNotFound {
+			return nil, nil
+		}
+
+		return nil, telemetry.Error(ctx, span, err, "error getting current app revision from cluster control plane client")
+	}
+
+	if currentAppRevisionResp == nil || currentAppRevisionResp.Msg == nil || currentAppRevisionResp.Msg.AppRevision == nil {
+		return nil, nil
+	}
+
+	return currentAppRevisionResp.Msg.AppRevision.App, nil
+}
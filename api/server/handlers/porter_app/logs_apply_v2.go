@@ -50,6 +50,8 @@ type AppLogsRequest struct {
 	Direction            string    `schema:"direction"`
 	AppRevisionID        string    `schema:"app_revision_id"`
 	JobRunName           string    `schema:"job_run_name"`
+	// PodName limits the logs to a single pod of the app. Only supported when getting historical logs
+	PodName string `schema:"pod_name"`
 }
 
 const (
@@ -163,6 +165,7 @@ func (c *AppLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		telemetry.AttributeKV{Key: "end-range", Value: request.EndRange.String()},
 		telemetry.AttributeKV{Key: "limit", Value: limit},
 		telemetry.AttributeKV{Key: "direction", Value: direction},
+		telemetry.AttributeKV{Key: "pod-name", Value: request.PodName},
 	)
 
 	k8sAgent, err := c.GetAgent(r, cluster, "")
@@ -179,10 +182,52 @@ func (c *AppLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if request.PodName != "" {
+		logs, err := porter_agent.GetHistoricalLogs(ctx, k8sAgent.Clientset, agentSvc, &types.GetLogRequest{
+			Limit:       limit,
+			StartRange:  &startRange,
+			EndRange:    &endRange,
+			PodSelector: request.PodName,
+			Namespace:   namespace,
+			Direction:   direction,
+			SearchParam: request.SearchParam,
+		})
+		if err != nil {
+			_ = telemetry.Error(ctx, span, err, "unable to get logs for pod")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("unable to get logs for pod %s", request.PodName), http.StatusInternalServerError))
+			return
+		}
+		if logs == nil {
+			err := telemetry.Error(ctx, span, nil, "logs response is nil")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		// the pod selector is not scoped to the app, so the logs are filtered by the app's labels
+		filter := porter_app.StructuredLogFilter{
+			AppName:            appName,
+			DeploymentTargetID: deploymentTarget.ID,
+			AppRevisionID:      request.AppRevisionID,
+			PodName:            request.PodName,
+		}
+		if request.ServiceName != "all" {
+			filter.ServiceName = request.ServiceName
+		}
+
+		res := AppLogsResponse{
+			Logs:                 porter_app.FilterStructuredLogs(porter_app.AgentLogToStructuredLog(logs.Logs), filter),
+			ForwardContinueTime:  logs.ForwardContinueTime,
+			BackwardContinueTime: logs.BackwardContinueTime,
+		}
+
+		c.WriteResult(w, r, res)
+		return
+	}
+
 	matchLabels := map[string]string{
 		lokiLabel_Namespace:          namespace,
 		lokiLabel_PorterAppName:      appName,
-		lokiLabel_DeploymentTargetId: request.DeploymentTargetID,
+		lokiLabel_DeploymentTargetId: deploymentTarget.ID,
 	}
 
 	if request.ServiceName != "all" {
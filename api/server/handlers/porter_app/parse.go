@@ -75,6 +75,7 @@ type SubdomainCreateOpts struct {
 	dnsClient     *dns.Client
 	appRootDomain string
 	stackName     string
+	clusterID     uint
 }
 
 type ParseConf struct {
@@ -767,6 +768,7 @@ func createDNSRecord(opts SubdomainCreateOpts) (*types.DNSRecord, error) {
 		ReleaseName: opts.stackName,
 		RootDomain:  opts.appRootDomain,
 		Endpoint:    endpoint,
+		ClusterID:   opts.clusterID,
 	}
 
 	record := createDomain.NewDNSRecordForEndpoint()
@@ -57,6 +57,8 @@ type ParsePorterYAMLToProtoResponse struct {
 	EncodedAppWithEnv
 	// PreviewApp contains preview environment specific overrides, if they exist
 	PreviewApp *EncodedAppWithEnv `json:"preview_app,omitempty"`
+	// PolicyWarnings are the violations of project policies which do not prevent the app from being applied
+	PolicyWarnings []types.PorterAppPolicyViolation `json:"policy_warnings,omitempty"`
 }
 
 // ServeHTTP receives a base64-encoded porter.yaml, parses the version, and then translates it into a base64-encoded app proto object
@@ -116,7 +118,25 @@ func (c *ParsePorterYAMLToProtoHandler) ServeHTTP(w http.ResponseWriter, r *http
 		return
 	}
 
-	response := &ParsePorterYAMLToProtoResponse{}
+	policyOutput, err := porter_app.EvaluateAppPolicies(ctx, porter_app.EvaluateAppPoliciesInput{
+		ProjectID: project.ID,
+		App:       patchedProto,
+		Repo:      c.Repo().OPAPolicyBundle(),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error evaluating app policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if err := policyOutput.DenialsError(); err != nil {
+		err := telemetry.Error(ctx, span, err, "app violates project policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	response := &ParsePorterYAMLToProtoResponse{
+		PolicyWarnings: policyOutput.Warnings,
+	}
 
 	encodedApp, err := encodeAppProto(ctx, patchedProto)
 	if err != nil {
@@ -1,10 +1,13 @@
 package porter_app
 
 import (
+	"context"
 	"net/http"
+	"sort"
 
 	"connectrpc.com/connect"
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
@@ -13,6 +16,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
 	"github.com/porter-dev/porter/internal/telemetry"
 )
 
@@ -107,15 +111,50 @@ func (c *RollbackAppRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 		telemetry.AttributeKV{Key: "deployment-target-name", Value: request.DeploymentTargetName},
 	)
 
+	deploymentTargetIdentifier := &porterv1.DeploymentTargetIdentifier{
+		Id:   request.DeploymentTargetID,
+		Name: deploymentTargetName,
+	}
+
+	targetRevision, err := rollbackTargetRevision(ctx, rollbackTargetRevisionInput{
+		ProjectID:                  project.ID,
+		AppID:                      app.ID,
+		AppName:                    appName,
+		AppRevisionID:              request.AppRevisionID,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+		ClusterControlPlaneClient:  c.Config().ClusterControlPlaneClient,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting rollback target revision")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "target-app-revision-id", Value: targetRevision.Id})
+
+	// the target revision may have been deployed before the current policies were added
+	policyOutput, err := porter_app.EvaluateAppPolicies(ctx, porter_app.EvaluateAppPoliciesInput{
+		ProjectID: project.ID,
+		App:       targetRevision.App,
+		Repo:      c.Repo().OPAPolicyBundle(),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error evaluating app policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if err := policyOutput.DenialsError(); err != nil {
+		err := telemetry.Error(ctx, span, err, "app violates project policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	rollbackReq := connect.NewRequest(&porterv1.RollbackRevisionRequest{
-		ProjectId: int64(project.ID),
-		AppId:     int64(app.ID),
-		DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
-			Id:   request.DeploymentTargetID,
-			Name: deploymentTargetName,
-		},
-		AppRevisionId: request.AppRevisionID,
-		AppName:       appName,
+		ProjectId:                  int64(project.ID),
+		AppId:                      int64(app.ID),
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+		AppRevisionId:              targetRevision.Id,
+		AppName:                    appName,
 	})
 	ccpResp, err := c.Config().ClusterControlPlaneClient.RollbackRevision(ctx, rollbackReq)
 	if err != nil {
@@ -144,3 +183,69 @@ func (c *RollbackAppRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 		TargetRevisionNumber: int(ccpResp.Msg.TargetRevisionNumber),
 	})
 }
+
+// deployedRevisionStatuses are the statuses of revisions which were successfully deployed, and so can be rolled back to
+var deployedRevisionStatuses = map[string]bool{
+	string(models.AppRevisionStatus_InstallSuccessful):    true,
+	string(models.AppRevisionStatus_DeploymentSuccessful): true,
+	string(models.AppRevisionStatus_DeploymentSuperseded): true,
+	string(models.AppRevisionStatus_RollbackSuccessful):   true,
+}
+
+type rollbackTargetRevisionInput struct {
+	ProjectID                  uint
+	AppID                      uint
+	AppName                    string
+	AppRevisionID              string
+	DeploymentTargetIdentifier *porterv1.DeploymentTargetIdentifier
+	ClusterControlPlaneClient  porterv1connect.ClusterControlPlaneServiceClient
+}
+
+// rollbackTargetRevision returns the revision to roll back to. If no revision is specified, the most recent successfully deployed
+// revision before the current one is used, so that the app which is rolled back to is known before the rollback.
+func rollbackTargetRevision(ctx context.Context, inp rollbackTargetRevisionInput) (*porterv1.AppRevision, error) {
+	ctx, span := telemetry.NewSpan(ctx, "rollback-target-revision")
+	defer span.End()
+
+	if inp.AppRevisionID != "" {
+		revisionResp, err := inp.ClusterControlPlaneClient.GetAppRevision(ctx, connect.NewRequest(&porterv1.GetAppRevisionRequest{
+			ProjectId:     int64(inp.ProjectID),
+			AppRevisionId: inp.AppRevisionID,
+		}))
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error getting app revision")
+		}
+		if revisionResp == nil || revisionResp.Msg == nil || revisionResp.Msg.AppRevision == nil {
+			return nil, telemetry.Error(ctx, span, nil, "app revision not found")
+		}
+
+		return revisionResp.Msg.AppRevision, nil
+	}
+
+	revisionsResp, err := inp.ClusterControlPlaneClient.ListAppRevisions(ctx, connect.NewRequest(&porterv1.ListAppRevisionsRequest{
+		ProjectId:                  int64(inp.ProjectID),
+		AppId:                      int64(inp.AppID),
+		AppName:                    inp.AppName,
+		DeploymentTargetIdentifier: inp.DeploymentTargetIdentifier,
+	}))
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error listing app revisions")
+	}
+	if revisionsResp == nil || revisionsResp.Msg == nil {
+		return nil, telemetry.Error(ctx, span, nil, "list app revisions response is nil")
+	}
+
+	revisions := append([]*porterv1.AppRevision{}, revisionsResp.Msg.AppRevisions...)
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].RevisionNumber > revisions[j].RevisionNumber
+	})
+
+	// the most recent revision is the current one
+	for i := 1; i < len(revisions); i++ {
+		if deployedRevisionStatuses[revisions[i].Status] {
+			return revisions[i], nil
+		}
+	}
+
+	return nil, telemetry.Error(ctx, span, nil, "no previously deployed revision to roll back to")
+}
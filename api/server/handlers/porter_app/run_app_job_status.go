@@ -101,6 +101,11 @@ func (c *AppJobRunStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	}
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "service-name", Value: request.ServiceName})
 
+	deploymentTargetIdentifier := request.DeploymentTargetID
+	if deploymentTargetIdentifier == "" {
+		deploymentTargetIdentifier = request.DeploymentTargetName
+	}
+
 	deploymentTargetName := request.DeploymentTargetName
 	if request.DeploymentTargetName == "" && request.DeploymentTargetID == "" {
 		defaultDeploymentTarget, err := defaultDeploymentTarget(ctx, defaultDeploymentTargetInput{
@@ -114,7 +119,16 @@ func (c *AppJobRunStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			return
 		}
 		deploymentTargetName = defaultDeploymentTarget.Name
+		deploymentTargetIdentifier = defaultDeploymentTarget.ID.String()
+	}
+
+	// this route reads the deployment target from its query, so restricted api tokens are checked here
+	if reqErr := authz.CheckAPITokenDeploymentTarget(ctx, c.Repo(), project.ID, deploymentTargetIdentifier); reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "api token cannot access deployment target")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
+		return
 	}
+
 	telemetry.WithAttributes(span,
 		telemetry.AttributeKV{Key: "deployment-target-name", Value: deploymentTargetName},
 		telemetry.AttributeKV{Key: "deployment-target-id", Value: request.DeploymentTargetID},
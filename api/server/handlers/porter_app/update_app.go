@@ -14,6 +14,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/deployment_target"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/porter_app"
 	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
@@ -107,6 +108,8 @@ type UpdateAppRequest struct {
 type UpdateAppResponse struct {
 	AppName       string `json:"app_name"`
 	AppRevisionId string `json:"app_revision_id"`
+	// PolicyWarnings are the violations of project policies which did not prevent the app from being applied
+	PolicyWarnings []types.PorterAppPolicyViolation `json:"policy_warnings,omitempty"`
 }
 
 // ServeHTTP translates the request into an UpdateApp request, forwards to the cluster control plane, and returns the response
@@ -156,7 +159,9 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	)
 
 	var addons, addonOverrides []*porterv1.Addon
+	var chartAddons, chartAddonOverrides []v2.Addon
 	var overrides *porterv1.PorterApp
+	var managesHelmOverrides bool
 	appProto := &porterv1.PorterApp{}
 
 	var previewEnvVariables map[string]string
@@ -221,10 +226,13 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		if appFromYaml.PreviewApp != nil {
 			overrides = appFromYaml.PreviewApp.AppProto
 			addonOverrides = appFromYaml.PreviewApp.Addons
+			chartAddonOverrides = appFromYaml.PreviewApp.ChartAddons
 			previewEnvVariables = appFromYaml.PreviewApp.EnvVariables
 		}
 
 		addons = appFromYaml.Addons
+		chartAddons = appFromYaml.ChartAddons
+		managesHelmOverrides = appFromYaml.ManagesHelmOverrides
 	}
 
 	if appProto != nil {
@@ -246,6 +254,66 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		appProto.Name = request.Name
 	}
 
+	if reqErr := authz.CheckAPITokenApp(ctx, appProto.Name); reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "api token cannot access app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusForbidden))
+		return
+	}
+
+	if request.ImageTagOverride != "" {
+		if appProto.Image == nil {
+			appProto.Image = &porterv1.AppImage{}
+		}
+		appProto.Image.Tag = request.ImageTagOverride
+	}
+
+	current, err := currentApp(ctx, currentAppInput{
+		ProjectID:                  project.ID,
+		ClusterID:                  cluster.ID,
+		AppName:                    appProto.Name,
+		DeploymentTargetIdentifier: deploymentTargetIdentifer,
+		ClusterControlPlaneClient:  c.Config().ClusterControlPlaneClient,
+		PorterAppRepository:        c.Repo().PorterApp(),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting current app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	// porter.yaml declares the sidecars, init containers and volumes of the app, so they replace the ones of the current revision,
+	// including when all of them were removed
+	if managesHelmOverrides {
+		var currentHelmOverrides *porterv1.HelmOverrides
+		if current != nil {
+			currentHelmOverrides = current.HelmOverrides
+		}
+
+		appProto.HelmOverrides, err = v2.MergeHelmOverrides(currentHelmOverrides, appProto.HelmOverrides)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error merging helm overrides")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	// the request may only contain part of the app, so policies are evaluated against the app which will be deployed
+	policyOutput, err := porter_app.EvaluateAppPolicies(ctx, porter_app.EvaluateAppPoliciesInput{
+		ProjectID: project.ID,
+		App:       porter_app.MergeAppUpdate(current, appProto, request.Deletions.ServiceNames),
+		Repo:      c.Repo().OPAPolicyBundle(),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error evaluating app policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if err := policyOutput.DenialsError(); err != nil {
+		err := telemetry.Error(ctx, span, err, "app violates project policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	sourceType, image, err := sourceFromAppAndGitSource(ctx, appProto, request.GitSource)
 	if err != nil {
 		err := telemetry.Error(ctx, span, err, "error getting source from app and git source")
@@ -283,11 +351,22 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if request.ImageTagOverride != "" {
-		if appProto.Image == nil {
-			appProto.Image = &porterv1.AppImage{}
+	// chart addons removed from porter.yaml are uninstalled, so addons are reconciled on every porter.yaml apply
+	if request.Base64PorterYAML != "" {
+		err = c.installChartAddons(ctx, r, installChartAddonsInput{
+			AppName:              appProto.Name,
+			Project:              project,
+			Cluster:              cluster,
+			DeploymentTargetID:   deploymentTargetID,
+			DeploymentTargetName: deploymentTargetName,
+			ChartAddons:          chartAddons,
+			PreviewChartAddons:   chartAddonOverrides,
+		})
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "error installing chart addons")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
 		}
-		appProto.Image.Tag = request.ImageTagOverride
 	}
 
 	updateReq := connect.NewRequest(&porterv1.UpdateAppRequest{
@@ -349,13 +428,126 @@ func (c *UpdateAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "resp-app-revision-id", Value: ccpResp.Msg.AppRevisionId})
 
 	response := &UpdateAppResponse{
-		AppRevisionId: ccpResp.Msg.AppRevisionId,
-		AppName:       appProto.Name,
+		AppRevisionId:  ccpResp.Msg.AppRevisionId,
+		AppName:        appProto.Name,
+		PolicyWarnings: policyOutput.Warnings,
 	}
 
 	c.WriteResult(w, r, response)
 }
 
+type installChartAddonsInput struct {
+	AppName              string
+	Project              *models.Project
+	Cluster              *models.Cluster
+	DeploymentTargetID   string
+	DeploymentTargetName string
+	ChartAddons          []v2.Addon
+	// PreviewChartAddons are the chart addons declared under previews in porter.yaml, which replace chart addons of the same name in preview deployment targets
+	PreviewChartAddons []v2.Addon
+}
+
+// installChartAddons installs the addons that are not deployed by the cluster control plane into the namespace of the deployment target,
+// and uninstalls the addons previously installed for the app which are no longer declared
+func (c *UpdateAppHandler) installChartAddons(ctx context.Context, r *http.Request, inp installChartAddonsInput) error {
+	ctx, span := telemetry.NewSpan(ctx, "install-chart-addons")
+	defer span.End()
+
+	var namespace string
+	var isPreview bool
+
+	if inp.DeploymentTargetID != "" || inp.DeploymentTargetName != "" {
+		deploymentTarget, err := deployment_target.DeploymentTargetDetails(ctx, deployment_target.DeploymentTargetDetailsInput{
+			ProjectID:            int64(inp.Project.ID),
+			ClusterID:            int64(inp.Cluster.ID),
+			DeploymentTargetID:   inp.DeploymentTargetID,
+			DeploymentTargetName: inp.DeploymentTargetName,
+			CCPClient:            c.Config().ClusterControlPlaneClient,
+		})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error getting deployment target details")
+		}
+
+		namespace = deploymentTarget.Namespace
+		isPreview = deploymentTarget.IsPreview
+	} else {
+		deploymentTarget, err := defaultDeploymentTarget(ctx, defaultDeploymentTargetInput{
+			ProjectID:                 inp.Project.ID,
+			ClusterID:                 inp.Cluster.ID,
+			ClusterControlPlaneClient: c.Config().ClusterControlPlaneClient,
+		})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error getting default deployment target")
+		}
+
+		namespace = deploymentTarget.Namespace
+	}
+
+	chartAddons := inp.ChartAddons
+	if isPreview {
+		chartAddons = mergeChartAddons(inp.ChartAddons, inp.PreviewChartAddons)
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "namespace", Value: namespace},
+		telemetry.AttributeKV{Key: "is-preview", Value: isPreview},
+	)
+
+	helmAgent, err := c.GetHelmAgent(ctx, r, inp.Cluster, namespace)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error getting helm agent")
+	}
+
+	agent, err := c.GetAgent(r, inp.Cluster, "")
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error getting kubernetes agent")
+	}
+
+	err = porter_app.InstallChartAddons(ctx, porter_app.InstallChartAddonsInput{
+		AppName:                     inp.AppName,
+		Addons:                      chartAddons,
+		Namespace:                   namespace,
+		AddonHelmRepoURL:            c.Config().ServerConf.DefaultAddonHelmRepoURL,
+		Cluster:                     inp.Cluster,
+		HelmAgent:                   helmAgent,
+		K8sAgent:                    agent,
+		Repo:                        c.Repo(),
+		DOConf:                      c.Config().DOConf,
+		DisablePullSecretsInjection: c.Config().ServerConf.DisablePullSecretsInjection,
+	})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error installing chart addons")
+	}
+
+	return nil
+}
+
+// mergeChartAddons replaces base chart addons with overrides of the same name, and appends overrides that do not exist in the base
+func mergeChartAddons(base, overrides []v2.Addon) []v2.Addon {
+	overridesByName := make(map[string]v2.Addon)
+	for _, addon := range overrides {
+		overridesByName[addon.Name] = addon
+	}
+
+	var merged []v2.Addon
+	for _, addon := range base {
+		if override, ok := overridesByName[addon.Name]; ok {
+			merged = append(merged, override)
+			delete(overridesByName, addon.Name)
+			continue
+		}
+		merged = append(merged, addon)
+	}
+
+	for _, addon := range overrides {
+		if _, ok := overridesByName[addon.Name]; ok {
+			merged = append(merged, addon)
+		}
+	}
+
+	return merged
+}
+
 func sourceFromAppAndGitSource(ctx context.Context, appProto *porterv1.PorterApp, gitSource GitSource) (porter_app.SourceType, *porter_app.Image, error) {
 	ctx, span := telemetry.NewSpan(ctx, "source-from-app-and-git-source")
 	defer span.End()
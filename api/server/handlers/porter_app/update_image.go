@@ -13,6 +13,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
 	"github.com/porter-dev/porter/internal/telemetry"
 )
 
@@ -102,15 +103,52 @@ func (c *UpdateImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		telemetry.AttributeKV{Key: "deployment-target-name", Value: request.DeploymentTargetName},
 	)
 
+	deploymentTargetIdentifier := &porterv1.DeploymentTargetIdentifier{
+		Id:   request.DeploymentTargetID,
+		Name: deploymentTargetName,
+	}
+
+	current, err := currentApp(ctx, currentAppInput{
+		ProjectID:                  project.ID,
+		ClusterID:                  cluster.ID,
+		AppName:                    appName,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
+		ClusterControlPlaneClient:  c.Config().ClusterControlPlaneClient,
+		PorterAppRepository:        c.Repo().PorterApp(),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error getting current app")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	policyOutput, err := porter_app.EvaluateAppPolicies(ctx, porter_app.EvaluateAppPoliciesInput{
+		ProjectID: project.ID,
+		App: porter_app.MergeAppUpdate(current, &porterv1.PorterApp{
+			Image: &porterv1.AppImage{
+				Repository: request.Repository,
+				Tag:        request.Tag,
+			},
+		}, nil),
+		Repo: c.Repo().OPAPolicyBundle(),
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error evaluating app policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+	if err := policyOutput.DenialsError(); err != nil {
+		err := telemetry.Error(ctx, span, err, "app violates project policies")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	updateImageReq := connect.NewRequest(&porterv1.UpdateAppImageRequest{
-		ProjectId:     int64(project.ID),
-		RepositoryUrl: request.Repository,
-		Tag:           request.Tag,
-		AppName:       appName,
-		DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
-			Id:   request.DeploymentTargetID,
-			Name: deploymentTargetName,
-		},
+		ProjectId:                  int64(project.ID),
+		RepositoryUrl:              request.Repository,
+		Tag:                        request.Tag,
+		AppName:                    appName,
+		DeploymentTargetIdentifier: deploymentTargetIdentifier,
 	})
 	ccpResp, err := c.Config().ClusterControlPlaneClient.UpdateAppImage(ctx, updateImageReq)
 	if err != nil {
@@ -393,6 +393,10 @@ func filterNewServiceValues(service v2.Service) v2.Service {
 		IngressAnnotations:            service.IngressAnnotations,
 		DisableTLS:                    service.DisableTLS,
 		Sleep:                         service.Sleep,
+		Sidecars:                      service.Sidecars,
+		InitContainers:                service.InitContainers,
+		SharedVolumes:                 service.SharedVolumes,
+		Volumes:                       service.Volumes,
 	}
 }
 
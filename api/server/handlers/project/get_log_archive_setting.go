@@ -0,0 +1,51 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// GetLogArchiveSettingHandler handles GET requests to /projects/{project_id}/log_archive
+type GetLogArchiveSettingHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewGetLogArchiveSettingHandler returns a new GetLogArchiveSettingHandler
+func NewGetLogArchiveSettingHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetLogArchiveSettingHandler {
+	return &GetLogArchiveSettingHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the log archive setting of the project. Projects which never configured a log archive have archival disabled
+func (p *GetLogArchiveSettingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-get-log-archive-setting")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	setting, err := p.Repo().LogArchive().ReadLogArchiveSetting(ctx, project.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err := telemetry.Error(ctx, span, err, "error reading log archive setting")
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		setting = &models.LogArchiveSetting{ProjectID: project.ID}
+	}
+
+	p.WriteResult(w, r, setting.ToLogArchiveSettingType())
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListLogArchiveRangesHandler handles GET requests to /projects/{project_id}/log_archive/ranges
+type ListLogArchiveRangesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewListLogArchiveRangesHandler returns a new ListLogArchiveRangesHandler
+func NewListLogArchiveRangesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListLogArchiveRangesHandler {
+	return &ListLogArchiveRangesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP lists the ranges of time for which the logs of the services of the project are archived
+func (p *ListLogArchiveRangesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-log-archive-ranges")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.ListLogArchiveRangesRequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "invalid request")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "cluster-id", Value: request.ClusterID},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: request.DeploymentTargetID},
+		telemetry.AttributeKV{Key: "app-name", Value: request.AppName},
+		telemetry.AttributeKV{Key: "service-name", Value: request.ServiceName},
+	)
+
+	var deploymentTargetID uuid.UUID
+	if request.DeploymentTargetID != "" {
+		id, err := uuid.Parse(request.DeploymentTargetID)
+		if err != nil {
+			err := telemetry.Error(ctx, span, err, "invalid deployment target id")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+		deploymentTargetID = id
+	}
+
+	archives, err := p.Repo().LogArchive().ListLogArchives(ctx, project.ID, repository.LogArchiveFilter{
+		ClusterID:          request.ClusterID,
+		DeploymentTargetID: deploymentTargetID,
+		AppName:            request.AppName,
+		ServiceName:        request.ServiceName,
+		Since:              request.StartRange,
+		Until:              request.EndRange,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error listing log archives")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	checkpoints, err := p.Repo().LogArchive().ListLogArchiveCheckpoints(ctx, project.ID)
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error listing log archive checkpoints")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.ListLogArchiveRangesResponse{
+		ArchivedUntil: make(map[uint]time.Time),
+		Ranges:        porter_app.LogArchiveRanges(archives),
+	}
+
+	for _, checkpoint := range checkpoints {
+		if request.ClusterID != 0 && checkpoint.ClusterID != request.ClusterID {
+			continue
+		}
+		res.ArchivedUntil[checkpoint.ClusterID] = checkpoint.ArchivedUntil
+	}
+
+	p.WriteResult(w, r, res)
+}
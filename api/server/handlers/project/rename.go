@@ -7,6 +7,7 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models"
@@ -37,6 +38,8 @@ func (c *RenameProjectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	before := proj.ToProjectType(c.Config().LaunchDarklyClient)
+
 	if request.Name != "" && proj.Name != request.Name {
 		proj.Name = request.Name
 	}
@@ -47,5 +50,7 @@ func (c *RenameProjectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	audit.RecordChange(r.Context(), before, project.ToProjectType(c.Config().LaunchDarklyClient))
+
 	c.WriteResult(w, r, project.ToProjectType(c.Config().LaunchDarklyClient))
 }
@@ -0,0 +1,119 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/safehttp"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// UpdateLogArchiveSettingHandler handles POST requests to /projects/{project_id}/log_archive
+type UpdateLogArchiveSettingHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUpdateLogArchiveSettingHandler returns a new UpdateLogArchiveSettingHandler
+func NewUpdateLogArchiveSettingHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateLogArchiveSettingHandler {
+	return &UpdateLogArchiveSettingHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP configures where the app logs of the project are archived. Logs are archived by the log archiver worker job
+func (p *UpdateLogArchiveSettingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-update-log-archive-setting")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateLogArchiveSettingRequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "invalid request")
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "enabled", Value: request.Enabled},
+		telemetry.AttributeKV{Key: "aws-integration-id", Value: request.AWSIntegrationID},
+		telemetry.AttributeKV{Key: "bucket", Value: request.Bucket},
+	)
+
+	// the endpoint is requested by the log archiver, so it must not point at internal services
+	if request.Endpoint != "" {
+		if err := safehttp.ValidateURL(request.Endpoint); err != nil {
+			err := telemetry.Error(ctx, span, err, "invalid endpoint")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	// the integration is read with the project id, so that only the credentials of the project can be used
+	_, err := p.Repo().AWSIntegration().ReadAWSIntegration(project.ID, request.AWSIntegrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = telemetry.Error(ctx, span, err, "aws integration not found")
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(fmt.Errorf("aws integration %d not found in project", request.AWSIntegrationID), http.StatusBadRequest))
+			return
+		}
+
+		err := telemetry.Error(ctx, span, err, "error reading aws integration")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	existing, err := p.Repo().LogArchive().ReadLogArchiveSetting(ctx, project.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		err := telemetry.Error(ctx, span, err, "error reading log archive setting")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// logs are archived from when archival was last enabled, rather than from where it was stopped
+	var enabledAt time.Time
+	switch {
+	case request.Enabled && (existing == nil || !existing.Enabled):
+		enabledAt = time.Now().UTC()
+	case existing != nil:
+		enabledAt = existing.EnabledAt
+	}
+
+	setting, err := p.Repo().LogArchive().UpsertLogArchiveSetting(ctx, &models.LogArchiveSetting{
+		ProjectID:        project.ID,
+		Enabled:          request.Enabled,
+		EnabledAt:        enabledAt,
+		AWSIntegrationID: request.AWSIntegrationID,
+		Bucket:           request.Bucket,
+		Region:           request.Region,
+		Endpoint:         request.Endpoint,
+		Prefix:           request.Prefix,
+	})
+	if err != nil {
+		err := telemetry.Error(ctx, span, err, "error saving log archive setting")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if existing != nil {
+		audit.RecordChange(ctx, existing.ToLogArchiveSettingType(), setting.ToLogArchiveSettingType())
+	} else {
+		audit.RecordChange(ctx, nil, setting.ToLogArchiveSettingType())
+	}
+
+	p.WriteResult(w, r, setting.ToLogArchiveSettingType())
+}
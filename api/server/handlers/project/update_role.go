@@ -1,8 +1,10 @@
 package project
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/porter-dev/porter/api/server/authz/policy"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
@@ -43,6 +45,35 @@ func (p *RoleUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	role.Kind = types.RoleKind(request.Kind)
+	role.PolicyUID = ""
+
+	switch role.Kind {
+	case types.RoleAdmin, types.RoleDeveloper, types.RoleViewer:
+	case types.RoleCustom:
+		// custom roles must point to a policy created in the project, rather than a preset policy
+		if request.PolicyUID == "" || isPresetPolicyUID(request.PolicyUID) {
+			p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("custom roles must set the uid of a project policy"),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		if _, reqErr := policy.GetAPIPolicyFromUID(p.Repo().Policy(), proj.ID, request.PolicyUID); reqErr != nil {
+			p.HandleAPIError(w, r, reqErr)
+			return
+		}
+
+		role.PolicyUID = request.PolicyUID
+	default:
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("role kind must be admin, developer, viewer or custom"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
 
 	role, err = p.Repo().Project().UpdateProjectRole(proj.ID, role)
 
@@ -57,3 +88,7 @@ func (p *RoleUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	p.WriteResult(w, r, res)
 }
+
+func isPresetPolicyUID(uid string) bool {
+	return uid == string(types.RoleAdmin) || uid == string(types.RoleDeveloper) || uid == string(types.RoleViewer)
+}
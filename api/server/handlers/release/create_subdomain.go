@@ -53,6 +53,7 @@ func (c *CreateSubdomainHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		ReleaseName: name,
 		RootDomain:  c.Config().ServerConf.AppRootDomain,
 		Endpoint:    endpoint,
+		ClusterID:   cluster.ID,
 	}
 
 	record := createDomain.NewDNSRecordForEndpoint()
@@ -17,6 +17,7 @@ import (
 	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/notifiers"
 	"github.com/porter-dev/porter/internal/notifier/slack"
 	"github.com/porter-dev/porter/internal/stacks"
 	"github.com/stefanmcshane/helm/pkg/release"
@@ -162,6 +163,7 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 
 	slackInts, _ := c.Repo().SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+	notifierInts, _ := c.Repo().NotifierIntegration().ListNotifierIntegrationsByProjectID(cluster.ProjectID)
 
 	rel, releaseErr := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
 
@@ -177,7 +179,9 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		notifConf = conf.ToNotificationConfigType()
 	}
 
-	deplNotifier := slack.NewDeploymentNotifier(notifConf, slackInts...)
+	deplNotifiers := []notifier.Notifier{slack.NewDeploymentNotifier(notifConf, slackInts...)}
+	deplNotifiers = append(deplNotifiers, notifiers.NewDeploymentNotifiers(r.Context(), notifierInts)...)
+	deplNotifier := notifier.NewMultiDeploymentNotifier(notifConf, deplNotifiers...)
 
 	notifyOpts := &notifier.NotifyOpts{
 		ProjectID:   cluster.ProjectID,
@@ -15,6 +15,7 @@ import (
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/notifiers"
 	"github.com/porter-dev/porter/internal/notifier/slack"
 	"github.com/porter-dev/porter/internal/telemetry"
 	"gorm.io/gorm"
@@ -152,6 +153,7 @@ func (c *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	slackInts, _ := c.Repo().SlackIntegration().ListSlackIntegrationsByProjectID(release.ProjectID)
+	notifierInts, _ := c.Repo().NotifierIntegration().ListNotifierIntegrationsByProjectID(release.ProjectID)
 
 	var notifConf *types.NotificationConfig
 	notifConf = nil
@@ -166,7 +168,9 @@ func (c *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		notifConf = conf.ToNotificationConfigType()
 	}
 
-	deplNotifier := slack.NewDeploymentNotifier(notifConf, slackInts...)
+	deplNotifiers := []notifier.Notifier{slack.NewDeploymentNotifier(notifConf, slackInts...)}
+	deplNotifiers = append(deplNotifiers, notifiers.NewDeploymentNotifiers(ctx, notifierInts)...)
+	deplNotifier := notifier.NewMultiDeploymentNotifier(notifConf, deplNotifiers...)
 
 	notifyOpts := &notifier.NotifyOpts{
 		ProjectID:   release.ProjectID,
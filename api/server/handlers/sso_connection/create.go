@@ -0,0 +1,198 @@
+package sso_connection
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/sso"
+	"github.com/porter-dev/porter/internal/sso/oidc"
+	"github.com/porter-dev/porter/internal/sso/saml"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// CreateSSOConnectionHandler creates OIDC and SAML sso connections of a project
+type CreateSSOConnectionHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewCreateSSOConnectionHandler returns a new CreateSSOConnectionHandler
+func NewCreateSSOConnectionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateSSOConnectionHandler {
+	return &CreateSSOConnectionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP checks that the identity provider can be reached and that the domains are not verified by another
+// connection, and stores the connection in the project. Its domains are unverified until their verification
+// records are published.
+func (c *CreateSSOConnectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-create-sso-connection")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateSSOConnectionRequest{}
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		err := telemetry.Error(ctx, span, nil, "error decoding request")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "connection-name", Value: request.Name},
+		telemetry.AttributeKV{Key: "connection-kind", Value: string(request.Kind)},
+	)
+
+	domains := make([]models.SSODomain, 0, len(request.Domains))
+	seen := make(map[string]bool)
+
+	for _, domain := range request.Domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if seen[domain] {
+			continue
+		}
+
+		seen[domain] = true
+
+		// a domain can only be verified by one connection, since its users are sent to the identity provider
+		// of the connection when they log in. Other connections can claim it until it is verified.
+		_, err := c.Repo().SSOConnection().ReadSSOConnectionByDomain(domain)
+		if err == nil {
+			err := telemetry.Error(ctx, span, nil, "domain "+domain+" is already verified by an sso connection")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "error reading sso connection by domain")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		token, err := sso.NewDomainVerificationToken()
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error generating domain verification token")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		domains = append(domains, models.SSODomain{
+			Domain:            domain,
+			VerificationToken: token,
+		})
+	}
+
+	mappings, err := json.Marshal(request.GroupRoleMappings)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error encoding group role mappings")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	conn := &models.SSOConnection{
+		ProjectID:         project.ID,
+		UniqueID:          uuid.New().String(),
+		Name:              request.Name,
+		Kind:              request.Kind,
+		Domains:           domains,
+		AutoProvision:     request.AutoProvision,
+		DefaultRole:       request.DefaultRole,
+		GroupRoleMappings: mappings,
+	}
+
+	// the identity provider is reached through a client which cannot request internal services
+	httpClient := sso.NewIdPHTTPClient(c.Config().ServerConf.SSOAllowPrivateIdPs)
+
+	switch request.Kind {
+	case types.SSOConnectionKind_OIDC:
+		conn.IssuerURL = request.IssuerURL
+		conn.ClientID = request.ClientID
+		conn.ClientSecret = []byte(request.ClientSecret)
+		conn.Scopes = strings.Join(request.Scopes, " ")
+		conn.GroupsClaim = request.GroupsClaim
+
+		_, err := oidc.NewProvider(ctx, oidc.Config{
+			IssuerURL:  conn.IssuerURL,
+			ClientID:   conn.ClientID,
+			HTTPClient: httpClient,
+		})
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading oidc identity provider")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	case types.SSOConnectionKind_SAML:
+		conn.IdPMetadataURL = request.IdPMetadataURL
+		conn.IdPSSOURL = request.IdPSSOURL
+		conn.IdPEntityID = request.IdPEntityID
+		conn.IdPCertificate = request.IdPCertificate
+		conn.GroupsAttribute = request.GroupsAttribute
+
+		if conn.IdPMetadataURL != "" {
+			if _, err := saml.FetchIdPMetadata(ctx, httpClient, conn.IdPMetadataURL); err != nil {
+				err = telemetry.Error(ctx, span, err, "error reading saml identity provider metadata")
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+
+			break
+		}
+
+		if conn.IdPSSOURL == "" || conn.IdPEntityID == "" || conn.IdPCertificate == "" {
+			err := telemetry.Error(ctx, span, nil, "idp metadata url, or idp sso url, entity id and certificate are required")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		if _, err := saml.ParseCertificates(conn.IdPCertificate); err != nil {
+			err = telemetry.Error(ctx, span, err, "invalid idp certificate")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	conn, err = c.Repo().SSOConnection().CreateSSOConnection(conn)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error creating sso connection")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	audit.RecordChange(ctx, nil, toSSOConnectionType(c.Config().ServerConf.ServerURL, conn))
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, toSSOConnectionType(c.Config().ServerConf.ServerURL, conn))
+}
+
+// toSSOConnectionType converts the connection to its API type, including the urls which are registered
+// with the identity provider
+func toSSOConnectionType(serverURL string, conn *models.SSOConnection) *types.SSOConnection {
+	res := conn.ToSSOConnectionType()
+	res.LoginURL = sso.LoginURL(serverURL, conn.UniqueID)
+
+	switch conn.Kind {
+	case types.SSOConnectionKind_OIDC:
+		res.RedirectURL = sso.OIDCRedirectURL(serverURL, conn.UniqueID)
+	case types.SSOConnectionKind_SAML:
+		res.RedirectURL = sso.SAMLACSURL(serverURL, conn.UniqueID)
+		res.MetadataURL = sso.SAMLMetadataURL(serverURL, conn.UniqueID)
+	}
+
+	return res
+}
@@ -0,0 +1,72 @@
+package sso_connection
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// DeleteSSOConnectionHandler deletes an sso connection of a project
+type DeleteSSOConnectionHandler struct {
+	handlers.PorterHandler
+}
+
+// NewDeleteSSOConnectionHandler returns a new DeleteSSOConnectionHandler
+func NewDeleteSSOConnectionHandler(
+	config *config.Config,
+) *DeleteSSOConnectionHandler {
+	return &DeleteSSOConnectionHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+// ServeHTTP deletes the sso connection if it belongs to the project. Users who were provisioned through the
+// connection keep their accounts, and can log in again once a password is set through a password reset.
+func (c *DeleteSSOConnectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-delete-sso-connection")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	connID, reqErr := requestutils.GetURLParamUint(r, types.URLParamSSOConnectionID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing sso connection id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "sso-connection-id", Value: connID})
+
+	conn, err := c.Repo().SSOConnection().ReadSSOConnection(project.ID, connID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "sso connection not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading sso connection")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	if err := c.Repo().SSOConnection().DeleteSSOConnection(conn); err != nil {
+		err = telemetry.Error(ctx, span, err, "error deleting sso connection")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	audit.RecordChange(ctx, toSSOConnectionType(c.Config().ServerConf.ServerURL, conn), nil)
+
+	w.WriteHeader(http.StatusOK)
+}
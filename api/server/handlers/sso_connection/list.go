@@ -0,0 +1,51 @@
+package sso_connection
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ListSSOConnectionsHandler lists the sso connections of a project
+type ListSSOConnectionsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewListSSOConnectionsHandler returns a new ListSSOConnectionsHandler
+func NewListSSOConnectionsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListSSOConnectionsHandler {
+	return &ListSSOConnectionsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the sso connections of the project, without their client secrets
+func (c *ListSSOConnectionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-list-sso-connections")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	conns, err := c.Repo().SSOConnection().ListSSOConnectionsByProjectID(project.ID)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error listing sso connections")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	res := make(types.ListSSOConnectionsResponse, 0)
+
+	for _, conn := range conns {
+		res = append(res, toSSOConnectionType(c.Config().ServerConf.ServerURL, conn))
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,108 @@
+package sso_connection
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/sso"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// VerifySSODomainsHandler verifies the domains of an sso connection through their DNS TXT records
+type VerifySSODomainsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+// NewVerifySSODomainsHandler returns a new VerifySSODomainsHandler
+func NewVerifySSODomainsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *VerifySSODomainsHandler {
+	return &VerifySSODomainsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP verifies each unverified domain of the connection whose verification record contains its token, and
+// returns the connection. Domains whose records are not published yet stay unverified, and can be verified again
+// later. A domain which another connection has verified in the meantime cannot be verified.
+func (c *VerifySSODomainsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-verify-sso-domains")
+	defer span.End()
+
+	project, _ := ctx.Value(types.ProjectScope).(*models.Project)
+
+	connID, reqErr := requestutils.GetURLParamUint(r, types.URLParamSSOConnectionID)
+	if reqErr != nil {
+		err := telemetry.Error(ctx, span, reqErr, "error parsing sso connection id")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "sso-connection-id", Value: connID})
+
+	conn, err := c.Repo().SSOConnection().ReadSSOConnection(project.ID, connID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "sso connection not found")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusNotFound))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading sso connection")
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+		return
+	}
+
+	var resolver sso.TXTResolver = net.DefaultResolver
+	if c.Config().SSODomainResolver != nil {
+		resolver = c.Config().SSODomainResolver
+	}
+
+	for i := range conn.Domains {
+		domain := &conn.Domains[i]
+		if domain.VerifiedAt != nil {
+			continue
+		}
+
+		if err := sso.VerifyDomain(ctx, resolver, domain.Domain, domain.VerificationToken); err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "unverified-domain", Value: domain.Domain})
+			continue
+		}
+
+		verified, err := c.Repo().SSOConnection().ReadSSOConnectionByDomain(domain.Domain)
+		if err == nil && verified.ID != conn.ID {
+			err := telemetry.Error(ctx, span, nil, "domain "+domain.Domain+" is already verified by an sso connection")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusConflict))
+			return
+		}
+
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = telemetry.Error(ctx, span, err, "error reading sso connection by domain")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+
+		now := time.Now().UTC()
+		domain.VerifiedAt = &now
+
+		if _, err := c.Repo().SSOConnection().UpdateSSODomain(domain); err != nil {
+			err = telemetry.Error(ctx, span, err, "error verifying sso domain")
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, toSSOConnectionType(c.Config().ServerConf.ServerURL, conn))
+}
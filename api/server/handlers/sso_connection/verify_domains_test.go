@@ -0,0 +1,79 @@
+package sso_connection_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/porter-dev/porter/api/server/handlers/sso_connection"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// fakeResolver returns the TXT records of names from a map
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	return f[name], nil
+}
+
+func verifyDomains(t *testing.T, conf *config.Config, project *models.Project, conn *models.SSOConnection) *types.SSOConnection {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1/sso_connections/1/verify_domains", nil)
+	req = apitest.WithProject(t, req, project)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamSSOConnectionID): "1",
+	})
+
+	sso_connection.NewVerifySSODomainsHandler(conf, shared.NewDefaultResultWriter(conf.Logger, conf.Alerter)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &types.SSOConnection{}
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(res))
+
+	return res
+}
+
+func TestVerifySSODomains(t *testing.T) {
+	conf := apitest.LoadConfig(t)
+
+	project, err := conf.Repo.Project().CreateProject(&models.Project{Name: "project"})
+	assert.NoError(t, err)
+
+	conn, err := conf.Repo.SSOConnection().CreateSSOConnection(&models.SSOConnection{
+		ProjectID: project.ID,
+		UniqueID:  "okta",
+		Kind:      types.SSOConnectionKind_OIDC,
+		Domains: []models.SSODomain{
+			{Domain: "example.com", VerificationToken: "porter-verification=example"},
+			{Domain: "gmail.com", VerificationToken: "porter-verification=gmail"},
+		},
+	})
+	assert.NoError(t, err)
+
+	// only the domain whose verification record contains its token is verified
+	conf.SSODomainResolver = fakeResolver{
+		"_porter-verification.example.com": {"v=spf1 -all", "porter-verification=example"},
+		"_porter-verification.gmail.com":   {"porter-verification=other"},
+	}
+
+	res := verifyDomains(t, conf, project, conn)
+
+	assert.Equal(t, []types.SSODomain{
+		{Domain: "example.com", Verified: true, VerificationRecord: "_porter-verification.example.com", VerificationToken: "porter-verification=example"},
+		{Domain: "gmail.com", Verified: false, VerificationRecord: "_porter-verification.gmail.com", VerificationToken: "porter-verification=gmail"},
+	}, res.Domains)
+
+	verified, err := conf.Repo.SSOConnection().ReadSSOConnectionByDomain("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, conn.ID, verified.ID)
+
+	_, err = conf.Repo.SSOConnection().ReadSSOConnectionByDomain("gmail.com")
+	assert.Error(t, err)
+}
@@ -73,6 +73,11 @@ func (p *UserOAuthGithubCallbackHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		return
 	}
 
+	if err := checkSSORequired(p.Config(), user.Email); err != nil {
+		redirectSSOLoginError(w, r, p, err)
+		return
+	}
+
 	p.Config().AnalyticsClient.Identify(analytics.CreateSegmentIdentifyUser(user))
 
 	// save the user as authenticated in the session
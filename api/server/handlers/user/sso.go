@@ -0,0 +1,554 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/authn"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/analytics"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/sso"
+	"github.com/porter-dev/porter/internal/sso/oidc"
+	"github.com/porter-dev/porter/internal/sso/saml"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// ssoLoginMaxAge is how long a user has to complete a login at the identity provider
+const ssoLoginMaxAge = 10 * time.Minute
+
+// ssoLoginError is a login failure which is shown to the user on the login page
+type ssoLoginError string
+
+func (e ssoLoginError) Error() string {
+	return string(e)
+}
+
+// ssoConnection is an sso connection of a project, or the sso login configured for the whole instance
+type ssoConnection struct {
+	// key identifies the connection in urls and on users: the unique id of a project connection, or
+	// "oidc" or "saml" for the instance
+	key  string
+	kind types.SSOConnectionKind
+
+	// projectID is the project of the connection, and is 0 for the instance
+	projectID uint
+
+	// domains are the verified domains of a project connection, whose users it authenticates
+	domains []string
+
+	autoProvision bool
+	defaultRole   types.RoleKind
+	mappings      []sso.GroupRoleMapping
+
+	oidcConf oidc.Config
+
+	// httpClient is used for requests to the identity provider of a project connection
+	httpClient *http.Client
+
+	samlMetadataURL     string
+	samlSSOURL          string
+	samlEntityID        string
+	samlCertificate     string
+	samlGroupsAttribute string
+}
+
+// getSSOConnection returns the connection identified by the sso_connection url parameter
+func getSSOConnection(ctx context.Context, config *config.Config, key string) (*ssoConnection, error) {
+	ctx, span := telemetry.NewSpan(ctx, "get-sso-connection")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "sso-connection", Value: key})
+
+	sc := config.ServerConf
+
+	switch types.SSOConnectionKind(key) {
+	case types.SSOConnectionKind_OIDC:
+		if !config.Metadata.OIDCLogin {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		mappings, err := sso.ParseGroupRoleMappings(sc.SSOGroupRoleMappings)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error parsing sso group role mappings")
+		}
+
+		return &ssoConnection{
+			key:           key,
+			kind:          types.SSOConnectionKind_OIDC,
+			autoProvision: sc.SSOAutoProvision,
+			mappings:      mappings,
+			oidcConf: oidc.Config{
+				IssuerURL:    sc.OIDCIssuerURL,
+				ClientID:     sc.OIDCClientID,
+				ClientSecret: sc.OIDCClientSecret,
+				RedirectURL:  sso.OIDCRedirectURL(sc.ServerURL, key),
+				Scopes:       strings.Fields(sc.OIDCScopes),
+				GroupsClaim:  sc.OIDCGroupsClaim,
+			},
+		}, nil
+	case types.SSOConnectionKind_SAML:
+		if !config.Metadata.SAMLLogin {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		mappings, err := sso.ParseGroupRoleMappings(sc.SSOGroupRoleMappings)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error parsing sso group role mappings")
+		}
+
+		return &ssoConnection{
+			key:                 key,
+			kind:                types.SSOConnectionKind_SAML,
+			autoProvision:       sc.SSOAutoProvision,
+			mappings:            mappings,
+			httpClient:          http.DefaultClient,
+			samlMetadataURL:     sc.SAMLIdPMetadataURL,
+			samlSSOURL:          sc.SAMLIdPSSOURL,
+			samlEntityID:        sc.SAMLIdPEntityID,
+			samlCertificate:     sc.SAMLIdPCertificate,
+			samlGroupsAttribute: sc.SAMLGroupsAttribute,
+		}, nil
+	}
+
+	conn, err := config.Repo.SSOConnection().ReadSSOConnectionByUniqueID(key)
+	if err != nil {
+		return nil, err
+	}
+
+	groupMappings, err := conn.GroupRoleMappingList()
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error decoding group role mappings")
+	}
+
+	mappings := make([]sso.GroupRoleMapping, 0, len(groupMappings))
+
+	// the mappings of a project connection only grant roles in its own project
+	for _, mapping := range groupMappings {
+		mappings = append(mappings, sso.GroupRoleMapping{
+			Group:     mapping.Group,
+			ProjectID: conn.ProjectID,
+			Role:      mapping.Role,
+		})
+	}
+
+	// project admins configure the identity provider, so it is reached through a client which cannot request
+	// internal services
+	httpClient := sso.NewIdPHTTPClient(sc.SSOAllowPrivateIdPs)
+
+	return &ssoConnection{
+		key:           key,
+		kind:          conn.Kind,
+		projectID:     conn.ProjectID,
+		domains:       conn.VerifiedDomainList(),
+		autoProvision: conn.AutoProvision,
+		defaultRole:   conn.DefaultRole,
+		mappings:      mappings,
+		oidcConf: oidc.Config{
+			IssuerURL:    conn.IssuerURL,
+			ClientID:     conn.ClientID,
+			ClientSecret: string(conn.ClientSecret),
+			RedirectURL:  sso.OIDCRedirectURL(sc.ServerURL, key),
+			Scopes:       conn.ScopeList(),
+			GroupsClaim:  conn.GroupsClaim,
+			HTTPClient:   httpClient,
+		},
+		httpClient:          httpClient,
+		samlMetadataURL:     conn.IdPMetadataURL,
+		samlSSOURL:          conn.IdPSSOURL,
+		samlEntityID:        conn.IdPEntityID,
+		samlCertificate:     conn.IdPCertificate,
+		samlGroupsAttribute: conn.GroupsAttribute,
+	}, nil
+}
+
+// oidcProvider returns the OIDC provider of the connection
+func (c *ssoConnection) oidcProvider(ctx context.Context) (*oidc.Provider, error) {
+	if c.kind != types.SSOConnectionKind_OIDC {
+		return nil, fmt.Errorf("sso connection %s is not an oidc connection", c.key)
+	}
+
+	return oidc.NewProvider(ctx, c.oidcConf)
+}
+
+// serviceProvider returns the SAML service provider of the connection, reading the identity provider
+// from its metadata if a metadata url is configured
+func (c *ssoConnection) serviceProvider(ctx context.Context, serverURL string) (*saml.ServiceProvider, error) {
+	if c.kind != types.SSOConnectionKind_SAML {
+		return nil, fmt.Errorf("sso connection %s is not a saml connection", c.key)
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:        sso.SAMLMetadataURL(serverURL, c.key),
+		ACSURL:          sso.SAMLACSURL(serverURL, c.key),
+		IdPSSOURL:       c.samlSSOURL,
+		IdPEntityID:     c.samlEntityID,
+		GroupsAttribute: c.samlGroupsAttribute,
+	}
+
+	if c.samlMetadataURL != "" {
+		metadata, err := saml.FetchIdPMetadata(ctx, c.httpClient, c.samlMetadataURL)
+		if err != nil {
+			return nil, err
+		}
+
+		sp.IdPSSOURL = metadata.SSOURL
+		sp.IdPEntityID = metadata.EntityID
+		sp.IdPCertificates = metadata.Certificates
+
+		return sp, nil
+	}
+
+	certs, err := saml.ParseCertificates(c.samlCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	sp.IdPCertificates = certs
+
+	return sp, nil
+}
+
+// ssoLoginState is the state of a login in progress, which is kept in a signed cookie rather than the
+// session, since identity providers post SAML responses cross-site and the session cookie is not sent
+type ssoLoginState struct {
+	Connection string
+	State      string
+
+	// Nonce is the nonce of an OIDC login, or the id of a SAML authentication request
+	Nonce string
+
+	RedirectURI string
+	ExpiresAt   int64
+}
+
+func ssoLoginCookieName(config *config.Config) string {
+	return config.ServerConf.CookieName + "_sso"
+}
+
+func ssoLoginCodecs(config *config.Config) []securecookie.Codec {
+	keyPairs := make([][]byte, 0, len(config.ServerConf.CookieSecrets))
+
+	for _, key := range config.ServerConf.CookieSecrets {
+		keyPairs = append(keyPairs, []byte(key))
+	}
+
+	return securecookie.CodecsFromPairs(keyPairs...)
+}
+
+// saveSSOLoginState stores the state of a login in progress in a signed cookie
+func saveSSOLoginState(w http.ResponseWriter, config *config.Config, state *ssoLoginState) error {
+	state.ExpiresAt = time.Now().Add(ssoLoginMaxAge).Unix()
+
+	encoded, err := securecookie.EncodeMulti(ssoLoginCookieName(config), state, ssoLoginCodecs(config)...)
+	if err != nil {
+		return err
+	}
+
+	cookie := &http.Cookie{
+		Name:     ssoLoginCookieName(config),
+		Value:    encoded,
+		Path:     "/api/sso/",
+		MaxAge:   int(ssoLoginMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   !config.ServerConf.CookieInsecure,
+		SameSite: http.SameSiteNoneMode,
+	}
+
+	// browsers reject cookies with SameSite=None which are not secure
+	if config.ServerConf.CookieInsecure {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+
+	http.SetCookie(w, cookie)
+
+	return nil
+}
+
+// readSSOLoginState reads the state of the login in progress through the connection, and clears it so that
+// it cannot be used again
+func readSSOLoginState(w http.ResponseWriter, r *http.Request, config *config.Config, connection string) (*ssoLoginState, error) {
+	cookie, err := r.Cookie(ssoLoginCookieName(config))
+	if err != nil {
+		return nil, ssoLoginError("login session expired, please try again")
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoLoginCookieName(config),
+		Path:     "/api/sso/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   !config.ServerConf.CookieInsecure,
+	})
+
+	state := &ssoLoginState{}
+
+	if err := securecookie.DecodeMulti(ssoLoginCookieName(config), cookie.Value, state, ssoLoginCodecs(config)...); err != nil {
+		return nil, fmt.Errorf("invalid sso login cookie: %w", err)
+	}
+
+	if state.Connection != connection {
+		return nil, errors.New("sso login was started through a different connection")
+	}
+
+	if time.Now().Unix() > state.ExpiresAt {
+		return nil, ssoLoginError("login session expired, please try again")
+	}
+
+	return state, nil
+}
+
+// safeRedirectURI only allows redirects to paths on the dashboard after a login
+func safeRedirectURI(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.Contains(redirect, `\`) {
+		return ""
+	}
+
+	return redirect
+}
+
+// upsertSSOUser returns the user with the identity asserted through the connection, creating the user
+// if necessary, and grants the project roles mapped to the groups of the identity
+func upsertSSOUser(ctx context.Context, config *config.Config, conn *ssoConnection, identity *sso.Identity) (*models.User, error) {
+	ctx, span := telemetry.NewSpan(ctx, "upsert-sso-user")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "sso-connection", Value: conn.key},
+		telemetry.AttributeKV{Key: "project-id", Value: conn.projectID},
+	)
+
+	if err := checkUserRestrictions(config.ServerConf, identity.Email); err != nil {
+		return nil, ssoLoginError(err.Error())
+	}
+
+	// project connections only authenticate users in the domains which they claim
+	if conn.projectID != 0 && !containsDomain(conn.domains, sso.EmailDomain(identity.Email)) {
+		return nil, ssoLoginError("email is not in a domain of the sso connection")
+	}
+
+	user, err := config.Repo.User().ReadUserBySSOSubject(conn.key, identity.Subject)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, telemetry.Error(ctx, span, err, "error reading user by sso subject")
+	}
+
+	if user == nil {
+		user, err = provisionSSOUser(ctx, config, conn, identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applySSORoles(ctx, config, conn, user, identity.Groups); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// provisionSSOUser links a user who logs in through the connection for the first time to an existing
+// user, or creates the user
+func provisionSSOUser(ctx context.Context, config *config.Config, conn *ssoConnection, identity *sso.Identity) (*models.User, error) {
+	ctx, span := telemetry.NewSpan(ctx, "provision-sso-user")
+	defer span.End()
+
+	existing, err := config.Repo.User().ReadUserByEmail(identity.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, telemetry.Error(ctx, span, err, "error reading user by email")
+	}
+
+	if existing != nil {
+		// existing accounts are linked by their verified email. Project connections only get here for
+		// emails in the domains which they have verified, whose users can no longer log in otherwise.
+		if !identity.EmailVerified || existing.SSOSubject != "" {
+			return nil, ssoLoginError("email already registered")
+		}
+
+		existing.SSOConnection = conn.key
+		existing.SSOSubject = identity.Subject
+
+		user, err := config.Repo.User().UpdateUser(existing)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error linking user to sso connection")
+		}
+
+		return user, nil
+	}
+
+	if !conn.autoProvision {
+		return nil, ssoLoginError("no Porter account exists for this user, please ask an admin for an invite")
+	}
+
+	user, err := config.Repo.User().CreateUser(&models.User{
+		Email:         identity.Email,
+		EmailVerified: !config.Metadata.Email || identity.EmailVerified,
+		FirstName:     identity.FirstName,
+		LastName:      identity.LastName,
+		SSOConnection: conn.key,
+		SSOSubject:    identity.Subject,
+	})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating user")
+	}
+
+	if err := addUserToDefaultProject(config, user); err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error adding user to default project")
+	}
+
+	return user, nil
+}
+
+// applySSORoles grants the user the roles mapped to their groups, so that the identity provider stays the
+// source of truth for the roles of its users in mapped projects. Roles in projects which no group maps to
+// are left unchanged.
+func applySSORoles(ctx context.Context, config *config.Config, conn *ssoConnection, user *models.User, groups []string) error {
+	ctx, span := telemetry.NewSpan(ctx, "apply-sso-roles")
+	defer span.End()
+
+	roles := sso.ProjectRoles(conn.mappings, groups)
+
+	if conn.projectID != 0 && conn.defaultRole != "" {
+		if _, ok := roles[conn.projectID]; !ok {
+			roles[conn.projectID] = conn.defaultRole
+		}
+	}
+
+	for projectID, kind := range roles {
+		project, err := config.Repo.Project().ReadProject(projectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+
+			return telemetry.Error(ctx, span, err, "error reading project")
+		}
+
+		role, err := config.Repo.Project().ReadProjectRole(projectID, user.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return telemetry.Error(ctx, span, err, "error reading project role")
+		}
+
+		if role == nil {
+			_, err = config.Repo.Project().CreateProjectRole(project, &models.Role{
+				Role: types.Role{
+					UserID:    user.ID,
+					ProjectID: projectID,
+					Kind:      kind,
+				},
+			})
+			if err != nil {
+				return telemetry.Error(ctx, span, err, "error creating project role")
+			}
+
+			continue
+		}
+
+		if role.Kind == kind {
+			continue
+		}
+
+		role.Kind = kind
+		role.PolicyUID = ""
+
+		if _, err := config.Repo.Project().UpdateProjectRole(projectID, role); err != nil {
+			return telemetry.Error(ctx, span, err, "error updating project role")
+		}
+	}
+
+	return nil
+}
+
+// completeSSOLogin logs in the user with the identity asserted through the connection and redirects them
+// to the dashboard. Login failures which the user can act on are shown on the login page.
+func completeSSOLogin(
+	w http.ResponseWriter,
+	r *http.Request,
+	p handlers.PorterHandlerReadWriter,
+	conn *ssoConnection,
+	loginState *ssoLoginState,
+	identity *sso.Identity,
+) {
+	ctx, span := telemetry.NewSpan(r.Context(), "complete-sso-login")
+	defer span.End()
+
+	user, err := upsertSSOUser(ctx, p.Config(), conn, identity)
+	if err != nil {
+		redirectSSOLoginError(w, r, p, err)
+		return
+	}
+
+	p.Config().AnalyticsClient.Identify(analytics.CreateSegmentIdentifyUser(user))
+
+	// save the user as authenticated in the session
+	redirect, err := authn.SaveUserAuthenticated(w, r, p.Config(), user)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving user session")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// non-fatal send email verification
+	if !user.EmailVerified {
+		if err := startEmailVerification(p.Config(), w, r, user); err != nil {
+			p.HandleAPIErrorNoWrite(w, r, apierrors.NewErrInternal(err))
+		}
+	}
+
+	if loginState.RedirectURI != "" {
+		redirect = loginState.RedirectURI
+	}
+
+	if redirect == "" {
+		redirect = "/dashboard"
+	}
+
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+// redirectSSOLoginError shows login failures which the user can act on on the login page, and fails
+// otherwise
+func redirectSSOLoginError(w http.ResponseWriter, r *http.Request, p handlers.PorterHandlerReadWriter, err error) {
+	var loginErr ssoLoginError
+	if errors.As(err, &loginErr) {
+		http.Redirect(w, r, "/login?error="+url.QueryEscape(loginErr.Error()), http.StatusFound)
+		return
+	}
+
+	p.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+}
+
+// checkSSORequired returns an error if the email is in a domain verified by an sso connection, whose users
+// must log in through its identity provider rather than with a password, GitHub or Google
+func checkSSORequired(config *config.Config, email string) error {
+	_, err := config.Repo.SSOConnection().ReadSSOConnectionByDomain(sso.EmailDomain(email))
+	if err == nil {
+		return ssoLoginError("users of this domain must log in through single sign-on")
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func containsDomain(domains []string, domain string) bool {
+	for _, d := range domains {
+		if d == domain {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/sso"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UserSSOLookupHandler finds the sso connection which a user logs in through, based on the domain of their email
+type UserSSOLookupHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUserSSOLookupHandler returns a UserSSOLookupHandler
+func NewUserSSOLookupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UserSSOLookupHandler {
+	return &UserSSOLookupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP returns the login url of the sso connection for the email, if there is one
+func (p *UserSSOLookupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-sso-lookup")
+	defer span.End()
+
+	request := &types.SSOLookupRequest{}
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	conn, err := p.Repo().SSOConnection().ReadSSOConnectionByDomain(sso.EmailDomain(request.Email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.WriteResult(w, r, &types.SSOLookupResponse{})
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading sso connection by domain")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, &types.SSOLookupResponse{
+		Required: true,
+		LoginURL: sso.LoginURL(p.Config().ServerConf.ServerURL, conn.UniqueID),
+	})
+}
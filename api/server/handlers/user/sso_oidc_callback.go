@@ -0,0 +1,94 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UserSSOOIDCCallbackHandler completes a login through an OIDC sso connection
+type UserSSOOIDCCallbackHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUserSSOOIDCCallbackHandler returns a UserSSOOIDCCallbackHandler
+func NewUserSSOOIDCCallbackHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UserSSOOIDCCallbackHandler {
+	return &UserSSOOIDCCallbackHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP exchanges the authorization code from the identity provider and logs in the user
+func (p *UserSSOOIDCCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-sso-oidc-callback")
+	defer span.End()
+
+	connectionKey, reqErr := requestutils.GetURLParamString(r, types.URLParamSSOConnection)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	loginState, err := readSSOLoginState(w, r, p.Config(), connectionKey)
+	if err != nil {
+		redirectSSOLoginError(w, r, p, err)
+		return
+	}
+
+	if r.URL.Query().Get("state") != loginState.State {
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(errors.New("state does not match the login request")))
+		return
+	}
+
+	// identity providers redirect with an error if the user is not authenticated or not assigned to the client
+	if idpErr := r.URL.Query().Get("error"); idpErr != "" {
+		description := r.URL.Query().Get("error_description")
+		if description == "" {
+			description = idpErr
+		}
+
+		redirectSSOLoginError(w, r, p, ssoLoginError(description))
+		return
+	}
+
+	conn, err := getSSOConnection(ctx, p.Config(), connectionKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading sso connection")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	provider, err := conn.oidcProvider(ctx)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading oidc provider")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	identity, err := provider.Exchange(ctx, r.URL.Query().Get("code"), loginState.Nonce)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error verifying oidc login")
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	completeSSOLogin(w, r, p, conn, loginState, identity)
+}
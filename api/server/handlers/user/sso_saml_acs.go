@@ -0,0 +1,90 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UserSSOSAMLACSHandler is the assertion consumer service of SAML sso connections, which completes a login
+// with the response posted by the identity provider
+type UserSSOSAMLACSHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUserSSOSAMLACSHandler returns a UserSSOSAMLACSHandler
+func NewUserSSOSAMLACSHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UserSSOSAMLACSHandler {
+	return &UserSSOSAMLACSHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP verifies the SAML response and logs in the user. Logins started at the identity provider are not
+// supported, since their responses cannot be tied to the browser which receives them.
+func (p *UserSSOSAMLACSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-sso-saml-acs")
+	defer span.End()
+
+	connectionKey, reqErr := requestutils.GetURLParamString(r, types.URLParamSSOConnection)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	loginState, err := readSSOLoginState(w, r, p.Config(), connectionKey)
+	if err != nil {
+		redirectSSOLoginError(w, r, p, err)
+		return
+	}
+
+	if r.PostForm.Get("RelayState") != loginState.State {
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(errors.New("relay state does not match the login request")))
+		return
+	}
+
+	conn, err := getSSOConnection(ctx, p.Config(), connectionKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading sso connection")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	sp, err := conn.serviceProvider(ctx, p.Config().ServerConf.ServerURL)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error reading saml identity provider")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	identity, err := sp.ParseResponse(r.PostForm.Get("SAMLResponse"), loginState.Nonce)
+	if err != nil {
+		err = telemetry.Error(ctx, span, err, "error verifying saml response")
+		p.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+		return
+	}
+
+	completeSSOLogin(w, r, p, conn, loginState, identity)
+}
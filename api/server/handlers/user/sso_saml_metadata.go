@@ -0,0 +1,74 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/sso"
+	"github.com/porter-dev/porter/internal/sso/saml"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UserSSOSAMLMetadataHandler serves the service provider metadata of a SAML sso connection, which is
+// registered with the identity provider
+type UserSSOSAMLMetadataHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUserSSOSAMLMetadataHandler returns a UserSSOSAMLMetadataHandler
+func NewUserSSOSAMLMetadataHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UserSSOSAMLMetadataHandler {
+	return &UserSSOSAMLMetadataHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP writes the metadata document
+func (p *UserSSOSAMLMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-sso-saml-metadata")
+	defer span.End()
+
+	connectionKey, reqErr := requestutils.GetURLParamString(r, types.URLParamSSOConnection)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	conn, err := getSSOConnection(ctx, p.Config(), connectionKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading sso connection")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if conn.kind != types.SSOConnectionKind_SAML {
+		p.HandleAPIError(w, r, apierrors.NewErrNotFound(errors.New("sso connection is not a saml connection")))
+		return
+	}
+
+	// the metadata of Porter does not depend on the identity provider, so it is served even if the
+	// identity provider is unreachable
+	sp := &saml.ServiceProvider{
+		EntityID: sso.SAMLMetadataURL(p.Config().ServerConf.ServerURL, conn.key),
+		ACSURL:   sso.SAMLACSURL(p.Config().ServerConf.ServerURL, conn.key),
+	}
+
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	w.Write(sp.Metadata())
+}
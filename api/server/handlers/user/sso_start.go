@@ -0,0 +1,100 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// UserSSOStartHandler starts a login through an OIDC or SAML sso connection
+type UserSSOStartHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+// NewUserSSOStartHandler returns a UserSSOStartHandler
+func NewUserSSOStartHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UserSSOStartHandler {
+	return &UserSSOStartHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP redirects the user to the identity provider of the connection
+func (p *UserSSOStartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.NewSpan(r.Context(), "serve-sso-start")
+	defer span.End()
+
+	connectionKey, reqErr := requestutils.GetURLParamString(r, types.URLParamSSOConnection)
+	if reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	conn, err := getSSOConnection(ctx, p.Config(), connectionKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.HandleAPIError(w, r, apierrors.NewErrNotFound(err))
+			return
+		}
+
+		err = telemetry.Error(ctx, span, err, "error reading sso connection")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	loginState := &ssoLoginState{
+		Connection:  conn.key,
+		State:       oauth.CreateRandomState(),
+		RedirectURI: safeRedirectURI(r.URL.Query().Get("redirect_uri")),
+	}
+
+	var loginURL string
+
+	switch conn.kind {
+	case types.SSOConnectionKind_OIDC:
+		provider, err := conn.oidcProvider(ctx)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading oidc provider")
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		loginState.Nonce = oauth.CreateRandomState()
+		loginURL = provider.AuthCodeURL(loginState.State, loginState.Nonce)
+	case types.SSOConnectionKind_SAML:
+		sp, err := conn.serviceProvider(ctx, p.Config().ServerConf.ServerURL)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error reading saml identity provider")
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		loginURL, loginState.Nonce, err = sp.AuthnRequestURL(loginState.State)
+		if err != nil {
+			err = telemetry.Error(ctx, span, err, "error creating saml authentication request")
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	if err := saveSSOLoginState(w, p.Config(), loginState); err != nil {
+		err = telemetry.Error(ctx, span, err, "error saving sso login state")
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	http.Redirect(w, r, loginURL, http.StatusFound)
+}
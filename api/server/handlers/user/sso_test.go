@@ -0,0 +1,352 @@
+package user_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/porter-dev/porter/api/server/handlers/user"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// newMockOIDCIdP starts a local OpenID Connect identity provider which issues an id token with the claims
+// for any authorization code
+func newMockOIDCIdP(t *testing.T, claims jwt.MapClaims) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	var server *httptest.Server
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kid": "key-1",
+					"kty": "RSA",
+					"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		claims["iss"] = server.URL
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+
+		signed, err := token.SignedString(key)
+		assert.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"id_token":     signed,
+		})
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// loginThroughSSO starts a login through the connection and completes it with the authorization code "code",
+// returning the response of the callback
+func loginThroughSSO(t *testing.T, config *config.Config, connection string, claims jwt.MapClaims) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/sso/"+connection+"/start", nil)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamSSOConnection): connection,
+	})
+
+	user.NewUserSSOStartHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+
+	authURL, err := url.Parse(rr.Header().Get("Location"))
+	assert.NoError(t, err)
+
+	claims["nonce"] = authURL.Query().Get("nonce")
+
+	callbackURL := "/api/sso/" + connection + "/oidc/callback?code=code&state=" + url.QueryEscape(authURL.Query().Get("state"))
+
+	req, callbackRR := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), callbackURL, nil)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamSSOConnection): connection,
+	})
+
+	for _, cookie := range rr.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	user.NewUserSSOOIDCCallbackHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	).ServeHTTP(callbackRR, req)
+
+	return callbackRR
+}
+
+// verifiedDomain returns a domain of an sso connection which has been verified
+func verifiedDomain(domain string) models.SSODomain {
+	verifiedAt := time.Now()
+	return models.SSODomain{Domain: domain, VerifiedAt: &verifiedAt}
+}
+
+func newSSOTestConfig(t *testing.T, claims jwt.MapClaims) (*config.Config, *models.SSOConnection) {
+	conf := apitest.LoadConfig(t)
+	conf.ServerConf.SSOAllowPrivateIdPs = true
+	conf.Metadata = config.MetadataFromConf(conf.ServerConf, "test")
+
+	idp := newMockOIDCIdP(t, claims)
+
+	project, err := conf.Repo.Project().CreateProject(&models.Project{Name: "project"})
+	assert.NoError(t, err)
+
+	conn, err := conf.Repo.SSOConnection().CreateSSOConnection(&models.SSOConnection{
+		ProjectID:         project.ID,
+		UniqueID:          "okta",
+		Name:              "Okta",
+		Kind:              types.SSOConnectionKind_OIDC,
+		Domains:           []models.SSODomain{verifiedDomain("example.com")},
+		AutoProvision:     true,
+		DefaultRole:       types.RoleViewer,
+		GroupRoleMappings: []byte(`[{"group":"porter-admins","role":"admin"}]`),
+		IssuerURL:         idp.URL,
+		ClientID:          "porter",
+		ClientSecret:      []byte("secret"),
+	})
+	assert.NoError(t, err)
+
+	return conf, conn
+}
+
+func ssoTestClaims(email string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"aud":            "porter",
+		"sub":            "okta-user-1",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"email":          email,
+		"email_verified": true,
+		"given_name":     "Jane",
+		"groups":         []string{"porter-admins"},
+	}
+}
+
+func TestSSOLoginProvisionsUser(t *testing.T) {
+	claims := ssoTestClaims("jane@example.com")
+	conf, conn := newSSOTestConfig(t, claims)
+
+	rr := loginThroughSSO(t, conf, conn.UniqueID, claims)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/dashboard", rr.Header().Get("Location"))
+
+	provisioned, err := conf.Repo.User().ReadUserBySSOSubject("okta", "okta-user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", provisioned.Email)
+	assert.Equal(t, "Jane", provisioned.FirstName)
+
+	role, err := conf.Repo.Project().ReadProjectRole(conn.ProjectID, provisioned.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, types.RoleAdmin, role.Kind)
+
+	// logging in again returns the same user
+	rr = loginThroughSSO(t, conf, conn.UniqueID, claims)
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/dashboard", rr.Header().Get("Location"))
+}
+
+func TestSSOLoginRejectsOtherDomains(t *testing.T) {
+	claims := ssoTestClaims("jane@other.com")
+	conf, conn := newSSOTestConfig(t, claims)
+
+	rr := loginThroughSSO(t, conf, conn.UniqueID, claims)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Contains(t, rr.Header().Get("Location"), "/login?error=")
+
+	_, err := conf.Repo.User().ReadUserByEmail("jane@other.com")
+	assert.Error(t, err)
+}
+
+func TestSSOLoginLinksExistingUsers(t *testing.T) {
+	claims := ssoTestClaims("mrp@example.com")
+	conf, conn := newSSOTestConfig(t, claims)
+
+	existing, err := conf.Repo.User().CreateUser(&models.User{Email: "mrp@example.com", Password: "hello"})
+	assert.NoError(t, err)
+
+	rr := loginThroughSSO(t, conf, conn.UniqueID, claims)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/dashboard", rr.Header().Get("Location"))
+
+	linked, err := conf.Repo.User().ReadUserBySSOSubject("okta", "okta-user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, existing.ID, linked.ID)
+
+	role, err := conf.Repo.Project().ReadProjectRole(conn.ProjectID, existing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, types.RoleAdmin, role.Kind)
+}
+
+func TestSSOLoginDoesNotLinkUnverifiedEmails(t *testing.T) {
+	claims := ssoTestClaims("mrp@example.com")
+	claims["email_verified"] = false
+	conf, conn := newSSOTestConfig(t, claims)
+
+	_, err := conf.Repo.User().CreateUser(&models.User{Email: "mrp@example.com"})
+	assert.NoError(t, err)
+
+	rr := loginThroughSSO(t, conf, conn.UniqueID, claims)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/login?error="+url.QueryEscape("email already registered"), rr.Header().Get("Location"))
+}
+
+func TestSSOLoginDoesNotTakeOverLinkedUsers(t *testing.T) {
+	claims := ssoTestClaims("mrp@example.com")
+	conf, conn := newSSOTestConfig(t, claims)
+
+	_, err := conf.Repo.User().CreateUser(&models.User{
+		Email:         "mrp@example.com",
+		SSOConnection: "oidc",
+		SSOSubject:    "other-user",
+	})
+	assert.NoError(t, err)
+
+	rr := loginThroughSSO(t, conf, conn.UniqueID, claims)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/login?error="+url.QueryEscape("email already registered"), rr.Header().Get("Location"))
+}
+
+func TestLoginRequiresSSOForClaimedDomains(t *testing.T) {
+	conf, _ := newSSOTestConfig(t, ssoTestClaims("jane@example.com"))
+	apitest.CreateTestUser(t, conf, true)
+
+	_, err := conf.Repo.SSOConnection().CreateSSOConnection(&models.SSOConnection{
+		UniqueID: "porter",
+		Kind:     types.SSOConnectionKind_OIDC,
+		Domains:  []models.SSODomain{verifiedDomain("porter.run")},
+	})
+	assert.NoError(t, err)
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/login",
+		&types.LoginUserRequest{
+			Email:    "mrp@porter.run",
+			Password: "hello",
+		},
+	)
+
+	user.NewUserLoginHandler(
+		conf,
+		shared.NewDefaultRequestDecoderValidator(conf.Logger, conf.Alerter),
+		shared.NewDefaultResultWriter(conf.Logger, conf.Alerter),
+	).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+		Error: "users of this domain must log in through single sign-on",
+	})
+}
+
+func TestSSOLoginRejectsUnverifiedDomains(t *testing.T) {
+	claims := ssoTestClaims("jane@unverified.com")
+	conf, conn := newSSOTestConfig(t, claims)
+
+	conn.Domains = append(conn.Domains, models.SSODomain{Domain: "unverified.com", VerificationToken: "token"})
+
+	rr := loginThroughSSO(t, conf, conn.UniqueID, claims)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Contains(t, rr.Header().Get("Location"), "/login?error=")
+
+	_, err := conf.Repo.User().ReadUserByEmail("jane@unverified.com")
+	assert.Error(t, err)
+}
+
+func TestSSOLoginRejectsPrivateIdPs(t *testing.T) {
+	claims := ssoTestClaims("jane@example.com")
+	conf, conn := newSSOTestConfig(t, claims)
+	conf.ServerConf.SSOAllowPrivateIdPs = false
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/sso/"+conn.UniqueID+"/start", nil)
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamSSOConnection): conn.UniqueID,
+	})
+
+	user.NewUserSSOStartHandler(
+		conf,
+		shared.NewDefaultRequestDecoderValidator(conf.Logger, conf.Alerter),
+		shared.NewDefaultResultWriter(conf.Logger, conf.Alerter),
+	).ServeHTTP(rr, req)
+
+	assert.NotEqual(t, http.StatusFound, rr.Code)
+}
+
+func TestLoginDoesNotRequireSSOForUnverifiedDomains(t *testing.T) {
+	conf, _ := newSSOTestConfig(t, ssoTestClaims("jane@example.com"))
+	apitest.CreateTestUser(t, conf, true)
+
+	// claiming a domain without verifying it does not lock its users out of other logins
+	_, err := conf.Repo.SSOConnection().CreateSSOConnection(&models.SSOConnection{
+		UniqueID: "porter",
+		Kind:     types.SSOConnectionKind_OIDC,
+		Domains:  []models.SSODomain{{Domain: "porter.run", VerificationToken: "token"}},
+	})
+	assert.NoError(t, err)
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/login",
+		&types.LoginUserRequest{
+			Email:    "mrp@porter.run",
+			Password: "hello",
+		},
+	)
+
+	user.NewUserLoginHandler(
+		conf,
+		shared.NewDefaultRequestDecoderValidator(conf.Logger, conf.Alerter),
+		shared.NewDefaultResultWriter(conf.Logger, conf.Alerter),
+	).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
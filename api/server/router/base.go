@@ -490,6 +490,131 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// GET /api/sso/lookup
+	ssoLookupEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/sso/lookup",
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	ssoLookupHandler := user.NewUserSSOLookupHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: ssoLookupEndpoint,
+		Handler:  ssoLookupHandler,
+		Router:   r,
+	})
+
+	// GET /api/sso/{sso_connection}/start
+	ssoStartEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/sso/{%s}/start", types.URLParamSSOConnection),
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	ssoStartHandler := user.NewUserSSOStartHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: ssoStartEndpoint,
+		Handler:  ssoStartHandler,
+		Router:   r,
+	})
+
+	// GET /api/sso/{sso_connection}/oidc/callback
+	ssoOIDCCallbackEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/sso/{%s}/oidc/callback", types.URLParamSSOConnection),
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	ssoOIDCCallbackHandler := user.NewUserSSOOIDCCallbackHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: ssoOIDCCallbackEndpoint,
+		Handler:  ssoOIDCCallbackHandler,
+		Router:   r,
+	})
+
+	// POST /api/sso/{sso_connection}/saml/acs
+	ssoSAMLACSEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/sso/{%s}/saml/acs", types.URLParamSSOConnection),
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	ssoSAMLACSHandler := user.NewUserSSOSAMLACSHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: ssoSAMLACSEndpoint,
+		Handler:  ssoSAMLACSHandler,
+		Router:   r,
+	})
+
+	// GET /api/sso/{sso_connection}/saml/metadata
+	ssoSAMLMetadataEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("/sso/{%s}/saml/metadata", types.URLParamSSOConnection),
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	ssoSAMLMetadataHandler := user.NewUserSSOSAMLMetadataHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: ssoSAMLMetadataEndpoint,
+		Handler:  ssoSAMLMetadataHandler,
+		Router:   r,
+	})
+
 	// GET /api/internal/credentials
 	getCredentialsEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
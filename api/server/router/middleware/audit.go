@@ -0,0 +1,372 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared/audit"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// RequestIDHeader is the header which holds the id of a request. If a request does not set it, the
+// audit middleware generates an id and returns it in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// redactedValue replaces the sensitive values of an audited resource
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeys are the substrings of the JSON keys whose values are redacted from audit logs. Nested objects
+// under these keys keep their structure, with each value redacted.
+var sensitiveKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"apikey",
+	"api_key",
+	"access_key",
+	"private",
+	"credential",
+	"cert",
+	"kubeconfig",
+	"authorization",
+	"signing",
+	"routing_key",
+	"encryption_key",
+}
+
+// opaqueKeys are the substrings of the JSON keys whose values are replaced wholesale in audit logs, since they hold
+// env variables, secrets or encoded payloads such as the app proto or porter.yaml, which may embed secrets anywhere
+var opaqueKeys = []string{
+	"variables",
+	"secrets",
+}
+
+// opaqueKeyPrefix is the prefix of the JSON keys of base64-encoded payloads, which are replaced wholesale in audit logs
+const opaqueKeyPrefix = "b64_"
+
+// IsAuditedRoute returns true if requests to the route are written to the audit log, which is the case for
+// project-scoped routes which mutate a resource
+func IsAuditedRoute(metadata *types.APIRequestMetadata) bool {
+	if metadata.IsWebsocket {
+		return false
+	}
+
+	switch metadata.Verb {
+	case types.APIVerbCreate, types.APIVerbUpdate, types.APIVerbDelete:
+	default:
+		return false
+	}
+
+	for _, scope := range metadata.Scopes {
+		if scope == types.ProjectScope {
+			return true
+		}
+	}
+
+	return false
+}
+
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *auditResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("ResponseWriter Interface does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// AuditMiddleware writes an audit log for every request to a route. It must be added after authentication, so that
+// the actor can be read from the request context, and before authorization, so that denied requests are logged too.
+// The project and cluster are read from the URL parameters of the route.
+type AuditMiddleware struct {
+	config *config.Config
+	verb   types.APIVerb
+}
+
+// NewAuditMiddleware returns an AuditMiddleware for the route described by the metadata
+func NewAuditMiddleware(config *config.Config, metadata *types.APIRequestMetadata) *AuditMiddleware {
+	return &AuditMiddleware{
+		config: config,
+		verb:   metadata.Verb,
+	}
+}
+
+func (mw *AuditMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+			r.Header.Set(RequestIDHeader, requestID)
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx, recorder := audit.WithRecorder(r.Context())
+		r = r.WithContext(ctx)
+
+		rw := &auditResponseWriter{w, http.StatusOK}
+
+		next.ServeHTTP(rw, r)
+
+		auditLog := &models.AuditLog{
+			Action:     string(mw.verb),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			RequestID:  requestID,
+			StatusCode: rw.statusCode,
+		}
+
+		if before, after, ok := recorder.Change(); ok && rw.statusCode < http.StatusBadRequest {
+			if changes := ResourceChanges(before, after); len(changes) > 0 {
+				if changesBytes, err := json.Marshal(changes); err == nil {
+					auditLog.Changes = changesBytes
+				}
+			}
+		}
+
+		mw.write(r, auditLog)
+	})
+}
+
+// write fills in the actor of the audit log from the request context, and its project and cluster from the URL
+// parameters, and stores it. Errors are logged rather than returned, since the response has already been written.
+func (mw *AuditMiddleware) write(r *http.Request, auditLog *models.AuditLog) {
+	// the request context may have been cancelled once the response was written
+	ctx, span := telemetry.NewSpan(context.Background(), "middleware-audit")
+	defer span.End()
+
+	projectID, err := strconv.ParseUint(chi.URLParam(r, string(types.URLParamProjectID)), 10, 64)
+	if err != nil || projectID == 0 {
+		return
+	}
+
+	// requests to projects which do not exist are denied without being logged
+	if _, err := mw.config.Repo.Project().ReadProject(uint(projectID)); err != nil {
+		return
+	}
+
+	auditLog.ProjectID = uint(projectID)
+
+	if clusterID, err := strconv.ParseUint(chi.URLParam(r, string(types.URLParamClusterID)), 10, 64); err == nil {
+		auditLog.ClusterID = uint(clusterID)
+	}
+
+	if apiToken, _ := r.Context().Value("api_token").(*models.APIToken); apiToken != nil {
+		auditLog.ActorType = string(types.AuditLogActorType_APIToken)
+		auditLog.ActorID = apiToken.UniqueID
+		auditLog.ActorEmail = apiToken.Name
+	} else if user, _ := r.Context().Value(types.UserScope).(*models.User); user != nil {
+		auditLog.ActorType = string(types.AuditLogActorType_User)
+		auditLog.ActorID = strconv.FormatUint(uint64(user.ID), 10)
+		auditLog.ActorEmail = user.Email
+	}
+
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		auditLog.Resource = rctx.RoutePattern()
+
+		params := make(map[string]string)
+
+		for i, key := range rctx.URLParams.Keys {
+			if key != "*" && i < len(rctx.URLParams.Values) {
+				params[key] = rctx.URLParams.Values[i]
+			}
+		}
+
+		if paramsBytes, err := json.Marshal(params); err == nil {
+			auditLog.ResourceParams = paramsBytes
+		}
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: auditLog.ProjectID},
+		telemetry.AttributeKV{Key: "request-id", Value: auditLog.RequestID},
+		telemetry.AttributeKV{Key: "resource", Value: auditLog.Resource},
+	)
+
+	if _, err := mw.config.Repo.AuditLog().CreateAuditLog(ctx, auditLog); err != nil {
+		mw.config.Logger.Error().Err(err).Str("request_id", auditLog.RequestID).Msg("error writing audit log")
+	}
+}
+
+// ResourceChanges returns the fields which differ between the JSON encodings of the states of a resource before
+// and after a request. Values under sensitive keys are redacted, keeping the structure of nested objects, while the
+// values of opaque keys, such as env variables and base64-encoded payloads, are replaced wholesale.
+func ResourceChanges(before, after interface{}) []types.AuditLogChange {
+	beforeDoc, err := toJSONDocument(before)
+	if err != nil {
+		return nil
+	}
+
+	afterDoc, err := toJSONDocument(after)
+	if err != nil {
+		return nil
+	}
+
+	changes := make([]types.AuditLogChange, 0)
+	diffValues("", beforeDoc, afterDoc, false, &changes)
+
+	return changes
+}
+
+// toJSONDocument returns the generic JSON encoding of a value
+func toJSONDocument(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(valueBytes, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// diffValues appends a change for each field which differs between before and after. Objects are compared field by
+// field, while other values, including lists, are compared as a whole.
+func diffValues(path string, before, after interface{}, sensitive bool, changes *[]types.AuditLogChange) {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+
+	if (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		keys := make([]string, 0, len(beforeMap)+len(afterMap))
+		for key := range beforeMap {
+			keys = append(keys, key)
+		}
+		for key := range afterMap {
+			if _, ok := beforeMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			beforeChild, afterChild := beforeMap[key], afterMap[key]
+
+			if isOpaqueKey(key) {
+				if !reflect.DeepEqual(beforeChild, afterChild) {
+					*changes = append(*changes, types.AuditLogChange{
+						Path:   childPath,
+						Before: opaqueValue(beforeChild),
+						After:  opaqueValue(afterChild),
+					})
+				}
+				continue
+			}
+
+			diffValues(childPath, beforeChild, afterChild, sensitive || isSensitiveKey(key), changes)
+		}
+
+		return
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+
+	*changes = append(*changes, types.AuditLogChange{
+		Path:   path,
+		Before: redactValue(before, sensitive),
+		After:  redactValue(after, sensitive),
+	})
+}
+
+// opaqueValue replaces a set value of an opaque key
+func opaqueValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return redactedValue
+}
+
+func redactValue(value interface{}, sensitive bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if isOpaqueKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+
+			v[key] = redactValue(child, sensitive || isSensitiveKey(key))
+		}
+
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child, sensitive)
+		}
+
+		return v
+	case nil:
+		return nil
+	}
+
+	if sensitive {
+		return redactedValue
+	}
+
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(key, sensitiveKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isOpaqueKey(key string) bool {
+	key = strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+
+	if strings.HasPrefix(key, opaqueKeyPrefix) {
+		return true
+	}
+
+	for _, opaqueKey := range opaqueKeys {
+		if strings.Contains(key, opaqueKey) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -730,6 +730,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -759,6 +760,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -788,6 +790,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -817,6 +820,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -962,6 +966,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -991,6 +996,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -1078,6 +1084,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -1341,6 +1348,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
@@ -1689,6 +1697,7 @@ func getPorterAppRoutes(
 				types.UserScope,
 				types.ProjectScope,
 				types.ClusterScope,
+				types.DeploymentTargetScope,
 			},
 		},
 	)
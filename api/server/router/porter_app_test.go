@@ -0,0 +1,581 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/router"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/token"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// stagingDeployerPolicy only allows deploying to the staging deployment target of cluster 1
+var stagingDeployerPolicy = []*types.PolicyDocument{
+	{
+		Scope: types.ProjectScope,
+		Verbs: types.ReadWriteVerbGroup(),
+		Children: map[types.PermissionScope]*types.PolicyDocument{
+			types.ClusterScope: {
+				Scope: types.ClusterScope,
+				Verbs: types.ReadWriteVerbGroup(),
+				Resources: []types.NameOrUInt{
+					{UInt: 1},
+				},
+				Children: map[types.PermissionScope]*types.PolicyDocument{
+					types.DeploymentTargetScope: {
+						Scope: types.DeploymentTargetScope,
+						Verbs: types.ReadWriteVerbGroup(),
+						Resources: []types.NameOrUInt{
+							{Name: "staging"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+type deployRouteTest struct {
+	description string
+	path        string
+	body        map[string]string
+	expStatus   int
+	expTarget   string
+}
+
+var deployRouteTests = []deployRouteTest{
+	{
+		description: "update to staging by name is allowed",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{"deployment_target_name": "staging"},
+		expStatus:   http.StatusOK,
+		expTarget:   "staging",
+	},
+	{
+		description: "update to production by name is forbidden",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "update to production by id is forbidden",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{"deployment_target_id": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "update without a deployment target uses the default deployment target",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{},
+		expStatus:   http.StatusOK,
+		expTarget:   "staging",
+	},
+	{
+		description: "create in production is forbidden",
+		path:        "/projects/1/clusters/1/apps/create",
+		body:        map[string]string{"deployment_target_id": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "rollback in production is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/rollback",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "rollback in staging is allowed",
+		path:        "/projects/1/clusters/1/apps/test-app/rollback",
+		body:        map[string]string{"deployment_target_id": "staging"},
+		expStatus:   http.StatusOK,
+		expTarget:   "staging",
+	},
+	{
+		description: "image update in production is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/update-image",
+		body:        map[string]string{"deployment_target_id": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "build settings update in production is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/build",
+		body:        map[string]string{"deployment_target_id": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "job run in production is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/run",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "job run in staging is allowed",
+		path:        "/projects/1/clusters/1/apps/test-app/run",
+		body:        map[string]string{"deployment_target_name": "staging"},
+		expStatus:   http.StatusOK,
+		expTarget:   "staging",
+	},
+	{
+		description: "job cancel in production is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/jobs/test-job-run/cancel",
+		body:        map[string]string{"deployment_target_id": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "job cancel in staging is allowed",
+		path:        "/projects/1/clusters/1/apps/test-app/jobs/test-job-run/cancel",
+		body:        map[string]string{"deployment_target_name": "staging"},
+		expStatus:   http.StatusOK,
+		expTarget:   "staging",
+	},
+	{
+		description: "subdomain in production is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/subdomain",
+		body:        map[string]string{"service_name": "web", "deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "subdomain without a deployment target uses the default deployment target",
+		path:        "/projects/1/clusters/1/apps/test-app/subdomain",
+		body:        map[string]string{"service_name": "web"},
+		expStatus:   http.StatusOK,
+		expTarget:   "staging",
+	},
+	{
+		description: "unknown deployment target is forbidden",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{"deployment_target_name": "unknown"},
+		expStatus:   http.StatusForbidden,
+	},
+}
+
+func TestDeployRoutesAreScopedToDeploymentTarget(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user, deploymentTargetIDs := createStagingDeployer(t, config)
+
+	next := &deployHandler{}
+	r := newPorterAppTestRouter(config, next)
+
+	cookie := apitest.AuthenticateUserWithCookie(t, config, user, false)
+
+	for _, test := range deployRouteTests {
+		body := map[string]string{}
+		for key, val := range test.body {
+			// reference deployment targets by id when the test names the id field
+			if id, ok := deploymentTargetIDs[val]; ok && key == "deployment_target_id" {
+				val = id
+			}
+
+			body[key] = val
+		}
+
+		*next = deployHandler{}
+
+		req, rr := apitest.GetRequestAndRecorder(t, http.MethodPost, test.path, body)
+		req.AddCookie(cookie)
+
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, test.expStatus, rr.Result().StatusCode, "[ %s ]: status code not equal", test.description)
+
+		if test.expStatus != http.StatusOK {
+			assert.False(t, next.WasCalled, "[ %s ]: handler should not have been called", test.description)
+			continue
+		}
+
+		assert.True(t, next.WasCalled, "[ %s ]: handler should have been called", test.description)
+		assert.Equal(t, test.expTarget, next.DeploymentTarget.Name, "[ %s ]: deployment target not equal", test.description)
+
+		// the handler must still be able to decode the request body
+		expBody, _ := json.Marshal(body)
+		assert.Equal(t, string(expBody), next.Body, "[ %s ]: request body not equal", test.description)
+	}
+}
+
+type attachEnvGroupTest struct {
+	description  string
+	appInstances []string
+	expStatus    int
+}
+
+var attachEnvGroupTests = []attachEnvGroupTest{
+	{
+		description:  "attaching to apps in staging is allowed",
+		appInstances: []string{"staging"},
+		expStatus:    http.StatusOK,
+	},
+	{
+		description:  "attaching to an app in production is forbidden",
+		appInstances: []string{"staging", "production"},
+		expStatus:    http.StatusForbidden,
+	},
+	{
+		description:  "attaching to an app in another project is forbidden",
+		appInstances: []string{"other-project"},
+		expStatus:    http.StatusForbidden,
+	},
+	{
+		description:  "attaching to an unknown app is forbidden",
+		appInstances: []string{"00000000-0000-0000-0000-000000000000"},
+		expStatus:    http.StatusForbidden,
+	},
+}
+
+func TestAttachEnvGroupIsScopedToAppDeploymentTargets(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user, deploymentTargetIDs := createStagingDeployer(t, config)
+	appInstanceIDs := createAppInstances(t, config, deploymentTargetIDs)
+
+	next := &deployHandler{}
+	r := newPorterAppTestRouter(config, next)
+
+	cookie := apitest.AuthenticateUserWithCookie(t, config, user, false)
+
+	for _, test := range attachEnvGroupTests {
+		ids := make([]string, 0, len(test.appInstances))
+		for _, name := range test.appInstances {
+			if id, ok := appInstanceIDs[name]; ok {
+				name = id
+			}
+
+			ids = append(ids, name)
+		}
+
+		*next = deployHandler{}
+
+		req, rr := apitest.GetRequestAndRecorder(t, http.MethodPost, "/projects/1/clusters/1/apps/attach-env-group", map[string]interface{}{
+			"env_group_name":   "shared",
+			"app_instance_ids": ids,
+		})
+		req.AddCookie(cookie)
+
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, test.expStatus, rr.Result().StatusCode, "[ %s ]: status code not equal", test.description)
+		assert.Equal(t, test.expStatus == http.StatusOK, next.WasCalled, "[ %s ]: handler called not equal", test.description)
+	}
+}
+
+type apiTokenDeployTest struct {
+	description string
+	path        string
+	body        map[string]string
+	expStatus   int
+}
+
+var apiTokenDeployTests = []apiTokenDeployTest{
+	{
+		description: "update to the restricted deployment target is allowed",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{"deployment_target_name": "staging"},
+		expStatus:   http.StatusOK,
+	},
+	{
+		description: "update to another deployment target is forbidden",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "update of the default deployment target is allowed",
+		path:        "/projects/1/clusters/1/apps/update",
+		body:        map[string]string{},
+		expStatus:   http.StatusOK,
+	},
+	{
+		description: "rollback in another deployment target is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/rollback",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "job run in another deployment target is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/run",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "image update in another deployment target is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/update-image",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "build settings update in another deployment target is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/build",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "job cancel in another deployment target is forbidden",
+		path:        "/projects/1/clusters/1/apps/test-app/jobs/test-job-run/cancel",
+		body:        map[string]string{"deployment_target_name": "production"},
+		expStatus:   http.StatusForbidden,
+	},
+	{
+		description: "build settings update in the restricted deployment target is allowed",
+		path:        "/projects/1/clusters/1/apps/test-app/build",
+		body:        map[string]string{"deployment_target_name": "staging"},
+		expStatus:   http.StatusOK,
+	},
+}
+
+func TestDeployRoutesCheckAPITokenDeploymentTargets(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user, deploymentTargetIDs := createStagingDeployer(t, config)
+
+	next := &deployHandler{}
+	r := newPorterAppTestRouter(config, next)
+
+	tokenStr := createAPIToken(t, config, user, deploymentTargetIDs["staging"], "")
+
+	for _, test := range apiTokenDeployTests {
+		*next = deployHandler{}
+
+		req, rr := apitest.GetRequestAndRecorder(t, http.MethodPost, test.path, test.body)
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, test.expStatus, rr.Result().StatusCode, "[ %s ]: status code not equal", test.description)
+		assert.Equal(t, test.expStatus == http.StatusOK, next.WasCalled, "[ %s ]: handler called not equal", test.description)
+	}
+}
+
+type apiTokenAttachEnvGroupTest struct {
+	description  string
+	appNames     string
+	appInstances []string
+	expStatus    int
+}
+
+var apiTokenAttachEnvGroupTests = []apiTokenAttachEnvGroupTest{
+	{
+		description:  "attaching to an app of the token in its deployment target is allowed",
+		appNames:     "test-app",
+		appInstances: []string{"staging"},
+		expStatus:    http.StatusOK,
+	},
+	{
+		description:  "attaching to an app in another deployment target is forbidden",
+		appNames:     "test-app",
+		appInstances: []string{"staging", "production"},
+		expStatus:    http.StatusForbidden,
+	},
+	{
+		description:  "attaching to another app is forbidden",
+		appNames:     "other-app",
+		appInstances: []string{"staging"},
+		expStatus:    http.StatusForbidden,
+	},
+}
+
+func TestAttachEnvGroupChecksAPITokenRestrictions(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user, deploymentTargetIDs := createStagingDeployer(t, config)
+	appInstanceIDs := createAppInstances(t, config, deploymentTargetIDs)
+
+	next := &deployHandler{}
+	r := newPorterAppTestRouter(config, next)
+
+	for _, test := range apiTokenAttachEnvGroupTests {
+		tokenStr := createAPIToken(t, config, user, deploymentTargetIDs["staging"], test.appNames)
+
+		ids := make([]string, 0, len(test.appInstances))
+		for _, name := range test.appInstances {
+			ids = append(ids, appInstanceIDs[name])
+		}
+
+		*next = deployHandler{}
+
+		req, rr := apitest.GetRequestAndRecorder(t, http.MethodPost, "/projects/1/clusters/1/apps/attach-env-group", map[string]interface{}{
+			"env_group_name":   "shared",
+			"app_instance_ids": ids,
+		})
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, test.expStatus, rr.Result().StatusCode, "[ %s ]: status code not equal", test.description)
+		assert.Equal(t, test.expStatus == http.StatusOK, next.WasCalled, "[ %s ]: handler called not equal", test.description)
+	}
+}
+
+// createAPIToken creates an admin api token of project 1 which is restricted to the given deployment targets and
+// apps, and returns the encoded token
+func createAPIToken(t *testing.T, config *config.Config, user *models.User, deploymentTargetIDs string, appNames string) string {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiry := time.Now().Add(time.Hour)
+
+	apiToken, err := config.Repo.APIToken().CreateAPIToken(&models.APIToken{
+		UniqueID:            uuid.NewString(),
+		ProjectID:           1,
+		CreatedByUserID:     user.ID,
+		Expiry:              &expiry,
+		Name:                "restricted-token",
+		SecretKey:           hashed,
+		PolicyUID:           "admin",
+		DeploymentTargetIDs: deploymentTargetIDs,
+		AppNames:            appNames,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := token.GetStoredTokenForAPI(apiToken.CreatedByUserID, apiToken.ProjectID, apiToken.UniqueID, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenStr, err := tok.EncodeToken(config.TokenConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenStr
+}
+
+// createStagingDeployer creates a project with a cluster, a default staging and a production deployment target, and
+// a user whose custom role only allows deploying to staging. It returns the user and the ids of the deployment targets
+// by name.
+func createStagingDeployer(t *testing.T, config *config.Config) (*models.User, map[string]string) {
+	user := apitest.CreateTestUser(t, config, true)
+
+	project, err := config.Repo.Project().CreateProject(&models.Project{Name: "test-project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policyBytes, err := json.Marshal(stagingDeployerPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := config.Repo.Policy().CreatePolicy(&models.Policy{
+		UniqueID:    "staging-deployer",
+		ProjectID:   project.ID,
+		Name:        "staging-deployer",
+		PolicyBytes: policyBytes,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := config.Repo.Project().CreateProjectRole(project, &models.Role{
+		Role: types.Role{
+			UserID:    user.ID,
+			ProjectID: project.ID,
+			Kind:      types.RoleCustom,
+			PolicyUID: "staging-deployer",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, err := config.Repo.Cluster().CreateCluster(&models.Cluster{Name: "test-cluster", ProjectID: project.ID}, config.LaunchDarklyClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]string)
+
+	for _, name := range []string{"staging", "production"} {
+		deploymentTarget, err := config.Repo.DeploymentTarget().CreateDeploymentTarget(&models.DeploymentTarget{
+			ProjectID:    int(project.ID),
+			ClusterID:    int(cluster.ID),
+			VanityName:   name,
+			Selector:     name,
+			SelectorType: models.DeploymentTargetSelectorType_Namespace,
+			IsDefault:    name == "staging",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids[name] = deploymentTarget.ID.String()
+	}
+
+	return user, ids
+}
+
+// createAppInstances creates an app in each deployment target, and one in the staging deployment target of another
+// project. It returns the ids of the app instances by deployment target, or "other-project".
+func createAppInstances(t *testing.T, config *config.Config, deploymentTargetIDs map[string]string) map[string]string {
+	ids := make(map[string]string)
+
+	for _, name := range []string{"staging", "production", "other-project"} {
+		projectID := uint(1)
+		deploymentTargetID := deploymentTargetIDs[name]
+
+		if name == "other-project" {
+			projectID = 2
+			deploymentTargetID = deploymentTargetIDs["staging"]
+		}
+
+		appInstance, err := config.Repo.AppInstance().Create(context.Background(), &models.AppInstance{
+			Name:               "test-app",
+			ProjectID:          projectID,
+			DeploymentTargetID: uuid.MustParse(deploymentTargetID),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids[name] = appInstance.ID.String()
+	}
+
+	return ids
+}
+
+// newPorterAppTestRouter registers the porter app routes with their middleware, serving every route with the handler
+func newPorterAppTestRouter(config *config.Config, handler http.Handler) *chi.Mux {
+	r := chi.NewRouter()
+
+	basePath := &types.Path{
+		RelativePath: "/projects/{project_id}/clusters/{cluster_id}",
+	}
+
+	var routes []*router.Route
+
+	r.Route(basePath.RelativePath, func(r chi.Router) {
+		routes, _ = getPorterAppRoutes(r, config, basePath, shared.NewAPIObjectEndpointFactory(config))
+	})
+
+	for _, route := range routes {
+		route.Handler = handler
+	}
+
+	registerRoutes(config, routes)
+
+	return r
+}
+
+type deployHandler struct {
+	WasCalled        bool
+	DeploymentTarget types.DeploymentTarget
+	Body             string
+}
+
+func (h *deployHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.WasCalled = true
+	h.DeploymentTarget, _ = r.Context().Value(types.DeploymentTargetScope).(types.DeploymentTarget)
+
+	body, _ := io.ReadAll(r.Body)
+	h.Body = strings.TrimSpace(string(body))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
 	"github.com/porter-dev/porter/api/server/handlers/helmrepo"
 	"github.com/porter-dev/porter/api/server/handlers/infra"
+	"github.com/porter-dev/porter/api/server/handlers/opa_policy"
 	"github.com/porter-dev/porter/api/server/handlers/policy"
 	"github.com/porter-dev/porter/api/server/handlers/project"
 	"github.com/porter-dev/porter/api/server/handlers/registry"
@@ -234,6 +235,89 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/log_archive -> project.NewGetLogArchiveSettingHandler
+	getLogArchiveSettingEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/log_archive",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getLogArchiveSettingHandler := project.NewGetLogArchiveSettingHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: getLogArchiveSettingEndpoint,
+		Handler:  getLogArchiveSettingHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/log_archive -> project.NewUpdateLogArchiveSettingHandler
+	updateLogArchiveSettingEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/log_archive",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	updateLogArchiveSettingHandler := project.NewUpdateLogArchiveSettingHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: updateLogArchiveSettingEndpoint,
+		Handler:  updateLogArchiveSettingHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/log_archive/ranges -> project.NewListLogArchiveRangesHandler
+	listLogArchiveRangesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/log_archive/ranges",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listLogArchiveRangesHandler := project.NewListLogArchiveRangesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listLogArchiveRangesEndpoint,
+		Handler:  listLogArchiveRangesHandler,
+		Router:   r,
+	})
+
 	// POST /api/projects/{project_id}/invite_admin -> project.NewProjectInviteAdminHandler
 	projectInviteAdminEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -1578,6 +1662,119 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// POST /api/projects/{project_id}/api_token/{api_token_id}/rotate -> api_token.NewAPITokenRotateHandler
+	apiTokenRotateEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/api_token/{%s}/rotate", relPath, types.URLParamTokenID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	apiTokenRotateHandler := api_token.NewAPITokenRotateHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: apiTokenRotateEndpoint,
+		Handler:  apiTokenRotateHandler,
+		Router:   r,
+	})
+
+	//  POST /api/projects/{project_id}/opa_policies -> opa_policy.NewCreateOPAPolicyBundleHandler
+	createOPAPolicyBundleEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/opa_policies",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createOPAPolicyBundleHandler := opa_policy.NewCreateOPAPolicyBundleHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: createOPAPolicyBundleEndpoint,
+		Handler:  createOPAPolicyBundleHandler,
+		Router:   r,
+	})
+
+	//  GET /api/projects/{project_id}/opa_policies -> opa_policy.NewListOPAPolicyBundlesHandler
+	listOPAPolicyBundlesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/opa_policies",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listOPAPolicyBundlesHandler := opa_policy.NewListOPAPolicyBundlesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: listOPAPolicyBundlesEndpoint,
+		Handler:  listOPAPolicyBundlesHandler,
+		Router:   r,
+	})
+
+	//  DELETE /api/projects/{project_id}/opa_policies/{opa_policy_bundle_id} -> opa_policy.NewDeleteOPAPolicyBundleHandler
+	deleteOPAPolicyBundleEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: fmt.Sprintf("%s/opa_policies/{%s}", relPath, types.URLParamOPAPolicyBundleID),
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteOPAPolicyBundleHandler := opa_policy.NewDeleteOPAPolicyBundleHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &router.Route{
+		Endpoint: deleteOPAPolicyBundleEndpoint,
+		Handler:  deleteOPAPolicyBundleHandler,
+		Router:   r,
+	})
+
 	//  POST /api/projects/{project_id}/helmrepos -> helmrepo.NewHelmRepoCreateHandler
 	hrCreateEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -47,6 +47,9 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 	projectOAuthRegisterer := NewProjectOAuthScopedRegisterer()
 	notificationRegisterer := NewNotificationScopedRegisterer()
 	slackIntegrationRegisterer := NewSlackIntegrationScopedRegisterer()
+	notifierIntegrationRegisterer := NewNotifierIntegrationScopedRegisterer()
+	auditLogRegisterer := NewAuditLogScopedRegisterer()
+	ssoConnectionRegisterer := NewSSOConnectionScopedRegisterer()
 	projRegisterer := NewProjectScopedRegisterer(
 		cloudProviderRegisterer,
 		clusterRegisterer,
@@ -58,6 +61,9 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 		projectIntegrationRegisterer,
 		projectOAuthRegisterer,
 		slackIntegrationRegisterer,
+		notifierIntegrationRegisterer,
+		auditLogRegisterer,
+		ssoConnectionRegisterer,
 		deploymentTargetRegisterer,
 		notificationRegisterer,
 	)
@@ -275,6 +281,12 @@ func registerRoutes(config *config.Config, routes []*router.Route) {
 					atomicGroup.Use(authNFactory.NewAuthenticated)
 				}
 			case types.ProjectScope:
+				// record mutations in the audit log before authorization, so that denied requests are logged as well
+				if middleware.IsAuditedRoute(route.Endpoint.Metadata) {
+					auditMw := middleware.NewAuditMiddleware(config, route.Endpoint.Metadata)
+					atomicGroup.Use(auditMw.Middleware)
+				}
+
 				policyFactory := authz.NewPolicyMiddleware(config, *route.Endpoint.Metadata, policyDocLoader)
 
 				atomicGroup.Use(policyFactory.Middleware)
@@ -7,11 +7,12 @@ import (
 // FakeUserNotifier just stores data about a single notification,
 // without sending the data anywhere
 type FakeUserNotifier struct {
-	lastPWResetOpts       *notifier.SendPasswordResetEmailOpts
-	lastGHResetOpts       *notifier.SendGithubRelinkEmailOpts
-	lastEmailVerOpts      *notifier.SendEmailVerificationOpts
-	lastProjInvOpts       *notifier.SendProjectInviteEmailOpts
-	lastDeleteProjectOpts *notifier.SendProjectDeleteEmailOpts
+	lastPWResetOpts        *notifier.SendPasswordResetEmailOpts
+	lastGHResetOpts        *notifier.SendGithubRelinkEmailOpts
+	lastEmailVerOpts       *notifier.SendEmailVerificationOpts
+	lastProjInvOpts        *notifier.SendProjectInviteEmailOpts
+	lastDeleteProjectOpts  *notifier.SendProjectDeleteEmailOpts
+	lastAPITokenExpiryOpts *notifier.SendAPITokenExpiryEmailOpts
 }
 
 func NewFakeUserNotifier() notifier.UserNotifier {
@@ -58,3 +59,8 @@ func (f *FakeUserNotifier) SendProjectDeleteEmail(opts *notifier.SendProjectDele
 	f.lastDeleteProjectOpts = opts
 	return nil
 }
+
+func (f *FakeUserNotifier) SendAPITokenExpiryEmail(opts *notifier.SendAPITokenExpiryEmailOpts) error {
+	f.lastAPITokenExpiryOpts = opts
+	return nil
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+type recorderKey struct{}
+
+// Recorder holds the state of the resource changed by an audited request, which handlers record with RecordChange
+type Recorder struct {
+	mu sync.Mutex
+
+	recorded bool
+	before   interface{}
+	after    interface{}
+}
+
+// WithRecorder returns a context with a new Recorder, which handlers called with the context record changes to
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	recorder := &Recorder{}
+
+	return context.WithValue(ctx, recorderKey{}, recorder), recorder
+}
+
+// RecordChange records the state of the resource changed by the request, before and after the change. The states
+// are encoded as JSON, so API types should be passed rather than models, which may hold secrets. before is nil for
+// created resources and after is nil for deleted resources. Requests which are not audited are ignored.
+func RecordChange(ctx context.Context, before, after interface{}) {
+	recorder, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recorder.recorded = true
+	recorder.before = before
+	recorder.after = after
+}
+
+// Change returns the recorded states of the resource, and whether a change was recorded
+func (r *Recorder) Change() (before, after interface{}, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.before, r.after, r.recorded
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/sso"
 	"github.com/porter-dev/porter/internal/telemetry"
 	"github.com/porter-dev/porter/pkg/logger"
 	"github.com/porter-dev/porter/provisioner/client"
@@ -100,6 +101,9 @@ type Config struct {
 	// DNSClient is a client for DNS, if the Porter instance supports vanity URLs
 	DNSClient *dns.Client
 
+	// SSODomainResolver looks up the TXT records which verify the email domains of sso connections
+	SSODomainResolver sso.TXTResolver
+
 	// ClusterControlPlaneClient is a client for ClusterControlPlane
 	ClusterControlPlaneClient porterv1connect.ClusterControlPlaneServiceClient
 
@@ -55,6 +55,35 @@ type ServerConf struct {
 	GoogleClientSecret     string `env:"GOOGLE_CLIENT_SECRET"`
 	GoogleRestrictedDomain string `env:"GOOGLE_RESTRICTED_DOMAIN"`
 
+	// Instance-wide OpenID Connect login, available at /api/sso/oidc/start when an issuer and client are set
+	OIDCIssuerURL    string `env:"OIDC_ISSUER_URL"`
+	OIDCClientID     string `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `env:"OIDC_CLIENT_SECRET"`
+	OIDCScopes       string `env:"OIDC_SCOPES"`
+	OIDCGroupsClaim  string `env:"OIDC_GROUPS_CLAIM"`
+
+	// Instance-wide SAML 2.0 login, available at /api/sso/saml/start. The identity provider is configured
+	// either through its metadata url, or through its sso url, entity id and PEM-encoded signing certificate.
+	SAMLIdPMetadataURL  string `env:"SAML_IDP_METADATA_URL"`
+	SAMLIdPSSOURL       string `env:"SAML_IDP_SSO_URL"`
+	SAMLIdPEntityID     string `env:"SAML_IDP_ENTITY_ID"`
+	SAMLIdPCertificate  string `env:"SAML_IDP_CERTIFICATE"`
+	SAMLGroupsAttribute string `env:"SAML_GROUPS_ATTRIBUTE"`
+
+	// SSOGroupRoleMappings grants project roles to the identity provider groups of users who log in through
+	// instance-wide OIDC or SAML, in the form "group=project_id:role,..."
+	SSOGroupRoleMappings string `env:"SSO_GROUP_ROLE_MAPPINGS"`
+
+	// SSOAutoProvision creates users who log in through instance-wide OIDC or SAML for the first time
+	SSOAutoProvision bool `env:"SSO_AUTO_PROVISION,default=true"`
+
+	// SSOAllowPrivateIdPs allows the sso connections of projects to use identity providers on private addresses
+	// and over http, for instances whose identity providers are on an internal network
+	SSOAllowPrivateIdPs bool `env:"SSO_ALLOW_PRIVATE_IDPS,default=false"`
+
+	// APITokenMaxLifetime is the longest that api tokens can be issued for when they are created or rotated
+	APITokenMaxLifetime time.Duration `env:"API_TOKEN_MAX_LIFETIME,default=8784h"`
+
 	// FeatureFlagClient controls which client to use (database or launch_darkly)
 	FeatureFlagClient  string `env:"FEATURE_FLAG_CLIENT,default=launch_darkly"`
 	LaunchDarklySDKKey string `env:"LAUNCHDARKLY_SDK_KEY"`
@@ -67,6 +96,7 @@ type ServerConf struct {
 	SendgridIncidentAlertTemplateID    string `env:"SENDGRID_INCIDENT_ALERT_TEMPLATE_ID"`
 	SendgridIncidentResolvedTemplateID string `env:"SENDGRID_INCIDENT_RESOLVED_TEMPLATE_ID"`
 	SendgridDeleteProjectTemplateID    string `env:"SENDGRID_DELETE_PROJECT_TEMPLATE_ID"`
+	SendgridAPITokenExpiryTemplateID   string `env:"SENDGRID_API_TOKEN_EXPIRY_TEMPLATE_ID"`
 	SendgridSenderEmail                string `env:"SENDGRID_SENDER_EMAIL"`
 
 	StripeSecretKey      string `env:"STRIPE_SECRET_KEY"`
@@ -99,16 +129,8 @@ type ServerConf struct {
 
 	SegmentClientKey string `env:"SEGMENT_CLIENT_KEY"`
 
-	// DnsProvider controls which provider to use for dns (powerdns or cloudflare)
-	// Setting this to empty string will disable external dns
-	DnsProvider string `env:"DNS_PROVIDER,default=powerdns"`
-
-	// Cloudflare API Key
-	CloudflareAPIToken string `env:"CLOUDFLARE_API_TOKEN"`
-
-	// PowerDNS client API key and the host of the PowerDNS API server
-	PowerDNSAPIServerURL string `env:"POWER_DNS_API_SERVER_URL"`
-	PowerDNSAPIKey       string `env:"POWER_DNS_API_KEY"`
+	// DNSConf configures the dns provider used for Porter subdomains
+	DNSConf
 
 	// Email for an admin user. On a self-hosted instance of Porter, the
 	// admin user is the only user that can log in and register. After the admin
@@ -164,12 +186,62 @@ type ServerConf struct {
 	TelemetryCollectorURL string `env:"TELEMETRY_COLLECTOR_URL,default=localhost:4317"`
 }
 
+// DNSConf is the configuration of the dns provider used for Porter subdomains of the app root domain
+type DNSConf struct {
+	// DnsProvider controls which provider to use for dns (powerdns, cloudflare, route53, clouddns or rfc2136)
+	// Setting this to empty string will disable external dns
+	DnsProvider string `env:"DNS_PROVIDER,default=powerdns"`
+
+	// Cloudflare API Key
+	CloudflareAPIToken string `env:"CLOUDFLARE_API_TOKEN"`
+
+	// PowerDNS client API key and the host of the PowerDNS API server
+	PowerDNSAPIServerURL string `env:"POWER_DNS_API_SERVER_URL"`
+	PowerDNSAPIKey       string `env:"POWER_DNS_API_KEY"`
+
+	// Route53 hosted zone of the app root domain, and optional static credentials. If the hosted zone ID is empty
+	// it is looked up by name, and if the access key is empty the default AWS credential chain is used.
+	Route53HostedZoneID    string `env:"ROUTE53_HOSTED_ZONE_ID"`
+	Route53AccessKeyID     string `env:"ROUTE53_ACCESS_KEY_ID"`
+	Route53SecretAccessKey string `env:"ROUTE53_SECRET_ACCESS_KEY"`
+
+	// Google Cloud DNS managed zone of the app root domain, and optional service account credentials. If the
+	// credentials are empty the application default credentials are used.
+	CloudDNSProjectID       string `env:"CLOUD_DNS_PROJECT_ID"`
+	CloudDNSManagedZone     string `env:"CLOUD_DNS_MANAGED_ZONE"`
+	CloudDNSCredentialsJSON string `env:"CLOUD_DNS_CREDENTIALS_JSON"`
+
+	// RFC2136 nameserver (host:port) which accepts dynamic updates for the app root domain, and the optional
+	// TSIG key used to sign updates. The secret is base64-encoded, and the algorithm defaults to hmac-sha256.
+	RFC2136Nameserver    string `env:"RFC2136_NAMESERVER"`
+	RFC2136TSIGKeyName   string `env:"RFC2136_TSIG_KEY_NAME"`
+	RFC2136TSIGSecret    string `env:"RFC2136_TSIG_SECRET"`
+	RFC2136TSIGAlgorithm string `env:"RFC2136_TSIG_ALGORITHM,default=hmac-sha256"`
+}
+
 // DBConf is the database configuration: if generated from environment variables,
 // it assumes the default docker-compose configuration is used
 type DBConf struct {
 	// EncryptionKey is the key to use for sensitive values that are encrypted at rest
 	EncryptionKey string `env:"ENCRYPTION_KEY,default=__random_strong_encryption_key__"`
 
+	// OldEncryptionKey is the previous EncryptionKey while it is rotated. Values which cannot be decrypted
+	// with EncryptionKey are decrypted with it, until the encryption-key-rotation job has re-encrypted them.
+	OldEncryptionKey string `env:"OLD_ENCRYPTION_KEY"`
+
+	// EncryptionKeyProvider enables envelope encryption: sensitive values are encrypted with per-record data
+	// keys, wrapped by a "local", "awskms", "gcpkms" or "vault" key provider. Values are encrypted with
+	// EncryptionKey if it is empty, and values previously encrypted with EncryptionKey remain readable.
+	EncryptionKeyProvider         string `env:"ENCRYPTION_KEY_PROVIDER"`
+	EncryptionLocalKeyFile        string `env:"ENCRYPTION_LOCAL_KEY_FILE"`
+	EncryptionAWSKMSKeyID         string `env:"ENCRYPTION_AWS_KMS_KEY_ID"`
+	EncryptionAWSKMSRegion        string `env:"ENCRYPTION_AWS_KMS_REGION"`
+	EncryptionGCPKMSKeyName       string `env:"ENCRYPTION_GCP_KMS_KEY_NAME"`
+	EncryptionVaultTransitAddr    string `env:"ENCRYPTION_VAULT_TRANSIT_ADDR"`
+	EncryptionVaultTransitToken   string `env:"ENCRYPTION_VAULT_TRANSIT_TOKEN"`
+	EncryptionVaultTransitMount   string `env:"ENCRYPTION_VAULT_TRANSIT_MOUNT,default=transit"`
+	EncryptionVaultTransitKeyName string `env:"ENCRYPTION_VAULT_TRANSIT_KEY_NAME"`
+
 	Host     string `env:"DB_HOST,default=postgres"`
 	Port     int    `env:"DB_PORT,default=5432"`
 	Username string `env:"DB_USER,default=porter"`
@@ -185,6 +257,14 @@ type DBConf struct {
 	VaultPrefix    string `env:"VAULT_PREFIX,default=production"`
 	VaultAPIKey    string `env:"VAULT_API_KEY"`
 	VaultServerURL string `env:"VAULT_SERVER_URL"`
+
+	// CredentialStorageBackend selects where the credentials of integrations are stored: one of "database",
+	// "vault", "awssecretsmanager" or "gcpsecretmanager". If unset, Vault is used when it is configured.
+	CredentialStorageBackend  string `env:"CREDENTIAL_STORAGE_BACKEND"`
+	CredentialStoragePrefix   string `env:"CREDENTIAL_STORAGE_PREFIX,default=porter"`
+	AWSSecretsManagerRegion   string `env:"AWS_SECRETS_MANAGER_REGION"`
+	AWSSecretsManagerKMSKeyID string `env:"AWS_SECRETS_MANAGER_KMS_KEY_ID"`
+	GCPSecretManagerProjectID string `env:"GCP_SECRET_MANAGER_PROJECT_ID"`
 }
 
 // RedisConf is the redis config required for the provisioner container
@@ -1,10 +1,12 @@
 package loader
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -23,15 +25,15 @@ import (
 	"github.com/porter-dev/porter/internal/auth/sessionstore"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/encryption/kms"
 	"github.com/porter-dev/porter/internal/features"
 	"github.com/porter-dev/porter/internal/helm/urlcache"
-	"github.com/porter-dev/porter/internal/integrations/cloudflare"
-	"github.com/porter-dev/porter/internal/integrations/dns"
-	"github.com/porter-dev/porter/internal/integrations/powerdns"
+	"github.com/porter-dev/porter/internal/integrations/dnsprovider"
 	"github.com/porter-dev/porter/internal/notifier"
 	"github.com/porter-dev/porter/internal/notifier/sendgrid"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	"github.com/porter-dev/porter/internal/repository/gorm"
 	"github.com/porter-dev/porter/internal/telemetry"
 	lr "github.com/porter-dev/porter/pkg/logger"
@@ -75,7 +77,12 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 	}
 
 	var instanceCredentialBackend credentials.CredentialStorage
-	if envConf.DBConf.VaultEnabled {
+	if envConf.DBConf.CredentialStorageBackend != "" {
+		instanceCredentialBackend, err = secretstore.NewCredentialStorage(context.Background(), envConf.DBConf)
+		if err != nil {
+			return nil, fmt.Errorf("error creating credential storage backend: %w", err)
+		}
+	} else if envConf.DBConf.VaultEnabled {
 		if envConf.DBConf.VaultAPIKey == "" || envConf.DBConf.VaultServerURL == "" || envConf.DBConf.VaultPrefix == "" {
 			return nil, errors.New("vault is enabled but missing required environment variables [VAULT_API_KEY,VAULT_SERVER_URL,VAULT_PREFIX]")
 		}
@@ -105,6 +112,11 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 		key[i] = b
 	}
 
+	err = kms.SetKeyProviderFromConf(context.Background(), envConf.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("could not set encryption key provider: %v", err)
+	}
+
 	res.Logger.Info().Msg("Creating new gorm repository")
 	res.Repo = gorm.NewRepository(InstanceDB, &key, instanceCredentialBackend)
 	res.Logger.Info().Msg("Created new gorm repository")
@@ -136,11 +148,12 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 				APIKey:      envConf.ServerConf.SendgridAPIKey,
 				SenderEmail: envConf.ServerConf.SendgridSenderEmail,
 			},
-			PWResetTemplateID:       envConf.ServerConf.SendgridPWResetTemplateID,
-			PWGHTemplateID:          envConf.ServerConf.SendgridPWGHTemplateID,
-			VerifyEmailTemplateID:   envConf.ServerConf.SendgridVerifyEmailTemplateID,
-			ProjectInviteTemplateID: envConf.ServerConf.SendgridProjectInviteTemplateID,
-			DeleteProjectTemplateID: envConf.ServerConf.SendgridDeleteProjectTemplateID,
+			PWResetTemplateID:        envConf.ServerConf.SendgridPWResetTemplateID,
+			PWGHTemplateID:           envConf.ServerConf.SendgridPWGHTemplateID,
+			VerifyEmailTemplateID:    envConf.ServerConf.SendgridVerifyEmailTemplateID,
+			ProjectInviteTemplateID:  envConf.ServerConf.SendgridProjectInviteTemplateID,
+			DeleteProjectTemplateID:  envConf.ServerConf.SendgridDeleteProjectTemplateID,
+			APITokenExpiryTemplateID: envConf.ServerConf.SendgridAPITokenExpiryTemplateID,
 		})
 		res.Logger.Info().Msg("Created new user notifier")
 	}
@@ -301,22 +314,13 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 	res.AnalyticsClient = analytics.InitializeAnalyticsSegmentClient(sc.SegmentClientKey, res.Logger)
 	res.Logger.Info().Msg("Created analytics client")
 
-	switch sc.DnsProvider {
-	case "powerdns":
-		if sc.PowerDNSAPIKey != "" && sc.PowerDNSAPIServerURL != "" {
-			res.DNSClient = &dns.Client{Client: powerdns.NewClient(sc.PowerDNSAPIServerURL, sc.PowerDNSAPIKey, sc.AppRootDomain)}
-		}
-	case "cloudflare":
-		if sc.CloudflareAPIToken != "" {
-			cloudflareClient, err := cloudflare.NewClient(sc.CloudflareAPIToken, sc.AppRootDomain)
-			if err != nil {
-				return res, fmt.Errorf("unable to create cloudflare client: %w", err)
-			}
-
-			res.DNSClient = &dns.Client{Client: cloudflareClient}
-		}
+	res.DNSClient, err = dnsprovider.NewClient(context.Background(), sc.DNSConf, sc.AppRootDomain)
+	if err != nil {
+		return res, err
 	}
 
+	res.SSODomainResolver = net.DefaultResolver
+
 	res.EnableCAPIProvisioner = sc.EnableCAPIProvisioner
 	if sc.EnableCAPIProvisioner {
 		res.Logger.Info().Msg("Creating CCP client")
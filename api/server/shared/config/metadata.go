@@ -10,6 +10,8 @@ type Metadata struct {
 	BasicLogin         bool   `json:"basic_login"`
 	GithubLogin        bool   `json:"github_login"`
 	GoogleLogin        bool   `json:"google_login"`
+	OIDCLogin          bool   `json:"oidc_login"`
+	SAMLLogin          bool   `json:"saml_login"`
 	SlackNotifications bool   `json:"slack_notifications"`
 	Email              bool   `json:"email"`
 	Analytics          bool   `json:"analytics"`
@@ -27,6 +29,8 @@ func MetadataFromConf(sc *env.ServerConf, version string) *Metadata {
 		GithubLogin:             sc.GithubClientID != "" && sc.GithubClientSecret != "" && sc.GithubLoginEnabled,
 		BasicLogin:              sc.BasicLoginEnabled,
 		GoogleLogin:             sc.GoogleClientID != "" && sc.GoogleClientSecret != "",
+		OIDCLogin:               sc.OIDCIssuerURL != "" && sc.OIDCClientID != "",
+		SAMLLogin:               sc.SAMLIdPMetadataURL != "" || (sc.SAMLIdPSSOURL != "" && sc.SAMLIdPCertificate != ""),
 		SlackNotifications:      sc.SlackClientID != "" && sc.SlackClientSecret != "",
 		Email:                   sc.SendgridAPIKey != "",
 		Analytics:               sc.SegmentClientKey != "",
@@ -12,6 +12,15 @@ type APITokenMeta struct {
 	PolicyName string `json:"policy_name"`
 	PolicyUID  string `json:"policy_uid"`
 	Name       string `json:"name"`
+
+	// DeploymentTargetIDs and AppNames restrict the token to the listed deployment targets and apps. The
+	// token is not restricted if they are empty.
+	DeploymentTargetIDs []string `json:"deployment_target_ids"`
+	AppNames            []string `json:"app_names"`
+
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
 }
 
 type APIToken struct {
@@ -25,4 +34,20 @@ type CreateAPIToken struct {
 	PolicyUID string    `json:"policy_uid" form:"required"`
 	ExpiresAt time.Time `json:"expires_at"`
 	Name      string    `json:"name" form:"required"`
+
+	// DeploymentTargets are the ids or names of the deployment targets which the token is restricted to
+	DeploymentTargets []string `json:"deployment_targets" form:"dive,required,excludesrune=0x2C"`
+
+	// AppNames are the names of the apps which the token is restricted to
+	AppNames []string `json:"app_names" form:"dive,required,excludesrune=0x2C"`
+}
+
+// RotateAPITokenRequest is the request to issue a new secret for an api token
+type RotateAPITokenRequest struct {
+	// OverlapSeconds is how long the previous secret of the token remains valid, so that the clients of the
+	// token can be updated. The previous secret stops working immediately if it is 0.
+	OverlapSeconds uint `json:"overlap_seconds" form:"max=604800"`
+
+	// ExpiresAt is the new expiry of the token, which is required since rotating reissues the token
+	ExpiresAt time.Time `json:"expires_at"`
 }
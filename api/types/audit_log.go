@@ -0,0 +1,66 @@
+package types
+
+import (
+	"time"
+)
+
+// AuditLogActorType is the kind of actor which made an audited request
+type AuditLogActorType string
+
+const (
+	// AuditLogActorType_User is a user authenticated with a session or a bearer token
+	AuditLogActorType_User AuditLogActorType = "user"
+
+	// AuditLogActorType_APIToken is a project API token
+	AuditLogActorType_APIToken AuditLogActorType = "api_token"
+)
+
+// AuditLog is a record of a single mutation made through the API
+type AuditLog struct {
+	ID         uint              `json:"id"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ProjectID  uint              `json:"project_id"`
+	ClusterID  uint              `json:"cluster_id,omitempty"`
+	ActorType  AuditLogActorType `json:"actor_type"`
+	ActorID    string            `json:"actor_id"`
+	ActorEmail string            `json:"actor_email"`
+	Action     string            `json:"action"`
+	Method     string            `json:"method"`
+	Resource   string            `json:"resource"`
+	Path       string            `json:"path"`
+	RequestID  string            `json:"request_id"`
+	StatusCode int               `json:"status_code"`
+
+	// ResourceParams are the URL parameters of the request, such as the cluster id or the name of a release
+	ResourceParams map[string]string `json:"resource_params,omitempty"`
+
+	// Changes are the fields of the resource which were changed by the request, with sensitive values redacted
+	Changes []AuditLogChange `json:"changes,omitempty"`
+}
+
+// AuditLogChange is a field of a resource which was changed by an audited request. Nested fields are joined by
+// periods in the path. Before is unset for created fields, and After is unset for removed fields.
+type AuditLogChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// ListAuditLogsRequest is the request for listing the audit logs of a project. Since and until are RFC 3339 timestamps.
+type ListAuditLogsRequest struct {
+	*PaginationRequest
+	ClusterID uint   `schema:"cluster_id"`
+	ActorID   string `schema:"actor_id"`
+	ActorType string `schema:"actor_type"`
+	Action    string `schema:"action"`
+	Resource  string `schema:"resource"`
+	RequestID string `schema:"request_id"`
+	Since     string `schema:"since"`
+	Until     string `schema:"until"`
+}
+
+// ListAuditLogsResponse is the response for listing the audit logs of a project, newest first
+type ListAuditLogsResponse struct {
+	AuditLogs  []AuditLog         `json:"audit_logs"`
+	Pagination PaginationResponse `json:"pagination"`
+}
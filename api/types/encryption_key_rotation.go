@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// EncryptionKeyRotationStatus is the progress of the re-encryption of stored secrets under a key
+type EncryptionKeyRotationStatus struct {
+	// KeyID is the id of the key which values are re-encrypted under
+	KeyID string `json:"key_id"`
+
+	// Completed is true once every table has been re-encrypted under the key
+	Completed bool `json:"completed"`
+
+	Tables []*EncryptionKeyRotationTable `json:"tables"`
+}
+
+// EncryptionKeyRotationTable is the progress of the re-encryption of one table
+type EncryptionKeyRotationTable struct {
+	Table       string     `json:"table"`
+	LastID      uint       `json:"last_id"`
+	Rotated     uint       `json:"rotated"`
+	Skipped     uint       `json:"skipped"`
+	Failed      uint       `json:"failed"`
+	FailedIDs   []uint     `json:"failed_ids,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
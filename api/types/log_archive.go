@@ -0,0 +1,78 @@
+package types
+
+import "time"
+
+// LogArchiveSetting configures the archival of the app logs of a project to S3-compatible storage
+type LogArchiveSetting struct {
+	ProjectID        uint `json:"project_id"`
+	Enabled          bool `json:"enabled"`
+	AWSIntegrationID uint `json:"aws_integration_id"`
+
+	// EnabledAt is when archival was last enabled. Logs written before are not archived
+	EnabledAt time.Time `json:"enabled_at"`
+
+	Bucket   string `json:"bucket"`
+	Region   string `json:"region"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Prefix   string `json:"prefix,omitempty"`
+}
+
+// UpdateLogArchiveSettingRequest is the request to configure the log archive of a project
+type UpdateLogArchiveSettingRequest struct {
+	Enabled bool `json:"enabled"`
+
+	// AWSIntegrationID is the id of the AWS integration whose credentials are used to write to the bucket
+	AWSIntegrationID uint   `json:"aws_integration_id" form:"required"`
+	Bucket           string `json:"bucket" form:"required"`
+	Region           string `json:"region" form:"required"`
+
+	// Endpoint is the optional URL of an S3-compatible storage, such as MinIO. It must use https and must not be a private address
+	Endpoint string `json:"endpoint" form:"omitempty,url"`
+	Prefix   string `json:"prefix"`
+}
+
+// LogArchive is an archived object holding the logs of a service for a window of time, as gzip-compressed
+// newline-delimited JSON
+type LogArchive struct {
+	ClusterID          uint      `json:"cluster_id"`
+	DeploymentTargetID string    `json:"deployment_target_id"`
+	AppName            string    `json:"app_name"`
+	ServiceName        string    `json:"service_name"`
+	Bucket             string    `json:"bucket"`
+	ObjectKey          string    `json:"object_key"`
+	StartTime          time.Time `json:"start_time"`
+	EndTime            time.Time `json:"end_time"`
+	LineCount          int       `json:"line_count"`
+	SizeBytes          int64     `json:"size_bytes"`
+}
+
+// ListLogArchiveRangesRequest filters the archived log ranges of a project
+type ListLogArchiveRangesRequest struct {
+	ClusterID          uint      `schema:"cluster_id"`
+	DeploymentTargetID string    `schema:"deployment_target_id"`
+	AppName            string    `schema:"app_name"`
+	ServiceName        string    `schema:"service_name"`
+	StartRange         time.Time `schema:"start_range"`
+	EndRange           time.Time `schema:"end_range"`
+}
+
+// LogArchiveRange is a contiguous range of time for which the logs of a service are archived
+type LogArchiveRange struct {
+	ClusterID          uint      `json:"cluster_id"`
+	DeploymentTargetID string    `json:"deployment_target_id"`
+	AppName            string    `json:"app_name"`
+	ServiceName        string    `json:"service_name"`
+	StartTime          time.Time `json:"start_time"`
+	EndTime            time.Time `json:"end_time"`
+	LineCount          int       `json:"line_count"`
+
+	// Archives are the objects which make up the range, oldest first
+	Archives []LogArchive `json:"archives"`
+}
+
+// ListLogArchiveRangesResponse is the response to listing the archived log ranges of a project
+type ListLogArchiveRangesResponse struct {
+	// ArchivedUntil is the time until which the logs of each cluster of the project were archived, keyed by cluster id
+	ArchivedUntil map[uint]time.Time `json:"archived_until"`
+	Ranges        []LogArchiveRange  `json:"ranges"`
+}
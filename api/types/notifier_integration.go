@@ -0,0 +1,57 @@
+package types
+
+import "time"
+
+const (
+	URLParamNotifierIntegrationID URLParam = "notifier_integration_id"
+)
+
+// NotifierIntegrationKind is the kind of service that a notifier integration sends notifications to
+type NotifierIntegrationKind string
+
+const (
+	// NotifierIntegrationKind_Webhook posts signed JSON notifications to a generic HTTP endpoint
+	NotifierIntegrationKind_Webhook NotifierIntegrationKind = "webhook"
+
+	// NotifierIntegrationKind_MSTeams posts adaptive cards to a Microsoft Teams incoming webhook
+	NotifierIntegrationKind_MSTeams NotifierIntegrationKind = "msteams"
+
+	// NotifierIntegrationKind_PagerDuty sends events to a PagerDuty Events API v2 integration
+	NotifierIntegrationKind_PagerDuty NotifierIntegrationKind = "pagerduty"
+)
+
+// NotifierIntegration is a project-wide integration which receives deployment and incident notifications.
+// Its secrets are never returned.
+type NotifierIntegration struct {
+	ID        uint                    `json:"id"`
+	ProjectID uint                    `json:"project_id"`
+	Name      string                  `json:"name"`
+	Kind      NotifierIntegrationKind `json:"kind"`
+
+	// Deployments and Incidents control which notifications are sent to the integration
+	Deployments bool `json:"deployments"`
+	Incidents   bool `json:"incidents"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateNotifierIntegrationRequest is the request to create a notifier integration
+type CreateNotifierIntegrationRequest struct {
+	Name string                  `json:"name" form:"required,max=255"`
+	Kind NotifierIntegrationKind `json:"kind" form:"required,oneof=webhook msteams pagerduty"`
+
+	// URL is the endpoint of a webhook or the incoming webhook URL of a Microsoft Teams channel
+	URL string `json:"url" form:"omitempty,url"`
+
+	// SigningSecret is the secret used to sign the payloads of a webhook
+	SigningSecret string `json:"signing_secret"`
+
+	// RoutingKey is the integration key of a PagerDuty service
+	RoutingKey string `json:"routing_key"`
+
+	Deployments bool `json:"deployments"`
+	Incidents   bool `json:"incidents"`
+}
+
+// ListNotifierIntegrationsResponse is the response to listing the notifier integrations of a project
+type ListNotifierIntegrationsResponse []*NotifierIntegration
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// OPAPolicyBundleMatch selects the objects in a cluster that the policies of a bundle are evaluated against
+type OPAPolicyBundleMatch struct {
+	// KubernetesService restricts the bundle to clusters of a kind, like `eks`
+	KubernetesService string `json:"kubernetes_service,omitempty"`
+
+	// parameters for Helm releases
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	ChartName string `json:"chart_name,omitempty"`
+
+	// generic labels parameter, used for pods and daemonsets
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// parameters for CRDs
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Resource string `json:"resource,omitempty"`
+}
+
+// OPAPolicy is a single Rego module in a policy bundle
+type OPAPolicy struct {
+	// Name is a display name for the policy
+	Name string `json:"name" form:"required"`
+
+	// Module is the Rego source of the policy
+	Module string `json:"module" form:"required"`
+}
+
+// OPAPolicyBundle is a user-supplied collection of Rego policies. Bundles of kind porter_app are evaluated against
+// apps when they are applied, and all other bundles are evaluated by the recommender alongside the built-in policies
+type OPAPolicyBundle struct {
+	ID        uint      `json:"id"`
+	ProjectID uint      `json:"project_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name             string               `json:"name"`
+	Kind             string               `json:"kind"`
+	Match            OPAPolicyBundleMatch `json:"match"`
+	MustExist        bool                 `json:"must_exist"`
+	OverrideSeverity string               `json:"override_severity,omitempty"`
+	Enforcement      OPAPolicyEnforcement `json:"enforcement,omitempty"`
+	Policies         []OPAPolicy          `json:"policies"`
+}
+
+// CreateOPAPolicyBundleRequest is the request to upload a policy bundle. A bundle with the same name in the
+// project is replaced.
+type CreateOPAPolicyBundleRequest struct {
+	Name string `json:"name" form:"required,dns1123"`
+
+	// Kind is the kind of object the policies are evaluated against: one of helm_release, pod, daemonset, crd_list or porter_app
+	Kind string `json:"kind" form:"required,oneof=helm_release pod daemonset crd_list porter_app"`
+
+	Match            OPAPolicyBundleMatch `json:"match"`
+	MustExist        bool                 `json:"must_exist"`
+	OverrideSeverity string               `json:"override_severity" form:"omitempty,oneof=critical high low"`
+
+	// Enforcement is only supported for porter_app bundles, and defaults to warn
+	Enforcement OPAPolicyEnforcement `json:"enforcement" form:"omitempty,oneof=deny warn"`
+
+	Policies []OPAPolicy `json:"policies" form:"required,min=1,dive"`
+}
+
+// OPAPolicyEnforcement is how a violation of a porter_app policy bundle is handled when an app is applied
+type OPAPolicyEnforcement string
+
+const (
+	// OPAPolicyEnforcement_Deny rejects the app before anything is deployed
+	OPAPolicyEnforcement_Deny OPAPolicyEnforcement = "deny"
+	// OPAPolicyEnforcement_Warn deploys the app and reports the violation as a warning
+	OPAPolicyEnforcement_Warn OPAPolicyEnforcement = "warn"
+)
+
+// PorterAppPolicyViolation is a service of an app which does not pass a porter_app policy
+type PorterAppPolicyViolation struct {
+	ServiceName string               `json:"service_name"`
+	PolicyID    string               `json:"policy_id"`
+	PolicyTitle string               `json:"policy_title"`
+	Severity    string               `json:"severity,omitempty"`
+	Enforcement OPAPolicyEnforcement `json:"enforcement"`
+	Messages    []string             `json:"messages,omitempty"`
+}
+
+// String describes the violation by its service name and policy title
+func (v PorterAppPolicyViolation) String() string {
+	return fmt.Sprintf("service %s: %s", v.ServiceName, v.PolicyTitle)
+}
@@ -51,6 +51,7 @@ var ScopeHeirarchy = ScopeTree{
 				ReleaseScope: {},
 			},
 			PreviewEnvironmentScope: {},
+			DeploymentTargetScope:   {},
 		},
 		RegistryScope:        {},
 		HelmRepoScope:        {},
@@ -120,6 +120,8 @@ const (
 	PorterAppEventType_AppEvent PorterAppEventType = "APP_EVENT"
 	// PorterAppEventType_Notification represents a translation of the porter agent app event into the new notification format, which details everything that occurs while the app is running
 	PorterAppEventType_Notification PorterAppEventType = "NOTIFICATION"
+	// PorterAppEventType_EnvGroupRollout represents the redeploy of an app which was triggered by an update to one of its linked environment groups
+	PorterAppEventType_EnvGroupRollout PorterAppEventType = "ENV_GROUP_ROLLOUT"
 )
 
 // PorterAppEventStatus is an alias for a string that represents a Porter Stack Event Status
@@ -134,6 +134,7 @@ type Collaborator struct {
 	UserID    uint   `json:"user_id"`
 	Email     string `json:"email"`
 	ProjectID uint   `json:"project_id"`
+	PolicyUID string `json:"policy_uid,omitempty"`
 }
 
 // ListCollaboratorsResponse is a struct that contains the response from a `GET projects/{project_id}/collaborators` request
@@ -143,6 +144,10 @@ type ListCollaboratorsResponse []*Collaborator
 type UpdateRoleRequest struct {
 	UserID uint   `json:"user_id,required"`
 	Kind   string `json:"kind,required"`
+
+	// PolicyUID is the unique id of the project policy granted by a custom role. It is required
+	// for the custom role kind, and ignored otherwise.
+	PolicyUID string `json:"policy_uid"`
 }
 
 // UpdateRoleResponse is a struct that contains the response from a `POST projects/{project_id}/roles` request
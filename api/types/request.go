@@ -65,6 +65,7 @@ const (
 	URLParamDeploymentTargetIdentifier URLParam = "deployment_target_identifier"
 	URLParamWebhookID                  URLParam = "webhook_id"
 	URLParamJobRunName                 URLParam = "job_run_name"
+	URLParamOPAPolicyBundleID          URLParam = "opa_policy_bundle_id"
 )
 
 type Path struct {
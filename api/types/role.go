@@ -13,4 +13,7 @@ type Role struct {
 	Kind      RoleKind `json:"kind"`
 	UserID    uint     `json:"user_id"`
 	ProjectID uint     `json:"project_id"`
+
+	// PolicyUID is the unique id of the project policy granted by a custom role
+	PolicyUID string `json:"policy_uid,omitempty"`
 }
@@ -0,0 +1,126 @@
+package types
+
+import "time"
+
+const (
+	URLParamSSOConnectionID URLParam = "sso_connection_id"
+
+	// URLParamSSOConnection is either the unique id of a project sso connection, or "oidc" or "saml" for
+	// the sso login configured for the whole instance
+	URLParamSSOConnection URLParam = "sso_connection"
+)
+
+// SSOConnectionKind is the protocol used by an sso connection
+type SSOConnectionKind string
+
+const (
+	// SSOConnectionKind_OIDC authenticates users with an OpenID Connect identity provider
+	SSOConnectionKind_OIDC SSOConnectionKind = "oidc"
+
+	// SSOConnectionKind_SAML authenticates users with a SAML 2.0 identity provider
+	SSOConnectionKind_SAML SSOConnectionKind = "saml"
+)
+
+// SSOGroupRoleMapping grants a project role to the members of an identity provider group
+type SSOGroupRoleMapping struct {
+	Group string   `json:"group" form:"required"`
+	Role  RoleKind `json:"role" form:"required,oneof=admin developer viewer"`
+}
+
+// SSOConnection is a single sign-on connection of a project to an OIDC or SAML identity provider. Users with
+// an email in one of its domains log in through the identity provider. Its client secret is never returned.
+type SSOConnection struct {
+	ID        uint              `json:"id"`
+	UniqueID  string            `json:"unique_id"`
+	ProjectID uint              `json:"project_id"`
+	Name      string            `json:"name"`
+	Kind      SSOConnectionKind `json:"kind"`
+	Domains   []SSODomain       `json:"domains"`
+
+	// AutoProvision creates users who log in for the first time
+	AutoProvision bool `json:"auto_provision"`
+
+	// DefaultRole is granted to users who are not in any mapped group. Users without a role are not
+	// added to the project.
+	DefaultRole       RoleKind              `json:"default_role,omitempty"`
+	GroupRoleMappings []SSOGroupRoleMapping `json:"group_role_mappings"`
+
+	IssuerURL   string   `json:"issuer_url,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+	GroupsClaim string   `json:"groups_claim,omitempty"`
+
+	IdPMetadataURL  string `json:"idp_metadata_url,omitempty"`
+	IdPSSOURL       string `json:"idp_sso_url,omitempty"`
+	IdPEntityID     string `json:"idp_entity_id,omitempty"`
+	GroupsAttribute string `json:"groups_attribute,omitempty"`
+
+	// LoginURL starts a login through the connection
+	LoginURL string `json:"login_url"`
+
+	// RedirectURL is the OIDC redirect url, or the SAML assertion consumer service url, to register with the
+	// identity provider
+	RedirectURL string `json:"redirect_url"`
+
+	// MetadataURL is the url of the SAML service provider metadata
+	MetadataURL string `json:"metadata_url,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SSODomain is an email domain claimed by an sso connection. Users in the domain only log in through the
+// connection once the domain is verified, by publishing the verification token in a TXT record.
+type SSODomain struct {
+	Domain   string `json:"domain"`
+	Verified bool   `json:"verified"`
+
+	// VerificationRecord is the name of the TXT record which must contain the verification token
+	VerificationRecord string `json:"verification_record"`
+	VerificationToken  string `json:"verification_token"`
+}
+
+// SSODomainVerificationRecord returns the name of the TXT record which verifies an sso connection domain
+func SSODomainVerificationRecord(domain string) string {
+	return "_porter-verification." + domain
+}
+
+// CreateSSOConnectionRequest is the request to create an sso connection
+type CreateSSOConnectionRequest struct {
+	Name    string            `json:"name" form:"required,max=255"`
+	Kind    SSOConnectionKind `json:"kind" form:"required,oneof=oidc saml"`
+	Domains []string          `json:"domains" form:"required,min=1,dive,fqdn"`
+
+	AutoProvision     bool                  `json:"auto_provision"`
+	DefaultRole       RoleKind              `json:"default_role" form:"omitempty,oneof=admin developer viewer"`
+	GroupRoleMappings []SSOGroupRoleMapping `json:"group_role_mappings" form:"dive"`
+
+	// OIDC connections
+	IssuerURL    string   `json:"issuer_url" form:"required_if=Kind oidc,omitempty,url"`
+	ClientID     string   `json:"client_id" form:"required_if=Kind oidc"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+	GroupsClaim  string   `json:"groups_claim"`
+
+	// SAML connections are configured either through the metadata url of the identity provider, or through
+	// its sso url, entity id and PEM-encoded signing certificate
+	IdPMetadataURL  string `json:"idp_metadata_url" form:"omitempty,url"`
+	IdPSSOURL       string `json:"idp_sso_url" form:"omitempty,url"`
+	IdPEntityID     string `json:"idp_entity_id"`
+	IdPCertificate  string `json:"idp_certificate"`
+	GroupsAttribute string `json:"groups_attribute"`
+}
+
+// ListSSOConnectionsResponse is the response to listing the sso connections of a project
+type ListSSOConnectionsResponse []*SSOConnection
+
+// SSOLookupRequest is the request to find the sso login for an email address
+type SSOLookupRequest struct {
+	Email string `schema:"email" form:"required,email"`
+}
+
+// SSOLookupResponse is the sso login for an email address
+type SSOLookupResponse struct {
+	// Required is true if users with the email must log in through sso
+	Required bool   `json:"required"`
+	LoginURL string `json:"login_url,omitempty"`
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WorkerJobRun is a single enqueued run of a job in the workers service
+type WorkerJobRun struct {
+	ID             uint                   `json:"id"`
+	JobID          string                 `json:"job_id"`
+	Input          map[string]interface{} `json:"input,omitempty"`
+	ConcurrencyKey string                 `json:"concurrency_key,omitempty"`
+	Status         string                 `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	MaxAttempts    int                    `json:"max_attempts"`
+	LastError      string                 `json:"last_error,omitempty"`
+	Result         json.RawMessage        `json:"result,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	NextRunAt      time.Time              `json:"next_run_at"`
+	StartedAt      *time.Time             `json:"started_at,omitempty"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+}
+
+// WorkerJobSchedule is the schedule of a job which the workers service enqueues periodically
+type WorkerJobSchedule struct {
+	JobID     string     `json:"job_id"`
+	Schedule  string     `json:"schedule"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastRunID uint       `json:"last_run_id,omitempty"`
+	NextRunAt time.Time  `json:"next_run_at"`
+}
@@ -25,6 +25,7 @@ func RegisterCommands() (*cobra.Command, error) {
 
 	rootCmd.AddCommand(registerCommand_App(cliConf))
 	rootCmd.AddCommand(registerCommand_Apply(cliConf))
+	rootCmd.AddCommand(registerCommand_Audit(cliConf))
 	rootCmd.AddCommand(registerCommand_Auth(cliConf))
 	rootCmd.AddCommand(registerCommand_Cluster(cliConf))
 	rootCmd.AddCommand(registerCommand_Config(cliConf))
@@ -300,14 +300,103 @@ in that it only updates the app, but does not attempt to build a new image.`,
 		Use:   "logs [application]",
 		Args:  cobra.MinimumNArgs(1),
 		Short: "Streams the latest logs for an application.",
+		Long: fmt.Sprintf(`
+  %s
+
+Streams the logs of an application as they are written. To print the logs of a time range instead,
+use the --until flag or set --follow=false. Times are either durations relative to now, or RFC3339 timestamps:
+
+  %s
+
+Logs can be filtered by service, revision number, pod and a search string, and printed as JSON:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app logs\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app logs example-app --since 2h --until 1h"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app logs example-app --service web --revision 12 --search timeout --output json --follow=false"),
+		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return checkLoginAndRunWithConfig(cmd, cliConf, args, appLogs)
 		},
 	}
 	appLogsCmd.PersistentFlags().String("service", "", "the name of the service to get logs for")
+	appLogsCmd.PersistentFlags().String("since", "", "only show logs newer than a duration such as 1h30m, or an RFC3339 timestamp (default 24h)")
+	appLogsCmd.PersistentFlags().String("until", "", "only show logs older than a duration such as 1h30m, or an RFC3339 timestamp. Implies --follow=false")
+	appLogsCmd.PersistentFlags().String("search", "", "only show logs containing the search string")
+	appLogsCmd.PersistentFlags().String("revision", "", "only show logs of a revision, given by its number or id")
+	appLogsCmd.PersistentFlags().String("pod", "", "only show logs of a pod")
+	appLogsCmd.PersistentFlags().StringP("output", "o", v2.LogOutput_Text, "the output format of the logs (text, json)")
+	appLogsCmd.PersistentFlags().BoolP("follow", "f", true, "stream new logs as they are written")
 
 	appCmd.AddCommand(appLogsCmd)
 
+	// appPortForwardCmd represents the "porter app port-forward" subcommand
+	appPortForwardCmd := &cobra.Command{
+		Use:   "port-forward [application] [LOCAL_PORT:]REMOTE_PORT...",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Forwards local ports to the services of an application.",
+		Long: fmt.Sprintf(`
+  %s
+
+Forwards one or more local ports to a ready pod of a service. If the pod restarts or the service is
+redeployed, the ports are forwarded to a new ready pod. Only supported for apps deployed with porter.yaml v2.
+
+  %s
+
+To forward ports to several services at once, specify the ports of each service in its --service flag:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app port-forward\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app port-forward my-app --service web 8080:80"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app port-forward my-app --service web=8080:80 --service worker=9090:9000,9091:9001"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appPortForward)
+		},
+	}
+	appPortForwardCmd.PersistentFlags().StringArray("service", nil, "the name of the service to forward ports to, optionally followed by its ports (e.g. worker=9090:9000)")
+
+	appCmd.AddCommand(appPortForwardCmd)
+
+	// appDevCmd represents the "porter app dev" subcommand
+	appDevCmd := &cobra.Command{
+		Use:   "dev [application] -- [COMMAND [args...]]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Runs a service of an application against your local code.",
+		Long: fmt.Sprintf(`
+  %s
+
+Starts a copy of a service's pod in the cluster, with the env groups of the application attached.
+Your local directory is synced into the pod's working directory as files change, the pod's logs are
+streamed, and the service's ports are forwarded to localhost. The pod is deleted on exit.
+
+Files are synced with tar, which must be available in the service's image. Use a command which reloads
+on file changes, such as a dev server, to pick up the synced files:
+
+  %s
+
+To sync a different directory, or to forward different ports, use the --dir, --remote-dir and --port flags:
+
+  %s
+`,
+			color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter app dev\":"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app dev my-app --service web -- npm run dev"),
+			color.New(color.FgGreen, color.Bold).Sprintf("porter app dev my-app --service web --dir ./src --remote-dir /app/src --port 3000:8080"),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, appDev)
+		},
+	}
+	appDevCmd.PersistentFlags().String("service", "", "the name of the service to run")
+	appDevCmd.PersistentFlags().StringP("dir", "d", ".", "the local directory to sync into the pod")
+	appDevCmd.PersistentFlags().String("remote-dir", "", "the directory in the pod to sync to, defaults to the working directory of the service")
+	appDevCmd.PersistentFlags().StringArray("port", nil, "a port to forward in the [LOCAL_PORT:]REMOTE_PORT format, defaults to the ports of the service")
+	appDevCmd.PersistentFlags().StringArray("ignore", []string{".git"}, "a pattern of file and directory names which are not synced")
+
+	appCmd.AddCommand(appDevCmd)
+
 	return appCmd
 }
 
@@ -603,12 +692,72 @@ func appLogs(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client
 		serviceName = serviceFlag
 	}
 
+	now := time.Now()
+
+	sinceFlag, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return fmt.Errorf("error getting since flag: %w", err)
+	}
+	since, err := v2.ParseLogTime(sinceFlag, now)
+	if err != nil {
+		return fmt.Errorf("invalid since flag: %w", err)
+	}
+
+	untilFlag, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return fmt.Errorf("error getting until flag: %w", err)
+	}
+	until, err := v2.ParseLogTime(untilFlag, now)
+	if err != nil {
+		return fmt.Errorf("invalid until flag: %w", err)
+	}
+
+	search, err := cmd.Flags().GetString("search")
+	if err != nil {
+		return fmt.Errorf("error getting search flag: %w", err)
+	}
+
+	revision, err := cmd.Flags().GetString("revision")
+	if err != nil {
+		return fmt.Errorf("error getting revision flag: %w", err)
+	}
+
+	podName, err := cmd.Flags().GetString("pod")
+	if err != nil {
+		return fmt.Errorf("error getting pod flag: %w", err)
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return fmt.Errorf("error getting output flag: %w", err)
+	}
+
+	follow, err := cmd.Flags().GetBool("follow")
+	if err != nil {
+		return fmt.Errorf("error getting follow flag: %w", err)
+	}
+
+	// an end time only makes sense for logs which have already been written
+	if untilFlag != "" {
+		if follow && cmd.Flags().Changed("follow") {
+			return fmt.Errorf("--until cannot be used with --follow")
+		}
+		follow = false
+	}
+
 	err = v2.AppLogs(ctx, v2.AppLogsInput{
 		CLIConfig:            cliConfig,
 		Client:               client,
 		AppName:              appName,
 		DeploymentTargetName: deploymentTargetName,
 		ServiceName:          serviceName,
+		Since:                since,
+		Until:                until,
+		Search:               search,
+		Revision:             revision,
+		PodName:              podName,
+		Output:               output,
+		Follow:               follow,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to get app logs: %w", err)
@@ -1508,6 +1657,19 @@ func appCreateEphemeralPodFromExisting(
 	container string,
 	args []string,
 ) (*v1.Pod, error) {
+	newPod := appEphemeralPodFromExisting(existing, container, args)
+
+	// create the pod and return it
+	return config.Clientset.CoreV1().Pods(existing.ObjectMeta.Namespace).Create(
+		ctx,
+		newPod,
+		metav1.CreateOptions{},
+	)
+}
+
+// appEphemeralPodFromExisting returns a copy of an existing pod which runs the passed in command in the given container.
+// If no command is passed in, the container keeps its original command.
+func appEphemeralPodFromExisting(existing *v1.Pod, container string, args []string) *v1.Pod {
 	newPod := existing.DeepCopy()
 
 	// only copy the pod spec, overwrite metadata
@@ -1521,22 +1683,17 @@ func appCreateEphemeralPodFromExisting(
 	// set restart policy to never
 	newPod.Spec.RestartPolicy = v1.RestartPolicyNever
 
-	// change the command in the pod to the passed in pod command
-	cmdRoot := args[0]
-	cmdArgs := make([]string, 0)
-
 	// annotate with the ephemeral pod tag
 	newPod.Labels = make(map[string]string)
 	newPod.Labels["porter/ephemeral-pod"] = "true"
 
-	if len(args) > 1 {
-		cmdArgs = args[1:]
-	}
-
 	for i := 0; i < len(newPod.Spec.Containers); i++ {
 		if newPod.Spec.Containers[i].Name == container {
-			newPod.Spec.Containers[i].Command = []string{cmdRoot}
-			newPod.Spec.Containers[i].Args = cmdArgs
+			// change the command in the pod to the passed in pod command
+			if len(args) > 0 {
+				newPod.Spec.Containers[i].Command = []string{args[0]}
+				newPod.Spec.Containers[i].Args = args[1:]
+			}
 			newPod.Spec.Containers[i].TTY = true
 			newPod.Spec.Containers[i].Stdin = true
 			newPod.Spec.Containers[i].StdinOnce = true
@@ -1586,12 +1743,7 @@ func appCreateEphemeralPodFromExisting(
 
 	newPod.Spec.NodeName = ""
 
-	// create the pod and return it
-	return config.Clientset.CoreV1().Pods(existing.ObjectMeta.Namespace).Create(
-		ctx,
-		newPod,
-		metav1.CreateOptions{},
-	)
+	return newPod
 }
 
 func appUpdateTag(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
@@ -0,0 +1,547 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/porter-dev/api-contracts/generated/go/helpers"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// appDevSyncInterval is how often the local directory is checked for changes
+const appDevSyncInterval = time.Second
+
+// appDevFileState is the state of a local file which is used to detect changes
+type appDevFileState struct {
+	ModTime time.Time
+	Size    int64
+	Mode    fs.FileMode
+}
+
+func appDev(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	project, err := client.GetProject(ctx, cliConfig.Project)
+	if err != nil {
+		return fmt.Errorf("could not retrieve project from Porter API. Please contact support@porter.run")
+	}
+	if !project.ValidateApplyV2 {
+		return fmt.Errorf("dev mode is only supported for apps deployed with porter.yaml v2")
+	}
+
+	serviceName, err := cmd.Flags().GetString("service")
+	if err != nil {
+		return fmt.Errorf("error getting service flag: %w", err)
+	}
+	if serviceName == "" {
+		return fmt.Errorf("a service must be specified with --service")
+	}
+
+	localDir, err := cmd.Flags().GetString("dir")
+	if err != nil {
+		return fmt.Errorf("error getting dir flag: %w", err)
+	}
+	localDir, err = filepath.Abs(localDir)
+	if err != nil {
+		return fmt.Errorf("invalid local directory: %w", err)
+	}
+	if info, err := os.Stat(localDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("local directory %s does not exist", localDir)
+	}
+
+	remoteDir, err := cmd.Flags().GetString("remote-dir")
+	if err != nil {
+		return fmt.Errorf("error getting remote-dir flag: %w", err)
+	}
+
+	ports, err := cmd.Flags().GetStringArray("port")
+	if err != nil {
+		return fmt.Errorf("error getting port flag: %w", err)
+	}
+	for _, port := range ports {
+		if _, err := parseAppPortForwardPort(port); err != nil {
+			return fmt.Errorf("invalid port %q: %w", port, err)
+		}
+	}
+
+	ignorePatterns, err := cmd.Flags().GetStringArray("ignore")
+	if err != nil {
+		return fmt.Errorf("error getting ignore flag: %w", err)
+	}
+	for _, pattern := range ignorePatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+	}
+
+	envGroups, err := appDevEnvGroups(ctx, client, cliConfig, appName)
+	if err != nil {
+		return err
+	}
+
+	config := &KubernetesSharedConfig{
+		Client:    client,
+		CLIConfig: cliConfig,
+	}
+
+	err = config.setSharedConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("could not retrieve kube credentials: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	existing, err := appGetReadyServicePod(ctx, config, appName, serviceName)
+	if err != nil {
+		return err
+	}
+	if len(existing.Spec.Containers) == 0 {
+		return fmt.Errorf("at least one container must exist in pod %s", existing.Name)
+	}
+	container := existing.Spec.Containers[0]
+
+	if remoteDir == "" {
+		remoteDir = container.WorkingDir
+	}
+	if remoteDir == "" {
+		return fmt.Errorf("the working directory of service %s is unknown, please specify it with --remote-dir", serviceName)
+	}
+
+	// forward the ports of the service by default
+	if len(ports) == 0 {
+		for _, containerPort := range container.Ports {
+			ports = append(ports, strconv.Itoa(int(containerPort.ContainerPort)))
+		}
+	}
+
+	_, _ = color.New(color.FgGreen).Printf("Creating a dev pod for service %s using image: %s\n", serviceName, container.Image)
+
+	newPod := appEphemeralPodFromExisting(existing, container.Name, args[1:])
+	appDevAttachEnvGroups(newPod, container.Name, envGroups)
+
+	// dev pods stream their logs instead of being attached to
+	for i := range newPod.Spec.Containers {
+		if newPod.Spec.Containers[i].Name == container.Name {
+			newPod.Spec.Containers[i].TTY = false
+			newPod.Spec.Containers[i].Stdin = false
+			newPod.Spec.Containers[i].StdinOnce = false
+		}
+	}
+
+	devPod, err := config.Clientset.CoreV1().Pods(newPod.Namespace).Create(ctx, newPod, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("could not create dev pod: %w", err)
+	}
+
+	// delete the dev pod no matter what. The context may already be canceled at this point, so a new one is used
+	defer appDeletePod(context.Background(), config, devPod.Name, devPod.Namespace) //nolint:errcheck,gosec
+
+	err = appCheckForPodDeletionCronJob(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	_, _ = color.New(color.FgYellow).Printf("Waiting for pod %s to be ready...", devPod.Name)
+	if err = appWaitForPod(ctx, config, devPod); err != nil {
+		color.New(color.FgRed).Println("failed") // nolint:errcheck,gosec
+		return appHandlePodAttachError(ctx, err, config, devPod.Namespace, devPod.Name, container.Name)
+	}
+
+	devPod, err = config.Clientset.CoreV1().Pods(devPod.Namespace).Get(ctx, devPod.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not get dev pod: %w", err)
+	}
+	if appIsPodExited(devPod) {
+		color.New(color.FgRed).Println("exited") // nolint:errcheck,gosec
+		_, _ = appPipePodLogsToStdout(ctx, config, devPod.Namespace, devPod.Name, container.Name, false)
+		return fmt.Errorf("dev pod %s exited before it was ready", devPod.Name)
+	}
+	color.New(color.FgGreen).Println("ready!") // nolint:errcheck,gosec
+
+	snapshot, err := appDevSnapshot(localDir, ignorePatterns)
+	if err != nil {
+		return err
+	}
+
+	_, _ = color.New(color.FgGreen).Printf("Syncing %s to %s...\n", localDir, remoteDir)
+	err = appDevSyncFiles(ctx, config, devPod, container.Name, localDir, remoteDir, appDevSortedPaths(snapshot), nil)
+	if err != nil {
+		return err
+	}
+
+	errChan := make(chan error, 3)
+
+	go func() {
+		_, err := appPipePodLogsToStdout(ctx, config, devPod.Namespace, devPod.Name, container.Name, true)
+		if err == nil {
+			err = fmt.Errorf("dev pod %s exited", devPod.Name)
+		}
+		errChan <- err
+	}()
+
+	if len(ports) > 0 {
+		go func() {
+			_, _ = color.New(color.FgGreen).Printf("Forwarding ports %v to pod %s\n", ports, devPod.Name)
+			err := appForwardPortsToPod(ctx, config, devPod, ports)
+			if err == nil {
+				err = fmt.Errorf("lost connection to dev pod %s", devPod.Name)
+			}
+			errChan <- err
+		}()
+	}
+
+	go func() {
+		errChan <- appDevWatchAndSync(ctx, config, devPod, container.Name, localDir, remoteDir, ignorePatterns, snapshot)
+	}()
+
+	color.New(color.FgGreen).Println("Dev mode started, watching for changes...[CTRL-C to exit]") // nolint:errcheck,gosec
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errChan:
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+}
+
+// appDevEnvGroups returns the env groups attached to the current revision of an app
+func appDevEnvGroups(ctx context.Context, client api.Client, cliConfig config.CLIConfig, appName string) ([]*porterv1.EnvGroup, error) {
+	currentAppRevisionResp, err := client.CurrentAppRevision(ctx, api.CurrentAppRevisionInput{
+		ProjectID:            cliConfig.Project,
+		ClusterID:            cliConfig.Cluster,
+		AppName:              appName,
+		DeploymentTargetName: deploymentTargetName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting current app revision: %w", err)
+	}
+	if currentAppRevisionResp == nil {
+		return nil, errors.New("current app revision response is nil")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(currentAppRevisionResp.AppRevision.B64AppProto)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode base64 app for revision: %w", err)
+	}
+
+	app := &porterv1.PorterApp{}
+	err = helpers.UnmarshalContractObject(decoded, app)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal app for revision: %w", err)
+	}
+
+	return app.EnvGroups, nil
+}
+
+// appDevAttachEnvGroups loads the versioned configmap and secret of each env group into a container, unless they are already loaded
+func appDevAttachEnvGroups(pod *v1.Pod, container string, envGroups []*porterv1.EnvGroup) {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name != container {
+			continue
+		}
+
+		attached := make(map[string]bool)
+		for _, envFrom := range pod.Spec.Containers[i].EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				attached["configmap/"+envFrom.ConfigMapRef.Name] = true
+			}
+			if envFrom.SecretRef != nil {
+				attached["secret/"+envFrom.SecretRef.Name] = true
+			}
+		}
+
+		optional := true
+		for _, envGroup := range envGroups {
+			if envGroup == nil || envGroup.Name == "" {
+				continue
+			}
+			versionedName := fmt.Sprintf("%s.%d", envGroup.Name, envGroup.Version)
+
+			if !attached["configmap/"+versionedName] {
+				pod.Spec.Containers[i].EnvFrom = append(pod.Spec.Containers[i].EnvFrom, v1.EnvFromSource{
+					ConfigMapRef: &v1.ConfigMapEnvSource{
+						LocalObjectReference: v1.LocalObjectReference{Name: versionedName},
+						Optional:             &optional,
+					},
+				})
+			}
+			if !attached["secret/"+versionedName] {
+				pod.Spec.Containers[i].EnvFrom = append(pod.Spec.Containers[i].EnvFrom, v1.EnvFromSource{
+					SecretRef: &v1.SecretEnvSource{
+						LocalObjectReference: v1.LocalObjectReference{Name: versionedName},
+						Optional:             &optional,
+					},
+				})
+			}
+		}
+	}
+}
+
+// appDevWatchAndSync polls the local directory and syncs changed and removed files into the dev pod until the context is canceled
+func appDevWatchAndSync(
+	ctx context.Context,
+	config *KubernetesSharedConfig,
+	pod *v1.Pod,
+	container, localDir, remoteDir string,
+	ignorePatterns []string,
+	snapshot map[string]appDevFileState,
+) error {
+	ticker := time.NewTicker(appDevSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		newSnapshot, err := appDevSnapshot(localDir, ignorePatterns)
+		if err != nil {
+			return err
+		}
+
+		changed, removed := appDevDiffSnapshots(snapshot, newSnapshot)
+		if len(changed) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		err = appDevSyncFiles(ctx, config, pod, container, localDir, remoteDir, changed, removed)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// the files are synced again on the next change, so a failed sync does not stop dev mode
+			color.New(color.FgRed).Fprintf(os.Stderr, "Error syncing files: %s\n", err.Error()) // nolint:errcheck,gosec
+			continue
+		}
+
+		snapshot = newSnapshot
+	}
+}
+
+// appDevSnapshot returns the state of all regular files in a directory, keyed by their slash separated path relative to the directory.
+// Files and directories whose name matches an ignore pattern are skipped.
+func appDevSnapshot(dir string, ignorePatterns []string) (map[string]appDevFileState, error) {
+	snapshot := make(map[string]appDevFileState)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+
+		for _, pattern := range ignorePatterns {
+			if matched, _ := filepath.Match(pattern, d.Name()); matched {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		snapshot[filepath.ToSlash(rel)] = appDevFileState{
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+			Mode:    info.Mode(),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading local directory %s: %w", dir, err)
+	}
+
+	return snapshot, nil
+}
+
+// appDevDiffSnapshots returns the sorted paths of files which were added or changed, and of files which were removed, between two snapshots
+func appDevDiffSnapshots(old, new map[string]appDevFileState) ([]string, []string) {
+	var changed, removed []string
+
+	for p, state := range new {
+		if oldState, ok := old[p]; !ok || oldState != state {
+			changed = append(changed, p)
+		}
+	}
+	for p := range old {
+		if _, ok := new[p]; !ok {
+			removed = append(removed, p)
+		}
+	}
+
+	sort.Strings(changed)
+	sort.Strings(removed)
+
+	return changed, removed
+}
+
+func appDevSortedPaths(snapshot map[string]appDevFileState) []string {
+	paths := make([]string, 0, len(snapshot))
+	for p := range snapshot {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// appDevSyncFiles copies changed files into the dev pod as a tar archive, and deletes removed files from it.
+// This requires tar and rm to be available in the container, as with kubectl cp.
+func appDevSyncFiles(
+	ctx context.Context,
+	config *KubernetesSharedConfig,
+	pod *v1.Pod,
+	container, localDir, remoteDir string,
+	changed, removed []string,
+) error {
+	if len(changed) > 0 {
+		pr, pw := io.Pipe()
+
+		go func() {
+			pw.CloseWithError(appDevWriteTar(pw, localDir, changed)) // nolint:errcheck,gosec
+		}()
+
+		err := appDevExec(ctx, config, pod, container, []string{"tar", "-xmf", "-", "-C", remoteDir}, pr)
+		pr.Close() // nolint:errcheck,gosec
+		if err != nil {
+			return fmt.Errorf("error copying files to pod %s: %w", pod.Name, err)
+		}
+	}
+
+	if len(removed) > 0 {
+		command := []string{"rm", "-f", "--"}
+		for _, p := range removed {
+			command = append(command, path.Join(remoteDir, p))
+		}
+
+		err := appDevExec(ctx, config, pod, container, command, nil)
+		if err != nil {
+			return fmt.Errorf("error removing files from pod %s: %w", pod.Name, err)
+		}
+	}
+
+	_, _ = color.New(color.FgBlue).Printf("Synced %d changed and %d removed files\n", len(changed), len(removed))
+
+	return nil
+}
+
+// appDevWriteTar writes a tar archive of files in a local directory, given by their slash separated relative paths
+func appDevWriteTar(w io.Writer, localDir string, paths []string) error {
+	tw := tar.NewWriter(w)
+
+	for _, p := range paths {
+		err := func() error {
+			f, err := os.Open(filepath.Join(localDir, filepath.FromSlash(p)))
+			if err != nil {
+				return err
+			}
+			defer f.Close() // nolint:errcheck
+
+			info, err := f.Stat()
+			if err != nil {
+				return err
+			}
+
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = p
+
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+
+			_, err = io.CopyN(tw, f, header.Size)
+			return err
+		}()
+		// files may be removed between the snapshot and the sync, and are then removed on the next sync
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error adding %s to archive: %w", p, err)
+		}
+	}
+
+	return tw.Close()
+}
+
+// appDevExec runs a command in a container of the dev pod without a TTY
+func appDevExec(ctx context.Context, config *KubernetesSharedConfig, pod *v1.Pod, container string, command []string, stdin io.Reader) error {
+	req := config.RestClient.Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec")
+
+	for _, arg := range command {
+		req.Param("command", arg)
+	}
+	req.Param("stdin", strconv.FormatBool(stdin != nil))
+	req.Param("stdout", "true")
+	req.Param("stderr", "true")
+	req.Param("container", container)
+
+	exec, err := remotecommand.NewSPDYExecutor(config.RestConf, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: io.Discard,
+		Stderr: &stderr,
+	})
+	if err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%w: %s", err, stderr.String())
+	}
+
+	return err
+}
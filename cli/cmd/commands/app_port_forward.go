@@ -0,0 +1,318 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// appPortForwardReconnectInterval is how long to wait before looking for a new pod after a port-forward is interrupted
+const appPortForwardReconnectInterval = 2 * time.Second
+
+// appPortForwardTarget is a service of an app and the ports to forward to it
+type appPortForwardTarget struct {
+	ServiceName string
+	// Ports are in the [LOCAL_PORT:]REMOTE_PORT format expected by portforward.NewOnAddresses
+	Ports []string
+}
+
+func appPortForward(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConfig config.CLIConfig, _ config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	appName := args[0]
+	if appName == "" {
+		return fmt.Errorf("app name must be specified")
+	}
+
+	project, err := client.GetProject(ctx, cliConfig.Project)
+	if err != nil {
+		return fmt.Errorf("could not retrieve project from Porter API. Please contact support@porter.run")
+	}
+	if !project.ValidateApplyV2 {
+		return fmt.Errorf("port-forward is only supported for apps deployed with porter.yaml v2")
+	}
+
+	serviceFlags, err := cmd.Flags().GetStringArray("service")
+	if err != nil {
+		return fmt.Errorf("error getting service flag: %w", err)
+	}
+
+	targets, err := parseAppPortForwardTargets(serviceFlags, args[1:])
+	if err != nil {
+		return err
+	}
+
+	config := &KubernetesSharedConfig{
+		Client:    client,
+		CLIConfig: cliConfig,
+	}
+
+	err = config.setSharedConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("could not retrieve kube credentials: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	color.New(color.FgGreen).Printf("Starting port-forward for app %s...[CTRL-C to exit]\n", appName) // nolint:errcheck,gosec
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(targets))
+
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target appPortForwardTarget) {
+			defer wg.Done()
+
+			errs[i] = appPortForwardService(ctx, config, appName, target)
+			if errs[i] != nil {
+				// stop forwarding the other services so that the command exits with the error
+				cancel()
+			}
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// parseAppPortForwardTargets parses the --service flags of porter app port-forward. A flag is either a service name, which uses
+// the ports passed as arguments, or a service name followed by its own ports, e.g. worker=9090:9000,9091:9001
+func parseAppPortForwardTargets(serviceFlags []string, portArgs []string) ([]appPortForwardTarget, error) {
+	if len(serviceFlags) == 0 {
+		return nil, fmt.Errorf("at least one service must be specified with --service")
+	}
+
+	var targets []appPortForwardTarget
+	localPorts := make(map[string]string)
+
+	for _, serviceFlag := range serviceFlags {
+		serviceName, portList, hasPorts := strings.Cut(serviceFlag, "=")
+		if serviceName == "" {
+			return nil, fmt.Errorf("invalid service %q: service name must be specified", serviceFlag)
+		}
+
+		ports := portArgs
+		if hasPorts {
+			ports = strings.Split(portList, ",")
+		}
+		if len(ports) == 0 {
+			return nil, fmt.Errorf("no ports specified for service %s", serviceName)
+		}
+
+		for _, port := range ports {
+			localPort, err := parseAppPortForwardPort(port)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q for service %s: %w", port, serviceName, err)
+			}
+
+			if otherService, ok := localPorts[localPort]; ok {
+				return nil, fmt.Errorf("local port %s is used by both service %s and service %s", localPort, otherService, serviceName)
+			}
+			localPorts[localPort] = serviceName
+		}
+
+		targets = append(targets, appPortForwardTarget{
+			ServiceName: serviceName,
+			Ports:       ports,
+		})
+	}
+
+	return targets, nil
+}
+
+// parseAppPortForwardPort validates a port in the [LOCAL_PORT:]REMOTE_PORT format and returns its local port
+func parseAppPortForwardPort(port string) (string, error) {
+	localPort, remotePort, found := strings.Cut(port, ":")
+	if !found {
+		remotePort = localPort
+	}
+
+	for _, p := range []string{localPort, remotePort} {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 || n > 65535 {
+			return "", fmt.Errorf("port must be a number between 1 and 65535")
+		}
+	}
+
+	return localPort, nil
+}
+
+// appPortForwardService forwards ports to a ready pod of a service until the context is canceled. If the pod
+// goes away, for example because the service was redeployed or the pod restarted, the ports are forwarded to a new ready pod.
+func appPortForwardService(ctx context.Context, config *KubernetesSharedConfig, appName string, target appPortForwardTarget) error {
+	connected := false
+
+	for {
+		pod, err := appGetReadyServicePod(ctx, config, appName, target.ServiceName)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil && !connected {
+			return err
+		}
+		if err != nil {
+			color.New(color.FgYellow).Printf("Error finding a ready pod of service %s: %s\n", target.ServiceName, err.Error()) // nolint:errcheck,gosec
+			time.Sleep(appPortForwardReconnectInterval)
+			continue
+		}
+
+		color.New(color.FgGreen).Printf("Forwarding ports %s to pod %s of service %s\n", strings.Join(target.Ports, ", "), pod.Name, target.ServiceName) // nolint:errcheck,gosec
+
+		err = appForwardPortsToPod(ctx, config, pod, target.Ports)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// errors on the first connection are usually caused by the setup, such as a local port which is already in use,
+		// so they are returned. Once connected, errors are most likely caused by the pod going away and are retried
+		if err != nil && !connected {
+			return fmt.Errorf("error forwarding ports to service %s: %w", target.ServiceName, err)
+		}
+		connected = true
+
+		if err != nil {
+			color.New(color.FgYellow).Printf("Error forwarding ports to pod %s of service %s: %s\n", pod.Name, target.ServiceName, err.Error()) // nolint:errcheck,gosec
+		}
+		color.New(color.FgYellow).Printf("Lost connection to pod %s of service %s, reconnecting...\n", pod.Name, target.ServiceName) // nolint:errcheck,gosec
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(appPortForwardReconnectInterval):
+		}
+	}
+}
+
+// appGetReadyServicePod waits for a ready pod of a service in the app's deployment target
+func appGetReadyServicePod(ctx context.Context, config *KubernetesSharedConfig, appName string, serviceName string) (*v1.Pod, error) {
+	for {
+		resp, err := config.Client.PorterYamlV2Pods(ctx, config.CLIConfig.Project, config.CLIConfig.Cluster, appName, deploymentTargetName)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve list of pods: %w", err)
+		}
+		if resp == nil {
+			return nil, errors.New("get pods response is nil")
+		}
+
+		var servicePods []v1.Pod
+		for _, pod := range *resp {
+			if pod.Labels[porter_app.LabelKey_ServiceName] != serviceName {
+				continue
+			}
+			// copies of the service's pods created by porter app run and porter app dev should not receive traffic
+			if _, ok := pod.Labels["porter/ephemeral-pod"]; ok {
+				continue
+			}
+			servicePods = append(servicePods, pod)
+		}
+
+		if len(*resp) > 0 && len(servicePods) == 0 {
+			return nil, fmt.Errorf("service %s not found in app %s", serviceName, appName)
+		}
+
+		// prefer the newest pod, since older pods are the first to be replaced during a rollout
+		sort.Slice(servicePods, func(i, j int) bool {
+			return servicePods[i].CreationTimestamp.After(servicePods[j].CreationTimestamp.Time)
+		})
+
+		for i := range servicePods {
+			if servicePods[i].DeletionTimestamp == nil && appIsPodReady(&servicePods[i]) {
+				return &servicePods[i], nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(appPortForwardReconnectInterval):
+		}
+	}
+}
+
+// appForwardPortsToPod forwards ports to a pod until the context is canceled, the connection is lost, or the pod is no longer ready
+func appForwardPortsToPod(ctx context.Context, config *KubernetesSharedConfig, pod *v1.Pod, ports []string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stopChannel := make(chan struct{})
+	readyChannel := make(chan struct{})
+
+	go func() {
+		appWaitForPodNotReady(ctx, config, pod)
+		close(stopChannel)
+	}()
+
+	req := config.RestClient.Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+
+	return forwardPorts("POST", req.URL(), config.RestConf, ports, stopChannel, readyChannel)
+}
+
+// appWaitForPodNotReady returns once the context is canceled or the pod is deleted or no longer ready
+func appWaitForPodNotReady(ctx context.Context, config *KubernetesSharedConfig, pod *v1.Pod) {
+	selector := fields.OneTermEqualSelector("metadata.name", pod.Name).String()
+	resourceVersion := pod.ResourceVersion
+
+	for {
+		w, err := config.Clientset.CoreV1().
+			Pods(pod.Namespace).
+			Watch(ctx, metav1.ListOptions{FieldSelector: selector, ResourceVersion: resourceVersion})
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(appPortForwardReconnectInterval):
+				continue
+			}
+		}
+
+		for evt := range w.ResultChan() {
+			switch evt.Type {
+			case watch.Deleted:
+				w.Stop()
+				return
+			case watch.Error:
+				// the resource version is too old, so the next watch starts from the current state of the pod
+				resourceVersion = ""
+				continue
+			}
+
+			updatedPod, ok := evt.Object.(*v1.Pod)
+			if !ok {
+				continue
+			}
+			if updatedPod.DeletionTimestamp != nil || !appIsPodReady(updatedPod) {
+				w.Stop()
+				return
+			}
+			resourceVersion = updatedPod.ResourceVersion
+		}
+
+		// the watch closes when the context is canceled, and periodically on the server side
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/config"
+	"github.com/spf13/cobra"
+)
+
+func registerCommand_Audit(cliConf config.CLIConfig) *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Commands that read the audit log of a project",
+	}
+
+	listAuditCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the mutations made to the project through the Porter API",
+		Long: `Lists the mutations made to the project through the Porter API, newest first
+
+The following columns are returned:
+* TIME:        when the request was made
+* ACTOR:       the email of the user, or the name of the API token, which made the request
+* ACTION:      the kind of mutation, such as create, update or delete
+* RESOURCE:    the route of the request
+* STATUS:      the HTTP status code of the response
+* REQUEST-ID:  the id of the request, which can be passed to --request-id
+
+The --since and --until flags accept an RFC 3339 timestamp such as 2023-10-01T00:00:00Z, or a
+duration such as 24h which is relative to the current time.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkLoginAndRunWithConfig(cmd, cliConf, args, listAuditLogs)
+			if err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	listAuditCmd.Flags().Uint("cluster", 0, "Only list mutations in the cluster with this id")
+	listAuditCmd.Flags().String("actor", "", "Only list mutations made by the user or API token with this id, email or name")
+	listAuditCmd.Flags().String("action", "", "Only list mutations of this kind (create, update or delete)")
+	listAuditCmd.Flags().String("resource", "", "Only list mutations of routes containing this string, such as envgroup")
+	listAuditCmd.Flags().String("request-id", "", "Only list the mutation made by the request with this id")
+	listAuditCmd.Flags().String("since", "", "Only list mutations made after this time")
+	listAuditCmd.Flags().String("until", "", "Only list mutations made before this time")
+	listAuditCmd.Flags().Int64("page", 1, "Page of results to list")
+	auditCmd.AddCommand(listAuditCmd)
+
+	return auditCmd
+}
+
+func listAuditLogs(ctx context.Context, _ *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	req := &types.ListAuditLogsRequest{
+		PaginationRequest: &types.PaginationRequest{},
+	}
+
+	var err error
+
+	if req.ClusterID, err = cmd.Flags().GetUint("cluster"); err != nil {
+		return fmt.Errorf("error finding cluster flag: %w", err)
+	}
+	if req.ActorID, err = cmd.Flags().GetString("actor"); err != nil {
+		return fmt.Errorf("error finding actor flag: %w", err)
+	}
+	if req.Action, err = cmd.Flags().GetString("action"); err != nil {
+		return fmt.Errorf("error finding action flag: %w", err)
+	}
+	if req.Resource, err = cmd.Flags().GetString("resource"); err != nil {
+		return fmt.Errorf("error finding resource flag: %w", err)
+	}
+	if req.RequestID, err = cmd.Flags().GetString("request-id"); err != nil {
+		return fmt.Errorf("error finding request-id flag: %w", err)
+	}
+	if req.Page, err = cmd.Flags().GetInt64("page"); err != nil {
+		return fmt.Errorf("error finding page flag: %w", err)
+	}
+
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return fmt.Errorf("error finding since flag: %w", err)
+	}
+	if req.Since, err = parseAuditTime(since); err != nil {
+		return fmt.Errorf("invalid since flag: %w", err)
+	}
+
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return fmt.Errorf("error finding until flag: %w", err)
+	}
+	if req.Until, err = parseAuditTime(until); err != nil {
+		return fmt.Errorf("invalid until flag: %w", err)
+	}
+
+	resp, err := client.ListAuditLogs(ctx, cliConf.Project, req)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", "TIME", "ACTOR", "ACTION", "RESOURCE", "STATUS", "REQUEST-ID")
+	for _, auditLog := range resp.AuditLogs {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%d\t%s\n",
+			auditLog.CreatedAt.Local().Format(time.RFC3339),
+			auditLog.ActorEmail,
+			auditLog.Action,
+			auditLog.Resource,
+			auditLog.StatusCode,
+			auditLog.RequestID,
+		)
+	}
+
+	_ = w.Flush()
+
+	if resp.Pagination.NumPages > 1 {
+		fmt.Printf("\npage %d of %d\n", resp.Pagination.CurrentPage, resp.Pagination.NumPages)
+	}
+
+	return nil
+}
+
+// parseAuditTime converts a time flag, which is either an RFC 3339 timestamp or a duration before now, to an RFC 3339 timestamp
+func parseAuditTime(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d).UTC().Format(time.RFC3339), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("%s is neither an RFC 3339 timestamp nor a duration", value)
+	}
+
+	return t.UTC().Format(time.RFC3339), nil
+}
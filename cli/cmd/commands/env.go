@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -79,7 +82,8 @@ Optionally, specify a file to write the environment variables to. Otherwise the
 		Long: `Set environment variables for an app or environment group.
 
 Both variables and secrets can be specified as key-value pairs.
-When updating an environment group, all apps linked to the environment group will be re-deployed, unless the --skip-redeploys flag is used.`,
+When updating an environment group, all apps linked to the environment group will be re-deployed, unless the --skip-redeploys flag is used.
+If --auto-rollout is enabled for an environment group, linked apps are re-deployed one by one, and the rollout stops at the first app which fails to deploy.`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return checkLoginAndRunWithConfig(cmd, cliConf, args, setEnv)
@@ -88,6 +92,7 @@ When updating an environment group, all apps linked to the environment group wil
 	setCommand.Flags().StringToStringP("variables", "v", nil, "variables to set")
 	setCommand.Flags().StringToStringP("secrets", "s", nil, "secrets to set")
 	setCommand.Flags().Bool("skip-redeploys", false, "skip re-deploying apps linked to the environment group")
+	setCommand.Flags().Bool("auto-rollout", false, "re-deploy apps linked to the environment group one by one on this and future updates (environment groups only)")
 
 	unsetCommand := &cobra.Command{
 		Use:   "unset",
@@ -105,9 +110,48 @@ When updating an environment group, all apps linked to the environment group wil
 	unsetCommand.Flags().StringSliceP("secrets", "s", nil, "secrets to unset")
 	unsetCommand.Flags().Bool("skip-redeploys", false, "skip re-deploying apps linked to the environment group")
 
+	historyCommand := &cobra.Command{
+		Use:   "history",
+		Short: "List the versions of an environment group",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envHistory)
+		},
+	}
+
+	diffCommand := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the changes between two versions of an environment group",
+		Long: `Show the keys which were added, removed or changed between two versions of an environment group.
+
+Secret values are redacted. By default, the latest version is compared to the version before it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envDiff)
+		},
+	}
+	diffCommand.Flags().Int("from", 0, "the version to compare from (defaults to the version before --to)")
+	diffCommand.Flags().Int("to", 0, "the version to compare to (defaults to the latest version)")
+
+	rollbackCommand := &cobra.Command{
+		Use:   "rollback [version]",
+		Short: "Restore a previous version of an environment group",
+		Long: `Restore the variables of a previous version of an environment group as a new version.
+
+All apps linked to the environment group will be re-deployed, unless the --skip-redeploys flag is used.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkLoginAndRunWithConfig(cmd, cliConf, args, envRollback)
+		},
+	}
+	rollbackCommand.Flags().Bool("skip-redeploys", false, "skip re-deploying apps linked to the environment group")
+
 	envCmd.AddCommand(pullCommand)
 	envCmd.AddCommand(setCommand)
 	envCmd.AddCommand(unsetCommand)
+	envCmd.AddCommand(historyCommand)
+	envCmd.AddCommand(diffCommand)
+	envCmd.AddCommand(rollbackCommand)
 
 	return envCmd
 }
@@ -185,6 +229,19 @@ func setEnv(ctx context.Context, user *types.GetAuthenticatedUserResponse, clien
 		return fmt.Errorf("could not get skip-redeploys: %w", err)
 	}
 
+	var autoRollout *bool
+	if cmd.Flags().Changed("auto-rollout") {
+		if envGroupName == "" {
+			return fmt.Errorf("--auto-rollout can only be set for environment groups")
+		}
+
+		enabled, err := cmd.Flags().GetBool("auto-rollout")
+		if err != nil {
+			return fmt.Errorf("could not get auto-rollout: %w", err)
+		}
+		autoRollout = &enabled
+	}
+
 	envVars = envVariables{
 		Variables: variables,
 		Secrets:   secrets,
@@ -224,6 +281,7 @@ func setEnv(ctx context.Context, user *types.GetAuthenticatedUserResponse, clien
 			Variables:     envVars.Variables,
 			Secrets:       envVars.Secrets,
 			SkipRedeploys: skipRedeploys,
+			AutoRollout:   autoRollout,
 		})
 		if err != nil {
 			return fmt.Errorf("could not set env group env variables: %w", err)
@@ -370,3 +428,120 @@ func exportEnvVars(envVars envVariables) error {
 
 	return nil
 }
+
+func envHistory(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	if envGroupName == "" {
+		return fmt.Errorf("history is only available for environment groups, please specify --group")
+	}
+
+	historyResp, err := client.GetEnvGroupHistory(ctx, cliConf.Project, cliConf.Cluster, envGroupName)
+	if err != nil {
+		return fmt.Errorf("could not get env group history: %w", err)
+	}
+	if historyResp == nil {
+		return fmt.Errorf("could not get env group history: response was nil")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "VERSION", "CREATED AT", "VARIABLES", "SECRETS") // nolint:errcheck,gosec
+	for _, version := range historyResp.Versions {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\n", version.Version, version.CreatedAtUTC.Local().Format(time.RFC822), len(version.Variables), len(version.SecretVariables)) // nolint:errcheck,gosec
+	}
+
+	return w.Flush()
+}
+
+func envDiff(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	if envGroupName == "" {
+		return fmt.Errorf("diff is only available for environment groups, please specify --group")
+	}
+
+	fromVersion, err := cmd.Flags().GetInt("from")
+	if err != nil {
+		return fmt.Errorf("could not get from: %w", err)
+	}
+
+	toVersion, err := cmd.Flags().GetInt("to")
+	if err != nil {
+		return fmt.Errorf("could not get to: %w", err)
+	}
+
+	diffResp, err := client.GetEnvGroupDiff(ctx, cliConf.Project, cliConf.Cluster, envGroupName, fromVersion, toVersion)
+	if err != nil {
+		return fmt.Errorf("could not get env group diff: %w", err)
+	}
+	if diffResp == nil {
+		return fmt.Errorf("could not get env group diff: response was nil")
+	}
+
+	color.New(color.FgGreen).Printf("Comparing version %d to version %d of environment group %s:\n", diffResp.FromVersion, diffResp.ToVersion, envGroupName) // nolint:errcheck,gosec
+
+	if len(diffResp.Added) == 0 && len(diffResp.Removed) == 0 && len(diffResp.Changed) == 0 {
+		fmt.Println("No changes") // nolint:errcheck,gosec
+		return nil
+	}
+
+	for _, change := range diffResp.Added {
+		color.New(color.FgGreen).Printf("+ %s=%s\n", change.Key, change.NewValue) // nolint:errcheck,gosec
+	}
+	for _, change := range diffResp.Removed {
+		color.New(color.FgRed).Printf("- %s=%s\n", change.Key, change.OldValue) // nolint:errcheck,gosec
+	}
+	for _, change := range diffResp.Changed {
+		color.New(color.FgYellow).Printf("~ %s: %s -> %s\n", change.Key, change.OldValue, change.NewValue) // nolint:errcheck,gosec
+	}
+
+	return nil
+}
+
+func envRollback(ctx context.Context, user *types.GetAuthenticatedUserResponse, client api.Client, cliConf config.CLIConfig, featureFlags config.FeatureFlags, cmd *cobra.Command, args []string) error {
+	if envGroupName == "" {
+		return fmt.Errorf("rollback is only available for environment groups, please specify --group")
+	}
+
+	version, err := strconv.Atoi(args[0])
+	if err != nil || version <= 0 {
+		return fmt.Errorf("invalid version %s: must be a positive integer", args[0])
+	}
+
+	skipRedeploys, err := cmd.Flags().GetBool("skip-redeploys")
+	if err != nil {
+		return fmt.Errorf("could not get skip-redeploys: %w", err)
+	}
+
+	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	s.Color("cyan") // nolint:errcheck,gosec
+	s.Suffix = fmt.Sprintf(" Rolling back environment group %s to version %d...", envGroupName, version)
+
+	s.Start()
+	rollbackResp, err := client.RollbackEnvGroup(ctx, api.RollbackEnvGroupInput{
+		ProjectID:     cliConf.Project,
+		ClusterID:     cliConf.Cluster,
+		EnvGroupName:  envGroupName,
+		Version:       version,
+		SkipRedeploys: skipRedeploys,
+	})
+	s.Stop()
+	if err != nil {
+		return fmt.Errorf("could not roll back env group: %w", err)
+	}
+	if rollbackResp == nil {
+		return fmt.Errorf("could not roll back env group: response was nil")
+	}
+
+	color.New(color.FgGreen).Printf("Restored version %d of environment group %s as version %d\n", version, envGroupName, rollbackResp.Version.Version) // nolint:errcheck,gosec
+
+	if len(rollbackResp.LinkedApplications) == 0 {
+		return nil
+	}
+
+	if skipRedeploys {
+		color.New(color.FgYellow).Printf("The following apps must be redeployed to use the restored variables: %s\n", strings.Join(rollbackResp.LinkedApplications, ", ")) // nolint:errcheck,gosec
+		return nil
+	}
+
+	color.New(color.FgGreen).Printf("Redeploying linked apps: %s\n", strings.Join(rollbackResp.LinkedApplications, ", ")) // nolint:errcheck,gosec
+
+	return nil
+}
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/cli/cmd/config"
+	"github.com/porter-dev/porter/internal/porter_app"
 )
 
 // AppLogsInput is the input for the AppLogs function
@@ -27,6 +30,20 @@ type AppLogsInput struct {
 	AppName string
 	// ServiceName is an optional service name filter
 	ServiceName string
+	// Since is an optional start time for the logs. Defaults to 24 hours ago
+	Since time.Time
+	// Until is an optional end time for the logs. Defaults to now, and is only used when not following the logs
+	Until time.Time
+	// Search is an optional search string which log lines must contain
+	Search string
+	// Revision is an optional revision number or revision id filter
+	Revision string
+	// PodName is an optional pod name filter
+	PodName string
+	// Output is the output format of the logs, either LogOutput_Text or LogOutput_JSON
+	Output string
+	// Follow streams new logs as they are written. Otherwise, the logs between Since and Until are printed
+	Follow bool
 }
 
 // LogLine represents a single line of log output
@@ -37,15 +54,43 @@ type LogLine struct {
 // ServiceName_AllServices is a special value for ServiceName that indicates all services should be included
 const ServiceName_AllServices = "all"
 
+const (
+	// LogOutput_Text prints only the log lines
+	LogOutput_Text = "text"
+	// LogOutput_JSON prints each log as a JSON object on its own line
+	LogOutput_JSON = "json"
+)
+
+// historicalLogsPageSize is the number of logs requested per page when not following the logs
+const historicalLogsPageSize = 1000
+
 // AppLogs gets logs for an app
 func AppLogs(ctx context.Context, inp AppLogsInput) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	if inp.Output == "" {
+		inp.Output = LogOutput_Text
+	}
+	if inp.Output != LogOutput_Text && inp.Output != LogOutput_JSON {
+		return fmt.Errorf("invalid output format %s: must be one of %s, %s", inp.Output, LogOutput_Text, LogOutput_JSON)
+	}
+
+	appRevisionID, err := logsAppRevisionID(ctx, inp)
+	if err != nil {
+		return err
+	}
+
+	if !inp.Follow {
+		return historicalAppLogs(ctx, inp, appRevisionID)
+	}
+
 	termChan := make(chan os.Signal, 1)
 	signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM)
 
-	color.New(color.FgGreen).Printf("Streaming logs for app %s...\n\n", inp.AppName) // nolint:errcheck,gosec
+	if inp.Output == LogOutput_Text {
+		color.New(color.FgGreen).Printf("Streaming logs for app %s...\n\n", inp.AppName) // nolint:errcheck,gosec
+	}
 
 	conn, err := inp.Client.AppLogsStream(ctx, api.AppLogsInput{
 		ProjectID:            inp.CLIConfig.Project,
@@ -53,6 +98,9 @@ func AppLogs(ctx context.Context, inp AppLogsInput) error {
 		AppName:              inp.AppName,
 		DeploymentTargetName: inp.DeploymentTargetName,
 		ServiceName:          inp.ServiceName,
+		StartRange:           inp.Since,
+		SearchParam:          inp.Search,
+		AppRevisionID:        appRevisionID,
 	})
 	if err != nil {
 		return fmt.Errorf("error connecting to app logs stream: %w", err)
@@ -87,16 +135,25 @@ func AppLogs(ctx context.Context, inp AppLogsInput) error {
 
 			lines := strings.Split(string(message), "\n")
 			for _, l := range lines {
-				var line LogLine
+				var line types.LogLine
 
 				err = json.Unmarshal([]byte(l), &line)
 				if err != nil {
-					// silently fail in case output is not properly formatted
-					continue
+					// fall back to the line alone if the metadata cannot be parsed
+					var rawLine LogLine
+					if err := json.Unmarshal([]byte(l), &rawLine); err != nil {
+						// silently fail in case output is not properly formatted
+						continue
+					}
+					line = types.LogLine{Line: rawLine.Line}
 				}
 
-				message = append([]byte(line.Line), '\n')
-				if _, err = os.Stdout.Write(message); err != nil {
+				// the stream cannot be filtered by pod, so the logs are filtered here
+				logs := porter_app.FilterStructuredLogs(porter_app.AgentLogToStructuredLog([]types.LogLine{line}), porter_app.StructuredLogFilter{
+					PodName: inp.PodName,
+				})
+
+				if err = printLogs(logs, inp.Output); err != nil {
 					return nil
 				}
 			}
@@ -104,3 +161,142 @@ func AppLogs(ctx context.Context, inp AppLogsInput) error {
 		}
 	}
 }
+
+// historicalAppLogs prints the logs between inp.Since and inp.Until, oldest first, paging through them
+func historicalAppLogs(ctx context.Context, inp AppLogsInput, appRevisionID string) error {
+	now := time.Now().UTC()
+
+	startRange := inp.Since
+	if startRange.IsZero() {
+		startRange = now.Add(-24 * time.Hour)
+	}
+
+	endRange := inp.Until
+	if endRange.IsZero() {
+		endRange = now
+	}
+
+	if !startRange.Before(endRange) {
+		return fmt.Errorf("start time %s must be before end time %s", startRange.Format(time.RFC3339), endRange.Format(time.RFC3339))
+	}
+
+	for {
+		resp, err := inp.Client.AppLogs(ctx, api.AppLogsInput{
+			ProjectID:            inp.CLIConfig.Project,
+			ClusterID:            inp.CLIConfig.Cluster,
+			AppName:              inp.AppName,
+			DeploymentTargetName: inp.DeploymentTargetName,
+			ServiceName:          inp.ServiceName,
+			StartRange:           startRange,
+			EndRange:             endRange,
+			SearchParam:          inp.Search,
+			AppRevisionID:        appRevisionID,
+			PodName:              inp.PodName,
+			Direction:            "forward",
+			Limit:                historicalLogsPageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("error getting app logs: %w", err)
+		}
+		if resp == nil {
+			return fmt.Errorf("error getting app logs: response was nil")
+		}
+
+		if err := printLogs(resp.Logs, inp.Output); err != nil {
+			return nil
+		}
+
+		// logs filtered by pod may return an empty page even if there are more logs, so only the continue time is used to stop paging
+		if resp.ForwardContinueTime == nil || !resp.ForwardContinueTime.After(startRange) || !resp.ForwardContinueTime.Before(endRange) {
+			return nil
+		}
+		startRange = *resp.ForwardContinueTime
+	}
+}
+
+// logsAppRevisionID returns the id of the revision to filter logs by. inp.Revision is treated as a revision number if it is an integer, and as an id otherwise
+func logsAppRevisionID(ctx context.Context, inp AppLogsInput) (string, error) {
+	if inp.Revision == "" {
+		return "", nil
+	}
+
+	revisionNumber, err := strconv.ParseUint(inp.Revision, 10, 64)
+	if err != nil {
+		return inp.Revision, nil
+	}
+
+	currentAppRevisionResp, err := inp.Client.CurrentAppRevision(ctx, api.CurrentAppRevisionInput{
+		ProjectID:            inp.CLIConfig.Project,
+		ClusterID:            inp.CLIConfig.Cluster,
+		AppName:              inp.AppName,
+		DeploymentTargetName: inp.DeploymentTargetName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error getting current app revision: %w", err)
+	}
+	if currentAppRevisionResp == nil {
+		return "", fmt.Errorf("error getting current app revision: response was nil")
+	}
+
+	revisionsResp, err := inp.Client.ListAppRevisions(ctx, inp.CLIConfig.Project, inp.CLIConfig.Cluster, inp.AppName, currentAppRevisionResp.AppRevision.DeploymentTarget.ID)
+	if err != nil {
+		return "", fmt.Errorf("error listing app revisions: %w", err)
+	}
+	if revisionsResp == nil {
+		return "", fmt.Errorf("error listing app revisions: response was nil")
+	}
+
+	for _, revision := range revisionsResp.AppRevisions {
+		if revision.RevisionNumber == revisionNumber {
+			return revision.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("revision %d not found for app %s", revisionNumber, inp.AppName)
+}
+
+// ParseLogTime parses the value of a log time flag, which is either an RFC3339 timestamp or a duration relative to now, such as 1h30m
+func ParseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		if duration < 0 {
+			return time.Time{}, fmt.Errorf("invalid time %s: duration must be positive", value)
+		}
+		return now.Add(-duration).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s: must be a duration such as 1h30m or an RFC3339 timestamp such as 2006-01-02T15:04:05Z", value)
+	}
+
+	return t.UTC(), nil
+}
+
+// printLogs writes logs to stdout in the given output format
+func printLogs(logs []porter_app.StructuredLog, output string) error {
+	for _, log := range logs {
+		var message []byte
+
+		switch output {
+		case LogOutput_JSON:
+			var err error
+			message, err = json.Marshal(log)
+			if err != nil {
+				return fmt.Errorf("error marshaling log: %w", err)
+			}
+		default:
+			message = []byte(log.Line)
+		}
+
+		message = append(message, '\n')
+		if _, err := os.Stdout.Write(message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
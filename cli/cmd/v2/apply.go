@@ -156,6 +156,13 @@ func Apply(ctx context.Context, inp ApplyInput) error {
 		return errors.New("app revision id is empty")
 	}
 
+	for _, warning := range updateResp.PolicyWarnings {
+		color.New(color.FgYellow).Printf("Policy warning for %s\n", warning.String()) // nolint:errcheck,gosec
+		for _, message := range warning.Messages {
+			color.New(color.FgYellow).Printf("  %s\n", message) // nolint:errcheck,gosec
+		}
+	}
+
 	appName := updateResp.AppName
 
 	buildSettings, err := client.GetBuildFromRevision(ctx, api.GetBuildFromRevisionInput{
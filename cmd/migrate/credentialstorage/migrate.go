@@ -0,0 +1,314 @@
+// Package credentialstorage moves the credentials of integrations between credential storage backends
+package credentialstorage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	lr "github.com/porter-dev/porter/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// process 100 records at a time
+const stepSize = 100
+
+// Migrate copies the credentials of all integrations from one credential storage backend to another. A nil
+// backend is the database, where credentials are stored on the integrations themselves. Credentials are
+// copied as stored, so values encrypted by the repository stay encrypted.
+//
+// Credentials which already exist in the destination are not overwritten, so the migration can be re-run
+// after a failure. If finalize is set and the source is the database, the credentials of the migrated
+// integrations are cleared from the database. Credentials are never deleted from other backends.
+func Migrate(db *gorm.DB, from, to credentials.CredentialStorage, finalize bool, logger *lr.Logger) error {
+	if from == nil && to == nil {
+		return errors.New("the source and destination credential storage backends are both the database")
+	}
+
+	var failed int
+
+	for _, migrate := range []func() (migrationResult, error){
+		func() (migrationResult, error) { return migrateModel(db, from, to, finalize, oauthCredentials) },
+		func() (migrationResult, error) { return migrateModel(db, from, to, finalize, gcpCredentials) },
+		func() (migrationResult, error) { return migrateModel(db, from, to, finalize, awsCredentials) },
+		func() (migrationResult, error) { return migrateModel(db, from, to, finalize, azureCredentials) },
+		func() (migrationResult, error) { return migrateModel(db, from, to, finalize, gitlabCredentials) },
+	} {
+		res, err := migrate()
+		if err != nil {
+			return fmt.Errorf("error migrating %s credentials: %w", res.kind, err)
+		}
+
+		logger.Info().Msgf(
+			"migrated %d %s credentials, skipped %d, failed %d",
+			res.migrated, res.kind, res.skipped, len(res.errors),
+		)
+
+		for id, err := range res.errors {
+			logger.Error().Err(err).Msgf("error migrating credentials of %s integration %d", res.kind, id)
+		}
+
+		failed += len(res.errors)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("credentials of %d integrations could not be migrated", failed)
+	}
+
+	return nil
+}
+
+// credentialModel describes how the credentials of an integration model are stored in the database and in
+// a credential storage backend
+type credentialModel[M any, C any] struct {
+	kind string
+
+	// fields are the names of the fields of the model which hold credentials
+	fields []string
+
+	id           func(model *M) uint
+	fromModel    func(model *M) *C
+	toModel      func(model *M, data *C)
+	readBackend  func(backend credentials.CredentialStorage, model *M) (*C, error)
+	writeBackend func(backend credentials.CredentialStorage, model *M, data *C) error
+}
+
+type migrationResult struct {
+	kind     string
+	migrated int
+	skipped  int
+	errors   map[uint]error
+}
+
+func migrateModel[M any, C any](
+	db *gorm.DB,
+	from, to credentials.CredentialStorage,
+	finalize bool,
+	cm credentialModel[M, C],
+) (migrationResult, error) {
+	res := migrationResult{
+		kind:   cm.kind,
+		errors: make(map[uint]error),
+	}
+
+	var lastID uint
+
+	for {
+		models := []*M{}
+
+		if err := db.Where("id > ?", lastID).Order("id asc").Limit(stepSize).Find(&models).Error; err != nil {
+			return res, err
+		}
+
+		if len(models) == 0 {
+			return res, nil
+		}
+
+		for _, model := range models {
+			lastID = cm.id(model)
+
+			migrated, err := migrateCredential(db, from, to, cm, model)
+			if err != nil {
+				res.errors[lastID] = err
+				continue
+			}
+
+			if migrated {
+				res.migrated++
+			} else {
+				res.skipped++
+			}
+
+			if finalize && from == nil {
+				cm.toModel(model, new(C))
+
+				if err := db.Model(model).Select(cm.fields).UpdateColumns(model).Error; err != nil {
+					res.errors[lastID] = fmt.Errorf("error clearing credentials from the database: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// migrateCredential copies the credentials of a model, and returns false if there was nothing to copy
+func migrateCredential[M any, C any](
+	db *gorm.DB,
+	from, to credentials.CredentialStorage,
+	cm credentialModel[M, C],
+	model *M,
+) (bool, error) {
+	if to == nil {
+		if !isEmptyCredential(cm.fromModel(model)) {
+			return false, nil
+		}
+	} else if existing, err := cm.readBackend(to, model); err == nil && existing != nil {
+		return false, nil
+	}
+
+	var data *C
+
+	if from == nil {
+		data = cm.fromModel(model)
+
+		// integrations created while a backend was configured have no credentials in the database
+		if isEmptyCredential(data) {
+			return false, nil
+		}
+	} else {
+		var err error
+
+		data, err = cm.readBackend(from, model)
+		if err != nil {
+			return false, fmt.Errorf("error reading credentials: %w", err)
+		}
+	}
+
+	if to == nil {
+		cm.toModel(model, data)
+
+		if err := db.Model(model).Select(cm.fields).UpdateColumns(model).Error; err != nil {
+			return false, fmt.Errorf("error writing credentials to the database: %w", err)
+		}
+
+		return true, nil
+	}
+
+	if err := cm.writeBackend(to, model, data); err != nil {
+		return false, fmt.Errorf("error writing credentials: %w", err)
+	}
+
+	return true, nil
+}
+
+// isEmptyCredential returns true if none of the byte fields of a credential are set
+func isEmptyCredential(data interface{}) bool {
+	v := reflect.Indirect(reflect.ValueOf(data))
+
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Field(i); field.Kind() == reflect.Slice && field.Len() > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+var oauthCredentials = credentialModel[ints.OAuthIntegration, credentials.OAuthCredential]{
+	kind:   "oauth",
+	fields: []string{"ClientID", "AccessToken", "RefreshToken"},
+	id:     func(m *ints.OAuthIntegration) uint { return m.ID },
+	fromModel: func(m *ints.OAuthIntegration) *credentials.OAuthCredential {
+		return &credentials.OAuthCredential{
+			ClientID:     m.ClientID,
+			AccessToken:  m.AccessToken,
+			RefreshToken: m.RefreshToken,
+		}
+	},
+	toModel: func(m *ints.OAuthIntegration, data *credentials.OAuthCredential) {
+		m.ClientID = data.ClientID
+		m.AccessToken = data.AccessToken
+		m.RefreshToken = data.RefreshToken
+	},
+	readBackend: func(b credentials.CredentialStorage, m *ints.OAuthIntegration) (*credentials.OAuthCredential, error) {
+		return b.GetOAuthCredential(m)
+	},
+	writeBackend: func(b credentials.CredentialStorage, m *ints.OAuthIntegration, data *credentials.OAuthCredential) error {
+		return b.WriteOAuthCredential(m, data)
+	},
+}
+
+var gcpCredentials = credentialModel[ints.GCPIntegration, credentials.GCPCredential]{
+	kind:   "gcp",
+	fields: []string{"GCPKeyData"},
+	id:     func(m *ints.GCPIntegration) uint { return m.ID },
+	fromModel: func(m *ints.GCPIntegration) *credentials.GCPCredential {
+		return &credentials.GCPCredential{
+			GCPKeyData: m.GCPKeyData,
+		}
+	},
+	toModel: func(m *ints.GCPIntegration, data *credentials.GCPCredential) {
+		m.GCPKeyData = data.GCPKeyData
+	},
+	readBackend: func(b credentials.CredentialStorage, m *ints.GCPIntegration) (*credentials.GCPCredential, error) {
+		return b.GetGCPCredential(m)
+	},
+	writeBackend: func(b credentials.CredentialStorage, m *ints.GCPIntegration, data *credentials.GCPCredential) error {
+		return b.WriteGCPCredential(m, data)
+	},
+}
+
+var awsCredentials = credentialModel[ints.AWSIntegration, credentials.AWSCredential]{
+	kind:   "aws",
+	fields: []string{"AWSClusterID", "AWSAccessKeyID", "AWSSecretAccessKey", "AWSSessionToken"},
+	id:     func(m *ints.AWSIntegration) uint { return m.ID },
+	fromModel: func(m *ints.AWSIntegration) *credentials.AWSCredential {
+		return &credentials.AWSCredential{
+			AWSClusterID:       m.AWSClusterID,
+			AWSAccessKeyID:     m.AWSAccessKeyID,
+			AWSSecretAccessKey: m.AWSSecretAccessKey,
+			AWSSessionToken:    m.AWSSessionToken,
+		}
+	},
+	toModel: func(m *ints.AWSIntegration, data *credentials.AWSCredential) {
+		m.AWSClusterID = data.AWSClusterID
+		m.AWSAccessKeyID = data.AWSAccessKeyID
+		m.AWSSecretAccessKey = data.AWSSecretAccessKey
+		m.AWSSessionToken = data.AWSSessionToken
+	},
+	readBackend: func(b credentials.CredentialStorage, m *ints.AWSIntegration) (*credentials.AWSCredential, error) {
+		return b.GetAWSCredential(m)
+	},
+	writeBackend: func(b credentials.CredentialStorage, m *ints.AWSIntegration, data *credentials.AWSCredential) error {
+		return b.WriteAWSCredential(m, data)
+	},
+}
+
+var azureCredentials = credentialModel[ints.AzureIntegration, credentials.AzureCredential]{
+	kind:   "azure",
+	fields: []string{"ServicePrincipalSecret", "ACRPassword1", "ACRPassword2", "AKSPassword"},
+	id:     func(m *ints.AzureIntegration) uint { return m.ID },
+	fromModel: func(m *ints.AzureIntegration) *credentials.AzureCredential {
+		return &credentials.AzureCredential{
+			ServicePrincipalSecret: m.ServicePrincipalSecret,
+			ACRPassword1:           m.ACRPassword1,
+			ACRPassword2:           m.ACRPassword2,
+			AKSPassword:            m.AKSPassword,
+		}
+	},
+	toModel: func(m *ints.AzureIntegration, data *credentials.AzureCredential) {
+		m.ServicePrincipalSecret = data.ServicePrincipalSecret
+		m.ACRPassword1 = data.ACRPassword1
+		m.ACRPassword2 = data.ACRPassword2
+		m.AKSPassword = data.AKSPassword
+	},
+	readBackend: func(b credentials.CredentialStorage, m *ints.AzureIntegration) (*credentials.AzureCredential, error) {
+		return b.GetAzureCredential(m)
+	},
+	writeBackend: func(b credentials.CredentialStorage, m *ints.AzureIntegration, data *credentials.AzureCredential) error {
+		return b.WriteAzureCredential(m, data)
+	},
+}
+
+var gitlabCredentials = credentialModel[ints.GitlabIntegration, credentials.GitlabCredential]{
+	kind:   "gitlab",
+	fields: []string{"AppClientID", "AppClientSecret"},
+	id:     func(m *ints.GitlabIntegration) uint { return m.ID },
+	fromModel: func(m *ints.GitlabIntegration) *credentials.GitlabCredential {
+		return &credentials.GitlabCredential{
+			AppClientID:     m.AppClientID,
+			AppClientSecret: m.AppClientSecret,
+		}
+	},
+	toModel: func(m *ints.GitlabIntegration, data *credentials.GitlabCredential) {
+		m.AppClientID = data.AppClientID
+		m.AppClientSecret = data.AppClientSecret
+	},
+	readBackend: func(b credentials.CredentialStorage, m *ints.GitlabIntegration) (*credentials.GitlabCredential, error) {
+		return b.GetGitlabCredential(m)
+	},
+	writeBackend: func(b credentials.CredentialStorage, m *ints.GitlabIntegration, data *credentials.GitlabCredential) error {
+		return b.WriteGitlabCredential(m, data)
+	},
+}
@@ -0,0 +1,141 @@
+package credentialstorage_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/cmd/migrate/credentialstorage"
+	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
+	"github.com/porter-dev/porter/internal/repository/gorm"
+	lr "github.com/porter-dev/porter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateDatabaseToSecretStore(t *testing.T) {
+	assert := assert.New(t)
+
+	db, err := adapter.New(&env.DBConf{
+		SQLLite:     true,
+		SQLLitePath: filepath.Join(t.TempDir(), "porter.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.AutoMigrate(
+		&models.Project{},
+		&ints.OAuthIntegration{},
+		&ints.GCPIntegration{},
+		&ints.AWSIntegration{},
+		&ints.AzureIntegration{},
+		&ints.GitlabIntegration{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := [32]byte{}
+	copy(key[:], "__random_strong_encryption_key__")
+
+	dbRepo := gorm.NewRepository(db, &key, nil)
+
+	project, err := dbRepo.Project().CreateProject(&models.Project{Name: "project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oauth, err := dbRepo.OAuthIntegration().CreateOAuthIntegration(&ints.OAuthIntegration{
+		SharedOAuthModel: ints.SharedOAuthModel{
+			AccessToken:  []byte("access"),
+			RefreshToken: []byte("refresh"),
+		},
+		ProjectID: project.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aws, err := dbRepo.AWSIntegration().CreateAWSIntegration(&ints.AWSIntegration{
+		ProjectID:          project.ID,
+		AWSAccessKeyID:     []byte("key-id"),
+		AWSSecretAccessKey: []byte("secret"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := secretstore.NewStorage(&memorySecretClient{secrets: make(map[string][]byte)})
+	logger := lr.NewConsole(false)
+
+	// move the credentials out of the database, and clear them from the database
+	assert.NoError(credentialstorage.Migrate(db, nil, store, true, logger))
+
+	storedOAuth := &ints.OAuthIntegration{}
+	assert.NoError(db.First(storedOAuth, oauth.ID).Error)
+	assert.Empty(storedOAuth.AccessToken, "credentials should be cleared from the database")
+
+	storedAWS := &ints.AWSIntegration{}
+	assert.NoError(db.First(storedAWS, aws.ID).Error)
+	assert.Empty(storedAWS.AWSSecretAccessKey, "credentials should be cleared from the database")
+
+	storeRepo := gorm.NewRepository(db, &key, store)
+
+	readOAuth, err := storeRepo.OAuthIntegration().ReadOAuthIntegration(project.ID, oauth.ID)
+	assert.NoError(err)
+	assert.Equal("access", string(readOAuth.AccessToken))
+	assert.Equal("refresh", string(readOAuth.RefreshToken))
+
+	readAWS, err := storeRepo.AWSIntegration().ReadAWSIntegration(project.ID, aws.ID)
+	assert.NoError(err)
+	assert.Equal("key-id", string(readAWS.AWSAccessKeyID))
+	assert.Equal("secret", string(readAWS.AWSSecretAccessKey))
+
+	// re-running the migration skips the credentials which were already moved
+	assert.NoError(credentialstorage.Migrate(db, nil, store, true, logger))
+
+	// move the credentials back into the database
+	assert.NoError(credentialstorage.Migrate(db, store, nil, false, logger))
+
+	readOAuth, err = dbRepo.OAuthIntegration().ReadOAuthIntegration(project.ID, oauth.ID)
+	assert.NoError(err)
+	assert.Equal("access", string(readOAuth.AccessToken))
+
+	readAWS, err = dbRepo.AWSIntegration().ReadAWSIntegration(project.ID, aws.ID)
+	assert.NoError(err)
+	assert.Equal("secret", string(readAWS.AWSSecretAccessKey))
+
+	assert.Error(credentialstorage.Migrate(db, nil, nil, false, logger))
+}
+
+// memorySecretClient stores secrets in memory
+type memorySecretClient struct {
+	mu      sync.Mutex
+	secrets map[string][]byte
+}
+
+func (c *memorySecretClient) PutSecret(ctx context.Context, path string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.secrets[path] = value
+
+	return nil
+}
+
+func (c *memorySecretClient) GetSecret(ctx context.Context, path string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.secrets[path]
+	if !ok {
+		return nil, secretstore.ErrSecretNotFound
+	}
+
+	return value, nil
+}
@@ -14,6 +14,10 @@ import (
 // process 100 records at a time
 const stepSize = 100
 
+// Rotate re-encrypts the encrypted models from the old key to the new key, while the servers are stopped.
+//
+// Deprecated: keys are rotated online by the "encryption-key-rotation" worker job, which can resume an
+// interrupted rotation and reads values encrypted under either key.
 func Rotate(db *_gorm.DB, oldKey, newKey *[32]byte) error {
 	oldKeyBytes := make([]byte, 32)
 	newKeyBytes := make([]byte, 32)
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 
 	"github.com/porter-dev/porter/api/server/shared/config/envloader"
+	"github.com/porter-dev/porter/cmd/migrate/credentialstorage"
 	"github.com/porter-dev/porter/cmd/migrate/keyrotate"
 	"github.com/porter-dev/porter/cmd/migrate/populate_source_config_display_name"
 	"github.com/porter-dev/porter/cmd/migrate/startup_migrations"
@@ -13,6 +15,7 @@ import (
 	adapter "github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/features"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	"github.com/porter-dev/porter/internal/repository/gorm"
 	lr "github.com/porter-dev/porter/pkg/logger"
 
@@ -155,6 +158,22 @@ func main() {
 	if err := InstanceMigrate(db, envConf.DBConf); err != nil {
 		logger.Fatal().Err(err).Msg("vault migration failed")
 	}
+
+	if shouldMigrate, from, to, finalize := shouldMigrateCredentialStorage(); shouldMigrate {
+		fromBackend, err := secretstore.NewBackend(context.Background(), envConf.DBConf, secretstore.BackendKind(from))
+		if err != nil {
+			logger.Fatal().Err(err).Msg("could not create source credential storage backend")
+		}
+
+		toBackend, err := secretstore.NewBackend(context.Background(), envConf.DBConf, secretstore.BackendKind(to))
+		if err != nil {
+			logger.Fatal().Err(err).Msg("could not create destination credential storage backend")
+		}
+
+		if err := credentialstorage.Migrate(db, fromBackend, toBackend, finalize, logger); err != nil {
+			logger.Fatal().Err(err).Msg("credential storage migration failed")
+		}
+	}
 }
 
 type RotateConf struct {
@@ -193,3 +212,29 @@ func shouldPopulateSourceConfigDisplayName() bool {
 
 	return c.PopulateSourceConfigDisplayName
 }
+
+type CredentialStorageMigrateConf struct {
+	// we add a dummy field to avoid empty struct issue with envdecode
+	DummyField string `env:"ASDF,default=asdf"`
+
+	// the credential storage backends to move credentials from and to, such as "database" or "awssecretsmanager"
+	CredentialStorageMigrateFrom string `env:"CREDENTIAL_STORAGE_MIGRATE_FROM"`
+	CredentialStorageMigrateTo   string `env:"CREDENTIAL_STORAGE_MIGRATE_TO"`
+
+	// if true, credentials migrated out of the database are cleared from the database
+	CredentialStorageMigrateFinalize bool `env:"CREDENTIAL_STORAGE_MIGRATE_FINALIZE"`
+}
+
+func shouldMigrateCredentialStorage() (bool, string, string, bool) {
+	var c CredentialStorageMigrateConf
+
+	if err := envdecode.StrictDecode(&c); err != nil {
+		log.Fatalf("Failed to decode credential storage migration conf: %s", err)
+		return false, "", "", false
+	}
+
+	return c.CredentialStorageMigrateFrom != "" && c.CredentialStorageMigrateTo != "",
+		c.CredentialStorageMigrateFrom,
+		c.CredentialStorageMigrateTo,
+		c.CredentialStorageMigrateFinalize
+}
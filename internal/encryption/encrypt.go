@@ -34,10 +34,39 @@ func GenerateRandomBytes(n int) (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
-// Encrypt encrypts data using 256-bit AES-GCM.  This both hides the content of
-// the data and provides a check that it hasn't been altered. Output takes the
-// form nonce|ciphertext|tag where '|' indicates concatenation.
+// Encrypt encrypts data using 256-bit AES-GCM. If a key provider is set, the data is encrypted with a new
+// data key which is wrapped by the provider and stored in an envelope with the output, and the static key
+// is not used. Otherwise the data is encrypted with the static key.
 func Encrypt(plaintext []byte, key *[32]byte) (ciphertext []byte, err error) {
+	if provider := getKeyProvider(); provider != nil {
+		return encryptEnvelope(provider, plaintext)
+	}
+
+	return EncryptWithKey(plaintext, key)
+}
+
+// Decrypt decrypts data which was encrypted by Encrypt. Envelopes are decrypted with the data key unwrapped
+// by the key provider, while other data is decrypted with the static key, or with the previous static key
+// while the static key is rotated.
+func Decrypt(ciphertext []byte, key *[32]byte) (plaintext []byte, err error) {
+	if isEnvelope(ciphertext) {
+		return decryptEnvelope(getKeyProvider(), ciphertext)
+	}
+
+	plaintext, err = DecryptWithKey(ciphertext, key)
+	if err != nil {
+		if previous := getPreviousKey(); previous != nil {
+			return DecryptWithKey(ciphertext, previous)
+		}
+	}
+
+	return plaintext, err
+}
+
+// EncryptWithKey encrypts data using 256-bit AES-GCM, regardless of the key provider.
+// This both hides the content of the data and provides a check that it hasn't been
+// altered. Output takes the form nonce|ciphertext|tag where '|' indicates concatenation.
+func EncryptWithKey(plaintext []byte, key *[32]byte) (ciphertext []byte, err error) {
 	block, err := aes.NewCipher(key[:])
 	if err != nil {
 		return nil, err
@@ -57,10 +86,10 @@ func Encrypt(plaintext []byte, key *[32]byte) (ciphertext []byte, err error) {
 	return gcm.Seal(nonce, nonce, plaintext, nil), nil
 }
 
-// Decrypt decrypts data using 256-bit AES-GCM.  This both hides the content of
-// the data and provides a check that it hasn't been altered. Expects input
-// form nonce|ciphertext|tag where '|' indicates concatenation.
-func Decrypt(ciphertext []byte, key *[32]byte) (plaintext []byte, err error) {
+// DecryptWithKey decrypts data using 256-bit AES-GCM, regardless of the key provider.
+// This both hides the content of the data and provides a check that it hasn't been
+// altered. Expects input form nonce|ciphertext|tag where '|' indicates concatenation.
+func DecryptWithKey(ciphertext []byte, key *[32]byte) (plaintext []byte, err error) {
 	block, err := aes.NewCipher(key[:])
 	if err != nil {
 		return nil, err
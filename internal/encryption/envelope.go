@@ -0,0 +1,238 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KeyProvider wraps the data keys of envelope-encrypted values with a key encryption key, such as a
+// key of a KMS. Key ids are versioned: when the current key is replaced, the provider keeps unwrapping
+// data keys which were wrapped by previous keys, so that values can be re-encrypted online.
+type KeyProvider interface {
+	// KeyID returns the versioned id of the key which wraps new data keys
+	KeyID() string
+
+	// WrapKey encrypts a data key with the key returned by KeyID
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data key which was wrapped by the key with the given id
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// envelopeMagic prefixes envelope-encrypted values. Values without the prefix were encrypted directly
+// with the static encryption key.
+var envelopeMagic = []byte{0x00, 'p', 'e', 'n', 'v', 0x01}
+
+// keyProviderTimeout bounds the calls made to the key provider, which is usually a remote KMS
+const keyProviderTimeout = 10 * time.Second
+
+// maxCachedDataKeys is the number of unwrapped data keys kept in memory, so that values which are read
+// often do not call the key provider each time
+const maxCachedDataKeys = 4096
+
+var (
+	keyProviderMu sync.RWMutex
+	keyProvider   KeyProvider
+	previousKey   *[32]byte
+
+	dataKeyCacheMu sync.Mutex
+	dataKeyCache   = make(map[string]*[32]byte)
+)
+
+// SetKeyProvider sets the provider used by Encrypt to wrap data keys. Values are encrypted with the static
+// key if the provider is nil. Values which were encrypted with the static key can always be decrypted.
+func SetKeyProvider(provider KeyProvider) {
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+
+	keyProvider = provider
+
+	dataKeyCacheMu.Lock()
+	dataKeyCache = make(map[string]*[32]byte)
+	dataKeyCacheMu.Unlock()
+}
+
+// SetPreviousKey sets the previous static encryption key. While the static key is rotated, values which
+// cannot be decrypted with the static key are decrypted with the previous key. No previous key is used if
+// the key is nil.
+func SetPreviousKey(key *[32]byte) {
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+
+	previousKey = key
+}
+
+func getPreviousKey() *[32]byte {
+	keyProviderMu.RLock()
+	defer keyProviderMu.RUnlock()
+
+	return previousKey
+}
+
+func getKeyProvider() KeyProvider {
+	keyProviderMu.RLock()
+	defer keyProviderMu.RUnlock()
+
+	return keyProvider
+}
+
+// CurrentKeyID returns the id of the key which wraps new data keys, or an empty string if values are
+// encrypted with the static key
+func CurrentKeyID() string {
+	if provider := getKeyProvider(); provider != nil {
+		return provider.KeyID()
+	}
+
+	return ""
+}
+
+// StaticKeyID returns an id of the static encryption key, which identifies the key without revealing it
+func StaticKeyID(key *[32]byte) string {
+	sum := sha256.Sum256(key[:])
+
+	return "static:" + hex.EncodeToString(sum[:8])
+}
+
+// EnvelopeKeyID returns the id of the key which wrapped the data key of an envelope-encrypted value. It
+// returns false if the value was encrypted with the static key.
+func EnvelopeKeyID(ciphertext []byte) (string, bool) {
+	keyID, _, _, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return "", false
+	}
+
+	return keyID, true
+}
+
+// NeedsReencryption returns true if the value was not encrypted under the current key, which is either
+// the current key of the key provider or the static key if no provider is set
+func NeedsReencryption(ciphertext []byte) bool {
+	if len(ciphertext) == 0 {
+		return false
+	}
+
+	keyID, _ := EnvelopeKeyID(ciphertext)
+
+	return keyID != CurrentKeyID()
+}
+
+func isEnvelope(ciphertext []byte) bool {
+	return bytes.HasPrefix(ciphertext, envelopeMagic)
+}
+
+// encryptEnvelope encrypts data with a new data key, and outputs the form
+// magic|len(key id)|key id|len(wrapped key)|wrapped key|nonce|ciphertext|tag
+func encryptEnvelope(provider KeyProvider, plaintext []byte) ([]byte, error) {
+	keyID := provider.KeyID()
+
+	if len(keyID) > 255 {
+		return nil, fmt.Errorf("key id %s is longer than 255 bytes", keyID)
+	}
+
+	dataKey := NewEncryptionKey()
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyProviderTimeout)
+	defer cancel()
+
+	wrappedKey, err := provider.WrapKey(ctx, dataKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data key with key %s: %w", keyID, err)
+	}
+
+	if len(wrappedKey) > 65535 {
+		return nil, fmt.Errorf("wrapped data key of key %s is longer than 65535 bytes", keyID)
+	}
+
+	sealed, err := EncryptWithKey(plaintext, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, 0, len(envelopeMagic)+1+len(keyID)+2+len(wrappedKey)+len(sealed))
+	res = append(res, envelopeMagic...)
+	res = append(res, byte(len(keyID)))
+	res = append(res, keyID...)
+	res = binary.BigEndian.AppendUint16(res, uint16(len(wrappedKey)))
+	res = append(res, wrappedKey...)
+	res = append(res, sealed...)
+
+	return res, nil
+}
+
+func decryptEnvelope(provider KeyProvider, ciphertext []byte) ([]byte, error) {
+	keyID, wrappedKey, sealed, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := keyID + "/" + string(wrappedKey)
+
+	dataKeyCacheMu.Lock()
+	dataKey, ok := dataKeyCache[cacheKey]
+	dataKeyCacheMu.Unlock()
+
+	if !ok {
+		if provider == nil {
+			return nil, fmt.Errorf("value was encrypted with key %s, but no key provider is set", keyID)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), keyProviderTimeout)
+		defer cancel()
+
+		unwrapped, err := provider.UnwrapKey(ctx, keyID, wrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("error unwrapping data key with key %s: %w", keyID, err)
+		}
+
+		if len(unwrapped) != 32 {
+			return nil, fmt.Errorf("data key unwrapped with key %s has invalid length %d", keyID, len(unwrapped))
+		}
+
+		dataKey = &[32]byte{}
+		copy(dataKey[:], unwrapped)
+
+		dataKeyCacheMu.Lock()
+		if len(dataKeyCache) >= maxCachedDataKeys {
+			dataKeyCache = make(map[string]*[32]byte)
+		}
+		dataKeyCache[cacheKey] = dataKey
+		dataKeyCacheMu.Unlock()
+	}
+
+	return DecryptWithKey(sealed, dataKey)
+}
+
+func parseEnvelope(ciphertext []byte) (keyID string, wrappedKey []byte, sealed []byte, err error) {
+	if !isEnvelope(ciphertext) {
+		return "", nil, nil, errors.New("value is not an envelope")
+	}
+
+	rest := ciphertext[len(envelopeMagic):]
+
+	if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+		return "", nil, nil, errors.New("malformed envelope key id")
+	}
+
+	keyID = string(rest[1 : 1+int(rest[0])])
+	rest = rest[1+int(rest[0]):]
+
+	if len(rest) < 2 {
+		return "", nil, nil, errors.New("malformed envelope data key")
+	}
+
+	wrappedLen := int(binary.BigEndian.Uint16(rest[:2]))
+	rest = rest[2:]
+
+	if len(rest) < wrappedLen {
+		return "", nil, nil, errors.New("malformed envelope data key")
+	}
+
+	return keyID, rest[:wrappedLen], rest[wrappedLen:], nil
+}
@@ -0,0 +1,80 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awskms "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// AWSKMSProvider wraps data keys with a symmetric AWS KMS key. The id of the key is its ARN or alias:
+// configuring a new key replaces the current version, and the data keys wrapped by previous keys are
+// unwrapped as long as the credentials of the server can decrypt with them.
+type AWSKMSProvider struct {
+	keyID  string
+	client kmsiface.KMSAPI
+}
+
+// NewAWSKMSProvider returns an AWSKMSProvider for the key with the given id, which uses the default
+// credentials chain of the AWS SDK
+func NewAWSKMSProvider(keyID, region string) (*AWSKMSProvider, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("the id of the AWS KMS key must be set")
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating AWS session: %w", err)
+	}
+
+	return NewAWSKMSProviderWithClient(keyID, awskms.New(sess)), nil
+}
+
+// NewAWSKMSProviderWithClient returns an AWSKMSProvider which calls KMS with the given client
+func NewAWSKMSProviderWithClient(keyID string, client kmsiface.KMSAPI) *AWSKMSProvider {
+	return &AWSKMSProvider{
+		keyID:  keyID,
+		client: client,
+	}
+}
+
+// KeyID returns the id of the current key
+func (p *AWSKMSProvider) KeyID() string {
+	return keyID(ProviderKind_AWSKMS, p.keyID)
+}
+
+// WrapKey encrypts a data key with the current key
+func (p *AWSKMSProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	res, err := p.client.EncryptWithContext(ctx, &awskms.EncryptInput{
+		KeyId:     aws.String(p.keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.CiphertextBlob, nil
+}
+
+// UnwrapKey decrypts a data key with the key in the given id
+func (p *AWSKMSProvider) UnwrapKey(ctx context.Context, id string, wrappedKey []byte) ([]byte, error) {
+	name, err := parseKeyID(ProviderKind_AWSKMS, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.client.DecryptWithContext(ctx, &awskms.DecryptInput{
+		KeyId:          aws.String(name),
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Plaintext, nil
+}
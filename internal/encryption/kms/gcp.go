@@ -0,0 +1,70 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// GCPKMSProvider wraps data keys with a symmetric GCP Cloud KMS key, named like
+// projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>. Versions of the key
+// which are rotated by Cloud KMS keep decrypting, while configuring a new key replaces the current
+// version of the provider.
+type GCPKMSProvider struct {
+	keyName string
+	service *cloudkms.Service
+}
+
+// NewGCPKMSProvider returns a GCPKMSProvider for the key with the given name, which uses the application
+// default credentials
+func NewGCPKMSProvider(ctx context.Context, keyName string) (*GCPKMSProvider, error) {
+	if keyName == "" {
+		return nil, fmt.Errorf("the name of the GCP KMS key must be set")
+	}
+
+	service, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCP KMS client: %w", err)
+	}
+
+	return &GCPKMSProvider{
+		keyName: keyName,
+		service: service,
+	}, nil
+}
+
+// KeyID returns the id of the current key
+func (p *GCPKMSProvider) KeyID() string {
+	return keyID(ProviderKind_GCPKMS, p.keyName)
+}
+
+// WrapKey encrypts a data key with the primary version of the current key
+func (p *GCPKMSProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	res, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Encrypt(p.keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Ciphertext)
+}
+
+// UnwrapKey decrypts a data key with the key in the given id
+func (p *GCPKMSProvider) UnwrapKey(ctx context.Context, id string, wrappedKey []byte) ([]byte, error) {
+	name, err := parseKeyID(ProviderKind_GCPKMS, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(name, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrappedKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Plaintext)
+}
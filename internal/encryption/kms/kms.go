@@ -0,0 +1,82 @@
+// Package kms contains the key providers which wrap the data keys of envelope-encrypted values
+package kms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/encryption"
+)
+
+// ProviderKind is the kind of a key provider, which also prefixes the ids of its keys
+type ProviderKind string
+
+const (
+	ProviderKind_Local        ProviderKind = "local"
+	ProviderKind_AWSKMS       ProviderKind = "awskms"
+	ProviderKind_GCPKMS       ProviderKind = "gcpkms"
+	ProviderKind_VaultTransit ProviderKind = "vault"
+)
+
+// NewKeyProvider returns the key provider configured by conf, or nil if values should be encrypted with
+// the static encryption key
+func NewKeyProvider(ctx context.Context, conf *env.DBConf) (encryption.KeyProvider, error) {
+	switch ProviderKind(conf.EncryptionKeyProvider) {
+	case "":
+		return nil, nil
+	case ProviderKind_Local:
+		return NewLocalFileProvider(conf.EncryptionLocalKeyFile)
+	case ProviderKind_AWSKMS:
+		return NewAWSKMSProvider(conf.EncryptionAWSKMSKeyID, conf.EncryptionAWSKMSRegion)
+	case ProviderKind_GCPKMS:
+		return NewGCPKMSProvider(ctx, conf.EncryptionGCPKMSKeyName)
+	case ProviderKind_VaultTransit:
+		return NewVaultTransitProvider(
+			conf.EncryptionVaultTransitAddr,
+			conf.EncryptionVaultTransitToken,
+			conf.EncryptionVaultTransitMount,
+			conf.EncryptionVaultTransitKeyName,
+		)
+	}
+
+	return nil, fmt.Errorf("unknown encryption key provider %s", conf.EncryptionKeyProvider)
+}
+
+// SetKeyProviderFromConf sets the key provider configured by conf as the provider of the encryption package,
+// along with the previous static encryption key if the static key is being rotated
+func SetKeyProviderFromConf(ctx context.Context, conf *env.DBConf) error {
+	provider, err := NewKeyProvider(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	encryption.SetKeyProvider(provider)
+
+	var previousKey *[32]byte
+	if conf.OldEncryptionKey != "" {
+		previousKey = &[32]byte{}
+		copy(previousKey[:], conf.OldEncryptionKey)
+	}
+
+	encryption.SetPreviousKey(previousKey)
+
+	return nil
+}
+
+func keyID(kind ProviderKind, name string) string {
+	return fmt.Sprintf("%s:%s", kind, name)
+}
+
+// parseKeyID returns the name of the key with the given id, and checks that the key is managed by a
+// provider of the given kind
+func parseKeyID(kind ProviderKind, id string) (string, error) {
+	prefix := string(kind) + ":"
+
+	if !strings.HasPrefix(id, prefix) || len(id) == len(prefix) {
+		return "", fmt.Errorf("key %s is not managed by the %s key provider", id, kind)
+	}
+
+	return strings.TrimPrefix(id, prefix), nil
+}
@@ -0,0 +1,191 @@
+package kms_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/encryption/kms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalFileProviderEnvelope(t *testing.T) {
+	assert := assert.New(t)
+
+	staticKey := encryption.NewEncryptionKey()
+
+	// values encrypted before a key provider is set use the static key
+	legacy, err := encryption.Encrypt([]byte("legacy"), staticKey)
+	assert.NoError(err)
+
+	_, isEnvelope := encryption.EnvelopeKeyID(legacy)
+	assert.False(isEnvelope, "value should be encrypted with the static key")
+
+	setLocalProvider(t, "1", "1")
+	defer encryption.SetKeyProvider(nil)
+
+	assert.True(encryption.NeedsReencryption(legacy), "value encrypted with the static key should be re-encrypted")
+
+	plaintext, err := encryption.Decrypt(legacy, staticKey)
+	assert.NoError(err)
+	assert.Equal("legacy", string(plaintext))
+
+	ciphertext, err := encryption.Encrypt([]byte("secret"), staticKey)
+	assert.NoError(err)
+
+	keyID, isEnvelope := encryption.EnvelopeKeyID(ciphertext)
+	assert.True(isEnvelope, "value should be an envelope")
+	assert.Equal("local:1", keyID)
+	assert.False(encryption.NeedsReencryption(ciphertext), "value encrypted with the current key should not be re-encrypted")
+
+	// the data key does not depend on the static key
+	plaintext, err = encryption.Decrypt(ciphertext, encryption.NewEncryptionKey())
+	assert.NoError(err)
+	assert.Equal("secret", string(plaintext))
+
+	// after the current version changes, values wrapped by the previous version can still be decrypted
+	setLocalProvider(t, "2", "1", "2")
+
+	assert.True(encryption.NeedsReencryption(ciphertext), "value encrypted with a previous key should be re-encrypted")
+
+	plaintext, err = encryption.Decrypt(ciphertext, staticKey)
+	assert.NoError(err)
+	assert.Equal("secret", string(plaintext))
+
+	// once the previous version is removed, values wrapped by it cannot be decrypted
+	setLocalProvider(t, "2", "2")
+
+	_, err = encryption.Decrypt(ciphertext, staticKey)
+	assert.Error(err)
+}
+
+func TestLocalFileProviderMissingCurrentKey(t *testing.T) {
+	path := writeLocalKeyFile(t, "2", "1")
+
+	_, err := kms.NewLocalFileProvider(path)
+	assert.Error(t, err)
+}
+
+func TestPreviousStaticKeyFromConf(t *testing.T) {
+	assert := assert.New(t)
+
+	oldKey := &[32]byte{}
+	copy(oldKey[:], "old-encryption-key")
+
+	newKey := &[32]byte{}
+	copy(newKey[:], "new-encryption-key")
+
+	legacy, err := encryption.EncryptWithKey([]byte("legacy"), oldKey)
+	assert.NoError(err)
+
+	// values encrypted under the previous key are readable while the static key is rotated
+	err = kms.SetKeyProviderFromConf(context.Background(), &env.DBConf{OldEncryptionKey: "old-encryption-key"})
+	assert.NoError(err)
+
+	plaintext, err := encryption.Decrypt(legacy, newKey)
+	assert.NoError(err)
+	assert.Equal("legacy", string(plaintext))
+
+	// once the previous key is removed, they are not
+	err = kms.SetKeyProviderFromConf(context.Background(), &env.DBConf{})
+	assert.NoError(err)
+
+	_, err = encryption.Decrypt(legacy, newKey)
+	assert.Error(err)
+}
+
+func TestVaultTransitProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	// the fake transit engine "encrypts" by prefixing the base64-encoded plaintext with the key name
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("token", r.Header.Get("X-Vault-Token"))
+
+		req := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+
+		data := map[string]string{}
+
+		switch {
+		case r.URL.Path == "/v1/transit/encrypt/porter":
+			data["ciphertext"] = "vault:v1:porter:" + req["plaintext"]
+		case r.URL.Path == "/v1/transit/decrypt/porter" && strings.HasPrefix(req["ciphertext"], "vault:v1:porter:"):
+			data["plaintext"] = strings.TrimPrefix(req["ciphertext"], "vault:v1:porter:")
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data}) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	provider, err := kms.NewVaultTransitProvider(server.URL, "token", "", "porter")
+	assert.NoError(err)
+	assert.Equal("vault:porter", provider.KeyID())
+
+	encryption.SetKeyProvider(provider)
+	defer encryption.SetKeyProvider(nil)
+
+	ciphertext, err := encryption.Encrypt([]byte("secret"), encryption.NewEncryptionKey())
+	assert.NoError(err)
+
+	plaintext, err := encryption.Decrypt(ciphertext, encryption.NewEncryptionKey())
+	assert.NoError(err)
+	assert.Equal("secret", string(plaintext))
+
+	// keys of other providers are rejected
+	_, err = provider.UnwrapKey(context.Background(), "local:1", []byte("wrapped"))
+	assert.Error(err)
+}
+
+func setLocalProvider(t *testing.T, current string, versions ...string) {
+	provider, err := kms.NewLocalFileProvider(writeLocalKeyFile(t, current, versions...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryption.SetKeyProvider(provider)
+}
+
+// localKeys holds the keys of every version used by the tests, so that each version keeps the same key
+// across key files
+var localKeys = map[string]string{}
+
+func writeLocalKeyFile(t *testing.T, current string, versions ...string) string {
+	keys := make(map[string]string)
+
+	for _, version := range versions {
+		if _, ok := localKeys[version]; !ok {
+			localKeys[version] = base64.StdEncoding.EncodeToString(encryption.NewEncryptionKey()[:])
+		}
+
+		keys[version] = localKeys[version]
+	}
+
+	fileBytes, err := json.Marshal(map[string]interface{}{
+		"current": current,
+		"keys":    keys,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	if err := os.WriteFile(path, fileBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
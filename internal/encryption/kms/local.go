@@ -0,0 +1,101 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/porter-dev/porter/internal/encryption"
+)
+
+// LocalFileProvider wraps data keys with 32-byte keys read from a local file. The file stores every
+// version of the key, so that the data keys wrapped by previous versions can still be unwrapped:
+//
+//	{
+//	  "current": "2",
+//	  "keys": {
+//	    "1": "<base64-encoded 32-byte key>",
+//	    "2": "<base64-encoded 32-byte key>"
+//	  }
+//	}
+type LocalFileProvider struct {
+	current string
+	keys    map[string]*[32]byte
+}
+
+type localKeyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// NewLocalFileProvider reads the keys of a LocalFileProvider from the file at path
+func NewLocalFileProvider(path string) (*LocalFileProvider, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the path of the local key file must be set")
+	}
+
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading local key file: %w", err)
+	}
+
+	keyFile := &localKeyFile{}
+
+	if err := json.Unmarshal(fileBytes, keyFile); err != nil {
+		return nil, fmt.Errorf("error parsing local key file: %w", err)
+	}
+
+	res := &LocalFileProvider{
+		current: keyFile.Current,
+		keys:    make(map[string]*[32]byte),
+	}
+
+	for version, encoded := range keyFile.Keys {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding version %s of local key: %w", version, err)
+		}
+
+		if len(decoded) != 32 {
+			return nil, fmt.Errorf("version %s of local key must be 32 bytes long", version)
+		}
+
+		key := &[32]byte{}
+		copy(key[:], decoded)
+
+		res.keys[version] = key
+	}
+
+	if _, ok := res.keys[res.current]; !ok {
+		return nil, fmt.Errorf("current version %s of local key not found in key file", res.current)
+	}
+
+	return res, nil
+}
+
+// KeyID returns the id of the current version of the key
+func (p *LocalFileProvider) KeyID() string {
+	return keyID(ProviderKind_Local, p.current)
+}
+
+// WrapKey encrypts a data key with the current version of the key
+func (p *LocalFileProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	return encryption.EncryptWithKey(dataKey, p.keys[p.current])
+}
+
+// UnwrapKey decrypts a data key with the version of the key in the given id
+func (p *LocalFileProvider) UnwrapKey(ctx context.Context, id string, wrappedKey []byte) ([]byte, error) {
+	version, err := parseKeyID(ProviderKind_Local, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := p.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("version %s of local key not found in key file", version)
+	}
+
+	return encryption.DecryptWithKey(wrappedKey, key)
+}
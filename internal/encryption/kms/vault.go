@@ -0,0 +1,137 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// VaultTransitProvider wraps data keys with a key of the Vault transit secrets engine. Vault versions the
+// key itself, so ciphertexts of rotated versions keep decrypting; configuring a new key name replaces the
+// current version of the provider.
+type VaultTransitProvider struct {
+	serverURL  string
+	token      string
+	mount      string
+	keyName    string
+	httpClient *http.Client
+}
+
+type vaultTransitRequest struct {
+	Plaintext  string `json:"plaintext,omitempty"`
+	Ciphertext string `json:"ciphertext,omitempty"`
+}
+
+type vaultTransitResponse struct {
+	Data struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+}
+
+// NewVaultTransitProvider returns a VaultTransitProvider for the key with the given name, in the transit
+// engine mounted at mount
+func NewVaultTransitProvider(serverURL, token, mount, keyName string) (*VaultTransitProvider, error) {
+	if serverURL == "" || token == "" || keyName == "" {
+		return nil, fmt.Errorf("the address, token and key name of the Vault transit engine must be set")
+	}
+
+	if mount == "" {
+		mount = "transit"
+	}
+
+	return &VaultTransitProvider{
+		serverURL: serverURL,
+		token:     token,
+		mount:     mount,
+		keyName:   keyName,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// KeyID returns the id of the current key
+func (p *VaultTransitProvider) KeyID() string {
+	return keyID(ProviderKind_VaultTransit, p.keyName)
+}
+
+// WrapKey encrypts a data key with the latest version of the current key
+func (p *VaultTransitProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	res := &vaultTransitResponse{}
+
+	err := p.postRequest(ctx, fmt.Sprintf("/v1/%s/encrypt/%s", p.mount, p.keyName), &vaultTransitRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(res.Data.Ciphertext), nil
+}
+
+// UnwrapKey decrypts a data key with the key in the given id
+func (p *VaultTransitProvider) UnwrapKey(ctx context.Context, id string, wrappedKey []byte) ([]byte, error) {
+	name, err := parseKeyID(ProviderKind_VaultTransit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &vaultTransitResponse{}
+
+	err = p.postRequest(ctx, fmt.Sprintf("/v1/%s/decrypt/%s", p.mount, name), &vaultTransitRequest{
+		Ciphertext: string(wrappedKey),
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Data.Plaintext)
+}
+
+func (p *VaultTransitProvider) postRequest(ctx context.Context, path string, data interface{}, dst interface{}) error {
+	reqURL, err := url.Parse(p.serverURL)
+	if err != nil {
+		return err
+	}
+
+	reqURL.Path = path
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set("X-Vault-Token", p.token)
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		resBytes, err := io.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("request failed with status code %d, but could not read body (%s)", res.StatusCode, err.Error())
+		}
+
+		return fmt.Errorf("request failed with status code %d: %s", res.StatusCode, string(resBytes))
+	}
+
+	return json.NewDecoder(res.Body).Decode(dst)
+}
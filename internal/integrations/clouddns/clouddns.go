@@ -0,0 +1,173 @@
+package clouddns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	clouddns "google.golang.org/api/dns/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+// TTL sets the TTL for Cloud DNS records
+const TTL = 300
+
+// Client is a struct wrapper around the Google Cloud DNS client, scoped to a single managed zone
+type Client struct {
+	projectID   string
+	managedZone string
+	runDomain   string
+
+	service *clouddns.Service
+}
+
+// NewClient creates a new Cloud DNS API client. If the credentials JSON is empty, the application
+// default credentials are used.
+func NewClient(ctx context.Context, credentialsJSON []byte, projectID, managedZone, runDomain string) (Client, error) {
+	var opts []option.ClientOption
+	if len(credentialsJSON) > 0 {
+		opts = append(opts, option.WithCredentialsJSON(credentialsJSON))
+	}
+
+	service, err := clouddns.NewService(ctx, opts...)
+	if err != nil {
+		return Client{}, fmt.Errorf("unable to create cloud dns service: %w", err)
+	}
+
+	return Client{
+		projectID:   projectID,
+		managedZone: managedZone,
+		runDomain:   runDomain,
+		service:     service,
+	}, nil
+}
+
+// CreateARecord creates or replaces an A record in the managed zone
+func (c Client) CreateARecord(record dns.Record) error {
+	return c.upsertRecord(record, record.Value)
+}
+
+// CreateCNAMERecord creates or replaces a CNAME record in the managed zone
+func (c Client) CreateCNAMERecord(record dns.Record) error {
+	return c.upsertRecord(record, canonicalize(record.Value))
+}
+
+// CreateTXTRecord creates or replaces a TXT record in the managed zone
+func (c Client) CreateTXTRecord(record dns.Record) error {
+	return c.upsertRecord(record, dns.QuoteTXT(record.Value))
+}
+
+// DeleteRecord deletes the resource record set with the name and type of the record
+func (c Client) DeleteRecord(record dns.Record) error {
+	_, err := c.service.ResourceRecordSets.Delete(
+		c.projectID,
+		c.managedZone,
+		canonicalize(c.hostname(record)),
+		record.Type.String(),
+	).Context(context.Background()).Do()
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete %s dns record: %w", record.Type, err)
+	}
+
+	return nil
+}
+
+// ListRecords lists the A, CNAME and TXT records in the managed zone
+func (c Client) ListRecords() ([]dns.Record, error) {
+	records := make([]dns.Record, 0)
+
+	err := c.service.ResourceRecordSets.List(c.projectID, c.managedZone).Pages(context.Background(), func(res *clouddns.ResourceRecordSetsListResponse) error {
+		for _, set := range res.Rrsets {
+			recordType, ok := dns.RecordTypeFromString(set.Type)
+			if !ok {
+				continue
+			}
+
+			name, ok := dns.RecordName(set.Name, c.runDomain)
+			if !ok {
+				continue
+			}
+
+			for _, value := range set.Rrdatas {
+				if recordType == dns.RecordType_TXT {
+					value = dns.UnquoteTXT(value)
+				} else {
+					value = strings.TrimSuffix(value, ".")
+				}
+
+				records = append(records, dns.Record{
+					Type:       recordType,
+					Name:       name,
+					RootDomain: c.runDomain,
+					Value:      value,
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dns records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (c Client) upsertRecord(record dns.Record, value string) error {
+	ctx := context.Background()
+
+	set := &clouddns.ResourceRecordSet{
+		Name:    canonicalize(c.hostname(record)),
+		Type:    record.Type.String(),
+		Ttl:     TTL,
+		Rrdatas: []string{value},
+	}
+
+	_, err := c.service.ResourceRecordSets.Get(c.projectID, c.managedZone, set.Name, set.Type).Context(ctx).Do()
+	if err != nil {
+		if !isNotFound(err) {
+			return fmt.Errorf("failed to look up %s dns record: %w", record.Type, err)
+		}
+
+		_, err = c.service.ResourceRecordSets.Create(c.projectID, c.managedZone, set).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to create %s dns record: %w", record.Type, err)
+		}
+
+		return nil
+	}
+
+	_, err = c.service.ResourceRecordSets.Patch(c.projectID, c.managedZone, set.Name, set.Type, set).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to update %s dns record: %w", record.Type, err)
+	}
+
+	return nil
+}
+
+// hostname returns the fully-qualified name of the record, defaulting to the run domain if the record has no root domain
+func (c Client) hostname(record dns.Record) string {
+	if record.RootDomain == "" {
+		record.RootDomain = c.runDomain
+	}
+
+	return record.Hostname()
+}
+
+func canonicalize(value string) string {
+	if strings.HasSuffix(value, ".") {
+		return value
+	}
+
+	return fmt.Sprintf("%s.", value)
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,170 @@
+package clouddns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	clouddns "google.golang.org/api/dns/v1"
+	"google.golang.org/api/option"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+const rrsetsPath = "/dns/v1/projects/project/managedZones/zone/rrsets"
+
+// fakeAPI is an in-memory implementation of the cloud dns resource record set endpoints, keyed by name and type
+type fakeAPI struct {
+	mu       sync.Mutex
+	rrsets   map[string]*clouddns.ResourceRecordSet
+	requests []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method)
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, rrsetsPath), "/")
+
+	var result interface{}
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		// the first page contains the first record set, and the second page the rest
+		res := &clouddns.ResourceRecordSetsListResponse{}
+		for _, k := range []string{"example.com./TXT", "app.example.com./A", "www.example.com./CNAME"} {
+			if set, ok := f.rrsets[k]; ok {
+				res.Rrsets = append(res.Rrsets, set)
+			}
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			res.Rrsets = res.Rrsets[:1]
+			res.NextPageToken = "next"
+		} else {
+			res.Rrsets = res.Rrsets[1:]
+		}
+		result = res
+	case r.Method == http.MethodPost && key == "":
+		set := &clouddns.ResourceRecordSet{}
+		if err := json.NewDecoder(r.Body).Decode(set); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.rrsets[set.Name+"/"+set.Type] = set
+		result = set
+	case f.rrsets[key] == nil:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`)) // nolint:errcheck,gosec
+		return
+	case r.Method == http.MethodGet:
+		result = f.rrsets[key]
+	case r.Method == http.MethodPatch:
+		set := &clouddns.ResourceRecordSet{}
+		if err := json.NewDecoder(r.Body).Decode(set); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.rrsets[key] = set
+		result = set
+	case r.Method == http.MethodDelete:
+		delete(f.rrsets, key)
+		result = map[string]string{}
+	}
+
+	json.NewEncoder(w).Encode(result) // nolint:errcheck,gosec
+}
+
+func newTestClient(t *testing.T, api *fakeAPI) Client {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	service, err := clouddns.NewService(context.Background(), option.WithEndpoint(server.URL+"/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return Client{projectID: "project", managedZone: "zone", runDomain: "example.com", service: service}
+}
+
+func TestUpsertRecord(t *testing.T) {
+	api := &fakeAPI{rrsets: map[string]*clouddns.ResourceRecordSet{}}
+	client := newTestClient(t, api)
+
+	if err := client.CreateCNAMERecord(dns.Record{Type: dns.RecordType_CNAME, Name: "www", Value: "lb.example.net"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	set := api.rrsets["www.example.com./CNAME"]
+	if set == nil || set.Ttl != TTL || len(set.Rrdatas) != 1 || set.Rrdatas[0] != "lb.example.net." {
+		t.Fatalf("expected cname record set to be created, got %+v", set)
+	}
+
+	if err := client.CreateCNAMERecord(dns.Record{Type: dns.RecordType_CNAME, Name: "www", Value: "lb2.example.net"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	set = api.rrsets["www.example.com./CNAME"]
+	if set == nil || set.Rrdatas[0] != "lb2.example.net." {
+		t.Fatalf("expected cname record set to be replaced, got %+v", set)
+	}
+
+	expected := []string{http.MethodGet, http.MethodPost, http.MethodGet, http.MethodPatch}
+	if strings.Join(api.requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected requests %v, got %v", expected, api.requests)
+	}
+}
+
+func TestDeleteRecord(t *testing.T) {
+	api := &fakeAPI{rrsets: map[string]*clouddns.ResourceRecordSet{
+		"app.example.com./A": {Name: "app.example.com.", Type: "A", Rrdatas: []string{"10.0.0.1"}},
+	}}
+	client := newTestClient(t, api)
+
+	if err := client.DeleteRecord(dns.Record{Type: dns.RecordType_A, Name: "app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.rrsets) != 0 {
+		t.Errorf("expected record set to be deleted, got %+v", api.rrsets)
+	}
+
+	// deleting a record which does not exist is not an error
+	if err := client.DeleteRecord(dns.Record{Type: dns.RecordType_A, Name: "app"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	api := &fakeAPI{rrsets: map[string]*clouddns.ResourceRecordSet{
+		"example.com./TXT":       {Name: "example.com.", Type: "TXT", Rrdatas: []string{`"porter" "-verify"`}},
+		"app.example.com./A":     {Name: "app.example.com.", Type: "A", Rrdatas: []string{"10.0.0.1", "10.0.0.2"}},
+		"www.example.com./CNAME": {Name: "www.example.com.", Type: "CNAME", Rrdatas: []string{"lb.example.net."}},
+	}}
+	client := newTestClient(t, api)
+
+	records, err := client.ListRecords()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []dns.Record{
+		{Type: dns.RecordType_TXT, Name: "@", RootDomain: "example.com", Value: "porter-verify"},
+		{Type: dns.RecordType_A, Name: "app", RootDomain: "example.com", Value: "10.0.0.1"},
+		{Type: dns.RecordType_A, Name: "app", RootDomain: "example.com", Value: "10.0.0.2"},
+		{Type: dns.RecordType_CNAME, Name: "www", RootDomain: "example.com", Value: "lb.example.net"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected records %+v, got %+v", expected, records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("expected record %+v, got %+v", expected[i], records[i])
+		}
+	}
+}
@@ -17,6 +17,9 @@ const (
 
 	// RecordType_CNAME declares an CNME record type for cloudflare
 	RecordType_CNAME = "CNAME"
+
+	// RecordType_TXT declares a TXT record type for cloudflare
+	RecordType_TXT = "TXT"
 )
 
 // TTL sets the TTL for Cloudflare DNS records
@@ -24,7 +27,8 @@ const TTL = 300
 
 // Client is a struct wrapper around the cloudflare client
 type Client struct {
-	zoneID string
+	zoneID    string
+	runDomain string
 
 	client *cloudflare.API
 }
@@ -41,7 +45,7 @@ func NewClient(apiToken string, runDomain string) (Client, error) {
 		return Client{}, err
 	}
 
-	return Client{client: client, zoneID: zoneID}, nil
+	return Client{client: client, zoneID: zoneID, runDomain: runDomain}, nil
 }
 
 // CreateCNAMERecord creates a new CNAME record for the nameserver
@@ -87,3 +91,77 @@ func (c Client) CreateARecord(record dns.Record) error {
 
 	return nil
 }
+
+// CreateTXTRecord creates a new TXT record for the nameserver
+//
+// The method ignores record.RootDomain in favor of the zoneID derived from c.runDomain
+func (c Client) CreateTXTRecord(record dns.Record) error {
+	cloudflareRecord := cloudflare.CreateDNSRecordParams{
+		Name:    record.Name,
+		Type:    string(RecordType_TXT),
+		Content: record.Value,
+		TTL:     TTL,
+	}
+
+	_, err := c.client.CreateDNSRecord(context.Background(), cloudflare.ZoneIdentifier(c.zoneID), cloudflareRecord)
+	if err != nil {
+		return fmt.Errorf("failed to create TXT dns record: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRecord deletes all records with the name and type of the record
+//
+// The method ignores record.RootDomain in favor of the zoneID derived from c.runDomain
+func (c Client) DeleteRecord(record dns.Record) error {
+	ctx := context.Background()
+	rc := cloudflare.ZoneIdentifier(c.zoneID)
+
+	existing, _, err := c.client.ListDNSRecords(ctx, rc, cloudflare.ListDNSRecordsParams{
+		Name: dns.Record{Name: record.Name, RootDomain: c.runDomain}.Hostname(),
+		Type: record.Type.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list %s dns records: %w", record.Type, err)
+	}
+
+	for _, existingRecord := range existing {
+		err = c.client.DeleteDNSRecord(ctx, rc, existingRecord.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s dns record: %w", record.Type, err)
+		}
+	}
+
+	return nil
+}
+
+// ListRecords lists the A, CNAME and TXT records in the zone of c.runDomain
+func (c Client) ListRecords() ([]dns.Record, error) {
+	cloudflareRecords, _, err := c.client.ListDNSRecords(context.Background(), cloudflare.ZoneIdentifier(c.zoneID), cloudflare.ListDNSRecordsParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dns records: %w", err)
+	}
+
+	records := make([]dns.Record, 0)
+	for _, cloudflareRecord := range cloudflareRecords {
+		recordType, ok := dns.RecordTypeFromString(cloudflareRecord.Type)
+		if !ok {
+			continue
+		}
+
+		name, ok := dns.RecordName(cloudflareRecord.Name, c.runDomain)
+		if !ok {
+			continue
+		}
+
+		records = append(records, dns.Record{
+			Type:       recordType,
+			Name:       name,
+			RootDomain: c.runDomain,
+			Value:      cloudflareRecord.Content,
+		})
+	}
+
+	return records, nil
+}
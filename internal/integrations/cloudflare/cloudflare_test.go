@@ -0,0 +1,162 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cloudflare/cloudflare-go"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+// fakeAPI is an in-memory implementation of the cloudflare zone and dns record endpoints used by the client
+type fakeAPI struct {
+	mu      sync.Mutex
+	records []cloudflare.DNSRecord
+	deleted []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result interface{}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/zones":
+		result = []cloudflare.Zone{{ID: "zone-id", Name: r.URL.Query().Get("name")}}
+	case r.Method == http.MethodGet && r.URL.Path == "/zones/zone-id/dns_records":
+		records := make([]cloudflare.DNSRecord, 0)
+		for _, record := range f.records {
+			if name := r.URL.Query().Get("name"); name != "" && record.Name != name {
+				continue
+			}
+			if recordType := r.URL.Query().Get("type"); recordType != "" && record.Type != recordType {
+				continue
+			}
+			records = append(records, record)
+		}
+		result = records
+	case r.Method == http.MethodPost && r.URL.Path == "/zones/zone-id/dns_records":
+		var record cloudflare.DNSRecord
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		record.ID = fmt.Sprintf("record-%d", len(f.records))
+		f.records = append(f.records, record)
+		result = record
+	case r.Method == http.MethodDelete:
+		var id string
+		if _, err := fmt.Sscanf(r.URL.Path, "/zones/zone-id/dns_records/%s", &id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		f.deleted = append(f.deleted, id)
+		result = map[string]string{"id": id}
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{ // nolint:errcheck,gosec
+		"success":     true,
+		"errors":      []interface{}{},
+		"messages":    []interface{}{},
+		"result":      result,
+		"result_info": map[string]int{"page": 1, "per_page": 100, "count": 1, "total_count": 1, "total_pages": 1},
+	})
+}
+
+func newTestClient(t *testing.T, api *fakeAPI) Client {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	cf, err := cloudflare.NewWithAPIToken("api-token", cloudflare.BaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	zoneID, err := cf.ZoneIDByName("example.com")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return Client{client: cf, zoneID: zoneID, runDomain: "example.com"}
+}
+
+func TestCreateRecords(t *testing.T) {
+	api := &fakeAPI{}
+	client := newTestClient(t, api)
+
+	if err := client.CreateARecord(dns.Record{Type: dns.RecordType_A, Name: "app", Value: "10.0.0.1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.CreateTXTRecord(dns.Record{Type: dns.RecordType_TXT, Name: "_porter", Value: "porter-verify"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(api.records) != 2 {
+		t.Fatalf("expected two records, got %+v", api.records)
+	}
+	if a := api.records[0]; a.Type != "A" || a.Name != "app" || a.Content != "10.0.0.1" || a.TTL != TTL || a.Proxied == nil || *a.Proxied {
+		t.Errorf("unexpected A record %+v", a)
+	}
+	if txt := api.records[1]; txt.Type != "TXT" || txt.Name != "_porter" || txt.Content != "porter-verify" {
+		t.Errorf("unexpected TXT record %+v", txt)
+	}
+}
+
+func TestDeleteRecord(t *testing.T) {
+	api := &fakeAPI{
+		records: []cloudflare.DNSRecord{
+			{ID: "a", Type: "CNAME", Name: "app.example.com", Content: "lb.example.net"},
+			{ID: "b", Type: "TXT", Name: "app.example.com", Content: "porter-verify"},
+			{ID: "c", Type: "CNAME", Name: "www.example.com", Content: "lb.example.net"},
+		},
+	}
+	client := newTestClient(t, api)
+
+	if err := client.DeleteRecord(dns.Record{Type: dns.RecordType_CNAME, Name: "app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(api.deleted) != 1 || api.deleted[0] != "a" {
+		t.Errorf("expected only record a to be deleted, got %v", api.deleted)
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	api := &fakeAPI{
+		records: []cloudflare.DNSRecord{
+			{ID: "a", Type: "A", Name: "app.example.com", Content: "10.0.0.1"},
+			{ID: "b", Type: "MX", Name: "example.com", Content: "mail.example.com"},
+			{ID: "c", Type: "TXT", Name: "example.com", Content: "porter-verify"},
+		},
+	}
+	client := newTestClient(t, api)
+
+	records, err := client.ListRecords()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []dns.Record{
+		{Type: dns.RecordType_A, Name: "app", RootDomain: "example.com", Value: "10.0.0.1"},
+		{Type: dns.RecordType_TXT, Name: "@", RootDomain: "example.com", Value: "porter-verify"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected records %+v, got %+v", expected, records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("expected record %+v, got %+v", expected[i], records[i])
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package dns
 
+import (
+	"fmt"
+	"strings"
+)
+
 // RecordType strongly types dns record types
 type RecordType int
 
@@ -9,13 +14,53 @@ const (
 
 	// RecordType_CNAME represents a DNS RecordType_CNAME record
 	RecordType_CNAME
+
+	// RecordType_TXT represents a DNS RecordType_TXT record
+	RecordType_TXT
 )
 
+// String returns the record type as written in a zone file, such as "CNAME"
+func (t RecordType) String() string {
+	switch t {
+	case RecordType_A:
+		return "A"
+	case RecordType_CNAME:
+		return "CNAME"
+	case RecordType_TXT:
+		return "TXT"
+	}
+
+	return fmt.Sprintf("RecordType(%d)", int(t))
+}
+
+// RecordTypeFromString parses a record type as written in a zone file. The second return value
+// is false if the record type is not managed by Porter.
+func RecordTypeFromString(recordType string) (RecordType, bool) {
+	switch strings.ToUpper(recordType) {
+	case "A":
+		return RecordType_A, true
+	case "CNAME":
+		return RecordType_CNAME, true
+	case "TXT":
+		return RecordType_TXT, true
+	}
+
+	return 0, false
+}
+
 // WrappedClient is an interface describing a wrapper
 // around a particular dns implementation
 type WrappedClient interface {
 	CreateARecord(record Record) error
 	CreateCNAMERecord(record Record) error
+	CreateTXTRecord(record Record) error
+
+	// DeleteRecord deletes all values of the record with the given name and type. Deleting a record which
+	// does not exist is not an error.
+	DeleteRecord(record Record) error
+
+	// ListRecords lists the A, CNAME and TXT records in the zone of the root domain
+	ListRecords() ([]Record, error)
 }
 
 // Client wraps the underlying powerdns client
@@ -33,11 +78,100 @@ type Record struct {
 	Value      string
 }
 
+// Hostname returns the fully-qualified name of the record, without a trailing period
+func (r Record) Hostname() string {
+	if r.Name == "" || r.Name == "@" {
+		return strings.TrimSuffix(r.RootDomain, ".")
+	}
+
+	return fmt.Sprintf("%s.%s", r.Name, strings.TrimSuffix(r.RootDomain, "."))
+}
+
 // CreateRecord creates a new dns record
 func (c Client) CreateRecord(record Record) error {
-	if record.Type == RecordType_A {
+	switch record.Type {
+	case RecordType_A:
 		return c.Client.CreateARecord(record)
+	case RecordType_TXT:
+		return c.Client.CreateTXTRecord(record)
 	}
 
 	return c.Client.CreateCNAMERecord(record)
 }
+
+// DeleteRecord deletes a dns record
+func (c Client) DeleteRecord(record Record) error {
+	return c.Client.DeleteRecord(record)
+}
+
+// ListRecords lists the dns records managed by the client
+func (c Client) ListRecords() ([]Record, error) {
+	return c.Client.ListRecords()
+}
+
+// RecordName returns the name of a fully-qualified hostname relative to the root domain. The second
+// return value is false if the hostname is not in the root domain.
+func RecordName(hostname, rootDomain string) (string, bool) {
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	rootDomain = strings.ToLower(strings.TrimSuffix(rootDomain, "."))
+
+	if hostname == rootDomain {
+		return "@", true
+	}
+
+	name := strings.TrimSuffix(hostname, "."+rootDomain)
+	if name == hostname {
+		return "", false
+	}
+
+	return name, true
+}
+
+// QuoteTXT quotes a TXT record value, splitting it into strings of at most 255 characters as
+// required by the DNS wire format
+func QuoteTXT(value string) string {
+	var chunks []string
+	for len(value) > 255 {
+		chunks = append(chunks, value[:255])
+		value = value[255:]
+	}
+	chunks = append(chunks, value)
+
+	quoted := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		chunk = strings.ReplaceAll(chunk, `\`, `\\`)
+		chunk = strings.ReplaceAll(chunk, `"`, `\"`)
+		quoted = append(quoted, fmt.Sprintf(`"%s"`, chunk))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// UnquoteTXT reverses QuoteTXT, joining the quoted strings of a TXT record value. Values which are not
+// quoted are returned unchanged.
+func UnquoteTXT(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, `"`) {
+		return value
+	}
+
+	var sb strings.Builder
+	inQuotes := false
+	escaped := false
+
+	for _, c := range value {
+		switch {
+		case escaped:
+			sb.WriteRune(c)
+			escaped = false
+		case c == '\\' && inQuotes:
+			escaped = true
+		case c == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+			sb.WriteRune(c)
+		}
+	}
+
+	return sb.String()
+}
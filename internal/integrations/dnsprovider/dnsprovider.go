@@ -0,0 +1,61 @@
+package dnsprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/integrations/clouddns"
+	"github.com/porter-dev/porter/internal/integrations/cloudflare"
+	"github.com/porter-dev/porter/internal/integrations/dns"
+	"github.com/porter-dev/porter/internal/integrations/powerdns"
+	"github.com/porter-dev/porter/internal/integrations/rfc2136"
+	"github.com/porter-dev/porter/internal/integrations/route53"
+)
+
+// NewClient creates a dns client for the provider configured in conf, managing records in the root domain.
+// If no provider is configured, or the configured provider is missing its credentials, the client is nil.
+func NewClient(ctx context.Context, conf env.DNSConf, rootDomain string) (*dns.Client, error) {
+	switch conf.DnsProvider {
+	case "powerdns":
+		if conf.PowerDNSAPIKey != "" && conf.PowerDNSAPIServerURL != "" {
+			return &dns.Client{Client: powerdns.NewClient(conf.PowerDNSAPIServerURL, conf.PowerDNSAPIKey, rootDomain)}, nil
+		}
+	case "cloudflare":
+		if conf.CloudflareAPIToken != "" {
+			cloudflareClient, err := cloudflare.NewClient(conf.CloudflareAPIToken, rootDomain)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create cloudflare client: %w", err)
+			}
+
+			return &dns.Client{Client: cloudflareClient}, nil
+		}
+	case "route53":
+		route53Client, err := route53.NewClient(conf.Route53AccessKeyID, conf.Route53SecretAccessKey, conf.Route53HostedZoneID, rootDomain)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create route53 client: %w", err)
+		}
+
+		return &dns.Client{Client: route53Client}, nil
+	case "clouddns":
+		if conf.CloudDNSProjectID != "" && conf.CloudDNSManagedZone != "" {
+			cloudDNSClient, err := clouddns.NewClient(ctx, []byte(conf.CloudDNSCredentialsJSON), conf.CloudDNSProjectID, conf.CloudDNSManagedZone, rootDomain)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create cloud dns client: %w", err)
+			}
+
+			return &dns.Client{Client: cloudDNSClient}, nil
+		}
+	case "rfc2136":
+		if conf.RFC2136Nameserver != "" {
+			rfc2136Client, err := rfc2136.NewClient(conf.RFC2136Nameserver, conf.RFC2136TSIGKeyName, conf.RFC2136TSIGSecret, conf.RFC2136TSIGAlgorithm, rootDomain)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create rfc2136 client: %w", err)
+			}
+
+			return &dns.Client{Client: rfc2136Client}, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -98,6 +98,89 @@ func (c Client) CreateARecord(record dns.Record) error {
 	})
 }
 
+// CreateTXTRecord creates a new TXT record for the nameserver, replacing any existing values
+func (c Client) CreateTXTRecord(record dns.Record) error {
+	hostnameC := canonicalize(record.Hostname())
+
+	return c.sendRequest("PATCH", &RecordData{
+		RRSets: []RR{{
+			Name:       hostnameC,
+			Type:       "TXT",
+			ChangeType: "REPLACE",
+			TTL:        300,
+			Records: []Record{{
+				Content:  dns.QuoteTXT(record.Value),
+				Disabled: false,
+				Name:     hostnameC,
+				Type:     "TXT",
+				Priority: 0,
+			}},
+		}},
+	})
+}
+
+// DeleteRecord deletes the resource record set with the name and type of the record
+func (c Client) DeleteRecord(record dns.Record) error {
+	return c.sendRequest("PATCH", &RecordData{
+		RRSets: []RR{{
+			Name:       canonicalize(record.Hostname()),
+			Type:       record.Type.String(),
+			ChangeType: "DELETE",
+			Records:    []Record{},
+		}},
+	})
+}
+
+// zone is the subset of a PowerDNS zone returned when listing records
+type zone struct {
+	RRSets []RR `json:"rrsets"`
+}
+
+// ListRecords lists the A, CNAME and TXT records in the zone of the run domain
+func (c Client) ListRecords() ([]dns.Record, error) {
+	resBytes, err := c.doRequest("GET", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	z := &zone{}
+	err = json.Unmarshal(resBytes, z)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding zone: %w", err)
+	}
+
+	records := make([]dns.Record, 0)
+	for _, rr := range z.RRSets {
+		recordType, ok := dns.RecordTypeFromString(rr.Type)
+		if !ok {
+			continue
+		}
+
+		name, ok := dns.RecordName(rr.Name, c.runDomain)
+		if !ok {
+			continue
+		}
+
+		for _, rec := range rr.Records {
+			value := rec.Content
+			if recordType == dns.RecordType_TXT {
+				value = dns.UnquoteTXT(value)
+			} else {
+				value = strings.TrimSuffix(value, ".")
+			}
+
+			records = append(records, dns.Record{
+				Type:       recordType,
+				Name:       name,
+				RootDomain: c.runDomain,
+				Value:      value,
+			})
+		}
+	}
+
+	return records, nil
+}
+
 func canonicalize(value string) string {
 	// if the string ends in a period, return
 	if value[len(value)-1:] == "." {
@@ -108,25 +191,35 @@ func canonicalize(value string) string {
 }
 
 func (c *Client) sendRequest(method string, data *RecordData) error {
+	_, err := c.doRequest(method, data)
+	return err
+}
+
+func (c *Client) doRequest(method string, data *RecordData) ([]byte, error) {
 	reqURL, err := url.Parse(c.serverURL)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	reqURL.Path = fmt.Sprintf("/api/v1/servers/localhost/zones/%s", c.runDomain)
 
-	strData, err := json.Marshal(data)
-	if err != nil {
-		return err
+	var body io.Reader
+	if data != nil {
+		strData, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		body = strings.NewReader(string(strData))
 	}
 
 	req, err := http.NewRequest(
 		method,
 		reqURL.String(),
-		strings.NewReader(string(strData)),
+		body,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
@@ -135,19 +228,24 @@ func (c *Client) sendRequest(method string, data *RecordData) error {
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer res.Body.Close()
 
+	resBytes, err := io.ReadAll(res.Body)
+
 	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		resBytes, err := io.ReadAll(res.Body)
 		if err != nil {
-			return fmt.Errorf("request failed with status code %d, but could not read body (%s)\n", res.StatusCode, err.Error())
+			return nil, fmt.Errorf("request failed with status code %d, but could not read body (%s)\n", res.StatusCode, err.Error())
 		}
 
-		return fmt.Errorf("request failed with status code %d: %s\n", res.StatusCode, string(resBytes))
+		return nil, fmt.Errorf("request failed with status code %d: %s\n", res.StatusCode, string(resBytes))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
 	}
 
-	return nil
+	return resBytes, nil
 }
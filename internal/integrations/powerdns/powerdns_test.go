@@ -0,0 +1,119 @@
+package powerdns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+func TestCreateTXTRecord(t *testing.T) {
+	var got RecordData
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/servers/localhost/zones/example.com" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("X-Api-Key") != "api-key" {
+			t.Errorf("expected api key header, got %s", r.Header.Get("X-Api-Key"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("%v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "api-key", "example.com")
+
+	err := client.CreateTXTRecord(dns.Record{Type: dns.RecordType_TXT, Name: "_porter", RootDomain: "example.com", Value: `verify "me"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.RRSets) != 1 || len(got.RRSets[0].Records) != 1 {
+		t.Fatalf("expected a single rrset with a single record, got %+v", got)
+	}
+	if got.RRSets[0].Name != "_porter.example.com." || got.RRSets[0].ChangeType != "REPLACE" || got.RRSets[0].Type != "TXT" {
+		t.Errorf("unexpected rrset %+v", got.RRSets[0])
+	}
+	if got.RRSets[0].Records[0].Content != `"verify \"me\""` {
+		t.Errorf("expected quoted txt value, got %s", got.RRSets[0].Records[0].Content)
+	}
+}
+
+func TestDeleteRecord(t *testing.T) {
+	var got RecordData
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("%v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "api-key", "example.com")
+
+	err := client.DeleteRecord(dns.Record{Type: dns.RecordType_CNAME, Name: "app", RootDomain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.RRSets) != 1 || got.RRSets[0].Name != "app.example.com." || got.RRSets[0].Type != "CNAME" || got.RRSets[0].ChangeType != "DELETE" {
+		t.Errorf("unexpected delete request %+v", got)
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		w.Write([]byte(`{"rrsets": [
+			{"name": "example.com.", "type": "SOA", "records": [{"content": "ns1.example.com. admin.example.com. 1 10800 3600 604800 3600"}]},
+			{"name": "app.example.com.", "type": "A", "records": [{"content": "10.0.0.1"}, {"content": "10.0.0.2"}]},
+			{"name": "www.example.com.", "type": "CNAME", "records": [{"content": "app.example.com."}]},
+			{"name": "_porter.example.com.", "type": "TXT", "records": [{"content": "\"porter-verify\""}]},
+			{"name": "app.other.com.", "type": "A", "records": [{"content": "10.0.0.3"}]}
+		]}`)) // nolint:errcheck,gosec
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "api-key", "example.com")
+
+	records, err := client.ListRecords()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []dns.Record{
+		{Type: dns.RecordType_A, Name: "app", RootDomain: "example.com", Value: "10.0.0.1"},
+		{Type: dns.RecordType_A, Name: "app", RootDomain: "example.com", Value: "10.0.0.2"},
+		{Type: dns.RecordType_CNAME, Name: "www", RootDomain: "example.com", Value: "app.example.com"},
+		{Type: dns.RecordType_TXT, Name: "_porter", RootDomain: "example.com", Value: "porter-verify"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected records %+v, got %+v", expected, records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("expected record %+v, got %+v", expected[i], records[i])
+		}
+	}
+}
+
+func TestRequestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "zone not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "api-key", "example.com")
+
+	if _, err := client.ListRecords(); err == nil {
+		t.Errorf("expected error")
+	}
+}
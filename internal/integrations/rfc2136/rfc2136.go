@@ -0,0 +1,862 @@
+package rfc2136
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // nolint:gosec // hmac-sha1 is a standard TSIG algorithm that some nameservers only support
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+// TTL sets the TTL for records created through dynamic updates
+const TTL = 300
+
+// opCodeUpdate is the DNS UPDATE operation code defined in RFC 2136
+const opCodeUpdate dnsmessage.OpCode = 5
+
+// typeTSIG is the resource record type of a transaction signature, defined in RFC 8945
+const typeTSIG dnsmessage.Type = 250
+
+// tsigFudge is the number of seconds of clock skew the nameserver allows when checking a signature
+const tsigFudge = 300
+
+// tsigAlgorithms are the supported TSIG algorithms, keyed by their algorithm name
+var tsigAlgorithms = map[string]func() hash.Hash{
+	"hmac-sha1.":   sha1.New,
+	"hmac-sha256.": sha256.New,
+	"hmac-sha512.": sha512.New,
+}
+
+// Client sends dynamic DNS updates (RFC 2136) to a nameserver over TCP, signed with a TSIG key if one is configured
+type Client struct {
+	nameserver string
+	runDomain  string
+
+	tsigKeyName   string
+	tsigAlgorithm string
+	tsigSecret    []byte
+
+	timeout time.Duration
+}
+
+// NewClient creates a new RFC 2136 client for the zone of the run domain. The nameserver is a host:port pair,
+// and the TSIG secret is base64-encoded. The TSIG algorithm defaults to hmac-sha256.
+func NewClient(nameserver, tsigKeyName, tsigSecret, tsigAlgorithm, runDomain string) (Client, error) {
+	if _, _, err := net.SplitHostPort(nameserver); err != nil {
+		nameserver = net.JoinHostPort(nameserver, "53")
+	}
+
+	client := Client{
+		nameserver: nameserver,
+		runDomain:  runDomain,
+		timeout:    30 * time.Second,
+	}
+
+	if tsigKeyName == "" {
+		return client, nil
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(tsigSecret)
+	if err != nil {
+		return Client{}, fmt.Errorf("tsig secret must be base64-encoded: %w", err)
+	}
+
+	if tsigAlgorithm == "" {
+		tsigAlgorithm = "hmac-sha256"
+	}
+	tsigAlgorithm = canonicalize(strings.ToLower(tsigAlgorithm))
+
+	if _, ok := tsigAlgorithms[tsigAlgorithm]; !ok {
+		return Client{}, fmt.Errorf("unsupported tsig algorithm %s", tsigAlgorithm)
+	}
+
+	client.tsigKeyName = canonicalize(strings.ToLower(tsigKeyName))
+	client.tsigAlgorithm = tsigAlgorithm
+	client.tsigSecret = secret
+
+	return client, nil
+}
+
+// CreateARecord creates or replaces an A record in the zone
+func (c Client) CreateARecord(record dns.Record) error {
+	addr, err := netip.ParseAddr(record.Value)
+	if err != nil || !addr.Is4() {
+		return fmt.Errorf("invalid ipv4 address %s", record.Value)
+	}
+
+	return c.replaceRecord(record, func(b *dnsmessage.Builder, h dnsmessage.ResourceHeader) error {
+		return b.AResource(h, dnsmessage.AResource{A: addr.As4()})
+	})
+}
+
+// CreateCNAMERecord creates or replaces a CNAME record in the zone
+func (c Client) CreateCNAMERecord(record dns.Record) error {
+	target, err := dnsmessage.NewName(canonicalize(record.Value))
+	if err != nil {
+		return fmt.Errorf("invalid cname target %s: %w", record.Value, err)
+	}
+
+	return c.replaceRecord(record, func(b *dnsmessage.Builder, h dnsmessage.ResourceHeader) error {
+		return b.CNAMEResource(h, dnsmessage.CNAMEResource{CNAME: target})
+	})
+}
+
+// CreateTXTRecord creates or replaces a TXT record in the zone
+func (c Client) CreateTXTRecord(record dns.Record) error {
+	return c.replaceRecord(record, func(b *dnsmessage.Builder, h dnsmessage.ResourceHeader) error {
+		return b.TXTResource(h, dnsmessage.TXTResource{TXT: splitTXT(record.Value)})
+	})
+}
+
+// DeleteRecord deletes the resource record set with the name and type of the record
+func (c Client) DeleteRecord(record dns.Record) error {
+	return c.replaceRecord(record, nil)
+}
+
+// ListRecords lists the A, CNAME and TXT records in the zone with a zone transfer (AXFR). The nameserver
+// must allow transfers of the zone to this client, or to its TSIG key.
+func (c Client) ListRecords() ([]dns.Record, error) {
+	zone, err := dnsmessage.NewName(canonicalize(c.runDomain))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone %s: %w", c.runDomain, err)
+	}
+
+	id, err := messageID()
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.buildMessage(dnsmessage.Header{ID: id}, func(b *dnsmessage.Builder) error {
+		if err := b.StartQuestions(); err != nil {
+			return err
+		}
+
+		return b.Question(dnsmessage.Question{Name: zone, Type: dnsmessage.TypeAXFR, Class: dnsmessage.ClassINET})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building zone transfer request: %w", err)
+	}
+
+	verifier, err := c.newResponseVerifier(msg)
+	if err != nil {
+		return nil, fmt.Errorf("error building zone transfer request: %w", err)
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() // nolint:errcheck
+
+	err = writeMessage(conn, msg)
+	if err != nil {
+		return nil, fmt.Errorf("error sending zone transfer request: %w", err)
+	}
+
+	records := make([]dns.Record, 0)
+	soaCount := 0
+
+	// a zone transfer is a stream of messages which starts and ends with the SOA record of the zone
+	for soaCount < 2 {
+		res, err := readMessage(conn)
+		if err != nil {
+			return nil, fmt.Errorf("error reading zone transfer response: %w", err)
+		}
+
+		if err := verifier.verify(res); err != nil {
+			return nil, fmt.Errorf("invalid zone transfer response: %w", err)
+		}
+
+		var p dnsmessage.Parser
+		header, err := p.Start(res)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing zone transfer response: %w", err)
+		}
+		if header.RCode != dnsmessage.RCodeSuccess {
+			return nil, fmt.Errorf("zone transfer failed: %s", header.RCode)
+		}
+
+		if err := p.SkipAllQuestions(); err != nil {
+			return nil, fmt.Errorf("error parsing zone transfer response: %w", err)
+		}
+
+		for {
+			h, err := p.AnswerHeader()
+			if errors.Is(err, dnsmessage.ErrSectionDone) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error parsing zone transfer response: %w", err)
+			}
+
+			record := dns.Record{RootDomain: c.runDomain}
+
+			switch h.Type {
+			case dnsmessage.TypeSOA:
+				soaCount++
+				err = p.SkipAnswer()
+			case dnsmessage.TypeA:
+				var r dnsmessage.AResource
+				r, err = p.AResource()
+				record.Type = dns.RecordType_A
+				record.Value = netip.AddrFrom4(r.A).String()
+			case dnsmessage.TypeCNAME:
+				var r dnsmessage.CNAMEResource
+				r, err = p.CNAMEResource()
+				record.Type = dns.RecordType_CNAME
+				record.Value = strings.TrimSuffix(r.CNAME.String(), ".")
+			case dnsmessage.TypeTXT:
+				var r dnsmessage.TXTResource
+				r, err = p.TXTResource()
+				record.Type = dns.RecordType_TXT
+				record.Value = strings.Join(r.TXT, "")
+			default:
+				err = p.SkipAnswer()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error parsing zone transfer response: %w", err)
+			}
+
+			if record.Value == "" {
+				continue
+			}
+
+			name, ok := dns.RecordName(h.Name.String(), c.runDomain)
+			if !ok {
+				continue
+			}
+			record.Name = name
+
+			records = append(records, record)
+		}
+	}
+
+	if err := verifier.finish(); err != nil {
+		return nil, fmt.Errorf("invalid zone transfer response: %w", err)
+	}
+
+	return records, nil
+}
+
+// replaceRecord sends an update which deletes the resource record set with the name and type of the record, and then
+// adds the record with addRecord. If addRecord is nil, the record set is only deleted.
+func (c Client) replaceRecord(record dns.Record, addRecord func(b *dnsmessage.Builder, h dnsmessage.ResourceHeader) error) error {
+	if record.RootDomain == "" {
+		record.RootDomain = c.runDomain
+	}
+
+	zone, err := dnsmessage.NewName(canonicalize(c.runDomain))
+	if err != nil {
+		return fmt.Errorf("invalid zone %s: %w", c.runDomain, err)
+	}
+
+	name, err := dnsmessage.NewName(canonicalize(record.Hostname()))
+	if err != nil {
+		return fmt.Errorf("invalid record name %s: %w", record.Hostname(), err)
+	}
+
+	recordType := dnsmessage.Type(0)
+	switch record.Type {
+	case dns.RecordType_A:
+		recordType = dnsmessage.TypeA
+	case dns.RecordType_CNAME:
+		recordType = dnsmessage.TypeCNAME
+	case dns.RecordType_TXT:
+		recordType = dnsmessage.TypeTXT
+	default:
+		return fmt.Errorf("unsupported record type %s", record.Type)
+	}
+
+	id, err := messageID()
+	if err != nil {
+		return err
+	}
+
+	msg, err := c.buildMessage(dnsmessage.Header{ID: id, OpCode: opCodeUpdate}, func(b *dnsmessage.Builder) error {
+		// the zone section of an update uses the question section format
+		if err := b.StartQuestions(); err != nil {
+			return err
+		}
+		if err := b.Question(dnsmessage.Question{Name: zone, Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET}); err != nil {
+			return err
+		}
+
+		// the prerequisite section is empty, and the update section uses the authority section format
+		if err := b.StartAnswers(); err != nil {
+			return err
+		}
+		if err := b.StartAuthorities(); err != nil {
+			return err
+		}
+
+		// deleting an rrset is expressed as a record of class ANY with no data
+		err := b.UnknownResource(
+			dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassANY, TTL: 0},
+			dnsmessage.UnknownResource{Type: recordType, Data: []byte{}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if addRecord == nil {
+			return nil
+		}
+
+		return addRecord(b, dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: TTL})
+	})
+	if err != nil {
+		return fmt.Errorf("error building update for %s record: %w", record.Type, err)
+	}
+
+	verifier, err := c.newResponseVerifier(msg)
+	if err != nil {
+		return fmt.Errorf("error building update for %s record: %w", record.Type, err)
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close() // nolint:errcheck
+
+	err = writeMessage(conn, msg)
+	if err != nil {
+		return fmt.Errorf("error sending update for %s record: %w", record.Type, err)
+	}
+
+	res, err := readMessage(conn)
+	if err != nil {
+		return fmt.Errorf("error reading update response for %s record: %w", record.Type, err)
+	}
+
+	if err := verifier.verify(res); err != nil {
+		return fmt.Errorf("invalid update response for %s record: %w", record.Type, err)
+	}
+
+	var p dnsmessage.Parser
+	header, err := p.Start(res)
+	if err != nil {
+		return fmt.Errorf("error parsing update response for %s record: %w", record.Type, err)
+	}
+	if header.RCode != dnsmessage.RCodeSuccess {
+		return fmt.Errorf("update for %s record failed: %s", record.Type, header.RCode)
+	}
+
+	return nil
+}
+
+// buildMessage builds a message with the given sections, and signs it if the client has a TSIG key
+func (c Client) buildMessage(header dnsmessage.Header, buildSections func(b *dnsmessage.Builder) error) ([]byte, error) {
+	build := func(tsig *dnsmessage.UnknownResource) ([]byte, error) {
+		b := dnsmessage.NewBuilder(nil, header)
+
+		if err := buildSections(&b); err != nil {
+			return nil, err
+		}
+
+		if tsig != nil {
+			keyName, err := dnsmessage.NewName(c.tsigKeyName)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.StartAdditionals(); err != nil {
+				return nil, err
+			}
+			if err := b.UnknownResource(dnsmessage.ResourceHeader{Name: keyName, Class: dnsmessage.ClassANY, TTL: 0}, *tsig); err != nil {
+				return nil, err
+			}
+		}
+
+		return b.Finish()
+	}
+
+	unsigned, err := build(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tsigKeyName == "" {
+		return unsigned, nil
+	}
+
+	tsig, err := c.sign(unsigned, header.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return build(tsig)
+}
+
+// sign computes the TSIG record of a message as described in RFC 8945, section 4.3
+func (c Client) sign(msg []byte, id uint16, now time.Time) (*dnsmessage.UnknownResource, error) {
+	keyName, err := packName(c.tsigKeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm, err := packName(c.tsigAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	timeSigned := make([]byte, 8)
+	binary.BigEndian.PutUint64(timeSigned, uint64(now.Unix()))
+	timeSigned = timeSigned[2:]
+
+	fudge := binary.BigEndian.AppendUint16(nil, tsigFudge)
+
+	// the mac covers the message, and the tsig variables: key name, class ANY, ttl 0, algorithm, time signed,
+	// fudge, error 0 and an empty other data field
+	mac := hmac.New(tsigAlgorithms[c.tsigAlgorithm], c.tsigSecret)
+	mac.Write(msg)                                                          // nolint:errcheck,gosec
+	mac.Write(tsigVariables(keyName, algorithm, timeSigned, fudge, 0, nil)) // nolint:errcheck,gosec
+	sum := mac.Sum(nil)
+
+	var data []byte
+	data = append(data, algorithm...)
+	data = append(data, timeSigned...)
+	data = append(data, fudge...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(sum)))
+	data = append(data, sum...)
+	data = binary.BigEndian.AppendUint16(data, id)
+	data = append(data, 0, 0, 0, 0) // error and other len
+
+	return &dnsmessage.UnknownResource{Type: typeTSIG, Data: data}, nil
+}
+
+// tsigVariables encodes the tsig variables covered by the mac of a message: key name, class ANY, ttl 0, algorithm,
+// time signed, fudge, error and other data
+func tsigVariables(keyName, algorithm, timeSigned, fudge []byte, errorCode uint16, otherData []byte) []byte {
+	var variables []byte
+	variables = append(variables, keyName...)
+	variables = binary.BigEndian.AppendUint16(variables, uint16(dnsmessage.ClassANY))
+	variables = append(variables, 0, 0, 0, 0)
+	variables = append(variables, algorithm...)
+	variables = append(variables, timeSigned...)
+	variables = append(variables, fudge...)
+	variables = binary.BigEndian.AppendUint16(variables, errorCode)
+	variables = binary.BigEndian.AppendUint16(variables, uint16(len(otherData)))
+
+	return append(variables, otherData...)
+}
+
+// maxUnsignedMessages is the number of consecutive unsigned messages allowed in a signed zone transfer (RFC 8945, section 5.3.1)
+const maxUnsignedMessages = 99
+
+// tsigRecord is the transaction signature of a message
+type tsigRecord struct {
+	algorithm  string
+	timeSigned []byte
+	fudge      []byte
+	mac        []byte
+	originalID uint16
+	errorCode  uint16
+	otherData  []byte
+}
+
+// responseVerifier checks the ids and transaction signatures of the responses to a request, as described in
+// RFC 8945, section 5.3. The first response must be signed with the key of the client. Later messages of a zone
+// transfer may be left unsigned, in which case they are covered by the signature of the next signed message.
+type responseVerifier struct {
+	client Client
+	id     uint16
+
+	// prevMAC is the mac of the request, and then of the last signed response
+	prevMAC []byte
+	// unsigned are the messages received since the last signed response
+	unsigned      []byte
+	unsignedCount int
+	verified      bool
+}
+
+// newResponseVerifier returns a verifier for the responses to a request built with buildMessage
+func (c Client) newResponseVerifier(req []byte) (*responseVerifier, error) {
+	if len(req) < 12 {
+		return nil, fmt.Errorf("request is too short")
+	}
+
+	v := &responseVerifier{
+		client: c,
+		id:     binary.BigEndian.Uint16(req),
+	}
+
+	if c.tsigKeyName == "" {
+		return v, nil
+	}
+
+	_, tsig, err := c.splitTSIG(req)
+	if err != nil {
+		return nil, err
+	}
+	if tsig == nil {
+		return nil, fmt.Errorf("request is not signed")
+	}
+	v.prevMAC = tsig.mac
+
+	return v, nil
+}
+
+// verify checks the next response to the request
+func (v *responseVerifier) verify(res []byte) error {
+	if len(res) < 12 {
+		return fmt.Errorf("response is too short")
+	}
+	if id := binary.BigEndian.Uint16(res); id != v.id {
+		return fmt.Errorf("response id %d does not match request id %d", id, v.id)
+	}
+
+	if v.client.tsigKeyName == "" {
+		return nil
+	}
+
+	unsigned, tsig, err := v.client.splitTSIG(res)
+	if err != nil {
+		return err
+	}
+
+	if tsig == nil {
+		if !v.verified {
+			return fmt.Errorf("response is not signed")
+		}
+		if v.unsignedCount >= maxUnsignedMessages {
+			return fmt.Errorf("more than %d consecutive responses are not signed", maxUnsignedMessages)
+		}
+
+		v.unsigned = append(v.unsigned, res...)
+		v.unsignedCount++
+
+		return nil
+	}
+
+	if tsig.errorCode != 0 {
+		return fmt.Errorf("nameserver rejected the request signature with tsig error %d", tsig.errorCode)
+	}
+	if tsig.algorithm != v.client.tsigAlgorithm {
+		return fmt.Errorf("response is signed with algorithm %s instead of %s", tsig.algorithm, v.client.tsigAlgorithm)
+	}
+	if tsig.originalID != v.id {
+		return fmt.Errorf("response signature id %d does not match request id %d", tsig.originalID, v.id)
+	}
+
+	signedAt := int64(binary.BigEndian.Uint64(append([]byte{0, 0}, tsig.timeSigned...)))
+	fudge := int64(binary.BigEndian.Uint16(tsig.fudge))
+	if now := time.Now().Unix(); now < signedAt-fudge || now > signedAt+fudge {
+		return fmt.Errorf("response signature time is outside of the allowed clock skew")
+	}
+
+	mac := hmac.New(tsigAlgorithms[v.client.tsigAlgorithm], v.client.tsigSecret)
+	mac.Write(binary.BigEndian.AppendUint16(nil, uint16(len(v.prevMAC)))) // nolint:errcheck,gosec
+	mac.Write(v.prevMAC)                                                  // nolint:errcheck,gosec
+
+	if !v.verified {
+		keyName, err := packName(v.client.tsigKeyName)
+		if err != nil {
+			return err
+		}
+		algorithm, err := packName(v.client.tsigAlgorithm)
+		if err != nil {
+			return err
+		}
+
+		mac.Write(unsigned)                                                                                       // nolint:errcheck,gosec
+		mac.Write(tsigVariables(keyName, algorithm, tsig.timeSigned, tsig.fudge, tsig.errorCode, tsig.otherData)) // nolint:errcheck,gosec
+	} else {
+		// later messages of a zone transfer only cover the timers, along with the messages since the last signature
+		mac.Write(v.unsigned)      // nolint:errcheck,gosec
+		mac.Write(unsigned)        // nolint:errcheck,gosec
+		mac.Write(tsig.timeSigned) // nolint:errcheck,gosec
+		mac.Write(tsig.fudge)      // nolint:errcheck,gosec
+	}
+
+	if !hmac.Equal(mac.Sum(nil), tsig.mac) {
+		return fmt.Errorf("response signature does not verify")
+	}
+
+	v.prevMAC = tsig.mac
+	v.unsigned = nil
+	v.unsignedCount = 0
+	v.verified = true
+
+	return nil
+}
+
+// finish checks that the last response was signed
+func (v *responseVerifier) finish() error {
+	if v.unsignedCount > 0 {
+		return fmt.Errorf("last response is not signed")
+	}
+
+	return nil
+}
+
+// splitTSIG returns the message without its transaction signature, along with the signature. If the message is not
+// signed, the message is returned as is, with a nil signature.
+func (c Client) splitTSIG(msg []byte) ([]byte, *tsigRecord, error) {
+	var p dnsmessage.Parser
+	if _, err := p.Start(msg); err != nil {
+		return nil, nil, err
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil, nil, err
+	}
+	if err := p.SkipAllAnswers(); err != nil {
+		return nil, nil, err
+	}
+	if err := p.SkipAllAuthorities(); err != nil {
+		return nil, nil, err
+	}
+
+	additionals, err := p.AllAdditionals()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, additional := range additionals {
+		if additional.Header.Type == typeTSIG && i != len(additionals)-1 {
+			return nil, nil, fmt.Errorf("tsig record must be the last record of the message")
+		}
+	}
+	if len(additionals) == 0 || additionals[len(additionals)-1].Header.Type != typeTSIG {
+		return msg, nil, nil
+	}
+
+	header := additionals[len(additionals)-1].Header
+	if strings.ToLower(header.Name.String()) != c.tsigKeyName || header.Class != dnsmessage.ClassANY {
+		return nil, nil, fmt.Errorf("message is signed with key %s instead of %s", header.Name.String(), c.tsigKeyName)
+	}
+
+	start, err := lastRecordOffset(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rdataStart, err := skipName(msg, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	rdataStart += 10
+	if rdataStart > len(msg) {
+		return nil, nil, fmt.Errorf("tsig record is truncated")
+	}
+
+	tsig, err := parseTSIGData(msg[rdataStart:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the signed message is the message without the tsig record, with the additional count decremented
+	unsigned := append([]byte{}, msg[:start]...)
+	binary.BigEndian.PutUint16(unsigned[10:], uint16(len(additionals)-1))
+
+	return unsigned, tsig, nil
+}
+
+// lastRecordOffset returns the offset of the last resource record of a message
+func lastRecordOffset(msg []byte) (int, error) {
+	if len(msg) < 12 {
+		return 0, fmt.Errorf("message is too short")
+	}
+
+	questions := int(binary.BigEndian.Uint16(msg[4:]))
+	records := int(binary.BigEndian.Uint16(msg[6:])) + int(binary.BigEndian.Uint16(msg[8:])) + int(binary.BigEndian.Uint16(msg[10:]))
+	if records == 0 {
+		return 0, fmt.Errorf("message has no records")
+	}
+
+	offset := 12
+	for i := 0; i < questions; i++ {
+		end, err := skipName(msg, offset)
+		if err != nil {
+			return 0, err
+		}
+		offset = end + 4
+	}
+
+	for i := 0; i < records-1; i++ {
+		end, err := skipName(msg, offset)
+		if err != nil {
+			return 0, err
+		}
+		if end+10 > len(msg) {
+			return 0, fmt.Errorf("resource record is truncated")
+		}
+		offset = end + 10 + int(binary.BigEndian.Uint16(msg[end+8:]))
+	}
+
+	if offset >= len(msg) {
+		return 0, fmt.Errorf("resource record is truncated")
+	}
+
+	return offset, nil
+}
+
+// skipName returns the offset following the, possibly compressed, domain name at the offset
+func skipName(msg []byte, offset int) (int, error) {
+	for {
+		if offset >= len(msg) {
+			return 0, fmt.Errorf("domain name is truncated")
+		}
+
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xc0 == 0xc0:
+			return offset + 2, nil
+		default:
+			offset += 1 + length
+		}
+	}
+}
+
+// parseTSIGData parses the data of a tsig record, whose algorithm name is never compressed
+func parseTSIGData(data []byte) (*tsigRecord, error) {
+	var labels []string
+
+	offset := 0
+	for {
+		if offset >= len(data) {
+			return nil, fmt.Errorf("tsig record is truncated")
+		}
+
+		length := int(data[offset])
+		if length == 0 {
+			offset++
+			break
+		}
+		if length > 63 || offset+1+length > len(data) {
+			return nil, fmt.Errorf("invalid tsig algorithm name")
+		}
+
+		labels = append(labels, strings.ToLower(string(data[offset+1:offset+1+length])))
+		offset += 1 + length
+	}
+
+	if offset+10 > len(data) {
+		return nil, fmt.Errorf("tsig record is truncated")
+	}
+
+	tsig := &tsigRecord{
+		algorithm:  canonicalize(strings.Join(labels, ".")),
+		timeSigned: data[offset : offset+6],
+		fudge:      data[offset+6 : offset+8],
+	}
+
+	macLen := int(binary.BigEndian.Uint16(data[offset+8:]))
+	offset += 10
+	if offset+macLen+6 > len(data) {
+		return nil, fmt.Errorf("tsig record is truncated")
+	}
+
+	tsig.mac = data[offset : offset+macLen]
+	offset += macLen
+
+	tsig.originalID = binary.BigEndian.Uint16(data[offset:])
+	tsig.errorCode = binary.BigEndian.Uint16(data[offset+2:])
+	otherLen := int(binary.BigEndian.Uint16(data[offset+4:]))
+	offset += 6
+	if offset+otherLen > len(data) {
+		return nil, fmt.Errorf("tsig record is truncated")
+	}
+	tsig.otherData = data[offset : offset+otherLen]
+
+	return tsig, nil
+}
+
+func (c Client) dial() (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", c.nameserver, c.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to nameserver %s: %w", c.nameserver, err)
+	}
+
+	err = conn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		conn.Close() // nolint:errcheck,gosec
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// writeMessage writes a message prefixed with its two-byte length, as required for DNS over TCP
+func writeMessage(w io.Writer, msg []byte) error {
+	if len(msg) > 0xffff {
+		return fmt.Errorf("message is too large")
+	}
+
+	_, err := w.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(msg))), msg...))
+	return err
+}
+
+// readMessage reads a message prefixed with its two-byte length
+func readMessage(r io.Reader) ([]byte, error) {
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(r, length); err != nil {
+		return nil, err
+	}
+
+	msg := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// packName encodes a domain name in the uncompressed, lowercase wire format used for TSIG variables
+func packName(name string) ([]byte, error) {
+	var packed []byte
+
+	for _, label := range strings.Split(strings.TrimSuffix(strings.ToLower(name), "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid domain name %s", name)
+		}
+
+		packed = append(packed, byte(len(label)))
+		packed = append(packed, label...)
+	}
+
+	return append(packed, 0), nil
+}
+
+// splitTXT splits a TXT record value into strings of at most 255 characters
+func splitTXT(value string) []string {
+	var chunks []string
+	for len(value) > 255 {
+		chunks = append(chunks, value[:255])
+		value = value[255:]
+	}
+
+	return append(chunks, value)
+}
+
+func messageID() (uint16, error) {
+	b := make([]byte, 2)
+	if _, err := rand.Read(b); err != nil {
+		return 0, fmt.Errorf("error generating message id: %w", err)
+	}
+
+	return binary.BigEndian.Uint16(b), nil
+}
+
+func canonicalize(value string) string {
+	if strings.HasSuffix(value, ".") {
+		return value
+	}
+
+	return fmt.Sprintf("%s.", value)
+}
@@ -0,0 +1,665 @@
+package rfc2136
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+// the vectors below were computed independently of this package (python hmac and openssl dgst -hmac) for an update
+// with id 0x1234 which deletes the A records of www.example.com., signed with key porter-key. and secret "porter-secret"
+// at 1700000000
+const (
+	testSecret = "cG9ydGVyLXNlY3JldA=="
+	testUpdate = "123428000001000000010000" + // header: id 0x1234, opcode 5, one zone and one update
+		"076578616d706c6503636f6d0000060001" + // zone: example.com. SOA IN
+		"03777777076578616d706c6503636f6d00000100ff000000000000" // update: www.example.com. A ANY ttl 0, no data
+)
+
+var testSignedAt = time.Unix(1700000000, 0)
+
+func TestSignVectors(t *testing.T) {
+	msg, err := hex.DecodeString(testUpdate)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := []struct {
+		algorithm string
+		expected  string
+	}{
+		{
+			algorithm: "hmac-sha1",
+			expected:  "09686d61632d736861310000006553f100012c00142beff752949cecab78ce70cbb52ae872545a59ec123400000000",
+		},
+		{
+			algorithm: "hmac-sha256",
+			expected:  "0b686d61632d7368613235360000006553f100012c002066640396e5de102fa8815a785c3cc467c411b002f9894598ebb25d72fe0fd75e123400000000",
+		},
+		{
+			algorithm: "HMAC-SHA512.",
+			expected:  "0b686d61632d7368613531320000006553f100012c0040079caf959dfcb0bb223e3a4ef6e166f4d32c772af6a24ce0aae96494558fb31bdb45140f6afdf7589c5de4c26d40485df40029972310fdae5d636ff802fc3ee3123400000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			client, err := NewClient("127.0.0.1", "Porter-Key", testSecret, tt.algorithm, "example.com")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			tsig, err := client.sign(msg, 0x1234, testSignedAt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tsig.Type != typeTSIG {
+				t.Errorf("expected type %d, got %d", typeTSIG, tsig.Type)
+			}
+			if got := hex.EncodeToString(tsig.Data); got != tt.expected {
+				t.Errorf("expected tsig data\n%s\ngot\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildMessageVector(t *testing.T) {
+	client, err := NewClient("127.0.0.1", "", "", "", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zone := dnsmessage.MustNewName("example.com.")
+	name := dnsmessage.MustNewName("www.example.com.")
+
+	msg, err := client.buildMessage(dnsmessage.Header{ID: 0x1234, OpCode: opCodeUpdate}, func(b *dnsmessage.Builder) error {
+		if err := b.StartQuestions(); err != nil {
+			return err
+		}
+		if err := b.Question(dnsmessage.Question{Name: zone, Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET}); err != nil {
+			return err
+		}
+		if err := b.StartAuthorities(); err != nil {
+			return err
+		}
+		return b.UnknownResource(
+			dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassANY},
+			dnsmessage.UnknownResource{Type: dnsmessage.TypeA, Data: []byte{}},
+		)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(msg); got != testUpdate {
+		t.Errorf("expected message\n%s\ngot\n%s", testUpdate, got)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("nameserver port defaults to 53", func(t *testing.T) {
+		client, err := NewClient("ns1.example.com", "", "", "", "example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.nameserver != "ns1.example.com:53" {
+			t.Errorf("expected nameserver ns1.example.com:53, got %s", client.nameserver)
+		}
+		if client.tsigKeyName != "" {
+			t.Errorf("expected client without a tsig key, got %s", client.tsigKeyName)
+		}
+	})
+
+	t.Run("tsig algorithm defaults to hmac-sha256", func(t *testing.T) {
+		client, err := NewClient("ns1.example.com:5353", "porter-key", testSecret, "", "example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.nameserver != "ns1.example.com:5353" {
+			t.Errorf("expected nameserver ns1.example.com:5353, got %s", client.nameserver)
+		}
+		if client.tsigAlgorithm != "hmac-sha256." || client.tsigKeyName != "porter-key." {
+			t.Errorf("expected canonical key name and algorithm, got %s and %s", client.tsigKeyName, client.tsigAlgorithm)
+		}
+	})
+
+	t.Run("tsig secret must be base64", func(t *testing.T) {
+		if _, err := NewClient("ns1.example.com", "porter-key", "not base64!", "", "example.com"); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+
+	t.Run("unsupported tsig algorithm", func(t *testing.T) {
+		if _, err := NewClient("ns1.example.com", "porter-key", testSecret, "hmac-md5", "example.com"); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestPackName(t *testing.T) {
+	packed, err := packName("Porter-Key.Example.com.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := hex.EncodeToString(packed); got != "0a706f727465722d6b6579076578616d706c6503636f6d00" {
+		t.Errorf("unexpected packed name %s", got)
+	}
+
+	for _, name := range []string{"", "a..b", strings.Repeat("a", 64) + ".com"} {
+		if _, err := packName(name); err == nil {
+			t.Errorf("expected error packing %q", name)
+		}
+	}
+}
+
+func TestSplitTXT(t *testing.T) {
+	chunks := splitTXT(strings.Repeat("a", 600))
+	if len(chunks) != 3 || len(chunks[0]) != 255 || len(chunks[1]) != 255 || len(chunks[2]) != 90 {
+		t.Errorf("unexpected chunks of lengths %d", len(chunks))
+	}
+
+	if chunks := splitTXT("porter"); len(chunks) != 1 || chunks[0] != "porter" {
+		t.Errorf("unexpected chunks %v", chunks)
+	}
+}
+
+// serve starts a nameserver which handles a single connection, reading one request and writing the responses
+// returned by handle
+func serve(t *testing.T, handle func(req []byte) [][]byte) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	t.Cleanup(func() { l.Close() }) // nolint:errcheck
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close() // nolint:errcheck
+
+		req, err := readMessage(conn)
+		if err != nil {
+			return
+		}
+
+		for _, res := range handle(req) {
+			if err := writeMessage(conn, res); err != nil {
+				return
+			}
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+// respond builds a response to the request with the given rcode and answers
+func respond(t *testing.T, req []byte, rcode dnsmessage.RCode, answers ...dnsmessage.Resource) []byte {
+	t.Helper()
+
+	var p dnsmessage.Parser
+	header, err := p.Start(req)
+	if err != nil {
+		t.Errorf("%v", err)
+		return nil
+	}
+
+	msg := dnsmessage.Message{
+		Header:  dnsmessage.Header{ID: header.ID, Response: true, OpCode: header.OpCode, RCode: rcode},
+		Answers: answers,
+	}
+
+	res, err := msg.Pack()
+	if err != nil {
+		t.Errorf("%v", err)
+		return nil
+	}
+
+	return res
+}
+
+// verifyTSIG checks that the last additional record of a signed request is a valid hmac-sha256 signature, and returns its mac
+func verifyTSIG(t *testing.T, req []byte, secret []byte) []byte {
+	t.Helper()
+
+	var p dnsmessage.Parser
+	header, err := p.Start(req)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := p.SkipAllAnswers(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := p.SkipAllAuthorities(); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	additionals, err := p.AllAdditionals()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(additionals) != 1 || additionals[0].Header.Type != typeTSIG {
+		t.Fatalf("expected a single tsig record, got %+v", additionals)
+	}
+
+	tsig := additionals[0]
+	data := tsig.Body.(*dnsmessage.UnknownResource).Data
+
+	// the signed message is the request without the tsig record, and with the additional count decremented
+	rrLen := len(tsig.Header.Name.String()) + 1 + 10 + len(data)
+	unsigned := append([]byte{}, req[:len(req)-rrLen]...)
+	binary.BigEndian.PutUint16(unsigned[10:], uint16(len(additionals)-1))
+
+	algorithm := []byte("\x0bhmac-sha256\x00")
+	if !bytes.HasPrefix(data, algorithm) {
+		t.Fatalf("expected hmac-sha256 algorithm, got %x", data)
+	}
+	variables := data[len(algorithm) : len(algorithm)+8] // time signed and fudge
+	macLen := int(binary.BigEndian.Uint16(data[len(algorithm)+8:]))
+	mac := data[len(algorithm)+10 : len(algorithm)+10+macLen]
+	origID := binary.BigEndian.Uint16(data[len(algorithm)+10+macLen:])
+
+	if origID != header.ID {
+		t.Errorf("expected original id %d, got %d", header.ID, origID)
+	}
+
+	expected := hmac.New(sha256.New, secret)
+	expected.Write(unsigned)                     // nolint:errcheck,gosec
+	expected.Write([]byte("\x0aporter-key\x00")) // nolint:errcheck,gosec
+	expected.Write([]byte{0, 255, 0, 0, 0, 0})   // nolint:errcheck,gosec
+	expected.Write(algorithm)                    // nolint:errcheck,gosec
+	expected.Write(variables)                    // nolint:errcheck,gosec
+	expected.Write([]byte{0, 0, 0, 0})           // nolint:errcheck,gosec
+	if !hmac.Equal(mac, expected.Sum(nil)) {
+		t.Errorf("tsig mac does not verify")
+	}
+
+	return mac
+}
+
+// signResponses signs the responses to a request with the given mac as a nameserver would, with hmac-sha256 and the
+// key porter-key. The first response covers the request mac and all tsig variables, and each later response covers
+// the previous mac and the timers only.
+func signResponses(t *testing.T, secret []byte, requestMAC []byte, responses ...[]byte) [][]byte {
+	t.Helper()
+
+	return signResponsesExcept(t, secret, requestMAC, nil, responses...)
+}
+
+// signResponsesExcept signs the responses like signResponses, but leaves the responses at the unsigned indices
+// unsigned. Those are covered by the signature of the next signed response.
+func signResponsesExcept(t *testing.T, secret []byte, requestMAC []byte, unsigned map[int]bool, responses ...[]byte) [][]byte {
+	t.Helper()
+
+	algorithm := []byte("\x0bhmac-sha256\x00")
+	timers := binary.BigEndian.AppendUint64(nil, uint64(time.Now().Unix()))[2:]
+	timers = binary.BigEndian.AppendUint16(timers, 300)
+
+	prevMAC := requestMAC
+	signed := make([][]byte, 0, len(responses))
+
+	var pending []byte
+
+	for i, res := range responses {
+		if unsigned[i] {
+			pending = append(pending, res...)
+			signed = append(signed, res)
+			continue
+		}
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(binary.BigEndian.AppendUint16(nil, uint16(len(prevMAC)))) // nolint:errcheck,gosec
+		mac.Write(prevMAC)                                                  // nolint:errcheck,gosec
+		mac.Write(pending)                                                  // nolint:errcheck,gosec
+		mac.Write(res)                                                      // nolint:errcheck,gosec
+		pending = nil
+		if i == 0 {
+			mac.Write([]byte("\x0aporter-key\x00")) // nolint:errcheck,gosec
+			mac.Write([]byte{0, 255, 0, 0, 0, 0})   // nolint:errcheck,gosec
+			mac.Write(algorithm)                    // nolint:errcheck,gosec
+			mac.Write(timers)                       // nolint:errcheck,gosec
+			mac.Write([]byte{0, 0, 0, 0})           // nolint:errcheck,gosec
+		} else {
+			mac.Write(timers) // nolint:errcheck,gosec
+		}
+		sum := mac.Sum(nil)
+		prevMAC = sum
+
+		var data []byte
+		data = append(data, algorithm...)
+		data = append(data, timers...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(sum)))
+		data = append(data, sum...)
+		data = append(data, res[:2]...) // original id
+		data = append(data, 0, 0, 0, 0)
+
+		msg := append([]byte{}, res...)
+		binary.BigEndian.PutUint16(msg[10:], binary.BigEndian.Uint16(msg[10:])+1)
+		msg = append(msg, []byte("\x0aporter-key\x00")...)
+		msg = append(msg, 0, 250, 0, 255, 0, 0, 0, 0)
+		msg = binary.BigEndian.AppendUint16(msg, uint16(len(data)))
+		msg = append(msg, data...)
+
+		signed = append(signed, msg)
+	}
+
+	return signed
+}
+
+func TestReplaceRecord(t *testing.T) {
+	var update dnsmessage.Message
+	var raw []byte
+
+	addr := serve(t, func(req []byte) [][]byte {
+		raw = req
+		if err := update.Unpack(req); err != nil {
+			t.Errorf("%v", err)
+		}
+		return signResponses(t, []byte("porter-secret"), verifyTSIG(t, req, []byte("porter-secret")), respond(t, req, dnsmessage.RCodeSuccess))
+	})
+
+	client, err := NewClient(addr, "porter-key", testSecret, "", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = client.CreateCNAMERecord(dns.Record{Type: dns.RecordType_CNAME, Name: "app", Value: "lb.example.net"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if update.Header.OpCode != opCodeUpdate {
+		t.Errorf("expected update opcode, got %d", update.Header.OpCode)
+	}
+	if len(update.Questions) != 1 || update.Questions[0].Name.String() != "example.com." || update.Questions[0].Type != dnsmessage.TypeSOA {
+		t.Errorf("expected zone example.com., got %+v", update.Questions)
+	}
+	if len(update.Answers) != 0 {
+		t.Errorf("expected no prerequisites, got %+v", update.Answers)
+	}
+	if len(update.Authorities) != 2 {
+		t.Fatalf("expected a delete and an add, got %+v", update.Authorities)
+	}
+
+	del := update.Authorities[0].Header
+	if del.Name.String() != "app.example.com." || del.Type != dnsmessage.TypeCNAME || del.Class != dnsmessage.ClassANY || del.TTL != 0 || del.Length != 0 {
+		t.Errorf("expected rrset delete of app.example.com. CNAME, got %+v", del)
+	}
+
+	add := update.Authorities[1]
+	if add.Header.Class != dnsmessage.ClassINET || add.Header.TTL != TTL {
+		t.Errorf("expected record in class IN with ttl %d, got %+v", TTL, add.Header)
+	}
+	if cname, ok := add.Body.(*dnsmessage.CNAMEResource); !ok || cname.CNAME.String() != "lb.example.net." {
+		t.Errorf("expected cname to lb.example.net., got %+v", add.Body)
+	}
+
+	verifyTSIG(t, raw, []byte("porter-secret"))
+}
+
+func TestReplaceRecordInvalidResponse(t *testing.T) {
+	secret := []byte("porter-secret")
+
+	tests := []struct {
+		name    string
+		handle  func(req []byte) [][]byte
+		wantErr string
+	}{
+		{
+			name: "unsigned response",
+			handle: func(req []byte) [][]byte {
+				return [][]byte{respond(t, req, dnsmessage.RCodeSuccess)}
+			},
+			wantErr: "response is not signed",
+		},
+		{
+			name: "response signed with another secret",
+			handle: func(req []byte) [][]byte {
+				return signResponses(t, []byte("forged-secret"), verifyTSIG(t, req, secret), respond(t, req, dnsmessage.RCodeSuccess))
+			},
+			wantErr: "signature does not verify",
+		},
+		{
+			name: "response signed for another request",
+			handle: func(req []byte) [][]byte {
+				return signResponses(t, secret, []byte("other request mac"), respond(t, req, dnsmessage.RCodeSuccess))
+			},
+			wantErr: "signature does not verify",
+		},
+		{
+			name: "response with another id",
+			handle: func(req []byte) [][]byte {
+				res := respond(t, req, dnsmessage.RCodeSuccess)
+				binary.BigEndian.PutUint16(res, binary.BigEndian.Uint16(res)+1)
+				return signResponses(t, secret, verifyTSIG(t, req, secret), res)
+			},
+			wantErr: "does not match request id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := serve(t, tt.handle)
+
+			client, err := NewClient(addr, "porter-key", testSecret, "", "example.com")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = client.DeleteRecord(dns.Record{Type: dns.RecordType_A, Name: "app"})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReplaceRecordRefused(t *testing.T) {
+	addr := serve(t, func(req []byte) [][]byte {
+		return [][]byte{respond(t, req, dnsmessage.RCodeRefused)}
+	})
+
+	client, err := NewClient(addr, "", "", "", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = client.DeleteRecord(dns.Record{Type: dns.RecordType_A, Name: "app"})
+	if err == nil || !strings.Contains(err.Error(), "RCodeRefused") {
+		t.Errorf("expected refused error, got %v", err)
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	zone := dnsmessage.MustNewName("example.com.")
+	soa := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: zone, Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET},
+		Body:   &dnsmessage.SOAResource{NS: dnsmessage.MustNewName("ns1.example.com."), MBox: dnsmessage.MustNewName("admin.example.com.")},
+	}
+	resource := func(name string, body dnsmessage.ResourceBody) dnsmessage.Resource {
+		return dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Class: dnsmessage.ClassINET, TTL: TTL},
+			Body:   body,
+		}
+	}
+
+	addr := serve(t, func(req []byte) [][]byte {
+		var p dnsmessage.Parser
+		if _, err := p.Start(req); err != nil {
+			t.Errorf("%v", err)
+		}
+		q, err := p.Question()
+		if err != nil || q.Type != dnsmessage.TypeAXFR || q.Name != zone {
+			t.Errorf("expected axfr of example.com., got %+v (%v)", q, err)
+		}
+
+		// the transfer is split over two messages, and ends with the second soa record
+		return [][]byte{
+			respond(t, req, dnsmessage.RCodeSuccess,
+				soa,
+				resource("app.example.com.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}),
+				resource("www.example.com.", &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("app.example.com.")}),
+			),
+			respond(t, req, dnsmessage.RCodeSuccess,
+				resource("_porter.example.com.", &dnsmessage.TXTResource{TXT: []string{"porter", "-verify"}}),
+				resource("example.com.", &dnsmessage.NSResource{NS: dnsmessage.MustNewName("ns1.example.com.")}),
+				soa,
+			),
+		}
+	})
+
+	client, err := NewClient(addr, "", "", "", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := client.ListRecords()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []dns.Record{
+		{Type: dns.RecordType_A, Name: "app", RootDomain: "example.com", Value: "10.0.0.1"},
+		{Type: dns.RecordType_CNAME, Name: "www", RootDomain: "example.com", Value: "app.example.com"},
+		{Type: dns.RecordType_TXT, Name: "_porter", RootDomain: "example.com", Value: "porter-verify"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected records %+v, got %+v", expected, records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("expected record %+v, got %+v", expected[i], records[i])
+		}
+	}
+}
+
+func TestListRecordsSigned(t *testing.T) {
+	secret := []byte("porter-secret")
+	zone := dnsmessage.MustNewName("example.com.")
+	soa := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: zone, Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET},
+		Body:   &dnsmessage.SOAResource{NS: dnsmessage.MustNewName("ns1.example.com."), MBox: dnsmessage.MustNewName("admin.example.com.")},
+	}
+	record := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName("app.example.com."), Class: dnsmessage.ClassINET, TTL: TTL},
+		Body:   &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}},
+	}
+
+	tests := []struct {
+		name    string
+		handle  func(req []byte) [][]byte
+		wantErr string
+	}{
+		{
+			name: "every message is signed",
+			handle: func(req []byte) [][]byte {
+				return signResponses(t, secret, verifyTSIG(t, req, secret),
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+					respond(t, req, dnsmessage.RCodeSuccess, record),
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+				)
+			},
+		},
+		{
+			name: "unsigned message is covered by the next signature",
+			handle: func(req []byte) [][]byte {
+				return signResponsesExcept(t, secret, verifyTSIG(t, req, secret), map[int]bool{1: true},
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+					respond(t, req, dnsmessage.RCodeSuccess, record),
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+				)
+			},
+		},
+		{
+			name: "forged message after the first",
+			handle: func(req []byte) [][]byte {
+				signed := signResponses(t, secret, verifyTSIG(t, req, secret),
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+					respond(t, req, dnsmessage.RCodeSuccess, record),
+				)
+				forged := signResponses(t, []byte("forged-secret"), verifyTSIG(t, req, secret),
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+				)
+				return [][]byte{signed[0], signed[1], forged[1]}
+			},
+			wantErr: "signature does not verify",
+		},
+		{
+			name: "unsigned last message",
+			handle: func(req []byte) [][]byte {
+				return signResponsesExcept(t, secret, verifyTSIG(t, req, secret), map[int]bool{1: true},
+					respond(t, req, dnsmessage.RCodeSuccess, soa, record),
+					respond(t, req, dnsmessage.RCodeSuccess, soa),
+				)
+			},
+			wantErr: "last response is not signed",
+		},
+		{
+			name: "unsigned first message",
+			handle: func(req []byte) [][]byte {
+				return [][]byte{respond(t, req, dnsmessage.RCodeSuccess, soa, record, soa)}
+			},
+			wantErr: "response is not signed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := serve(t, tt.handle)
+
+			client, err := NewClient(addr, "porter-key", testSecret, "", "example.com")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			records, err := client.ListRecords()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(records) != 1 || records[0].Value != "10.0.0.1" {
+				t.Errorf("expected the A record of app, got %+v", records)
+			}
+		})
+	}
+}
+
+func TestListRecordsRefused(t *testing.T) {
+	addr := serve(t, func(req []byte) [][]byte {
+		return [][]byte{respond(t, req, dnsmessage.RCodeRefused)}
+	})
+
+	client, err := NewClient(addr, "", "", "", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.ListRecords()
+	if err == nil || !strings.Contains(err.Error(), "RCodeRefused") {
+		t.Errorf("expected refused error, got %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package route53
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+// TTL sets the TTL for Route53 DNS records
+const TTL = 300
+
+// Client is a struct wrapper around the Route53 client, scoped to a single hosted zone
+type Client struct {
+	hostedZoneID string
+	runDomain    string
+
+	client *route53.Route53
+}
+
+// NewClient creates a new Route53 API client. If the access key is empty, credentials are loaded from the
+// default AWS credential chain. If the hosted zone ID is empty, it is looked up from the run domain.
+func NewClient(accessKeyID, secretAccessKey, hostedZoneID, runDomain string) (Client, error) {
+	awsConf := &aws.Config{
+		// Route53 is a global service, so the region only determines the API endpoint
+		Region: aws.String("us-east-1"),
+	}
+
+	if accessKeyID != "" {
+		awsConf.Credentials = credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")
+	}
+
+	sess, err := session.NewSession(awsConf)
+	if err != nil {
+		return Client{}, fmt.Errorf("unable to create aws session: %w", err)
+	}
+
+	client := Client{
+		hostedZoneID: hostedZoneID,
+		runDomain:    runDomain,
+		client:       route53.New(sess),
+	}
+
+	if client.hostedZoneID == "" {
+		client.hostedZoneID, err = client.hostedZoneIDByName(runDomain)
+		if err != nil {
+			return Client{}, err
+		}
+	}
+
+	return client, nil
+}
+
+// CreateARecord creates or replaces an A record in the hosted zone
+func (c Client) CreateARecord(record dns.Record) error {
+	return c.upsertRecord(record, record.Value)
+}
+
+// CreateCNAMERecord creates or replaces a CNAME record in the hosted zone
+func (c Client) CreateCNAMERecord(record dns.Record) error {
+	return c.upsertRecord(record, record.Value)
+}
+
+// CreateTXTRecord creates or replaces a TXT record in the hosted zone
+func (c Client) CreateTXTRecord(record dns.Record) error {
+	return c.upsertRecord(record, dns.QuoteTXT(record.Value))
+}
+
+// DeleteRecord deletes the resource record set with the name and type of the record
+func (c Client) DeleteRecord(record dns.Record) error {
+	hostname := canonicalize(c.hostname(record))
+
+	out, err := c.client.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(c.hostedZoneID),
+		StartRecordName: aws.String(hostname),
+		StartRecordType: aws.String(record.Type.String()),
+		MaxItems:        aws.String("1"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up %s dns record: %w", record.Type, err)
+	}
+
+	// the list starts at the given name and type, so the first set is a different record if ours does not exist
+	if len(out.ResourceRecordSets) == 0 {
+		return nil
+	}
+
+	existing := out.ResourceRecordSets[0]
+	if !strings.EqualFold(unescape(aws.StringValue(existing.Name)), hostname) || aws.StringValue(existing.Type) != record.Type.String() {
+		return nil
+	}
+
+	_, err = c.client.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(c.hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{{
+				Action:            aws.String(route53.ChangeActionDelete),
+				ResourceRecordSet: existing,
+			}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s dns record: %w", record.Type, err)
+	}
+
+	return nil
+}
+
+// ListRecords lists the A, CNAME and TXT records in the hosted zone
+func (c Client) ListRecords() ([]dns.Record, error) {
+	records := make([]dns.Record, 0)
+
+	err := c.client.ListResourceRecordSetsPages(&route53.ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(c.hostedZoneID),
+	}, func(out *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+		for _, set := range out.ResourceRecordSets {
+			recordType, ok := dns.RecordTypeFromString(aws.StringValue(set.Type))
+			if !ok {
+				continue
+			}
+
+			name, ok := dns.RecordName(unescape(aws.StringValue(set.Name)), c.runDomain)
+			if !ok {
+				continue
+			}
+
+			for _, rr := range set.ResourceRecords {
+				value := aws.StringValue(rr.Value)
+				if recordType == dns.RecordType_TXT {
+					value = dns.UnquoteTXT(value)
+				} else {
+					value = strings.TrimSuffix(value, ".")
+				}
+
+				records = append(records, dns.Record{
+					Type:       recordType,
+					Name:       name,
+					RootDomain: c.runDomain,
+					Value:      value,
+				})
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dns records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (c Client) upsertRecord(record dns.Record, value string) error {
+	_, err := c.client.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(c.hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{{
+				Action: aws.String(route53.ChangeActionUpsert),
+				ResourceRecordSet: &route53.ResourceRecordSet{
+					Name: aws.String(canonicalize(c.hostname(record))),
+					Type: aws.String(record.Type.String()),
+					TTL:  aws.Int64(TTL),
+					ResourceRecords: []*route53.ResourceRecord{{
+						Value: aws.String(value),
+					}},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s dns record: %w", record.Type, err)
+	}
+
+	return nil
+}
+
+func (c Client) hostedZoneIDByName(runDomain string) (string, error) {
+	out, err := c.client.ListHostedZonesByName(&route53.ListHostedZonesByNameInput{
+		DNSName: aws.String(canonicalize(runDomain)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up hosted zone for %s: %w", runDomain, err)
+	}
+
+	for _, zone := range out.HostedZones {
+		if strings.EqualFold(aws.StringValue(zone.Name), canonicalize(runDomain)) {
+			return strings.TrimPrefix(aws.StringValue(zone.Id), "/hostedzone/"), nil
+		}
+	}
+
+	return "", fmt.Errorf("no hosted zone found for %s", runDomain)
+}
+
+// hostname returns the fully-qualified name of the record, defaulting to the run domain if the record has no root domain
+func (c Client) hostname(record dns.Record) string {
+	if record.RootDomain == "" {
+		record.RootDomain = c.runDomain
+	}
+
+	return record.Hostname()
+}
+
+func canonicalize(value string) string {
+	if strings.HasSuffix(value, ".") {
+		return value
+	}
+
+	return fmt.Sprintf("%s.", value)
+}
+
+// unescape reverses the octal escaping Route53 applies to wildcard record names
+func unescape(name string) string {
+	return strings.ReplaceAll(name, `\052`, "*")
+}
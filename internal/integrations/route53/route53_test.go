@@ -0,0 +1,208 @@
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+
+	"github.com/porter-dev/porter/internal/integrations/dns"
+)
+
+type resourceRecordSet struct {
+	Name            string   `xml:"Name"`
+	Type            string   `xml:"Type"`
+	TTL             int64    `xml:"TTL"`
+	ResourceRecords []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+type change struct {
+	Action            string            `xml:"Action"`
+	ResourceRecordSet resourceRecordSet `xml:"ResourceRecordSet"`
+}
+
+// fakeAPI is an in-memory implementation of the route53 hosted zone and record set endpoints used by the client
+type fakeAPI struct {
+	mu      sync.Mutex
+	rrsets  []resourceRecordSet
+	changes []change
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/2013-04-01/hostedzonesbyname":
+		fmt.Fprintf(w, `<ListHostedZonesByNameResponse>
+			<HostedZones>
+				<HostedZone><Id>/hostedzone/ZOTHER</Id><Name>other.com.</Name><CallerReference>a</CallerReference></HostedZone>
+				<HostedZone><Id>/hostedzone/Z123</Id><Name>%s</Name><CallerReference>b</CallerReference></HostedZone>
+			</HostedZones>
+			<IsTruncated>false</IsTruncated>
+			<MaxItems>100</MaxItems>
+		</ListHostedZonesByNameResponse>`, r.URL.Query().Get("dnsname"))
+	case r.Method == http.MethodGet && r.URL.Path == "/2013-04-01/hostedzone/Z123/rrset":
+		// record sets are sorted by name and type, and listed from the start name and type if given
+		var rrsets []resourceRecordSet
+		for _, set := range f.rrsets {
+			if name := r.URL.Query().Get("name"); name != "" && set.Name+"/"+set.Type < name+"/"+r.URL.Query().Get("type") {
+				continue
+			}
+			rrsets = append(rrsets, set)
+		}
+		if maxItems := r.URL.Query().Get("maxitems"); maxItems == "1" && len(rrsets) > 1 {
+			rrsets = rrsets[:1]
+		}
+
+		out, err := xml.Marshal(struct {
+			XMLName            xml.Name            `xml:"ListResourceRecordSetsResponse"`
+			ResourceRecordSets []resourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+			IsTruncated        bool                `xml:"IsTruncated"`
+			MaxItems           string              `xml:"MaxItems"`
+		}{ResourceRecordSets: rrsets, MaxItems: "100"})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(out) // nolint:errcheck,gosec
+	case r.Method == http.MethodPost && r.URL.Path == "/2013-04-01/hostedzone/Z123/rrset/":
+		var req struct {
+			Changes []change `xml:"ChangeBatch>Changes>Change"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.changes = append(f.changes, req.Changes...)
+		w.Write([]byte(`<ChangeResourceRecordSetsResponse>
+			<ChangeInfo><Id>/change/C1</Id><Status>PENDING</Status><SubmittedAt>2023-01-01T00:00:00Z</SubmittedAt></ChangeInfo>
+		</ChangeResourceRecordSetsResponse>`)) // nolint:errcheck,gosec
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func newTestClient(t *testing.T, api *fakeAPI) Client {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("access-key", "secret-key", ""),
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	client := Client{runDomain: "example.com", client: route53.New(sess)}
+
+	client.hostedZoneID, err = client.hostedZoneIDByName("example.com")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return client
+}
+
+func TestHostedZoneIDByName(t *testing.T) {
+	client := newTestClient(t, &fakeAPI{})
+
+	if client.hostedZoneID != "Z123" {
+		t.Errorf("expected hosted zone Z123, got %s", client.hostedZoneID)
+	}
+}
+
+func TestCreateTXTRecord(t *testing.T) {
+	api := &fakeAPI{}
+	client := newTestClient(t, api)
+
+	if err := client.CreateTXTRecord(dns.Record{Type: dns.RecordType_TXT, Name: "_porter", Value: "porter-verify"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(api.changes) != 1 {
+		t.Fatalf("expected a single change, got %+v", api.changes)
+	}
+
+	c := api.changes[0]
+	if c.Action != route53.ChangeActionUpsert || c.ResourceRecordSet.Name != "_porter.example.com." || c.ResourceRecordSet.Type != "TXT" || c.ResourceRecordSet.TTL != TTL {
+		t.Errorf("unexpected change %+v", c)
+	}
+	if len(c.ResourceRecordSet.ResourceRecords) != 1 || c.ResourceRecordSet.ResourceRecords[0] != `"porter-verify"` {
+		t.Errorf("expected quoted txt value, got %v", c.ResourceRecordSet.ResourceRecords)
+	}
+}
+
+func TestDeleteRecord(t *testing.T) {
+	api := &fakeAPI{rrsets: []resourceRecordSet{
+		{Name: "app.example.com.", Type: "A", TTL: TTL, ResourceRecords: []string{"10.0.0.1"}},
+		{Name: "www.example.com.", Type: "CNAME", TTL: TTL, ResourceRecords: []string{"lb.example.net"}},
+	}}
+	client := newTestClient(t, api)
+
+	if err := client.DeleteRecord(dns.Record{Type: dns.RecordType_A, Name: "app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(api.changes) != 1 || api.changes[0].Action != route53.ChangeActionDelete {
+		t.Fatalf("expected a single delete, got %+v", api.changes)
+	}
+	if set := api.changes[0].ResourceRecordSet; set.Name != "app.example.com." || set.Type != "A" || set.ResourceRecords[0] != "10.0.0.1" {
+		t.Errorf("expected the existing record set to be deleted, got %+v", set)
+	}
+
+	// the list starts at the next record set if the record does not exist, which must not be deleted
+	if err := client.DeleteRecord(dns.Record{Type: dns.RecordType_CNAME, Name: "api"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.changes) != 1 {
+		t.Errorf("expected no further changes, got %+v", api.changes)
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	api := &fakeAPI{rrsets: []resourceRecordSet{
+		{Name: "example.com.", Type: "NS", ResourceRecords: []string{"ns1.example.com."}},
+		{Name: "example.com.", Type: "TXT", ResourceRecords: []string{`"porter" "-verify"`}},
+		{Name: `\052.example.com.`, Type: "A", ResourceRecords: []string{"10.0.0.1"}},
+		{Name: "www.example.com.", Type: "CNAME", ResourceRecords: []string{"lb.example.net."}},
+	}}
+	client := newTestClient(t, api)
+
+	records, err := client.ListRecords()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []dns.Record{
+		{Type: dns.RecordType_TXT, Name: "@", RootDomain: "example.com", Value: "porter-verify"},
+		{Type: dns.RecordType_A, Name: "*", RootDomain: "example.com", Value: "10.0.0.1"},
+		{Type: dns.RecordType_CNAME, Name: "www", RootDomain: "example.com", Value: "lb.example.net"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected records %+v, got %+v", expected, records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("expected record %+v, got %+v", expected[i], records[i])
+		}
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	if got := unescape(`\052.apps.example.com.`); !strings.HasPrefix(got, "*.") {
+		t.Errorf("expected wildcard name, got %s", got)
+	}
+}
@@ -113,6 +113,7 @@ type CreateDNSRecordConfig struct {
 	ReleaseName string
 	RootDomain  string
 	Endpoint    string
+	ClusterID   uint
 }
 
 // NewDNSRecordForEndpoint generates a random subdomain and returns a DNSRecord
@@ -127,6 +128,7 @@ func (c *CreateDNSRecordConfig) NewDNSRecordForEndpoint() *models.DNSRecord {
 		RootDomain:      c.RootDomain,
 		Endpoint:        c.Endpoint,
 		Hostname:        fmt.Sprintf("%s.%s", subdomain, c.RootDomain),
+		ClusterID:       c.ClusterID,
 	}
 }
 
@@ -146,3 +148,19 @@ func (e *DNSRecord) CreateDomain(dnsClient *dns.Client) error {
 		RootDomain: e.RootDomain,
 	})
 }
+
+// DeleteDomain deletes the record for the vanity domain
+func (e *DNSRecord) DeleteDomain(dnsClient *dns.Client) error {
+	isIPv4 := net.ParseIP(e.Endpoint) != nil
+
+	dnsType := dns.RecordType_CNAME
+	if isIPv4 {
+		dnsType = dns.RecordType_A
+	}
+
+	return dnsClient.DeleteRecord(dns.Record{
+		Type:       dnsType,
+		Name:       e.SubdomainPrefix,
+		RootDomain: e.RootDomain,
+	})
+}
@@ -68,5 +68,10 @@ func DeleteEnvironmentGroup(ctx context.Context, a *kubernetes.Agent, name strin
 		}
 	}
 
+	err = SetExternalSecrets(ctx, a, name, ExternalSecrets{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to delete environment group external secrets")
+	}
+
 	return nil
 }
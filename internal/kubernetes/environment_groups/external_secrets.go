@@ -0,0 +1,466 @@
+package environment_groups
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/telemetry"
+	v1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// LabelKey_ExternalSecretsEnvironmentGroup is the label key of the configmap which stores the external secret references of an environment group,
+	// and of the ExternalSecrets which resolve them in target namespaces
+	LabelKey_ExternalSecretsEnvironmentGroup = "porter.run/external-secrets-environment-group"
+
+	// AnnotationKey_ExternalSecretsHash is the annotation key storing the hash of the external secret references which are resolved into a synced secret
+	AnnotationKey_ExternalSecretsHash = "porter.run/external-secrets-hash"
+
+	// externalSecretsConfigMapKey is the key of the configmap data which holds the JSON-encoded external secrets
+	externalSecretsConfigMapKey = "external_secrets"
+)
+
+// ExternalSecretProvider is the secret store which holds an external secret
+type ExternalSecretProvider string
+
+const (
+	ExternalSecretProvider_Vault             ExternalSecretProvider = "vault"
+	ExternalSecretProvider_AWSSecretsManager ExternalSecretProvider = "awssecretsmanager"
+	ExternalSecretProvider_GCPSecretManager  ExternalSecretProvider = "gcpsecretmanager"
+)
+
+// ExternalSecretReference points to a secret value which is stored outside of Porter. Only the reference is stored in the
+// environment group: the value is read inside the cluster by the External Secrets Operator, and never passes through Porter.
+type ExternalSecretReference struct {
+	// Provider is the secret store which holds the secret
+	Provider ExternalSecretProvider `json:"provider"`
+	// Path is the path of a Vault secret in the mount of the secret store (such as my-app), the name or ARN of an AWS secret, or the name of a GCP secret
+	Path string `json:"path"`
+	// Key selects a key of a secret which holds a JSON object
+	Key string `json:"key,omitempty"`
+	// Version pins the version of the secret. If empty, the latest version is used and is refreshed after the refresh interval
+	Version string `json:"version,omitempty"`
+}
+
+// ExternalSecrets are the external secret references of an environment group
+type ExternalSecrets struct {
+	// References maps the names of the environment variables to their secrets
+	References map[string]ExternalSecretReference `json:"references"`
+	// RefreshInterval is the duration after which the External Secrets Operator reads the secrets again. If empty, secrets are only read
+	// when the references or the environment group version change
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+}
+
+// Validate checks that the external secrets are well-formed
+func (e ExternalSecrets) Validate() error {
+	if e.RefreshInterval != "" {
+		interval, err := time.ParseDuration(e.RefreshInterval)
+		if err != nil {
+			return fmt.Errorf("invalid refresh interval %s: %w", e.RefreshInterval, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("refresh interval must be positive")
+		}
+	}
+
+	for name, ref := range e.References {
+		if name == "" {
+			return fmt.Errorf("external secret variable name cannot be empty")
+		}
+
+		switch ref.Provider {
+		case ExternalSecretProvider_Vault, ExternalSecretProvider_AWSSecretsManager, ExternalSecretProvider_GCPSecretManager:
+		default:
+			return fmt.Errorf("unknown provider %s for external secret %s", ref.Provider, name)
+		}
+
+		if ref.Path == "" {
+			return fmt.Errorf("external secret %s must have a path", name)
+		}
+	}
+
+	return nil
+}
+
+// providers returns the sorted providers of the references
+func (e ExternalSecrets) providers() []ExternalSecretProvider {
+	seen := make(map[ExternalSecretProvider]bool)
+	var providers []ExternalSecretProvider
+
+	for _, ref := range e.References {
+		if !seen[ref.Provider] {
+			seen[ref.Provider] = true
+			providers = append(providers, ref.Provider)
+		}
+	}
+
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+
+	return providers
+}
+
+// hash returns a hash of the references and refresh interval, which changes whenever either of them changes
+func (e ExternalSecrets) hash() (string, error) {
+	names := make([]string, 0, len(e.References))
+	for name := range e.References {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		refBytes, err := json.Marshal(e.References[name])
+		if err != nil {
+			return "", err
+		}
+
+		h.Write([]byte(name))
+		h.Write(refBytes)
+	}
+	h.Write([]byte(e.RefreshInterval))
+
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// ExternalSecretStoreName returns the name of the ClusterSecretStore of the External Secrets Operator which reads secrets from the given provider.
+// The store is set up in the cluster with the credentials of the project which owns the cluster, so Porter never reads the secrets itself.
+func ExternalSecretStoreName(provider ExternalSecretProvider) string {
+	return fmt.Sprintf("porter-%s", provider)
+}
+
+var (
+	externalSecretGVR     = schema.GroupVersionResource{Group: "external-secrets.io", Version: "v1beta1", Resource: "externalsecrets"}
+	clusterSecretStoreGVR = schema.GroupVersionResource{Group: "external-secrets.io", Version: "v1beta1", Resource: "clustersecretstores"}
+)
+
+// ErrExternalSecretStoreNotFound is returned if external secrets reference a provider which has no secret store in the cluster
+var ErrExternalSecretStoreNotFound = errors.New("cluster has no secret store for external secret provider")
+
+// newExternalSecretsClient returns the client which manages the resources of the External Secrets Operator. Tests replace it with a fake.
+var newExternalSecretsClient = func(a *kubernetes.Agent) (dynamic.Interface, error) {
+	restConf, err := a.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(restConf)
+}
+
+func externalSecretsConfigMapName(environmentGroupName string) string {
+	return fmt.Sprintf("%s.external-secrets", environmentGroupName)
+}
+
+// SetExternalSecrets stores the external secret references of an environment group in the porter-env-group namespace. The references
+// apply to every version of the environment group, and are removed if there are none. The cluster must have a secret store for each
+// referenced provider.
+func SetExternalSecrets(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, externalSecrets ExternalSecrets) error {
+	ctx, span := telemetry.NewSpan(ctx, "set-environment-group-external-secrets")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName},
+		telemetry.AttributeKV{Key: "external-secrets", Value: len(externalSecrets.References)},
+	)
+
+	if environmentGroupName == "" {
+		return telemetry.Error(ctx, span, nil, "environment group name cannot be empty")
+	}
+
+	if err := externalSecrets.Validate(); err != nil {
+		return telemetry.Error(ctx, span, err, "invalid external secrets")
+	}
+
+	configMaps := a.Clientset.CoreV1().ConfigMaps(Namespace_EnvironmentGroups)
+	name := externalSecretsConfigMapName(environmentGroupName)
+
+	if len(externalSecrets.References) == 0 {
+		err := configMaps.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !k8serror.IsNotFound(err) {
+			return telemetry.Error(ctx, span, err, "unable to delete external secrets")
+		}
+
+		return nil
+	}
+
+	client, err := newExternalSecretsClient(a)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to create external secrets client")
+	}
+
+	for _, provider := range externalSecrets.providers() {
+		_, err := client.Resource(clusterSecretStoreGVR).Get(ctx, ExternalSecretStoreName(provider), metav1.GetOptions{})
+		if err != nil {
+			telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "external-secret-provider", Value: string(provider)})
+
+			if k8serror.IsNotFound(err) || meta.IsNoMatchError(err) {
+				return telemetry.Error(ctx, span, fmt.Errorf("%w %s: create ClusterSecretStore %s", ErrExternalSecretStoreNotFound, provider, ExternalSecretStoreName(provider)), "secret store not found")
+			}
+
+			return telemetry.Error(ctx, span, err, "unable to get secret store")
+		}
+	}
+
+	externalSecretsBytes, err := json.Marshal(externalSecrets)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to marshal external secrets")
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: Namespace_EnvironmentGroups,
+			Labels: map[string]string{
+				LabelKey_ExternalSecretsEnvironmentGroup: environmentGroupName,
+				LabelKey_PorterManaged:                   "true",
+			},
+		},
+		Data: map[string]string{
+			externalSecretsConfigMapKey: string(externalSecretsBytes),
+		},
+	}
+
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !k8serror.IsNotFound(err) {
+		return telemetry.Error(ctx, span, err, "unable to update external secrets")
+	}
+
+	_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to create external secrets")
+	}
+
+	return nil
+}
+
+// ExternalSecretsForEnvironmentGroup returns the external secret references of an environment group. The references do not contain any secret values
+// and can be returned to the user.
+func ExternalSecretsForEnvironmentGroup(ctx context.Context, a *kubernetes.Agent, environmentGroupName string) (ExternalSecrets, error) {
+	ctx, span := telemetry.NewSpan(ctx, "get-environment-group-external-secrets")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName})
+
+	var externalSecrets ExternalSecrets
+
+	configMap, err := a.Clientset.CoreV1().ConfigMaps(Namespace_EnvironmentGroups).Get(ctx, externalSecretsConfigMapName(environmentGroupName), metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return externalSecrets, nil
+		}
+
+		return externalSecrets, telemetry.Error(ctx, span, err, "unable to get external secrets")
+	}
+
+	if err := json.Unmarshal([]byte(configMap.Data[externalSecretsConfigMapKey]), &externalSecrets); err != nil {
+		return externalSecrets, telemetry.Error(ctx, span, err, "unable to unmarshal external secrets")
+	}
+
+	return externalSecrets, nil
+}
+
+// syncExternalSecretsToNamespace creates an ExternalSecret for each provider of the external secrets of an environment group, which the External Secrets
+// Operator resolves into the environment group secret in the target namespace, and refreshes on the refresh interval. ExternalSecrets are only written if
+// the references changed since the last sync, and are owned by the secret so that they are removed with it.
+func syncExternalSecretsToNamespace(ctx context.Context, a *kubernetes.Agent, environmentGroup EnvironmentGroup, targetNamespace string) error {
+	ctx, span := telemetry.NewSpan(ctx, "sync-environment-group-external-secrets")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroup.Name},
+		telemetry.AttributeKV{Key: "environment-group-version", Value: environmentGroup.Version},
+		telemetry.AttributeKV{Key: "target-namespace", Value: targetNamespace},
+	)
+
+	externalSecrets, err := ExternalSecretsForEnvironmentGroup(ctx, a, environmentGroup.Name)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to get external secrets")
+	}
+
+	secretName := fmt.Sprintf("%s.%d", environmentGroup.Name, environmentGroup.Version)
+
+	secret, err := a.Clientset.CoreV1().Secrets(targetNamespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to get environment group secret in target namespace")
+	}
+
+	referencesHash := ""
+	if len(externalSecrets.References) > 0 {
+		referencesHash, err = externalSecrets.hash()
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "unable to hash external secrets")
+		}
+	}
+
+	if secret.Annotations[AnnotationKey_ExternalSecretsHash] == referencesHash {
+		return nil
+	}
+
+	client, err := newExternalSecretsClient(a)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to create external secrets client")
+	}
+
+	externalSecretClient := client.Resource(externalSecretGVR).Namespace(targetNamespace)
+
+	desired := make(map[string]bool)
+	for _, provider := range externalSecrets.providers() {
+		externalSecret := newExternalSecret(secret, environmentGroup, provider, externalSecrets)
+		desired[externalSecret.GetName()] = true
+
+		existing, err := externalSecretClient.Get(ctx, externalSecret.GetName(), metav1.GetOptions{})
+		if err != nil && !k8serror.IsNotFound(err) {
+			return telemetry.Error(ctx, span, err, "unable to get external secret in target namespace")
+		}
+
+		if err == nil {
+			externalSecret.SetResourceVersion(existing.GetResourceVersion())
+
+			_, err = externalSecretClient.Update(ctx, externalSecret, metav1.UpdateOptions{})
+			if err != nil {
+				return telemetry.Error(ctx, span, err, "unable to update external secret in target namespace")
+			}
+
+			continue
+		}
+
+		_, err = externalSecretClient.Create(ctx, externalSecret, metav1.CreateOptions{})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "unable to create external secret in target namespace")
+		}
+	}
+
+	// remove the ExternalSecrets of providers which are no longer referenced by this version
+	existing, err := externalSecretClient.List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%d", LabelKey_ExternalSecretsEnvironmentGroup, environmentGroup.Name, LabelKey_EnvironmentGroupVersion, environmentGroup.Version),
+	})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return telemetry.Error(ctx, span, err, "unable to list external secrets in target namespace")
+	}
+	if existing != nil {
+		for _, externalSecret := range existing.Items {
+			if desired[externalSecret.GetName()] {
+				continue
+			}
+
+			err := externalSecretClient.Delete(ctx, externalSecret.GetName(), metav1.DeleteOptions{})
+			if err != nil && !k8serror.IsNotFound(err) {
+				return telemetry.Error(ctx, span, err, "unable to delete external secret in target namespace")
+			}
+		}
+	}
+
+	// the operator merges values into the secret and does not remove them, so the data is rebuilt from the environment group
+	// to drop the values of removed references. The operator writes the remaining values again.
+	data := make(map[string][]byte)
+	for k, v := range environmentGroup.SecretVariables {
+		data[k] = []byte(v)
+	}
+	secret.Data = data
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	if referencesHash == "" {
+		delete(secret.Annotations, AnnotationKey_ExternalSecretsHash)
+	} else {
+		secret.Annotations[AnnotationKey_ExternalSecretsHash] = referencesHash
+	}
+
+	_, err = a.Clientset.CoreV1().Secrets(targetNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "unable to update environment group secret in target namespace")
+	}
+
+	return nil
+}
+
+// newExternalSecret returns the ExternalSecret which merges the secrets of the given provider into the environment group secret
+func newExternalSecret(secret *v1.Secret, environmentGroup EnvironmentGroup, provider ExternalSecretProvider, externalSecrets ExternalSecrets) *unstructured.Unstructured {
+	names := make([]string, 0, len(externalSecrets.References))
+	for name, ref := range externalSecrets.References {
+		if ref.Provider == provider {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	data := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		ref := externalSecrets.References[name]
+
+		remoteRef := map[string]interface{}{
+			"key": ref.Path,
+		}
+		if ref.Key != "" {
+			remoteRef["property"] = ref.Key
+		}
+		if ref.Version != "" {
+			remoteRef["version"] = ref.Version
+		}
+
+		data = append(data, map[string]interface{}{
+			"secretKey": name,
+			"remoteRef": remoteRef,
+		})
+	}
+
+	// a refresh interval of 0 makes the operator read the secrets only when the ExternalSecret changes
+	refreshInterval := "0"
+	if externalSecrets.RefreshInterval != "" {
+		refreshInterval = externalSecrets.RefreshInterval
+	}
+
+	externalSecret := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": externalSecretGVR.GroupVersion().String(),
+			"kind":       "ExternalSecret",
+			"spec": map[string]interface{}{
+				"refreshInterval": refreshInterval,
+				"secretStoreRef": map[string]interface{}{
+					"kind": "ClusterSecretStore",
+					"name": ExternalSecretStoreName(provider),
+				},
+				"target": map[string]interface{}{
+					"name":           secret.Name,
+					"creationPolicy": "Merge",
+				},
+				"data": data,
+			},
+		},
+	}
+
+	externalSecret.SetName(fmt.Sprintf("%s.%s", secret.Name, provider))
+	externalSecret.SetNamespace(secret.Namespace)
+	externalSecret.SetLabels(map[string]string{
+		LabelKey_ExternalSecretsEnvironmentGroup: environmentGroup.Name,
+		LabelKey_EnvironmentGroupVersion:         strconv.Itoa(environmentGroup.Version),
+		LabelKey_PorterManaged:                   "true",
+	})
+	externalSecret.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Name:       secret.Name,
+			UID:        secret.UID,
+		},
+	})
+
+	return externalSecret
+}
@@ -0,0 +1,263 @@
+package environment_groups
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// useFakeExternalSecretsClient replaces the external secrets client with a fake which has a secret store for each of the given providers
+func useFakeExternalSecretsClient(t *testing.T, providers ...ExternalSecretProvider) *dynamicfake.FakeDynamicClient {
+	t.Helper()
+
+	var stores []runtime.Object
+	for _, provider := range providers {
+		store := &unstructured.Unstructured{}
+		store.SetAPIVersion(clusterSecretStoreGVR.GroupVersion().String())
+		store.SetKind("ClusterSecretStore")
+		store.SetName(ExternalSecretStoreName(provider))
+		stores = append(stores, store)
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		externalSecretGVR:     "ExternalSecretList",
+		clusterSecretStoreGVR: "ClusterSecretStoreList",
+	}, stores...)
+
+	previous := newExternalSecretsClient
+	newExternalSecretsClient = func(a *kubernetes.Agent) (dynamic.Interface, error) {
+		return client, nil
+	}
+	t.Cleanup(func() { newExternalSecretsClient = previous })
+
+	return client
+}
+
+func externalSecretsInAppNamespace(t *testing.T, client dynamic.Interface) map[string]*unstructured.Unstructured {
+	t.Helper()
+
+	list, err := client.Resource(externalSecretGVR).Namespace("app-ns").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unable to list external secrets: %v", err)
+	}
+
+	externalSecrets := make(map[string]*unstructured.Unstructured)
+	for i := range list.Items {
+		externalSecrets[list.Items[i].GetName()] = &list.Items[i]
+	}
+
+	return externalSecrets
+}
+
+func newExternalSecretsTestAgent(t *testing.T) *kubernetes.Agent {
+	t.Helper()
+
+	a := &kubernetes.Agent{Clientset: fake.NewSimpleClientset()}
+
+	err := CreateOrUpdateBaseEnvironmentGroup(context.Background(), a, EnvironmentGroup{
+		Name:            "my-env",
+		Variables:       map[string]string{"PLAIN": "plain"},
+		SecretVariables: map[string]string{"SECRET": "secret"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to create environment group: %v", err)
+	}
+
+	return a
+}
+
+func syncedSecretData(t *testing.T, a *kubernetes.Agent) map[string]string {
+	t.Helper()
+
+	secret, err := a.Clientset.CoreV1().Secrets("app-ns").Get(context.Background(), "my-env.1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get synced secret: %v", err)
+	}
+
+	data := make(map[string]string)
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+
+	return data
+}
+
+func syncToAppNamespace(t *testing.T, a *kubernetes.Agent) {
+	t.Helper()
+
+	_, err := SyncLatestVersionToNamespace(context.Background(), a, SyncLatestVersionToNamespaceInput{
+		BaseEnvironmentGroupName: "my-env",
+		TargetNamespace:          "app-ns",
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to sync environment group: %v", err)
+	}
+}
+
+func TestSyncCreatesExternalSecrets(t *testing.T) {
+	ctx := context.Background()
+	a := newExternalSecretsTestAgent(t)
+	client := useFakeExternalSecretsClient(t, ExternalSecretProvider_Vault, ExternalSecretProvider_AWSSecretsManager)
+
+	err := SetExternalSecrets(ctx, a, "my-env", ExternalSecrets{
+		RefreshInterval: "1h",
+		References: map[string]ExternalSecretReference{
+			"DB_PASSWORD": {Provider: ExternalSecretProvider_Vault, Path: "db", Key: "password"},
+			"API_KEY":     {Provider: ExternalSecretProvider_AWSSecretsManager, Path: "api-key", Version: "3"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to set external secrets: %v", err)
+	}
+
+	syncToAppNamespace(t, a)
+
+	externalSecrets := externalSecretsInAppNamespace(t, client)
+	if len(externalSecrets) != 2 {
+		t.Fatalf("expected an external secret per provider, got %d", len(externalSecrets))
+	}
+
+	vault, ok := externalSecrets["my-env.1.vault"]
+	if !ok {
+		t.Fatalf("expected vault external secret, got %v", externalSecrets)
+	}
+
+	storeName, _, _ := unstructured.NestedString(vault.Object, "spec", "secretStoreRef", "name")
+	if storeName != "porter-vault" {
+		t.Errorf("expected store porter-vault, got %s", storeName)
+	}
+
+	targetName, _, _ := unstructured.NestedString(vault.Object, "spec", "target", "name")
+	creationPolicy, _, _ := unstructured.NestedString(vault.Object, "spec", "target", "creationPolicy")
+	if targetName != "my-env.1" || creationPolicy != "Merge" {
+		t.Errorf("expected values to be merged into my-env.1, got %s with policy %s", targetName, creationPolicy)
+	}
+
+	refreshInterval, _, _ := unstructured.NestedString(vault.Object, "spec", "refreshInterval")
+	if refreshInterval != "1h" {
+		t.Errorf("expected refresh interval 1h, got %s", refreshInterval)
+	}
+
+	data, _, _ := unstructured.NestedSlice(vault.Object, "spec", "data")
+	if len(data) != 1 {
+		t.Fatalf("expected 1 vault secret, got %v", data)
+	}
+	secretKey, _, _ := unstructured.NestedString(data[0].(map[string]interface{}), "secretKey")
+	property, _, _ := unstructured.NestedString(data[0].(map[string]interface{}), "remoteRef", "property")
+	if secretKey != "DB_PASSWORD" || property != "password" {
+		t.Errorf("unexpected vault secret data: %v", data[0])
+	}
+
+	aws := externalSecrets["my-env.1.awssecretsmanager"]
+	awsData, _, _ := unstructured.NestedSlice(aws.Object, "spec", "data")
+	version, _, _ := unstructured.NestedString(awsData[0].(map[string]interface{}), "remoteRef", "version")
+	if version != "3" {
+		t.Errorf("expected pinned version 3, got %s", version)
+	}
+
+	// no values are written to the secret by porter
+	secretData := syncedSecretData(t, a)
+	if _, ok := secretData["DB_PASSWORD"]; ok || secretData["SECRET"] != "secret" {
+		t.Fatalf("unexpected synced secret data: %v", secretData)
+	}
+
+	// removed providers and references are dropped
+	err = SetExternalSecrets(ctx, a, "my-env", ExternalSecrets{
+		References: map[string]ExternalSecretReference{
+			"DB_PASSWORD": {Provider: ExternalSecretProvider_Vault, Path: "db", Key: "password"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to set external secrets: %v", err)
+	}
+
+	syncToAppNamespace(t, a)
+
+	externalSecrets = externalSecretsInAppNamespace(t, client)
+	if _, ok := externalSecrets["my-env.1.awssecretsmanager"]; ok || len(externalSecrets) != 1 {
+		t.Fatalf("expected only the vault external secret, got %v", externalSecrets)
+	}
+
+	refreshInterval, _, _ = unstructured.NestedString(externalSecrets["my-env.1.vault"].Object, "spec", "refreshInterval")
+	if refreshInterval != "0" {
+		t.Errorf("expected secrets without a refresh interval to be read once, got %s", refreshInterval)
+	}
+
+	err = SetExternalSecrets(ctx, a, "my-env", ExternalSecrets{})
+	if err != nil {
+		t.Fatalf("unable to remove external secrets: %v", err)
+	}
+
+	syncToAppNamespace(t, a)
+
+	if externalSecrets := externalSecretsInAppNamespace(t, client); len(externalSecrets) != 0 {
+		t.Fatalf("expected external secrets to be removed, got %v", externalSecrets)
+	}
+
+	secret, err := a.Clientset.CoreV1().Secrets("app-ns").Get(ctx, "my-env.1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get synced secret: %v", err)
+	}
+	if _, ok := secret.Annotations[AnnotationKey_ExternalSecretsHash]; ok {
+		t.Fatalf("expected external secrets hash to be removed")
+	}
+}
+
+func TestSetExternalSecretsWithoutSecretStore(t *testing.T) {
+	ctx := context.Background()
+	a := newExternalSecretsTestAgent(t)
+	useFakeExternalSecretsClient(t, ExternalSecretProvider_Vault)
+
+	err := SetExternalSecrets(ctx, a, "my-env", ExternalSecrets{
+		References: map[string]ExternalSecretReference{
+			"DB_PASSWORD": {Provider: ExternalSecretProvider_AWSSecretsManager, Path: "arn:aws:secretsmanager:us-east-1:123456789012:secret:db"},
+		},
+	})
+	if !errors.Is(err, ErrExternalSecretStoreNotFound) {
+		t.Fatalf("expected secret store not found error, got %v", err)
+	}
+
+	externalSecrets, err := ExternalSecretsForEnvironmentGroup(ctx, a, "my-env")
+	if err != nil {
+		t.Fatalf("unable to get external secrets: %v", err)
+	}
+	if len(externalSecrets.References) != 0 {
+		t.Fatalf("expected no external secrets to be stored, got %v", externalSecrets.References)
+	}
+}
+
+func TestSyncWithoutExternalSecretsSkipsClient(t *testing.T) {
+	a := newExternalSecretsTestAgent(t)
+
+	previous := newExternalSecretsClient
+	newExternalSecretsClient = func(a *kubernetes.Agent) (dynamic.Interface, error) {
+		return nil, errors.New("external secrets client should not be created")
+	}
+	defer func() { newExternalSecretsClient = previous }()
+
+	syncToAppNamespace(t, a)
+}
+
+func TestExternalSecretsValidate(t *testing.T) {
+	invalid := []ExternalSecrets{
+		{RefreshInterval: "soon"},
+		{RefreshInterval: "-1h"},
+		{References: map[string]ExternalSecretReference{"A": {Provider: "keepass", Path: "a"}}},
+		{References: map[string]ExternalSecretReference{"A": {Provider: ExternalSecretProvider_GCPSecretManager}}},
+	}
+
+	for _, secrets := range invalid {
+		if err := secrets.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", secrets)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package environment_groups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/telemetry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnvironmentGroupVersions returns all versions of an environment group stored in the porter-env-group namespace, from newest to oldest.
+// It replaces all secret values with a dummy variable and can be used to return values to the user.
+func EnvironmentGroupVersions(ctx context.Context, a *kubernetes.Agent, environmentGroupName string) ([]EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "list-environment-group-versions")
+	defer span.End()
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName})
+
+	if environmentGroupName == "" {
+		return nil, telemetry.Error(ctx, span, nil, "environment group name cannot be empty")
+	}
+
+	versions, err := ListEnvironmentGroups(ctx, a, WithEnvironmentGroupName(environmentGroupName), WithNamespace(Namespace_EnvironmentGroups))
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "unable to list environment group versions")
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// baseEnvironmentGroupVersion returns a version of an environment group stored in the porter-env-group namespace.
+// This is a private function because it returns all secret values.
+func baseEnvironmentGroupVersion(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, version int) (EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "get-environment-group-version-private")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName},
+		telemetry.AttributeKV{Key: "environment-group-version", Value: version},
+	)
+
+	var eg EnvironmentGroup
+
+	if version <= 0 {
+		return eg, telemetry.Error(ctx, span, nil, "environment group version must be positive")
+	}
+
+	environmentGroups, err := listEnvironmentGroups(ctx, a,
+		WithEnvironmentGroupName(environmentGroupName),
+		WithEnvironmentGroupVersion(version),
+		WithNamespace(Namespace_EnvironmentGroups),
+	)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to list environment group version")
+	}
+
+	if len(environmentGroups) != 1 {
+		return eg, telemetry.Error(ctx, span, nil, fmt.Sprintf("version %d of environment group %s does not exist", version, environmentGroupName))
+	}
+
+	return environmentGroups[0], nil
+}
+
+// EnvironmentGroupVariableChange is a variable which differs between two versions of an environment group
+type EnvironmentGroupVariableChange struct {
+	// Key is the name of the variable
+	Key string `json:"key"`
+	// Secret is true if the variable is a secret variable, in which case its values are replaced with a dummy value
+	Secret bool `json:"secret"`
+	// OldValue is the value in the older version, and is empty if the variable was added
+	OldValue string `json:"old_value,omitempty"`
+	// NewValue is the value in the newer version, and is empty if the variable was removed
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// EnvironmentGroupDiff contains the variables which were added, removed or changed between two versions of an environment group
+type EnvironmentGroupDiff struct {
+	FromVersion int                              `json:"from_version"`
+	ToVersion   int                              `json:"to_version"`
+	Added       []EnvironmentGroupVariableChange `json:"added"`
+	Removed     []EnvironmentGroupVariableChange `json:"removed"`
+	Changed     []EnvironmentGroupVariableChange `json:"changed"`
+}
+
+// DiffEnvironmentGroupVersions compares two versions of an environment group. Secret values are compared, but are replaced with a dummy value
+// in the result so that the diff can be returned to the user.
+func DiffEnvironmentGroupVersions(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, fromVersion int, toVersion int) (EnvironmentGroupDiff, error) {
+	ctx, span := telemetry.NewSpan(ctx, "diff-environment-group-versions")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName},
+		telemetry.AttributeKV{Key: "from-version", Value: fromVersion},
+		telemetry.AttributeKV{Key: "to-version", Value: toVersion},
+	)
+
+	diff := EnvironmentGroupDiff{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Added:       []EnvironmentGroupVariableChange{},
+		Removed:     []EnvironmentGroupVariableChange{},
+		Changed:     []EnvironmentGroupVariableChange{},
+	}
+
+	from, err := baseEnvironmentGroupVersion(ctx, a, environmentGroupName, fromVersion)
+	if err != nil {
+		return diff, telemetry.Error(ctx, span, err, "unable to get environment group version to diff from")
+	}
+
+	to, err := baseEnvironmentGroupVersion(ctx, a, environmentGroupName, toVersion)
+	if err != nil {
+		return diff, telemetry.Error(ctx, span, err, "unable to get environment group version to diff to")
+	}
+
+	diffVariables(&diff, from.Variables, to.Variables, false)
+	diffVariables(&diff, from.SecretVariables, to.SecretVariables, true)
+
+	for _, changes := range [][]EnvironmentGroupVariableChange{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].Key == changes[j].Key {
+				return !changes[i].Secret
+			}
+			return changes[i].Key < changes[j].Key
+		})
+	}
+
+	return diff, nil
+}
+
+func diffVariables(diff *EnvironmentGroupDiff, from map[string]string, to map[string]string, secret bool) {
+	redact := func(value string) string {
+		if secret {
+			return EnvGroupSecretDummyValue
+		}
+		return value
+	}
+
+	for key, oldValue := range from {
+		newValue, ok := to[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, EnvironmentGroupVariableChange{Key: key, Secret: secret, OldValue: redact(oldValue)})
+			continue
+		}
+
+		if oldValue != newValue {
+			diff.Changed = append(diff.Changed, EnvironmentGroupVariableChange{Key: key, Secret: secret, OldValue: redact(oldValue), NewValue: redact(newValue)})
+		}
+	}
+
+	for key, newValue := range to {
+		if _, ok := from[key]; !ok {
+			diff.Added = append(diff.Added, EnvironmentGroupVariableChange{Key: key, Secret: secret, NewValue: redact(newValue)})
+		}
+	}
+}
+
+// RollbackEnvironmentGroup restores the variables of a previous version of an environment group as a new version, so that the history of
+// the environment group is kept. Apps linked to the environment group must be redeployed to use the new version. The new version is returned,
+// with all secret values replaced by a dummy value.
+func RollbackEnvironmentGroup(ctx context.Context, a *kubernetes.Agent, environmentGroupName string, version int) (EnvironmentGroup, error) {
+	ctx, span := telemetry.NewSpan(ctx, "rollback-environment-group")
+	defer span.End()
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "environment-group-name", Value: environmentGroupName},
+		telemetry.AttributeKV{Key: "rollback-version", Value: version},
+	)
+
+	var eg EnvironmentGroup
+
+	target, err := baseEnvironmentGroupVersion(ctx, a, environmentGroupName, version)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to get environment group version to roll back to")
+	}
+
+	latest, err := latestBaseEnvironmentGroup(ctx, a, environmentGroupName)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to get latest environment group version")
+	}
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "latest-version", Value: latest.Version})
+
+	if latest.Version == version {
+		return eg, telemetry.Error(ctx, span, nil, "cannot roll back to the latest version of an environment group")
+	}
+
+	// carry over labels such as the type of the environment group, which are not part of its variables
+	latestConfigMap, err := a.Clientset.CoreV1().ConfigMaps(Namespace_EnvironmentGroups).Get(ctx, fmt.Sprintf("%s.%d", latest.Name, latest.Version), metav1.GetOptions{})
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to get latest environment group configmap")
+	}
+
+	additionalLabels := make(map[string]string)
+	for k, v := range latestConfigMap.Labels {
+		if k == LabelKey_EnvironmentGroupName || k == LabelKey_EnvironmentGroupVersion {
+			continue
+		}
+		additionalLabels[k] = v
+	}
+
+	newEnvironmentGroup := EnvironmentGroup{
+		Name:            target.Name,
+		Variables:       target.Variables,
+		SecretVariables: target.SecretVariables,
+		Version:         latest.Version + 1,
+	}
+
+	err = createVersionedEnvironmentGroupInNamespace(ctx, a, newEnvironmentGroup, Namespace_EnvironmentGroups, additionalLabels)
+	if err != nil {
+		return eg, telemetry.Error(ctx, span, err, "unable to create rolled back environment group version")
+	}
+
+	restored, err := EnvironmentGroupVersions(ctx, a, environmentGroupName)
+	if err != nil || len(restored) == 0 {
+		return eg, telemetry.Error(ctx, span, err, "unable to get rolled back environment group version")
+	}
+
+	return restored[0], nil
+}
@@ -0,0 +1,112 @@
+package environment_groups
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newHistoryTestAgent(t *testing.T) *kubernetes.Agent {
+	t.Helper()
+
+	ctx := context.Background()
+	a := &kubernetes.Agent{Clientset: fake.NewSimpleClientset()}
+
+	versions := []EnvironmentGroup{
+		{
+			Name:            "my-env",
+			Variables:       map[string]string{"A": "1", "B": "2"},
+			SecretVariables: map[string]string{"PASSWORD": "hunter2", "TOKEN": "abc"},
+		},
+		{
+			Name:            "my-env",
+			Variables:       map[string]string{"A": "1", "B": "3", "C": "4"},
+			SecretVariables: map[string]string{"PASSWORD": "hunter3"},
+		},
+	}
+
+	for _, version := range versions {
+		if err := CreateOrUpdateBaseEnvironmentGroup(ctx, a, version, nil); err != nil {
+			t.Fatalf("unable to create environment group: %v", err)
+		}
+	}
+
+	return a
+}
+
+func TestEnvironmentGroupVersions(t *testing.T) {
+	a := newHistoryTestAgent(t)
+
+	versions, err := EnvironmentGroupVersions(context.Background(), a, "my-env")
+	if err != nil {
+		t.Fatalf("unable to list versions: %v", err)
+	}
+
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 1 {
+		t.Fatalf("expected versions 2 and 1, got %+v", versions)
+	}
+
+	if versions[0].SecretVariables["PASSWORD"] != EnvGroupSecretDummyValue {
+		t.Fatalf("secret value was not redacted")
+	}
+}
+
+func TestDiffEnvironmentGroupVersions(t *testing.T) {
+	a := newHistoryTestAgent(t)
+
+	diff, err := DiffEnvironmentGroupVersions(context.Background(), a, "my-env", 1, 2)
+	if err != nil {
+		t.Fatalf("unable to diff versions: %v", err)
+	}
+
+	if len(diff.Added) != 1 || diff.Added[0].Key != "C" || diff.Added[0].NewValue != "4" {
+		t.Errorf("unexpected added variables: %+v", diff.Added)
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0].Key != "TOKEN" || diff.Removed[0].OldValue != EnvGroupSecretDummyValue {
+		t.Errorf("unexpected removed variables: %+v", diff.Removed)
+	}
+
+	if len(diff.Changed) != 2 {
+		t.Fatalf("expected 2 changed variables, got %+v", diff.Changed)
+	}
+	if diff.Changed[0].Key != "B" || diff.Changed[0].OldValue != "2" || diff.Changed[0].NewValue != "3" {
+		t.Errorf("unexpected changed variable: %+v", diff.Changed[0])
+	}
+	if diff.Changed[1].Key != "PASSWORD" || !diff.Changed[1].Secret || diff.Changed[1].NewValue != EnvGroupSecretDummyValue {
+		t.Errorf("unexpected changed secret: %+v", diff.Changed[1])
+	}
+
+	if _, err := DiffEnvironmentGroupVersions(context.Background(), a, "my-env", 1, 5); err == nil {
+		t.Errorf("expected an error for a missing version")
+	}
+}
+
+func TestRollbackEnvironmentGroup(t *testing.T) {
+	ctx := context.Background()
+	a := newHistoryTestAgent(t)
+
+	restored, err := RollbackEnvironmentGroup(ctx, a, "my-env", 1)
+	if err != nil {
+		t.Fatalf("unable to roll back: %v", err)
+	}
+
+	if restored.Version != 3 {
+		t.Fatalf("expected rollback to create version 3, got %d", restored.Version)
+	}
+
+	latest, err := latestBaseEnvironmentGroup(ctx, a, "my-env")
+	if err != nil {
+		t.Fatalf("unable to get latest version: %v", err)
+	}
+
+	if latest.Version != 3 || latest.Variables["B"] != "2" || latest.SecretVariables["TOKEN"] != "abc" || latest.SecretVariables["PASSWORD"] != "hunter2" {
+		t.Fatalf("unexpected restored version: %+v", latest)
+	}
+
+	if _, err := RollbackEnvironmentGroup(ctx, a, "my-env", 3); err == nil {
+		t.Errorf("expected an error when rolling back to the latest version")
+	}
+}
@@ -25,7 +25,8 @@ type SyncLatestVersionToNamespaceOutput struct {
 
 // SyncLatestVersionToNamespace gets the latest version of a given environment group, and makes a copy of it in the target
 // namespace. If the versions match, no changes will be made. In either case, the name of an environment group in the target namespace will be returned
-// unless an error has occurred.
+// unless an error has occurred. External secrets of the environment group are resolved into the copy inside the cluster by the External Secrets
+// Operator, which refreshes them on their refresh interval.
 func SyncLatestVersionToNamespace(ctx context.Context, a *kubernetes.Agent, inp SyncLatestVersionToNamespaceInput, additionalLabels map[string]string) (SyncLatestVersionToNamespaceOutput, error) {
 	ctx, span := telemetry.NewSpan(ctx, "sync-env-group-version-to-namespace")
 	defer span.End()
@@ -60,6 +61,14 @@ func SyncLatestVersionToNamespace(ctx context.Context, a *kubernetes.Agent, inp
 	}
 
 	if targetEnvironmentGroup.Name == baseEnvironmentGroup.Name && targetEnvironmentGroup.Version == baseEnvironmentGroup.Version {
+		// external secret references may have changed even if the version has not
+		if inp.TargetNamespace != Namespace_EnvironmentGroups {
+			err = syncExternalSecretsToNamespace(ctx, a, baseEnvironmentGroup, inp.TargetNamespace)
+			if err != nil {
+				return output, telemetry.Error(ctx, span, err, "unable to sync external secrets to target namespace")
+			}
+		}
+
 		return SyncLatestVersionToNamespaceOutput{
 			EnvironmentGroupVersionedName: fmt.Sprintf("%s.%d", baseEnvironmentGroup.Name, baseEnvironmentGroup.Version),
 		}, nil
@@ -70,6 +79,14 @@ func SyncLatestVersionToNamespace(ctx context.Context, a *kubernetes.Agent, inp
 		return output, telemetry.Error(ctx, span, err, "unable to create environment group in target namespace")
 	}
 
+	// external secrets are only resolved into target namespaces, so that their values are never stored with the base environment group
+	if inp.TargetNamespace != Namespace_EnvironmentGroups {
+		err = syncExternalSecretsToNamespace(ctx, a, baseEnvironmentGroup, inp.TargetNamespace)
+		if err != nil {
+			return output, telemetry.Error(ctx, span, err, "unable to sync external secrets to target namespace")
+		}
+	}
+
 	output = SyncLatestVersionToNamespaceOutput{
 		EnvironmentGroupVersionedName: targetConfigmapName,
 	}
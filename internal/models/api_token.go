@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/porter-dev/porter/api/types"
@@ -20,8 +21,31 @@ type APIToken struct {
 	PolicyName      string
 	Name            string
 
+	// DeploymentTargetIDs is a comma-separated list of the ids of the deployment targets which the token
+	// is restricted to. The token is not restricted to deployment targets if it is empty.
+	DeploymentTargetIDs string
+
+	// AppNames is a comma-separated list of the names of the apps which the token is restricted to. The
+	// token is not restricted to apps if it is empty.
+	AppNames string
+
+	// LastUsedAt and LastUsedIP record the last authenticated request made with the token
+	LastUsedAt *time.Time
+	LastUsedIP string
+
+	// RotatedAt is the time at which the secret of the token was last rotated
+	RotatedAt *time.Time
+
+	// ExpiryNotifiedAt is the time at which the creator of the token was notified that it expires soon
+	ExpiryNotifiedAt *time.Time
+
 	// SecretKey is hashed like a password before storage
 	SecretKey []byte
+
+	// PreviousSecretKey is the hashed secret key which was replaced by the last rotation. It remains
+	// valid until PreviousSecretExpiry.
+	PreviousSecretKey    []byte
+	PreviousSecretExpiry *time.Time
 }
 
 func (p *APIToken) IsExpired() bool {
@@ -29,14 +53,44 @@ func (p *APIToken) IsExpired() bool {
 	return timeLeft < 0
 }
 
+// IsPreviousSecretValid returns true if the secret replaced by the last rotation is still accepted
+func (p *APIToken) IsPreviousSecretValid() bool {
+	return len(p.PreviousSecretKey) > 0 && p.PreviousSecretExpiry != nil && time.Now().Before(*p.PreviousSecretExpiry)
+}
+
+// DeploymentTargetIDList returns the ids of the deployment targets which the token is restricted to
+func (p *APIToken) DeploymentTargetIDList() []string {
+	return splitList(p.DeploymentTargetIDs)
+}
+
+// AppNameList returns the names of the apps which the token is restricted to
+func (p *APIToken) AppNameList() []string {
+	return splitList(p.AppNames)
+}
+
+// AllowsDeploymentTarget returns true if the token can act on the deployment target with the given id
+func (p *APIToken) AllowsDeploymentTarget(id string) bool {
+	return allowedByList(p.DeploymentTargetIDList(), id)
+}
+
+// AllowsApp returns true if the token can act on the app with the given name
+func (p *APIToken) AllowsApp(name string) bool {
+	return allowedByList(p.AppNameList(), name)
+}
+
 func (p *APIToken) ToAPITokenMetaType() *types.APITokenMeta {
 	return &types.APITokenMeta{
-		ID:         p.UniqueID,
-		CreatedAt:  p.CreatedAt,
-		ExpiresAt:  *p.Expiry,
-		PolicyName: p.PolicyName,
-		PolicyUID:  p.PolicyUID,
-		Name:       p.Name,
+		ID:                  p.UniqueID,
+		CreatedAt:           p.CreatedAt,
+		ExpiresAt:           *p.Expiry,
+		PolicyName:          p.PolicyName,
+		PolicyUID:           p.PolicyUID,
+		Name:                p.Name,
+		DeploymentTargetIDs: p.DeploymentTargetIDList(),
+		AppNames:            p.AppNameList(),
+		LastUsedAt:          p.LastUsedAt,
+		LastUsedIP:          p.LastUsedIP,
+		RotatedAt:           p.RotatedAt,
 	}
 }
 
@@ -47,3 +101,26 @@ func (p *APIToken) ToAPITokenType(policy []*types.PolicyDocument, token string)
 		Token:        token,
 	}
 }
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	return strings.Split(value, ",")
+}
+
+// allowedByList returns true if the list is empty, meaning that nothing is restricted, or contains the value
+func allowedByList(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AuditLog is a database model that records a single mutation made through the API
+type AuditLog struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the mutation was made in
+	ProjectID uint `gorm:"index"`
+
+	// ClusterID is the ID of the cluster that the mutation was made in, or 0 if the route is not cluster-scoped
+	ClusterID uint
+
+	// ActorType is whether the request was made by a user or by an API token
+	ActorType string
+
+	// ActorID is the ID of the user, or the unique ID of the API token, that made the request
+	ActorID string
+
+	// ActorEmail is the email of the user, or the name of the API token, that made the request
+	ActorEmail string
+
+	// Action is the verb of the route, such as "create" or "delete"
+	Action string
+
+	// Method is the HTTP method of the request
+	Method string
+
+	// Resource is the route pattern of the request, such as "/projects/{project_id}/clusters/{cluster_id}"
+	Resource string
+
+	// ResourceParams is the JSON-encoded map of the URL parameters of the request
+	ResourceParams []byte
+
+	// Path is the requested URL path
+	Path string
+
+	// RequestID is the ID of the request, taken from the X-Request-ID header if it was set
+	RequestID string `gorm:"index"`
+
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+
+	// Changes is the JSON-encoded list of fields of the resource changed by the request, with sensitive values
+	// redacted. It is empty for requests which failed or whose handler does not record the resource state.
+	Changes []byte
+}
+
+// ToAuditLogType generates an external types.AuditLog to be shared over REST
+func (a *AuditLog) ToAuditLogType() types.AuditLog {
+	res := types.AuditLog{
+		ID:         a.ID,
+		CreatedAt:  a.CreatedAt,
+		ProjectID:  a.ProjectID,
+		ClusterID:  a.ClusterID,
+		ActorType:  types.AuditLogActorType(a.ActorType),
+		ActorID:    a.ActorID,
+		ActorEmail: a.ActorEmail,
+		Action:     a.Action,
+		Method:     a.Method,
+		Resource:   a.Resource,
+		Path:       a.Path,
+		RequestID:  a.RequestID,
+		StatusCode: a.StatusCode,
+	}
+
+	if len(a.ResourceParams) > 0 {
+		_ = json.Unmarshal(a.ResourceParams, &res.ResourceParams)
+	}
+
+	if len(a.Changes) > 0 {
+		_ = json.Unmarshal(a.Changes, &res.Changes)
+	}
+
+	return res
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// EncryptionKeyRotation is a database model that tracks the re-encryption of the encrypted columns of one
+// table under a key, so that the rotation can resume where it stopped
+type EncryptionKeyRotation struct {
+	gorm.Model
+
+	// KeyID is the id of the key which values are re-encrypted under: the id of the current key of the key
+	// provider, or the id of the static encryption key
+	KeyID string `json:"key_id" gorm:"uniqueIndex:idx_encryption_key_rotation_table"`
+
+	// ModelTable is the name of the table which is re-encrypted
+	ModelTable string `json:"model_table" gorm:"uniqueIndex:idx_encryption_key_rotation_table"`
+
+	// LastID is the id of the last row which was processed. The rotation resumes after it.
+	LastID uint `json:"last_id"`
+
+	// Rotated, Skipped and Failed count the rows which were re-encrypted, which were already encrypted under
+	// the key, and which could not be re-encrypted
+	Rotated uint `json:"rotated"`
+	Skipped uint `json:"skipped"`
+	Failed  uint `json:"failed"`
+
+	// FailedIDs is a comma-separated list of the ids of the rows which could not be re-encrypted. They are
+	// retried by the next run of the rotation.
+	FailedIDs string `json:"failed_ids"`
+
+	// LastError is the last error encountered while re-encrypting a row of the table
+	LastError string `json:"last_error"`
+
+	// CompletedAt is set once every row of the table has been re-encrypted
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// FailedIDList returns the ids of the rows which could not be re-encrypted
+func (r *EncryptionKeyRotation) FailedIDList() []uint {
+	res := []uint{}
+
+	for _, id := range strings.Split(r.FailedIDs, ",") {
+		parsed, err := strconv.ParseUint(id, 10, 64)
+		if err == nil {
+			res = append(res, uint(parsed))
+		}
+	}
+
+	return res
+}
+
+// SetFailedIDs stores the ids of the rows which could not be re-encrypted, and counts them as failed
+func (r *EncryptionKeyRotation) SetFailedIDs(ids []uint) {
+	strIDs := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		strIDs = append(strIDs, strconv.FormatUint(uint64(id), 10))
+	}
+
+	r.FailedIDs = strings.Join(strIDs, ",")
+	r.Failed = uint(len(ids))
+}
+
+// ToEncryptionKeyRotationTableType generates an external types.EncryptionKeyRotationTable to be shared over REST
+func (r *EncryptionKeyRotation) ToEncryptionKeyRotationTableType() *types.EncryptionKeyRotationTable {
+	return &types.EncryptionKeyRotationTable{
+		Table:       r.ModelTable,
+		LastID:      r.LastID,
+		Rotated:     r.Rotated,
+		Skipped:     r.Skipped,
+		Failed:      r.Failed,
+		FailedIDs:   r.FailedIDList(),
+		LastError:   r.LastError,
+		UpdatedAt:   r.UpdatedAt,
+		CompletedAt: r.CompletedAt,
+	}
+}
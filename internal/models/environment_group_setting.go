@@ -0,0 +1,21 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// EnvironmentGroupSetting stores the Porter settings of an environment group. Environment groups themselves are stored
+// on the cluster, so settings are keyed by the cluster and the name of the environment group.
+type EnvironmentGroupSetting struct {
+	gorm.Model
+
+	ProjectID uint `json:"project_id"`
+	ClusterID uint `json:"cluster_id" gorm:"uniqueIndex:idx_environment_group_setting_name"`
+
+	// EnvironmentGroupName is the name of the environment group
+	EnvironmentGroupName string `json:"environment_group_name" gorm:"uniqueIndex:idx_environment_group_setting_name"`
+
+	// AutoRollout redeploys the apps linked to the environment group one by one when the environment group is updated,
+	// and stops at the first app which fails to deploy
+	AutoRollout bool `json:"auto_rollout"`
+}
@@ -0,0 +1,52 @@
+package integrations
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// NotifierIntegration sends deployment and incident notifications of a project to a generic webhook, a
+// Microsoft Teams channel or a PagerDuty service.
+type NotifierIntegration struct {
+	gorm.Model
+
+	// The project that this integration belongs to
+	ProjectID uint `json:"project_id"`
+
+	// The id of the user that created this integration
+	UserID uint `json:"user_id"`
+
+	// The display name of the integration
+	Name string `json:"name"`
+
+	// The kind of service that notifications are sent to
+	Kind types.NotifierIntegrationKind `json:"kind"`
+
+	// Whether deployment and incident notifications are sent to the integration
+	Deployments bool `json:"deployments"`
+	Incidents   bool `json:"incidents"`
+
+	// ------------------------------------------------------------------
+	// All fields below encrypted before storage.
+	// ------------------------------------------------------------------
+
+	// The webhook to call, unused for PagerDuty
+	URL []byte
+
+	// The secret used to sign webhook payloads, or the routing key of a PagerDuty service
+	Secret []byte
+}
+
+// ToNotifierIntegrationType converts the integration to its API type, omitting its secrets
+func (n *NotifierIntegration) ToNotifierIntegrationType() *types.NotifierIntegration {
+	return &types.NotifierIntegration{
+		ID:          n.ID,
+		ProjectID:   n.ProjectID,
+		Name:        n.Name,
+		Kind:        n.Kind,
+		Deployments: n.Deployments,
+		Incidents:   n.Incidents,
+		CreatedAt:   n.CreatedAt,
+	}
+}
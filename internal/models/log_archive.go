@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// LogArchiveSetting stores where the app logs of a project are archived. Logs are written to an S3 bucket, or to
+// any S3-compatible storage if an endpoint is set, using the credentials of an AWS integration of the project.
+type LogArchiveSetting struct {
+	gorm.Model
+
+	ProjectID uint `json:"project_id" gorm:"uniqueIndex"`
+
+	// Enabled archives the logs of the project each time the log archiver job runs
+	Enabled bool `json:"enabled"`
+
+	// EnabledAt is when archival was last enabled. Logs written before are not archived
+	EnabledAt time.Time `json:"enabled_at"`
+
+	// AWSIntegrationID is the id of the AWS integration whose credentials are used to write to the bucket
+	AWSIntegrationID uint `json:"aws_integration_id"`
+
+	Bucket string `json:"bucket"`
+	Region string `json:"region"`
+
+	// Endpoint is the optional URL of an S3-compatible storage, such as MinIO
+	Endpoint string `json:"endpoint"`
+
+	// Prefix is prepended to the key of every archived object
+	Prefix string `json:"prefix"`
+}
+
+// ToLogArchiveSettingType generates an external types.LogArchiveSetting to be shared over REST
+func (s *LogArchiveSetting) ToLogArchiveSettingType() *types.LogArchiveSetting {
+	return &types.LogArchiveSetting{
+		ProjectID:        s.ProjectID,
+		Enabled:          s.Enabled,
+		EnabledAt:        s.EnabledAt,
+		AWSIntegrationID: s.AWSIntegrationID,
+		Bucket:           s.Bucket,
+		Region:           s.Region,
+		Endpoint:         s.Endpoint,
+		Prefix:           s.Prefix,
+	}
+}
+
+// LogArchiveCheckpoint stores the time until which the logs of a cluster have been archived
+type LogArchiveCheckpoint struct {
+	gorm.Model
+
+	ProjectID uint `json:"project_id"`
+	ClusterID uint `json:"cluster_id" gorm:"uniqueIndex"`
+
+	// ArchivedUntil is the end of the last window for which the logs of every app in the cluster were archived
+	ArchivedUntil time.Time `json:"archived_until"`
+}
+
+// LogArchive is an object in the log archive, holding the logs of a service for a window of time
+// as gzip-compressed newline-delimited JSON
+type LogArchive struct {
+	gorm.Model
+
+	ProjectID   uint   `json:"project_id" gorm:"index:idx_log_archive_app"`
+	ClusterID   uint   `json:"cluster_id" gorm:"index:idx_log_archive_app"`
+	AppName     string `json:"app_name" gorm:"index:idx_log_archive_app"`
+	ServiceName string `json:"service_name"`
+
+	// DeploymentTargetID is the deployment target of the app, since apps with the same name can be deployed to
+	// several targets of a cluster, such as preview environments
+	DeploymentTargetID uuid.UUID `json:"deployment_target_id" gorm:"type:uuid"`
+
+	// Bucket and ObjectKey locate the object. Objects are keyed by their window, so that archiving a window again overwrites them
+	Bucket    string `json:"bucket"`
+	ObjectKey string `json:"object_key" gorm:"uniqueIndex"`
+
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+
+	LineCount int   `json:"line_count"`
+	SizeBytes int64 `json:"size_bytes"`
+}
+
+// ToLogArchiveType generates an external types.LogArchive to be shared over REST
+func (a *LogArchive) ToLogArchiveType() types.LogArchive {
+	return types.LogArchive{
+		ClusterID:          a.ClusterID,
+		DeploymentTargetID: a.DeploymentTargetID.String(),
+		AppName:            a.AppName,
+		ServiceName:        a.ServiceName,
+		Bucket:             a.Bucket,
+		ObjectKey:          a.ObjectKey,
+		StartTime:          a.StartTime,
+		EndTime:            a.EndTime,
+		LineCount:          a.LineCount,
+		SizeBytes:          a.SizeBytes,
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// OPAPolicyBundle is a database model that represents a user-supplied collection of Rego policies for a project
+type OPAPolicyBundle struct {
+	gorm.Model
+
+	// ProjectID is the ID of the project that the bundle belongs to
+	ProjectID uint `gorm:"index"`
+
+	// Name is the name of the bundle, which is unique within the project
+	Name string
+
+	// Kind is the kind of object the policies are evaluated against, such as "helm_release"
+	Kind string
+
+	// Match is the JSON-encoded types.OPAPolicyBundleMatch which selects the objects to evaluate
+	Match []byte
+
+	// MustExist reports a failure if no helm release matches the bundle
+	MustExist bool
+
+	// OverrideSeverity overrides the severity declared by each policy
+	OverrideSeverity string
+
+	// Enforcement is whether violations of a porter_app bundle reject the app or are reported as warnings
+	Enforcement string
+
+	// Policies is the JSON-encoded list of types.OPAPolicy in the bundle
+	Policies []byte
+}
+
+// ToOPAPolicyBundleType generates an external types.OPAPolicyBundle to be shared over REST
+func (b *OPAPolicyBundle) ToOPAPolicyBundleType() (*types.OPAPolicyBundle, error) {
+	res := &types.OPAPolicyBundle{
+		ID:               b.ID,
+		ProjectID:        b.ProjectID,
+		CreatedAt:        b.CreatedAt,
+		UpdatedAt:        b.UpdatedAt,
+		Name:             b.Name,
+		Kind:             b.Kind,
+		MustExist:        b.MustExist,
+		OverrideSeverity: b.OverrideSeverity,
+		Enforcement:      types.OPAPolicyEnforcement(b.Enforcement),
+	}
+
+	if len(b.Match) > 0 {
+		if err := json.Unmarshal(b.Match, &res.Match); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(b.Policies) > 0 {
+		if err := json.Unmarshal(b.Policies, &res.Policies); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
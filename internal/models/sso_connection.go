@@ -0,0 +1,145 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// SSOConnection connects a project to an OIDC or SAML identity provider which its users log in through
+type SSOConnection struct {
+	gorm.Model
+
+	ProjectID uint `json:"project_id" gorm:"index"`
+
+	// UniqueID identifies the connection in its login urls
+	UniqueID string `json:"unique_id" gorm:"unique"`
+
+	Name string                  `json:"name"`
+	Kind types.SSOConnectionKind `json:"kind"`
+
+	// Domains are the email domains of the users of the connection
+	Domains []SSODomain `json:"domains" gorm:"foreignKey:SSOConnectionID"`
+
+	AutoProvision bool           `json:"auto_provision"`
+	DefaultRole   types.RoleKind `json:"default_role"`
+
+	// GroupRoleMappings is a JSON-encoded list of types.SSOGroupRoleMapping
+	GroupRoleMappings []byte `json:"group_role_mappings"`
+
+	// OIDC connections
+	IssuerURL   string `json:"issuer_url"`
+	ClientID    string `json:"client_id"`
+	Scopes      string `json:"scopes"`
+	GroupsClaim string `json:"groups_claim"`
+
+	// SAML connections
+	IdPMetadataURL  string `json:"idp_metadata_url"`
+	IdPSSOURL       string `json:"idp_sso_url"`
+	IdPEntityID     string `json:"idp_entity_id"`
+	IdPCertificate  string `json:"idp_certificate"`
+	GroupsAttribute string `json:"groups_attribute"`
+
+	// ------------------------------------------------------------------
+	// All fields below encrypted before storage.
+	// ------------------------------------------------------------------
+
+	// ClientSecret is the client secret of an OIDC connection
+	ClientSecret []byte `json:"client_secret"`
+}
+
+// VerifiedDomainList returns the email domains of the connection which have been verified
+func (c *SSOConnection) VerifiedDomainList() []string {
+	res := make([]string, 0, len(c.Domains))
+
+	for _, domain := range c.Domains {
+		if domain.VerifiedAt != nil {
+			res = append(res, domain.Domain)
+		}
+	}
+
+	return res
+}
+
+// ScopeList returns the OIDC scopes requested by the connection
+func (c *SSOConnection) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// GroupRoleMappingList decodes the group role mappings of the connection
+func (c *SSOConnection) GroupRoleMappingList() ([]types.SSOGroupRoleMapping, error) {
+	mappings := make([]types.SSOGroupRoleMapping, 0)
+
+	if len(c.GroupRoleMappings) == 0 {
+		return mappings, nil
+	}
+
+	if err := json.Unmarshal(c.GroupRoleMappings, &mappings); err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+// ToSSOConnectionType converts the connection to its API type, omitting its client secret. The urls of the
+// connection are set by the caller, since they depend on the server url.
+func (c *SSOConnection) ToSSOConnectionType() *types.SSOConnection {
+	mappings, _ := c.GroupRoleMappingList()
+
+	domains := make([]types.SSODomain, 0, len(c.Domains))
+	for _, domain := range c.Domains {
+		domains = append(domains, *domain.ToSSODomainType())
+	}
+
+	return &types.SSOConnection{
+		ID:                c.ID,
+		UniqueID:          c.UniqueID,
+		ProjectID:         c.ProjectID,
+		Name:              c.Name,
+		Kind:              c.Kind,
+		Domains:           domains,
+		AutoProvision:     c.AutoProvision,
+		DefaultRole:       c.DefaultRole,
+		GroupRoleMappings: mappings,
+		IssuerURL:         c.IssuerURL,
+		ClientID:          c.ClientID,
+		Scopes:            c.ScopeList(),
+		GroupsClaim:       c.GroupsClaim,
+		IdPMetadataURL:    c.IdPMetadataURL,
+		IdPSSOURL:         c.IdPSSOURL,
+		IdPEntityID:       c.IdPEntityID,
+		GroupsAttribute:   c.GroupsAttribute,
+		CreatedAt:         c.CreatedAt,
+	}
+}
+
+// SSODomain is an email domain claimed by an sso connection. Users in the domain are only authenticated through
+// the connection, and required to log in through it, once the domain is verified with a DNS TXT record.
+type SSODomain struct {
+	gorm.Model
+
+	SSOConnectionID uint `json:"sso_connection_id" gorm:"index"`
+
+	// Domain is the lower-cased email domain. Any number of connections can claim a domain, but only one can
+	// verify it.
+	Domain string `json:"domain" gorm:"index;uniqueIndex:idx_sso_domains_verified_domain,where:verified_at IS NOT NULL"`
+
+	// VerificationToken is the value of the TXT record which verifies the domain
+	VerificationToken string `json:"verification_token"`
+
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+// ToSSODomainType converts the domain to its API type
+func (d *SSODomain) ToSSODomainType() *types.SSODomain {
+	return &types.SSODomain{
+		Domain:             d.Domain,
+		Verified:           d.VerifiedAt != nil,
+		VerificationRecord: types.SSODomainVerificationRecord(d.Domain),
+		VerificationToken:  d.VerificationToken,
+	}
+}
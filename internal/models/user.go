@@ -23,6 +23,11 @@ type User struct {
 	// The github user id used for login (optional)
 	GithubUserID int64
 	GoogleUserID string
+
+	// The sso connection the user logs in through, either the unique id of a project connection or
+	// "oidc" or "saml" for instance-wide sso, and the subject of the user at its identity provider (optional)
+	SSOConnection string `gorm:"index:idx_users_sso_subject"`
+	SSOSubject    string `gorm:"index:idx_users_sso_subject"`
 }
 
 // ToUserType generates an external types.User to be shared over REST
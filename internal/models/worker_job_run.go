@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// WorkerJobRunStatus is the status of a single run of a worker job
+type WorkerJobRunStatus string
+
+const (
+	// WorkerJobRunStatus_Queued is the status for a run that is waiting to be picked up by a worker
+	WorkerJobRunStatus_Queued WorkerJobRunStatus = "QUEUED"
+	// WorkerJobRunStatus_Running is the status for a run that has been claimed by a worker
+	WorkerJobRunStatus_Running WorkerJobRunStatus = "RUNNING"
+	// WorkerJobRunStatus_Succeeded is the status for a run that completed without error
+	WorkerJobRunStatus_Succeeded WorkerJobRunStatus = "SUCCEEDED"
+	// WorkerJobRunStatus_Retrying is the status for a run that failed and is waiting for its next attempt
+	WorkerJobRunStatus_Retrying WorkerJobRunStatus = "RETRYING"
+	// WorkerJobRunStatus_Dead is the status for a run that exhausted its attempts, and is kept on the dead-letter list
+	WorkerJobRunStatus_Dead WorkerJobRunStatus = "DEAD"
+)
+
+// WorkerJobRun is a database model that represents a single enqueued run of a worker job
+type WorkerJobRun struct {
+	gorm.Model
+
+	// JobID is the ID of the job being run, such as "recommender"
+	JobID string `json:"job_id" gorm:"index"`
+
+	// Input is the JSON-encoded input that the job was enqueued with
+	Input []byte `json:"input"`
+
+	// ConcurrencyKey serializes runs: of the runs which share a non-empty key, only the oldest unfinished one can be claimed
+	ConcurrencyKey string `json:"concurrency_key" gorm:"index"`
+
+	// Status is the current status of the run
+	Status WorkerJobRunStatus `json:"status" gorm:"index"`
+
+	// Attempts is the number of times the run has been attempted
+	Attempts int `json:"attempts"`
+
+	// MaxAttempts is the number of attempts after which the run is moved to the dead-letter list
+	MaxAttempts int `json:"max_attempts"`
+
+	// NextRunAt is the time (UTC) after which the run can be picked up by a worker
+	NextRunAt time.Time `json:"next_run_at" gorm:"index"`
+
+	// LastError is the error returned by the most recent failed attempt
+	LastError string `json:"last_error"`
+
+	// Result is the JSON-encoded report of a successful run, for jobs which implement worker.ResultReporter
+	Result []byte `json:"result"`
+
+	// StartedAt is the time (UTC) that the most recent attempt started
+	StartedAt *time.Time `json:"started_at"`
+
+	// CompletedAt is the time (UTC) that the run succeeded or was moved to the dead-letter list
+	CompletedAt *time.Time `json:"completed_at"`
+
+	// LockedBy is the ID of the worker process which has claimed the run
+	LockedBy string `json:"locked_by"`
+
+	// LockedUntil is the time (UTC) after which a running run is considered abandoned and can be claimed again
+	LockedUntil *time.Time `json:"locked_until"`
+}
+
+// ToWorkerJobRunType generates an external types.WorkerJobRun to be shared over REST
+func (r *WorkerJobRun) ToWorkerJobRunType() *types.WorkerJobRun {
+	var input map[string]interface{}
+	if len(r.Input) > 0 {
+		// the input was marshaled from a map when enqueued, so a failure here only drops it from the response
+		_ = json.Unmarshal(r.Input, &input)
+	}
+
+	return &types.WorkerJobRun{
+		ID:             r.ID,
+		JobID:          r.JobID,
+		Input:          input,
+		ConcurrencyKey: r.ConcurrencyKey,
+		Status:         string(r.Status),
+		Attempts:       r.Attempts,
+		MaxAttempts:    r.MaxAttempts,
+		LastError:      r.LastError,
+		Result:         r.Result,
+		CreatedAt:      r.CreatedAt,
+		NextRunAt:      r.NextRunAt,
+		StartedAt:      r.StartedAt,
+		CompletedAt:    r.CompletedAt,
+	}
+}
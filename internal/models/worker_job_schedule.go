@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// WorkerJobSchedule is a database model that tracks the runs of a worker job enqueued by the scheduler. It is shared by
+// all replicas of the workers service, so that each scheduled run is only enqueued once.
+type WorkerJobSchedule struct {
+	gorm.Model
+
+	// JobID is the ID of the scheduled job, such as "recommender"
+	JobID string `json:"job_id" gorm:"uniqueIndex"`
+
+	// Schedule is the cron expression the job is scheduled with
+	Schedule string `json:"schedule"`
+
+	// LastRunAt is the time (UTC) that the job was last enqueued by the scheduler
+	LastRunAt *time.Time `json:"last_run_at"`
+
+	// LastRunID is the ID of the WorkerJobRun that was last enqueued by the scheduler
+	LastRunID uint `json:"last_run_id"`
+
+	// NextRunAt is the time (UTC) that the job is next due to be enqueued
+	NextRunAt time.Time `json:"next_run_at"`
+}
+
+// ToWorkerJobScheduleType generates an external types.WorkerJobSchedule to be shared over REST
+func (s *WorkerJobSchedule) ToWorkerJobScheduleType() *types.WorkerJobSchedule {
+	return &types.WorkerJobSchedule{
+		JobID:     s.JobID,
+		Schedule:  s.Schedule,
+		LastRunAt: s.LastRunAt,
+		LastRunID: s.LastRunID,
+		NextRunAt: s.NextRunAt,
+	}
+}
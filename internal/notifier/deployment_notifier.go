@@ -1,6 +1,11 @@
 package notifier
 
-import "time"
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
 
 type Notifier interface {
 	Notify(opts *NotifyOpts) error
@@ -43,3 +48,40 @@ type NotifyOpts struct {
 
 	Version int
 }
+
+// MultiDeploymentNotifier sends deployment notifications to several notifiers, subject to the notification
+// config of the deployment
+type MultiDeploymentNotifier struct {
+	notifConf *types.NotificationConfig
+	notifiers []Notifier
+}
+
+// NewMultiDeploymentNotifier returns a Notifier which notifies each of the given notifiers
+func NewMultiDeploymentNotifier(notifConf *types.NotificationConfig, notifiers ...Notifier) Notifier {
+	return &MultiDeploymentNotifier{notifConf, notifiers}
+}
+
+// Notify notifies each notifier, unless notifications for the status are disabled by the notification config
+func (m *MultiDeploymentNotifier) Notify(opts *NotifyOpts) error {
+	if m.notifConf != nil {
+		if !m.notifConf.Enabled {
+			return nil
+		}
+		if opts.Status == StatusHelmDeployed && !m.notifConf.Success {
+			return nil
+		}
+		if (opts.Status == StatusPodCrashed || opts.Status == StatusHelmFailed) && !m.notifConf.Failure {
+			return nil
+		}
+	}
+
+	var errs []error
+
+	for _, n := range m.notifiers {
+		if err := n.Notify(opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
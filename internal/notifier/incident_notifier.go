@@ -1,6 +1,10 @@
 package notifier
 
-import "github.com/porter-dev/porter/api/types"
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+)
 
 type IncidentNotifier interface {
 	NotifyNew(incident *types.Incident, url string) error
@@ -23,13 +27,16 @@ func (m *MultiIncidentNotifier) NotifyNew(incident *types.Incident, url string)
 		return nil
 	}
 
+	// notify every notifier, so that a failing integration does not prevent the others from being notified
+	var errs []error
+
 	for _, n := range m.notifiers {
 		if err := n.NotifyNew(incident, url); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func (m *MultiIncidentNotifier) NotifyResolved(incident *types.Incident, url string) error {
@@ -39,11 +46,14 @@ func (m *MultiIncidentNotifier) NotifyResolved(incident *types.Incident, url str
 		return nil
 	}
 
+	// notify every notifier, so that a failing integration does not prevent the others from being notified
+	var errs []error
+
 	for _, n := range m.notifiers {
 		if err := n.NotifyResolved(incident, url); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
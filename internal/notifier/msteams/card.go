@@ -0,0 +1,127 @@
+package msteams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier/webhook"
+)
+
+// Message is the payload posted to a Microsoft Teams incoming webhook, wrapping a single adaptive card
+type Message struct {
+	Type        string        `json:"type"`
+	Attachments []*Attachment `json:"attachments"`
+}
+
+// Attachment is an attachment of a Microsoft Teams message
+type Attachment struct {
+	ContentType string        `json:"contentType"`
+	Content     *AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard is an adaptive card, see https://adaptivecards.io/explorer/AdaptiveCard.html
+type AdaptiveCard struct {
+	Schema  string     `json:"$schema"`
+	Type    string     `json:"type"`
+	Version string     `json:"version"`
+	Body    []*Element `json:"body"`
+	Actions []*Action  `json:"actions,omitempty"`
+}
+
+// Element is a TextBlock or FactSet element of an adaptive card
+type Element struct {
+	Type string `json:"type"`
+
+	// TextBlock properties
+	Text   string `json:"text,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Color  string `json:"color,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+
+	// FactSet properties
+	Facts []*Fact `json:"facts,omitempty"`
+}
+
+// Fact is a single title and value pair of a FactSet
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Action is an Action.OpenUrl button of an adaptive card
+type Action struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// newMessage returns a message containing an adaptive card with a title, a summary, facts and a link
+func newMessage(title, color, summary string, facts []*Fact, linkTitle, url string) *Message {
+	body := []*Element{
+		{
+			Type:   "TextBlock",
+			Text:   title,
+			Size:   "Medium",
+			Weight: "Bolder",
+			Color:  color,
+			Wrap:   true,
+		},
+		{
+			Type:  "FactSet",
+			Facts: facts,
+		},
+	}
+
+	if summary != "" {
+		body = append(body, &Element{
+			Type: "TextBlock",
+			Text: summary,
+			Wrap: true,
+		})
+	}
+
+	card := &AdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+	}
+
+	if url != "" {
+		card.Actions = []*Action{{
+			Type:  "Action.OpenUrl",
+			Title: linkTitle,
+			URL:   url,
+		}}
+	}
+
+	return &Message{
+		Type: "message",
+		Attachments: []*Attachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+}
+
+// send posts the message to the incoming webhook of each integration
+func send(ctx context.Context, sender *webhook.Sender, teamsInts []*integrations.NotifierIntegration, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, teamsInt := range teamsInts {
+		if err := sender.Post(ctx, string(teamsInt.URL), payload, nil); err != nil {
+			errs = append(errs, fmt.Errorf("microsoft teams integration %s: %w", teamsInt.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
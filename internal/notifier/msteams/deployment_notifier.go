@@ -0,0 +1,70 @@
+package msteams
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/webhook"
+)
+
+// DeploymentNotifier posts deployment notifications to Microsoft Teams channels as adaptive cards
+type DeploymentNotifier struct {
+	ctx       context.Context
+	teamsInts []*integrations.NotifierIntegration
+	sender    *webhook.Sender
+}
+
+// NewDeploymentNotifier returns a DeploymentNotifier which posts to the given Microsoft Teams integrations until ctx is done
+func NewDeploymentNotifier(ctx context.Context, teamsInts ...*integrations.NotifierIntegration) *DeploymentNotifier {
+	return &DeploymentNotifier{
+		ctx:       ctx,
+		teamsInts: teamsInts,
+		sender:    webhook.NewSender(),
+	}
+}
+
+// Notify posts a card describing the deployment to each channel
+func (n *DeploymentNotifier) Notify(opts *notifier.NotifyOpts) error {
+	var title, color, linkTitle string
+
+	switch opts.Status {
+	case notifier.StatusHelmDeployed:
+		title = fmt.Sprintf("Your application %s was successfully updated on Porter!", opts.Name)
+		color = "Good"
+		linkTitle = "View the new release"
+	case notifier.StatusHelmFailed:
+		title = fmt.Sprintf("Your application %s failed to deploy on Porter.", opts.Name)
+		color = "Attention"
+		linkTitle = "View the status"
+	case notifier.StatusPodCrashed:
+		title = fmt.Sprintf("Your application %s crashed on Porter.", opts.Name)
+		color = "Attention"
+		linkTitle = "View the application"
+	default:
+		return nil
+	}
+
+	facts := []*Fact{
+		{Title: "Name", Value: opts.Name},
+		{Title: "Namespace", Value: opts.Namespace},
+		{Title: "Cluster", Value: opts.ClusterName},
+	}
+
+	if opts.Timestamp != nil {
+		facts = append(facts, &Fact{Title: "Timestamp", Value: opts.Timestamp.UTC().Format("2006-01-02 15:04:05 UTC")})
+	}
+
+	if opts.Status == notifier.StatusHelmDeployed || opts.Status == notifier.StatusHelmFailed {
+		facts = append(facts, &Fact{Title: "Version", Value: strconv.Itoa(opts.Version)})
+	}
+
+	var summary string
+	if opts.Status != notifier.StatusHelmDeployed {
+		summary = opts.Info
+	}
+
+	return send(n.ctx, n.sender, n.teamsInts, newMessage(title, color, summary, facts, linkTitle, opts.URL))
+}
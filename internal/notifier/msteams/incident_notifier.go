@@ -0,0 +1,70 @@
+package msteams
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier/webhook"
+)
+
+// IncidentNotifier posts incident notifications to Microsoft Teams channels as adaptive cards
+type IncidentNotifier struct {
+	ctx       context.Context
+	teamsInts []*integrations.NotifierIntegration
+	sender    *webhook.Sender
+}
+
+// NewIncidentNotifier returns an IncidentNotifier which posts to the given Microsoft Teams integrations until ctx is done
+func NewIncidentNotifier(ctx context.Context, teamsInts ...*integrations.NotifierIntegration) *IncidentNotifier {
+	return &IncidentNotifier{
+		ctx:       ctx,
+		teamsInts: teamsInts,
+		sender:    webhook.NewSender(),
+	}
+}
+
+// NotifyNew posts a card describing the new incident to each channel
+func (n *IncidentNotifier) NotifyNew(incident *types.Incident, url string) error {
+	resourceKind := "application"
+
+	if strings.ToLower(string(incident.InvolvedObjectKind)) == "job" {
+		resourceKind = "job"
+	}
+
+	facts := []*Fact{
+		{Title: "Name", Value: incident.ReleaseName},
+		{Title: "Namespace", Value: incident.ReleaseNamespace},
+		{Title: "Created at", Value: incident.CreatedAt.UTC().Format("2006-01-02 15:04:05 UTC")},
+	}
+
+	return send(n.ctx, n.sender, n.teamsInts, newMessage(
+		fmt.Sprintf("Your %s %s crashed on Porter.", resourceKind, incident.ReleaseName),
+		"Attention",
+		incident.Summary,
+		facts,
+		"View the incident",
+		url,
+	))
+}
+
+// NotifyResolved posts a card describing the resolved incident to each channel
+func (n *IncidentNotifier) NotifyResolved(incident *types.Incident, url string) error {
+	facts := []*Fact{
+		{Title: "Name", Value: incident.ReleaseName},
+		{Title: "Namespace", Value: incident.ReleaseNamespace},
+		{Title: "Created at", Value: incident.CreatedAt.UTC().Format("2006-01-02 15:04:05 UTC")},
+		{Title: "Resolved at", Value: incident.UpdatedAt.UTC().Format("2006-01-02 15:04:05 UTC")},
+	}
+
+	return send(n.ctx, n.sender, n.teamsInts, newMessage(
+		fmt.Sprintf("The incident for application %s has been resolved.", incident.ReleaseName),
+		"Good",
+		incident.Summary,
+		facts,
+		"View the incident",
+		url,
+	))
+}
@@ -0,0 +1,75 @@
+package notifiers
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/msteams"
+	"github.com/porter-dev/porter/internal/notifier/pagerduty"
+	"github.com/porter-dev/porter/internal/notifier/webhook"
+)
+
+// NewDeploymentNotifiers returns the deployment notifiers of the notifier integrations of a project which
+// receive deployment notifications. Notifications are sent until ctx is done.
+func NewDeploymentNotifiers(ctx context.Context, notifierInts []*integrations.NotifierIntegration) []notifier.Notifier {
+	byKind := groupByKind(notifierInts, func(notifierInt *integrations.NotifierIntegration) bool {
+		return notifierInt.Deployments
+	})
+
+	res := make([]notifier.Notifier, 0)
+
+	if ints := byKind[types.NotifierIntegrationKind_Webhook]; len(ints) > 0 {
+		res = append(res, webhook.NewDeploymentNotifier(ctx, ints...))
+	}
+
+	if ints := byKind[types.NotifierIntegrationKind_MSTeams]; len(ints) > 0 {
+		res = append(res, msteams.NewDeploymentNotifier(ctx, ints...))
+	}
+
+	if ints := byKind[types.NotifierIntegrationKind_PagerDuty]; len(ints) > 0 {
+		res = append(res, pagerduty.NewDeploymentNotifier(ctx, ints...))
+	}
+
+	return res
+}
+
+// NewIncidentNotifiers returns the incident notifiers of the notifier integrations of a project which
+// receive incident notifications. Notifications are sent until ctx is done.
+func NewIncidentNotifiers(ctx context.Context, notifierInts []*integrations.NotifierIntegration) []notifier.IncidentNotifier {
+	byKind := groupByKind(notifierInts, func(notifierInt *integrations.NotifierIntegration) bool {
+		return notifierInt.Incidents
+	})
+
+	res := make([]notifier.IncidentNotifier, 0)
+
+	if ints := byKind[types.NotifierIntegrationKind_Webhook]; len(ints) > 0 {
+		res = append(res, webhook.NewIncidentNotifier(ctx, ints...))
+	}
+
+	if ints := byKind[types.NotifierIntegrationKind_MSTeams]; len(ints) > 0 {
+		res = append(res, msteams.NewIncidentNotifier(ctx, ints...))
+	}
+
+	if ints := byKind[types.NotifierIntegrationKind_PagerDuty]; len(ints) > 0 {
+		res = append(res, pagerduty.NewIncidentNotifier(ctx, ints...))
+	}
+
+	return res
+}
+
+func groupByKind(
+	notifierInts []*integrations.NotifierIntegration,
+	filter func(notifierInt *integrations.NotifierIntegration) bool,
+) map[types.NotifierIntegrationKind][]*integrations.NotifierIntegration {
+	res := make(map[types.NotifierIntegrationKind][]*integrations.NotifierIntegration)
+
+	for _, notifierInt := range notifierInts {
+		if filter(notifierInt) {
+			res[notifierInt.Kind] = append(res[notifierInt.Kind], notifierInt)
+		}
+	}
+
+	return res
+}
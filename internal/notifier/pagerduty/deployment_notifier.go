@@ -0,0 +1,80 @@
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/webhook"
+)
+
+// DeploymentNotifier triggers PagerDuty alerts when a deployment fails or crashes, and resolves them once the
+// application deploys successfully
+type DeploymentNotifier struct {
+	ctx           context.Context
+	pagerDutyInts []*integrations.NotifierIntegration
+	sender        *webhook.Sender
+}
+
+// NewDeploymentNotifier returns a DeploymentNotifier which sends events to the given PagerDuty integrations until ctx is done
+func NewDeploymentNotifier(ctx context.Context, pagerDutyInts ...*integrations.NotifierIntegration) *DeploymentNotifier {
+	return &DeploymentNotifier{
+		ctx:           ctx,
+		pagerDutyInts: pagerDutyInts,
+		sender:        webhook.NewSender(),
+	}
+}
+
+// Notify sends a trigger event for failed and crashed deployments, and a resolve event for successful deployments
+func (n *DeploymentNotifier) Notify(opts *notifier.NotifyOpts) error {
+	// alerts of an application are deduplicated, so that a successful deployment resolves the alert of a failure
+	dedupKey := fmt.Sprintf("porter-deployment-%d-%s-%s", opts.ClusterID, opts.Namespace, opts.Name)
+
+	event := Event{
+		DedupKey:  dedupKey,
+		ClientURL: opts.URL,
+	}
+
+	var summary string
+
+	switch opts.Status {
+	case notifier.StatusHelmDeployed:
+		event.EventAction = EventAction_Resolve
+		return send(n.ctx, n.sender, n.pagerDutyInts, event)
+	case notifier.StatusHelmFailed:
+		summary = fmt.Sprintf("Application %s failed to deploy on Porter", opts.Name)
+	case notifier.StatusPodCrashed:
+		summary = fmt.Sprintf("Application %s crashed on Porter", opts.Name)
+	default:
+		return nil
+	}
+
+	timestamp := time.Now().UTC()
+	if opts.Timestamp != nil {
+		timestamp = opts.Timestamp.UTC()
+	}
+
+	event.EventAction = EventAction_Trigger
+	event.Payload = &EventPayload{
+		Summary:   truncate(summary),
+		Source:    opts.ClusterName,
+		Severity:  "error",
+		Timestamp: timestamp.Format(time.RFC3339),
+		Component: opts.Name,
+		Group:     opts.Namespace,
+		Class:     string(opts.Status),
+		CustomDetails: map[string]string{
+			"info":    opts.Info,
+			"version": strconv.Itoa(opts.Version),
+		},
+	}
+
+	if opts.URL != "" {
+		event.Links = []*Link{{Href: opts.URL, Text: "View the application on Porter"}}
+	}
+
+	return send(n.ctx, n.sender, n.pagerDutyInts, event)
+}
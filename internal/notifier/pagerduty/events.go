@@ -0,0 +1,90 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier/webhook"
+)
+
+// EventsAPIURL is the endpoint of the PagerDuty Events API v2
+const EventsAPIURL = "https://events.pagerduty.com/v2/enqueue"
+
+// EventAction is the action of a PagerDuty event
+type EventAction string
+
+const (
+	// EventAction_Trigger opens an alert, or adds to the open alert with the same dedup key
+	EventAction_Trigger EventAction = "trigger"
+
+	// EventAction_Resolve resolves the open alert with the same dedup key
+	EventAction_Resolve EventAction = "resolve"
+)
+
+// Event is an event sent to the PagerDuty Events API v2
+type Event struct {
+	RoutingKey  string        `json:"routing_key"`
+	EventAction EventAction   `json:"event_action"`
+	DedupKey    string        `json:"dedup_key"`
+	Payload     *EventPayload `json:"payload,omitempty"`
+	Links       []*Link       `json:"links,omitempty"`
+	Client      string        `json:"client,omitempty"`
+	ClientURL   string        `json:"client_url,omitempty"`
+}
+
+// EventPayload describes the alert of a trigger event
+type EventPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// Link is a link attached to an alert
+type Link struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// send sends the event to the service of each integration, using the routing key of the integration
+func send(ctx context.Context, sender *webhook.Sender, pagerDutyInts []*integrations.NotifierIntegration, event Event) error {
+	if event.Payload != nil && event.Payload.Timestamp == "" {
+		event.Payload.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	event.Client = "Porter"
+
+	var errs []error
+
+	for _, pagerDutyInt := range pagerDutyInts {
+		event.RoutingKey = string(pagerDutyInt.Secret)
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		if err := sender.Post(ctx, EventsAPIURL, payload, nil); err != nil {
+			errs = append(errs, fmt.Errorf("pagerduty integration %s: %w", pagerDutyInt.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// truncate shortens the summary of an alert to the 1024 characters allowed by PagerDuty
+func truncate(summary string) string {
+	if len(summary) <= 1024 {
+		return summary
+	}
+
+	return summary[:1021] + "..."
+}
@@ -0,0 +1,79 @@
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier/webhook"
+)
+
+// IncidentNotifier triggers a PagerDuty alert for each incident, and resolves it with the incident
+type IncidentNotifier struct {
+	ctx           context.Context
+	pagerDutyInts []*integrations.NotifierIntegration
+	sender        *webhook.Sender
+}
+
+// NewIncidentNotifier returns an IncidentNotifier which sends events to the given PagerDuty integrations until ctx is done
+func NewIncidentNotifier(ctx context.Context, pagerDutyInts ...*integrations.NotifierIntegration) *IncidentNotifier {
+	return &IncidentNotifier{
+		ctx:           ctx,
+		pagerDutyInts: pagerDutyInts,
+		sender:        webhook.NewSender(),
+	}
+}
+
+// NotifyNew sends a trigger event for the incident
+func (n *IncidentNotifier) NotifyNew(incident *types.Incident, url string) error {
+	severity := "error"
+	if incident.Severity == types.SeverityCritical {
+		severity = "critical"
+	}
+
+	summary := incident.ShortSummary
+	if summary == "" {
+		summary = incident.Summary
+	}
+
+	event := Event{
+		EventAction: EventAction_Trigger,
+		DedupKey:    dedupKey(incident),
+		ClientURL:   url,
+		Payload: &EventPayload{
+			Summary:   truncate(fmt.Sprintf("%s: %s", incident.ReleaseName, summary)),
+			Source:    incident.InvolvedObjectName,
+			Severity:  severity,
+			Timestamp: incident.CreatedAt.UTC().Format(time.RFC3339),
+			Component: incident.ReleaseName,
+			Group:     incident.ReleaseNamespace,
+			Class:     string(incident.InvolvedObjectKind),
+			CustomDetails: map[string]string{
+				"summary":  incident.Summary,
+				"detail":   incident.Detail,
+				"revision": incident.Revision,
+			},
+		},
+	}
+
+	if url != "" {
+		event.Links = []*Link{{Href: url, Text: "View the incident on Porter"}}
+	}
+
+	return send(n.ctx, n.sender, n.pagerDutyInts, event)
+}
+
+// NotifyResolved sends a resolve event for the incident
+func (n *IncidentNotifier) NotifyResolved(incident *types.Incident, url string) error {
+	return send(n.ctx, n.sender, n.pagerDutyInts, Event{
+		EventAction: EventAction_Resolve,
+		DedupKey:    dedupKey(incident),
+		ClientURL:   url,
+	})
+}
+
+func dedupKey(incident *types.Incident) string {
+	return fmt.Sprintf("porter-incident-%s", incident.ID)
+}
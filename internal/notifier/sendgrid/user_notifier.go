@@ -1,6 +1,8 @@
 package sendgrid
 
 import (
+	"time"
+
 	"github.com/porter-dev/porter/internal/notifier"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
@@ -12,11 +14,12 @@ type UserNotifier struct {
 
 type UserNotifierOpts struct {
 	*SharedOpts
-	PWResetTemplateID       string
-	PWGHTemplateID          string
-	VerifyEmailTemplateID   string
-	ProjectInviteTemplateID string
-	DeleteProjectTemplateID string
+	PWResetTemplateID        string
+	PWGHTemplateID           string
+	VerifyEmailTemplateID    string
+	ProjectInviteTemplateID  string
+	DeleteProjectTemplateID  string
+	APITokenExpiryTemplateID string
 }
 
 func NewUserNotifier(opts *UserNotifierOpts) notifier.UserNotifier {
@@ -183,3 +186,38 @@ func (s *UserNotifier) SendProjectDeleteEmail(opts *notifier.SendProjectDeleteEm
 
 	return err
 }
+
+func (s *UserNotifier) SendAPITokenExpiryEmail(opts *notifier.SendAPITokenExpiryEmailOpts) error {
+	request := sendgrid.GetRequest(s.opts.APIKey, "/v3/mail/send", "https://api.sendgrid.com")
+	request.Method = "POST"
+
+	sgMail := &mail.SGMailV3{
+		Personalizations: []*mail.Personalization{
+			{
+				To: []*mail.Email{
+					{
+						Address: opts.Email,
+					},
+				},
+				DynamicTemplateData: map[string]interface{}{
+					"email":      opts.Email,
+					"project":    opts.Project,
+					"token_name": opts.TokenName,
+					"expires_at": opts.ExpiresAt.UTC().Format(time.RFC1123),
+					"url":        opts.URL,
+				},
+			},
+		},
+		From: &mail.Email{
+			Address: s.opts.SenderEmail,
+			Name:    "Porter",
+		},
+		TemplateID: s.opts.APITokenExpiryTemplateID,
+	}
+
+	request.Body = mail.GetRequestBody(sgMail)
+
+	_, err := sendgrid.API(request)
+
+	return err
+}
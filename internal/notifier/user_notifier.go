@@ -1,5 +1,7 @@
 package notifier
 
+import "time"
+
 type SendPasswordResetEmailOpts struct {
 	Email string
 	URL   string
@@ -27,12 +29,21 @@ type SendProjectDeleteEmailOpts struct {
 	Email   string
 }
 
+type SendAPITokenExpiryEmailOpts struct {
+	Email     string
+	Project   string
+	TokenName string
+	ExpiresAt time.Time
+	URL       string
+}
+
 type UserNotifier interface {
 	SendPasswordResetEmail(opts *SendPasswordResetEmailOpts) error
 	SendGithubRelinkEmail(opts *SendGithubRelinkEmailOpts) error
 	SendEmailVerification(opts *SendEmailVerificationOpts) error
 	SendProjectInviteEmail(opts *SendProjectInviteEmailOpts) error
 	SendProjectDeleteEmail(opts *SendProjectDeleteEmailOpts) error
+	SendAPITokenExpiryEmail(opts *SendAPITokenExpiryEmailOpts) error
 }
 
 type EmptyUserNotifier struct{}
@@ -56,3 +67,7 @@ func (e *EmptyUserNotifier) SendProjectInviteEmail(opts *SendProjectInviteEmailO
 func (e *EmptyUserNotifier) SendProjectDeleteEmail(opts *SendProjectDeleteEmailOpts) error {
 	return nil
 }
+
+func (e *EmptyUserNotifier) SendAPITokenExpiryEmail(opts *SendAPITokenExpiryEmailOpts) error {
+	return nil
+}
@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier"
+)
+
+// EventType is the type of event sent to a webhook
+type EventType string
+
+const (
+	// EventType_Deployment is sent when a deployment succeeds, fails or crashes
+	EventType_Deployment EventType = "deployment"
+
+	// EventType_IncidentNew is sent when an incident is opened
+	EventType_IncidentNew EventType = "incident.new"
+
+	// EventType_IncidentResolved is sent when an incident is resolved
+	EventType_IncidentResolved EventType = "incident.resolved"
+)
+
+// Event is the JSON payload posted to a webhook
+type Event struct {
+	Type      EventType `json:"type"`
+	ProjectID uint      `json:"project_id"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// URL links to the deployment or incident in the Porter dashboard
+	URL string `json:"url"`
+
+	Deployment *DeploymentEvent `json:"deployment,omitempty"`
+	Incident   *types.Incident  `json:"incident,omitempty"`
+}
+
+// DeploymentEvent describes the deployment of an event of type EventType_Deployment
+type DeploymentEvent struct {
+	ClusterID   uint                      `json:"cluster_id"`
+	ClusterName string                    `json:"cluster_name"`
+	Name        string                    `json:"name"`
+	Namespace   string                    `json:"namespace"`
+	Status      notifier.DeploymentStatus `json:"status"`
+	Info        string                    `json:"info,omitempty"`
+	Version     int                       `json:"version,omitempty"`
+}
+
+// DeploymentNotifier posts deployment events to generic webhooks
+type DeploymentNotifier struct {
+	ctx         context.Context
+	webhookInts []*integrations.NotifierIntegration
+	sender      *Sender
+}
+
+// NewDeploymentNotifier returns a DeploymentNotifier which posts to the given webhook integrations until ctx is done
+func NewDeploymentNotifier(ctx context.Context, webhookInts ...*integrations.NotifierIntegration) *DeploymentNotifier {
+	return &DeploymentNotifier{
+		ctx:         ctx,
+		webhookInts: webhookInts,
+		sender:      NewSender(),
+	}
+}
+
+// Notify posts a deployment event to each webhook
+func (n *DeploymentNotifier) Notify(opts *notifier.NotifyOpts) error {
+	timestamp := time.Now().UTC()
+	if opts.Timestamp != nil {
+		timestamp = opts.Timestamp.UTC()
+	}
+
+	return send(n.ctx, n.sender, n.webhookInts, &Event{
+		Type:      EventType_Deployment,
+		ProjectID: opts.ProjectID,
+		Timestamp: timestamp,
+		URL:       opts.URL,
+		Deployment: &DeploymentEvent{
+			ClusterID:   opts.ClusterID,
+			ClusterName: opts.ClusterName,
+			Name:        opts.Name,
+			Namespace:   opts.Namespace,
+			Status:      opts.Status,
+			Info:        opts.Info,
+			Version:     opts.Version,
+		},
+	})
+}
+
+// IncidentNotifier posts incident events to generic webhooks
+type IncidentNotifier struct {
+	ctx         context.Context
+	webhookInts []*integrations.NotifierIntegration
+	sender      *Sender
+}
+
+// NewIncidentNotifier returns an IncidentNotifier which posts to the given webhook integrations until ctx is done
+func NewIncidentNotifier(ctx context.Context, webhookInts ...*integrations.NotifierIntegration) *IncidentNotifier {
+	return &IncidentNotifier{
+		ctx:         ctx,
+		webhookInts: webhookInts,
+		sender:      NewSender(),
+	}
+}
+
+// NotifyNew posts an incident.new event to each webhook
+func (n *IncidentNotifier) NotifyNew(incident *types.Incident, url string) error {
+	return send(n.ctx, n.sender, n.webhookInts, &Event{
+		Type:      EventType_IncidentNew,
+		Timestamp: time.Now().UTC(),
+		URL:       url,
+		Incident:  incident,
+	})
+}
+
+// NotifyResolved posts an incident.resolved event to each webhook
+func (n *IncidentNotifier) NotifyResolved(incident *types.Incident, url string) error {
+	return send(n.ctx, n.sender, n.webhookInts, &Event{
+		Type:      EventType_IncidentResolved,
+		Timestamp: time.Now().UTC(),
+		URL:       url,
+		Incident:  incident,
+	})
+}
+
+// send posts the event to each webhook, signing it if the webhook has a signing secret
+func send(ctx context.Context, sender *Sender, webhookInts []*integrations.NotifierIntegration, event *Event) error {
+	var errs []error
+
+	for _, webhookInt := range webhookInts {
+		if event.ProjectID == 0 {
+			event.ProjectID = webhookInt.ProjectID
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		header := SignatureHeaders(webhookInt.Secret, payload, time.Now())
+		if len(webhookInt.Secret) == 0 {
+			header.Del(HeaderSignature)
+		}
+		header.Set(HeaderEvent, string(event.Type))
+
+		if err := sender.Post(ctx, string(webhookInt.URL), payload, header); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", webhookInt.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
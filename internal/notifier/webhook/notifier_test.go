@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/notifier"
+)
+
+func TestDeploymentNotifierPayload(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewDeploymentNotifier(context.Background(), &integrations.NotifierIntegration{
+		ProjectID: 1,
+		Name:      "hooks",
+		URL:       []byte(server.URL),
+		Secret:    []byte("secret"),
+	})
+	n.sender = testSender(server)
+
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := n.Notify(&notifier.NotifyOpts{
+		ClusterID:   2,
+		ClusterName: "production",
+		Name:        "web",
+		Namespace:   "default",
+		Status:      notifier.StatusHelmFailed,
+		Info:        "image pull failed",
+		Version:     3,
+		URL:         "https://dashboard.porter.run/apps/web",
+		Timestamp:   &timestamp,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("expected a json payload, got %s", body)
+	}
+
+	expected := map[string]interface{}{
+		"type":       "deployment",
+		"project_id": float64(1),
+		"timestamp":  "2024-01-02T03:04:05Z",
+		"url":        "https://dashboard.porter.run/apps/web",
+		"deployment": map[string]interface{}{
+			"cluster_id":   float64(2),
+			"cluster_name": "production",
+			"name":         "web",
+			"namespace":    "default",
+			"status":       "helm_failed",
+			"info":         "image pull failed",
+			"version":      float64(3),
+		},
+	}
+	expectedBytes, _ := json.Marshal(expected)
+	gotBytes, _ := json.Marshal(event)
+	if string(expectedBytes) != string(gotBytes) {
+		t.Errorf("expected payload\n%s\ngot\n%s", expectedBytes, gotBytes)
+	}
+
+	if got := header.Get(HeaderEvent); got != string(EventType_Deployment) {
+		t.Errorf("expected event header %s, got %s", EventType_Deployment, got)
+	}
+	if got := header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected json content type, got %s", got)
+	}
+
+	ts := header.Get(HeaderTimestamp)
+	if _, err := strconv.ParseInt(ts, 10, 64); err != nil {
+		t.Errorf("expected unix timestamp header, got %s", ts)
+	}
+	if got := header.Get(HeaderSignature); got != "sha256="+Sign([]byte("secret"), ts, body) {
+		t.Errorf("expected signature of the payload, got %s", got)
+	}
+}
+
+func TestNotifierWithoutSecretIsUnsigned(t *testing.T) {
+	var header http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewIncidentNotifier(context.Background(), &integrations.NotifierIntegration{
+		ProjectID: 1,
+		Name:      "hooks",
+		URL:       []byte(server.URL),
+	})
+	n.sender = testSender(server)
+
+	if err := n.NotifyResolved(nil, "https://dashboard.porter.run"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := header.Get(HeaderSignature); got != "" {
+		t.Errorf("expected no signature, got %s", got)
+	}
+	if got := header.Get(HeaderEvent); got != string(EventType_IncidentResolved) {
+		t.Errorf("expected event header %s, got %s", EventType_IncidentResolved, got)
+	}
+}
+
+func TestSignVector(t *testing.T) {
+	// computed independently with: printf '1700000000.{"type":"deployment"}' | openssl dgst -sha256 -hmac secret
+	expected := "36ce2b493b75e693cc0f9fa1245476db93ba48d28b65725faaf2b8e71c13e9a3"
+
+	if got := Sign([]byte("secret"), "1700000000", []byte(`{"type":"deployment"}`)); got != expected {
+		t.Errorf("expected signature %s, got %s", expected, got)
+	}
+}
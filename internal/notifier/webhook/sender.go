@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/porter-dev/porter/internal/safehttp"
+)
+
+const (
+	// HeaderSignature is the header containing the HMAC-SHA256 signature of a payload, as "sha256=<hex digest>"
+	HeaderSignature = "X-Porter-Signature"
+
+	// HeaderTimestamp is the header containing the unix time at which a payload was signed
+	HeaderTimestamp = "X-Porter-Timestamp"
+
+	// HeaderEvent is the header containing the type of event in a payload
+	HeaderEvent = "X-Porter-Event"
+)
+
+// Sender posts JSON payloads to webhooks, retrying on network errors, rate limits and server errors
+type Sender struct {
+	Client *http.Client
+
+	// MaxAttempts is the maximum number of times a payload is sent
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry, which doubles with each retry
+	Backoff time.Duration
+}
+
+// NewSender returns a Sender with a 5 second timeout, which attempts to send each payload 3 times. Webhook urls are
+// configured by users, so the sender only makes https requests to public addresses.
+func NewSender() *Sender {
+	return &Sender{
+		Client:      safehttp.NewClient(time.Second * 5),
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond * 500,
+	}
+}
+
+// Post sends the payload to the url with the given headers, and returns an error if every attempt failed. Attempts
+// and the delays between them stop once the context is done.
+func (s *Sender) Post(ctx context.Context, url string, payload []byte, header http.Header) error {
+	var lastErr error
+
+	backoff := s.Backoff
+
+	for attempt := 1; attempt <= s.MaxAttempts; attempt++ {
+		retry, err := s.post(ctx, url, payload, header)
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		if !retry || attempt == s.MaxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("error sending webhook after %d attempts: %w", attempt, errors.Join(lastErr, ctx.Err()))
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+
+	return fmt.Errorf("error sending webhook after %d attempts: %w", s.MaxAttempts, lastErr)
+}
+
+// post sends the payload once, returning whether a failed request should be retried
+func (s *Sender) post(ctx context.Context, url string, payload []byte, header http.Header) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+
+	return retry, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// SignatureHeaders returns the headers signing the payload with the secret at the given time. The signature
+// is the hex-encoded HMAC-SHA256 of the timestamp and the payload, joined by a period.
+func SignatureHeaders(secret []byte, payload []byte, timestamp time.Time) http.Header {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	header := http.Header{}
+	header.Set(HeaderTimestamp, ts)
+	header.Set(HeaderSignature, "sha256="+Sign(secret, ts, payload))
+
+	return header
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of the timestamp and the payload, joined by a period
+func Sign(secret []byte, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testSender returns a sender which can reach the test server, without delays between attempts
+func testSender(server *httptest.Server) *Sender {
+	return &Sender{
+		Client:      server.Client(),
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	}
+}
+
+func TestSenderRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantAttempts int32
+	}{
+		{
+			name:         "succeeds after a server error",
+			statuses:     []int{http.StatusInternalServerError, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			name:         "retries rate limits",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusNoContent},
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after the maximum attempts",
+			statuses:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			wantErr:      true,
+			wantAttempts: 3,
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := attempts.Add(1)
+				w.WriteHeader(tt.statuses[attempt-1])
+			}))
+			defer server.Close()
+
+			err := testSender(server).Post(context.Background(), server.URL, []byte(`{}`), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %t, got %v", tt.wantErr, err)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, got)
+			}
+		})
+	}
+}
+
+func TestSenderStopsWhenContextIsDone(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := testSender(server)
+	sender.Backoff = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := sender.Post(ctx, server.URL, []byte(`{}`), nil)
+	if err == nil {
+		t.Fatalf("expected an error once the context is done")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the retries to stop with the context, took %s", elapsed)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestSenderRejectsNonPublicURLs(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	sender := NewSender()
+	sender.Backoff = time.Millisecond
+
+	for _, url := range []string{
+		server.URL,
+		"https://169.254.169.254/latest/meta-data",
+		"http://hooks.example.com/porter",
+	} {
+		if err := sender.Post(context.Background(), url, []byte(`{}`), nil); err == nil {
+			t.Errorf("expected post to %s to be rejected", url)
+		}
+	}
+
+	if got := requests.Load(); got != 0 {
+		t.Errorf("expected no request to reach the server, got %d", got)
+	}
+}
@@ -0,0 +1,282 @@
+package opa
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/open-policy-agent/opa/rego"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// PorterAppPolicyKind is the kind of policy bundle which is evaluated against each service of an app when the app is applied
+const PorterAppPolicyKind = "porter_app"
+
+// builtInAppPolicyFS contains the porter_app policies which are evaluated for every project
+//
+//go:embed policies/porter_app/*.rego
+var builtInAppPolicyFS embed.FS
+
+var (
+	builtInAppPoliciesOnce sync.Once
+	builtInAppPolicies     []appPolicy
+	builtInAppPoliciesErr  error
+)
+
+// appPolicy is a compiled porter_app policy, along with how its violations are handled
+type appPolicy struct {
+	query            rego.PreparedEvalQuery
+	enforcement      types.OPAPolicyEnforcement
+	overrideSeverity string
+}
+
+// EvaluateAppPolicies evaluates the built-in porter_app policies and the porter_app bundles of a project against each
+// service of the app, and returns a violation for every service which does not pass a policy. Violations of the
+// built-in policies are always warnings.
+func EvaluateAppPolicies(ctx context.Context, app *porterv1.PorterApp, bundles []*models.OPAPolicyBundle) ([]types.PorterAppPolicyViolation, error) {
+	violations := make([]types.PorterAppPolicyViolation, 0)
+
+	if app == nil {
+		return violations, nil
+	}
+
+	policies, err := loadBuiltInAppPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bundle := range bundles {
+		if bundle.Kind != PorterAppPolicyKind {
+			continue
+		}
+
+		bundleType, err := bundle.ToOPAPolicyBundleType()
+		if err != nil {
+			return nil, fmt.Errorf("error reading policy bundle %s: %w", bundle.Name, err)
+		}
+
+		bundlePolicies, err := appPoliciesFromBundle(ctx, bundleType)
+		if err != nil {
+			return nil, fmt.Errorf("error loading policy bundle %s: %w", bundle.Name, err)
+		}
+
+		policies = append(policies, bundlePolicies...)
+	}
+
+	appInput, err := protoToInput(app)
+	if err != nil {
+		return nil, fmt.Errorf("error converting app to policy input: %w", err)
+	}
+
+	for _, service := range appServices(app) {
+		serviceInput, err := protoToInput(service)
+		if err != nil {
+			return nil, fmt.Errorf("error converting service %s to policy input: %w", service.Name, err)
+		}
+
+		input := map[string]interface{}{
+			"app":          appInput,
+			"service":      serviceInput,
+			"service_name": service.Name,
+			"service_type": appServiceType(service),
+		}
+
+		for _, policy := range policies {
+			results, err := policy.query.Eval(ctx, rego.EvalInput(input))
+			if err != nil {
+				return nil, fmt.Errorf("error evaluating policy for service %s: %w", service.Name, err)
+			}
+
+			if len(results) != 1 {
+				continue
+			}
+
+			rawQueryRes := &rawQueryResult{}
+			err = mapstructure.Decode(results[0].Expressions[0].Value, rawQueryRes)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding policy result for service %s: %w", service.Name, err)
+			}
+
+			if rawQueryRes.Allow {
+				continue
+			}
+
+			severity := rawQueryRes.PolicySeverity
+			if policy.overrideSeverity != "" {
+				severity = policy.overrideSeverity
+			}
+
+			violations = append(violations, types.PorterAppPolicyViolation{
+				ServiceName: service.Name,
+				PolicyID:    rawQueryRes.PolicyID,
+				PolicyTitle: rawQueryRes.PolicyTitle,
+				Severity:    severity,
+				Enforcement: policy.enforcement,
+				Messages:    rawQueryRes.FailureMessage,
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// loadBuiltInAppPolicies compiles the embedded porter_app policies once, and returns a copy of them
+func loadBuiltInAppPolicies(ctx context.Context) ([]appPolicy, error) {
+	builtInAppPoliciesOnce.Do(func() {
+		paths, err := fs.Glob(builtInAppPolicyFS, "policies/porter_app/*.rego")
+		if err != nil {
+			builtInAppPoliciesErr = err
+			return
+		}
+
+		for _, p := range paths {
+			module, err := builtInAppPolicyFS.ReadFile(p)
+			if err != nil {
+				builtInAppPoliciesErr = err
+				return
+			}
+
+			query, err := compilePolicy(context.Background(), types.OPAPolicy{
+				Name:   path.Base(p),
+				Module: string(module),
+			})
+			if err != nil {
+				builtInAppPoliciesErr = err
+				return
+			}
+
+			builtInAppPolicies = append(builtInAppPolicies, appPolicy{
+				query:       query,
+				enforcement: types.OPAPolicyEnforcement_Warn,
+			})
+		}
+	})
+
+	if builtInAppPoliciesErr != nil {
+		return nil, fmt.Errorf("error loading built-in app policies: %w", builtInAppPoliciesErr)
+	}
+
+	return append([]appPolicy{}, builtInAppPolicies...), nil
+}
+
+// appPoliciesFromBundle compiles the policies of a porter_app bundle
+func appPoliciesFromBundle(ctx context.Context, bundle *types.OPAPolicyBundle) ([]appPolicy, error) {
+	if bundle.Kind != PorterAppPolicyKind {
+		return nil, fmt.Errorf("bundle %s is not a %s bundle", bundle.Name, PorterAppPolicyKind)
+	}
+
+	if bundle.MustExist {
+		return nil, fmt.Errorf("must_exist is not supported for %s bundles", PorterAppPolicyKind)
+	}
+
+	enforcement := bundle.Enforcement
+	switch enforcement {
+	case "":
+		enforcement = types.OPAPolicyEnforcement_Warn
+	case types.OPAPolicyEnforcement_Deny, types.OPAPolicyEnforcement_Warn:
+	default:
+		return nil, fmt.Errorf("unsupported enforcement %s", enforcement)
+	}
+
+	queries, err := compileBundlePolicies(ctx, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]appPolicy, 0, len(queries))
+	for _, query := range queries {
+		policies = append(policies, appPolicy{
+			query:            query,
+			enforcement:      enforcement,
+			overrideSeverity: bundle.OverrideSeverity,
+		})
+	}
+
+	return policies, nil
+}
+
+// appServices returns the services, predeploy job and initial deploy job of an app, each named as in porter.yaml
+func appServices(app *porterv1.PorterApp) []*porterv1.Service {
+	var services []*porterv1.Service
+
+	if app.ServiceList != nil {
+		services = append(services, app.ServiceList...)
+	} else {
+		names := make([]string, 0, len(app.Services)) // nolint:staticcheck // temporarily using deprecated field for backwards compatibility
+		for name := range app.Services {              // nolint:staticcheck // temporarily using deprecated field for backwards compatibility
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			service := proto.Clone(app.Services[name]).(*porterv1.Service) // nolint:staticcheck // temporarily using deprecated field for backwards compatibility
+			service.Name = name
+			services = append(services, service)
+		}
+	}
+
+	if app.Predeploy != nil {
+		predeploy := proto.Clone(app.Predeploy).(*porterv1.Service)
+		predeploy.Name = "predeploy"
+		services = append(services, predeploy)
+	}
+
+	if app.InitialDeploy != nil {
+		initialDeploy := proto.Clone(app.InitialDeploy).(*porterv1.Service)
+		initialDeploy.Name = "initialDeploy"
+		services = append(services, initialDeploy)
+	}
+
+	return services
+}
+
+// appServiceType returns the type of a service as written in porter.yaml
+func appServiceType(service *porterv1.Service) string {
+	switch {
+	case service.GetWebConfig() != nil:
+		return "web"
+	case service.GetWorkerConfig() != nil:
+		return "worker"
+	case service.GetJobConfig() != nil:
+		return "job"
+	}
+
+	switch service.Type {
+	case porterv1.ServiceType_SERVICE_TYPE_WEB:
+		return "web"
+	case porterv1.ServiceType_SERVICE_TYPE_WORKER:
+		return "worker"
+	case porterv1.ServiceType_SERVICE_TYPE_JOB:
+		return "job"
+	}
+
+	return ""
+}
+
+// protoToInput converts a proto message to a policy input which uses the proto field names, like
+// `cpu_cores` and `web_config`. As in the proto JSON mapping, 64-bit integers are encoded as strings.
+func protoToInput(message proto.Message) (map[string]interface{}, error) {
+	by, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	input := make(map[string]interface{})
+	err = json.Unmarshal(by, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	return input, nil
+}
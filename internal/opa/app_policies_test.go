@@ -0,0 +1,251 @@
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func compliantServices() []*porterv1.Service {
+	private := true
+
+	return []*porterv1.Service{
+		{
+			Name:     "web",
+			CpuCores: 1,
+			Config: &porterv1.Service_WebConfig{
+				WebConfig: &porterv1.WebServiceConfig{
+					HealthCheck: &porterv1.HealthCheck{Enabled: true, HttpPath: "/healthz"},
+				},
+			},
+		},
+		{
+			Name:     "internal",
+			CpuCores: 1,
+			Config: &porterv1.Service_WebConfig{
+				WebConfig: &porterv1.WebServiceConfig{Private: &private},
+			},
+		},
+		{
+			Name:     "cron",
+			CpuCores: 0.5,
+			Config: &porterv1.Service_JobConfig{
+				JobConfig: &porterv1.JobServiceConfig{Cron: "*/5 * * * *", TimeoutSeconds: 600},
+			},
+		},
+		{
+			Name: "adhoc",
+			Config: &porterv1.Service_JobConfig{
+				JobConfig: &porterv1.JobServiceConfig{},
+			},
+		},
+	}
+}
+
+func policyIDsByService(violations []types.PorterAppPolicyViolation) map[string][]string {
+	res := make(map[string][]string)
+	for _, violation := range violations {
+		res[violation.ServiceName] = append(res[violation.ServiceName], violation.PolicyID)
+	}
+	return res
+}
+
+func TestBuiltInAppPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []*porterv1.Service
+		expected map[string][]string
+	}{
+		{
+			name:     "compliant services pass",
+			services: compliantServices(),
+			expected: map[string][]string{},
+		},
+		{
+			name: "cpu over the cap",
+			services: []*porterv1.Service{
+				{
+					Name:     "worker",
+					CpuCores: 8,
+					Config:   &porterv1.Service_WorkerConfig{WorkerConfig: &porterv1.WorkerServiceConfig{}},
+				},
+			},
+			expected: map[string][]string{"worker": {"porter_app_cpu_cap"}},
+		},
+		{
+			name: "public web service without a health check",
+			services: []*porterv1.Service{
+				{
+					Name:   "web",
+					Config: &porterv1.Service_WebConfig{WebConfig: &porterv1.WebServiceConfig{}},
+				},
+			},
+			expected: map[string][]string{"web": {"porter_app_web_health_check"}},
+		},
+		{
+			name: "cron job without a timeout",
+			services: []*porterv1.Service{
+				{
+					Name:   "cron",
+					Config: &porterv1.Service_JobConfig{JobConfig: &porterv1.JobServiceConfig{Cron: "0 * * * *"}},
+				},
+			},
+			expected: map[string][]string{"cron": {"porter_app_cron_job_timeout"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := EvaluateAppPolicies(context.Background(), &porterv1.PorterApp{
+				Name:        "test-app",
+				ServiceList: tt.services,
+			}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := policyIDsByService(violations)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected violations %v, got %v", tt.expected, got)
+			}
+			for service, policyIDs := range tt.expected {
+				if len(got[service]) != len(policyIDs) || got[service][0] != policyIDs[0] {
+					t.Errorf("expected service %s to violate %v, got %v", service, policyIDs, got[service])
+				}
+			}
+
+			for _, violation := range violations {
+				if violation.Enforcement != types.OPAPolicyEnforcement_Warn {
+					t.Errorf("expected built-in policy %s to warn, got %s", violation.PolicyID, violation.Enforcement)
+				}
+				if violation.PolicyTitle == "" || len(violation.Messages) == 0 {
+					t.Errorf("expected violation of %s to have a title and message", violation.PolicyID)
+				}
+			}
+		})
+	}
+}
+
+const noLatestTagPolicy = `package project.no_latest_tag
+
+import future.keywords.if
+
+POLICY_ID := "no_latest_tag"
+
+POLICY_TITLE := "Images should not use the latest tag"
+
+POLICY_SEVERITY := "high"
+
+default allow := false
+
+allow if {
+	input.app.image.tag != "latest"
+}
+`
+
+// slowPolicy iterates over millions of pairs without ever matching, to simulate an expensive user policy
+const slowPolicy = `package project.slow
+
+import future.keywords.if
+
+POLICY_ID := "slow"
+
+POLICY_TITLE := "Slow policy"
+
+default allow := true
+
+allow := false if {
+	some i, j
+	numbers.range(1, 10000)[i]
+	numbers.range(1, 10000)[j]
+	i * j < 0
+}
+`
+
+func appPolicyBundle(t *testing.T, enforcement types.OPAPolicyEnforcement, module string) *models.OPAPolicyBundle {
+	t.Helper()
+
+	policies, err := json.Marshal([]types.OPAPolicy{{Name: "policy.rego", Module: module}})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return &models.OPAPolicyBundle{
+		Name:        "project-policies",
+		Kind:        PorterAppPolicyKind,
+		Enforcement: string(enforcement),
+		Policies:    policies,
+	}
+}
+
+func TestAppPolicyBundles(t *testing.T) {
+	app := &porterv1.PorterApp{
+		Name:        "test-app",
+		Image:       &porterv1.AppImage{Repository: "nginx", Tag: "latest"},
+		ServiceList: compliantServices()[:1],
+	}
+
+	t.Run("deny bundles reject the app", func(t *testing.T) {
+		violations, err := EvaluateAppPolicies(context.Background(), app, []*models.OPAPolicyBundle{
+			appPolicyBundle(t, types.OPAPolicyEnforcement_Deny, noLatestTagPolicy),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(violations) != 1 || violations[0].PolicyID != "no_latest_tag" || violations[0].Enforcement != types.OPAPolicyEnforcement_Deny {
+			t.Fatalf("expected a single denial of no_latest_tag, got %+v", violations)
+		}
+		if violations[0].ServiceName != "web" || violations[0].PolicyTitle != "Images should not use the latest tag" {
+			t.Errorf("expected denial to report the service and policy title, got %+v", violations[0])
+		}
+	})
+
+	t.Run("evaluation stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := EvaluateAppPolicies(ctx, app, []*models.OPAPolicyBundle{
+			appPolicyBundle(t, types.OPAPolicyEnforcement_Deny, slowPolicy),
+		})
+		if err == nil {
+			t.Fatalf("expected an error when the evaluation deadline is exceeded")
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected the evaluation to stop at the deadline, took %s", elapsed)
+		}
+	})
+
+	t.Run("bundles of other kinds are skipped", func(t *testing.T) {
+		bundle := appPolicyBundle(t, types.OPAPolicyEnforcement_Deny, noLatestTagPolicy)
+		bundle.Kind = string(Pod)
+
+		violations, err := EvaluateAppPolicies(context.Background(), app, []*models.OPAPolicyBundle{bundle})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(violations) != 0 {
+			t.Errorf("expected no violations, got %+v", violations)
+		}
+	})
+}
+
+func TestValidateAppPolicyBundle(t *testing.T) {
+	bundle := &types.OPAPolicyBundle{
+		Name:        "apps",
+		Kind:        PorterAppPolicyKind,
+		Enforcement: types.OPAPolicyEnforcement_Deny,
+		Policies:    []types.OPAPolicy{{Name: "policy.rego", Module: noLatestTagPolicy}},
+	}
+
+	if err := ValidatePolicyBundle(context.Background(), bundle); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package opa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// PolicyBundleCategoryPrefix prefixes the category names of user-supplied policy bundles, so that they cannot
+// shadow the built-in policy collections
+const PolicyBundleCategoryPrefix = "project."
+
+// unsafeBuiltins are the built-in functions that user-supplied policies cannot call, as they would let a
+// policy reach the network or inspect the worker it runs in
+var unsafeBuiltins = map[string]struct{}{
+	ast.HTTPSend.Name:        {},
+	ast.NetLookupIPAddr.Name: {},
+	ast.OPARuntime.Name:      {},
+}
+
+// requiredPolicyRules are the rules that every user-supplied policy must define, so that its result can be
+// reported like the built-in policies
+var requiredPolicyRules = []string{"allow", "POLICY_ID", "POLICY_TITLE"}
+
+// ValidatePolicyBundle checks that the match parameters of a bundle are valid for its kind, and that each policy
+// compiles and defines the rules the recommender reads
+func ValidatePolicyBundle(ctx context.Context, bundle *types.OPAPolicyBundle) error {
+	if bundle.Kind == PorterAppPolicyKind {
+		_, err := appPoliciesFromBundle(ctx, bundle)
+		return err
+	}
+
+	if bundle.Enforcement != "" {
+		return fmt.Errorf("enforcement is only supported for %s bundles", PorterAppPolicyKind)
+	}
+
+	_, err := queryCollectionFromBundle(ctx, bundle)
+	return err
+}
+
+// WithPolicyBundles returns a copy of the policies which also contains the given user-supplied bundles. Bundles
+// of kind porter_app are skipped, as they are evaluated when an app is applied.
+func (p *KubernetesPolicies) WithPolicyBundles(ctx context.Context, bundles []*models.OPAPolicyBundle) (*KubernetesPolicies, error) {
+	policies := make(map[string]KubernetesOPAQueryCollection)
+
+	if p != nil {
+		for name, collection := range p.Policies {
+			policies[name] = collection
+		}
+	}
+
+	for _, bundle := range bundles {
+		if bundle.Kind == PorterAppPolicyKind {
+			continue
+		}
+
+		bundleType, err := bundle.ToOPAPolicyBundleType()
+		if err != nil {
+			return nil, fmt.Errorf("error reading policy bundle %s: %w", bundle.Name, err)
+		}
+
+		collection, err := queryCollectionFromBundle(ctx, bundleType)
+		if err != nil {
+			return nil, fmt.Errorf("error loading policy bundle %s: %w", bundle.Name, err)
+		}
+
+		policies[PolicyBundleCategoryPrefix+bundle.Name] = collection
+	}
+
+	return &KubernetesPolicies{
+		Policies: policies,
+	}, nil
+}
+
+func queryCollectionFromBundle(ctx context.Context, bundle *types.OPAPolicyBundle) (KubernetesOPAQueryCollection, error) {
+	collection := KubernetesOPAQueryCollection{
+		Kind:             KubernetesBuiltInKind(bundle.Kind),
+		Match:            MatchParameters(bundle.Match),
+		MustExist:        bundle.MustExist,
+		OverrideSeverity: bundle.OverrideSeverity,
+		ObjectIDPrefix:   fmt.Sprintf("project_policy/%s", bundle.Name),
+	}
+
+	switch collection.Kind {
+	case HelmRelease:
+		if collection.Match.Name == "" && collection.Match.ChartName == "" {
+			return collection, fmt.Errorf("helm_release bundles must match on a release name or chart name")
+		}
+	case CRDList:
+		if collection.Match.Version == "" || collection.Match.Resource == "" {
+			return collection, fmt.Errorf("crd_list bundles must match on a version and resource")
+		}
+	case Pod, Daemonset:
+	default:
+		return collection, fmt.Errorf("unsupported bundle kind %s", bundle.Kind)
+	}
+
+	if collection.MustExist && (collection.Kind != HelmRelease || collection.Match.Name == "") {
+		return collection, fmt.Errorf("must_exist is only supported for helm_release bundles which match on a release name")
+	}
+
+	queries, err := compileBundlePolicies(ctx, bundle)
+	if err != nil {
+		return collection, err
+	}
+	collection.Queries = queries
+
+	return collection, nil
+}
+
+// compileBundlePolicies prepares a query for each policy of a bundle, checking that it defines the required rules
+func compileBundlePolicies(ctx context.Context, bundle *types.OPAPolicyBundle) ([]rego.PreparedEvalQuery, error) {
+	if len(bundle.Policies) == 0 {
+		return nil, fmt.Errorf("bundle must contain at least one policy")
+	}
+
+	queries := make([]rego.PreparedEvalQuery, 0, len(bundle.Policies))
+
+	for _, policy := range bundle.Policies {
+		query, err := compilePolicy(ctx, policy)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, query)
+	}
+
+	return queries, nil
+}
+
+// compilePolicy parses a Rego module and prepares a query for its package, without access to unsafe built-ins
+func compilePolicy(ctx context.Context, policy types.OPAPolicy) (rego.PreparedEvalQuery, error) {
+	module, err := ast.ParseModule(policy.Name, policy.Module)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, fmt.Errorf("error parsing policy %s: %w", policy.Name, err)
+	}
+	if module == nil {
+		return rego.PreparedEvalQuery{}, fmt.Errorf("policy %s is empty", policy.Name)
+	}
+
+	rules := make(map[string]bool)
+	for _, rule := range module.Rules {
+		rules[rule.Head.Name.String()] = true
+	}
+
+	for _, required := range requiredPolicyRules {
+		if !rules[required] {
+			return rego.PreparedEvalQuery{}, fmt.Errorf("policy %s must define the rule %s", policy.Name, required)
+		}
+	}
+
+	query, err := rego.New(
+		rego.Query(module.Package.Path.String()),
+		rego.ParsedModule(module),
+		rego.UnsafeBuiltins(unsafeBuiltins),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, fmt.Errorf("error compiling policy %s: %w", policy.Name, err)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,199 @@
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+const privilegedPodPolicy = `package project.no_privileged_pods
+
+import future.keywords.if
+
+POLICY_ID := "no_privileged_pods"
+
+POLICY_TITLE := "Pods should not run privileged containers"
+
+POLICY_SEVERITY := "high"
+
+default allow := true
+
+allow := false if {
+	input.spec.containers[_].securityContext.privileged
+}
+`
+
+func TestValidatePolicyBundle(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundle  types.OPAPolicyBundle
+		wantErr bool
+	}{
+		{
+			name: "valid pod bundle",
+			bundle: types.OPAPolicyBundle{
+				Name:     "pods",
+				Kind:     string(Pod),
+				Policies: []types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}},
+			},
+		},
+		{
+			name: "valid helm release bundle",
+			bundle: types.OPAPolicyBundle{
+				Name:      "releases",
+				Kind:      string(HelmRelease),
+				Match:     types.OPAPolicyBundleMatch{Name: "ingress-nginx"},
+				MustExist: true,
+				Policies:  []types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}},
+			},
+		},
+		{
+			name: "bundle without policies",
+			bundle: types.OPAPolicyBundle{
+				Name: "pods",
+				Kind: string(Pod),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported kind",
+			bundle: types.OPAPolicyBundle{
+				Name:     "secrets",
+				Kind:     "secret",
+				Policies: []types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy which does not parse",
+			bundle: types.OPAPolicyBundle{
+				Name:     "pods",
+				Kind:     string(Pod),
+				Policies: []types.OPAPolicy{{Name: "policy.rego", Module: "package project.broken\n\nallow := {\n"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy without required rules",
+			bundle: types.OPAPolicyBundle{
+				Name:     "pods",
+				Kind:     string(Pod),
+				Policies: []types.OPAPolicy{{Name: "policy.rego", Module: "package project.empty\n\nallow := true\n"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy calling http.send",
+			bundle: types.OPAPolicyBundle{
+				Name: "pods",
+				Kind: string(Pod),
+				Policies: []types.OPAPolicy{{Name: "policy.rego", Module: `package project.exfiltrate
+
+POLICY_ID := "exfiltrate"
+
+POLICY_TITLE := "Exfiltrate"
+
+allow := http.send({"method": "GET", "url": "http://example.com"}).status_code == 200
+`}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "enforcement on a recommender bundle",
+			bundle: types.OPAPolicyBundle{
+				Name:        "pods",
+				Kind:        string(Pod),
+				Enforcement: types.OPAPolicyEnforcement_Deny,
+				Policies:    []types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "helm release bundle without a match",
+			bundle: types.OPAPolicyBundle{
+				Name:     "releases",
+				Kind:     string(HelmRelease),
+				Policies: []types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "crd list bundle without a resource",
+			bundle: types.OPAPolicyBundle{
+				Name:     "certificates",
+				Kind:     string(CRDList),
+				Match:    types.OPAPolicyBundleMatch{Version: "v1"},
+				Policies: []types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "must exist on a bundle matching a chart",
+			bundle: types.OPAPolicyBundle{
+				Name:      "releases",
+				Kind:      string(HelmRelease),
+				Match:     types.OPAPolicyBundleMatch{ChartName: "ingress-nginx"},
+				MustExist: true,
+				Policies:  []types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := tt.bundle
+			err := ValidatePolicyBundle(context.Background(), &bundle)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithPolicyBundles(t *testing.T) {
+	policies, err := json.Marshal([]types.OPAPolicy{{Name: "policy.rego", Module: privilegedPodPolicy}})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	builtIn := &KubernetesPolicies{
+		Policies: map[string]KubernetesOPAQueryCollection{
+			"pods": {Kind: Pod},
+		},
+	}
+
+	res, err := builtIn.WithPolicyBundles(context.Background(), []*models.OPAPolicyBundle{
+		{Name: "pods", Kind: string(Pod), Policies: policies},
+		{Name: "apps", Kind: PorterAppPolicyKind, Policies: policies},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res.Policies) != 2 {
+		t.Fatalf("expected the built-in policies and the pod bundle, got %d collections", len(res.Policies))
+	}
+
+	collection, ok := res.Policies[PolicyBundleCategoryPrefix+"pods"]
+	if !ok {
+		t.Fatalf("expected the pod bundle to be prefixed with %s", PolicyBundleCategoryPrefix)
+	}
+	if len(collection.Queries) != 1 || collection.ObjectIDPrefix != "project_policy/pods" {
+		t.Errorf("unexpected collection for the pod bundle: %+v", collection)
+	}
+
+	if len(builtIn.Policies) != 1 {
+		t.Errorf("expected the built-in policies to be left unchanged, got %d collections", len(builtIn.Policies))
+	}
+
+	_, err = builtIn.WithPolicyBundles(context.Background(), []*models.OPAPolicyBundle{
+		{Name: "broken", Kind: string(Pod), Policies: []byte(`[{"name":"policy.rego","module":"package broken"}]`)},
+	})
+	if err == nil {
+		t.Errorf("expected an error for a bundle whose policy does not define the required rules")
+	}
+}
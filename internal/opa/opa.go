@@ -47,6 +47,10 @@ type KubernetesOPAQueryCollection struct {
 	MustExist        bool
 	OverrideSeverity string
 	Queries          []rego.PreparedEvalQuery
+
+	// ObjectIDPrefix namespaces the object IDs of the results, so that results of user-supplied
+	// policies are stored separately from the built-in results for the same object
+	ObjectIDPrefix string
 }
 
 type MatchParameters struct {
@@ -75,6 +79,7 @@ type OPARecommenderQueryResult struct {
 	CategoryName string
 	ObjectID     string
 
+	PolicyID       string
 	PolicyVersion  string
 	PolicySeverity string
 	PolicyTitle    string
@@ -149,6 +154,12 @@ func (runner *KubernetesOPARunner) GetRecommendations(categories []string) ([]*O
 				continue
 			}
 
+			if queryCollection.ObjectIDPrefix != "" {
+				for _, currResult := range currResults {
+					currResult.ObjectID = fmt.Sprintf("%s/%s/%s", queryCollection.ObjectIDPrefix, currResult.ObjectID, currResult.PolicyID)
+				}
+			}
+
 			res = append(res, currResults...)
 		}
 	}
@@ -449,6 +460,7 @@ func rawQueryResToRecommenderQueryResult(rawQueryRes *rawQueryResult, objectID,
 	queryRes.Allow = rawQueryRes.Allow
 	queryRes.PolicySeverity = getSeverity(rawQueryRes.PolicySeverity, collection)
 	queryRes.PolicyTitle = rawQueryRes.PolicyTitle
+	queryRes.PolicyID = rawQueryRes.PolicyID
 	queryRes.PolicyVersion = rawQueryRes.PolicyVersion
 
 	return queryRes
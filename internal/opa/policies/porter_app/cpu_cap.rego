@@ -0,0 +1,27 @@
+package porter_app.cpu_cap
+
+import future.keywords.contains
+import future.keywords.if
+
+POLICY_ID := "porter_app_cpu_cap"
+
+POLICY_VERSION := "v0.0.1"
+
+POLICY_SEVERITY := "high"
+
+MAX_CPU_CORES := 4
+
+POLICY_TITLE := sprintf("Services should not request more than %d CPU cores", [MAX_CPU_CORES])
+
+POLICY_SUCCESS_MESSAGE := "Success: service is within the CPU cap"
+
+default allow := false
+
+allow if {
+	object.get(input.service, "cpu_cores", 0) <= MAX_CPU_CORES
+}
+
+FAILURE_MESSAGE contains msg if {
+	not allow
+	msg := sprintf("Service %s requests %v CPU cores", [input.service_name, input.service.cpu_cores])
+}
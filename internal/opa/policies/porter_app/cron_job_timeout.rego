@@ -0,0 +1,35 @@
+package porter_app.cron_job_timeout
+
+import future.keywords.contains
+import future.keywords.if
+
+POLICY_ID := "porter_app_cron_job_timeout"
+
+POLICY_VERSION := "v0.0.1"
+
+POLICY_SEVERITY := "low"
+
+POLICY_TITLE := "Cron jobs should set a timeout"
+
+POLICY_SUCCESS_MESSAGE := "Success: service is not a cron job or has a timeout"
+
+default allow := false
+
+is_cron_job if {
+	input.service_type == "job"
+	input.service.job_config.cron != ""
+}
+
+allow if {
+	not is_cron_job
+}
+
+# timeout_seconds is a 64-bit integer, which is encoded as a string in the service input
+allow if {
+	to_number(input.service.job_config.timeout_seconds) > 0
+}
+
+FAILURE_MESSAGE contains msg if {
+	not allow
+	msg := sprintf("Cron job %s runs on schedule %s without a timeout", [input.service_name, input.service.job_config.cron])
+}
@@ -0,0 +1,34 @@
+package porter_app.web_health_check
+
+import future.keywords.contains
+import future.keywords.if
+
+POLICY_ID := "porter_app_web_health_check"
+
+POLICY_VERSION := "v0.0.1"
+
+POLICY_SEVERITY := "high"
+
+POLICY_TITLE := "Public web services should have a health check"
+
+POLICY_SUCCESS_MESSAGE := "Success: service is private or has a health check"
+
+default allow := false
+
+is_public_web if {
+	input.service_type == "web"
+	not input.service.web_config.private
+}
+
+allow if {
+	not is_public_web
+}
+
+allow if {
+	input.service.web_config.health_check.enabled
+}
+
+FAILURE_MESSAGE contains msg if {
+	not allow
+	msg := sprintf("Web service %s is public but has no health check enabled", [input.service_name])
+}
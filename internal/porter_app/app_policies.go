@@ -0,0 +1,180 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/opa"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// AppPolicyEvaluationTimeout bounds the evaluation of the policies of an app, since policy bundles contain user-supplied Rego
+const AppPolicyEvaluationTimeout = 10 * time.Second
+
+// EvaluateAppPoliciesInput is the input to the EvaluateAppPolicies function
+type EvaluateAppPoliciesInput struct {
+	ProjectID uint
+	// App is the full app being deployed. Use MergeAppUpdate to get the deployed app from a partial update.
+	App  *porterv1.PorterApp
+	Repo repository.OPAPolicyBundleRepository
+}
+
+// AppPolicyEvaluation contains the policy violations of an app, split by how they are enforced
+type AppPolicyEvaluation struct {
+	Warnings []types.PorterAppPolicyViolation
+	Denials  []types.PorterAppPolicyViolation
+}
+
+// DenialsError returns an error which lists each denied service along with the title of the policy it violates, or nil if nothing is denied
+func (e AppPolicyEvaluation) DenialsError() error {
+	if len(e.Denials) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, 0, len(e.Denials))
+	for _, denial := range e.Denials {
+		descriptions = append(descriptions, denial.String())
+	}
+
+	return fmt.Errorf("app violates project policies: %s", strings.Join(descriptions, "; "))
+}
+
+// EvaluateAppPolicies evaluates the built-in porter_app policies and the porter_app policy bundles of the project against the app
+func EvaluateAppPolicies(ctx context.Context, inp EvaluateAppPoliciesInput) (AppPolicyEvaluation, error) {
+	ctx, span := telemetry.NewSpan(ctx, "evaluate-app-policies")
+	defer span.End()
+
+	var evaluation AppPolicyEvaluation
+
+	if inp.Repo == nil {
+		return evaluation, telemetry.Error(ctx, span, nil, "policy bundle repository is nil")
+	}
+
+	bundles, err := inp.Repo.ListOPAPolicyBundlesByProjectID(ctx, inp.ProjectID)
+	if err != nil {
+		return evaluation, telemetry.Error(ctx, span, err, "error listing policy bundles")
+	}
+
+	evalCtx, cancel := context.WithTimeout(ctx, AppPolicyEvaluationTimeout)
+	defer cancel()
+
+	violations, err := opa.EvaluateAppPolicies(evalCtx, inp.App, bundles)
+	if err != nil {
+		if errors.Is(evalCtx.Err(), context.DeadlineExceeded) {
+			return evaluation, telemetry.Error(ctx, span, err, fmt.Sprintf("app policies did not finish evaluating within %s", AppPolicyEvaluationTimeout))
+		}
+		return evaluation, telemetry.Error(ctx, span, err, "error evaluating app policies")
+	}
+
+	for _, violation := range violations {
+		if violation.Enforcement == types.OPAPolicyEnforcement_Deny {
+			evaluation.Denials = append(evaluation.Denials, violation)
+			continue
+		}
+		evaluation.Warnings = append(evaluation.Warnings, violation)
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "policy-warnings", Value: len(evaluation.Warnings)},
+		telemetry.AttributeKV{Key: "policy-denials", Value: len(evaluation.Denials)},
+	)
+
+	return evaluation, nil
+}
+
+// MergeAppUpdate returns the app which results from applying a partial update to the current app, as done by the cluster control plane.
+// Fields set in the update replace those of the current app, nested messages and maps are merged, and services are merged by name.
+// Services named in deletedServiceNames are removed. The current app and the update are not modified.
+func MergeAppUpdate(current, update *porterv1.PorterApp, deletedServiceNames []string) *porterv1.PorterApp {
+	merged := &porterv1.PorterApp{}
+	if current != nil {
+		merged = proto.Clone(current).(*porterv1.PorterApp)
+	}
+
+	if update != nil {
+		update = proto.Clone(update).(*porterv1.PorterApp)
+
+		serviceList := mergeServiceList(merged.ServiceList, update.ServiceList)
+		update.ServiceList = nil
+
+		mergeMessage(merged.ProtoReflect(), update.ProtoReflect())
+		merged.ServiceList = serviceList
+	}
+
+	if len(deletedServiceNames) > 0 {
+		deleted := make(map[string]bool)
+		for _, name := range deletedServiceNames {
+			deleted[name] = true
+		}
+
+		var services []*porterv1.Service
+		for _, service := range merged.ServiceList {
+			if !deleted[service.Name] {
+				services = append(services, service)
+			}
+		}
+		merged.ServiceList = services
+
+		for name := range deleted {
+			delete(merged.Services, name) // nolint:staticcheck // temporarily using deprecated field for backwards compatibility
+		}
+	}
+
+	return merged
+}
+
+// mergeServiceList merges updated services into the current services by name, and appends new services
+func mergeServiceList(current, update []*porterv1.Service) []*porterv1.Service {
+	merged := append([]*porterv1.Service{}, current...)
+
+	for _, service := range update {
+		found := false
+		for _, existing := range merged {
+			if existing.Name == service.Name {
+				mergeMessage(existing.ProtoReflect(), service.ProtoReflect())
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, service)
+		}
+	}
+
+	return merged
+}
+
+// mergeMessage sets the populated fields of src on dst. Lists are replaced, while maps and messages which are set on both are merged.
+func mergeMessage(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			dst.Set(fd, v)
+		case fd.IsMap():
+			dstMap := dst.Mutable(fd).Map()
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				if fd.MapValue().Message() != nil && dstMap.Has(k) {
+					mergeMessage(dstMap.Mutable(k).Message(), mv.Message())
+					return true
+				}
+				dstMap.Set(k, mv)
+				return true
+			})
+		case fd.Message() != nil && dst.Has(fd):
+			mergeMessage(dst.Mutable(fd).Message(), v.Message())
+		default:
+			dst.Set(fd, v)
+		}
+		return true
+	})
+}
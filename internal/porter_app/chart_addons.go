@@ -0,0 +1,346 @@
+package porter_app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/oauth2"
+	v1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/stefanmcshane/helm/pkg/storage/driver"
+)
+
+// chartAddonCredentialLength is the length of the generated credentials for chart addons
+const chartAddonCredentialLength = 32
+
+// chartAddonsRecordKey is the key of the configmap data listing the chart addons installed for an app
+const chartAddonsRecordKey = "addons"
+
+// InstallChartAddonsInput is the input to the InstallChartAddons function
+type InstallChartAddonsInput struct {
+	// AppName is the name of the app which declares the addons
+	AppName string
+	// Addons are the chart addons declared in porter.yaml
+	Addons []v2.Addon
+	// Namespace is the namespace of the deployment target the addons are installed into
+	Namespace string
+	// AddonHelmRepoURL is the helm repository containing the addon charts
+	AddonHelmRepoURL string
+
+	Cluster                     *models.Cluster
+	HelmAgent                   *helm.Agent
+	K8sAgent                    *kubernetes.Agent
+	Repo                        repository.Repository
+	DOConf                      *oauth2.Config
+	DisablePullSecretsInjection bool
+}
+
+// InstallChartAddons installs or upgrades each chart addon in the deployment target namespace, and creates or updates an env group
+// named after the addon which contains its connection details. Credentials are generated on first install and kept in a secret
+// in the deployment target namespace, so they are stable across upgrades.
+//
+// The addons installed for an app are recorded in the namespace, so that addons which were removed from porter.yaml are uninstalled.
+// Their credentials and persistent volume claims are kept, so that the data of an addon is not lost if it is declared again.
+func InstallChartAddons(ctx context.Context, inp InstallChartAddonsInput) error {
+	ctx, span := telemetry.NewSpan(ctx, "install-chart-addons")
+	defer span.End()
+
+	if inp.AppName == "" {
+		return telemetry.Error(ctx, span, nil, "app name is empty")
+	}
+	if inp.HelmAgent == nil {
+		return telemetry.Error(ctx, span, nil, "helm agent is nil")
+	}
+	if inp.K8sAgent == nil {
+		return telemetry.Error(ctx, span, nil, "k8s agent is nil")
+	}
+	if inp.Cluster == nil {
+		return telemetry.Error(ctx, span, nil, "cluster is nil")
+	}
+	if inp.Namespace == "" {
+		return telemetry.Error(ctx, span, nil, "namespace is empty")
+	}
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+		telemetry.AttributeKV{Key: "namespace", Value: inp.Namespace},
+		telemetry.AttributeKV{Key: "addon-count", Value: len(inp.Addons)},
+	)
+
+	installedAddonNames, err := readChartAddonsRecord(ctx, inp.K8sAgent, inp.Namespace, inp.AppName)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error reading installed chart addons")
+	}
+
+	if len(inp.Addons) == 0 && len(installedAddonNames) == 0 {
+		return nil
+	}
+
+	registries, err := inp.Repo.Registry().ListRegistriesByProjectID(inp.Cluster.ProjectID)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error listing project registries")
+	}
+
+	for _, addon := range inp.Addons {
+		definition, err := v2.ValidateAddon(ctx, addon)
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error validating addon")
+		}
+
+		chartDefinition, ok := definition.(v2.ChartAddonDefinition)
+		if !ok {
+			return telemetry.Error(ctx, span, nil, "addon is not installed from a chart")
+		}
+
+		credentialsSecretName := fmt.Sprintf("%s-credentials", addon.Name)
+
+		credentials, err := getOrCreateChartAddonCredentials(ctx, inp.K8sAgent, inp.Namespace, credentialsSecretName, chartDefinition.CredentialKeys())
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error getting addon credentials")
+		}
+
+		chart, err := loader.LoadChartPublic(ctx, inp.AddonHelmRepoURL, chartDefinition.ChartName(), "")
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error loading addon chart")
+		}
+
+		_, err = inp.HelmAgent.UpgradeInstallChart(ctx, &helm.InstallChartConfig{
+			Chart:      chart,
+			Name:       addon.Name,
+			Namespace:  inp.Namespace,
+			Values:     chartDefinition.Values(addon, credentialsSecretName),
+			Cluster:    inp.Cluster,
+			Repo:       inp.Repo,
+			Registries: registries,
+		}, inp.DOConf, inp.DisablePullSecretsInjection)
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error installing addon chart")
+		}
+
+		host := fmt.Sprintf("%s.%s.svc.cluster.local", addon.Name, inp.Namespace)
+		variables, secretVariables := chartDefinition.ConnectionVariables(addon, host, credentials)
+
+		err = environment_groups.CreateOrUpdateBaseEnvironmentGroup(ctx, inp.K8sAgent, environment_groups.EnvironmentGroup{
+			Name:            addon.Name,
+			Variables:       variables,
+			SecretVariables: secretVariables,
+		}, map[string]string{
+			environment_groups.LabelKey_DefaultAddonEnvironment: "true",
+		})
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error creating addon env group")
+		}
+
+		_, err = environment_groups.SyncLatestVersionToNamespace(ctx, inp.K8sAgent, environment_groups.SyncLatestVersionToNamespaceInput{
+			BaseEnvironmentGroupName: addon.Name,
+			TargetNamespace:          inp.Namespace,
+		}, nil)
+		if err != nil {
+			return telemetry.Error(ctx, span, err, "error syncing addon env group to namespace")
+		}
+	}
+
+	declared := make(map[string]bool)
+	var addonNames []string
+	for _, addon := range inp.Addons {
+		declared[addon.Name] = true
+		addonNames = append(addonNames, addon.Name)
+	}
+
+	for _, name := range installedAddonNames {
+		if declared[name] {
+			continue
+		}
+
+		if err := uninstallChartAddon(ctx, inp.HelmAgent, inp.K8sAgent, name); err != nil {
+			return telemetry.Error(ctx, span, err, "error uninstalling removed chart addon")
+		}
+	}
+
+	if err := writeChartAddonsRecord(ctx, inp.K8sAgent, inp.Namespace, inp.AppName, addonNames); err != nil {
+		return telemetry.Error(ctx, span, err, "error recording installed chart addons")
+	}
+
+	return nil
+}
+
+// uninstallChartAddon uninstalls the release of a chart addon, and deletes its env group unless an app is still linked to it
+func uninstallChartAddon(ctx context.Context, helmAgent *helm.Agent, k8sAgent *kubernetes.Agent, name string) error {
+	ctx, span := telemetry.NewSpan(ctx, "uninstall-chart-addon")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "addon-name", Value: name})
+
+	_, err := helmAgent.UninstallChart(ctx, name)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return telemetry.Error(ctx, span, err, "error uninstalling addon chart")
+	}
+
+	linkedApplications, err := environment_groups.LinkedApplications(ctx, k8sAgent, name, true)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error listing apps linked to addon env group")
+	}
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "linked-applications", Value: len(linkedApplications)})
+
+	if len(linkedApplications) > 0 {
+		return nil
+	}
+
+	if err := environment_groups.DeleteEnvironmentGroup(ctx, k8sAgent, name); err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting addon env group")
+	}
+
+	return nil
+}
+
+// chartAddonsRecordName returns the name of the configmap listing the chart addons installed for an app
+func chartAddonsRecordName(appName string) string {
+	return fmt.Sprintf("%s-chart-addons", appName)
+}
+
+// readChartAddonsRecord returns the names of the chart addons installed for an app in a namespace
+func readChartAddonsRecord(ctx context.Context, agent *kubernetes.Agent, namespace, appName string) ([]string, error) {
+	configMap, err := agent.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, chartAddonsRecordName(appName), metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if configMap.Data[chartAddonsRecordKey] == "" {
+		return nil, nil
+	}
+
+	return strings.Split(configMap.Data[chartAddonsRecordKey], ","), nil
+}
+
+// writeChartAddonsRecord records the names of the chart addons installed for an app in a namespace
+func writeChartAddonsRecord(ctx context.Context, agent *kubernetes.Agent, namespace, appName string, addonNames []string) error {
+	configMaps := agent.Clientset.CoreV1().ConfigMaps(namespace)
+	name := chartAddonsRecordName(appName)
+
+	if len(addonNames) == 0 {
+		err := configMaps.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !k8serror.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	data := map[string]string{
+		chartAddonsRecordKey: strings.Join(addonNames, ","),
+	}
+
+	configMap, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return err
+		}
+
+		_, err = configMaps.Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					environment_groups.LabelKey_PorterManaged: "true",
+				},
+			},
+			Data: data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+
+	configMap.Data = data
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// getOrCreateChartAddonCredentials returns the credentials stored in the given secret, generating any keys which do not exist yet
+func getOrCreateChartAddonCredentials(ctx context.Context, agent *kubernetes.Agent, namespace, secretName string, keys []string) (map[string]string, error) {
+	ctx, span := telemetry.NewSpan(ctx, "get-or-create-chart-addon-credentials")
+	defer span.End()
+
+	credentials := make(map[string]string)
+
+	if len(keys) == 0 {
+		return credentials, nil
+	}
+
+	secrets := agent.Clientset.CoreV1().Secrets(namespace)
+
+	secret, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return credentials, telemetry.Error(ctx, span, err, "error getting credentials secret")
+		}
+		secret = nil
+	}
+
+	var changed bool
+	data := make(map[string][]byte)
+	if secret != nil {
+		for k, v := range secret.Data {
+			data[k] = v
+		}
+	}
+
+	for _, key := range keys {
+		if value, ok := data[key]; ok && len(value) > 0 {
+			credentials[key] = string(value)
+			continue
+		}
+
+		value, err := kubernetes.RandomString(chartAddonCredentialLength)
+		if err != nil {
+			return credentials, telemetry.Error(ctx, span, err, "error generating credential")
+		}
+
+		data[key] = []byte(value)
+		credentials[key] = value
+		changed = true
+	}
+
+	if !changed {
+		return credentials, nil
+	}
+
+	if secret == nil {
+		_, err = secrets.Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+				Labels: map[string]string{
+					environment_groups.LabelKey_PorterManaged: "true",
+				},
+			},
+			Data: data,
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return credentials, telemetry.Error(ctx, span, err, "error creating credentials secret")
+		}
+
+		return credentials, nil
+	}
+
+	secret.Data = data
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return credentials, telemetry.Error(ctx, span, err, "error updating credentials secret")
+	}
+
+	return credentials, nil
+}
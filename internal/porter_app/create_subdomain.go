@@ -14,6 +14,7 @@ import (
 type CreatePorterSubdomainInput struct {
 	AppName             string
 	RootDomain          string
+	ClusterID           uint
 	KubernetesAgent     *kubernetes.Agent
 	DNSClient           *dns.Client
 	DNSRecordRepository repository.DNSRecordRepository
@@ -51,6 +52,7 @@ func CreatePorterSubdomain(ctx context.Context, input CreatePorterSubdomainInput
 		ReleaseName: input.AppName,
 		RootDomain:  input.RootDomain,
 		Endpoint:    endpoint,
+		ClusterID:   input.ClusterID,
 	}
 
 	record := createDomainConf.NewDNSRecordForEndpoint()
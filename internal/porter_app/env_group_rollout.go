@@ -0,0 +1,316 @@
+package porter_app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"github.com/porter-dev/porter/internal/worker"
+)
+
+const (
+	defaultEnvGroupRolloutPollInterval = 5 * time.Second
+	defaultEnvGroupRolloutAppTimeout   = 15 * time.Minute
+)
+
+// EnvGroupRolloutJobID is the ID of the worker job which runs RolloutEnvGroup
+const EnvGroupRolloutJobID = "env-group-rollout"
+
+// EnqueueEnvGroupRollout enqueues a rollout of an environment group on the persistent worker queue, so that it survives restarts of the server.
+// Rollouts of the same environment group run one at a time, in the order they were enqueued.
+func EnqueueEnvGroupRollout(ctx context.Context, repo repository.WorkerJobRunRepository, projectID, clusterID uint, envGroupName string) (*models.WorkerJobRun, error) {
+	ctx, span := telemetry.NewSpan(ctx, "enqueue-env-group-rollout")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: clusterID},
+		telemetry.AttributeKV{Key: "env-group-name", Value: envGroupName},
+	)
+
+	if envGroupName == "" {
+		return nil, telemetry.Error(ctx, span, nil, "must provide an env group name")
+	}
+
+	run, err := worker.EnqueueJob(ctx, repo, worker.EnqueueJobInput{
+		JobID: EnvGroupRolloutJobID,
+		Input: map[string]interface{}{
+			"project_id":     projectID,
+			"cluster_id":     clusterID,
+			"env_group_name": envGroupName,
+		},
+		ConcurrencyKey: fmt.Sprintf("%s-%d-%s", EnvGroupRolloutJobID, clusterID, envGroupName),
+	})
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "unable to enqueue env group rollout")
+	}
+
+	return run, nil
+}
+
+// RolloutEnvGroupInput is the input struct for RolloutEnvGroup
+type RolloutEnvGroupInput struct {
+	ProjectID    uint
+	ClusterID    uint
+	EnvGroupName string
+
+	// LinkedApps are the apps which use the environment group, as returned by environment_groups.LinkedApplications
+	LinkedApps []environment_groups.LinkedPorterApplication
+
+	// PollInterval is how often the status of a new revision is checked. Defaults to 5 seconds
+	PollInterval time.Duration
+	// AppTimeout is how long a single app may take to deploy before the rollout is considered failed. Defaults to 15 minutes
+	AppTimeout time.Duration
+
+	CCPClient                  porterv1connect.ClusterControlPlaneServiceClient
+	PorterAppRepository        repository.PorterAppRepository
+	DeploymentTargetRepository repository.DeploymentTargetRepository
+	PorterAppEventRepository   repository.PorterAppEventRepository
+	OPAPolicyBundleRepository  repository.OPAPolicyBundleRepository
+}
+
+// RolloutEnvGroup redeploys the apps linked to an environment group one by one, so that they use its latest version.
+// Each redeploy is reported as an ENV_GROUP_ROLLOUT app event. The rollout stops at the first app which is denied by the project's
+// policies or fails to deploy, and the apps which were not redeployed get a canceled event.
+func RolloutEnvGroup(ctx context.Context, inp RolloutEnvGroupInput) error {
+	ctx, span := telemetry.NewSpan(ctx, "rollout-env-group")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: inp.ProjectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: inp.ClusterID},
+		telemetry.AttributeKV{Key: "env-group-name", Value: inp.EnvGroupName},
+		telemetry.AttributeKV{Key: "linked-apps", Value: len(inp.LinkedApps)},
+	)
+
+	if inp.EnvGroupName == "" {
+		return telemetry.Error(ctx, span, nil, "must provide an env group name")
+	}
+
+	if inp.PollInterval == 0 {
+		inp.PollInterval = defaultEnvGroupRolloutPollInterval
+	}
+	if inp.AppTimeout == 0 {
+		inp.AppTimeout = defaultEnvGroupRolloutAppTimeout
+	}
+
+	apps := make([]environment_groups.LinkedPorterApplication, len(inp.LinkedApps))
+	copy(apps, inp.LinkedApps)
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Namespace == apps[j].Namespace {
+			return apps[i].Name < apps[j].Name
+		}
+		return apps[i].Namespace < apps[j].Namespace
+	})
+
+	for i, app := range apps {
+		err := rolloutEnvGroupToApp(ctx, inp, app)
+		if err == nil {
+			continue
+		}
+
+		for _, skippedApp := range apps[i+1:] {
+			// the rollout has already failed, so the canceled events are best effort
+			_, _ = createEnvGroupRolloutEvent(ctx, inp, skippedApp, types.PorterAppEventStatus_Canceled, map[string]any{
+				"reason": fmt.Sprintf("rollout stopped because app %s failed to deploy", app.Name),
+			})
+		}
+
+		telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "failed-app-name", Value: app.Name})
+		return telemetry.Error(ctx, span, err, "env group rollout failed")
+	}
+
+	return nil
+}
+
+func rolloutEnvGroupToApp(ctx context.Context, inp RolloutEnvGroupInput, app environment_groups.LinkedPorterApplication) error {
+	ctx, span := telemetry.NewSpan(ctx, "rollout-env-group-to-app")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "app-name", Value: app.Name},
+		telemetry.AttributeKV{Key: "app-namespace", Value: app.Namespace},
+	)
+
+	event, err := createEnvGroupRolloutEvent(ctx, inp, app, types.PorterAppEventStatus_Progressing, nil)
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error creating rollout event")
+	}
+
+	var revisionID string
+	err = evaluateRolloutPolicies(ctx, inp, app, event)
+	if err == nil {
+		revisionID, err = redeployAppWithEnvGroup(ctx, inp, app, event.DeploymentTargetID)
+	}
+	if err == nil {
+		event.Metadata["app_revision_id"] = revisionID
+		err = waitForRevision(ctx, inp, revisionID)
+	}
+
+	event.Status = string(types.PorterAppEventStatus_Success)
+	if err != nil {
+		event.Status = string(types.PorterAppEventStatus_Failed)
+		event.Metadata["error"] = err.Error()
+	}
+
+	if updateErr := inp.PorterAppEventRepository.UpdateEvent(ctx, event); updateErr != nil {
+		return telemetry.Error(ctx, span, updateErr, "error updating rollout event")
+	}
+
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error redeploying app")
+	}
+
+	return nil
+}
+
+func createEnvGroupRolloutEvent(
+	ctx context.Context,
+	inp RolloutEnvGroupInput,
+	app environment_groups.LinkedPorterApplication,
+	status types.PorterAppEventStatus,
+	metadata map[string]any,
+) (*models.PorterAppEvent, error) {
+	porterApp, err := inp.PorterAppRepository.ReadPorterAppByName(inp.ClusterID, app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading porter app %s: %w", app.Name, err)
+	}
+	if porterApp == nil || porterApp.ID == 0 {
+		return nil, fmt.Errorf("porter app %s not found", app.Name)
+	}
+
+	deploymentTarget, err := inp.DeploymentTargetRepository.DeploymentTargetBySelectorAndSelectorType(
+		inp.ProjectID,
+		inp.ClusterID,
+		app.Namespace,
+		string(models.DeploymentTargetSelectorType_Namespace),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error reading deployment target of namespace %s: %w", app.Namespace, err)
+	}
+	if deploymentTarget == nil || deploymentTarget.ID == uuid.Nil {
+		return nil, fmt.Errorf("deployment target of namespace %s not found", app.Namespace)
+	}
+
+	event := &models.PorterAppEvent{
+		ID:                 uuid.New(),
+		Status:             string(status),
+		Type:               string(types.PorterAppEventType_EnvGroupRollout),
+		TypeExternalSource: "PORTER",
+		PorterAppID:        porterApp.ID,
+		DeploymentTargetID: deploymentTarget.ID,
+		Metadata: map[string]any{
+			"env_group_name": inp.EnvGroupName,
+		},
+	}
+	for k, v := range metadata {
+		event.Metadata[k] = v
+	}
+
+	if err := inp.PorterAppEventRepository.CreateEvent(ctx, event); err != nil {
+		return nil, fmt.Errorf("error creating porter app event: %w", err)
+	}
+
+	return event, nil
+}
+
+// evaluateRolloutPolicies checks that the current revision of an app passes the project's policies before it is redeployed with the env group
+func evaluateRolloutPolicies(ctx context.Context, inp RolloutEnvGroupInput, app environment_groups.LinkedPorterApplication, event *models.PorterAppEvent) error {
+	currentResp, err := inp.CCPClient.CurrentAppRevision(ctx, connect.NewRequest(&porterv1.CurrentAppRevisionRequest{
+		ProjectId: int64(inp.ProjectID),
+		AppId:     int64(event.PorterAppID),
+		AppName:   app.Name,
+		DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
+			Id: event.DeploymentTargetID.String(),
+		},
+	}))
+	if err != nil {
+		return fmt.Errorf("error getting current revision of app %s: %w", app.Name, err)
+	}
+	if currentResp == nil || currentResp.Msg == nil || currentResp.Msg.AppRevision == nil {
+		return fmt.Errorf("current revision of app %s not found", app.Name)
+	}
+
+	evaluation, err := EvaluateAppPolicies(ctx, EvaluateAppPoliciesInput{
+		ProjectID: inp.ProjectID,
+		App:       currentResp.Msg.AppRevision.App,
+		Repo:      inp.OPAPolicyBundleRepository,
+	})
+	if err != nil {
+		return fmt.Errorf("error evaluating policies of app %s: %w", app.Name, err)
+	}
+
+	return evaluation.DenialsError()
+}
+
+// redeployAppWithEnvGroup creates a new revision of an app. The latest version of the env group is attached by the cluster control plane.
+func redeployAppWithEnvGroup(ctx context.Context, inp RolloutEnvGroupInput, app environment_groups.LinkedPorterApplication, deploymentTargetID uuid.UUID) (string, error) {
+	updateReq := connect.NewRequest(&porterv1.UpdateAppRequest{
+		ProjectId: int64(inp.ProjectID),
+		DeploymentTargetIdentifier: &porterv1.DeploymentTargetIdentifier{
+			Id: deploymentTargetID.String(),
+		},
+		App: &porterv1.PorterApp{
+			Name: app.Name,
+			EnvGroups: []*porterv1.EnvGroup{
+				{
+					Name: inp.EnvGroupName,
+				},
+			},
+		},
+	})
+
+	ccpResp, err := inp.CCPClient.UpdateApp(ctx, updateReq)
+	if err != nil {
+		return "", fmt.Errorf("error calling ccp update app: %w", err)
+	}
+	if ccpResp == nil || ccpResp.Msg == nil || ccpResp.Msg.AppRevisionId == "" {
+		return "", fmt.Errorf("ccp update app did not return a revision")
+	}
+
+	return ccpResp.Msg.AppRevisionId, nil
+}
+
+// waitForRevision polls the status of a revision until it succeeds, fails or times out
+func waitForRevision(ctx context.Context, inp RolloutEnvGroupInput, revisionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, inp.AppTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(inp.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		statusResp, err := inp.CCPClient.AppRevisionStatus(ctx, connect.NewRequest(&porterv1.AppRevisionStatusRequest{
+			ProjectId:     int64(inp.ProjectID),
+			AppRevisionId: revisionID,
+		}))
+		if err != nil {
+			return fmt.Errorf("error getting status of revision %s: %w", revisionID, err)
+		}
+
+		if statusResp != nil && statusResp.Msg != nil {
+			switch statusResp.Msg.Status {
+			case porterv1.EnumAppRevisionStatus_ENUM_APP_REVISION_STATUS_SUCCESSFUL:
+				return nil
+			case porterv1.EnumAppRevisionStatus_ENUM_APP_REVISION_STATUS_FAILED:
+				return fmt.Errorf("revision %s failed to deploy", revisionID)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for revision %s to deploy", revisionID)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package porter_app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/safehttp"
+	"github.com/porter-dev/porter/internal/telemetry"
+)
+
+// LogArchiveWindow is the length of the windows in which logs are archived. Windows start on the hour, so that
+// archiving a window again overwrites the objects written the first time.
+const LogArchiveWindow = time.Hour
+
+// logArchiveUnknownService is the service name used in object keys for logs without a service label
+const logArchiveUnknownService = "_unknown"
+
+// logArchiveDialTimeout is the timeout for connecting to S3-compatible storage endpoints
+const logArchiveDialTimeout = 10 * time.Second
+
+// defaultLogArchivePageSize is the number of logs requested from the agent at a time
+const defaultLogArchivePageSize = 5000
+
+// LogArchiveStore writes archived logs to object storage
+type LogArchiveStore interface {
+	// PutObject writes a gzip-compressed newline-delimited JSON object, overwriting any object with the same key
+	PutObject(ctx context.Context, key string, body []byte) error
+}
+
+// S3LogArchiveStore writes archived logs to an S3 bucket, or to any S3-compatible storage
+type S3LogArchiveStore struct {
+	client *s3.S3
+	bucket string
+}
+
+// NewS3LogArchiveStore returns a store which writes to the bucket of the setting, using the credentials of the AWS integration
+func NewS3LogArchiveStore(setting *models.LogArchiveSetting, awsIntegration *integrations.AWSIntegration) (*S3LogArchiveStore, error) {
+	if setting == nil || awsIntegration == nil {
+		return nil, fmt.Errorf("log archive setting and aws integration must be set")
+	}
+
+	sess, err := awsIntegration.GetSession()
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws session: %w", err)
+	}
+
+	conf := &aws.Config{
+		Region: aws.String(setting.Region),
+	}
+
+	// S3-compatible storage generally does not support virtual-hosted-style requests. Endpoints are set by users, so they
+	// are only requested over https at public addresses.
+	if setting.Endpoint != "" {
+		if err := safehttp.ValidateURL(setting.Endpoint); err != nil {
+			return nil, fmt.Errorf("invalid endpoint: %w", err)
+		}
+
+		conf.Endpoint = aws.String(setting.Endpoint)
+		conf.S3ForcePathStyle = aws.Bool(true)
+		conf.HTTPClient = &http.Client{Transport: safehttp.NewTransport(logArchiveDialTimeout)}
+	}
+
+	return &S3LogArchiveStore{
+		client: s3.New(sess, conf),
+		bucket: setting.Bucket,
+	}, nil
+}
+
+// PutObject writes an object to the bucket
+func (s *S3LogArchiveStore) PutObject(ctx context.Context, key string, body []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Body:            bytes.NewReader(body),
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	return err
+}
+
+// LogArchiveObjectKey returns the key of the object holding the logs of a service for the window starting at windowStart.
+// Keys are partitioned by deployment target, app, service and day, such as
+// <prefix>/project_id=1/cluster_id=2/deployment_target_id=<uuid>/app=web/service=api/date=2023-01-02/hour=15.ndjson.gz
+func LogArchiveObjectKey(
+	prefix string,
+	projectID, clusterID uint,
+	deploymentTargetID uuid.UUID,
+	appName, serviceName string,
+	windowStart time.Time,
+) string {
+	if serviceName == "" {
+		serviceName = logArchiveUnknownService
+	}
+
+	windowStart = windowStart.UTC()
+
+	return path.Join(
+		prefix,
+		fmt.Sprintf("project_id=%d", projectID),
+		fmt.Sprintf("cluster_id=%d", clusterID),
+		fmt.Sprintf("deployment_target_id=%s", deploymentTargetID.String()),
+		fmt.Sprintf("app=%s", appName),
+		fmt.Sprintf("service=%s", serviceName),
+		fmt.Sprintf("date=%s", windowStart.Format("2006-01-02")),
+		fmt.Sprintf("hour=%s.ndjson.gz", windowStart.Format("15")),
+	)
+}
+
+// EncodeLogArchive encodes logs as gzip-compressed newline-delimited JSON
+func EncodeLogArchive(logs []StructuredLog) ([]byte, error) {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+
+	for _, log := range logs {
+		if err := enc.Encode(log); err != nil {
+			return nil, fmt.Errorf("error encoding log: %w", err)
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("error compressing logs: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ArchiveAppLogsInput is the input to ArchiveAppLogs
+type ArchiveAppLogsInput struct {
+	ProjectID uint
+	ClusterID uint
+	AppName   string
+
+	// DeploymentTargetID is the deployment target of the app. Only the logs of the app in this target are archived
+	DeploymentTargetID uuid.UUID
+
+	// Setting is the log archive setting of the project
+	Setting *models.LogArchiveSetting
+
+	// WindowStart is the start of the window to archive. The window ends LogArchiveWindow later
+	WindowStart time.Time
+
+	// Logs queries the logs stored by the porter agent of the cluster, such as porter_agent.Logs
+	Logs func(ctx context.Context, req *types.LogRequest) (*types.GetLogResponse, error)
+	// PageSize is the number of logs requested at a time. Defaults to 5000
+	PageSize uint
+
+	Store                LogArchiveStore
+	LogArchiveRepository repository.LogArchiveRepository
+}
+
+// ArchiveAppLogs archives the logs written by an app in a deployment target during a window, writing one object per service.
+// Services without logs in the window are skipped.
+func ArchiveAppLogs(ctx context.Context, inp ArchiveAppLogsInput) ([]*models.LogArchive, error) {
+	ctx, span := telemetry.NewSpan(ctx, "archive-app-logs")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: inp.ProjectID},
+		telemetry.AttributeKV{Key: "cluster-id", Value: inp.ClusterID},
+		telemetry.AttributeKV{Key: "app-name", Value: inp.AppName},
+		telemetry.AttributeKV{Key: "deployment-target-id", Value: inp.DeploymentTargetID.String()},
+		telemetry.AttributeKV{Key: "window-start", Value: inp.WindowStart.String()},
+	)
+
+	if inp.Setting == nil {
+		return nil, telemetry.Error(ctx, span, nil, "log archive setting is nil")
+	}
+	if inp.DeploymentTargetID == uuid.Nil {
+		return nil, telemetry.Error(ctx, span, nil, "deployment target id is nil")
+	}
+	if inp.Logs == nil || inp.Store == nil || inp.LogArchiveRepository == nil {
+		return nil, telemetry.Error(ctx, span, nil, "logs, store and log archive repository must be set")
+	}
+
+	pageSize := inp.PageSize
+	if pageSize == 0 {
+		pageSize = defaultLogArchivePageSize
+	}
+
+	windowStart := inp.WindowStart.UTC()
+	windowEnd := windowStart.Add(LogArchiveWindow)
+
+	logsByService := make(map[string][]StructuredLog)
+	startRange := windowStart
+
+	matchLabels := map[string]string{
+		lokiLabel_PorterAppName:      inp.AppName,
+		lokiLabel_DeploymentTargetId: inp.DeploymentTargetID.String(),
+	}
+
+	for {
+		endRange := windowEnd
+
+		resp, err := inp.Logs(ctx, &types.LogRequest{
+			Limit:       pageSize,
+			StartRange:  &startRange,
+			EndRange:    &endRange,
+			MatchLabels: matchLabels,
+			Direction:   "forward",
+		})
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error getting logs")
+		}
+		if resp == nil {
+			return nil, telemetry.Error(ctx, span, nil, "logs response is nil")
+		}
+
+		for _, log := range AgentLogToStructuredLog(resp.Logs) {
+			// the end of the range is inclusive for the agent, but belongs to the next window
+			if log.Timestamp.Before(windowStart) || !log.Timestamp.Before(windowEnd) {
+				continue
+			}
+
+			logsByService[log.ServiceName] = append(logsByService[log.ServiceName], log)
+		}
+
+		if resp.ForwardContinueTime == nil || !resp.ForwardContinueTime.After(startRange) || !resp.ForwardContinueTime.Before(windowEnd) {
+			break
+		}
+		startRange = *resp.ForwardContinueTime
+	}
+
+	serviceNames := make([]string, 0, len(logsByService))
+	for serviceName := range logsByService {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+
+	archives := make([]*models.LogArchive, 0, len(serviceNames))
+
+	for _, serviceName := range serviceNames {
+		logs := logsByService[serviceName]
+
+		body, err := EncodeLogArchive(logs)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error encoding logs")
+		}
+
+		key := LogArchiveObjectKey(inp.Setting.Prefix, inp.ProjectID, inp.ClusterID, inp.DeploymentTargetID, inp.AppName, serviceName, windowStart)
+
+		if err := inp.Store.PutObject(ctx, key, body); err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error writing archived logs")
+		}
+
+		archive, err := inp.LogArchiveRepository.UpsertLogArchive(ctx, &models.LogArchive{
+			ProjectID:          inp.ProjectID,
+			ClusterID:          inp.ClusterID,
+			DeploymentTargetID: inp.DeploymentTargetID,
+			AppName:            inp.AppName,
+			ServiceName:        serviceName,
+			Bucket:             inp.Setting.Bucket,
+			ObjectKey:          key,
+			StartTime:          windowStart,
+			EndTime:            windowEnd,
+			LineCount:          len(logs),
+			SizeBytes:          int64(len(body)),
+		})
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error saving log archive")
+		}
+
+		archives = append(archives, archive)
+	}
+
+	return archives, nil
+}
+
+// LogArchiveRanges merges the archives of each service into contiguous ranges of time, ordered by
+// cluster, deployment target, app, service and start time. Archives must be ordered by start time.
+func LogArchiveRanges(archives []*models.LogArchive) []types.LogArchiveRange {
+	type serviceKey struct {
+		clusterID          uint
+		deploymentTargetID string
+		appName            string
+		serviceName        string
+	}
+
+	var keys []serviceKey
+	rangesByService := make(map[serviceKey][]types.LogArchiveRange)
+
+	for _, archive := range archives {
+		key := serviceKey{archive.ClusterID, archive.DeploymentTargetID.String(), archive.AppName, archive.ServiceName}
+
+		ranges, ok := rangesByService[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+
+		if len(ranges) > 0 && !archive.StartTime.After(ranges[len(ranges)-1].EndTime) {
+			last := &ranges[len(ranges)-1]
+
+			if archive.EndTime.After(last.EndTime) {
+				last.EndTime = archive.EndTime
+			}
+			last.LineCount += archive.LineCount
+			last.Archives = append(last.Archives, archive.ToLogArchiveType())
+
+			continue
+		}
+
+		rangesByService[key] = append(ranges, types.LogArchiveRange{
+			ClusterID:          archive.ClusterID,
+			DeploymentTargetID: archive.DeploymentTargetID.String(),
+			AppName:            archive.AppName,
+			ServiceName:        archive.ServiceName,
+			StartTime:          archive.StartTime,
+			EndTime:            archive.EndTime,
+			LineCount:          archive.LineCount,
+			Archives:           []types.LogArchive{archive.ToLogArchiveType()},
+		})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].clusterID != keys[j].clusterID {
+			return keys[i].clusterID < keys[j].clusterID
+		}
+		if keys[i].deploymentTargetID != keys[j].deploymentTargetID {
+			return keys[i].deploymentTargetID < keys[j].deploymentTargetID
+		}
+		if keys[i].appName != keys[j].appName {
+			return keys[i].appName < keys[j].appName
+		}
+		return keys[i].serviceName < keys[j].serviceName
+	})
+
+	res := make([]types.LogArchiveRange, 0)
+	for _, key := range keys {
+		res = append(res, rangesByService[key]...)
+	}
+
+	return res
+}
@@ -11,10 +11,12 @@ type StructuredLog struct {
 	Timestamp          time.Time `json:"timestamp"`
 	Line               string    `json:"line"`
 	OutputStream       string    `json:"output_stream"`
+	AppName            string    `json:"app_name,omitempty"`
 	ServiceName        string    `json:"service_name"`
 	AppRevisionID      string    `json:"app_revision_id"`
 	DeploymentTargetID string    `json:"deployment_target_id"`
 	AppInstanceID      string    `json:"app_instance_id"`
+	PodName            string    `json:"pod_name,omitempty"`
 	JobName            string    `json:"job_name,omitempty"`
 	JobRunID           string    `json:"job_run_id,omitempty"`
 }
@@ -38,12 +40,14 @@ func AgentLogToStructuredLog(rawLogs []types.LogLine) []StructuredLog {
 		structuredLog := StructuredLog{
 			Line:               log.Line,
 			OutputStream:       log.Metadata.OutputStream,
+			AppName:            log.Metadata.RawLabels[lokiLabel_PorterAppName],
 			ServiceName:        log.Metadata.RawLabels[lokiLabel_PorterServiceName],
 			AppRevisionID:      log.Metadata.RawLabels[lokiLabel_PorterAppRevisionID],
 			DeploymentTargetID: log.Metadata.RawLabels[lokiLabel_DeploymentTargetId],
 			JobName:            log.Metadata.RawLabels[lokiLabel_JobRunName],
 			JobRunID:           log.Metadata.RawLabels[lokiLabel_ControllerUID],
 			AppInstanceID:      log.Metadata.RawLabels[lokiLabel_AppInstanceID],
+			PodName:            log.Metadata.PodName,
 		}
 
 		if log.Timestamp != nil {
@@ -55,3 +59,39 @@ func AgentLogToStructuredLog(rawLogs []types.LogLine) []StructuredLog {
 
 	return logs
 }
+
+// StructuredLogFilter filters structured logs. Empty fields match all logs
+type StructuredLogFilter struct {
+	AppName            string
+	DeploymentTargetID string
+	ServiceName        string
+	AppRevisionID      string
+	PodName            string
+}
+
+// FilterStructuredLogs returns the logs which match the filter
+func FilterStructuredLogs(logs []StructuredLog, filter StructuredLogFilter) []StructuredLog {
+	var filtered []StructuredLog
+
+	for _, log := range logs {
+		if filter.AppName != "" && log.AppName != filter.AppName {
+			continue
+		}
+		if filter.DeploymentTargetID != "" && log.DeploymentTargetID != filter.DeploymentTargetID {
+			continue
+		}
+		if filter.ServiceName != "" && log.ServiceName != filter.ServiceName {
+			continue
+		}
+		if filter.AppRevisionID != "" && log.AppRevisionID != filter.AppRevisionID {
+			continue
+		}
+		if filter.PodName != "" && log.PodName != filter.PodName {
+			continue
+		}
+
+		filtered = append(filtered, log)
+	}
+
+	return filtered
+}
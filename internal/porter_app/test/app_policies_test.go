@@ -0,0 +1,82 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func TestMergeAppUpdate(t *testing.T) {
+	current := &porterv1.PorterApp{
+		Name:  "test-app",
+		Image: &porterv1.AppImage{Repository: "nginx", Tag: "v1"},
+		EnvGroups: []*porterv1.EnvGroup{
+			{Name: "shared", Version: 1},
+			{Name: "secrets", Version: 2},
+		},
+		ServiceList: []*porterv1.Service{
+			{
+				Name:     "web",
+				CpuCores: 1,
+				Config: &porterv1.Service_WebConfig{
+					WebConfig: &porterv1.WebServiceConfig{
+						HealthCheck: &porterv1.HealthCheck{Enabled: true, HttpPath: "/healthz"},
+					},
+				},
+			},
+			{
+				Name:     "worker",
+				CpuCores: 1,
+				Config:   &porterv1.Service_WorkerConfig{WorkerConfig: &porterv1.WorkerServiceConfig{}},
+			},
+		},
+	}
+
+	t.Run("partial updates are merged into the current app", func(t *testing.T) {
+		is := is.New(t)
+
+		merged := porter_app.MergeAppUpdate(current, &porterv1.PorterApp{
+			Image: &porterv1.AppImage{Tag: "v2"},
+			EnvGroups: []*porterv1.EnvGroup{
+				{Name: "shared", Version: 3},
+			},
+			ServiceList: []*porterv1.Service{
+				{Name: "web", CpuCores: 8},
+				{Name: "cron", Config: &porterv1.Service_JobConfig{JobConfig: &porterv1.JobServiceConfig{Cron: "0 * * * *"}}},
+			},
+		}, nil)
+
+		is.Equal(merged.Image.Repository, "nginx")
+		is.Equal(merged.Image.Tag, "v2")
+		is.Equal(len(merged.EnvGroups), 1) // lists are replaced
+		is.Equal(merged.EnvGroups[0].Version, int64(3))
+		is.Equal(len(merged.ServiceList), 3)
+		is.Equal(merged.ServiceList[0].CpuCores, float32(8))
+		is.True(merged.ServiceList[0].GetWebConfig().GetHealthCheck().GetEnabled()) // fields missing from the update are kept
+		is.Equal(merged.ServiceList[2].Name, "cron")
+
+		is.Equal(current.Image.Tag, "v1") // the current app is not modified
+		is.Equal(current.ServiceList[0].CpuCores, float32(1))
+	})
+
+	t.Run("deleted services are removed", func(t *testing.T) {
+		is := is.New(t)
+
+		merged := porter_app.MergeAppUpdate(current, nil, []string{"worker"})
+
+		is.Equal(len(merged.ServiceList), 1)
+		is.Equal(merged.ServiceList[0].Name, "web")
+	})
+
+	t.Run("updates to new apps are returned as is", func(t *testing.T) {
+		is := is.New(t)
+
+		merged := porter_app.MergeAppUpdate(nil, current, nil)
+
+		is.Equal(merged.Name, "test-app")
+		is.Equal(len(merged.ServiceList), 2)
+	})
+}
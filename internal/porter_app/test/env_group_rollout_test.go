@@ -0,0 +1,256 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+type rolloutCCPClient struct {
+	porterv1connect.ClusterControlPlaneServiceClient
+
+	failingApps map[string]bool
+	updatedApps []string
+	revisionApp map[string]string
+	appImages   map[string]*porterv1.AppImage
+}
+
+func (c *rolloutCCPClient) CurrentAppRevision(ctx context.Context, req *connect.Request[porterv1.CurrentAppRevisionRequest]) (*connect.Response[porterv1.CurrentAppRevisionResponse], error) {
+	image, ok := c.appImages[req.Msg.AppName]
+	if !ok {
+		image = &porterv1.AppImage{Repository: "nginx", Tag: "v1"}
+	}
+
+	return connect.NewResponse(&porterv1.CurrentAppRevisionResponse{
+		AppRevision: &porterv1.AppRevision{
+			App: &porterv1.PorterApp{
+				Name:  req.Msg.AppName,
+				Image: image,
+				ServiceList: []*porterv1.Service{
+					{Name: "worker", Config: &porterv1.Service_WorkerConfig{WorkerConfig: &porterv1.WorkerServiceConfig{}}},
+				},
+			},
+		},
+	}), nil
+}
+
+func (c *rolloutCCPClient) UpdateApp(ctx context.Context, req *connect.Request[porterv1.UpdateAppRequest]) (*connect.Response[porterv1.UpdateAppResponse], error) {
+	appName := req.Msg.App.Name
+	c.updatedApps = append(c.updatedApps, appName)
+
+	revisionID := uuid.New().String()
+	c.revisionApp[revisionID] = appName
+
+	return connect.NewResponse(&porterv1.UpdateAppResponse{AppRevisionId: revisionID}), nil
+}
+
+func (c *rolloutCCPClient) AppRevisionStatus(ctx context.Context, req *connect.Request[porterv1.AppRevisionStatusRequest]) (*connect.Response[porterv1.AppRevisionStatusResponse], error) {
+	status := porterv1.EnumAppRevisionStatus_ENUM_APP_REVISION_STATUS_SUCCESSFUL
+	if c.failingApps[c.revisionApp[req.Msg.AppRevisionId]] {
+		status = porterv1.EnumAppRevisionStatus_ENUM_APP_REVISION_STATUS_FAILED
+	}
+
+	return connect.NewResponse(&porterv1.AppRevisionStatusResponse{Status: status}), nil
+}
+
+type rolloutPorterAppRepository struct {
+	repository.PorterAppRepository
+}
+
+func (r rolloutPorterAppRepository) ReadPorterAppByName(clusterID uint, name string) (*models.PorterApp, error) {
+	ids := map[string]uint{"api": 1, "web": 2, "worker": 3}
+
+	id, ok := ids[name]
+	if !ok {
+		return nil, errors.New("app not found")
+	}
+
+	app := &models.PorterApp{Name: name, ClusterID: clusterID}
+	app.ID = id
+
+	return app, nil
+}
+
+type rolloutDeploymentTargetRepository struct {
+	repository.DeploymentTargetRepository
+
+	id uuid.UUID
+}
+
+func (r rolloutDeploymentTargetRepository) DeploymentTargetBySelectorAndSelectorType(projectID uint, clusterID uint, selector, selectorType string) (*models.DeploymentTarget, error) {
+	return &models.DeploymentTarget{ID: r.id, Selector: selector, SelectorType: models.DeploymentTargetSelectorType(selectorType)}, nil
+}
+
+type rolloutPorterAppEventRepository struct {
+	repository.PorterAppEventRepository
+
+	events map[uuid.UUID]*models.PorterAppEvent
+}
+
+func (r *rolloutPorterAppEventRepository) CreateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	r.events[appEvent.ID] = appEvent
+	return nil
+}
+
+func (r *rolloutPorterAppEventRepository) UpdateEvent(ctx context.Context, appEvent *models.PorterAppEvent) error {
+	r.events[appEvent.ID] = appEvent
+	return nil
+}
+
+func (r *rolloutPorterAppEventRepository) statusByAppID() map[uint]string {
+	statuses := make(map[uint]string)
+	for _, event := range r.events {
+		statuses[event.PorterAppID] = event.Status
+	}
+	return statuses
+}
+
+func rolloutInput(ccpClient *rolloutCCPClient, events *rolloutPorterAppEventRepository, policies ...*models.OPAPolicyBundle) porter_app.RolloutEnvGroupInput {
+	policyRepo := test.NewOPAPolicyBundleRepository(true)
+	for _, policy := range policies {
+		_, _ = policyRepo.CreateOPAPolicyBundle(context.Background(), policy)
+	}
+
+	return porter_app.RolloutEnvGroupInput{
+		ProjectID:    1,
+		ClusterID:    1,
+		EnvGroupName: "shared",
+		LinkedApps: []environment_groups.LinkedPorterApplication{
+			{Name: "worker", Namespace: "default"},
+			{Name: "api", Namespace: "default"},
+			{Name: "web", Namespace: "default"},
+		},
+		PollInterval:               time.Millisecond,
+		AppTimeout:                 time.Second,
+		CCPClient:                  ccpClient,
+		PorterAppRepository:        rolloutPorterAppRepository{},
+		DeploymentTargetRepository: rolloutDeploymentTargetRepository{id: uuid.New()},
+		PorterAppEventRepository:   events,
+		OPAPolicyBundleRepository:  policyRepo,
+	}
+}
+
+func TestRolloutEnvGroup(t *testing.T) {
+	ccpClient := &rolloutCCPClient{revisionApp: make(map[string]string)}
+	events := &rolloutPorterAppEventRepository{events: make(map[uuid.UUID]*models.PorterAppEvent)}
+
+	err := porter_app.RolloutEnvGroup(context.Background(), rolloutInput(ccpClient, events))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ccpClient.updatedApps) != 3 || ccpClient.updatedApps[0] != "api" || ccpClient.updatedApps[2] != "worker" {
+		t.Fatalf("expected apps to be redeployed in order, got %v", ccpClient.updatedApps)
+	}
+
+	for appID, status := range events.statusByAppID() {
+		if status != string(types.PorterAppEventStatus_Success) {
+			t.Errorf("expected app %d to succeed, got %s", appID, status)
+		}
+	}
+
+	for _, event := range events.events {
+		if event.Type != string(types.PorterAppEventType_EnvGroupRollout) || event.Metadata["env_group_name"] != "shared" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	}
+}
+
+func TestRolloutEnvGroupStopsOnFailure(t *testing.T) {
+	ccpClient := &rolloutCCPClient{
+		revisionApp: make(map[string]string),
+		failingApps: map[string]bool{"web": true},
+	}
+	events := &rolloutPorterAppEventRepository{events: make(map[uuid.UUID]*models.PorterAppEvent)}
+
+	err := porter_app.RolloutEnvGroup(context.Background(), rolloutInput(ccpClient, events))
+	if err == nil {
+		t.Fatalf("expected the rollout to fail")
+	}
+
+	if len(ccpClient.updatedApps) != 2 {
+		t.Fatalf("expected the rollout to stop after the failed app, got %v", ccpClient.updatedApps)
+	}
+
+	expected := map[uint]string{
+		1: string(types.PorterAppEventStatus_Success),
+		2: string(types.PorterAppEventStatus_Failed),
+		3: string(types.PorterAppEventStatus_Canceled),
+	}
+
+	statuses := events.statusByAppID()
+	for appID, status := range expected {
+		if statuses[appID] != status {
+			t.Errorf("expected app %d to be %s, got %s", appID, status, statuses[appID])
+		}
+	}
+}
+
+const rolloutNoLatestTagPolicy = `package project.no_latest_tag
+
+import future.keywords.if
+
+POLICY_ID := "no_latest_tag"
+
+POLICY_TITLE := "Images should not use the latest tag"
+
+default allow := false
+
+allow if {
+	input.app.image.tag != "latest"
+}
+`
+
+func TestRolloutEnvGroupStopsOnPolicyDenial(t *testing.T) {
+	ccpClient := &rolloutCCPClient{
+		revisionApp: make(map[string]string),
+		appImages:   map[string]*porterv1.AppImage{"web": {Repository: "nginx", Tag: "latest"}},
+	}
+	events := &rolloutPorterAppEventRepository{events: make(map[uuid.UUID]*models.PorterAppEvent)}
+
+	policies, err := json.Marshal([]types.OPAPolicy{{Name: "no_latest_tag.rego", Module: rolloutNoLatestTagPolicy}})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	err = porter_app.RolloutEnvGroup(context.Background(), rolloutInput(ccpClient, events, &models.OPAPolicyBundle{
+		ProjectID:   1,
+		Name:        "images",
+		Kind:        "porter_app",
+		Enforcement: string(types.OPAPolicyEnforcement_Deny),
+		Policies:    policies,
+	}))
+	if err == nil {
+		t.Fatalf("expected the rollout to fail")
+	}
+
+	if len(ccpClient.updatedApps) != 1 || ccpClient.updatedApps[0] != "api" {
+		t.Fatalf("expected only the app before the denied app to be redeployed, got %v", ccpClient.updatedApps)
+	}
+
+	expected := map[uint]string{
+		1: string(types.PorterAppEventStatus_Success),
+		2: string(types.PorterAppEventStatus_Failed),
+		3: string(types.PorterAppEventStatus_Canceled),
+	}
+
+	statuses := events.statusByAppID()
+	for appID, status := range expected {
+		if statuses[appID] != status {
+			t.Errorf("expected app %d to be %s, got %s", appID, status, statuses[appID])
+		}
+	}
+}
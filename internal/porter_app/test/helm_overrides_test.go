@@ -0,0 +1,179 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/matryer/is"
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/internal/porter_app"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
+)
+
+func decodeHelmOverrides(t *testing.T, helmOverrides *porterv1.HelmOverrides) map[string]any {
+	t.Helper()
+
+	values := make(map[string]any)
+	if helmOverrides == nil || helmOverrides.B64Values == "" {
+		return values
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(helmOverrides.B64Values)
+	if err != nil {
+		t.Fatalf("error decoding helm overrides: %v", err)
+	}
+	if err := json.Unmarshal(decoded, &values); err != nil {
+		t.Fatalf("error unmarshaling helm overrides: %v", err)
+	}
+
+	return values
+}
+
+func encodeHelmOverrides(t *testing.T, values map[string]any) *porterv1.HelmOverrides {
+	t.Helper()
+
+	by, err := json.Marshal(values)
+	if err != nil {
+		t.Fatalf("error marshaling helm overrides: %v", err)
+	}
+
+	return &porterv1.HelmOverrides{B64Values: base64.StdEncoding.EncodeToString(by)}
+}
+
+// valueAt returns the value at a path of map keys and slice indexes in decoded json
+func valueAt(t *testing.T, values any, path ...any) any {
+	t.Helper()
+
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			m, ok := values.(map[string]any)
+			if !ok {
+				t.Fatalf("expected object at %v, got %v", p, values)
+			}
+			values = m[p]
+		case int:
+			s, ok := values.([]any)
+			if !ok || len(s) <= p {
+				t.Fatalf("expected list with index %d, got %v", p, values)
+			}
+			values = s[p]
+		}
+	}
+
+	return values
+}
+
+func TestHelmOverridesFromSidecars(t *testing.T) {
+	is := is.New(t)
+
+	file, err := os.ReadFile("../testdata/v2_input_sidecars.yaml")
+	is.NoErr(err)
+
+	app, err := porter_app.ParseYAML(context.Background(), file, "test-app")
+	is.NoErr(err)
+
+	values := decodeHelmOverrides(t, app.AppProto.HelmOverrides)
+
+	is.Equal(valueAt(t, values, "example-web-web", "sidecars", 0, "name"), "log-shipper")
+	is.Equal(valueAt(t, values, "example-web-web", "sidecars", 0, "env", 0, "value"), "info")
+	is.Equal(valueAt(t, values, "example-web-web", "sidecars", 0, "resources", "limits", "memory"), "128Mi")
+	is.Equal(valueAt(t, values, "example-web-web", "sidecars", 0, "volumeMounts", 0, "readOnly"), true)
+	is.Equal(valueAt(t, values, "example-web-web", "initContainers", 0, "image"), "migrate/migrate:v4.17.0")
+	is.Equal(valueAt(t, values, "example-web-web", "extraVolumes", 0, "name"), "logs")
+	is.True(valueAt(t, values, "example-web-web", "extraVolumes", 0, "emptyDir") != nil) // shared volumes are backed by an emptyDir
+	is.Equal(valueAt(t, values, "example-web-web", "extraVolumeMounts", 0, "mountPath"), "/var/log/app")
+	is.Equal(valueAt(t, values, "example-wkr-wkr", "sidecars", 0, "name"), "cloud-sql-proxy")
+	is.Equal(values["persistentVolumeClaims"], nil)
+}
+
+func TestHelmOverridesFromVolumes(t *testing.T) {
+	is := is.New(t)
+
+	file, err := os.ReadFile("../testdata/v2_input_volumes.yaml")
+	is.NoErr(err)
+
+	app, err := porter_app.ParseYAML(context.Background(), file, "test-app")
+	is.NoErr(err)
+
+	values := decodeHelmOverrides(t, app.AppProto.HelmOverrides)
+
+	is.Equal(valueAt(t, values, "persistentVolumeClaims", 0, "metadata", "name"), "model-cache")
+	is.Equal(valueAt(t, values, "persistentVolumeClaims", 0, "spec", "resources", "requests", "storage"), "50Gi")
+	is.Equal(valueAt(t, values, "persistentVolumeClaims", 0, "spec", "storageClassName"), "gp3")
+	is.Equal(valueAt(t, values, "persistentVolumeClaims", 0, "spec", "accessModes", 0), "ReadWriteMany")
+	is.Equal(valueAt(t, values, "persistentVolumeClaims", 1, "spec", "accessModes", 0), "ReadWriteOnce")
+	is.Equal(valueAt(t, values, "example-web-web", "extraVolumes", 0, "persistentVolumeClaim", "claimName"), "model-cache")
+	is.Equal(valueAt(t, values, "example-web-web", "extraVolumeMounts", 0, "readOnly"), true)
+	is.Equal(valueAt(t, values, "example-wkr-wkr", "extraVolumes", 1, "persistentVolumeClaim", "claimName"), "queue-data")
+	is.Equal(valueAt(t, values, "example-wkr-wkr", "extraVolumeMounts", 1, "mountPath"), "/var/lib/queue")
+}
+
+func TestMergeHelmOverrides(t *testing.T) {
+	current := map[string]any{
+		"persistentVolumeClaims": []any{map[string]any{"metadata": map[string]any{"name": "queue-data"}}},
+		"example-web-web": map[string]any{
+			"sidecars":       []any{map[string]any{"name": "log-shipper"}},
+			"podAnnotations": map[string]any{"team": "web"},
+		},
+		"example-wkr-wkr": map[string]any{
+			"sidecars": []any{map[string]any{"name": "cloud-sql-proxy"}},
+		},
+	}
+
+	t.Run("removed sidecars and volumes are cleared while other overrides are kept", func(t *testing.T) {
+		is := is.New(t)
+
+		merged, err := v2.MergeHelmOverrides(encodeHelmOverrides(t, current), nil)
+		is.NoErr(err)
+
+		is.Equal(decodeHelmOverrides(t, merged), map[string]any{
+			"example-web-web": map[string]any{
+				"podAnnotations": map[string]any{"team": "web"},
+			},
+		})
+	})
+
+	t.Run("rendered values replace the values managed by porter.yaml", func(t *testing.T) {
+		is := is.New(t)
+
+		rendered := encodeHelmOverrides(t, map[string]any{
+			"example-web-web": map[string]any{
+				"initContainers": []any{map[string]any{"name": "migrate"}},
+			},
+		})
+
+		merged, err := v2.MergeHelmOverrides(encodeHelmOverrides(t, current), rendered)
+		is.NoErr(err)
+
+		is.Equal(decodeHelmOverrides(t, merged), map[string]any{
+			"example-web-web": map[string]any{
+				"initContainers": []any{map[string]any{"name": "migrate"}},
+				"podAnnotations": map[string]any{"team": "web"},
+			},
+		})
+	})
+
+	t.Run("overrides are explicitly cleared when the last value is removed", func(t *testing.T) {
+		is := is.New(t)
+
+		merged, err := v2.MergeHelmOverrides(encodeHelmOverrides(t, map[string]any{
+			"example-wkr-wkr": current["example-wkr-wkr"],
+		}), nil)
+		is.NoErr(err)
+
+		is.True(merged != nil) // overrides are only removed when explicitly empty
+		is.Equal(merged.B64Values, "")
+	})
+
+	t.Run("no overrides are sent for apps without overrides", func(t *testing.T) {
+		is := is.New(t)
+
+		merged, err := v2.MergeHelmOverrides(nil, nil)
+		is.NoErr(err)
+		is.True(merged == nil)
+	})
+}
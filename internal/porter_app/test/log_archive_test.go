@@ -0,0 +1,253 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+type memoryLogArchiveStore struct {
+	objects map[string][]byte
+}
+
+func (s *memoryLogArchiveStore) PutObject(ctx context.Context, key string, body []byte) error {
+	s.objects[key] = body
+	return nil
+}
+
+// pagedLogs returns the logs in the requested range which match the labels, pageSize at a time, like the porter agent
+func pagedLogs(logs []types.LogLine, pageSize int) func(ctx context.Context, req *types.LogRequest) (*types.GetLogResponse, error) {
+	return func(ctx context.Context, req *types.LogRequest) (*types.GetLogResponse, error) {
+		resp := &types.GetLogResponse{}
+
+	logs:
+		for _, log := range logs {
+			if log.Timestamp.Before(*req.StartRange) || log.Timestamp.After(*req.EndRange) {
+				continue
+			}
+			for label, value := range req.MatchLabels {
+				if log.Metadata.RawLabels[label] != value {
+					continue logs
+				}
+			}
+			if len(resp.Logs) == pageSize {
+				continueTime := *log.Timestamp
+				resp.ForwardContinueTime = &continueTime
+				break
+			}
+
+			resp.Logs = append(resp.Logs, log)
+		}
+
+		return resp, nil
+	}
+}
+
+func logLine(line string, deploymentTargetID uuid.UUID, serviceName string, timestamp time.Time) types.LogLine {
+	return types.LogLine{
+		Line:      line,
+		Timestamp: &timestamp,
+		Metadata: types.LogMetadata{
+			RawLabels: map[string]string{
+				"porter_run_app_name":             "web",
+				"porter_run_deployment_target_id": deploymentTargetID.String(),
+				"porter_run_service_name":         serviceName,
+			},
+		},
+	}
+}
+
+func decodeLogArchive(t *testing.T, body []byte) []porter_app.StructuredLog {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("error reading gzip: %v", err)
+	}
+
+	var logs []porter_app.StructuredLog
+
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var log porter_app.StructuredLog
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			t.Fatalf("error decoding line %q: %v", scanner.Text(), err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs
+}
+
+func TestLogArchiveObjectKey(t *testing.T) {
+	windowStart := time.Date(2023, 1, 2, 15, 0, 0, 0, time.UTC)
+	deploymentTargetID := uuid.MustParse("8a3b8a3c-5c3e-4d0a-9a1e-0d4a1b2c3d4e")
+
+	key := porter_app.LogArchiveObjectKey("logs", 1, 2, deploymentTargetID, "web", "api", windowStart)
+	if key != "logs/project_id=1/cluster_id=2/deployment_target_id=8a3b8a3c-5c3e-4d0a-9a1e-0d4a1b2c3d4e/app=web/service=api/date=2023-01-02/hour=15.ndjson.gz" {
+		t.Errorf("unexpected key: %s", key)
+	}
+
+	key = porter_app.LogArchiveObjectKey("", 1, 2, deploymentTargetID, "web", "", windowStart)
+	if key != "project_id=1/cluster_id=2/deployment_target_id=8a3b8a3c-5c3e-4d0a-9a1e-0d4a1b2c3d4e/app=web/service=_unknown/date=2023-01-02/hour=15.ndjson.gz" {
+		t.Errorf("unexpected key: %s", key)
+	}
+}
+
+func TestArchiveAppLogs(t *testing.T) {
+	windowStart := time.Date(2023, 1, 2, 15, 0, 0, 0, time.UTC)
+	deploymentTargetID := uuid.New()
+	previewTargetID := uuid.New()
+
+	logs := []types.LogLine{
+		logLine("before", deploymentTargetID, "api", windowStart.Add(-time.Second)),
+		logLine("api 1", deploymentTargetID, "api", windowStart),
+		logLine("worker 1", deploymentTargetID, "worker", windowStart.Add(time.Minute)),
+		logLine("preview api 1", previewTargetID, "api", windowStart.Add(time.Minute)),
+		logLine("api 2", deploymentTargetID, "api", windowStart.Add(2*time.Minute)),
+		logLine("api 3", deploymentTargetID, "api", windowStart.Add(3*time.Minute)),
+		logLine("next window", deploymentTargetID, "api", windowStart.Add(porter_app.LogArchiveWindow)),
+	}
+
+	store := &memoryLogArchiveStore{objects: make(map[string][]byte)}
+	repo := test.NewLogArchiveRepository(true)
+
+	inp := porter_app.ArchiveAppLogsInput{
+		ProjectID:            1,
+		ClusterID:            2,
+		AppName:              "web",
+		DeploymentTargetID:   deploymentTargetID,
+		Setting:              &models.LogArchiveSetting{ProjectID: 1, Bucket: "archive", Prefix: "logs"},
+		WindowStart:          windowStart,
+		Logs:                 pagedLogs(logs, 2),
+		PageSize:             2,
+		Store:                store,
+		LogArchiveRepository: repo,
+	}
+
+	archives, err := porter_app.ArchiveAppLogs(context.Background(), inp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(archives) != 2 || archives[0].ServiceName != "api" || archives[1].ServiceName != "worker" {
+		t.Fatalf("expected an archive per service, got %+v", archives)
+	}
+
+	apiKey := porter_app.LogArchiveObjectKey("logs", 1, 2, deploymentTargetID, "web", "api", windowStart)
+
+	apiLogs := decodeLogArchive(t, store.objects[apiKey])
+	if len(apiLogs) != 3 || apiLogs[0].Line != "api 1" || apiLogs[2].Line != "api 3" {
+		t.Fatalf("unexpected archived logs: %+v", apiLogs)
+	}
+	if archives[0].LineCount != 3 || archives[0].ObjectKey != apiKey || archives[0].Bucket != "archive" || archives[0].DeploymentTargetID != deploymentTargetID {
+		t.Errorf("unexpected archive: %+v", archives[0])
+	}
+	if !archives[0].StartTime.Equal(windowStart) || !archives[0].EndTime.Equal(windowStart.Add(porter_app.LogArchiveWindow)) {
+		t.Errorf("unexpected archive range: %s - %s", archives[0].StartTime, archives[0].EndTime)
+	}
+
+	// archiving a window again overwrites its objects
+	_, err = porter_app.ArchiveAppLogs(context.Background(), inp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err := repo.ListLogArchives(context.Background(), 1, repository.LogArchiveFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 2 || len(store.objects) != 2 {
+		t.Errorf("expected 2 archives, got %d archives and %d objects", len(stored), len(store.objects))
+	}
+
+	// the same app in another deployment target is archived separately
+	inp.DeploymentTargetID = previewTargetID
+
+	archives, err = porter_app.ArchiveAppLogs(context.Background(), inp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	previewKey := porter_app.LogArchiveObjectKey("logs", 1, 2, previewTargetID, "web", "api", windowStart)
+	if len(archives) != 1 || archives[0].ObjectKey != previewKey || archives[0].LineCount != 1 {
+		t.Fatalf("expected an archive for the preview deployment target, got %+v", archives)
+	}
+
+	previewLogs := decodeLogArchive(t, store.objects[previewKey])
+	if len(previewLogs) != 1 || previewLogs[0].Line != "preview api 1" {
+		t.Errorf("unexpected archived logs: %+v", previewLogs)
+	}
+
+	stored, err = repo.ListLogArchives(context.Background(), 1, repository.LogArchiveFilter{DeploymentTargetID: previewTargetID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("expected 1 archive in the preview deployment target, got %d", len(stored))
+	}
+}
+
+func TestLogArchiveRanges(t *testing.T) {
+	start := time.Date(2023, 1, 2, 15, 0, 0, 0, time.UTC)
+	window := func(i int) (time.Time, time.Time) {
+		return start.Add(time.Duration(i) * time.Hour), start.Add(time.Duration(i+1) * time.Hour)
+	}
+
+	deploymentTargetID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	previewTargetID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	var archives []*models.LogArchive
+	for _, a := range []struct {
+		deploymentTargetID uuid.UUID
+		service            string
+		window             int
+	}{
+		{deploymentTargetID, "api", 0}, {deploymentTargetID, "worker", 0}, {previewTargetID, "api", 1},
+		{deploymentTargetID, "api", 1}, {deploymentTargetID, "api", 3},
+	} {
+		startTime, endTime := window(a.window)
+		archives = append(archives, &models.LogArchive{
+			ClusterID:          1,
+			DeploymentTargetID: a.deploymentTargetID,
+			AppName:            "web",
+			ServiceName:        a.service,
+			StartTime:          startTime,
+			EndTime:            endTime,
+			LineCount:          10,
+		})
+	}
+
+	ranges := porter_app.LogArchiveRanges(archives)
+
+	if len(ranges) != 4 {
+		t.Fatalf("expected 4 ranges, got %+v", ranges)
+	}
+
+	if ranges[0].ServiceName != "api" || !ranges[0].StartTime.Equal(start) || !ranges[0].EndTime.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("expected contiguous archives to be merged, got %+v", ranges[0])
+	}
+	if ranges[0].LineCount != 20 || len(ranges[0].Archives) != 2 {
+		t.Errorf("unexpected merged range: %+v", ranges[0])
+	}
+	if ranges[1].ServiceName != "api" || !ranges[1].StartTime.Equal(start.Add(3*time.Hour)) {
+		t.Errorf("expected a separate range after a gap, got %+v", ranges[1])
+	}
+	if ranges[2].ServiceName != "worker" {
+		t.Errorf("expected a range for the worker service, got %+v", ranges[2])
+	}
+	if ranges[3].DeploymentTargetID != previewTargetID.String() || ranges[3].LineCount != 10 {
+		t.Errorf("expected a separate range for the preview deployment target, got %+v", ranges[3])
+	}
+}
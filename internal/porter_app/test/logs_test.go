@@ -0,0 +1,72 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/porter_app"
+)
+
+func TestAgentLogToStructuredLog(t *testing.T) {
+	logs := porter_app.AgentLogToStructuredLog([]types.LogLine{
+		{
+			Line: "hello",
+			Metadata: types.LogMetadata{
+				PodName: "web-7d9c8-abcde",
+				RawLabels: map[string]string{
+					"porter_run_app_name":        "my-app",
+					"porter_run_service_name":    "web",
+					"porter_run_app_revision_id": "revision-1",
+				},
+			},
+		},
+	})
+
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log, got %d", len(logs))
+	}
+	if logs[0].PodName != "web-7d9c8-abcde" || logs[0].AppName != "my-app" || logs[0].ServiceName != "web" || logs[0].AppRevisionID != "revision-1" {
+		t.Errorf("unexpected structured log: %+v", logs[0])
+	}
+}
+
+func TestFilterStructuredLogs(t *testing.T) {
+	logs := []porter_app.StructuredLog{
+		{Line: "1", AppName: "my-app", DeploymentTargetID: "target-1", ServiceName: "web", AppRevisionID: "revision-1", PodName: "web-a"},
+		{Line: "2", AppName: "my-app", DeploymentTargetID: "target-1", ServiceName: "web", AppRevisionID: "revision-2", PodName: "web-b"},
+		{Line: "3", AppName: "my-app", DeploymentTargetID: "target-2", ServiceName: "worker", AppRevisionID: "revision-2", PodName: "worker-a"},
+		{Line: "4", AppName: "other-app", DeploymentTargetID: "target-1", ServiceName: "web", PodName: "web-c"},
+		{Line: "5", PodName: "unlabeled"},
+	}
+
+	tests := []struct {
+		name     string
+		filter   porter_app.StructuredLogFilter
+		expected []string
+	}{
+		{"empty filter", porter_app.StructuredLogFilter{}, []string{"1", "2", "3", "4", "5"}},
+		{"app", porter_app.StructuredLogFilter{AppName: "my-app"}, []string{"1", "2", "3"}},
+		{"app and deployment target", porter_app.StructuredLogFilter{AppName: "my-app", DeploymentTargetID: "target-1"}, []string{"1", "2"}},
+		{"pod of another app", porter_app.StructuredLogFilter{AppName: "my-app", PodName: "web-c"}, nil},
+		{"unlabeled pod", porter_app.StructuredLogFilter{AppName: "my-app", PodName: "unlabeled"}, nil},
+		{"service", porter_app.StructuredLogFilter{ServiceName: "web"}, []string{"1", "2", "4"}},
+		{"revision", porter_app.StructuredLogFilter{AppRevisionID: "revision-2"}, []string{"2", "3"}},
+		{"pod", porter_app.StructuredLogFilter{PodName: "web-b"}, []string{"2"}},
+		{"service and revision", porter_app.StructuredLogFilter{ServiceName: "worker", AppRevisionID: "revision-1"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := porter_app.FilterStructuredLogs(logs, tt.filter)
+
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("expected %d logs, got %d", len(tt.expected), len(filtered))
+			}
+			for i, log := range filtered {
+				if log.Line != tt.expected[i] {
+					t.Errorf("expected log %s at index %d, got %s", tt.expected[i], i, log.Line)
+				}
+			}
+		})
+	}
+}
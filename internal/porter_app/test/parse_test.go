@@ -11,6 +11,7 @@ import (
 
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
 	"github.com/porter-dev/porter/internal/porter_app"
+	v2 "github.com/porter-dev/porter/internal/porter_app/v2"
 	"github.com/sergi/go-diff/diffmatchpatch"
 
 	"github.com/matryer/is"
@@ -45,6 +46,139 @@ func TestParseYAML(t *testing.T) {
 	}
 }
 
+func TestParseYAMLInvalidVolumes(t *testing.T) {
+	tests := []struct {
+		name       string
+		porterYaml string
+	}{
+		{
+			name: "undeclared volume",
+			porterYaml: `version: v2
+services:
+  - name: example-wkr
+    type: worker
+    volumes:
+      - name: data
+        mountPath: /data
+`,
+		},
+		{
+			name: "read-write-once volume shared between services",
+			porterYaml: `version: v2
+volumes:
+  - name: data
+    sizeGigabytes: 10
+services:
+  - name: example-wkr
+    type: worker
+    volumes:
+      - name: data
+        mountPath: /data
+  - name: example-job
+    type: job
+    volumes:
+      - name: data
+        mountPath: /data
+`,
+		},
+		{
+			name: "volume with no size",
+			porterYaml: `version: v2
+volumes:
+  - name: data
+services:
+  - name: example-wkr
+    type: worker
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := porter_app.ParseYAML(context.Background(), []byte(tt.porterYaml), "test-app")
+			is.True(err != nil) // invalid volumes should fail to parse
+		})
+	}
+}
+
+func TestParseYAMLAddons(t *testing.T) {
+	is := is.New(t)
+
+	porterYaml := `version: v2
+services:
+  - name: example-wkr
+    type: worker
+addons:
+  - name: db
+    type: postgres
+    storageGigabytes: 10
+  - name: orders-db
+    type: mysql
+    ramMegabytes: 512
+    storageGigabytes: 5
+  - name: queue
+    type: rabbitmq
+    storageGigabytes: 0.5
+  - name: cache
+    type: memcached
+`
+
+	got, err := porter_app.ParseYAML(context.Background(), []byte(porterYaml), "test-app")
+	is.NoErr(err) // addons of registered types should parse without issues
+
+	is.Equal(len(got.Addons), 1) // only postgres is deployed by the cluster control plane
+	is.Equal(got.Addons[0].Type, porterv1.AddonType_ADDON_TYPE_POSTGRES)
+
+	var chartAddonNames []string
+	for _, addon := range got.ChartAddons {
+		chartAddonNames = append(chartAddonNames, addon.Name)
+	}
+	is.Equal(chartAddonNames, []string{"orders-db", "queue", "cache"})
+
+	values := func(addon v2.Addon) map[string]any {
+		definition, ok := v2.AddonDefinitionForType(addon.Type)
+		is.True(ok)
+		chartDefinition, ok := definition.(v2.ChartAddonDefinition)
+		is.True(ok)
+		return chartDefinition.Values(addon, addon.Name+"-credentials")
+	}
+	is.Equal(values(got.ChartAddons[0])["primary"].(map[string]any)["persistence"], map[string]any{"enabled": true, "size": "5Gi"})
+	is.Equal(values(got.ChartAddons[1])["persistence"], map[string]any{"enabled": true, "size": "512Mi"}) // fractional sizes are not truncated
+
+	_, err = porter_app.ParseYAML(context.Background(), []byte(`version: v2
+services:
+  - name: example-wkr
+    type: worker
+addons:
+  - name: orders-db
+    type: mysql
+`), "test-app")
+	is.True(err != nil) // mysql requires storage
+
+	_, err = porter_app.ParseYAML(context.Background(), []byte(`version: v2
+services:
+  - name: example-wkr
+    type: worker
+addons:
+  - name: cache
+    type: memcached
+    storageGigabytes: 1
+`), "test-app")
+	is.True(err != nil) // memcached does not support storage
+
+	_, err = porter_app.ParseYAML(context.Background(), []byte(`version: v2
+services:
+  - name: example-wkr
+    type: worker
+addons:
+  - name: search
+    type: elasticsearch
+`), "test-app")
+	is.True(err != nil) // unregistered addon types are rejected
+}
+
 var result_nobuild = &porterv1.PorterApp{
 	Name: "test-app",
 	ServiceList: []*porterv1.Service{
@@ -20,6 +20,8 @@ func TestPorterAppToYAML(t *testing.T) {
 		want               *porterv1.PorterApp
 	}{
 		{"v2_input_no_build_no_env", result_nobuild},
+		{"v2_input_sidecars", nil},
+		{"v2_input_volumes", nil},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,247 @@
+package v2
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultChartAddonCpuCores     float32 = 0.5
+	defaultChartAddonRamMegabytes         = 512
+)
+
+// chartAddonResources returns the helm values for the container resources of a chart addon, falling back to defaults for unset values
+func chartAddonResources(addon Addon) map[string]any {
+	cpuCores := addon.CpuCores
+	if cpuCores == 0 {
+		cpuCores = defaultChartAddonCpuCores
+	}
+
+	ramMegabytes := addon.RamMegabytes
+	if ramMegabytes == 0 {
+		ramMegabytes = defaultChartAddonRamMegabytes
+	}
+
+	resources := map[string]any{
+		"cpu":    fmt.Sprintf("%dm", int(cpuCores*1000)),
+		"memory": fmt.Sprintf("%dMi", ramMegabytes),
+	}
+
+	return map[string]any{
+		"requests": resources,
+		"limits":   resources,
+	}
+}
+
+// chartAddonPersistence returns the helm values for the persistent volume of a chart addon. Storage is validated to be set for addons with persistence.
+func chartAddonPersistence(addon Addon) map[string]any {
+	return map[string]any{
+		"enabled": true,
+		"size":    storageQuantity(addon.StorageGigabytes),
+	}
+}
+
+// storageQuantity formats a storage size in gigabytes as a kubernetes quantity, using Mi for fractional sizes so they are not truncated
+func storageQuantity(storageGigabytes float32) string {
+	megabytes := int64(math.Round(float64(storageGigabytes) * 1024))
+	if megabytes%1024 == 0 {
+		return fmt.Sprintf("%dGi", megabytes/1024)
+	}
+
+	return fmt.Sprintf("%dMi", megabytes)
+}
+
+// chartAddonDatabaseName returns a database name derived from the addon name that is valid for sql and document databases
+func chartAddonDatabaseName(addon Addon) string {
+	return strings.ReplaceAll(addon.Name, "-", "_")
+}
+
+type mysqlAddon struct{}
+
+// Validate implements AddonDefinition
+func (mysqlAddon) Validate(addon Addon) error {
+	return addonResourceLimits{minRamMegabytes: 256, requiresStorage: true}.validate(addon)
+}
+
+// ChartName implements ChartAddonDefinition
+func (mysqlAddon) ChartName() string {
+	return "mysql"
+}
+
+// CredentialKeys implements ChartAddonDefinition
+func (mysqlAddon) CredentialKeys() []string {
+	return []string{"mysql-root-password", "mysql-password"}
+}
+
+// Values implements ChartAddonDefinition
+func (mysqlAddon) Values(addon Addon, credentialsSecretName string) map[string]any {
+	return map[string]any{
+		"fullnameOverride": addon.Name,
+		"auth": map[string]any{
+			"username":       "porter",
+			"database":       chartAddonDatabaseName(addon),
+			"existingSecret": credentialsSecretName,
+		},
+		"primary": map[string]any{
+			"resources":   chartAddonResources(addon),
+			"persistence": chartAddonPersistence(addon),
+		},
+	}
+}
+
+// ConnectionVariables implements ChartAddonDefinition
+func (mysqlAddon) ConnectionVariables(addon Addon, host string, credentials map[string]string) (map[string]string, map[string]string) {
+	database := chartAddonDatabaseName(addon)
+	password := credentials["mysql-password"]
+
+	variables := map[string]string{
+		"DB_HOST": host,
+		"DB_PORT": "3306",
+		"DB_USER": "porter",
+		"DB_NAME": database,
+	}
+	secretVariables := map[string]string{
+		"DB_PASS":      password,
+		"DATABASE_URL": fmt.Sprintf("mysql://porter:%s@%s:3306/%s", url.QueryEscape(password), host, database),
+	}
+
+	return variables, secretVariables
+}
+
+type mongodbAddon struct{}
+
+// Validate implements AddonDefinition
+func (mongodbAddon) Validate(addon Addon) error {
+	return addonResourceLimits{minRamMegabytes: 256, requiresStorage: true}.validate(addon)
+}
+
+// ChartName implements ChartAddonDefinition
+func (mongodbAddon) ChartName() string {
+	return "mongodb"
+}
+
+// CredentialKeys implements ChartAddonDefinition
+func (mongodbAddon) CredentialKeys() []string {
+	return []string{"mongodb-root-password", "mongodb-passwords"}
+}
+
+// Values implements ChartAddonDefinition
+func (mongodbAddon) Values(addon Addon, credentialsSecretName string) map[string]any {
+	return map[string]any{
+		"fullnameOverride": addon.Name,
+		"architecture":     "standalone",
+		"auth": map[string]any{
+			"usernames":      []string{"porter"},
+			"databases":      []string{chartAddonDatabaseName(addon)},
+			"existingSecret": credentialsSecretName,
+		},
+		"resources":   chartAddonResources(addon),
+		"persistence": chartAddonPersistence(addon),
+	}
+}
+
+// ConnectionVariables implements ChartAddonDefinition
+func (mongodbAddon) ConnectionVariables(addon Addon, host string, credentials map[string]string) (map[string]string, map[string]string) {
+	database := chartAddonDatabaseName(addon)
+	password := credentials["mongodb-passwords"]
+
+	variables := map[string]string{
+		"MONGO_HOST": host,
+		"MONGO_PORT": "27017",
+		"MONGO_USER": "porter",
+		"MONGO_DB":   database,
+	}
+	secretVariables := map[string]string{
+		"MONGO_PASS": password,
+		"MONGO_URL":  fmt.Sprintf("mongodb://porter:%s@%s:27017/%s", url.QueryEscape(password), host, database),
+	}
+
+	return variables, secretVariables
+}
+
+type rabbitmqAddon struct{}
+
+// Validate implements AddonDefinition
+func (rabbitmqAddon) Validate(addon Addon) error {
+	return addonResourceLimits{minRamMegabytes: 256, requiresStorage: true}.validate(addon)
+}
+
+// ChartName implements ChartAddonDefinition
+func (rabbitmqAddon) ChartName() string {
+	return "rabbitmq"
+}
+
+// CredentialKeys implements ChartAddonDefinition
+func (rabbitmqAddon) CredentialKeys() []string {
+	return []string{"rabbitmq-password", "rabbitmq-erlang-cookie"}
+}
+
+// Values implements ChartAddonDefinition
+func (rabbitmqAddon) Values(addon Addon, credentialsSecretName string) map[string]any {
+	return map[string]any{
+		"fullnameOverride": addon.Name,
+		"auth": map[string]any{
+			"username":               "porter",
+			"existingPasswordSecret": credentialsSecretName,
+			"existingErlangSecret":   credentialsSecretName,
+		},
+		"resources":   chartAddonResources(addon),
+		"persistence": chartAddonPersistence(addon),
+	}
+}
+
+// ConnectionVariables implements ChartAddonDefinition
+func (rabbitmqAddon) ConnectionVariables(addon Addon, host string, credentials map[string]string) (map[string]string, map[string]string) {
+	password := credentials["rabbitmq-password"]
+
+	variables := map[string]string{
+		"RABBITMQ_HOST": host,
+		"RABBITMQ_PORT": "5672",
+		"RABBITMQ_USER": "porter",
+	}
+	secretVariables := map[string]string{
+		"RABBITMQ_PASS": password,
+		"AMQP_URL":      fmt.Sprintf("amqp://porter:%s@%s:5672", url.QueryEscape(password), host),
+	}
+
+	return variables, secretVariables
+}
+
+type memcachedAddon struct{}
+
+// Validate implements AddonDefinition
+func (memcachedAddon) Validate(addon Addon) error {
+	return addonResourceLimits{minRamMegabytes: 64, disallowsStorage: true}.validate(addon)
+}
+
+// ChartName implements ChartAddonDefinition
+func (memcachedAddon) ChartName() string {
+	return "memcached"
+}
+
+// CredentialKeys implements ChartAddonDefinition
+func (memcachedAddon) CredentialKeys() []string {
+	return nil
+}
+
+// Values implements ChartAddonDefinition
+func (memcachedAddon) Values(addon Addon, credentialsSecretName string) map[string]any {
+	return map[string]any{
+		"fullnameOverride": addon.Name,
+		"architecture":     "standalone",
+		"resources":        chartAddonResources(addon),
+	}
+}
+
+// ConnectionVariables implements ChartAddonDefinition
+func (memcachedAddon) ConnectionVariables(addon Addon, host string, credentials map[string]string) (map[string]string, map[string]string) {
+	variables := map[string]string{
+		"MEMCACHED_HOST":    host,
+		"MEMCACHED_PORT":    "11211",
+		"MEMCACHED_SERVERS": fmt.Sprintf("%s:11211", host),
+	}
+
+	return variables, map[string]string{}
+}
@@ -2,11 +2,113 @@ package v2
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
 
 	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
 	"github.com/porter-dev/porter/internal/telemetry"
 )
 
+// AddonDefinition describes an addon type that can be declared in the addons block of a Porter YAML file
+type AddonDefinition interface {
+	// Validate checks that the resource configuration of the addon is valid for this addon type
+	Validate(addon Addon) error
+}
+
+// ProtoAddonDefinition is an addon type that is deployed by the cluster control plane, and can be converted to the Addon proto type
+type ProtoAddonDefinition interface {
+	AddonDefinition
+	// Proto converts the addon to the Addon proto type
+	Proto(addon Addon) *porterv1.Addon
+}
+
+// ChartAddonDefinition is an addon type that is installed as a helm chart from the default addon helm repository,
+// alongside an auto-generated env group containing its connection details
+type ChartAddonDefinition interface {
+	AddonDefinition
+	// ChartName is the name of the chart in the default addon helm repository
+	ChartName() string
+	// CredentialKeys are the keys of the generated credentials that the chart reads from its credentials secret
+	CredentialKeys() []string
+	// Values returns the helm values used to install the addon. The chart is expected to read its credentials from the given secret.
+	Values(addon Addon, credentialsSecretName string) map[string]any
+	// ConnectionVariables returns the normal and secret variables of the env group generated for the addon
+	ConnectionVariables(addon Addon, host string, credentials map[string]string) (map[string]string, map[string]string)
+}
+
+var (
+	addonRegistryMu sync.RWMutex
+	addonRegistry   = make(map[string]AddonDefinition)
+)
+
+// RegisterAddonType registers an addon definition for the given type, overwriting any existing definition
+func RegisterAddonType(addonType string, definition AddonDefinition) {
+	addonRegistryMu.Lock()
+	defer addonRegistryMu.Unlock()
+
+	addonRegistry[addonType] = definition
+}
+
+// AddonDefinitionForType returns the registered addon definition for the given type
+func AddonDefinitionForType(addonType string) (AddonDefinition, bool) {
+	addonRegistryMu.RLock()
+	defer addonRegistryMu.RUnlock()
+
+	definition, ok := addonRegistry[addonType]
+	return definition, ok
+}
+
+// RegisteredAddonTypes returns all registered addon types, sorted alphabetically
+func RegisteredAddonTypes() []string {
+	addonRegistryMu.RLock()
+	defer addonRegistryMu.RUnlock()
+
+	var types []string
+	for addonType := range addonRegistry {
+		types = append(types, addonType)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+func init() {
+	RegisterAddonType("postgres", postgresAddon{})
+	RegisterAddonType("redis", redisAddon{})
+	RegisterAddonType("mysql", mysqlAddon{})
+	RegisterAddonType("mongodb", mongodbAddon{})
+	RegisterAddonType("rabbitmq", rabbitmqAddon{})
+	RegisterAddonType("memcached", memcachedAddon{})
+}
+
+// ValidateAddon checks that the addon has a name and a registered type, and that its configuration is valid for that type
+func ValidateAddon(ctx context.Context, addon Addon) (AddonDefinition, error) {
+	ctx, span := telemetry.NewSpan(ctx, "validate-addon")
+	defer span.End()
+
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "addon-name", Value: addon.Name},
+		telemetry.AttributeKV{Key: "addon-type", Value: addon.Type},
+	)
+
+	if addon.Name == "" {
+		return nil, telemetry.Error(ctx, span, nil, "addon found with no name")
+	}
+
+	definition, ok := AddonDefinitionForType(addon.Type)
+	if !ok {
+		return nil, telemetry.Error(ctx, span, nil, "invalid addon type")
+	}
+
+	err := definition.Validate(addon)
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "invalid addon config")
+	}
+
+	return definition, nil
+}
+
 // ProtoFromAddon converts an Addon to the Addon proto type
 func ProtoFromAddon(ctx context.Context, addon Addon) (*porterv1.Addon, error) {
 	ctx, span := telemetry.NewSpan(ctx, "proto-from-addon")
@@ -16,30 +118,19 @@ func ProtoFromAddon(ctx context.Context, addon Addon) (*porterv1.Addon, error) {
 		Name: addon.Name,
 	}
 
-	addonType, err := addonEnumProtoFromType(ctx, addon.Type)
+	definition, err := ValidateAddon(ctx, addon)
 	if err != nil {
-		return addonProto, telemetry.Error(ctx, span, err, "error getting addon type")
+		return addonProto, telemetry.Error(ctx, span, err, "error validating addon")
 	}
 
-	switch addonType {
-	case porterv1.AddonType_ADDON_TYPE_POSTGRES:
-		addonProto.Type = addonType
-		postgres := postgresConfigProtoFromAddon(addon)
-
-		addonProto.Config = &porterv1.Addon_Postgres{
-			Postgres: postgres,
-		}
-	case porterv1.AddonType_ADDON_TYPE_REDIS:
-		addonProto.Type = addonType
-		redis := redisConfigProtoFromAddon(addon)
-
-		addonProto.Config = &porterv1.Addon_Redis{
-			Redis: redis,
-		}
-	default:
+	protoDefinition, ok := definition.(ProtoAddonDefinition)
+	if !ok {
 		return addonProto, telemetry.Error(ctx, span, nil, "specified addon type not supported")
 	}
 
+	addonProto = protoDefinition.Proto(addon)
+	addonProto.Name = addon.Name
+
 	var envGroups []*porterv1.EnvGroup
 
 	for _, envGroup := range addon.EnvGroups {
@@ -54,36 +145,87 @@ func ProtoFromAddon(ctx context.Context, addon Addon) (*porterv1.Addon, error) {
 	return addonProto, nil
 }
 
-func addonEnumProtoFromType(ctx context.Context, addonType string) (porterv1.AddonType, error) {
-	ctx, span := telemetry.NewSpan(ctx, "addon-enum-proto-from-type")
-	defer span.End()
+// isProtoAddon returns true if the addon type is deployed by the cluster control plane
+func isProtoAddon(addonType string) bool {
+	definition, ok := AddonDefinitionForType(addonType)
+	if !ok {
+		return false
+	}
 
-	var addonTypeEnum porterv1.AddonType
+	_, ok = definition.(ProtoAddonDefinition)
+	return ok
+}
 
-	switch addonType {
-	case "postgres":
-		addonTypeEnum = porterv1.AddonType_ADDON_TYPE_POSTGRES
-	case "redis":
-		addonTypeEnum = porterv1.AddonType_ADDON_TYPE_REDIS
-	default:
-		return addonTypeEnum, telemetry.Error(ctx, span, nil, "invalid addon type")
+// addonResourceLimits are the bounds used to validate the resource configuration of an addon
+type addonResourceLimits struct {
+	minRamMegabytes     int
+	maxCpuCores         float32
+	requiresStorage     bool
+	disallowsStorage    bool
+	maxStorageGigabytes float32
+}
+
+func (l addonResourceLimits) validate(addon Addon) error {
+	if addon.CpuCores < 0 || addon.RamMegabytes < 0 || addon.StorageGigabytes < 0 {
+		return fmt.Errorf("addon '%s' has negative resources", addon.Name)
+	}
+	if l.maxCpuCores > 0 && addon.CpuCores > l.maxCpuCores {
+		return fmt.Errorf("addon '%s' of type %s cannot have more than %v cpu cores", addon.Name, addon.Type, l.maxCpuCores)
+	}
+	if addon.RamMegabytes != 0 && addon.RamMegabytes < l.minRamMegabytes {
+		return fmt.Errorf("addon '%s' of type %s requires at least %d MB of ram", addon.Name, addon.Type, l.minRamMegabytes)
+	}
+	if l.disallowsStorage && addon.StorageGigabytes != 0 {
+		return fmt.Errorf("addon '%s' of type %s does not support storage", addon.Name, addon.Type)
+	}
+	if l.requiresStorage && addon.StorageGigabytes <= 0 {
+		return fmt.Errorf("addon '%s' of type %s requires storageGigabytes to be set", addon.Name, addon.Type)
+	}
+	if l.maxStorageGigabytes > 0 && addon.StorageGigabytes > l.maxStorageGigabytes {
+		return fmt.Errorf("addon '%s' of type %s cannot have more than %v GB of storage", addon.Name, addon.Type, l.maxStorageGigabytes)
 	}
 
-	return addonTypeEnum, nil
+	return nil
 }
 
-func postgresConfigProtoFromAddon(addon Addon) *porterv1.Postgres {
-	return &porterv1.Postgres{
-		RamMegabytes:     int32(addon.RamMegabytes),
-		CpuCores:         addon.CpuCores,
-		StorageGigabytes: int32(addon.StorageGigabytes),
+type postgresAddon struct{}
+
+// Validate implements AddonDefinition
+func (postgresAddon) Validate(addon Addon) error {
+	return addonResourceLimits{}.validate(addon)
+}
+
+// Proto implements ProtoAddonDefinition
+func (postgresAddon) Proto(addon Addon) *porterv1.Addon {
+	return &porterv1.Addon{
+		Type: porterv1.AddonType_ADDON_TYPE_POSTGRES,
+		Config: &porterv1.Addon_Postgres{
+			Postgres: &porterv1.Postgres{
+				RamMegabytes:     int32(addon.RamMegabytes),
+				CpuCores:         addon.CpuCores,
+				StorageGigabytes: int32(addon.StorageGigabytes),
+			},
+		},
 	}
 }
 
-func redisConfigProtoFromAddon(addon Addon) *porterv1.Redis {
-	return &porterv1.Redis{
-		RamMegabytes:     int32(addon.RamMegabytes),
-		CpuCores:         addon.CpuCores,
-		StorageGigabytes: int32(addon.StorageGigabytes),
+type redisAddon struct{}
+
+// Validate implements AddonDefinition
+func (redisAddon) Validate(addon Addon) error {
+	return addonResourceLimits{}.validate(addon)
+}
+
+// Proto implements ProtoAddonDefinition
+func (redisAddon) Proto(addon Addon) *porterv1.Addon {
+	return &porterv1.Addon{
+		Type: porterv1.AddonType_ADDON_TYPE_REDIS,
+		Config: &porterv1.Addon_Redis{
+			Redis: &porterv1.Redis{
+				RamMegabytes:     int32(addon.RamMegabytes),
+				CpuCores:         addon.CpuCores,
+				StorageGigabytes: int32(addon.StorageGigabytes),
+			},
+		},
 	}
 }
@@ -0,0 +1,245 @@
+package v2
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Container is an additional container that runs in the same pod as a service, either alongside it (sidecar) or before it starts (init container)
+type Container struct {
+	Name         string            `yaml:"name"`
+	Image        string            `yaml:"image"`
+	Command      []string          `yaml:"command,omitempty"`
+	Args         []string          `yaml:"args,omitempty"`
+	Env          map[string]string `yaml:"env,omitempty"`
+	CpuCores     float32           `yaml:"cpuCores,omitempty"`
+	RamMegabytes int               `yaml:"ramMegabytes,omitempty"`
+	VolumeMounts []VolumeMount     `yaml:"volumeMounts,omitempty"`
+}
+
+// VolumeMount mounts a named volume into a container
+type VolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+// validateServiceContainers checks that the sidecars, init containers and shared volume mounts of a service are well-formed
+func validateServiceContainers(service Service) error {
+	names := map[string]bool{
+		service.Name: true,
+	}
+
+	containers := append(append([]Container{}, service.Sidecars...), service.InitContainers...)
+	for _, container := range containers {
+		if container.Name == "" {
+			return fmt.Errorf("container in service '%s' has no name", service.Name)
+		}
+		if names[container.Name] {
+			return fmt.Errorf("duplicate container name '%s' in service '%s'", container.Name, service.Name)
+		}
+		names[container.Name] = true
+
+		if container.Image == "" {
+			return fmt.Errorf("container '%s' in service '%s' has no image", container.Name, service.Name)
+		}
+
+		for _, mount := range container.VolumeMounts {
+			if mount.Name == "" || mount.MountPath == "" {
+				return fmt.Errorf("volume mount in container '%s' must specify a name and a mount path", container.Name)
+			}
+		}
+	}
+
+	for _, mount := range service.SharedVolumes {
+		if mount.Name == "" || mount.MountPath == "" {
+			return fmt.Errorf("shared volume in service '%s' must specify a name and a mount path", service.Name)
+		}
+	}
+
+	return nil
+}
+
+func k8sContainerFromContainer(container Container) (corev1.Container, error) {
+	k8sContainer := corev1.Container{
+		Name:    container.Name,
+		Image:   container.Image,
+		Command: container.Command,
+		Args:    container.Args,
+	}
+
+	var envKeys []string
+	for key := range container.Env {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+
+	for _, key := range envKeys {
+		k8sContainer.Env = append(k8sContainer.Env, corev1.EnvVar{
+			Name:  key,
+			Value: container.Env[key],
+		})
+	}
+
+	if container.CpuCores < 0 || container.RamMegabytes < 0 {
+		return k8sContainer, fmt.Errorf("container '%s' has negative resources", container.Name)
+	}
+
+	resources := corev1.ResourceList{}
+	if container.CpuCores > 0 {
+		resources[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(container.CpuCores*1000), resource.DecimalSI)
+	}
+	if container.RamMegabytes > 0 {
+		resources[corev1.ResourceMemory] = *resource.NewQuantity(int64(container.RamMegabytes)*1024*1024, resource.BinarySI)
+	}
+	if len(resources) > 0 {
+		k8sContainer.Resources = corev1.ResourceRequirements{
+			Requests: resources,
+			Limits:   resources,
+		}
+	}
+
+	for _, mount := range container.VolumeMounts {
+		k8sContainer.VolumeMounts = append(k8sContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      mount.Name,
+			MountPath: mount.MountPath,
+			ReadOnly:  mount.ReadOnly,
+		})
+	}
+
+	return k8sContainer, nil
+}
+
+func containerFromK8sContainer(k8sContainer corev1.Container) Container {
+	container := Container{
+		Name:    k8sContainer.Name,
+		Image:   k8sContainer.Image,
+		Command: k8sContainer.Command,
+		Args:    k8sContainer.Args,
+	}
+
+	if len(k8sContainer.Env) > 0 {
+		container.Env = make(map[string]string)
+		for _, envVar := range k8sContainer.Env {
+			container.Env[envVar.Name] = envVar.Value
+		}
+	}
+
+	if cpu, ok := k8sContainer.Resources.Requests[corev1.ResourceCPU]; ok {
+		container.CpuCores = float32(cpu.MilliValue()) / 1000
+	}
+	if memory, ok := k8sContainer.Resources.Requests[corev1.ResourceMemory]; ok {
+		container.RamMegabytes = int(memory.Value() / (1024 * 1024))
+	}
+
+	for _, mount := range k8sContainer.VolumeMounts {
+		container.VolumeMounts = append(container.VolumeMounts, VolumeMount{
+			Name:      mount.Name,
+			MountPath: mount.MountPath,
+			ReadOnly:  mount.ReadOnly,
+		})
+	}
+
+	return container
+}
+
+// renderContainerHelmValues renders the sidecars, init containers and shared volumes of each service. Volume names that match a persistent
+// volume of the app are backed by its claim, and all other volume names are backed by an emptyDir shared across the pod.
+func renderContainerHelmValues(porterApp PorterApp, values *helmOverrideValues) error {
+	for _, service := range porterApp.Services {
+		if len(service.Sidecars) == 0 && len(service.InitContainers) == 0 && len(service.SharedVolumes) == 0 {
+			continue
+		}
+
+		if err := validateServiceContainers(service); err != nil {
+			return err
+		}
+
+		serviceValues := values.service(service.Name)
+		var volumeNames []string
+
+		for _, sidecar := range service.Sidecars {
+			container, err := k8sContainerFromContainer(sidecar)
+			if err != nil {
+				return err
+			}
+			serviceValues.Sidecars = append(serviceValues.Sidecars, container)
+
+			for _, mount := range sidecar.VolumeMounts {
+				volumeNames = append(volumeNames, mount.Name)
+			}
+		}
+
+		for _, initContainer := range service.InitContainers {
+			container, err := k8sContainerFromContainer(initContainer)
+			if err != nil {
+				return err
+			}
+			serviceValues.InitContainers = append(serviceValues.InitContainers, container)
+
+			for _, mount := range initContainer.VolumeMounts {
+				volumeNames = append(volumeNames, mount.Name)
+			}
+		}
+
+		for _, mount := range service.SharedVolumes {
+			volumeNames = append(volumeNames, mount.Name)
+			serviceValues.ExtraVolumeMounts = append(serviceValues.ExtraVolumeMounts, corev1.VolumeMount{
+				Name:      mount.Name,
+				MountPath: mount.MountPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+
+		for _, name := range volumeNames {
+			if values.isPersistentVolume(name) {
+				serviceValues.addVolume(pvcBackedVolume(name))
+				continue
+			}
+
+			serviceValues.addVolume(corev1.Volume{
+				Name: name,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
+		}
+	}
+
+	return nil
+}
+
+// attachContainerHelmValues reads the sidecars, init containers and shared volumes of each service back onto the app
+func attachContainerHelmValues(values *helmOverrideValues, porterApp *PorterApp) error {
+	for i := range porterApp.Services {
+		service := &porterApp.Services[i]
+
+		serviceValues, ok := values.Services[service.Name]
+		if !ok {
+			continue
+		}
+
+		for _, sidecar := range serviceValues.Sidecars {
+			service.Sidecars = append(service.Sidecars, containerFromK8sContainer(sidecar))
+		}
+		for _, initContainer := range serviceValues.InitContainers {
+			service.InitContainers = append(service.InitContainers, containerFromK8sContainer(initContainer))
+		}
+		for _, mount := range serviceValues.ExtraVolumeMounts {
+			if values.isPersistentVolume(mount.Name) {
+				continue
+			}
+
+			service.SharedVolumes = append(service.SharedVolumes, VolumeMount{
+				Name:      mount.Name,
+				MountPath: mount.MountPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+	}
+
+	return nil
+}
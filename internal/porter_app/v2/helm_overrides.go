@@ -0,0 +1,303 @@
+package v2
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	porterv1 "github.com/porter-dev/api-contracts/generated/go/porter/v1"
+	"github.com/porter-dev/porter/internal/telemetry"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// helmValuesKey_PersistentVolumeClaims is the top-level key of the app chart values under which persistent volume claims are rendered
+const helmValuesKey_PersistentVolumeClaims = "persistentVolumeClaims"
+
+// serviceHelmValues are the helm values used to render the extra containers and volumes of a single service
+type serviceHelmValues struct {
+	Sidecars          []corev1.Container   `json:"sidecars,omitempty"`
+	InitContainers    []corev1.Container   `json:"initContainers,omitempty"`
+	ExtraVolumes      []corev1.Volume      `json:"extraVolumes,omitempty"`
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+}
+
+// managedServiceHelmValuesKeys are the keys of the service subchart values which are managed by porter.yaml
+var managedServiceHelmValuesKeys = []string{"sidecars", "initContainers", "extraVolumes", "extraVolumeMounts"}
+
+// addVolume adds a volume to the service, unless a volume with the same name was already added
+func (v *serviceHelmValues) addVolume(volume corev1.Volume) {
+	for _, existing := range v.ExtraVolumes {
+		if existing.Name == volume.Name {
+			return
+		}
+	}
+
+	v.ExtraVolumes = append(v.ExtraVolumes, volume)
+}
+
+func (v *serviceHelmValues) isEmpty() bool {
+	return len(v.Sidecars) == 0 && len(v.InitContainers) == 0 && len(v.ExtraVolumes) == 0 && len(v.ExtraVolumeMounts) == 0
+}
+
+// helmOverrideValues are the helm values of the app chart which are managed by porter.yaml
+type helmOverrideValues struct {
+	PersistentVolumeClaims []corev1.PersistentVolumeClaim
+
+	// Services are the values of each service subchart, keyed by service name
+	Services map[string]*serviceHelmValues
+}
+
+// service returns the values of a service subchart, creating them if needed
+func (v *helmOverrideValues) service(name string) *serviceHelmValues {
+	if v.Services == nil {
+		v.Services = make(map[string]*serviceHelmValues)
+	}
+
+	if _, ok := v.Services[name]; !ok {
+		v.Services[name] = &serviceHelmValues{}
+	}
+
+	return v.Services[name]
+}
+
+// isPersistentVolume returns true if the name is the name of a persistent volume claim of the app
+func (v *helmOverrideValues) isPersistentVolume(name string) bool {
+	for _, pvc := range v.PersistentVolumeClaims {
+		if pvc.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// helmOverrideRenderer renders a part of a porter.yaml app into the helm values of the app chart, and reads it back
+type helmOverrideRenderer struct {
+	// render adds the values for the app to the override values
+	render func(porterApp PorterApp, values *helmOverrideValues) error
+	// attach reads the override values back onto the app
+	attach func(values *helmOverrideValues, porterApp *PorterApp) error
+}
+
+// helmOverrideRenderers are run in order, so volumes are rendered before the containers which mount them
+var helmOverrideRenderers = []helmOverrideRenderer{
+	{render: renderVolumeHelmValues, attach: attachVolumeHelmValues},
+	{render: renderContainerHelmValues, attach: attachContainerHelmValues},
+}
+
+// helmValuesKeyForService returns the key of the service's subchart in the app chart values
+func helmValuesKeyForService(name string, serviceType porterv1.ServiceType) (string, error) {
+	switch serviceType {
+	case porterv1.ServiceType_SERVICE_TYPE_WEB:
+		return fmt.Sprintf("%s-web", name), nil
+	case porterv1.ServiceType_SERVICE_TYPE_WORKER:
+		return fmt.Sprintf("%s-wkr", name), nil
+	case porterv1.ServiceType_SERVICE_TYPE_JOB:
+		return fmt.Sprintf("%s-job", name), nil
+	default:
+		return "", fmt.Errorf("invalid service type '%s'", serviceType)
+	}
+}
+
+// helmOverridesFromApp renders the sidecars, init containers and persistent volumes of an app into helm overrides for the app chart.
+// Nil is returned if the app declares none of them. Use MergeHelmOverrides to apply the overrides to an existing app.
+func helmOverridesFromApp(ctx context.Context, porterApp PorterApp, serviceProtos []*porterv1.Service) (*porterv1.HelmOverrides, error) {
+	ctx, span := telemetry.NewSpan(ctx, "helm-overrides-from-app")
+	defer span.End()
+
+	if len(porterApp.Services) != len(serviceProtos) {
+		return nil, telemetry.Error(ctx, span, nil, "mismatched service and service proto count")
+	}
+
+	overrideValues := &helmOverrideValues{}
+	for _, renderer := range helmOverrideRenderers {
+		if err := renderer.render(porterApp, overrideValues); err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error rendering helm overrides")
+		}
+	}
+
+	values := make(map[string]any)
+
+	if len(overrideValues.PersistentVolumeClaims) > 0 {
+		values[helmValuesKey_PersistentVolumeClaims] = overrideValues.PersistentVolumeClaims
+	}
+
+	for i, service := range porterApp.Services {
+		serviceValues, ok := overrideValues.Services[service.Name]
+		if !ok || serviceValues.isEmpty() {
+			continue
+		}
+
+		key, err := helmValuesKeyForService(service.Name, serviceProtos[i].Type)
+		if err != nil {
+			return nil, telemetry.Error(ctx, span, err, "error getting helm values key for service")
+		}
+
+		sort.Slice(serviceValues.ExtraVolumes, func(i, j int) bool {
+			return serviceValues.ExtraVolumes[i].Name < serviceValues.ExtraVolumes[j].Name
+		})
+
+		values[key] = serviceValues
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	return encodeHelmOverrides(values)
+}
+
+// attachHelmOverridesToApp reads the sidecars, init containers and persistent volumes stored in the app's helm overrides back onto the app
+func attachHelmOverridesToApp(helmOverrides *porterv1.HelmOverrides, porterApp *PorterApp) error {
+	values, err := decodeHelmOverrides(helmOverrides)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	overrideValues := &helmOverrideValues{}
+
+	if raw, ok := values[helmValuesKey_PersistentVolumeClaims]; ok {
+		if err := json.Unmarshal(raw, &overrideValues.PersistentVolumeClaims); err != nil {
+			return fmt.Errorf("error unmarshaling persistent volume claims: %w", err)
+		}
+	}
+
+	for _, service := range porterApp.Services {
+		var serviceType porterv1.ServiceType
+		switch service.Type {
+		case ServiceType_Web:
+			serviceType = porterv1.ServiceType_SERVICE_TYPE_WEB
+		case ServiceType_Worker:
+			serviceType = porterv1.ServiceType_SERVICE_TYPE_WORKER
+		case ServiceType_Job:
+			serviceType = porterv1.ServiceType_SERVICE_TYPE_JOB
+		default:
+			return errors.New("service type unspecified")
+		}
+
+		key, err := helmValuesKeyForService(service.Name, serviceType)
+		if err != nil {
+			return err
+		}
+
+		raw, ok := values[key]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(raw, overrideValues.service(service.Name)); err != nil {
+			return fmt.Errorf("error unmarshaling helm values for service '%s': %w", service.Name, err)
+		}
+	}
+
+	for _, renderer := range helmOverrideRenderers {
+		if err := renderer.attach(overrideValues, porterApp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MergeHelmOverrides merges the helm overrides rendered from porter.yaml into the current helm overrides of an app. The values managed
+// by porter.yaml are replaced, so that sidecars, init containers and volumes removed from porter.yaml are removed from the app, while any
+// other overrides are kept. Since overrides are only cleared when their values are explicitly empty, empty overrides are returned if the
+// merged overrides are empty but the current overrides are not, and nil is returned if both are empty.
+func MergeHelmOverrides(current, rendered *porterv1.HelmOverrides) (*porterv1.HelmOverrides, error) {
+	currentValues, err := decodeHelmOverrides(current)
+	if err != nil {
+		return nil, err
+	}
+
+	renderedValues, err := decodeHelmOverrides(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]any)
+
+	for key, raw := range currentValues {
+		if key == helmValuesKey_PersistentVolumeClaims {
+			continue
+		}
+
+		var subchartValues map[string]any
+		if err := json.Unmarshal(raw, &subchartValues); err != nil {
+			// not a subchart, so none of its values are managed by porter.yaml
+			merged[key] = raw
+			continue
+		}
+
+		for _, managedKey := range managedServiceHelmValuesKeys {
+			delete(subchartValues, managedKey)
+		}
+
+		if len(subchartValues) > 0 {
+			merged[key] = subchartValues
+		}
+	}
+
+	for key, raw := range renderedValues {
+		var renderedSubchartValues map[string]any
+		if err := json.Unmarshal(raw, &renderedSubchartValues); err != nil {
+			merged[key] = raw
+			continue
+		}
+
+		subchartValues, ok := merged[key].(map[string]any)
+		if !ok {
+			merged[key] = renderedSubchartValues
+			continue
+		}
+
+		for k, v := range renderedSubchartValues {
+			subchartValues[k] = v
+		}
+	}
+
+	if len(merged) == 0 {
+		if len(currentValues) == 0 {
+			return nil, nil
+		}
+
+		return &porterv1.HelmOverrides{B64Values: ""}, nil
+	}
+
+	return encodeHelmOverrides(merged)
+}
+
+func encodeHelmOverrides(values map[string]any) (*porterv1.HelmOverrides, error) {
+	by, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling helm overrides: %w", err)
+	}
+
+	return &porterv1.HelmOverrides{
+		B64Values: base64.StdEncoding.EncodeToString(by),
+	}, nil
+}
+
+func decodeHelmOverrides(helmOverrides *porterv1.HelmOverrides) (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage)
+
+	if helmOverrides == nil || helmOverrides.B64Values == "" {
+		return values, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(helmOverrides.B64Values)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding helm overrides: %w", err)
+	}
+
+	if err := json.Unmarshal(decoded, &values); err != nil {
+		return nil, fmt.Errorf("error unmarshaling helm overrides: %w", err)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,219 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// VolumeAccessMode is the access mode of a persistent volume
+type VolumeAccessMode string
+
+const (
+	// VolumeAccessMode_ReadWriteOnce allows the volume to be mounted read-write by a single node. This is the default access mode.
+	VolumeAccessMode_ReadWriteOnce VolumeAccessMode = "ReadWriteOnce"
+	// VolumeAccessMode_ReadWriteMany allows the volume to be mounted read-write by many nodes, and is required to share a volume between services
+	VolumeAccessMode_ReadWriteMany VolumeAccessMode = "ReadWriteMany"
+	// VolumeAccessMode_ReadOnlyMany allows the volume to be mounted read-only by many nodes
+	VolumeAccessMode_ReadOnlyMany VolumeAccessMode = "ReadOnlyMany"
+)
+
+// LabelKey_VolumeName is the label set on persistent volume claims created for a porter app volume
+const LabelKey_VolumeName = "porter.run/volume-name"
+
+// Volume is a named persistent volume that can be mounted by one or more services of an app
+type Volume struct {
+	Name          string           `yaml:"name"`
+	SizeGigabytes int              `yaml:"sizeGigabytes"`
+	StorageClass  string           `yaml:"storageClass,omitempty"`
+	AccessMode    VolumeAccessMode `yaml:"accessMode,omitempty"`
+}
+
+// validateVolumes checks that the persistent volumes of an app are well-formed, and that every service mount refers to a declared volume
+func validateVolumes(porterApp PorterApp) error {
+	volumesByName := make(map[string]Volume)
+
+	for _, volume := range porterApp.Volumes {
+		if volume.Name == "" {
+			return fmt.Errorf("volume found with no name")
+		}
+		if errs := validation.IsDNS1123Subdomain(volume.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid volume name '%s': %s", volume.Name, strings.Join(errs, ", "))
+		}
+		if _, ok := volumesByName[volume.Name]; ok {
+			return fmt.Errorf("duplicate volume name '%s'", volume.Name)
+		}
+		if volume.SizeGigabytes <= 0 {
+			return fmt.Errorf("volume '%s' must have a size greater than 0", volume.Name)
+		}
+
+		switch volume.AccessMode {
+		case "", VolumeAccessMode_ReadWriteOnce, VolumeAccessMode_ReadWriteMany, VolumeAccessMode_ReadOnlyMany:
+		default:
+			return fmt.Errorf("invalid access mode '%s' for volume '%s'", volume.AccessMode, volume.Name)
+		}
+
+		volumesByName[volume.Name] = volume
+	}
+
+	mountedBy := make(map[string][]string)
+
+	for _, service := range porterApp.Services {
+		for _, mount := range service.Volumes {
+			if mount.Name == "" || mount.MountPath == "" {
+				return fmt.Errorf("volume in service '%s' must specify a name and a mount path", service.Name)
+			}
+			if _, ok := volumesByName[mount.Name]; !ok {
+				return fmt.Errorf("service '%s' mounts undeclared volume '%s'", service.Name, mount.Name)
+			}
+
+			mountedBy[mount.Name] = append(mountedBy[mount.Name], service.Name)
+		}
+	}
+
+	for name, services := range mountedBy {
+		volume := volumesByName[name]
+		if len(services) > 1 && (volume.AccessMode == "" || volume.AccessMode == VolumeAccessMode_ReadWriteOnce) {
+			return fmt.Errorf("volume '%s' is shared by services %s and must use access mode %s", name, strings.Join(services, ", "), VolumeAccessMode_ReadWriteMany)
+		}
+	}
+
+	return nil
+}
+
+func pvcFromVolume(volume Volume) corev1.PersistentVolumeClaim {
+	accessMode := volume.AccessMode
+	if accessMode == "" {
+		accessMode = VolumeAccessMode_ReadWriteOnce
+	}
+
+	pvc := corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "PersistentVolumeClaim",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: volume.Name,
+			Labels: map[string]string{
+				LabelKey_VolumeName: volume.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.PersistentVolumeAccessMode(accessMode),
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: *resource.NewQuantity(int64(volume.SizeGigabytes)*1024*1024*1024, resource.BinarySI),
+				},
+			},
+		},
+	}
+
+	if volume.StorageClass != "" {
+		storageClass := volume.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	return pvc
+}
+
+func volumeFromPVC(pvc corev1.PersistentVolumeClaim) Volume {
+	volume := Volume{
+		Name: pvc.Name,
+	}
+
+	if name, ok := pvc.Labels[LabelKey_VolumeName]; ok {
+		volume.Name = name
+	}
+
+	if storage, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		volume.SizeGigabytes = int(storage.Value() / (1024 * 1024 * 1024))
+	}
+
+	if pvc.Spec.StorageClassName != nil {
+		volume.StorageClass = *pvc.Spec.StorageClassName
+	}
+
+	if len(pvc.Spec.AccessModes) > 0 && pvc.Spec.AccessModes[0] != corev1.ReadWriteOnce {
+		volume.AccessMode = VolumeAccessMode(pvc.Spec.AccessModes[0])
+	}
+
+	return volume
+}
+
+// renderVolumeHelmValues renders the persistent volumes of the app as claims, and mounts them into the services which declare them
+func renderVolumeHelmValues(porterApp PorterApp, values *helmOverrideValues) error {
+	if err := validateVolumes(porterApp); err != nil {
+		return err
+	}
+
+	for _, volume := range porterApp.Volumes {
+		values.PersistentVolumeClaims = append(values.PersistentVolumeClaims, pvcFromVolume(volume))
+	}
+
+	for _, service := range porterApp.Services {
+		if len(service.Volumes) == 0 {
+			continue
+		}
+
+		serviceValues := values.service(service.Name)
+
+		for _, mount := range service.Volumes {
+			serviceValues.addVolume(pvcBackedVolume(mount.Name))
+			serviceValues.ExtraVolumeMounts = append(serviceValues.ExtraVolumeMounts, corev1.VolumeMount{
+				Name:      mount.Name,
+				MountPath: mount.MountPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+	}
+
+	return nil
+}
+
+// attachVolumeHelmValues reads the persistent volumes of the app, and the services which mount them, back onto the app
+func attachVolumeHelmValues(values *helmOverrideValues, porterApp *PorterApp) error {
+	for _, pvc := range values.PersistentVolumeClaims {
+		porterApp.Volumes = append(porterApp.Volumes, volumeFromPVC(pvc))
+	}
+
+	for i := range porterApp.Services {
+		service := &porterApp.Services[i]
+
+		serviceValues, ok := values.Services[service.Name]
+		if !ok {
+			continue
+		}
+
+		for _, mount := range serviceValues.ExtraVolumeMounts {
+			if !values.isPersistentVolume(mount.Name) {
+				continue
+			}
+
+			service.Volumes = append(service.Volumes, VolumeMount{
+				Name:      mount.Name,
+				MountPath: mount.MountPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+	}
+
+	return nil
+}
+
+// pvcBackedVolume returns a pod volume backed by the claim of a persistent volume
+func pvcBackedVolume(name string) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: name,
+			},
+		},
+	}
+}
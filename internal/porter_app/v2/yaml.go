@@ -16,12 +16,18 @@ type AppProtoWithEnv struct {
 	AppProto     *porterv1.PorterApp
 	Addons       []*porterv1.Addon
 	EnvVariables map[string]string
+	// ChartAddons are addons which are not deployed by the cluster control plane, and are instead installed as helm charts
+	ChartAddons []Addon
 }
 
 // AppWithPreviewOverrides is a porter app definition with its preview app definition, if it exists
 type AppWithPreviewOverrides struct {
 	AppProtoWithEnv
 	PreviewApp *AppProtoWithEnv
+
+	// ManagesHelmOverrides is true if porter.yaml declares the sidecars, init containers and volumes of the app. The helm overrides
+	// of the app must then be merged into the overrides of its current revision with MergeHelmOverrides before they are applied.
+	ManagesHelmOverrides bool
 }
 
 // AppProtoFromYaml converts a Porter YAML file into a PorterApp proto object
@@ -47,16 +53,14 @@ func AppProtoFromYaml(ctx context.Context, porterYamlBytes []byte) (AppWithPrevi
 	}
 	out.AppProto = appProto
 	out.EnvVariables = envVariables
+	out.ManagesHelmOverrides = true
 
-	var addons []*porterv1.Addon
-	for _, addon := range porterYaml.Addons {
-		addonProto, err := ProtoFromAddon(ctx, addon)
-		if err != nil {
-			return out, telemetry.Error(ctx, span, err, "error converting addon to proto")
-		}
-		addons = append(addons, addonProto)
+	addons, chartAddons, err := splitAddons(ctx, porterYaml.Addons)
+	if err != nil {
+		return out, telemetry.Error(ctx, span, err, "error converting addons")
 	}
 	out.Addons = addons
+	out.ChartAddons = chartAddons
 
 	if porterYaml.Previews != nil {
 		previewConfig := *porterYaml.Previews
@@ -70,18 +74,47 @@ func AppProtoFromYaml(ctx context.Context, porterYamlBytes []byte) (AppWithPrevi
 			EnvVariables: previewEnvVariables,
 		}
 
-		var previewAddons []*porterv1.Addon
-		for _, addon := range previewConfig.Addons {
+		previewAddons, previewChartAddons, err := splitAddons(ctx, previewConfig.Addons)
+		if err != nil {
+			return out, telemetry.Error(ctx, span, err, "error converting preview addons")
+		}
+		out.PreviewApp.Addons = previewAddons
+		out.PreviewApp.ChartAddons = previewChartAddons
+	}
+
+	return out, nil
+}
+
+// splitAddons converts the addons deployed by the cluster control plane to protos, and validates the remaining addons which are installed as helm charts
+func splitAddons(ctx context.Context, addons []Addon) ([]*porterv1.Addon, []Addon, error) {
+	ctx, span := telemetry.NewSpan(ctx, "split-addons")
+	defer span.End()
+
+	var addonProtos []*porterv1.Addon
+	var chartAddons []Addon
+
+	for _, addon := range addons {
+		if isProtoAddon(addon.Type) {
 			addonProto, err := ProtoFromAddon(ctx, addon)
 			if err != nil {
-				return out, telemetry.Error(ctx, span, err, "error converting preview addon to proto")
+				return nil, nil, telemetry.Error(ctx, span, err, "error converting addon to proto")
 			}
-			previewAddons = append(previewAddons, addonProto)
+			addonProtos = append(addonProtos, addonProto)
+			continue
 		}
-		out.PreviewApp.Addons = previewAddons
+
+		definition, err := ValidateAddon(ctx, addon)
+		if err != nil {
+			return nil, nil, telemetry.Error(ctx, span, err, "error validating addon")
+		}
+		if _, ok := definition.(ChartAddonDefinition); !ok {
+			return nil, nil, telemetry.Error(ctx, span, nil, "specified addon type not supported")
+		}
+
+		chartAddons = append(chartAddons, addon)
 	}
 
-	return out, nil
+	return addonProtos, chartAddons, nil
 }
 
 // ServiceType is the type of a service in a Porter YAML file
@@ -109,6 +142,7 @@ type PorterApp struct {
 	InitialDeploy *Service      `yaml:"initialDeploy,omitempty"`
 	EnvGroups     []string      `yaml:"envGroups,omitempty"`
 	EfsStorage    *EfsStorage   `yaml:"efsStorage,omitempty"`
+	Volumes       []Volume      `yaml:"volumes,omitempty"`
 	RequiredApps  []RequiredApp `yaml:"requiredApps,omitempty"`
 	AutoRollback  *AutoRollback `yaml:"autoRollback,omitempty"`
 }
@@ -191,6 +225,10 @@ type Service struct {
 	IngressAnnotations            map[string]string `yaml:"ingressAnnotations,omitempty" validate:"excluded_unless=Type web"`
 	DisableTLS                    *bool             `yaml:"disableTLS,omitempty" validate:"excluded_unless=Type web"`
 	Sleep                         *bool             `yaml:"sleep,omitempty" validate:"excluded_unless=Type job"`
+	Sidecars                      []Container       `yaml:"sidecars,omitempty"`
+	InitContainers                []Container       `yaml:"initContainers,omitempty"`
+	SharedVolumes                 []VolumeMount     `yaml:"sharedVolumes,omitempty"`
+	Volumes                       []VolumeMount     `yaml:"volumes,omitempty"`
 }
 
 // AutoScaling represents the autoscaling settings for web services
@@ -266,6 +304,12 @@ func ProtoFromApp(ctx context.Context, porterApp PorterApp) (*porterv1.PorterApp
 	}
 	appProto.ServiceList = services
 
+	helmOverrides, err := helmOverridesFromApp(ctx, porterApp, services)
+	if err != nil {
+		return appProto, nil, telemetry.Error(ctx, span, err, "error converting app to helm overrides")
+	}
+	appProto.HelmOverrides = helmOverrides
+
 	if porterApp.Predeploy != nil {
 		predeployProto, err := serviceProtoFromConfig(*porterApp.Predeploy, porterv1.ServiceType_SERVICE_TYPE_JOB)
 		if err != nil {
@@ -549,6 +593,11 @@ func AppFromProto(appProto *porterv1.PorterApp) (PorterApp, error) {
 		porterApp.Services = append(porterApp.Services, appService)
 	}
 
+	err := attachHelmOverridesToApp(appProto.HelmOverrides, &porterApp)
+	if err != nil {
+		return porterApp, err
+	}
+
 	if appProto.Predeploy != nil {
 		appPredeploy, err := appServiceFromProto(appProto.Predeploy)
 		if err != nil {
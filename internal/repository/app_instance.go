@@ -8,6 +8,8 @@ import (
 
 // AppInstanceRepository represents the set of queries on the AppInstance model
 type AppInstanceRepository interface {
-	// Get returns an app instance by its id
-	Get(ctx context.Context, id string) (*models.AppInstance, error)
+	// Get returns an app instance of a project by its id
+	Get(ctx context.Context, projectID uint, id string) (*models.AppInstance, error)
+	// Create creates an app instance
+	Create(ctx context.Context, appInstance *models.AppInstance) (*models.AppInstance, error)
 }
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+)
+
+// AuditLogFilter is used to filter the AuditLogs of a project
+type AuditLogFilter struct {
+	ClusterID uint
+	ActorID   string
+	ActorType string
+	Action    string
+	Resource  string
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+}
+
+// AuditLogFilters is a function that applies filters to the AuditLogs
+type AuditLogFilters func(*AuditLogFilter)
+
+// WithAuditLogClusterID filters the AuditLogs by cluster id
+func WithAuditLogClusterID(clusterID uint) AuditLogFilters {
+	return func(f *AuditLogFilter) {
+		f.ClusterID = clusterID
+	}
+}
+
+// WithAuditLogActor filters the AuditLogs by the id of the user or API token which made the request
+func WithAuditLogActor(actorType string, actorID string) AuditLogFilters {
+	return func(f *AuditLogFilter) {
+		f.ActorType = actorType
+		f.ActorID = actorID
+	}
+}
+
+// WithAuditLogAction filters the AuditLogs by action, such as "create" or "delete"
+func WithAuditLogAction(action string) AuditLogFilters {
+	return func(f *AuditLogFilter) {
+		f.Action = action
+	}
+}
+
+// WithAuditLogResource filters the AuditLogs to the route patterns containing the resource
+func WithAuditLogResource(resource string) AuditLogFilters {
+	return func(f *AuditLogFilter) {
+		f.Resource = resource
+	}
+}
+
+// WithAuditLogRequestID filters the AuditLogs by request id
+func WithAuditLogRequestID(requestID string) AuditLogFilters {
+	return func(f *AuditLogFilter) {
+		f.RequestID = requestID
+	}
+}
+
+// WithAuditLogTimeRange filters the AuditLogs to those created in the range. A zero time leaves that end of the range open.
+func WithAuditLogTimeRange(since, until time.Time) AuditLogFilters {
+	return func(f *AuditLogFilter) {
+		f.Since = since
+		f.Until = until
+	}
+}
+
+// AuditLogRepository represents the set of queries on the AuditLog model
+type AuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) (*models.AuditLog, error)
+	// ListAuditLogs returns the audit logs of a project, sorted by created_at descending
+	ListAuditLogs(ctx context.Context, projectID uint, filters []AuditLogFilters, opts ...helpers.QueryOption) ([]*models.AuditLog, helpers.PaginatedResult, error)
+}
@@ -0,0 +1,114 @@
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+// AWSSecretsManagerClient stores secrets in AWS Secrets Manager, under names like
+// <prefix>/<project id>/<kind>/<integration id>
+type AWSSecretsManagerClient struct {
+	prefix   string
+	kmsKeyID string
+	client   secretsmanageriface.SecretsManagerAPI
+}
+
+// NewAWSSecretsManagerClient returns an AWSSecretsManagerClient which uses the default credentials chain
+// of the AWS SDK. Secrets are encrypted with the given KMS key, or with the default key of the account if
+// it is empty.
+func NewAWSSecretsManagerClient(region, prefix, kmsKeyID string) (*AWSSecretsManagerClient, error) {
+	if prefix == "" {
+		return nil, errors.New("the prefix of the credential secrets must be set")
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating AWS session: %w", err)
+	}
+
+	return NewAWSSecretsManagerClientWithClient(prefix, kmsKeyID, secretsmanager.New(sess)), nil
+}
+
+// NewAWSSecretsManagerClientWithClient returns an AWSSecretsManagerClient which calls Secrets Manager with
+// the given client
+func NewAWSSecretsManagerClientWithClient(
+	prefix, kmsKeyID string,
+	client secretsmanageriface.SecretsManagerAPI,
+) *AWSSecretsManagerClient {
+	return &AWSSecretsManagerClient{
+		prefix:   prefix,
+		kmsKeyID: kmsKeyID,
+		client:   client,
+	}
+}
+
+// PutSecret adds a new version to the secret at the given path, and creates the secret if it does not exist
+func (c *AWSSecretsManagerClient) PutSecret(ctx context.Context, path string, value []byte) error {
+	name := c.secretName(path)
+
+	_, err := c.client.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(name),
+		SecretString: aws.String(string(value)),
+	})
+	if err == nil || !isAWSNotFound(err) {
+		return err
+	}
+
+	input := &secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: aws.String(string(value)),
+		Tags: []*secretsmanager.Tag{
+			{
+				Key:   aws.String("porter-prefix"),
+				Value: aws.String(c.prefix),
+			},
+		},
+	}
+
+	if c.kmsKeyID != "" {
+		input.KmsKeyId = aws.String(c.kmsKeyID)
+	}
+
+	_, err = c.client.CreateSecretWithContext(ctx, input)
+
+	return err
+}
+
+// GetSecret returns the current version of the secret at the given path
+func (c *AWSSecretsManagerClient) GetSecret(ctx context.Context, path string) ([]byte, error) {
+	res, err := c.client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(c.secretName(path)),
+	})
+	if err != nil {
+		if isAWSNotFound(err) {
+			return nil, ErrSecretNotFound
+		}
+
+		return nil, err
+	}
+
+	if res.SecretString != nil {
+		return []byte(*res.SecretString), nil
+	}
+
+	return res.SecretBinary, nil
+}
+
+func (c *AWSSecretsManagerClient) secretName(path string) string {
+	return fmt.Sprintf("%s/%s", c.prefix, path)
+}
+
+func isAWSNotFound(err error) bool {
+	var awsErr awserr.Error
+
+	return errors.As(err, &awsErr) && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException
+}
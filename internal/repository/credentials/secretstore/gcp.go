@@ -0,0 +1,111 @@
+package secretstore
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// GCPSecretManagerClient stores secrets in GCP Secret Manager, with ids like
+// <prefix>-<project id>-<kind>-<integration id>, since secret ids cannot contain slashes
+type GCPSecretManagerClient struct {
+	projectID string
+	prefix    string
+	service   *secretmanager.Service
+}
+
+// NewGCPSecretManagerClient returns a GCPSecretManagerClient which stores secrets in the given GCP project.
+// It uses the application default credentials, unless client options are passed.
+func NewGCPSecretManagerClient(
+	ctx context.Context,
+	projectID, prefix string,
+	opts ...option.ClientOption,
+) (*GCPSecretManagerClient, error) {
+	if projectID == "" {
+		return nil, errors.New("the GCP project of the credential secrets must be set")
+	}
+
+	if prefix == "" {
+		return nil, errors.New("the prefix of the credential secrets must be set")
+	}
+
+	service, err := secretmanager.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCP Secret Manager client: %w", err)
+	}
+
+	return &GCPSecretManagerClient{
+		projectID: projectID,
+		prefix:    prefix,
+		service:   service,
+	}, nil
+}
+
+// PutSecret adds a new version to the secret at the given path, and creates the secret if it does not exist
+func (c *GCPSecretManagerClient) PutSecret(ctx context.Context, path string, value []byte) error {
+	req := &secretmanager.AddSecretVersionRequest{
+		Payload: &secretmanager.SecretPayload{
+			Data: base64.StdEncoding.EncodeToString(value),
+		},
+	}
+
+	_, err := c.service.Projects.Secrets.AddVersion(c.secretName(path), req).Context(ctx).Do()
+	if err == nil || !isGCPNotFound(err) {
+		return err
+	}
+
+	_, err = c.service.Projects.Secrets.Create(fmt.Sprintf("projects/%s", c.projectID), &secretmanager.Secret{
+		Labels: map[string]string{
+			"porter-prefix": strings.ToLower(c.prefix),
+		},
+		Replication: &secretmanager.Replication{
+			Automatic: &secretmanager.Automatic{},
+		},
+	}).SecretId(c.secretID(path)).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.service.Projects.Secrets.AddVersion(c.secretName(path), req).Context(ctx).Do()
+
+	return err
+}
+
+// GetSecret returns the latest version of the secret at the given path
+func (c *GCPSecretManagerClient) GetSecret(ctx context.Context, path string) ([]byte, error) {
+	res, err := c.service.Projects.Secrets.Versions.Access(c.secretName(path) + "/versions/latest").Context(ctx).Do()
+	if err != nil {
+		if isGCPNotFound(err) {
+			return nil, ErrSecretNotFound
+		}
+
+		return nil, err
+	}
+
+	if res.Payload == nil {
+		return nil, fmt.Errorf("secret %s has no payload", c.secretID(path))
+	}
+
+	return base64.StdEncoding.DecodeString(res.Payload.Data)
+}
+
+func (c *GCPSecretManagerClient) secretID(path string) string {
+	return fmt.Sprintf("%s-%s", c.prefix, strings.ReplaceAll(path, "/", "-"))
+}
+
+func (c *GCPSecretManagerClient) secretName(path string) string {
+	return fmt.Sprintf("projects/%s/secrets/%s", c.projectID, c.secretID(path))
+}
+
+func isGCPNotFound(err error) bool {
+	var apiErr *googleapi.Error
+
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,238 @@
+// Package secretstore contains the credential storage backends which keep the credentials of integrations
+// in a cloud secret store, and selects the backend configured for an instance
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+)
+
+// BackendKind is the kind of a credential storage backend
+type BackendKind string
+
+const (
+	BackendKind_Database          BackendKind = "database"
+	BackendKind_Vault             BackendKind = "vault"
+	BackendKind_AWSSecretsManager BackendKind = "awssecretsmanager"
+	BackendKind_GCPSecretManager  BackendKind = "gcpsecretmanager"
+)
+
+// ErrSecretNotFound is returned by a SecretClient when a secret does not exist
+var ErrSecretNotFound = errors.New("secret not found")
+
+// ErrTokensNotSupported is returned when a scoped token is requested from a secret store which cannot
+// issue one
+var ErrTokensNotSupported = errors.New("credential storage backend does not support scoped tokens")
+
+// secretStoreTimeout bounds the calls made to the secret store
+const secretStoreTimeout = 30 * time.Second
+
+// NewCredentialStorage returns the credential storage backend configured by conf, or nil if credentials
+// are stored in the database. If no backend is set, Vault is used when it is configured.
+func NewCredentialStorage(ctx context.Context, conf *env.DBConf) (credentials.CredentialStorage, error) {
+	if conf.CredentialStorageBackend == "" {
+		if conf.VaultAPIKey != "" && conf.VaultServerURL != "" && conf.VaultPrefix != "" {
+			return NewBackend(ctx, conf, BackendKind_Vault)
+		}
+
+		return nil, nil
+	}
+
+	return NewBackend(ctx, conf, BackendKind(conf.CredentialStorageBackend))
+}
+
+// NewBackend returns the credential storage backend of the given kind, configured by conf. It returns nil
+// for the database backend, since credentials are then stored on the integrations themselves.
+func NewBackend(ctx context.Context, conf *env.DBConf, kind BackendKind) (credentials.CredentialStorage, error) {
+	switch kind {
+	case BackendKind_Database:
+		return nil, nil
+	case BackendKind_Vault:
+		if conf.VaultAPIKey == "" || conf.VaultServerURL == "" || conf.VaultPrefix == "" {
+			return nil, errors.New("vault credential storage requires VAULT_API_KEY, VAULT_SERVER_URL and VAULT_PREFIX")
+		}
+
+		return vault.NewClient(conf.VaultServerURL, conf.VaultAPIKey, conf.VaultPrefix), nil
+	case BackendKind_AWSSecretsManager:
+		client, err := NewAWSSecretsManagerClient(conf.AWSSecretsManagerRegion, conf.CredentialStoragePrefix, conf.AWSSecretsManagerKMSKeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewStorage(client), nil
+	case BackendKind_GCPSecretManager:
+		client, err := NewGCPSecretManagerClient(ctx, conf.GCPSecretManagerProjectID, conf.CredentialStoragePrefix)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewStorage(client), nil
+	}
+
+	return nil, fmt.Errorf("unknown credential storage backend %s", kind)
+}
+
+// SecretClient reads and writes the secrets of a secret store. Secrets are identified by paths like
+// <project id>/<kind>/<integration id>, which the client maps to the names of its store.
+type SecretClient interface {
+	// PutSecret creates the secret at the given path, or adds a new version if it exists
+	PutSecret(ctx context.Context, path string, value []byte) error
+
+	// GetSecret returns the latest version of the secret at the given path, or ErrSecretNotFound
+	GetSecret(ctx context.Context, path string) ([]byte, error)
+}
+
+// Storage implements credentials.CredentialStorage by storing each credential as a JSON-encoded secret
+type Storage struct {
+	client SecretClient
+}
+
+// NewStorage returns a Storage which stores credentials with the given client
+func NewStorage(client SecretClient) *Storage {
+	return &Storage{client}
+}
+
+func (s *Storage) WriteOAuthCredential(
+	oauthIntegration *integrations.OAuthIntegration,
+	data *credentials.OAuthCredential,
+) error {
+	return s.write(secretPath(oauthIntegration.ProjectID, "oauth", oauthIntegration.ID), data)
+}
+
+func (s *Storage) GetOAuthCredential(oauthIntegration *integrations.OAuthIntegration) (*credentials.OAuthCredential, error) {
+	data := &credentials.OAuthCredential{}
+
+	if err := s.read(secretPath(oauthIntegration.ProjectID, "oauth", oauthIntegration.ID), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *Storage) CreateOAuthToken(oauthIntegration *integrations.OAuthIntegration) (string, error) {
+	return "", ErrTokensNotSupported
+}
+
+func (s *Storage) WriteGCPCredential(
+	gcpIntegration *integrations.GCPIntegration,
+	data *credentials.GCPCredential,
+) error {
+	return s.write(secretPath(gcpIntegration.ProjectID, "gcp", gcpIntegration.ID), data)
+}
+
+func (s *Storage) GetGCPCredential(gcpIntegration *integrations.GCPIntegration) (*credentials.GCPCredential, error) {
+	data := &credentials.GCPCredential{}
+
+	if err := s.read(secretPath(gcpIntegration.ProjectID, "gcp", gcpIntegration.ID), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *Storage) CreateGCPToken(gcpIntegration *integrations.GCPIntegration) (string, error) {
+	return "", ErrTokensNotSupported
+}
+
+func (s *Storage) WriteAWSCredential(
+	awsIntegration *integrations.AWSIntegration,
+	data *credentials.AWSCredential,
+) error {
+	return s.write(secretPath(awsIntegration.ProjectID, "aws", awsIntegration.ID), data)
+}
+
+func (s *Storage) GetAWSCredential(awsIntegration *integrations.AWSIntegration) (*credentials.AWSCredential, error) {
+	data := &credentials.AWSCredential{}
+
+	if err := s.read(secretPath(awsIntegration.ProjectID, "aws", awsIntegration.ID), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *Storage) CreateAWSToken(awsIntegration *integrations.AWSIntegration) (string, error) {
+	return "", ErrTokensNotSupported
+}
+
+func (s *Storage) WriteAzureCredential(
+	azIntegration *integrations.AzureIntegration,
+	data *credentials.AzureCredential,
+) error {
+	return s.write(secretPath(azIntegration.ProjectID, "azure", azIntegration.ID), data)
+}
+
+func (s *Storage) GetAzureCredential(azIntegration *integrations.AzureIntegration) (*credentials.AzureCredential, error) {
+	data := &credentials.AzureCredential{}
+
+	if err := s.read(secretPath(azIntegration.ProjectID, "azure", azIntegration.ID), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *Storage) CreateAzureToken(azIntegration *integrations.AzureIntegration) (string, error) {
+	return "", ErrTokensNotSupported
+}
+
+func (s *Storage) WriteGitlabCredential(
+	giIntegration *integrations.GitlabIntegration,
+	data *credentials.GitlabCredential,
+) error {
+	return s.write(secretPath(giIntegration.ProjectID, "gitlab", giIntegration.ID), data)
+}
+
+func (s *Storage) GetGitlabCredential(giIntegration *integrations.GitlabIntegration) (*credentials.GitlabCredential, error) {
+	data := &credentials.GitlabCredential{}
+
+	if err := s.read(secretPath(giIntegration.ProjectID, "gitlab", giIntegration.ID), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *Storage) CreateGitlabToken(giIntegration *integrations.GitlabIntegration) (string, error) {
+	return "", ErrTokensNotSupported
+}
+
+func (s *Storage) write(path string, data interface{}) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretStoreTimeout)
+	defer cancel()
+
+	if err := s.client.PutSecret(ctx, path, value); err != nil {
+		return fmt.Errorf("error writing secret %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) read(path string, dst interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretStoreTimeout)
+	defer cancel()
+
+	value, err := s.client.GetSecret(ctx, path)
+	if err != nil {
+		return fmt.Errorf("error reading secret %s: %w", path, err)
+	}
+
+	return json.Unmarshal(value, dst)
+}
+
+func secretPath(projectID uint, kind string, id uint) string {
+	return fmt.Sprintf("%d/%s/%d", projectID, kind, id)
+}
@@ -0,0 +1,273 @@
+package secretstore_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+)
+
+func TestAWSSecretsManagerStorage(t *testing.T) {
+	assert := assert.New(t)
+
+	fake := &fakeSecretsManager{secrets: make(map[string]string)}
+	storage := secretstore.NewStorage(secretstore.NewAWSSecretsManagerClientWithClient("porter", "alias/porter", fake))
+
+	testStorage(t, storage)
+
+	assert.Contains(fake.secrets, "porter/1/oauth/2")
+	assert.Contains(fake.secrets, "porter/1/aws/3")
+	assert.Equal("alias/porter", fake.kmsKeyID)
+}
+
+func TestGCPSecretManagerStorage(t *testing.T) {
+	assert := assert.New(t)
+
+	fake := &fakeSecretManager{secrets: make(map[string][]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := secretstore.NewGCPSecretManagerClient(
+		context.Background(),
+		"gcp-project",
+		"porter",
+		option.WithEndpoint(server.URL+"/"),
+		option.WithoutAuthentication(),
+	)
+	assert.NoError(err)
+
+	testStorage(t, secretstore.NewStorage(client))
+
+	assert.Contains(fake.secrets, "projects/gcp-project/secrets/porter-1-oauth-2")
+	assert.Len(fake.secrets["projects/gcp-project/secrets/porter-1-oauth-2"], 2, "overwriting a credential should add a version")
+}
+
+func TestNewBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	backend, err := secretstore.NewCredentialStorage(context.Background(), &env.DBConf{})
+	assert.NoError(err)
+	assert.Nil(backend, "credentials should be stored in the database by default")
+
+	backend, err = secretstore.NewCredentialStorage(context.Background(), &env.DBConf{
+		VaultAPIKey:    "key",
+		VaultServerURL: "http://vault",
+		VaultPrefix:    "production",
+	})
+	assert.NoError(err)
+	assert.NotNil(backend, "vault should be used when it is configured")
+
+	_, err = secretstore.NewCredentialStorage(context.Background(), &env.DBConf{
+		CredentialStorageBackend: string(secretstore.BackendKind_Vault),
+	})
+	assert.Error(err)
+
+	_, err = secretstore.NewCredentialStorage(context.Background(), &env.DBConf{
+		CredentialStorageBackend: string(secretstore.BackendKind_GCPSecretManager),
+		CredentialStoragePrefix:  "porter",
+	})
+	assert.Error(err, "the GCP project should be required")
+
+	_, err = secretstore.NewCredentialStorage(context.Background(), &env.DBConf{
+		CredentialStorageBackend: "unknown",
+	})
+	assert.Error(err)
+}
+
+func testStorage(t *testing.T, storage credentials.CredentialStorage) {
+	assert := assert.New(t)
+
+	oauth := &integrations.OAuthIntegration{}
+	oauth.ID = 2
+	oauth.ProjectID = 1
+
+	_, err := storage.GetOAuthCredential(oauth)
+	assert.ErrorIs(err, secretstore.ErrSecretNotFound)
+
+	assert.NoError(storage.WriteOAuthCredential(oauth, &credentials.OAuthCredential{
+		AccessToken:  []byte("access"),
+		RefreshToken: []byte("refresh"),
+	}))
+
+	assert.NoError(storage.WriteOAuthCredential(oauth, &credentials.OAuthCredential{
+		AccessToken:  []byte("access-2"),
+		RefreshToken: []byte("refresh-2"),
+	}))
+
+	oauthCred, err := storage.GetOAuthCredential(oauth)
+	assert.NoError(err)
+	assert.Equal("access-2", string(oauthCred.AccessToken))
+	assert.Equal("refresh-2", string(oauthCred.RefreshToken))
+
+	awsInt := &integrations.AWSIntegration{}
+	awsInt.ID = 3
+	awsInt.ProjectID = 1
+
+	assert.NoError(storage.WriteAWSCredential(awsInt, &credentials.AWSCredential{
+		AWSAccessKeyID:     []byte("key-id"),
+		AWSSecretAccessKey: []byte("secret"),
+	}))
+
+	awsCred, err := storage.GetAWSCredential(awsInt)
+	assert.NoError(err)
+	assert.Equal("key-id", string(awsCred.AWSAccessKeyID))
+	assert.Equal("secret", string(awsCred.AWSSecretAccessKey))
+
+	_, err = storage.CreateAWSToken(awsInt)
+	assert.ErrorIs(err, secretstore.ErrTokensNotSupported)
+}
+
+// fakeSecretsManager stores the current version of each secret in memory
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	mu       sync.Mutex
+	secrets  map[string]string
+	kmsKeyID string
+}
+
+func (f *fakeSecretsManager) CreateSecretWithContext(
+	ctx aws.Context,
+	input *secretsmanager.CreateSecretInput,
+	opts ...request.Option,
+) (*secretsmanager.CreateSecretOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.secrets[*input.Name]; ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceExistsException, "secret exists", nil)
+	}
+
+	f.secrets[*input.Name] = *input.SecretString
+	f.kmsKeyID = aws.StringValue(input.KmsKeyId)
+
+	return &secretsmanager.CreateSecretOutput{Name: input.Name}, nil
+}
+
+func (f *fakeSecretsManager) PutSecretValueWithContext(
+	ctx aws.Context,
+	input *secretsmanager.PutSecretValueInput,
+	opts ...request.Option,
+) (*secretsmanager.PutSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.secrets[*input.SecretId]; !ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+	}
+
+	f.secrets[*input.SecretId] = *input.SecretString
+
+	return &secretsmanager.PutSecretValueOutput{Name: input.SecretId}, nil
+}
+
+func (f *fakeSecretsManager) GetSecretValueWithContext(
+	ctx aws.Context,
+	input *secretsmanager.GetSecretValueInput,
+	opts ...request.Option,
+) (*secretsmanager.GetSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.secrets[*input.SecretId]
+	if !ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+	}
+
+	return &secretsmanager.GetSecretValueOutput{Name: input.SecretId, SecretString: aws.String(value)}, nil
+}
+
+// fakeSecretManager serves the secrets endpoints of the GCP Secret Manager REST API, storing the base64-encoded
+// versions of each secret in memory
+type fakeSecretManager struct {
+	mu      sync.Mutex
+	secrets map[string][]string
+}
+
+func (f *fakeSecretManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/secrets"):
+		name := path + "/" + r.URL.Query().Get("secretId")
+
+		if _, ok := f.secrets[name]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		f.secrets[name] = []string{}
+
+		json.NewEncoder(w).Encode(map[string]string{"name": name}) // nolint:errcheck
+	case r.Method == http.MethodPost && strings.HasSuffix(path, ":addVersion"):
+		name := strings.TrimSuffix(path, ":addVersion")
+
+		if _, ok := f.secrets[name]; !ok {
+			writeGCPNotFound(w)
+			return
+		}
+
+		req := struct {
+			Payload struct {
+				Data string `json:"data"`
+			} `json:"payload"`
+		}{}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f.secrets[name] = append(f.secrets[name], req.Payload.Data)
+
+		json.NewEncoder(w).Encode(map[string]string{"name": name + "/versions/1"}) // nolint:errcheck
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/versions/latest:access"):
+		name := strings.TrimSuffix(path, "/versions/latest:access")
+
+		versions := f.secrets[name]
+		if len(versions) == 0 {
+			writeGCPNotFound(w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{ // nolint:errcheck
+			"name": name + "/versions/latest",
+			"payload": map[string]string{
+				"data": versions[len(versions)-1],
+			},
+		})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func writeGCPNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{ // nolint:errcheck
+		"error": map[string]interface{}{
+			"code":    http.StatusNotFound,
+			"message": "secret not found",
+			"status":  "NOT_FOUND",
+		},
+	})
+}
@@ -8,4 +8,6 @@ import (
 // DNSRecord model
 type DNSRecordRepository interface {
 	CreateDNSRecord(record *models.DNSRecord) (*models.DNSRecord, error)
+	ListDNSRecords() ([]*models.DNSRecord, error)
+	DeleteDNSRecord(record *models.DNSRecord) error
 }
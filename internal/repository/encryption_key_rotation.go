@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// EncryptionKeyRotationRepository represents the set of queries on the EncryptionKeyRotation model
+type EncryptionKeyRotationRepository interface {
+	CreateEncryptionKeyRotation(ctx context.Context, rotation *models.EncryptionKeyRotation) (*models.EncryptionKeyRotation, error)
+	ReadEncryptionKeyRotation(ctx context.Context, keyID, table string) (*models.EncryptionKeyRotation, error)
+	UpdateEncryptionKeyRotation(ctx context.Context, rotation *models.EncryptionKeyRotation) (*models.EncryptionKeyRotation, error)
+	ListEncryptionKeyRotations(ctx context.Context, keyID string) ([]*models.EncryptionKeyRotation, error)
+}
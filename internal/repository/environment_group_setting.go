@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// EnvironmentGroupSettingRepository represents the set of queries on the EnvironmentGroupSetting model
+type EnvironmentGroupSettingRepository interface {
+	// ReadEnvironmentGroupSetting returns the settings of an environment group, or gorm.ErrRecordNotFound if none were stored
+	ReadEnvironmentGroupSetting(ctx context.Context, clusterID uint, environmentGroupName string) (*models.EnvironmentGroupSetting, error)
+	// UpsertEnvironmentGroupSetting creates or updates the settings of an environment group
+	UpsertEnvironmentGroupSetting(ctx context.Context, setting *models.EnvironmentGroupSetting) (*models.EnvironmentGroupSetting, error)
+	// ListEnvironmentGroupSettings returns the settings of all environment groups of a cluster
+	ListEnvironmentGroupSettings(ctx context.Context, clusterID uint) ([]*models.EnvironmentGroupSetting, error)
+	// DeleteEnvironmentGroupSetting deletes the settings of an environment group, if any
+	DeleteEnvironmentGroupSetting(ctx context.Context, clusterID uint, environmentGroupName string) error
+}
@@ -1,6 +1,8 @@
 package gorm
 
 import (
+	"time"
+
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 	"gorm.io/gorm"
@@ -53,3 +55,26 @@ func (repo *APITokenRepository) UpdateAPIToken(
 
 	return token, nil
 }
+
+// UpdateAPITokenLastUsed stores the last used time and ip of the token, without overwriting any other
+// field which may have been changed since the token was read
+func (repo *APITokenRepository) UpdateAPITokenLastUsed(token *models.APIToken) error {
+	return repo.db.Model(&models.APIToken{}).Where("id = ?", token.ID).UpdateColumns(map[string]interface{}{
+		"last_used_at": token.LastUsedAt,
+		"last_used_ip": token.LastUsedIP,
+	}).Error
+}
+
+// ListAPITokensExpiringBefore lists the tokens which are not revoked or expired, expire before the given
+// time, and whose creators have not been notified of the expiry yet
+func (repo *APITokenRepository) ListAPITokensExpiringBefore(before time.Time) ([]*models.APIToken, error) {
+	tokens := []*models.APIToken{}
+
+	if err := repo.db.Where(
+		"NOT revoked AND expiry_notified_at IS NULL AND expiry > ? AND expiry <= ?", time.Now(), before,
+	).Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
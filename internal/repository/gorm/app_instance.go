@@ -3,6 +3,7 @@ package gorm
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/telemetry"
@@ -20,21 +21,44 @@ func NewAppInstanceRepository(db *gorm.DB) repository.AppInstanceRepository {
 	return &AppInstanceRepository{db}
 }
 
-// Get returns an app instance by its id
-func (repo *AppInstanceRepository) Get(ctx context.Context, id string) (*models.AppInstance, error) {
+// Get returns an app instance of a project by its id
+func (repo *AppInstanceRepository) Get(ctx context.Context, projectID uint, id string) (*models.AppInstance, error) {
 	ctx, span := telemetry.NewSpan(ctx, "gorm-get-app-instance")
 	defer span.End()
 
 	appInstance := &models.AppInstance{}
 
-	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "app-instance-id", Value: id})
+	telemetry.WithAttributes(span,
+		telemetry.AttributeKV{Key: "project-id", Value: projectID},
+		telemetry.AttributeKV{Key: "app-instance-id", Value: id},
+	)
 	if id == "" {
 		return nil, telemetry.Error(ctx, span, nil, "id is empty")
 	}
 
-	if err := repo.db.Where("id = ?", id).First(&appInstance).Error; err != nil {
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, id).First(&appInstance).Error; err != nil {
 		return nil, telemetry.Error(ctx, span, err, "error getting app instance")
 	}
 
 	return appInstance, nil
 }
+
+// Create creates an app instance
+func (repo *AppInstanceRepository) Create(ctx context.Context, appInstance *models.AppInstance) (*models.AppInstance, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-app-instance")
+	defer span.End()
+
+	if appInstance == nil {
+		return nil, telemetry.Error(ctx, span, nil, "app instance is nil")
+	}
+
+	if appInstance.ID == uuid.Nil {
+		appInstance.ID = uuid.New()
+	}
+
+	if err := repo.db.Create(appInstance).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating app instance")
+	}
+
+	return appInstance, nil
+}
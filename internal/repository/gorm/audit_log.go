@@ -0,0 +1,89 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository uses gorm.DB for querying the database
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository returns an AuditLogRepository which uses
+// gorm.DB for querying the database
+func NewAuditLogRepository(db *gorm.DB) repository.AuditLogRepository {
+	return &AuditLogRepository{db}
+}
+
+// CreateAuditLog creates a new audit log entry
+func (repo *AuditLogRepository) CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) (*models.AuditLog, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-audit-log")
+	defer span.End()
+
+	if auditLog == nil {
+		return nil, telemetry.Error(ctx, span, nil, "audit log is nil")
+	}
+
+	if err := repo.db.Create(auditLog).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating audit log")
+	}
+
+	return auditLog, nil
+}
+
+// ListAuditLogs returns the audit logs of a project matching the filters, sorted by created_at descending
+func (repo *AuditLogRepository) ListAuditLogs(ctx context.Context, projectID uint, filters []repository.AuditLogFilters, opts ...helpers.QueryOption) ([]*models.AuditLog, helpers.PaginatedResult, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-list-audit-logs")
+	defer span.End()
+
+	telemetry.WithAttributes(span, telemetry.AttributeKV{Key: "project-id", Value: projectID})
+
+	auditLogs := []*models.AuditLog{}
+	paginatedResult := helpers.PaginatedResult{}
+
+	filter := &repository.AuditLogFilter{}
+	for _, f := range filters {
+		f(filter)
+	}
+
+	db := repo.db.Model(&models.AuditLog{}).Where("project_id = ?", projectID)
+
+	if filter.ClusterID != 0 {
+		db = db.Where("cluster_id = ?", filter.ClusterID)
+	}
+	if filter.ActorType != "" {
+		db = db.Where("actor_type = ?", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		db = db.Where("actor_id = ? OR actor_email = ?", filter.ActorID, filter.ActorID)
+	}
+	if filter.Action != "" {
+		db = db.Where("action = ?", filter.Action)
+	}
+	if filter.Resource != "" {
+		db = db.Where("resource LIKE ?", "%"+filter.Resource+"%")
+	}
+	if filter.RequestID != "" {
+		db = db.Where("request_id = ?", filter.RequestID)
+	}
+	if !filter.Since.IsZero() {
+		db = db.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		db = db.Where("created_at <= ?", filter.Until)
+	}
+
+	resultDB := db.Order("created_at DESC").Scopes(helpers.Paginate(db, &paginatedResult, opts...))
+
+	if err := resultDB.Find(&auditLogs).Error; err != nil {
+		return nil, paginatedResult, telemetry.Error(ctx, span, err, "error listing audit logs")
+	}
+
+	return auditLogs, paginatedResult, nil
+}
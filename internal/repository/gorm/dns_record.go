@@ -25,3 +25,19 @@ func (repo *DNSRecordRepository) CreateDNSRecord(record *models.DNSRecord) (*mod
 
 	return record, nil
 }
+
+// ListDNSRecords lists all dns records
+func (repo *DNSRecordRepository) ListDNSRecords() ([]*models.DNSRecord, error) {
+	records := []*models.DNSRecord{}
+
+	if err := repo.db.Order("id asc").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// DeleteDNSRecord deletes a dns record
+func (repo *DNSRecordRepository) DeleteDNSRecord(record *models.DNSRecord) error {
+	return repo.db.Delete(record).Error
+}
@@ -0,0 +1,87 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// EncryptionKeyRotationRepository uses gorm.DB for querying the database
+type EncryptionKeyRotationRepository struct {
+	db *gorm.DB
+}
+
+// NewEncryptionKeyRotationRepository returns an EncryptionKeyRotationRepository which uses
+// gorm.DB for querying the database
+func NewEncryptionKeyRotationRepository(db *gorm.DB) repository.EncryptionKeyRotationRepository {
+	return &EncryptionKeyRotationRepository{db}
+}
+
+// CreateEncryptionKeyRotation creates the progress of the rotation of a table
+func (repo *EncryptionKeyRotationRepository) CreateEncryptionKeyRotation(
+	ctx context.Context,
+	rotation *models.EncryptionKeyRotation,
+) (*models.EncryptionKeyRotation, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-encryption-key-rotation")
+	defer span.End()
+
+	if rotation == nil {
+		return nil, telemetry.Error(ctx, span, nil, "encryption key rotation is nil")
+	}
+
+	if err := repo.db.Create(rotation).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating encryption key rotation")
+	}
+
+	return rotation, nil
+}
+
+// ReadEncryptionKeyRotation reads the progress of the rotation of a table under a key
+func (repo *EncryptionKeyRotationRepository) ReadEncryptionKeyRotation(
+	ctx context.Context,
+	keyID, table string,
+) (*models.EncryptionKeyRotation, error) {
+	rotation := &models.EncryptionKeyRotation{}
+
+	if err := repo.db.Where("key_id = ? AND model_table = ?", keyID, table).First(rotation).Error; err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+// UpdateEncryptionKeyRotation updates the progress of the rotation of a table
+func (repo *EncryptionKeyRotationRepository) UpdateEncryptionKeyRotation(
+	ctx context.Context,
+	rotation *models.EncryptionKeyRotation,
+) (*models.EncryptionKeyRotation, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-encryption-key-rotation")
+	defer span.End()
+
+	if rotation == nil {
+		return nil, telemetry.Error(ctx, span, nil, "encryption key rotation is nil")
+	}
+
+	if err := repo.db.Save(rotation).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating encryption key rotation")
+	}
+
+	return rotation, nil
+}
+
+// ListEncryptionKeyRotations lists the progress of the rotation of every table under a key
+func (repo *EncryptionKeyRotationRepository) ListEncryptionKeyRotations(
+	ctx context.Context,
+	keyID string,
+) ([]*models.EncryptionKeyRotation, error) {
+	rotations := []*models.EncryptionKeyRotation{}
+
+	if err := repo.db.Where("key_id = ?", keyID).Order("id asc").Find(&rotations).Error; err != nil {
+		return nil, err
+	}
+
+	return rotations, nil
+}
@@ -0,0 +1,95 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestEncryptionKeyRotationProgress(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_encryption_key_rotations.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+
+	_, err := tester.repo.EncryptionKeyRotation().ReadEncryptionKeyRotation(ctx, "local:2", "clusters")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	for _, table := range []string{"clusters", "registries"} {
+		_, err := tester.repo.EncryptionKeyRotation().CreateEncryptionKeyRotation(ctx, &models.EncryptionKeyRotation{
+			KeyID:      "local:2",
+			ModelTable: table,
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	// the progress of a previous rotation is kept separately
+	_, err = tester.repo.EncryptionKeyRotation().CreateEncryptionKeyRotation(ctx, &models.EncryptionKeyRotation{
+		KeyID:      "local:1",
+		ModelTable: "clusters",
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	rotation, err := tester.repo.EncryptionKeyRotation().ReadEncryptionKeyRotation(ctx, "local:2", "clusters")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	completedAt := time.Now().UTC()
+	rotation.LastID = 42
+	rotation.Rotated = 40
+	rotation.Skipped = 2
+	rotation.CompletedAt = &completedAt
+
+	if _, err := tester.repo.EncryptionKeyRotation().UpdateEncryptionKeyRotation(ctx, rotation); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	rotations, err := tester.repo.EncryptionKeyRotation().ListEncryptionKeyRotations(ctx, "local:2")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(rotations) != 2 {
+		t.Fatalf("expected 2 tables for key local:2, got %d", len(rotations))
+	}
+
+	if rotations[0].ModelTable != "clusters" || rotations[0].LastID != 42 || rotations[0].CompletedAt == nil {
+		t.Errorf("expected clusters to be completed at row 42, got %s at row %d", rotations[0].ModelTable, rotations[0].LastID)
+	}
+
+	if rotations[1].ModelTable != "registries" || rotations[1].CompletedAt != nil {
+		t.Errorf("expected registries to be in progress, got %s", rotations[1].ModelTable)
+	}
+
+	// rows which failed are kept for the next run
+	rotations[1].LastID = 10
+	rotations[1].SetFailedIDs([]uint{3, 7})
+
+	if _, err := tester.repo.EncryptionKeyRotation().UpdateEncryptionKeyRotation(ctx, rotations[1]); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	rotation, err = tester.repo.EncryptionKeyRotation().ReadEncryptionKeyRotation(ctx, "local:2", "registries")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if failedIDs := rotation.FailedIDList(); rotation.Failed != 2 || len(failedIDs) != 2 || failedIDs[0] != 3 || failedIDs[1] != 7 {
+		t.Errorf("expected rows 3 and 7 to be failed, got %d failed with ids %v", rotation.Failed, failedIDs)
+	}
+}
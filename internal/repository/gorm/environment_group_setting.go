@@ -0,0 +1,98 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// EnvironmentGroupSettingRepository uses gorm.DB for querying the database
+type EnvironmentGroupSettingRepository struct {
+	db *gorm.DB
+}
+
+// NewEnvironmentGroupSettingRepository returns an EnvironmentGroupSettingRepository which uses
+// gorm.DB for querying the database
+func NewEnvironmentGroupSettingRepository(db *gorm.DB) repository.EnvironmentGroupSettingRepository {
+	return &EnvironmentGroupSettingRepository{db}
+}
+
+// ReadEnvironmentGroupSetting returns the settings of an environment group, or gorm.ErrRecordNotFound if none were stored
+func (repo *EnvironmentGroupSettingRepository) ReadEnvironmentGroupSetting(
+	ctx context.Context,
+	clusterID uint,
+	environmentGroupName string,
+) (*models.EnvironmentGroupSetting, error) {
+	setting := &models.EnvironmentGroupSetting{}
+
+	if err := repo.db.Where("cluster_id = ? AND environment_group_name = ?", clusterID, environmentGroupName).First(setting).Error; err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// UpsertEnvironmentGroupSetting creates or updates the settings of an environment group
+func (repo *EnvironmentGroupSettingRepository) UpsertEnvironmentGroupSetting(
+	ctx context.Context,
+	setting *models.EnvironmentGroupSetting,
+) (*models.EnvironmentGroupSetting, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-upsert-environment-group-setting")
+	defer span.End()
+
+	if setting == nil {
+		return nil, telemetry.Error(ctx, span, nil, "environment group setting is nil")
+	}
+
+	existing, err := repo.ReadEnvironmentGroupSetting(ctx, setting.ClusterID, setting.EnvironmentGroupName)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, telemetry.Error(ctx, span, err, "error reading environment group setting")
+	}
+
+	if existing != nil {
+		setting.ID = existing.ID
+		setting.CreatedAt = existing.CreatedAt
+	}
+
+	if err := repo.db.Save(setting).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error saving environment group setting")
+	}
+
+	return setting, nil
+}
+
+// ListEnvironmentGroupSettings returns the settings of all environment groups of a cluster
+func (repo *EnvironmentGroupSettingRepository) ListEnvironmentGroupSettings(
+	ctx context.Context,
+	clusterID uint,
+) ([]*models.EnvironmentGroupSetting, error) {
+	settings := []*models.EnvironmentGroupSetting{}
+
+	if err := repo.db.Where("cluster_id = ?", clusterID).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// DeleteEnvironmentGroupSetting deletes the settings of an environment group, if any
+func (repo *EnvironmentGroupSettingRepository) DeleteEnvironmentGroupSetting(
+	ctx context.Context,
+	clusterID uint,
+	environmentGroupName string,
+) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-environment-group-setting")
+	defer span.End()
+
+	// settings are hard deleted, so that an environment group which is recreated with the same name starts without settings
+	err := repo.db.Unscoped().Where("cluster_id = ? AND environment_group_name = ?", clusterID, environmentGroupName).Delete(&models.EnvironmentGroupSetting{}).Error
+	if err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting environment group setting")
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestUpsertEnvironmentGroupSetting(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_environment_group_settings.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+
+	_, err := tester.repo.EnvironmentGroupSetting().ReadEnvironmentGroupSetting(ctx, 1, "my-env")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	for _, autoRollout := range []bool{true, false, true} {
+		_, err := tester.repo.EnvironmentGroupSetting().UpsertEnvironmentGroupSetting(ctx, &models.EnvironmentGroupSetting{
+			ProjectID:            1,
+			ClusterID:            1,
+			EnvironmentGroupName: "my-env",
+			AutoRollout:          autoRollout,
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	// an environment group with the same name on another cluster has its own settings
+	_, err = tester.repo.EnvironmentGroupSetting().UpsertEnvironmentGroupSetting(ctx, &models.EnvironmentGroupSetting{
+		ProjectID:            1,
+		ClusterID:            2,
+		EnvironmentGroupName: "my-env",
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	setting, err := tester.repo.EnvironmentGroupSetting().ReadEnvironmentGroupSetting(ctx, 1, "my-env")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !setting.AutoRollout {
+		t.Errorf("expected auto rollout to be enabled")
+	}
+
+	settings, err := tester.repo.EnvironmentGroupSetting().ListEnvironmentGroupSettings(ctx, 1)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(settings) != 1 {
+		t.Errorf("expected 1 setting for cluster, got %d", len(settings))
+	}
+
+	err = tester.repo.EnvironmentGroupSetting().DeleteEnvironmentGroupSetting(ctx, 1, "my-env")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	_, err = tester.repo.EnvironmentGroupSetting().ReadEnvironmentGroupSetting(ctx, 1, "my-env")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found after delete, got %v", err)
+	}
+
+	// settings can be created again once deleted
+	_, err = tester.repo.EnvironmentGroupSetting().UpsertEnvironmentGroupSetting(ctx, &models.EnvironmentGroupSetting{
+		ProjectID:            1,
+		ClusterID:            1,
+		EnvironmentGroupName: "my-env",
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+}
@@ -81,6 +81,14 @@ func setupTestEnv(tester *tester, t *testing.T) {
 		&models.Allowlist{},
 		&models.Tag{},
 		&models.APIToken{},
+		&models.WorkerJobRun{},
+		&models.EncryptionKeyRotation{},
+		&models.EnvironmentGroupSetting{},
+		&models.LogArchiveSetting{},
+		&models.LogArchiveCheckpoint{},
+		&models.LogArchive{},
+		&models.SSOConnection{},
+		&models.SSODomain{},
 		&ints.KubeIntegration{},
 		&ints.BasicIntegration{},
 		&ints.OIDCIntegration{},
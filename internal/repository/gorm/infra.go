@@ -245,6 +245,29 @@ func (repo *InfraRepository) UpdateOperation(
 	return operation, nil
 }
 
+// UpdateOperationStatus sets the status of an operation to toStatus only if its status is fromStatus,
+// and returns whether the operation was updated. The status is compared in the update query, so that
+// only one of several concurrent updates from the same status succeeds.
+func (repo *InfraRepository) UpdateOperationStatus(
+	operation *models.Operation,
+	fromStatus, toStatus string,
+) (bool, error) {
+	res := repo.db.Model(&models.Operation{}).
+		Where("id = ? AND status = ?", operation.ID, fromStatus).
+		Update("status", toStatus)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	operation.Status = toStatus
+
+	return true, nil
+}
+
 // EncryptInfraData will encrypt the infra data before
 // writing to the DB
 func (repo *InfraRepository) EncryptInfraData(
@@ -89,3 +89,56 @@ func TestListInfrasByProjectID(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestUpdateOperationStatus(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_update_operation_status.db",
+	}
+
+	setupTestEnv(tester, t)
+	initInfra(tester, t)
+	defer cleanup(tester, t)
+
+	operationUID, err := models.GetOperationID()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	operation, err := tester.repo.Infra().AddOperation(tester.initInfras[0], &models.Operation{
+		UID:         operationUID,
+		Type:        "plan",
+		Status:      "planned",
+		LastApplied: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// only the first of two approvals read with the same status updates the operation
+	first := *operation
+	second := *operation
+
+	updated, err := tester.repo.Infra().UpdateOperationStatus(&first, "planned", "approved")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if !updated || first.Status != "approved" {
+		t.Errorf("expected the first update to succeed, got %t with status %s", updated, first.Status)
+	}
+
+	updated, err = tester.repo.Infra().UpdateOperationStatus(&second, "planned", "approved")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if updated || second.Status != "planned" {
+		t.Errorf("expected the second update to fail, got %t with status %s", updated, second.Status)
+	}
+
+	operation, err = tester.repo.Infra().ReadOperation(tester.initInfras[0].ID, operationUID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if operation.Status != "approved" {
+		t.Errorf("expected status approved, got %s", operation.Status)
+	}
+}
@@ -0,0 +1,188 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// LogArchiveRepository uses gorm.DB for querying the database
+type LogArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewLogArchiveRepository returns a LogArchiveRepository which uses gorm.DB for querying the database
+func NewLogArchiveRepository(db *gorm.DB) repository.LogArchiveRepository {
+	return &LogArchiveRepository{db}
+}
+
+// ReadLogArchiveSetting returns the log archive setting of a project, or gorm.ErrRecordNotFound if none was stored
+func (repo *LogArchiveRepository) ReadLogArchiveSetting(ctx context.Context, projectID uint) (*models.LogArchiveSetting, error) {
+	setting := &models.LogArchiveSetting{}
+
+	if err := repo.db.Where("project_id = ?", projectID).First(setting).Error; err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// UpsertLogArchiveSetting creates or updates the log archive setting of a project
+func (repo *LogArchiveRepository) UpsertLogArchiveSetting(
+	ctx context.Context,
+	setting *models.LogArchiveSetting,
+) (*models.LogArchiveSetting, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-upsert-log-archive-setting")
+	defer span.End()
+
+	if setting == nil {
+		return nil, telemetry.Error(ctx, span, nil, "log archive setting is nil")
+	}
+
+	existing, err := repo.ReadLogArchiveSetting(ctx, setting.ProjectID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, telemetry.Error(ctx, span, err, "error reading log archive setting")
+	}
+
+	if existing != nil {
+		setting.ID = existing.ID
+		setting.CreatedAt = existing.CreatedAt
+	}
+
+	if err := repo.db.Save(setting).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error saving log archive setting")
+	}
+
+	return setting, nil
+}
+
+// ListEnabledLogArchiveSettings returns the log archive settings of every project which archives its logs
+func (repo *LogArchiveRepository) ListEnabledLogArchiveSettings(ctx context.Context) ([]*models.LogArchiveSetting, error) {
+	settings := []*models.LogArchiveSetting{}
+
+	if err := repo.db.Where("enabled = ?", true).Order("project_id asc").Find(&settings).Error; err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// ReadLogArchiveCheckpoint returns the checkpoint of a cluster, or gorm.ErrRecordNotFound if its logs were never archived
+func (repo *LogArchiveRepository) ReadLogArchiveCheckpoint(ctx context.Context, clusterID uint) (*models.LogArchiveCheckpoint, error) {
+	checkpoint := &models.LogArchiveCheckpoint{}
+
+	if err := repo.db.Where("cluster_id = ?", clusterID).First(checkpoint).Error; err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// UpsertLogArchiveCheckpoint creates or updates the checkpoint of a cluster
+func (repo *LogArchiveRepository) UpsertLogArchiveCheckpoint(
+	ctx context.Context,
+	checkpoint *models.LogArchiveCheckpoint,
+) (*models.LogArchiveCheckpoint, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-upsert-log-archive-checkpoint")
+	defer span.End()
+
+	if checkpoint == nil {
+		return nil, telemetry.Error(ctx, span, nil, "log archive checkpoint is nil")
+	}
+
+	existing, err := repo.ReadLogArchiveCheckpoint(ctx, checkpoint.ClusterID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, telemetry.Error(ctx, span, err, "error reading log archive checkpoint")
+	}
+
+	if existing != nil {
+		checkpoint.ID = existing.ID
+		checkpoint.CreatedAt = existing.CreatedAt
+	}
+
+	if err := repo.db.Save(checkpoint).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error saving log archive checkpoint")
+	}
+
+	return checkpoint, nil
+}
+
+// ListLogArchiveCheckpoints returns the checkpoints of the clusters of a project
+func (repo *LogArchiveRepository) ListLogArchiveCheckpoints(ctx context.Context, projectID uint) ([]*models.LogArchiveCheckpoint, error) {
+	checkpoints := []*models.LogArchiveCheckpoint{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("cluster_id asc").Find(&checkpoints).Error; err != nil {
+		return nil, err
+	}
+
+	return checkpoints, nil
+}
+
+// UpsertLogArchive creates or updates an archived object, keyed by its object key
+func (repo *LogArchiveRepository) UpsertLogArchive(ctx context.Context, archive *models.LogArchive) (*models.LogArchive, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-upsert-log-archive")
+	defer span.End()
+
+	if archive == nil {
+		return nil, telemetry.Error(ctx, span, nil, "log archive is nil")
+	}
+
+	existing := &models.LogArchive{}
+
+	err := repo.db.Where("object_key = ?", archive.ObjectKey).First(existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, telemetry.Error(ctx, span, err, "error reading log archive")
+	}
+
+	if err == nil {
+		archive.ID = existing.ID
+		archive.CreatedAt = existing.CreatedAt
+	}
+
+	if err := repo.db.Save(archive).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error saving log archive")
+	}
+
+	return archive, nil
+}
+
+// ListLogArchives returns the archived objects of a project which match the filter, ordered by start time
+func (repo *LogArchiveRepository) ListLogArchives(
+	ctx context.Context,
+	projectID uint,
+	filter repository.LogArchiveFilter,
+) ([]*models.LogArchive, error) {
+	archives := []*models.LogArchive{}
+
+	query := repo.db.Where("project_id = ?", projectID)
+
+	if filter.ClusterID != 0 {
+		query = query.Where("cluster_id = ?", filter.ClusterID)
+	}
+	if filter.DeploymentTargetID != uuid.Nil {
+		query = query.Where("deployment_target_id = ?", filter.DeploymentTargetID)
+	}
+	if filter.AppName != "" {
+		query = query.Where("app_name = ?", filter.AppName)
+	}
+	if filter.ServiceName != "" {
+		query = query.Where("service_name = ?", filter.ServiceName)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("end_time > ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("start_time < ?", filter.Until)
+	}
+
+	if err := query.Order("start_time asc, id asc").Find(&archives).Error; err != nil {
+		return nil, err
+	}
+
+	return archives, nil
+}
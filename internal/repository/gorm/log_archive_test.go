@@ -0,0 +1,144 @@
+package gorm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+func TestLogArchiveSetting(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_log_archive_settings.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+
+	for _, enabled := range []bool{true, false, true} {
+		_, err := tester.repo.LogArchive().UpsertLogArchiveSetting(ctx, &models.LogArchiveSetting{
+			ProjectID: 1,
+			Enabled:   enabled,
+			Bucket:    "archive",
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	_, err := tester.repo.LogArchive().UpsertLogArchiveSetting(ctx, &models.LogArchiveSetting{
+		ProjectID: 2,
+		Bucket:    "archive",
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	settings, err := tester.repo.LogArchive().ListEnabledLogArchiveSettings(ctx)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(settings) != 1 || settings[0].ProjectID != 1 {
+		t.Errorf("expected only the setting of project 1 to be enabled, got %+v", settings)
+	}
+}
+
+func TestLogArchives(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_log_archives.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+	start := time.Date(2023, 1, 2, 15, 0, 0, 0, time.UTC)
+	deploymentTargetIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	for i, service := range []string{"api", "worker", "api"} {
+		for attempt := 0; attempt < 2; attempt++ {
+			_, err := tester.repo.LogArchive().UpsertLogArchive(ctx, &models.LogArchive{
+				ProjectID:          1,
+				ClusterID:          1,
+				DeploymentTargetID: deploymentTargetIDs[i%2],
+				AppName:            "web",
+				ServiceName:        service,
+				ObjectKey:          service + start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339),
+				StartTime:          start.Add(time.Duration(i) * time.Hour),
+				EndTime:            start.Add(time.Duration(i+1) * time.Hour),
+				LineCount:          attempt + 1,
+			})
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+		}
+	}
+
+	archives, err := tester.repo.LogArchive().ListLogArchives(ctx, 1, repository.LogArchiveFilter{})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(archives) != 3 {
+		t.Fatalf("expected archives to be upserted by object key, got %d archives", len(archives))
+	}
+	if archives[0].LineCount != 2 {
+		t.Errorf("expected archive to be overwritten, got line count %d", archives[0].LineCount)
+	}
+
+	archives, err = tester.repo.LogArchive().ListLogArchives(ctx, 1, repository.LogArchiveFilter{
+		ServiceName: "api",
+		Since:       start.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(archives) != 1 || !archives[0].StartTime.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("expected only the second api archive, got %+v", archives)
+	}
+
+	archives, err = tester.repo.LogArchive().ListLogArchives(ctx, 1, repository.LogArchiveFilter{
+		DeploymentTargetID: deploymentTargetIDs[1],
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(archives) != 1 || archives[0].ServiceName != "worker" || archives[0].DeploymentTargetID != deploymentTargetIDs[1] {
+		t.Errorf("expected only the worker archive to be in the second deployment target, got %+v", archives)
+	}
+
+	_, err = tester.repo.LogArchive().UpsertLogArchiveCheckpoint(ctx, &models.LogArchiveCheckpoint{
+		ProjectID:     1,
+		ClusterID:     1,
+		ArchivedUntil: start,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	_, err = tester.repo.LogArchive().UpsertLogArchiveCheckpoint(ctx, &models.LogArchiveCheckpoint{
+		ProjectID:     1,
+		ClusterID:     1,
+		ArchivedUntil: start.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	checkpoints, err := tester.repo.LogArchive().ListLogArchiveCheckpoints(ctx, 1)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(checkpoints) != 1 || !checkpoints[0].ArchivedUntil.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected a single updated checkpoint, got %+v", checkpoints)
+	}
+}
@@ -69,6 +69,17 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&models.AppTemplate{},
 		&models.GithubWebhook{},
 		&models.Datastore{},
+		&models.WorkerJobRun{},
+		&models.WorkerJobSchedule{},
+		&models.OPAPolicyBundle{},
+		&models.AuditLog{},
+		&models.SSOConnection{},
+		&models.SSODomain{},
+		&models.EncryptionKeyRotation{},
+		&models.EnvironmentGroupSetting{},
+		&models.LogArchiveSetting{},
+		&models.LogArchiveCheckpoint{},
+		&models.LogArchive{},
 		&ints.KubeIntegration{},
 		&ints.BasicIntegration{},
 		&ints.OIDCIntegration{},
@@ -85,6 +96,7 @@ func AutoMigrate(db *gorm.DB, debug bool) error {
 		&ints.GithubAppInstallation{},
 		&ints.GithubAppOAuthIntegration{},
 		&ints.SlackIntegration{},
+		&ints.NotifierIntegration{},
 		&models.Ipam{},
 		&models.AppEventWebhooks{},
 	)
@@ -0,0 +1,131 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// NotifierIntegrationRepository uses gorm.DB for querying the database
+type NotifierIntegrationRepository struct {
+	db  *gorm.DB
+	key *[32]byte
+}
+
+// NewNotifierIntegrationRepository returns a NotifierIntegrationRepository which uses
+// gorm.DB for querying the database. It accepts an encryption key to encrypt
+// sensitive data
+func NewNotifierIntegrationRepository(
+	db *gorm.DB,
+	key *[32]byte,
+) repository.NotifierIntegrationRepository {
+	return &NotifierIntegrationRepository{db, key}
+}
+
+// CreateNotifierIntegration creates a new notifier integration
+func (repo *NotifierIntegrationRepository) CreateNotifierIntegration(
+	notifierInt *ints.NotifierIntegration,
+) (*ints.NotifierIntegration, error) {
+	err := repo.EncryptNotifierIntegrationData(notifierInt, repo.key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.Create(notifierInt).Error; err != nil {
+		return nil, err
+	}
+
+	err = repo.DecryptNotifierIntegrationData(notifierInt, repo.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifierInt, nil
+}
+
+// ListNotifierIntegrationsByProjectID finds all notifier integrations
+// for a given project id
+func (repo *NotifierIntegrationRepository) ListNotifierIntegrationsByProjectID(
+	projectID uint,
+) ([]*ints.NotifierIntegration, error) {
+	notifierInts := []*ints.NotifierIntegration{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("id asc").Find(&notifierInts).Error; err != nil {
+		return nil, err
+	}
+
+	for _, notifierInt := range notifierInts {
+		if err := repo.DecryptNotifierIntegrationData(notifierInt, repo.key); err != nil {
+			return nil, err
+		}
+	}
+
+	return notifierInts, nil
+}
+
+// DeleteNotifierIntegration deletes a notifier integration by ID
+func (repo *NotifierIntegrationRepository) DeleteNotifierIntegration(
+	integrationID uint,
+) error {
+	if err := repo.db.Where("id = ?", integrationID).Delete(&ints.NotifierIntegration{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EncryptNotifierIntegrationData will encrypt the notifier integration data before
+// writing to the DB
+func (repo *NotifierIntegrationRepository) EncryptNotifierIntegrationData(
+	notifierInt *ints.NotifierIntegration,
+	key *[32]byte,
+) error {
+	if len(notifierInt.URL) > 0 {
+		cipherData, err := encryption.Encrypt(notifierInt.URL, key)
+		if err != nil {
+			return err
+		}
+
+		notifierInt.URL = cipherData
+	}
+
+	if len(notifierInt.Secret) > 0 {
+		cipherData, err := encryption.Encrypt(notifierInt.Secret, key)
+		if err != nil {
+			return err
+		}
+
+		notifierInt.Secret = cipherData
+	}
+
+	return nil
+}
+
+// DecryptNotifierIntegrationData will decrypt the notifier integration data before
+// returning it from the DB
+func (repo *NotifierIntegrationRepository) DecryptNotifierIntegrationData(
+	notifierInt *ints.NotifierIntegration,
+	key *[32]byte,
+) error {
+	if len(notifierInt.URL) > 0 {
+		plaintext, err := encryption.Decrypt(notifierInt.URL, key)
+		if err != nil {
+			return err
+		}
+
+		notifierInt.URL = plaintext
+	}
+
+	if len(notifierInt.Secret) > 0 {
+		plaintext, err := encryption.Decrypt(notifierInt.Secret, key)
+		if err != nil {
+			return err
+		}
+
+		notifierInt.Secret = plaintext
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// OPAPolicyBundleRepository uses gorm.DB for querying the database
+type OPAPolicyBundleRepository struct {
+	db *gorm.DB
+}
+
+// NewOPAPolicyBundleRepository returns an OPAPolicyBundleRepository which uses
+// gorm.DB for querying the database
+func NewOPAPolicyBundleRepository(db *gorm.DB) repository.OPAPolicyBundleRepository {
+	return &OPAPolicyBundleRepository{db}
+}
+
+// CreateOPAPolicyBundle creates a new policy bundle
+func (repo *OPAPolicyBundleRepository) CreateOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) (*models.OPAPolicyBundle, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-opa-policy-bundle")
+	defer span.End()
+
+	if bundle == nil {
+		return nil, telemetry.Error(ctx, span, nil, "policy bundle is nil")
+	}
+
+	if err := repo.db.Create(bundle).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating policy bundle")
+	}
+
+	return bundle, nil
+}
+
+// ReadOPAPolicyBundle reads a policy bundle by its id
+func (repo *OPAPolicyBundleRepository) ReadOPAPolicyBundle(ctx context.Context, projectID, id uint) (*models.OPAPolicyBundle, error) {
+	bundle := &models.OPAPolicyBundle{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, id).First(bundle).Error; err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// ReadOPAPolicyBundleByName reads a policy bundle by its name
+func (repo *OPAPolicyBundleRepository) ReadOPAPolicyBundleByName(ctx context.Context, projectID uint, name string) (*models.OPAPolicyBundle, error) {
+	bundle := &models.OPAPolicyBundle{}
+
+	if err := repo.db.Where("project_id = ? AND name = ?", projectID, name).First(bundle).Error; err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// ListOPAPolicyBundlesByProjectID lists all policy bundles in a project
+func (repo *OPAPolicyBundleRepository) ListOPAPolicyBundlesByProjectID(ctx context.Context, projectID uint) ([]*models.OPAPolicyBundle, error) {
+	bundles := []*models.OPAPolicyBundle{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("name ASC").Find(&bundles).Error; err != nil {
+		return nil, err
+	}
+
+	return bundles, nil
+}
+
+// UpdateOPAPolicyBundle updates a policy bundle
+func (repo *OPAPolicyBundleRepository) UpdateOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) (*models.OPAPolicyBundle, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-opa-policy-bundle")
+	defer span.End()
+
+	if bundle == nil {
+		return nil, telemetry.Error(ctx, span, nil, "policy bundle is nil")
+	}
+
+	if err := repo.db.Save(bundle).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating policy bundle")
+	}
+
+	return bundle, nil
+}
+
+// DeleteOPAPolicyBundle deletes a policy bundle
+func (repo *OPAPolicyBundleRepository) DeleteOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-delete-opa-policy-bundle")
+	defer span.End()
+
+	if bundle == nil {
+		return telemetry.Error(ctx, span, nil, "policy bundle is nil")
+	}
+
+	if err := repo.db.Delete(bundle).Error; err != nil {
+		return telemetry.Error(ctx, span, err, "error deleting policy bundle")
+	}
+
+	return nil
+}
@@ -33,6 +33,7 @@ type GormRepository struct {
 	githubAppInstallation     repository.GithubAppInstallationRepository
 	githubAppOAuthIntegration repository.GithubAppOAuthIntegrationRepository
 	slackIntegration          repository.SlackIntegrationRepository
+	notifierIntegration       repository.NotifierIntegrationRepository
 	appEventWebhook           repository.AppEventWebhookRepository
 	gitlabIntegration         repository.GitlabIntegrationRepository
 	gitlabAppOAuthIntegration repository.GitlabAppOAuthIntegrationRepository
@@ -62,6 +63,14 @@ type GormRepository struct {
 	datastore                 repository.DatastoreRepository
 	appInstance               repository.AppInstanceRepository
 	ipam                      repository.IpamRepository
+	workerJobRun              repository.WorkerJobRunRepository
+	workerJobSchedule         repository.WorkerJobScheduleRepository
+	opaPolicyBundle           repository.OPAPolicyBundleRepository
+	auditLog                  repository.AuditLogRepository
+	ssoConnection             repository.SSOConnectionRepository
+	encryptionKeyRotation     repository.EncryptionKeyRotationRepository
+	environmentGroupSetting   repository.EnvironmentGroupSettingRepository
+	logArchive                repository.LogArchiveRepository
 }
 
 func (t *GormRepository) User() repository.UserRepository {
@@ -168,6 +177,11 @@ func (t *GormRepository) SlackIntegration() repository.SlackIntegrationRepositor
 	return t.slackIntegration
 }
 
+// NotifierIntegration returns the NotifierIntegrationRepository interface implemented by gorm
+func (t *GormRepository) NotifierIntegration() repository.NotifierIntegrationRepository {
+	return t.notifierIntegration
+}
+
 // AppEventWebhook returns the AppEventWebhookRepository interface implemented by gorm
 func (t *GormRepository) AppEventWebhook() repository.AppEventWebhookRepository {
 	return t.appEventWebhook
@@ -293,6 +307,31 @@ func (t *GormRepository) Ipam() repository.IpamRepository {
 	return t.ipam
 }
 
+// WorkerJobRun returns the WorkerJobRunRepository interface implemented by gorm
+func (t *GormRepository) WorkerJobRun() repository.WorkerJobRunRepository {
+	return t.workerJobRun
+}
+
+// WorkerJobSchedule returns the WorkerJobScheduleRepository interface implemented by gorm
+func (t *GormRepository) WorkerJobSchedule() repository.WorkerJobScheduleRepository {
+	return t.workerJobSchedule
+}
+
+// OPAPolicyBundle returns the OPAPolicyBundleRepository interface implemented by gorm
+func (t *GormRepository) OPAPolicyBundle() repository.OPAPolicyBundleRepository {
+	return t.opaPolicyBundle
+}
+
+// AuditLog returns the AuditLogRepository interface implemented by gorm
+func (t *GormRepository) AuditLog() repository.AuditLogRepository {
+	return t.auditLog
+}
+
+// SSOConnection returns the SSOConnectionRepository interface implemented by gorm
+func (t *GormRepository) SSOConnection() repository.SSOConnectionRepository {
+	return t.ssoConnection
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.CredentialStorage) repository.Repository {
@@ -323,6 +362,7 @@ func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.Creden
 		githubAppInstallation:     NewGithubAppInstallationRepository(db),
 		githubAppOAuthIntegration: NewGithubAppOAuthIntegrationRepository(db),
 		slackIntegration:          NewSlackIntegrationRepository(db, key),
+		notifierIntegration:       NewNotifierIntegrationRepository(db, key),
 		gitlabIntegration:         NewGitlabIntegrationRepository(db, key, storageBackend),
 		gitlabAppOAuthIntegration: NewGitlabAppOAuthIntegrationRepository(db, key, storageBackend),
 		notificationConfig:        NewNotificationConfigRepository(db),
@@ -352,5 +392,28 @@ func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.Creden
 		appInstance:               NewAppInstanceRepository(db),
 		ipam:                      NewIpamRepository(db),
 		appEventWebhook:           NewAppEventWebhookRepository(db),
+		workerJobRun:              NewWorkerJobRunRepository(db),
+		workerJobSchedule:         NewWorkerJobScheduleRepository(db),
+		opaPolicyBundle:           NewOPAPolicyBundleRepository(db),
+		auditLog:                  NewAuditLogRepository(db),
+		ssoConnection:             NewSSOConnectionRepository(db, key),
+		encryptionKeyRotation:     NewEncryptionKeyRotationRepository(db),
+		environmentGroupSetting:   NewEnvironmentGroupSettingRepository(db),
+		logArchive:                NewLogArchiveRepository(db),
 	}
 }
+
+// EncryptionKeyRotation returns the EncryptionKeyRotationRepository interface implemented by gorm
+func (t *GormRepository) EncryptionKeyRotation() repository.EncryptionKeyRotationRepository {
+	return t.encryptionKeyRotation
+}
+
+// EnvironmentGroupSetting returns the EnvironmentGroupSettingRepository interface implemented by gorm
+func (t *GormRepository) EnvironmentGroupSetting() repository.EnvironmentGroupSettingRepository {
+	return t.environmentGroupSetting
+}
+
+// LogArchive returns the LogArchiveRepository interface implemented by gorm
+func (t *GormRepository) LogArchive() repository.LogArchiveRepository {
+	return t.logArchive
+}
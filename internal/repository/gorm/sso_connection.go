@@ -0,0 +1,161 @@
+package gorm
+
+import (
+	"strings"
+
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// SSOConnectionRepository uses gorm.DB for querying the database
+type SSOConnectionRepository struct {
+	db  *gorm.DB
+	key *[32]byte
+}
+
+// NewSSOConnectionRepository returns a SSOConnectionRepository which uses
+// gorm.DB for querying the database. It accepts an encryption key to encrypt
+// sensitive data
+func NewSSOConnectionRepository(db *gorm.DB, key *[32]byte) repository.SSOConnectionRepository {
+	return &SSOConnectionRepository{db, key}
+}
+
+// CreateSSOConnection creates a new sso connection
+func (repo *SSOConnectionRepository) CreateSSOConnection(conn *models.SSOConnection) (*models.SSOConnection, error) {
+	if err := repo.EncryptSSOConnectionData(conn, repo.key); err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.Create(conn).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptSSOConnectionData(conn, repo.key); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// ReadSSOConnection finds an sso connection of a project by its id
+func (repo *SSOConnectionRepository) ReadSSOConnection(projectID, connID uint) (*models.SSOConnection, error) {
+	conn := &models.SSOConnection{}
+
+	if err := repo.db.Preload("Domains").Where("project_id = ? AND id = ?", projectID, connID).First(conn).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptSSOConnectionData(conn, repo.key); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// ReadSSOConnectionByUniqueID finds an sso connection by the unique id used in its login urls
+func (repo *SSOConnectionRepository) ReadSSOConnectionByUniqueID(uniqueID string) (*models.SSOConnection, error) {
+	conn := &models.SSOConnection{}
+
+	if err := repo.db.Preload("Domains").Where("unique_id = ?", uniqueID).First(conn).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptSSOConnectionData(conn, repo.key); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// ReadSSOConnectionByDomain finds the sso connection which has verified the domain, which users with an email
+// in the domain log in through
+func (repo *SSOConnectionRepository) ReadSSOConnectionByDomain(domain string) (*models.SSOConnection, error) {
+	ssoDomain := &models.SSODomain{}
+
+	if err := repo.db.Where("domain = ? AND verified_at IS NOT NULL", strings.ToLower(domain)).First(ssoDomain).Error; err != nil {
+		return nil, err
+	}
+
+	conn := &models.SSOConnection{}
+
+	if err := repo.db.Preload("Domains").Where("id = ?", ssoDomain.SSOConnectionID).First(conn).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptSSOConnectionData(conn, repo.key); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// ListSSOConnectionsByProjectID finds all sso connections for a given project id
+func (repo *SSOConnectionRepository) ListSSOConnectionsByProjectID(projectID uint) ([]*models.SSOConnection, error) {
+	conns := []*models.SSOConnection{}
+
+	if err := repo.db.Preload("Domains").Where("project_id = ?", projectID).Order("id asc").Find(&conns).Error; err != nil {
+		return nil, err
+	}
+
+	for _, conn := range conns {
+		if err := repo.DecryptSSOConnectionData(conn, repo.key); err != nil {
+			return nil, err
+		}
+	}
+
+	return conns, nil
+}
+
+// UpdateSSODomain modifies an existing sso domain in the database. Verifying a domain which another connection
+// has already verified fails.
+func (repo *SSOConnectionRepository) UpdateSSODomain(domain *models.SSODomain) (*models.SSODomain, error) {
+	if err := repo.db.Save(domain).Error; err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+// DeleteSSOConnection deletes an sso connection and its domains. The domains are deleted permanently, so that
+// they can be claimed again.
+func (repo *SSOConnectionRepository) DeleteSSOConnection(conn *models.SSOConnection) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("sso_connection_id = ?", conn.ID).Delete(&models.SSODomain{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(conn).Error
+	})
+}
+
+// EncryptSSOConnectionData will encrypt the sso connection data before
+// writing to the DB
+func (repo *SSOConnectionRepository) EncryptSSOConnectionData(conn *models.SSOConnection, key *[32]byte) error {
+	if len(conn.ClientSecret) > 0 {
+		cipherData, err := encryption.Encrypt(conn.ClientSecret, key)
+		if err != nil {
+			return err
+		}
+
+		conn.ClientSecret = cipherData
+	}
+
+	return nil
+}
+
+// DecryptSSOConnectionData will decrypt the sso connection data before
+// returning it from the DB
+func (repo *SSOConnectionRepository) DecryptSSOConnectionData(conn *models.SSOConnection, key *[32]byte) error {
+	if len(conn.ClientSecret) > 0 {
+		plaintext, err := encryption.Decrypt(conn.ClientSecret, key)
+		if err != nil {
+			return err
+		}
+
+		conn.ClientSecret = plaintext
+	}
+
+	return nil
+}
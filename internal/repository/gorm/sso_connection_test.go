@@ -0,0 +1,120 @@
+package gorm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestReadSSOConnectionByDomain(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_sso_connections.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	repo := tester.repo.SSOConnection()
+
+	conn, err := repo.CreateSSOConnection(&models.SSOConnection{
+		ProjectID:    1,
+		UniqueID:     "okta",
+		ClientSecret: []byte("secret"),
+		Domains: []models.SSODomain{
+			{Domain: "ba.com", VerificationToken: "token-1"},
+			{Domain: "example.com", VerificationToken: "token-2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// unverified domains are not enforced
+	if _, err := repo.ReadSSOConnectionByDomain("ba.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected unverified domain not to be found, got %v", err)
+	}
+
+	verifiedAt := time.Now()
+	conn.Domains[0].VerifiedAt = &verifiedAt
+
+	if _, err := repo.UpdateSSODomain(&conn.Domains[0]); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	found, err := repo.ReadSSOConnectionByDomain("BA.com")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if found.ID != conn.ID || string(found.ClientSecret) != "secret" || len(found.Domains) != 2 {
+		t.Errorf("expected connection %d with its domains and decrypted secret, got %+v", conn.ID, found)
+	}
+
+	// domains are matched exactly
+	for _, domain := range []string{"a.com", "b", "example.com"} {
+		if _, err := repo.ReadSSOConnectionByDomain(domain); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected domain %s not to be found, got %v", domain, err)
+		}
+	}
+}
+
+func TestSSODomainVerifiedOnce(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_sso_domains.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	repo := tester.repo.SSOConnection()
+
+	var conns []*models.SSOConnection
+
+	// any number of connections can claim a domain before it is verified
+	for _, uniqueID := range []string{"okta", "other"} {
+		conn, err := repo.CreateSSOConnection(&models.SSOConnection{
+			ProjectID: 1,
+			UniqueID:  uniqueID,
+			Domains:   []models.SSODomain{{Domain: "example.com", VerificationToken: uniqueID}},
+		})
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		conns = append(conns, conn)
+	}
+
+	verifiedAt := time.Now()
+
+	conns[0].Domains[0].VerifiedAt = &verifiedAt
+	if _, err := repo.UpdateSSODomain(&conns[0].Domains[0]); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	conns[1].Domains[0].VerifiedAt = &verifiedAt
+	if _, err := repo.UpdateSSODomain(&conns[1].Domains[0]); err == nil {
+		t.Fatalf("expected a domain to only be verified by one connection")
+	}
+
+	// deleting the connection frees its domains
+	if err := repo.DeleteSSOConnection(conns[0]); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := repo.UpdateSSODomain(&conns[1].Domains[0]); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	found, err := repo.ReadSSOConnectionByDomain("example.com")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if found.ID != conns[1].ID {
+		t.Errorf("expected connection %d to have verified the domain, got %d", conns[1].ID, found.ID)
+	}
+}
@@ -73,6 +73,16 @@ func (repo *UserRepository) ReadUserByGoogleUserID(id string) (*models.User, err
 	return user, nil
 }
 
+// ReadUserBySSOSubject finds a single user based on the sso connection they log in through and their
+// subject at its identity provider
+func (repo *UserRepository) ReadUserBySSOSubject(connection, subject string) (*models.User, error) {
+	user := &models.User{}
+	if err := repo.db.Where("sso_connection = ? AND sso_subject = ?", connection, subject).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 // UpdateUser modifies an existing User in the database
 func (repo *UserRepository) UpdateUser(user *models.User) (*models.User, error) {
 	if err := repo.db.Save(user).Error; err != nil {
@@ -0,0 +1,209 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// workerJobRunClaimAttempts is the number of candidate runs a worker tries to claim before giving up, in case other
+// workers claim the same runs concurrently
+const workerJobRunClaimAttempts = 5
+
+// WorkerJobRunRepository uses gorm.DB for querying the database
+type WorkerJobRunRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkerJobRunRepository returns a WorkerJobRunRepository which uses
+// gorm.DB for querying the database
+func NewWorkerJobRunRepository(db *gorm.DB) repository.WorkerJobRunRepository {
+	return &WorkerJobRunRepository{db}
+}
+
+// CreateWorkerJobRun creates a new worker job run
+func (repo *WorkerJobRunRepository) CreateWorkerJobRun(ctx context.Context, run *models.WorkerJobRun) (*models.WorkerJobRun, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-worker-job-run")
+	defer span.End()
+
+	if run == nil {
+		return nil, telemetry.Error(ctx, span, nil, "worker job run is nil")
+	}
+
+	if err := repo.db.Create(run).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating worker job run")
+	}
+
+	return run, nil
+}
+
+// ReadWorkerJobRun reads a worker job run by its id
+func (repo *WorkerJobRunRepository) ReadWorkerJobRun(ctx context.Context, id uint) (*models.WorkerJobRun, error) {
+	run := &models.WorkerJobRun{}
+
+	if err := repo.db.Where("id = ?", id).First(run).Error; err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// UpdateWorkerJobRun updates a worker job run
+func (repo *WorkerJobRunRepository) UpdateWorkerJobRun(ctx context.Context, run *models.WorkerJobRun) (*models.WorkerJobRun, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-worker-job-run")
+	defer span.End()
+
+	if run == nil {
+		return nil, telemetry.Error(ctx, span, nil, "worker job run is nil")
+	}
+
+	if err := repo.db.Save(run).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating worker job run")
+	}
+
+	return run, nil
+}
+
+// ClaimWorkerJobRun claims the oldest run which is due to be attempted, marking it as running and locked by the given worker until lockedUntil.
+// Runs which are still marked as running after their lock has expired are considered abandoned and can be claimed again
+// if they have attempts left. Abandoned runs without attempts left are moved to the dead-letter list.
+// gorm.ErrRecordNotFound is returned if no run is due.
+func (repo *WorkerJobRunRepository) ClaimWorkerJobRun(ctx context.Context, workerID string, now time.Time, lockedUntil time.Time) (*models.WorkerJobRun, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-claim-worker-job-run")
+	defer span.End()
+
+	// abandoned runs which used up their attempts are not run again, as their job is not necessarily safe to retry
+	err := repo.db.Model(&models.WorkerJobRun{}).
+		Where("status = ? AND locked_until < ? AND attempts >= max_attempts", models.WorkerJobRunStatus_Running, now).
+		Updates(map[string]interface{}{
+			"status":       models.WorkerJobRunStatus_Dead,
+			"last_error":   "worker lock expired while the run was running",
+			"completed_at": now,
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error
+	if err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error moving abandoned worker job runs to the dead-letter list")
+	}
+
+	// runs which share a concurrency key are claimed one at a time, oldest first
+	claimable := "((status IN ? AND next_run_at <= ?) OR (status = ? AND locked_until < ? AND attempts < max_attempts)) AND (concurrency_key = '' OR NOT EXISTS (" +
+		"SELECT 1 FROM worker_job_runs AS earlier WHERE earlier.concurrency_key = worker_job_runs.concurrency_key AND earlier.id < worker_job_runs.id " +
+		"AND earlier.status IN ? AND earlier.deleted_at IS NULL))"
+	claimableArgs := []interface{}{
+		[]models.WorkerJobRunStatus{models.WorkerJobRunStatus_Queued, models.WorkerJobRunStatus_Retrying},
+		now,
+		models.WorkerJobRunStatus_Running,
+		now,
+		[]models.WorkerJobRunStatus{models.WorkerJobRunStatus_Queued, models.WorkerJobRunStatus_Retrying, models.WorkerJobRunStatus_Running},
+	}
+
+	for i := 0; i < workerJobRunClaimAttempts; i++ {
+		candidate := &models.WorkerJobRun{}
+
+		if err := repo.db.Where(claimable, claimableArgs...).Order("next_run_at ASC").First(candidate).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			return nil, telemetry.Error(ctx, span, err, "error finding claimable worker job run")
+		}
+
+		// the claimable condition is repeated in the update so that only one worker can claim the run
+		res := repo.db.Model(&models.WorkerJobRun{}).Where("id = ?", candidate.ID).Where(claimable, claimableArgs...).Updates(map[string]interface{}{
+			"status":       models.WorkerJobRunStatus_Running,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"started_at":   now,
+			"locked_by":    workerID,
+			"locked_until": lockedUntil,
+		})
+		if res.Error != nil {
+			return nil, telemetry.Error(ctx, span, res.Error, "error claiming worker job run")
+		}
+
+		if res.RowsAffected == 1 {
+			return repo.ReadWorkerJobRun(ctx, candidate.ID)
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ExtendWorkerJobRunLock extends the lock of a running run held by the given worker until lockedUntil.
+// gorm.ErrRecordNotFound is returned if the run is no longer running or is locked by another worker.
+func (repo *WorkerJobRunRepository) ExtendWorkerJobRunLock(ctx context.Context, id uint, workerID string, lockedUntil time.Time) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-extend-worker-job-run-lock")
+	defer span.End()
+
+	res := repo.db.Model(&models.WorkerJobRun{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, models.WorkerJobRunStatus_Running, workerID).
+		Update("locked_until", lockedUntil)
+	if res.Error != nil {
+		return telemetry.Error(ctx, span, res.Error, "error extending worker job run lock")
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// FinishWorkerJobRun records the outcome of a running run claimed by the given worker.
+// gorm.ErrRecordNotFound is returned if the run is no longer running or is locked by another worker, so that a worker
+// which lost its lock cannot overwrite the run of its new claimant.
+func (repo *WorkerJobRunRepository) FinishWorkerJobRun(ctx context.Context, run *models.WorkerJobRun, workerID string) error {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-finish-worker-job-run")
+	defer span.End()
+
+	if run == nil {
+		return telemetry.Error(ctx, span, nil, "worker job run is nil")
+	}
+
+	res := repo.db.Model(&models.WorkerJobRun{}).
+		Where("id = ? AND status = ? AND locked_by = ?", run.ID, models.WorkerJobRunStatus_Running, workerID).
+		Updates(map[string]interface{}{
+			"status":       run.Status,
+			"last_error":   run.LastError,
+			"result":       run.Result,
+			"next_run_at":  run.NextRunAt,
+			"completed_at": run.CompletedAt,
+			"locked_by":    run.LockedBy,
+			"locked_until": run.LockedUntil,
+		})
+	if res.Error != nil {
+		return telemetry.Error(ctx, span, res.Error, "error finishing worker job run")
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ListWorkerJobRunsByJobID lists the most recent runs of a job, newest first
+func (repo *WorkerJobRunRepository) ListWorkerJobRunsByJobID(ctx context.Context, jobID string, limit int) ([]*models.WorkerJobRun, error) {
+	runs := []*models.WorkerJobRun{}
+
+	if err := repo.db.Where("job_id = ?", jobID).Order("id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// ListWorkerJobRunsByStatus lists the most recent runs with the given status, newest first
+func (repo *WorkerJobRunRepository) ListWorkerJobRunsByStatus(ctx context.Context, status models.WorkerJobRunStatus, limit int) ([]*models.WorkerJobRun, error) {
+	runs := []*models.WorkerJobRun{}
+
+	if err := repo.db.Where("status = ?", status).Order("id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,239 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestClaimWorkerJobRun(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_worker_job_runs.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	runs := []*models.WorkerJobRun{
+		{JobID: "recommender", Status: models.WorkerJobRunStatus_Retrying, MaxAttempts: 3, NextRunAt: now.Add(time.Hour)},
+		{JobID: "recommender", Status: models.WorkerJobRunStatus_Queued, MaxAttempts: 3, NextRunAt: now.Add(-time.Minute)},
+		{JobID: "recommender", Status: models.WorkerJobRunStatus_Dead, MaxAttempts: 3, NextRunAt: now.Add(-time.Hour)},
+	}
+
+	for _, run := range runs {
+		if _, err := tester.repo.WorkerJobRun().CreateWorkerJobRun(ctx, run); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	claimed, err := tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-1", now, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if claimed.ID != runs[1].ID {
+		t.Errorf("expected to claim run %d, claimed %d", runs[1].ID, claimed.ID)
+	}
+	if claimed.Status != models.WorkerJobRunStatus_Running || claimed.Attempts != 1 || claimed.LockedBy != "worker-1" {
+		t.Errorf("expected claimed run to be running with 1 attempt locked by worker-1, got %s with %d attempts locked by %s", claimed.Status, claimed.Attempts, claimed.LockedBy)
+	}
+
+	// nothing else is due, and the claimed run is still locked
+	_, err = tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-2", now, now.Add(time.Minute))
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	// once the lock expires, the abandoned run can be claimed again
+	later := now.Add(2 * time.Minute)
+	reclaimed, err := tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-2", later, later.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if reclaimed.ID != runs[1].ID || reclaimed.Attempts != 2 || reclaimed.LockedBy != "worker-2" {
+		t.Errorf("expected run %d to be reclaimed by worker-2 on its second attempt, got run %d on attempt %d locked by %s", runs[1].ID, reclaimed.ID, reclaimed.Attempts, reclaimed.LockedBy)
+	}
+
+	// only the worker holding the lock can extend it
+	err = tester.repo.WorkerJobRun().ExtendWorkerJobRunLock(ctx, runs[1].ID, "worker-1", later.Add(time.Hour))
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found when extending a lock held by another worker, got %v", err)
+	}
+
+	if err := tester.repo.WorkerJobRun().ExtendWorkerJobRunLock(ctx, runs[1].ID, "worker-2", later.Add(time.Hour)); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	_, err = tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-1", later.Add(2*time.Minute), later.Add(3*time.Minute))
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected extended lock to prevent the run from being reclaimed, got %v", err)
+	}
+
+	dead, err := tester.repo.WorkerJobRun().ListWorkerJobRunsByStatus(ctx, models.WorkerJobRunStatus_Dead, 10)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(dead) != 1 || dead[0].ID != runs[2].ID {
+		t.Errorf("expected dead-letter list to contain run %d", runs[2].ID)
+	}
+}
+
+func TestClaimWorkerJobRunConcurrencyKey(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_worker_job_run_concurrency_keys.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	runs := []*models.WorkerJobRun{
+		{JobID: "env-group-rollout", ConcurrencyKey: "env-group-rollout-1-app", Status: models.WorkerJobRunStatus_Retrying, MaxAttempts: 3, NextRunAt: now.Add(time.Hour)},
+		{JobID: "env-group-rollout", ConcurrencyKey: "env-group-rollout-1-app", Status: models.WorkerJobRunStatus_Queued, MaxAttempts: 1, NextRunAt: now.Add(-time.Minute)},
+		{JobID: "env-group-rollout", ConcurrencyKey: "env-group-rollout-1-db", Status: models.WorkerJobRunStatus_Queued, MaxAttempts: 1, NextRunAt: now.Add(-time.Minute)},
+	}
+
+	for _, run := range runs {
+		if _, err := tester.repo.WorkerJobRun().CreateWorkerJobRun(ctx, run); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	// the queued run of the app env group waits for the earlier retrying run
+	claimed, err := tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-1", now, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if claimed.ID != runs[2].ID {
+		t.Errorf("expected to claim run %d, claimed %d", runs[2].ID, claimed.ID)
+	}
+
+	_, err = tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-1", now, now.Add(time.Minute))
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	// once the earlier run is dead, the next run of the env group can be claimed
+	runs[0].Status = models.WorkerJobRunStatus_Dead
+	if _, err := tester.repo.WorkerJobRun().UpdateWorkerJobRun(ctx, runs[0]); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	claimed, err = tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-1", now, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if claimed.ID != runs[1].ID {
+		t.Errorf("expected to claim run %d, claimed %d", runs[1].ID, claimed.ID)
+	}
+}
+
+func TestClaimWorkerJobRunWithoutAttemptsLeft(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_worker_job_run_attempts.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	run := &models.WorkerJobRun{JobID: "env-group-rollout", Status: models.WorkerJobRunStatus_Queued, MaxAttempts: 1, NextRunAt: now.Add(-time.Minute)}
+	if _, err := tester.repo.WorkerJobRun().CreateWorkerJobRun(ctx, run); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	claimed, err := tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-1", now, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// the run only had one attempt, so it is not run again once its worker's lock expires
+	later := now.Add(2 * time.Minute)
+	_, err = tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-2", later, later.Add(time.Minute))
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	stored, err := tester.repo.WorkerJobRun().ReadWorkerJobRun(ctx, claimed.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if stored.Status != models.WorkerJobRunStatus_Dead || stored.Attempts != 1 {
+		t.Errorf("expected abandoned run to be dead after 1 attempt, got %s after %d attempts", stored.Status, stored.Attempts)
+	}
+
+	// the worker which lost the lock cannot record an outcome
+	claimed.Status = models.WorkerJobRunStatus_Succeeded
+	err = tester.repo.WorkerJobRun().FinishWorkerJobRun(ctx, claimed, "worker-1")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found when finishing a run which is no longer running, got %v", err)
+	}
+}
+
+func TestFinishWorkerJobRun(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_worker_job_run_finish.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	run := &models.WorkerJobRun{JobID: "recommender", Status: models.WorkerJobRunStatus_Queued, MaxAttempts: 3, NextRunAt: now.Add(-time.Minute)}
+	if _, err := tester.repo.WorkerJobRun().CreateWorkerJobRun(ctx, run); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-1", now, now.Add(time.Minute)); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// the lock expires and another worker claims the run
+	later := now.Add(2 * time.Minute)
+	claimed, err := tester.repo.WorkerJobRun().ClaimWorkerJobRun(ctx, "worker-2", later, later.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	stale := *claimed
+	stale.Status = models.WorkerJobRunStatus_Retrying
+	stale.LastError = "stale failure"
+
+	err = tester.repo.WorkerJobRun().FinishWorkerJobRun(ctx, &stale, "worker-1")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected record not found when finishing a run locked by another worker, got %v", err)
+	}
+
+	claimed.Status = models.WorkerJobRunStatus_Succeeded
+	claimed.LockedBy = ""
+	claimed.LockedUntil = nil
+
+	if err := tester.repo.WorkerJobRun().FinishWorkerJobRun(ctx, claimed, "worker-2"); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	stored, err := tester.repo.WorkerJobRun().ReadWorkerJobRun(ctx, claimed.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if stored.Status != models.WorkerJobRunStatus_Succeeded || stored.LastError != "" || stored.LockedBy != "" {
+		t.Errorf("expected run to be recorded as succeeded by worker-2, got %s with error %q locked by %q", stored.Status, stored.LastError, stored.LockedBy)
+	}
+}
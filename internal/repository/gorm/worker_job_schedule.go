@@ -0,0 +1,83 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/telemetry"
+	"gorm.io/gorm"
+)
+
+// WorkerJobScheduleRepository uses gorm.DB for querying the database
+type WorkerJobScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkerJobScheduleRepository returns a WorkerJobScheduleRepository which uses
+// gorm.DB for querying the database
+func NewWorkerJobScheduleRepository(db *gorm.DB) repository.WorkerJobScheduleRepository {
+	return &WorkerJobScheduleRepository{db}
+}
+
+// CreateWorkerJobSchedule creates a new worker job schedule
+func (repo *WorkerJobScheduleRepository) CreateWorkerJobSchedule(ctx context.Context, schedule *models.WorkerJobSchedule) (*models.WorkerJobSchedule, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-create-worker-job-schedule")
+	defer span.End()
+
+	if schedule == nil {
+		return nil, telemetry.Error(ctx, span, nil, "worker job schedule is nil")
+	}
+
+	if err := repo.db.Create(schedule).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error creating worker job schedule")
+	}
+
+	return schedule, nil
+}
+
+// ReadWorkerJobSchedule reads the schedule of the given job
+func (repo *WorkerJobScheduleRepository) ReadWorkerJobSchedule(ctx context.Context, jobID string) (*models.WorkerJobSchedule, error) {
+	schedule := &models.WorkerJobSchedule{}
+
+	if err := repo.db.Where("job_id = ?", jobID).First(schedule).Error; err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// UpdateWorkerJobSchedule updates a worker job schedule
+func (repo *WorkerJobScheduleRepository) UpdateWorkerJobSchedule(ctx context.Context, schedule *models.WorkerJobSchedule) (*models.WorkerJobSchedule, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-update-worker-job-schedule")
+	defer span.End()
+
+	if schedule == nil {
+		return nil, telemetry.Error(ctx, span, nil, "worker job schedule is nil")
+	}
+
+	if err := repo.db.Save(schedule).Error; err != nil {
+		return nil, telemetry.Error(ctx, span, err, "error updating worker job schedule")
+	}
+
+	return schedule, nil
+}
+
+// ClaimWorkerJobSchedule moves a schedule which is due at the given time on to its next run. Only one caller can claim each
+// scheduled run, as the update only matches while the run is still due, so false is returned if the run is not due or was
+// already claimed by another replica.
+func (repo *WorkerJobScheduleRepository) ClaimWorkerJobSchedule(ctx context.Context, jobID string, now time.Time, nextRunAt time.Time) (bool, error) {
+	ctx, span := telemetry.NewSpan(ctx, "gorm-claim-worker-job-schedule")
+	defer span.End()
+
+	res := repo.db.Model(&models.WorkerJobSchedule{}).Where("job_id = ? AND next_run_at <= ?", jobID, now).Updates(map[string]interface{}{
+		"last_run_at": now,
+		"next_run_at": nextRunAt,
+	})
+	if res.Error != nil {
+		return false, telemetry.Error(ctx, span, res.Error, "error claiming worker job schedule")
+	}
+
+	return res.RowsAffected == 1, nil
+}
@@ -17,4 +17,8 @@ type InfraRepository interface {
 	ListOperations(infraID uint) ([]*models.Operation, error)
 	GetLatestOperation(infra *models.Infra) (*models.Operation, error)
 	UpdateOperation(repo *models.Operation) (*models.Operation, error)
+
+	// UpdateOperationStatus sets the status of an operation to toStatus only if its status is fromStatus,
+	// and returns whether the operation was updated
+	UpdateOperationStatus(operation *models.Operation, fromStatus, toStatus string) (bool, error)
 }
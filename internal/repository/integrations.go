@@ -53,6 +53,13 @@ type SlackIntegrationRepository interface {
 	DeleteSlackIntegration(integrationID uint) error
 }
 
+// NotifierIntegrationRepository represents the set of queries on a notifier integration
+type NotifierIntegrationRepository interface {
+	CreateNotifierIntegration(notifierInt *ints.NotifierIntegration) (*ints.NotifierIntegration, error)
+	ListNotifierIntegrationsByProjectID(projectID uint) ([]*ints.NotifierIntegration, error)
+	DeleteNotifierIntegration(integrationID uint) error
+}
+
 // AWSIntegrationRepository represents the set of queries on the AWS auth
 // mechanism
 type AWSIntegrationRepository interface {
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// LogArchiveFilter filters the archived logs of a project. Zero fields match all archives
+type LogArchiveFilter struct {
+	ClusterID          uint
+	DeploymentTargetID uuid.UUID
+	AppName            string
+	ServiceName        string
+
+	// Since and Until select the archives which overlap the time range
+	Since time.Time
+	Until time.Time
+}
+
+// LogArchiveRepository represents the set of queries on the log archive models
+type LogArchiveRepository interface {
+	// ReadLogArchiveSetting returns the log archive setting of a project, or gorm.ErrRecordNotFound if none was stored
+	ReadLogArchiveSetting(ctx context.Context, projectID uint) (*models.LogArchiveSetting, error)
+	// UpsertLogArchiveSetting creates or updates the log archive setting of a project
+	UpsertLogArchiveSetting(ctx context.Context, setting *models.LogArchiveSetting) (*models.LogArchiveSetting, error)
+	// ListEnabledLogArchiveSettings returns the log archive settings of every project which archives its logs
+	ListEnabledLogArchiveSettings(ctx context.Context) ([]*models.LogArchiveSetting, error)
+
+	// ReadLogArchiveCheckpoint returns the checkpoint of a cluster, or gorm.ErrRecordNotFound if its logs were never archived
+	ReadLogArchiveCheckpoint(ctx context.Context, clusterID uint) (*models.LogArchiveCheckpoint, error)
+	// UpsertLogArchiveCheckpoint creates or updates the checkpoint of a cluster
+	UpsertLogArchiveCheckpoint(ctx context.Context, checkpoint *models.LogArchiveCheckpoint) (*models.LogArchiveCheckpoint, error)
+	// ListLogArchiveCheckpoints returns the checkpoints of the clusters of a project
+	ListLogArchiveCheckpoints(ctx context.Context, projectID uint) ([]*models.LogArchiveCheckpoint, error)
+
+	// UpsertLogArchive creates or updates an archived object, keyed by its object key
+	UpsertLogArchive(ctx context.Context, archive *models.LogArchive) (*models.LogArchive, error)
+	// ListLogArchives returns the archived objects of a project which match the filter, ordered by start time
+	ListLogArchives(ctx context.Context, projectID uint, filter LogArchiveFilter) ([]*models.LogArchive, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// OPAPolicyBundleRepository represents the set of queries on the OPAPolicyBundle model
+type OPAPolicyBundleRepository interface {
+	CreateOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) (*models.OPAPolicyBundle, error)
+	ReadOPAPolicyBundle(ctx context.Context, projectID, id uint) (*models.OPAPolicyBundle, error)
+	ReadOPAPolicyBundleByName(ctx context.Context, projectID uint, name string) (*models.OPAPolicyBundle, error)
+	ListOPAPolicyBundlesByProjectID(ctx context.Context, projectID uint) ([]*models.OPAPolicyBundle, error)
+	UpdateOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) (*models.OPAPolicyBundle, error)
+	DeleteOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) error
+}
@@ -27,6 +27,7 @@ type Repository interface {
 	GithubAppInstallation() GithubAppInstallationRepository
 	GithubAppOAuthIntegration() GithubAppOAuthIntegrationRepository
 	SlackIntegration() SlackIntegrationRepository
+	NotifierIntegration() NotifierIntegrationRepository
 	AppEventWebhook() AppEventWebhookRepository
 	GitlabIntegration() GitlabIntegrationRepository
 	GitlabAppOAuthIntegration() GitlabAppOAuthIntegrationRepository
@@ -55,4 +56,12 @@ type Repository interface {
 	GithubWebhook() GithubWebhookRepository
 	Datastore() DatastoreRepository
 	AppInstance() AppInstanceRepository
+	WorkerJobRun() WorkerJobRunRepository
+	WorkerJobSchedule() WorkerJobScheduleRepository
+	OPAPolicyBundle() OPAPolicyBundleRepository
+	AuditLog() AuditLogRepository
+	SSOConnection() SSOConnectionRepository
+	EncryptionKeyRotation() EncryptionKeyRotationRepository
+	EnvironmentGroupSetting() EnvironmentGroupSettingRepository
+	LogArchive() LogArchiveRepository
 }
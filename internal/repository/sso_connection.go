@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// SSOConnectionRepository represents the set of queries on the sso connections of projects
+type SSOConnectionRepository interface {
+	CreateSSOConnection(conn *models.SSOConnection) (*models.SSOConnection, error)
+	ReadSSOConnection(projectID, connID uint) (*models.SSOConnection, error)
+	ReadSSOConnectionByUniqueID(uniqueID string) (*models.SSOConnection, error)
+	ReadSSOConnectionByDomain(domain string) (*models.SSOConnection, error)
+	ListSSOConnectionsByProjectID(projectID uint) ([]*models.SSOConnection, error)
+	UpdateSSODomain(domain *models.SSODomain) (*models.SSODomain, error)
+	DeleteSSOConnection(conn *models.SSOConnection) error
+}
@@ -1,32 +1,121 @@
 package test
 
 import (
+	"errors"
+	"time"
+
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
 )
 
+// APITokenRepository will return errors on queries if canQuery is false
+// and only stores tokens in-memory that are indexed by their array index + 1
 type APITokenRepository struct {
 	canQuery bool
+	tokens   []*models.APIToken
 }
 
 func NewAPITokenRepository(canQuery bool) repository.APITokenRepository {
-	return &APITokenRepository{canQuery}
+	return &APITokenRepository{canQuery, []*models.APIToken{}}
 }
 
+// CreateAPIToken appends a new token to the in-memory tokens array
 func (repo *APITokenRepository) CreateAPIToken(a *models.APIToken) (*models.APIToken, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.tokens = append(repo.tokens, a)
+	a.ID = uint(len(repo.tokens))
+
+	return a, nil
 }
 
+// ListAPITokensByProjectID lists the in-memory tokens of a project which are not revoked
 func (repo *APITokenRepository) ListAPITokensByProjectID(projectID uint) ([]*models.APIToken, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.APIToken, 0)
+
+	for _, token := range repo.tokens {
+		if token.ProjectID == projectID && !token.Revoked {
+			res = append(res, token)
+		}
+	}
+
+	return res, nil
 }
 
+// ReadAPIToken finds an in-memory token of a project by its unique id
 func (repo *APITokenRepository) ReadAPIToken(projectID uint, uid string) (*models.APIToken, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, token := range repo.tokens {
+		if token.ProjectID == projectID && token.UniqueID == uid {
+			return token, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
+// UpdateAPIToken replaces the in-memory token with the same id
 func (repo *APITokenRepository) UpdateAPIToken(
 	token *models.APIToken,
 ) (*models.APIToken, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(token.ID-1) >= len(repo.tokens) || repo.tokens[token.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.tokens[token.ID-1] = token
+
+	return token, nil
+}
+
+// UpdateAPITokenLastUsed stores the last used time and ip of the in-memory token with the same id
+func (repo *APITokenRepository) UpdateAPITokenLastUsed(token *models.APIToken) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(token.ID-1) >= len(repo.tokens) || repo.tokens[token.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.tokens[token.ID-1].LastUsedAt = token.LastUsedAt
+	repo.tokens[token.ID-1].LastUsedIP = token.LastUsedIP
+
+	return nil
+}
+
+// ListAPITokensExpiringBefore lists the in-memory tokens which expire before the given time, and whose
+// creators have not been notified of the expiry yet
+func (repo *APITokenRepository) ListAPITokensExpiringBefore(before time.Time) ([]*models.APIToken, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.APIToken, 0)
+	now := time.Now()
+
+	for _, token := range repo.tokens {
+		if token.Revoked || token.ExpiryNotifiedAt != nil || token.Expiry == nil {
+			continue
+		}
+
+		if token.Expiry.After(now) && !token.Expiry.After(before) {
+			res = append(res, token)
+		}
+	}
+
+	return res, nil
 }
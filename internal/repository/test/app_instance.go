@@ -4,21 +4,49 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
 )
 
 // AppInstanceRepository is a test repository that implements repository.AppInstanceRepository
 type AppInstanceRepository struct {
-	canQuery bool
+	canQuery     bool
+	appInstances []*models.AppInstance
 }
 
 // NewAppInstanceRepository returns the test AppInstanceRepository
-func NewAppInstanceRepository() repository.AppInstanceRepository {
-	return &AppInstanceRepository{canQuery: false}
+func NewAppInstanceRepository(canQuery bool) repository.AppInstanceRepository {
+	return &AppInstanceRepository{canQuery: canQuery}
 }
 
-// Get returns an app instance by its id
-func (repo *AppInstanceRepository) Get(ctx context.Context, id string) (*models.AppInstance, error) {
-	return nil, errors.New("cannot read database")
+// Get returns an app instance of a project by its id
+func (repo *AppInstanceRepository) Get(ctx context.Context, projectID uint, id string) (*models.AppInstance, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, appInstance := range repo.appInstances {
+		if appInstance.ProjectID == projectID && appInstance.ID.String() == id {
+			return appInstance, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Create creates an app instance
+func (repo *AppInstanceRepository) Create(ctx context.Context, appInstance *models.AppInstance) (*models.AppInstance, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if appInstance.ID == uuid.Nil {
+		appInstance.ID = uuid.New()
+	}
+
+	repo.appInstances = append(repo.appInstances, appInstance)
+
+	return appInstance, nil
 }
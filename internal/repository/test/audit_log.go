@@ -0,0 +1,50 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/gorm/helpers"
+)
+
+// AuditLogRepository is an in-memory repository that implements repository.AuditLogRepository
+type AuditLogRepository struct {
+	canQuery  bool
+	auditLogs []*models.AuditLog
+}
+
+// NewAuditLogRepository will return errors if canQuery is false
+func NewAuditLogRepository(canQuery bool) repository.AuditLogRepository {
+	return &AuditLogRepository{canQuery: canQuery}
+}
+
+// CreateAuditLog creates a new audit log entry
+func (repo *AuditLogRepository) CreateAuditLog(ctx context.Context, auditLog *models.AuditLog) (*models.AuditLog, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.auditLogs = append(repo.auditLogs, auditLog)
+	auditLog.ID = uint(len(repo.auditLogs))
+
+	return auditLog, nil
+}
+
+// ListAuditLogs returns the audit logs of a project, ignoring filters and pagination
+func (repo *AuditLogRepository) ListAuditLogs(ctx context.Context, projectID uint, filters []repository.AuditLogFilters, opts ...helpers.QueryOption) ([]*models.AuditLog, helpers.PaginatedResult, error) {
+	if !repo.canQuery {
+		return nil, helpers.PaginatedResult{}, errors.New("cannot read database")
+	}
+
+	res := make([]*models.AuditLog, 0)
+
+	for i := len(repo.auditLogs) - 1; i >= 0; i-- {
+		if repo.auditLogs[i].ProjectID == projectID {
+			res = append(res, repo.auditLogs[i])
+		}
+	}
+
+	return res, helpers.PaginatedResult{NumPages: 1, CurrentPage: 1, NextPage: 1}, nil
+}
@@ -3,46 +3,124 @@ package test
 import (
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
 )
 
 // DeploymentTargetRepository is a test repository that implements repository.DeploymentTargetRepository
 type DeploymentTargetRepository struct {
-	canQuery bool
+	canQuery          bool
+	deploymentTargets []*models.DeploymentTarget
 }
 
 // NewDeploymentTargetRepository returns the test DeploymentTargetRepository
-func NewDeploymentTargetRepository() repository.DeploymentTargetRepository {
-	return &DeploymentTargetRepository{canQuery: false}
+func NewDeploymentTargetRepository(canQuery bool) repository.DeploymentTargetRepository {
+	return &DeploymentTargetRepository{canQuery: canQuery}
 }
 
 // DeploymentTargetBySelectorAndSelectorType finds a deployment target for a projectID and clusterID by its selector and selector type
 func (repo *DeploymentTargetRepository) DeploymentTargetBySelectorAndSelectorType(projectID uint, clusterID uint, selector, selectorType string) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if uint(deploymentTarget.ProjectID) == projectID && uint(deploymentTarget.ClusterID) == clusterID &&
+			deploymentTarget.Selector == selector && string(deploymentTarget.SelectorType) == selectorType {
+			return deploymentTarget, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
 // ListForCluster returns all deployment targets for a project
 func (repo *DeploymentTargetRepository) ListForCluster(projectID uint, clusterID uint, preview bool) ([]*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	var res []*models.DeploymentTarget
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if uint(deploymentTarget.ProjectID) == projectID && uint(deploymentTarget.ClusterID) == clusterID && deploymentTarget.Preview == preview {
+			res = append(res, deploymentTarget)
+		}
+	}
+
+	return res, nil
 }
 
 // List returns all deployment targets for a project
 func (repo *DeploymentTargetRepository) List(projectID uint, preview bool) ([]*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	var res []*models.DeploymentTarget
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if uint(deploymentTarget.ProjectID) == projectID && deploymentTarget.Preview == preview {
+			res = append(res, deploymentTarget)
+		}
+	}
+
+	return res, nil
 }
 
 // CreateDeploymentTarget creates a new deployment target
 func (repo *DeploymentTargetRepository) CreateDeploymentTarget(deploymentTarget *models.DeploymentTarget) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot write database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if deploymentTarget.ID == uuid.Nil {
+		deploymentTarget.ID = uuid.New()
+	}
+
+	repo.deploymentTargets = append(repo.deploymentTargets, deploymentTarget)
+
+	return deploymentTarget, nil
 }
 
 // DeploymentTarget finds a deployment target by its id if a uuid is provided or by name
 func (repo *DeploymentTargetRepository) DeploymentTarget(projectID uint, deploymentTargetIdentifier string) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	if deploymentTargetIdentifier == "" {
+		return nil, errors.New("deployment target identifier cannot be empty")
+	}
+
+	id, err := uuid.Parse(deploymentTargetIdentifier)
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if uint(deploymentTarget.ProjectID) != projectID {
+			continue
+		}
+
+		if (err == nil && deploymentTarget.ID == id) || (err != nil && deploymentTarget.VanityName == deploymentTargetIdentifier) {
+			return deploymentTarget, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
 // DeploymentTargetById finds a deployment target by its uuid
 func (repo *DeploymentTargetRepository) DeploymentTargetById(id string) (*models.DeploymentTarget, error) {
-	return nil, errors.New("cannot read database")
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, deploymentTarget := range repo.deploymentTargets {
+		if deploymentTarget.ID.String() == id {
+			return deploymentTarget, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
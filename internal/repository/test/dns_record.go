@@ -5,6 +5,7 @@ import (
 
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
 )
 
 // DNSRecordRepository implements repository.DNSRecordRepository
@@ -34,3 +35,35 @@ func (repo *DNSRecordRepository) CreateDNSRecord(
 
 	return record, nil
 }
+
+// ListDNSRecords lists all dns records
+func (repo *DNSRecordRepository) ListDNSRecords() ([]*models.DNSRecord, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.DNSRecord, 0)
+
+	for _, record := range repo.dnsRecords {
+		if record != nil {
+			res = append(res, record)
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteDNSRecord deletes a dns record
+func (repo *DNSRecordRepository) DeleteDNSRecord(record *models.DNSRecord) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if record.ID == 0 || int(record.ID-1) >= len(repo.dnsRecords) || repo.dnsRecords[record.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.dnsRecords[record.ID-1] = nil
+
+	return nil
+}
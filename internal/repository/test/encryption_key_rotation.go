@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// EncryptionKeyRotationRepository is an in-memory repository that implements
+// repository.EncryptionKeyRotationRepository
+type EncryptionKeyRotationRepository struct {
+	canQuery bool
+
+	mu        sync.Mutex
+	rotations []*models.EncryptionKeyRotation
+}
+
+// NewEncryptionKeyRotationRepository will return errors if canQuery is false
+func NewEncryptionKeyRotationRepository(canQuery bool) repository.EncryptionKeyRotationRepository {
+	return &EncryptionKeyRotationRepository{canQuery: canQuery}
+}
+
+// CreateEncryptionKeyRotation creates the progress of the rotation of a table
+func (repo *EncryptionKeyRotationRepository) CreateEncryptionKeyRotation(
+	ctx context.Context,
+	rotation *models.EncryptionKeyRotation,
+) (*models.EncryptionKeyRotation, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	rotation.ID = uint(len(repo.rotations) + 1)
+	rotation.CreatedAt = time.Now().UTC()
+	rotation.UpdatedAt = rotation.CreatedAt
+
+	repo.rotations = append(repo.rotations, rotation)
+
+	return rotation, nil
+}
+
+// ReadEncryptionKeyRotation reads the progress of the rotation of a table under a key
+func (repo *EncryptionKeyRotationRepository) ReadEncryptionKeyRotation(
+	ctx context.Context,
+	keyID, table string,
+) (*models.EncryptionKeyRotation, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, rotation := range repo.rotations {
+		if rotation.KeyID == keyID && rotation.ModelTable == table {
+			return rotation, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// UpdateEncryptionKeyRotation updates the progress of the rotation of a table
+func (repo *EncryptionKeyRotationRepository) UpdateEncryptionKeyRotation(
+	ctx context.Context,
+	rotation *models.EncryptionKeyRotation,
+) (*models.EncryptionKeyRotation, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if rotation.ID == 0 || int(rotation.ID) > len(repo.rotations) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	rotation.UpdatedAt = time.Now().UTC()
+	repo.rotations[rotation.ID-1] = rotation
+
+	return rotation, nil
+}
+
+// ListEncryptionKeyRotations lists the progress of the rotation of every table under a key
+func (repo *EncryptionKeyRotationRepository) ListEncryptionKeyRotations(
+	ctx context.Context,
+	keyID string,
+) ([]*models.EncryptionKeyRotation, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]*models.EncryptionKeyRotation, 0)
+
+	for _, rotation := range repo.rotations {
+		if rotation.KeyID == keyID {
+			res = append(res, rotation)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// EnvironmentGroupSettingRepository is an in-memory repository that implements
+// repository.EnvironmentGroupSettingRepository
+type EnvironmentGroupSettingRepository struct {
+	canQuery bool
+
+	mu       sync.Mutex
+	settings []*models.EnvironmentGroupSetting
+}
+
+// NewEnvironmentGroupSettingRepository will return errors if canQuery is false
+func NewEnvironmentGroupSettingRepository(canQuery bool) repository.EnvironmentGroupSettingRepository {
+	return &EnvironmentGroupSettingRepository{canQuery: canQuery}
+}
+
+// ReadEnvironmentGroupSetting returns the settings of an environment group, or gorm.ErrRecordNotFound if none were stored
+func (repo *EnvironmentGroupSettingRepository) ReadEnvironmentGroupSetting(
+	ctx context.Context,
+	clusterID uint,
+	environmentGroupName string,
+) (*models.EnvironmentGroupSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, setting := range repo.settings {
+		if setting.ClusterID == clusterID && setting.EnvironmentGroupName == environmentGroupName {
+			return setting, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// UpsertEnvironmentGroupSetting creates or updates the settings of an environment group
+func (repo *EnvironmentGroupSettingRepository) UpsertEnvironmentGroupSetting(
+	ctx context.Context,
+	setting *models.EnvironmentGroupSetting,
+) (*models.EnvironmentGroupSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	setting.UpdatedAt = time.Now().UTC()
+
+	for i, existing := range repo.settings {
+		if existing.ClusterID == setting.ClusterID && existing.EnvironmentGroupName == setting.EnvironmentGroupName {
+			setting.ID = existing.ID
+			setting.CreatedAt = existing.CreatedAt
+			repo.settings[i] = setting
+
+			return setting, nil
+		}
+	}
+
+	setting.ID = uint(len(repo.settings) + 1)
+	setting.CreatedAt = setting.UpdatedAt
+	repo.settings = append(repo.settings, setting)
+
+	return setting, nil
+}
+
+// ListEnvironmentGroupSettings returns the settings of all environment groups of a cluster
+func (repo *EnvironmentGroupSettingRepository) ListEnvironmentGroupSettings(
+	ctx context.Context,
+	clusterID uint,
+) ([]*models.EnvironmentGroupSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]*models.EnvironmentGroupSetting, 0)
+
+	for _, setting := range repo.settings {
+		if setting.ClusterID == clusterID {
+			res = append(res, setting)
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteEnvironmentGroupSetting deletes the settings of an environment group, if any
+func (repo *EnvironmentGroupSettingRepository) DeleteEnvironmentGroupSetting(
+	ctx context.Context,
+	clusterID uint,
+	environmentGroupName string,
+) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	settings := make([]*models.EnvironmentGroupSetting, 0, len(repo.settings))
+	for _, setting := range repo.settings {
+		if setting.ClusterID != clusterID || setting.EnvironmentGroupName != environmentGroupName {
+			settings = append(settings, setting)
+		}
+	}
+	repo.settings = settings
+
+	return nil
+}
@@ -10,8 +10,9 @@ import (
 
 // InfraRepository implements repository.InfraRepository
 type InfraRepository struct {
-	canQuery bool
-	infras   []*models.Infra
+	canQuery   bool
+	infras     []*models.Infra
+	operations []*models.Operation
 }
 
 // NewInfraRepository will return errors if canQuery is false
@@ -19,6 +20,7 @@ func NewInfraRepository(canQuery bool) repository.InfraRepository {
 	return &InfraRepository{
 		canQuery,
 		[]*models.Infra{},
+		[]*models.Operation{},
 	}
 }
 
@@ -92,24 +94,103 @@ func (repo *InfraRepository) UpdateInfra(
 	return ai, nil
 }
 
+// AddOperation adds an operation to an infra
 func (repo *InfraRepository) AddOperation(infra *models.Infra, operation *models.Operation) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	operation.InfraID = infra.ID
+	repo.operations = append(repo.operations, operation)
+	operation.ID = uint(len(repo.operations))
+
+	return operation, nil
 }
 
+// GetLatestOperation returns the last operation added to an infra
 func (repo *InfraRepository) GetLatestOperation(infra *models.Infra) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for i := len(repo.operations) - 1; i >= 0; i-- {
+		if repo.operations[i].InfraID == infra.ID {
+			return repo.operations[i], nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
+// ListOperations returns the operations of an infra, latest first
 func (repo *InfraRepository) ListOperations(infraID uint) ([]*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Operation, 0)
+
+	for i := len(repo.operations) - 1; i >= 0; i-- {
+		if repo.operations[i].InfraID == infraID {
+			res = append(res, repo.operations[i])
+		}
+	}
+
+	return res, nil
 }
 
+// ReadOperation finds an operation of an infra by its uid
 func (repo *InfraRepository) ReadOperation(infraID uint, operationUID string) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, operation := range repo.operations {
+		if operation.InfraID == infraID && operation.UID == operationUID {
+			return operation, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
+// UpdateOperation modifies an existing operation
 func (repo *InfraRepository) UpdateOperation(
 	operation *models.Operation,
 ) (*models.Operation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(operation.ID-1) >= len(repo.operations) || repo.operations[operation.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.operations[operation.ID-1] = operation
+
+	return operation, nil
+}
+
+// UpdateOperationStatus sets the status of an operation to toStatus only if its status is fromStatus
+func (repo *InfraRepository) UpdateOperationStatus(
+	operation *models.Operation,
+	fromStatus, toStatus string,
+) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("Cannot write database")
+	}
+
+	if int(operation.ID-1) >= len(repo.operations) || repo.operations[operation.ID-1] == nil {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	stored := repo.operations[operation.ID-1]
+	if stored.Status != fromStatus {
+		return false, nil
+	}
+
+	stored.Status = toStatus
+	operation.Status = toStatus
+
+	return true, nil
 }
@@ -0,0 +1,232 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// LogArchiveRepository is an in-memory repository that implements repository.LogArchiveRepository
+type LogArchiveRepository struct {
+	canQuery bool
+
+	mu          sync.Mutex
+	settings    []*models.LogArchiveSetting
+	checkpoints []*models.LogArchiveCheckpoint
+	archives    []*models.LogArchive
+}
+
+// NewLogArchiveRepository will return errors if canQuery is false
+func NewLogArchiveRepository(canQuery bool) repository.LogArchiveRepository {
+	return &LogArchiveRepository{canQuery: canQuery}
+}
+
+// ReadLogArchiveSetting returns the log archive setting of a project, or gorm.ErrRecordNotFound if none was stored
+func (repo *LogArchiveRepository) ReadLogArchiveSetting(ctx context.Context, projectID uint) (*models.LogArchiveSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, setting := range repo.settings {
+		if setting.ProjectID == projectID {
+			return setting, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// UpsertLogArchiveSetting creates or updates the log archive setting of a project
+func (repo *LogArchiveRepository) UpsertLogArchiveSetting(
+	ctx context.Context,
+	setting *models.LogArchiveSetting,
+) (*models.LogArchiveSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	setting.UpdatedAt = time.Now().UTC()
+
+	for i, existing := range repo.settings {
+		if existing.ProjectID == setting.ProjectID {
+			setting.ID = existing.ID
+			setting.CreatedAt = existing.CreatedAt
+			repo.settings[i] = setting
+
+			return setting, nil
+		}
+	}
+
+	setting.ID = uint(len(repo.settings) + 1)
+	setting.CreatedAt = setting.UpdatedAt
+	repo.settings = append(repo.settings, setting)
+
+	return setting, nil
+}
+
+// ListEnabledLogArchiveSettings returns the log archive settings of every project which archives its logs
+func (repo *LogArchiveRepository) ListEnabledLogArchiveSettings(ctx context.Context) ([]*models.LogArchiveSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]*models.LogArchiveSetting, 0)
+
+	for _, setting := range repo.settings {
+		if setting.Enabled {
+			res = append(res, setting)
+		}
+	}
+
+	return res, nil
+}
+
+// ReadLogArchiveCheckpoint returns the checkpoint of a cluster, or gorm.ErrRecordNotFound if its logs were never archived
+func (repo *LogArchiveRepository) ReadLogArchiveCheckpoint(ctx context.Context, clusterID uint) (*models.LogArchiveCheckpoint, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, checkpoint := range repo.checkpoints {
+		if checkpoint.ClusterID == clusterID {
+			return checkpoint, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// UpsertLogArchiveCheckpoint creates or updates the checkpoint of a cluster
+func (repo *LogArchiveRepository) UpsertLogArchiveCheckpoint(
+	ctx context.Context,
+	checkpoint *models.LogArchiveCheckpoint,
+) (*models.LogArchiveCheckpoint, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	checkpoint.UpdatedAt = time.Now().UTC()
+
+	for i, existing := range repo.checkpoints {
+		if existing.ClusterID == checkpoint.ClusterID {
+			checkpoint.ID = existing.ID
+			checkpoint.CreatedAt = existing.CreatedAt
+			repo.checkpoints[i] = checkpoint
+
+			return checkpoint, nil
+		}
+	}
+
+	checkpoint.ID = uint(len(repo.checkpoints) + 1)
+	checkpoint.CreatedAt = checkpoint.UpdatedAt
+	repo.checkpoints = append(repo.checkpoints, checkpoint)
+
+	return checkpoint, nil
+}
+
+// ListLogArchiveCheckpoints returns the checkpoints of the clusters of a project
+func (repo *LogArchiveRepository) ListLogArchiveCheckpoints(ctx context.Context, projectID uint) ([]*models.LogArchiveCheckpoint, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]*models.LogArchiveCheckpoint, 0)
+
+	for _, checkpoint := range repo.checkpoints {
+		if checkpoint.ProjectID == projectID {
+			res = append(res, checkpoint)
+		}
+	}
+
+	return res, nil
+}
+
+// UpsertLogArchive creates or updates an archived object, keyed by its object key
+func (repo *LogArchiveRepository) UpsertLogArchive(ctx context.Context, archive *models.LogArchive) (*models.LogArchive, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	archive.UpdatedAt = time.Now().UTC()
+
+	for i, existing := range repo.archives {
+		if existing.ObjectKey == archive.ObjectKey {
+			archive.ID = existing.ID
+			archive.CreatedAt = existing.CreatedAt
+			repo.archives[i] = archive
+
+			return archive, nil
+		}
+	}
+
+	archive.ID = uint(len(repo.archives) + 1)
+	archive.CreatedAt = archive.UpdatedAt
+	repo.archives = append(repo.archives, archive)
+
+	return archive, nil
+}
+
+// ListLogArchives returns the archived objects of a project which match the filter, ordered by start time
+func (repo *LogArchiveRepository) ListLogArchives(
+	ctx context.Context,
+	projectID uint,
+	filter repository.LogArchiveFilter,
+) ([]*models.LogArchive, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]*models.LogArchive, 0)
+
+	for _, archive := range repo.archives {
+		switch {
+		case archive.ProjectID != projectID,
+			filter.ClusterID != 0 && archive.ClusterID != filter.ClusterID,
+			filter.DeploymentTargetID != uuid.Nil && archive.DeploymentTargetID != filter.DeploymentTargetID,
+			filter.AppName != "" && archive.AppName != filter.AppName,
+			filter.ServiceName != "" && archive.ServiceName != filter.ServiceName,
+			!filter.Since.IsZero() && !archive.EndTime.After(filter.Since),
+			!filter.Until.IsZero() && !archive.StartTime.Before(filter.Until):
+			continue
+		}
+
+		res = append(res, archive)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].StartTime.Before(res[j].StartTime)
+	})
+
+	return res, nil
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"errors"
+
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// NotifierIntegrationRepository implements repository.NotifierIntegrationRepository
+type NotifierIntegrationRepository struct {
+	canQuery     bool
+	notifierInts []*ints.NotifierIntegration
+}
+
+// NewNotifierIntegrationRepository will return errors if canQuery is false
+func NewNotifierIntegrationRepository(canQuery bool) repository.NotifierIntegrationRepository {
+	return &NotifierIntegrationRepository{
+		canQuery,
+		[]*ints.NotifierIntegration{},
+	}
+}
+
+// CreateNotifierIntegration creates a new notifier integration
+func (repo *NotifierIntegrationRepository) CreateNotifierIntegration(
+	notifierInt *ints.NotifierIntegration,
+) (*ints.NotifierIntegration, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.notifierInts = append(repo.notifierInts, notifierInt)
+	notifierInt.ID = uint(len(repo.notifierInts))
+
+	return notifierInt, nil
+}
+
+// ListNotifierIntegrationsByProjectID finds all notifier integrations for a given project id
+func (repo *NotifierIntegrationRepository) ListNotifierIntegrationsByProjectID(
+	projectID uint,
+) ([]*ints.NotifierIntegration, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*ints.NotifierIntegration, 0)
+
+	for _, notifierInt := range repo.notifierInts {
+		if notifierInt != nil && notifierInt.ProjectID == projectID {
+			res = append(res, notifierInt)
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteNotifierIntegration deletes a notifier integration by ID
+func (repo *NotifierIntegrationRepository) DeleteNotifierIntegration(integrationID uint) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if integrationID == 0 || int(integrationID-1) >= len(repo.notifierInts) || repo.notifierInts[integrationID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.notifierInts[integrationID-1] = nil
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// OPAPolicyBundleRepository is an in-memory repository that implements repository.OPAPolicyBundleRepository
+type OPAPolicyBundleRepository struct {
+	canQuery bool
+	bundles  []*models.OPAPolicyBundle
+}
+
+// NewOPAPolicyBundleRepository will return errors if canQuery is false
+func NewOPAPolicyBundleRepository(canQuery bool) repository.OPAPolicyBundleRepository {
+	return &OPAPolicyBundleRepository{canQuery: canQuery}
+}
+
+// CreateOPAPolicyBundle creates a new policy bundle
+func (repo *OPAPolicyBundleRepository) CreateOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) (*models.OPAPolicyBundle, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.bundles = append(repo.bundles, bundle)
+	bundle.ID = uint(len(repo.bundles))
+
+	return bundle, nil
+}
+
+// ReadOPAPolicyBundle reads a policy bundle by its id
+func (repo *OPAPolicyBundleRepository) ReadOPAPolicyBundle(ctx context.Context, projectID, id uint) (*models.OPAPolicyBundle, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, bundle := range repo.bundles {
+		if bundle != nil && bundle.ProjectID == projectID && bundle.ID == id {
+			return bundle, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ReadOPAPolicyBundleByName reads a policy bundle by its name
+func (repo *OPAPolicyBundleRepository) ReadOPAPolicyBundleByName(ctx context.Context, projectID uint, name string) (*models.OPAPolicyBundle, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	for _, bundle := range repo.bundles {
+		if bundle != nil && bundle.ProjectID == projectID && bundle.Name == name {
+			return bundle, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListOPAPolicyBundlesByProjectID lists all policy bundles in a project
+func (repo *OPAPolicyBundleRepository) ListOPAPolicyBundlesByProjectID(ctx context.Context, projectID uint) ([]*models.OPAPolicyBundle, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	res := []*models.OPAPolicyBundle{}
+	for _, bundle := range repo.bundles {
+		if bundle != nil && bundle.ProjectID == projectID {
+			res = append(res, bundle)
+		}
+	}
+
+	return res, nil
+}
+
+// UpdateOPAPolicyBundle updates a policy bundle
+func (repo *OPAPolicyBundleRepository) UpdateOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) (*models.OPAPolicyBundle, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	if int(bundle.ID) > len(repo.bundles) || bundle.ID == 0 || repo.bundles[bundle.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.bundles[bundle.ID-1] = bundle
+
+	return bundle, nil
+}
+
+// DeleteOPAPolicyBundle deletes a policy bundle
+func (repo *OPAPolicyBundleRepository) DeleteOPAPolicyBundle(ctx context.Context, bundle *models.OPAPolicyBundle) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	if int(bundle.ID) > len(repo.bundles) || bundle.ID == 0 || repo.bundles[bundle.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.bundles[bundle.ID-1] = nil
+
+	return nil
+}
@@ -32,6 +32,7 @@ type TestRepository struct {
 	gitlabIntegration         repository.GitlabIntegrationRepository
 	gitlabAppOAuthIntegration repository.GitlabAppOAuthIntegrationRepository
 	slackIntegration          repository.SlackIntegrationRepository
+	notifierIntegration       repository.NotifierIntegrationRepository
 	appEventWebhook           repository.AppEventWebhookRepository
 	notificationConfig        repository.NotificationConfigRepository
 	jobNotificationConfig     repository.JobNotificationConfigRepository
@@ -59,6 +60,14 @@ type TestRepository struct {
 	githubWebhook             repository.GithubWebhookRepository
 	datastore                 repository.DatastoreRepository
 	appInstance               repository.AppInstanceRepository
+	workerJobRun              repository.WorkerJobRunRepository
+	workerJobSchedule         repository.WorkerJobScheduleRepository
+	opaPolicyBundle           repository.OPAPolicyBundleRepository
+	auditLog                  repository.AuditLogRepository
+	ssoConnection             repository.SSOConnectionRepository
+	encryptionKeyRotation     repository.EncryptionKeyRotationRepository
+	environmentGroupSetting   repository.EnvironmentGroupSettingRepository
+	logArchive                repository.LogArchiveRepository
 }
 
 func (t *TestRepository) User() repository.UserRepository {
@@ -169,6 +178,11 @@ func (t *TestRepository) SlackIntegration() repository.SlackIntegrationRepositor
 	return t.slackIntegration
 }
 
+// NotifierIntegration returns a test NotifierIntegrationRepository
+func (t *TestRepository) NotifierIntegration() repository.NotifierIntegrationRepository {
+	return t.notifierIntegration
+}
+
 func (t *TestRepository) AppEventWebhook() repository.AppEventWebhookRepository {
 	return t.appEventWebhook
 }
@@ -283,6 +297,31 @@ func (t *TestRepository) AppInstance() repository.AppInstanceRepository {
 	return t.appInstance
 }
 
+// WorkerJobRun returns a test WorkerJobRunRepository
+func (t *TestRepository) WorkerJobRun() repository.WorkerJobRunRepository {
+	return t.workerJobRun
+}
+
+// WorkerJobSchedule returns a test WorkerJobScheduleRepository
+func (t *TestRepository) WorkerJobSchedule() repository.WorkerJobScheduleRepository {
+	return t.workerJobSchedule
+}
+
+// OPAPolicyBundle returns a test OPAPolicyBundleRepository
+func (t *TestRepository) OPAPolicyBundle() repository.OPAPolicyBundleRepository {
+	return t.opaPolicyBundle
+}
+
+// AuditLog returns a test AuditLogRepository
+func (t *TestRepository) AuditLog() repository.AuditLogRepository {
+	return t.auditLog
+}
+
+// SSOConnection returns a test SSOConnectionRepository
+func (t *TestRepository) SSOConnection() repository.SSOConnectionRepository {
+	return t.ssoConnection
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(canQuery bool, failingMethods ...string) repository.Repository {
@@ -314,6 +353,7 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		gitlabIntegration:         NewGitlabIntegrationRepository(canQuery),
 		gitlabAppOAuthIntegration: NewGitlabAppOAuthIntegrationRepository(canQuery),
 		slackIntegration:          NewSlackIntegrationRepository(canQuery),
+		notifierIntegration:       NewNotifierIntegrationRepository(canQuery),
 		appEventWebhook:           NewAppEventWebhookRepository(canQuery),
 		notificationConfig:        NewNotificationConfigRepository(canQuery),
 		jobNotificationConfig:     NewJobNotificationConfigRepository(canQuery),
@@ -335,11 +375,34 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		porterApp:                 NewPorterAppRepository(canQuery, failingMethods...),
 		porterAppEvent:            NewPorterAppEventRepository(canQuery),
 		systemServiceStatus:       NewSystemServiceStatusRepository(canQuery),
-		deploymentTarget:          NewDeploymentTargetRepository(),
+		deploymentTarget:          NewDeploymentTargetRepository(canQuery),
 		appRevision:               NewAppRevisionRepository(),
 		appTemplate:               NewAppTemplateRepository(),
 		githubWebhook:             NewGithubWebhookRepository(),
 		datastore:                 NewDatastoreRepository(),
-		appInstance:               NewAppInstanceRepository(),
+		appInstance:               NewAppInstanceRepository(canQuery),
+		workerJobRun:              NewWorkerJobRunRepository(canQuery),
+		workerJobSchedule:         NewWorkerJobScheduleRepository(canQuery),
+		opaPolicyBundle:           NewOPAPolicyBundleRepository(canQuery),
+		auditLog:                  NewAuditLogRepository(canQuery),
+		ssoConnection:             NewSSOConnectionRepository(canQuery),
+		encryptionKeyRotation:     NewEncryptionKeyRotationRepository(canQuery),
+		environmentGroupSetting:   NewEnvironmentGroupSettingRepository(canQuery),
+		logArchive:                NewLogArchiveRepository(canQuery),
 	}
 }
+
+// EncryptionKeyRotation returns a test EncryptionKeyRotationRepository
+func (t *TestRepository) EncryptionKeyRotation() repository.EncryptionKeyRotationRepository {
+	return t.encryptionKeyRotation
+}
+
+// EnvironmentGroupSetting returns a test EnvironmentGroupSettingRepository
+func (t *TestRepository) EnvironmentGroupSetting() repository.EnvironmentGroupSettingRepository {
+	return t.environmentGroupSetting
+}
+
+// LogArchive returns a test LogArchiveRepository
+func (t *TestRepository) LogArchive() repository.LogArchiveRepository {
+	return t.logArchive
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// SSOConnectionRepository implements repository.SSOConnectionRepository
+type SSOConnectionRepository struct {
+	canQuery bool
+	conns    []*models.SSOConnection
+
+	// domainCount is the number of domains which have been created, to assign domain ids
+	domainCount uint
+}
+
+// NewSSOConnectionRepository will return errors if canQuery is false
+func NewSSOConnectionRepository(canQuery bool) repository.SSOConnectionRepository {
+	return &SSOConnectionRepository{
+		canQuery: canQuery,
+		conns:    []*models.SSOConnection{},
+	}
+}
+
+// CreateSSOConnection creates a new sso connection
+func (repo *SSOConnectionRepository) CreateSSOConnection(conn *models.SSOConnection) (*models.SSOConnection, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.conns = append(repo.conns, conn)
+	conn.ID = uint(len(repo.conns))
+
+	for i := range conn.Domains {
+		repo.domainCount++
+		conn.Domains[i].ID = repo.domainCount
+		conn.Domains[i].SSOConnectionID = conn.ID
+	}
+
+	return conn, nil
+}
+
+// ReadSSOConnection finds an sso connection of a project by its id
+func (repo *SSOConnectionRepository) ReadSSOConnection(projectID, connID uint) (*models.SSOConnection, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, conn := range repo.conns {
+		if conn != nil && conn.ProjectID == projectID && conn.ID == connID {
+			return conn, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ReadSSOConnectionByUniqueID finds an sso connection by its unique id
+func (repo *SSOConnectionRepository) ReadSSOConnectionByUniqueID(uniqueID string) (*models.SSOConnection, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, conn := range repo.conns {
+		if conn != nil && conn.UniqueID == uniqueID {
+			return conn, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ReadSSOConnectionByDomain finds the sso connection which has verified an email domain
+func (repo *SSOConnectionRepository) ReadSSOConnectionByDomain(domain string) (*models.SSOConnection, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, conn := range repo.conns {
+		if conn == nil {
+			continue
+		}
+
+		for _, connDomain := range conn.VerifiedDomainList() {
+			if connDomain == strings.ToLower(domain) {
+				return conn, nil
+			}
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListSSOConnectionsByProjectID finds all sso connections for a given project id
+func (repo *SSOConnectionRepository) ListSSOConnectionsByProjectID(projectID uint) ([]*models.SSOConnection, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.SSOConnection, 0)
+
+	for _, conn := range repo.conns {
+		if conn != nil && conn.ProjectID == projectID {
+			res = append(res, conn)
+		}
+	}
+
+	return res, nil
+}
+
+// UpdateSSODomain modifies an existing sso domain, and fails if another connection has verified the domain
+func (repo *SSOConnectionRepository) UpdateSSODomain(domain *models.SSODomain) (*models.SSODomain, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	for _, conn := range repo.conns {
+		if conn == nil {
+			continue
+		}
+
+		for i := range conn.Domains {
+			existing := &conn.Domains[i]
+
+			if existing.ID != domain.ID && domain.VerifiedAt != nil && existing.VerifiedAt != nil && existing.Domain == domain.Domain {
+				return nil, errors.New("domain is already verified by another sso connection")
+			}
+		}
+	}
+
+	for _, conn := range repo.conns {
+		if conn == nil {
+			continue
+		}
+
+		for i := range conn.Domains {
+			if conn.Domains[i].ID == domain.ID {
+				conn.Domains[i] = *domain
+				return domain, nil
+			}
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// DeleteSSOConnection deletes an sso connection
+func (repo *SSOConnectionRepository) DeleteSSOConnection(conn *models.SSOConnection) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if conn.ID == 0 || int(conn.ID-1) >= len(repo.conns) || repo.conns[conn.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.conns[conn.ID-1] = nil
+
+	return nil
+}
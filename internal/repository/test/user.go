@@ -129,6 +129,21 @@ func (repo *UserRepository) ReadUserByGoogleUserID(id string) (*models.User, err
 	return nil, gorm.ErrRecordNotFound
 }
 
+// ReadUserBySSOSubject finds a single user based on their sso connection and subject
+func (repo *UserRepository) ReadUserBySSOSubject(connection, subject string) (*models.User, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, u := range repo.users {
+		if u != nil && u.SSOConnection == connection && u.SSOSubject == subject && subject != "" {
+			return u, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
 // UpdateUser modifies an existing User in the database
 func (repo *UserRepository) UpdateUser(user *models.User) (*models.User, error) {
 	if !repo.canQuery {
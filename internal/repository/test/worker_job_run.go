@@ -0,0 +1,224 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// WorkerJobRunRepository is an in-memory repository that implements repository.WorkerJobRunRepository
+type WorkerJobRunRepository struct {
+	canQuery bool
+
+	mu   sync.Mutex
+	runs []*models.WorkerJobRun
+}
+
+// NewWorkerJobRunRepository will return errors if canQuery is false
+func NewWorkerJobRunRepository(canQuery bool) repository.WorkerJobRunRepository {
+	return &WorkerJobRunRepository{canQuery: canQuery}
+}
+
+// CreateWorkerJobRun creates a new worker job run
+func (repo *WorkerJobRunRepository) CreateWorkerJobRun(ctx context.Context, run *models.WorkerJobRun) (*models.WorkerJobRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	run.ID = uint(len(repo.runs) + 1)
+	run.CreatedAt = time.Now().UTC()
+	run.UpdatedAt = run.CreatedAt
+
+	copied := *run
+	repo.runs = append(repo.runs, &copied)
+
+	return run, nil
+}
+
+// ReadWorkerJobRun reads a worker job run by its id
+func (repo *WorkerJobRunRepository) ReadWorkerJobRun(ctx context.Context, id uint) (*models.WorkerJobRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if int(id) > len(repo.runs) || id == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	copied := *repo.runs[id-1]
+	return &copied, nil
+}
+
+// UpdateWorkerJobRun updates a worker job run
+func (repo *WorkerJobRunRepository) UpdateWorkerJobRun(ctx context.Context, run *models.WorkerJobRun) (*models.WorkerJobRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if int(run.ID) > len(repo.runs) || run.ID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	run.UpdatedAt = time.Now().UTC()
+
+	copied := *run
+	repo.runs[run.ID-1] = &copied
+
+	return run, nil
+}
+
+// ClaimWorkerJobRun claims the oldest run which is due to be attempted
+func (repo *WorkerJobRunRepository) ClaimWorkerJobRun(ctx context.Context, workerID string, now time.Time, lockedUntil time.Time) (*models.WorkerJobRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var candidate *models.WorkerJobRun
+
+	for _, run := range repo.runs {
+		if run.Status == models.WorkerJobRunStatus_Running && run.LockedUntil != nil && run.LockedUntil.Before(now) && run.Attempts >= run.MaxAttempts {
+			run.Status = models.WorkerJobRunStatus_Dead
+			run.LastError = "worker lock expired while the run was running"
+			run.CompletedAt = &now
+			run.LockedBy = ""
+			run.LockedUntil = nil
+		}
+	}
+
+	// runs which share a concurrency key are claimed one at a time, oldest first
+	blockedKeys := make(map[string]bool)
+
+	for _, run := range repo.runs {
+		due := (run.Status == models.WorkerJobRunStatus_Queued || run.Status == models.WorkerJobRunStatus_Retrying) && !run.NextRunAt.After(now)
+		abandoned := run.Status == models.WorkerJobRunStatus_Running && run.LockedUntil != nil && run.LockedUntil.Before(now) && run.Attempts < run.MaxAttempts
+		unfinished := run.Status == models.WorkerJobRunStatus_Queued || run.Status == models.WorkerJobRunStatus_Retrying || run.Status == models.WorkerJobRunStatus_Running
+
+		blocked := run.ConcurrencyKey != "" && blockedKeys[run.ConcurrencyKey]
+		if run.ConcurrencyKey != "" && unfinished {
+			blockedKeys[run.ConcurrencyKey] = true
+		}
+
+		if (due || abandoned) && !blocked && (candidate == nil || run.NextRunAt.Before(candidate.NextRunAt)) {
+			candidate = run
+		}
+	}
+
+	if candidate == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	candidate.Status = models.WorkerJobRunStatus_Running
+	candidate.Attempts++
+	candidate.StartedAt = &now
+	candidate.LockedBy = workerID
+	candidate.LockedUntil = &lockedUntil
+
+	copied := *candidate
+	return &copied, nil
+}
+
+// ExtendWorkerJobRunLock extends the lock of a running run held by the given worker
+func (repo *WorkerJobRunRepository) ExtendWorkerJobRunLock(ctx context.Context, id uint, workerID string, lockedUntil time.Time) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if int(id) > len(repo.runs) || id == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	run := repo.runs[id-1]
+	if run.Status != models.WorkerJobRunStatus_Running || run.LockedBy != workerID {
+		return gorm.ErrRecordNotFound
+	}
+
+	run.LockedUntil = &lockedUntil
+
+	return nil
+}
+
+// FinishWorkerJobRun records the outcome of a running run claimed by the given worker
+func (repo *WorkerJobRunRepository) FinishWorkerJobRun(ctx context.Context, run *models.WorkerJobRun, workerID string) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if int(run.ID) > len(repo.runs) || run.ID == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	stored := repo.runs[run.ID-1]
+	if stored.Status != models.WorkerJobRunStatus_Running || stored.LockedBy != workerID {
+		return gorm.ErrRecordNotFound
+	}
+
+	stored.Status = run.Status
+	stored.LastError = run.LastError
+	stored.Result = run.Result
+	stored.NextRunAt = run.NextRunAt
+	stored.CompletedAt = run.CompletedAt
+	stored.LockedBy = run.LockedBy
+	stored.LockedUntil = run.LockedUntil
+	stored.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+// ListWorkerJobRunsByJobID lists the most recent runs of a job, newest first
+func (repo *WorkerJobRunRepository) ListWorkerJobRunsByJobID(ctx context.Context, jobID string, limit int) ([]*models.WorkerJobRun, error) {
+	return repo.list(func(run *models.WorkerJobRun) bool { return run.JobID == jobID }, limit)
+}
+
+// ListWorkerJobRunsByStatus lists the most recent runs with the given status, newest first
+func (repo *WorkerJobRunRepository) ListWorkerJobRunsByStatus(ctx context.Context, status models.WorkerJobRunStatus, limit int) ([]*models.WorkerJobRun, error) {
+	return repo.list(func(run *models.WorkerJobRun) bool { return run.Status == status }, limit)
+}
+
+func (repo *WorkerJobRunRepository) list(filter func(run *models.WorkerJobRun) bool, limit int) ([]*models.WorkerJobRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := []*models.WorkerJobRun{}
+	for _, run := range repo.runs {
+		if filter(run) {
+			copied := *run
+			res = append(res, &copied)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID > res[j].ID })
+
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// WorkerJobScheduleRepository is an in-memory repository that implements repository.WorkerJobScheduleRepository
+type WorkerJobScheduleRepository struct {
+	canQuery bool
+
+	mu        sync.Mutex
+	schedules map[string]*models.WorkerJobSchedule
+}
+
+// NewWorkerJobScheduleRepository will return errors if canQuery is false
+func NewWorkerJobScheduleRepository(canQuery bool) repository.WorkerJobScheduleRepository {
+	return &WorkerJobScheduleRepository{
+		canQuery:  canQuery,
+		schedules: make(map[string]*models.WorkerJobSchedule),
+	}
+}
+
+// CreateWorkerJobSchedule creates a new worker job schedule
+func (repo *WorkerJobScheduleRepository) CreateWorkerJobSchedule(ctx context.Context, schedule *models.WorkerJobSchedule) (*models.WorkerJobSchedule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.schedules[schedule.JobID]; ok {
+		return nil, errors.New("schedule already exists")
+	}
+
+	schedule.ID = uint(len(repo.schedules) + 1)
+
+	copied := *schedule
+	repo.schedules[schedule.JobID] = &copied
+
+	return schedule, nil
+}
+
+// ReadWorkerJobSchedule reads the schedule of the given job
+func (repo *WorkerJobScheduleRepository) ReadWorkerJobSchedule(ctx context.Context, jobID string) (*models.WorkerJobSchedule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot read database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	schedule, ok := repo.schedules[jobID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	copied := *schedule
+	return &copied, nil
+}
+
+// UpdateWorkerJobSchedule updates a worker job schedule
+func (repo *WorkerJobScheduleRepository) UpdateWorkerJobSchedule(ctx context.Context, schedule *models.WorkerJobSchedule) (*models.WorkerJobSchedule, error) {
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.schedules[schedule.JobID]; !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	copied := *schedule
+	repo.schedules[schedule.JobID] = &copied
+
+	return schedule, nil
+}
+
+// ClaimWorkerJobSchedule moves a schedule which is due at the given time on to its next run
+func (repo *WorkerJobScheduleRepository) ClaimWorkerJobSchedule(ctx context.Context, jobID string, now time.Time, nextRunAt time.Time) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	schedule, ok := repo.schedules[jobID]
+	if !ok || schedule.NextRunAt.After(now) {
+		return false, nil
+	}
+
+	schedule.LastRunAt = &now
+	schedule.NextRunAt = nextRunAt
+
+	return true, nil
+}
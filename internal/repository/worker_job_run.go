@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// WorkerJobRunRepository represents the set of queries on the WorkerJobRun model
+type WorkerJobRunRepository interface {
+	CreateWorkerJobRun(ctx context.Context, run *models.WorkerJobRun) (*models.WorkerJobRun, error)
+	ReadWorkerJobRun(ctx context.Context, id uint) (*models.WorkerJobRun, error)
+	UpdateWorkerJobRun(ctx context.Context, run *models.WorkerJobRun) (*models.WorkerJobRun, error)
+	ClaimWorkerJobRun(ctx context.Context, workerID string, now time.Time, lockedUntil time.Time) (*models.WorkerJobRun, error)
+	ExtendWorkerJobRunLock(ctx context.Context, id uint, workerID string, lockedUntil time.Time) error
+	FinishWorkerJobRun(ctx context.Context, run *models.WorkerJobRun, workerID string) error
+	ListWorkerJobRunsByJobID(ctx context.Context, jobID string, limit int) ([]*models.WorkerJobRun, error)
+	ListWorkerJobRunsByStatus(ctx context.Context, status models.WorkerJobRunStatus, limit int) ([]*models.WorkerJobRun, error)
+}
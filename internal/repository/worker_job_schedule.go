@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// WorkerJobScheduleRepository represents the set of queries on the WorkerJobSchedule model
+type WorkerJobScheduleRepository interface {
+	CreateWorkerJobSchedule(ctx context.Context, schedule *models.WorkerJobSchedule) (*models.WorkerJobSchedule, error)
+	ReadWorkerJobSchedule(ctx context.Context, jobID string) (*models.WorkerJobSchedule, error)
+	UpdateWorkerJobSchedule(ctx context.Context, schedule *models.WorkerJobSchedule) (*models.WorkerJobSchedule, error)
+	ClaimWorkerJobSchedule(ctx context.Context, jobID string, now time.Time, nextRunAt time.Time) (bool, error)
+}
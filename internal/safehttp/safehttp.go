@@ -0,0 +1,98 @@
+// Package safehttp provides http clients for requests to urls which are configured by users, such as identity
+// providers, webhooks and storage endpoints, so that the server cannot be made to request internal services.
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, which is not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// NewClient returns a client which only makes https requests to public addresses. Addresses are checked when
+// connecting, after the name is resolved, and redirects are checked the same way.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: NewTransport(timeout),
+	}
+}
+
+// NewTransport returns the transport of NewClient, for clients which set their own timeouts or wrap the transport
+func NewTransport(dialTimeout time.Duration) http.RoundTripper {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return fmt.Errorf("address %s is not a public address", host)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return httpsOnlyTransport{transport}
+}
+
+// httpsOnlyTransport rejects requests which are not made over https, including redirects
+type httpsOnlyTransport struct {
+	next http.RoundTripper
+}
+
+func (t httpsOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return nil, errors.New("urls must use https")
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// IsPublicIP returns false for loopback, private, link-local, unspecified and multicast addresses
+func IsPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// ValidateURL checks that a user-supplied url uses https, and that its host is not a non-public ip address. Host
+// names are resolved and checked when connecting.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if u.Scheme != "https" {
+		return errors.New("urls must use https")
+	}
+
+	if u.Hostname() == "" {
+		return errors.New("url must have a host")
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !IsPublicIP(ip) {
+		return fmt.Errorf("address %s is not a public address", u.Hostname())
+	}
+
+	return nil
+}
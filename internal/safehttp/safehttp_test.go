@@ -0,0 +1,59 @@
+package safehttp
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientRejectsPrivateAddresses(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(time.Second).Get(server.URL)
+	assert.ErrorContains(t, err, "is not a public address")
+
+	_, err = NewClient(time.Second).Get("http://example.com")
+	assert.ErrorContains(t, err, "must use https")
+}
+
+func TestValidateURL(t *testing.T) {
+	for rawURL, valid := range map[string]bool{
+		"https://hooks.example.com/porter": true,
+		"https://8.8.8.8/porter":           true,
+		"http://hooks.example.com/porter":  false,
+		"https://127.0.0.1:8080/porter":    false,
+		"https://169.254.169.254/latest":   false,
+		"https://[::1]/porter":             false,
+		"https:///porter":                  false,
+		"hooks.example.com":                false,
+	} {
+		assert.Equal(t, valid, ValidateURL(rawURL) == nil, rawURL)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fd00::1":          false,
+		"fe80::1":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		assert.Equal(t, public, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+}
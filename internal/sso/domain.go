@@ -0,0 +1,45 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/encryption"
+)
+
+// TXTResolver looks up the TXT records of a domain name. It is implemented by *net.Resolver.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// ErrDomainNotVerified is returned if the verification record of a domain does not contain its token
+var ErrDomainNotVerified = errors.New("domain verification record not found")
+
+// NewDomainVerificationToken returns a random token which the owner of a domain publishes in its
+// verification record
+func NewDomainVerificationToken() (string, error) {
+	token, err := encryption.GenerateRandomBytes(16)
+	if err != nil {
+		return "", err
+	}
+
+	return "porter-verification=" + token, nil
+}
+
+// VerifyDomain checks that the verification record of the domain contains the token
+func VerifyDomain(ctx context.Context, resolver TXTResolver, domain, token string) error {
+	records, err := resolver.LookupTXT(ctx, types.SSODomainVerificationRecord(domain))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrDomainNotVerified, err.Error())
+	}
+
+	for _, record := range records {
+		if record == token {
+			return nil
+		}
+	}
+
+	return ErrDomainNotVerified
+}
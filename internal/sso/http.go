@@ -0,0 +1,22 @@
+package sso
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/internal/safehttp"
+)
+
+// idpRequestTimeout bounds requests to identity providers
+const idpRequestTimeout = 10 * time.Second
+
+// NewIdPHTTPClient returns a client for requests to identity providers which are configured by project admins.
+// Unless private addresses are allowed, the client only makes https requests to public addresses, so that the
+// server cannot be made to request internal services.
+func NewIdPHTTPClient(allowPrivateAddresses bool) *http.Client {
+	if allowPrivateAddresses {
+		return &http.Client{Timeout: idpRequestTimeout}
+	}
+
+	return safehttp.NewClient(idpRequestTimeout)
+}
@@ -0,0 +1,365 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+
+	"github.com/porter-dev/porter/internal/sso"
+)
+
+// DefaultGroupsClaim is the id token claim which holds the groups of a user if none is configured
+const DefaultGroupsClaim = "groups"
+
+// DefaultScopes are the scopes requested if none are configured
+var DefaultScopes = []string{"openid", "email", "profile", "groups"}
+
+// Config is the configuration of a generic OpenID Connect identity provider
+type Config struct {
+	// IssuerURL is the issuer of the identity provider, which serves /.well-known/openid-configuration
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the callback url registered with the identity provider
+	RedirectURL string
+
+	Scopes      []string
+	GroupsClaim string
+
+	// HTTPClient is used for all requests to the identity provider, and defaults to a client with a timeout
+	HTTPClient *http.Client
+}
+
+// Provider authenticates users against an OpenID Connect identity provider using the authorization code flow
+type Provider struct {
+	config    Config
+	client    *http.Client
+	discovery discoveryDocument
+	oauthConf *oauth2.Config
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider reads the discovery document of the identity provider and returns a Provider
+func NewProvider(ctx context.Context, conf Config) (*Provider, error) {
+	if conf.IssuerURL == "" || conf.ClientID == "" {
+		return nil, errors.New("oidc issuer url and client id are required")
+	}
+
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = DefaultScopes
+	}
+
+	if conf.GroupsClaim == "" {
+		conf.GroupsClaim = DefaultGroupsClaim
+	}
+
+	client := conf.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	p := &Provider{
+		config: conf,
+		client: client,
+	}
+
+	discoveryURL := strings.TrimSuffix(conf.IssuerURL, "/") + "/.well-known/openid-configuration"
+
+	if err := p.getJSON(ctx, discoveryURL, "", &p.discovery); err != nil {
+		return nil, fmt.Errorf("error reading oidc discovery document: %w", err)
+	}
+
+	// the issuer in the discovery document must match the configured issuer, so that tokens from
+	// a different issuer are not accepted
+	if strings.TrimSuffix(p.discovery.Issuer, "/") != strings.TrimSuffix(conf.IssuerURL, "/") {
+		return nil, fmt.Errorf("oidc issuer %s does not match the configured issuer %s", p.discovery.Issuer, conf.IssuerURL)
+	}
+
+	p.oauthConf = &oauth2.Config{
+		ClientID:     conf.ClientID,
+		ClientSecret: conf.ClientSecret,
+		RedirectURL:  conf.RedirectURL,
+		Scopes:       conf.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.discovery.AuthorizationEndpoint,
+			TokenURL: p.discovery.TokenEndpoint,
+		},
+	}
+
+	return p, nil
+}
+
+// AuthCodeURL returns the url which starts a login at the identity provider
+func (p *Provider) AuthCodeURL(state, nonce string) string {
+	return p.oauthConf.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+}
+
+// Exchange exchanges the authorization code for an id token, verifies the id token and returns the identity it asserts
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (*sso.Identity, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+
+	token, err := p.oauthConf.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response does not contain an id token")
+	}
+
+	claims, err := p.VerifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := p.identityFromClaims(claims)
+
+	// some identity providers only return the email from the userinfo endpoint
+	if identity.Email == "" && p.discovery.UserinfoEndpoint != "" {
+		userinfo := jwt.MapClaims{}
+
+		if err := p.getJSON(ctx, p.discovery.UserinfoEndpoint, token.AccessToken, &userinfo); err != nil {
+			return nil, fmt.Errorf("error reading userinfo: %w", err)
+		}
+
+		if sub, _ := userinfo["sub"].(string); sub != identity.Subject {
+			return nil, errors.New("userinfo subject does not match the id token")
+		}
+
+		fromUserinfo := p.identityFromClaims(userinfo)
+		identity.Email = fromUserinfo.Email
+		identity.EmailVerified = fromUserinfo.EmailVerified
+
+		if len(identity.Groups) == 0 {
+			identity.Groups = fromUserinfo.Groups
+		}
+	}
+
+	if identity.Email == "" {
+		return nil, errors.New("identity provider did not return an email for the user")
+	}
+
+	return identity, nil
+}
+
+// VerifyIDToken verifies the signature, issuer, audience, expiry and nonce of an id token and returns its claims
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	keys, err := p.signingKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+
+		// identity providers with a single signing key may not set a key id
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+
+		return nil, fmt.Errorf("no signing key found for key id %q", kid)
+	}, jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if !claims.VerifyIssuer(p.discovery.Issuer, true) {
+		return nil, errors.New("id token was issued by a different issuer")
+	}
+
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.New("id token was issued for a different client")
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("id token does not expire")
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); nonce == "" || tokenNonce != nonce {
+		return nil, errors.New("id token nonce does not match the login request")
+	}
+
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, errors.New("id token does not have a subject")
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) identityFromClaims(claims jwt.MapClaims) *sso.Identity {
+	identity := &sso.Identity{}
+
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.FirstName, _ = claims["given_name"].(string)
+	identity.LastName, _ = claims["family_name"].(string)
+
+	// some identity providers encode email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+
+	switch groups := claims[p.config.GroupsClaim].(type) {
+	case []interface{}:
+		for _, group := range groups {
+			if s, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, s)
+			}
+		}
+	case string:
+		identity.Groups = []string{groups}
+	}
+
+	return identity
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// signingKeys returns the public keys of the identity provider by key id
+func (p *Provider) signingKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+
+	if err := p.getJSON(ctx, p.discovery.JWKSURI, "", &jwks); err != nil {
+		return nil, fmt.Errorf("error reading oidc signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			// skip keys of unsupported types rather than failing every login
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("identity provider has no supported signing keys")
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+func (p *Provider) getJSON(ctx context.Context, url, accessToken string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", res.StatusCode, url)
+	}
+
+	return json.NewDecoder(res.Body).Decode(target)
+}
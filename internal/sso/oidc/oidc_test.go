@@ -0,0 +1,239 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/porter-dev/porter/internal/sso/oidc"
+)
+
+// mockIdP is a local OpenID Connect identity provider which issues an id token with the configured claims
+// for the authorization code "code"
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+
+	// userinfo is returned from the userinfo endpoint
+	userinfo map[string]interface{}
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	idp := &mockIdP{key: key}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"userinfo_endpoint":      idp.server.URL + "/userinfo",
+			"jwks_uri":               idp.server.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kid": "key-1",
+					"kty": "RSA",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+		token.Header["kid"] = "key-1"
+
+		signed, err := token.SignedString(idp.key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     signed,
+		})
+	})
+
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		json.NewEncoder(w).Encode(idp.userinfo)
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	idp.claims = jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"aud":            "porter",
+		"sub":            "user-1",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          "nonce",
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"given_name":     "Jane",
+		"family_name":    "Doe",
+		"groups":         []string{"porter-admins", "engineering"},
+	}
+
+	return idp
+}
+
+func newProvider(t *testing.T, idp *mockIdP) *oidc.Provider {
+	provider, err := oidc.NewProvider(context.Background(), oidc.Config{
+		IssuerURL:    idp.server.URL,
+		ClientID:     "porter",
+		ClientSecret: "secret",
+		RedirectURL:  "https://porter.example.com/api/sso/oidc/oidc/callback",
+	})
+	assert.NoError(t, err)
+
+	return provider
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newProvider(t, idp)
+
+	authURL, err := url.Parse(provider.AuthCodeURL("state", "nonce"))
+	assert.NoError(t, err)
+
+	query := authURL.Query()
+	assert.Equal(t, idp.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t, "porter", query.Get("client_id"))
+	assert.Equal(t, "state", query.Get("state"))
+	assert.Equal(t, "nonce", query.Get("nonce"))
+	assert.Equal(t, "openid email profile groups", query.Get("scope"))
+}
+
+func TestExchange(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newProvider(t, idp)
+
+	identity, err := provider.Exchange(context.Background(), "code", "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", identity.Subject)
+	assert.Equal(t, "jane@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Jane", identity.FirstName)
+	assert.Equal(t, "Doe", identity.LastName)
+	assert.Equal(t, []string{"porter-admins", "engineering"}, identity.Groups)
+}
+
+func TestExchangeUserinfoFallback(t *testing.T) {
+	idp := newMockIdP(t)
+	delete(idp.claims, "email")
+	delete(idp.claims, "email_verified")
+
+	idp.userinfo = map[string]interface{}{
+		"sub":            "user-1",
+		"email":          "jane@example.com",
+		"email_verified": "true",
+	}
+
+	provider := newProvider(t, idp)
+
+	identity, err := provider.Exchange(context.Background(), "code", "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+
+	// the userinfo must describe the same user as the id token
+	idp.userinfo["sub"] = "user-2"
+
+	_, err = provider.Exchange(context.Background(), "code", "nonce")
+	assert.Error(t, err)
+}
+
+func TestExchangeRejectsInvalidTokens(t *testing.T) {
+	tests := map[string]struct {
+		mutate func(idp *mockIdP)
+		nonce  string
+	}{
+		"wrong nonce": {
+			mutate: func(idp *mockIdP) {},
+			nonce:  "other-nonce",
+		},
+		"wrong audience": {
+			mutate: func(idp *mockIdP) { idp.claims["aud"] = "other-client" },
+			nonce:  "nonce",
+		},
+		"wrong issuer": {
+			mutate: func(idp *mockIdP) { idp.claims["iss"] = "https://evil.example.com" },
+			nonce:  "nonce",
+		},
+		"expired": {
+			mutate: func(idp *mockIdP) { idp.claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+			nonce:  "nonce",
+		},
+		"no expiry": {
+			mutate: func(idp *mockIdP) { delete(idp.claims, "exp") },
+			nonce:  "nonce",
+		},
+		"signed by another key": {
+			mutate: func(idp *mockIdP) {
+				key, _ := rsa.GenerateKey(rand.Reader, 2048)
+				idp.key = key
+			},
+			nonce: "nonce",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			idp := newMockIdP(t)
+			provider := newProvider(t, idp)
+
+			test.mutate(idp)
+
+			_, err := provider.Exchange(context.Background(), "code", test.nonce)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewProviderIssuerMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+
+	_, err := oidc.NewProvider(context.Background(), oidc.Config{
+		IssuerURL: idp.server.URL + "/other",
+		ClientID:  "porter",
+	})
+	assert.Error(t, err)
+}
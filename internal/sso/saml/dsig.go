@@ -0,0 +1,121 @@
+package saml
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+// verifyResponse verifies the enveloped signatures of a response and of its assertion against the certificates of
+// the identity provider, and returns the response and assertion as they were signed. Either the response or the
+// assertion must be signed, and a signature which is present must be valid. Keys embedded in a signature are only
+// accepted if they are one of the certificates of the identity provider.
+func (sp *ServiceProvider) verifyResponse(doc []byte) (*element, *element, error) {
+	etreeDoc := etree.NewDocument()
+	if err := etreeDoc.ReadFromBytes(doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid xml: %w", err)
+	}
+
+	response := etreeDoc.Root()
+	if response == nil {
+		return nil, nil, errors.New("invalid xml: incomplete document")
+	}
+
+	responseSigned := false
+
+	verified, err := sp.verifySignature(response)
+	switch {
+	case err == nil:
+		response = verified
+		responseSigned = true
+	case !errors.Is(err, dsig.ErrMissingSignature):
+		return nil, nil, fmt.Errorf("invalid saml response signature: %w", err)
+	}
+
+	var assertions []*etree.Element
+	for _, child := range response.ChildElements() {
+		if child.Tag == "Assertion" && child.NamespaceURI() == assertionNamespace {
+			assertions = append(assertions, child)
+		}
+	}
+
+	if len(assertions) != 1 {
+		return nil, nil, errors.New("saml response must contain exactly one assertion")
+	}
+
+	assertion := assertions[0]
+
+	verified, err = sp.verifySignature(assertion)
+	switch {
+	case err == nil:
+		assertion = verified
+	case !errors.Is(err, dsig.ErrMissingSignature):
+		return nil, nil, fmt.Errorf("invalid saml assertion signature: %w", err)
+	case !responseSigned:
+		return nil, nil, errors.New("saml response is not signed")
+	}
+
+	responseEl, err := fromEtree(response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assertionEl, err := fromEtree(assertion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return responseEl, assertionEl, nil
+}
+
+// verifySignature verifies the enveloped signature of the element, and returns the element as it was signed.
+// dsig.ErrMissingSignature is returned if no signature references the element.
+func (sp *ServiceProvider) verifySignature(el *etree.Element) (*etree.Element, error) {
+	detached, err := detach(el)
+	if err != nil {
+		return nil, err
+	}
+
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: sp.IdPCertificates,
+	})
+	validationContext.Clock = dsig.NewFakeClockAt(sp.now())
+
+	return validationContext.Validate(detached)
+}
+
+// detach copies the element out of its document, declaring the namespaces of its ancestors on the copy
+func detach(el *etree.Element) (*etree.Element, error) {
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = ctx.SubContext(el)
+	if err != nil {
+		return nil, err
+	}
+
+	return etreeutils.NSDetatch(ctx, el)
+}
+
+// fromEtree converts a verified element into an element which keeps the prefixes of the document
+func fromEtree(el *etree.Element) (*element, error) {
+	detached, err := detach(el)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(detached)
+
+	b, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseXML(b)
+}
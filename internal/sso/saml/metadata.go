@@ -0,0 +1,209 @@
+package saml
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const metadataNamespace = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+// maxMetadataSize is the largest identity provider metadata document which is read
+const maxMetadataSize = 1 << 20
+
+// Metadata returns the metadata of the service provider, which is registered with the identity provider
+func (sp *ServiceProvider) Metadata() []byte {
+	return []byte(fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<md:EntityDescriptor xmlns:md="%s" entityID="%s">`+
+			`<md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%s">`+
+			`<md:NameIDFormat>%s</md:NameIDFormat>`+
+			`<md:AssertionConsumerService Binding="%s" Location="%s" index="0" isDefault="true"></md:AssertionConsumerService>`+
+			`</md:SPSSODescriptor>`+
+			`</md:EntityDescriptor>`,
+		metadataNamespace,
+		escapeAttr(sp.EntityID),
+		protocolNamespace,
+		emailAddressNameIDFormat,
+		httpPostBinding,
+		escapeAttr(sp.ACSURL),
+	))
+}
+
+// IdPMetadata is the part of the metadata of an identity provider needed to authenticate users against it
+type IdPMetadata struct {
+	EntityID     string
+	SSOURL       string
+	Certificates []*x509.Certificate
+}
+
+type entityDescriptor struct {
+	EntityID         string `xml:"entityID,attr"`
+	IDPSSODescriptor *struct {
+		KeyDescriptors []struct {
+			Use              string   `xml:"use,attr"`
+			X509Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+		} `xml:"KeyDescriptor"`
+		SingleSignOnServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"SingleSignOnService"`
+	} `xml:"IDPSSODescriptor"`
+}
+
+// ParseIdPMetadata reads the entity id, the HTTP-Redirect single sign-on url and the signing certificates
+// from the metadata of an identity provider
+func ParseIdPMetadata(doc []byte) (*IdPMetadata, error) {
+	// the document is either an EntityDescriptor, or an EntitiesDescriptor which is read through its first EntityDescriptor
+	var metadata struct {
+		XMLName xml.Name
+		entityDescriptor
+		EntityDescriptors []entityDescriptor `xml:"EntityDescriptor"`
+	}
+
+	if err := xml.Unmarshal(doc, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid identity provider metadata: %w", err)
+	}
+
+	descriptor := metadata.entityDescriptor
+
+	switch metadata.XMLName.Local {
+	case "EntityDescriptor":
+	case "EntitiesDescriptor":
+		if len(metadata.EntityDescriptors) == 0 {
+			return nil, errors.New("identity provider metadata has no entity descriptor")
+		}
+
+		descriptor = metadata.EntityDescriptors[0]
+	default:
+		return nil, errors.New("identity provider metadata has no entity descriptor")
+	}
+
+	if descriptor.IDPSSODescriptor == nil {
+		return nil, errors.New("identity provider metadata has no IDPSSODescriptor")
+	}
+
+	res := &IdPMetadata{
+		EntityID: descriptor.EntityID,
+	}
+
+	for _, service := range descriptor.IDPSSODescriptor.SingleSignOnServices {
+		if service.Binding == httpRedirectBinding {
+			res.SSOURL = service.Location
+			break
+		}
+	}
+
+	if res.SSOURL == "" {
+		return nil, errors.New("identity provider does not support the HTTP-Redirect binding")
+	}
+
+	for _, keyDescriptor := range descriptor.IDPSSODescriptor.KeyDescriptors {
+		if keyDescriptor.Use != "" && keyDescriptor.Use != "signing" {
+			continue
+		}
+
+		for _, encoded := range keyDescriptor.X509Certificates {
+			der, err := decodeBase64(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid identity provider certificate: %w", err)
+			}
+
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("invalid identity provider certificate: %w", err)
+			}
+
+			res.Certificates = append(res.Certificates, cert)
+		}
+	}
+
+	if len(res.Certificates) == 0 {
+		return nil, errors.New("identity provider metadata has no signing certificate")
+	}
+
+	return res, nil
+}
+
+// FetchIdPMetadata downloads and parses the metadata of an identity provider with the client
+func FetchIdPMetadata(ctx context.Context, client *http.Client, metadataURL string) (*IdPMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching identity provider metadata: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching identity provider metadata", res.StatusCode)
+	}
+
+	doc, err := io.ReadAll(io.LimitReader(res.Body, maxMetadataSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading identity provider metadata: %w", err)
+	}
+
+	return ParseIdPMetadata(doc)
+}
+
+// ParseCertificates parses PEM-encoded certificates. A single certificate may also be given as bare base64,
+// as it appears in identity provider metadata.
+func ParseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	rest := []byte(strings.TrimSpace(data))
+
+	if !strings.HasPrefix(string(rest), "-----BEGIN") {
+		der, err := decodeBase64(string(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+
+		return []*x509.Certificate{cert}, nil
+	}
+
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+
+	return certs, nil
+}
@@ -0,0 +1,373 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/sso"
+)
+
+const (
+	protocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+
+	statusSuccess            = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	bearerConfirmation       = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	httpPostBinding          = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	httpRedirectBinding      = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	emailAddressNameIDFormat = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+)
+
+// DefaultGroupsAttribute is the assertion attribute which holds the groups of a user if none is configured
+const DefaultGroupsAttribute = "groups"
+
+// maxClockSkew is the clock difference tolerated when checking the validity period of an assertion
+const maxClockSkew = 2 * time.Minute
+
+// the assertion attributes which identity providers commonly use for the email and name of a user
+var (
+	emailAttributes = []string{
+		"email",
+		"mail",
+		"emailaddress",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress",
+		"urn:oid:0.9.2342.19200300.100.1.3",
+	}
+	firstNameAttributes = []string{
+		"firstname",
+		"givenname",
+		"given_name",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname",
+		"urn:oid:2.5.4.42",
+	}
+	lastNameAttributes = []string{
+		"lastname",
+		"surname",
+		"sn",
+		"family_name",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname",
+		"urn:oid:2.5.4.4",
+	}
+)
+
+// ServiceProvider authenticates users against a SAML 2.0 identity provider, using the HTTP-Redirect binding
+// for authentication requests and the HTTP-POST binding for responses. Encrypted assertions are not supported.
+type ServiceProvider struct {
+	// EntityID is the entity id of Porter, which is also the url of its metadata
+	EntityID string
+
+	// ACSURL is the assertion consumer service url which receives responses from the identity provider
+	ACSURL string
+
+	IdPSSOURL   string
+	IdPEntityID string
+
+	// IdPCertificates are the certificates of the identity provider which may sign responses
+	IdPCertificates []*x509.Certificate
+
+	GroupsAttribute string
+
+	// Now returns the current time, and defaults to time.Now
+	Now func() time.Time
+}
+
+// AuthnRequestURL returns the url which starts a login at the identity provider, and the id of the
+// authentication request which the response must be in response to
+func (sp *ServiceProvider) AuthnRequestURL(relayState string) (string, string, error) {
+	idBytes := make([]byte, 20)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", err
+	}
+
+	// ids must not start with a digit
+	requestID := "id-" + hex.EncodeToString(idBytes)
+
+	request := fmt.Sprintf(
+		`<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" IssueInstant="%s" Destination="%s" AssertionConsumerServiceURL="%s" ProtocolBinding="%s">`+
+			`<saml:Issuer>%s</saml:Issuer>`+
+			`<samlp:NameIDPolicy AllowCreate="true"></samlp:NameIDPolicy>`+
+			`</samlp:AuthnRequest>`,
+		protocolNamespace,
+		assertionNamespace,
+		requestID,
+		sp.now().UTC().Format(time.RFC3339),
+		escapeAttr(sp.IdPSSOURL),
+		escapeAttr(sp.ACSURL),
+		httpPostBinding,
+		escapeText(sp.EntityID),
+	)
+
+	var buf bytes.Buffer
+
+	writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := writer.Write([]byte(request)); err != nil {
+		return "", "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", "", err
+	}
+
+	ssoURL, err := url.Parse(sp.IdPSSOURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid identity provider sso url: %w", err)
+	}
+
+	query := ssoURL.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+	if relayState != "" {
+		query.Set("RelayState", relayState)
+	}
+
+	ssoURL.RawQuery = query.Encode()
+
+	return ssoURL.String(), requestID, nil
+}
+
+// ParseResponse verifies a base64-encoded response posted by the identity provider, which must be in response
+// to the authentication request with the id, and returns the identity it asserts
+func (sp *ServiceProvider) ParseResponse(encodedResponse, requestID string) (*sso.Identity, error) {
+	if requestID == "" {
+		return nil, errors.New("no authentication request is in progress")
+	}
+
+	doc, err := decodeBase64(encodedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("invalid saml response encoding: %w", err)
+	}
+
+	// documents with a DTD are rejected before the signatures are verified
+	unverified, err := parseXML(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !unverified.is(protocolNamespace, "Response") {
+		return nil, errors.New("document is not a saml response")
+	}
+
+	status := unverified.child(protocolNamespace, "Status")
+	if status == nil {
+		return nil, errors.New("saml response has no status")
+	}
+
+	if code := status.child(protocolNamespace, "StatusCode"); code == nil || code.attr("Value") != statusSuccess {
+		message := ""
+		if statusMessage := status.child(protocolNamespace, "StatusMessage"); statusMessage != nil {
+			message = statusMessage.text()
+		}
+
+		return nil, fmt.Errorf("identity provider did not authenticate the user: %s", message)
+	}
+
+	if len(unverified.childElements(assertionNamespace, "EncryptedAssertion")) > 0 {
+		return nil, errors.New("encrypted saml assertions are not supported")
+	}
+
+	// the response and assertion are read as they were signed from here on
+	response, assertion, err := sp.verifyResponse(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.attr("Version") != "2.0" {
+		return nil, errors.New("unsupported saml version")
+	}
+
+	if response.attr("InResponseTo") != requestID {
+		return nil, errors.New("saml response is not in response to the login request")
+	}
+
+	if destination := response.attr("Destination"); destination != "" && destination != sp.ACSURL {
+		return nil, errors.New("saml response was sent to a different destination")
+	}
+
+	if issuer := response.child(assertionNamespace, "Issuer"); issuer != nil && issuer.text() != sp.IdPEntityID {
+		return nil, errors.New("saml response was issued by a different identity provider")
+	}
+
+	if err := sp.validateAssertion(assertion, requestID); err != nil {
+		return nil, err
+	}
+
+	return sp.identityFromAssertion(assertion)
+}
+
+// validateAssertion checks the issuer, validity period, audience and subject confirmation of an assertion
+func (sp *ServiceProvider) validateAssertion(assertion *element, requestID string) error {
+	now := sp.now()
+
+	issuer := assertion.child(assertionNamespace, "Issuer")
+	if issuer == nil || issuer.text() != sp.IdPEntityID {
+		return errors.New("saml assertion was issued by a different identity provider")
+	}
+
+	conditions := assertion.child(assertionNamespace, "Conditions")
+	if conditions == nil {
+		return errors.New("saml assertion has no conditions")
+	}
+
+	if err := checkValidityPeriod(conditions.attr("NotBefore"), conditions.attr("NotOnOrAfter"), now); err != nil {
+		return fmt.Errorf("saml assertion is not valid: %w", err)
+	}
+
+	audienceRestrictions := conditions.childElements(assertionNamespace, "AudienceRestriction")
+	if len(audienceRestrictions) == 0 {
+		return errors.New("saml assertion has no audience restriction")
+	}
+
+	// every audience restriction must be satisfied
+	for _, restriction := range audienceRestrictions {
+		found := false
+
+		for _, audience := range restriction.childElements(assertionNamespace, "Audience") {
+			if audience.text() == sp.EntityID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return errors.New("saml assertion is intended for a different audience")
+		}
+	}
+
+	subject := assertion.child(assertionNamespace, "Subject")
+	if subject == nil {
+		return errors.New("saml assertion has no subject")
+	}
+
+	for _, confirmation := range subject.childElements(assertionNamespace, "SubjectConfirmation") {
+		if confirmation.attr("Method") != bearerConfirmation {
+			continue
+		}
+
+		data := confirmation.child(assertionNamespace, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+
+		if data.attr("Recipient") != sp.ACSURL {
+			continue
+		}
+
+		if inResponseTo := data.attr("InResponseTo"); inResponseTo != "" && inResponseTo != requestID {
+			continue
+		}
+
+		if data.attr("NotOnOrAfter") == "" || checkValidityPeriod(data.attr("NotBefore"), data.attr("NotOnOrAfter"), now) != nil {
+			continue
+		}
+
+		return nil
+	}
+
+	return errors.New("saml assertion has no valid bearer subject confirmation")
+}
+
+func (sp *ServiceProvider) identityFromAssertion(assertion *element) (*sso.Identity, error) {
+	identity := &sso.Identity{
+		// the identity provider vouches for the email of the user
+		EmailVerified: true,
+	}
+
+	nameID := assertion.child(assertionNamespace, "Subject").child(assertionNamespace, "NameID")
+	if nameID == nil || nameID.text() == "" {
+		return nil, errors.New("saml assertion has no name id")
+	}
+
+	identity.Subject = nameID.text()
+
+	attributes := make(map[string][]string)
+
+	for _, statement := range assertion.childElements(assertionNamespace, "AttributeStatement") {
+		for _, attribute := range statement.childElements(assertionNamespace, "Attribute") {
+			name := strings.ToLower(attribute.attr("Name"))
+
+			for _, value := range attribute.childElements(assertionNamespace, "AttributeValue") {
+				if text := value.text(); text != "" {
+					attributes[name] = append(attributes[name], text)
+				}
+			}
+		}
+	}
+
+	first := func(names []string) string {
+		for _, name := range names {
+			if values := attributes[name]; len(values) > 0 {
+				return values[0]
+			}
+		}
+
+		return ""
+	}
+
+	identity.Email = first(emailAttributes)
+	if identity.Email == "" && (nameID.attr("Format") == emailAddressNameIDFormat || strings.Contains(identity.Subject, "@")) {
+		identity.Email = identity.Subject
+	}
+
+	if identity.Email == "" {
+		return nil, errors.New("saml assertion does not contain an email for the user")
+	}
+
+	identity.FirstName = first(firstNameAttributes)
+	identity.LastName = first(lastNameAttributes)
+
+	groupsAttribute := sp.GroupsAttribute
+	if groupsAttribute == "" {
+		groupsAttribute = DefaultGroupsAttribute
+	}
+
+	identity.Groups = attributes[strings.ToLower(groupsAttribute)]
+
+	return identity, nil
+}
+
+func (sp *ServiceProvider) now() time.Time {
+	if sp.Now != nil {
+		return sp.Now()
+	}
+
+	return time.Now()
+}
+
+func checkValidityPeriod(notBefore, notOnOrAfter string, now time.Time) error {
+	if notBefore != "" {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return fmt.Errorf("invalid NotBefore: %w", err)
+		}
+
+		if now.Add(maxClockSkew).Before(t) {
+			return errors.New("not valid yet")
+		}
+	}
+
+	if notOnOrAfter != "" {
+		t, err := time.Parse(time.RFC3339, notOnOrAfter)
+		if err != nil {
+			return fmt.Errorf("invalid NotOnOrAfter: %w", err)
+		}
+
+		if !now.Add(-maxClockSkew).Before(t) {
+			return errors.New("expired")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package saml
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseXMLRejectsDTD(t *testing.T) {
+	_, err := parseXML([]byte(`<!DOCTYPE foo [<!ENTITY x "y">]><foo>&x;</foo>`))
+	assert.Error(t, err)
+}
+
+// mockIdP signs saml responses like an identity provider
+type mockIdP struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mock-idp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &mockIdP{key: key, cert: cert}
+}
+
+type responseOpts struct {
+	requestID    string
+	recipient    string
+	audience     string
+	email        string
+	groups       []string
+	notOnOrAfter time.Time
+}
+
+func (idp *mockIdP) assertion(opts responseOpts) string {
+	var groups strings.Builder
+	for _, group := range opts.groups {
+		groups.WriteString(fmt.Sprintf(`<saml:AttributeValue xsi:type="xs:string">%s</saml:AttributeValue>`, group))
+	}
+
+	return fmt.Sprintf(`<saml:Assertion xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ID="assertion-1" Version="2.0" IssueInstant="2023-10-01T00:00:00Z">`+
+		`<saml:Issuer>https://idp.example.com</saml:Issuer>`+
+		`<saml:Subject>`+
+		`<saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">%s</saml:NameID>`+
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">`+
+		`<saml:SubjectConfirmationData InResponseTo="%s" NotOnOrAfter="%s" Recipient="%s"/>`+
+		`</saml:SubjectConfirmation>`+
+		`</saml:Subject>`+
+		`<saml:Conditions NotBefore="2023-10-01T00:00:00Z" NotOnOrAfter="%s">`+
+		`<saml:AudienceRestriction><saml:Audience>%s</saml:Audience></saml:AudienceRestriction>`+
+		`</saml:Conditions>`+
+		`<saml:AttributeStatement>`+
+		`<saml:Attribute Name="firstName"><saml:AttributeValue xsi:type="xs:string">Jane</saml:AttributeValue></saml:Attribute>`+
+		`<saml:Attribute Name="groups">%s</saml:Attribute>`+
+		`</saml:AttributeStatement>`+
+		`</saml:Assertion>`,
+		opts.email,
+		opts.requestID,
+		opts.notOnOrAfter.UTC().Format(time.RFC3339),
+		opts.recipient,
+		opts.notOnOrAfter.UTC().Format(time.RFC3339),
+		opts.audience,
+		groups.String(),
+	)
+}
+
+func (idp *mockIdP) response(opts responseOpts, assertion string) string {
+	return fmt.Sprintf(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="response-1" Version="2.0" InResponseTo="%s" Destination="%s">`+
+		`<saml:Issuer>https://idp.example.com</saml:Issuer>`+
+		`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>`+
+		`%s`+
+		`</samlp:Response>`,
+		opts.requestID,
+		opts.recipient,
+		assertion,
+	)
+}
+
+// sign inserts an enveloped signature of the element with the id into the response, after the issuer of the
+// element as identity providers do
+func (idp *mockIdP) sign(t *testing.T, doc, id string) string {
+	t.Helper()
+
+	etreeDoc := etree.NewDocument()
+	assert.NoError(t, etreeDoc.ReadFromString(doc))
+
+	target := etreeDoc.FindElement(fmt.Sprintf("//[@ID='%s']", id))
+	assert.NotNil(t, target)
+
+	detached, err := detach(target)
+	assert.NoError(t, err)
+
+	signingContext := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(tls.Certificate{
+		Certificate: [][]byte{idp.cert.Raw},
+		PrivateKey:  idp.key,
+	}))
+	signingContext.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+
+	signed, err := signingContext.SignEnveloped(detached)
+	assert.NoError(t, err)
+
+	// the signature is appended to the element, and moved after its issuer
+	signature := signed.RemoveChildAt(len(signed.Child) - 1)
+	signed.InsertChildAt(signed.SelectElement("Issuer").Index()+1, signature)
+
+	if parent := target.Parent(); parent != nil {
+		parent.InsertChildAt(target.Index(), signed)
+		parent.RemoveChild(target)
+	} else {
+		etreeDoc.SetRoot(signed)
+	}
+
+	res, err := etreeDoc.WriteToString()
+	assert.NoError(t, err)
+
+	return res
+}
+
+func newTestServiceProvider(idp *mockIdP) *ServiceProvider {
+	return &ServiceProvider{
+		EntityID:        "https://porter.example.com/api/sso/saml/saml/metadata",
+		ACSURL:          "https://porter.example.com/api/sso/saml/saml/acs",
+		IdPSSOURL:       "https://idp.example.com/sso",
+		IdPEntityID:     "https://idp.example.com",
+		IdPCertificates: []*x509.Certificate{idp.cert},
+	}
+}
+
+func defaultResponseOpts(sp *ServiceProvider) responseOpts {
+	return responseOpts{
+		requestID:    "id-123",
+		recipient:    sp.ACSURL,
+		audience:     sp.EntityID,
+		email:        "jane@example.com",
+		groups:       []string{"porter-admins", "engineering"},
+		notOnOrAfter: time.Now().Add(5 * time.Minute),
+	}
+}
+
+func encode(doc string) string {
+	return base64.StdEncoding.EncodeToString([]byte(doc))
+}
+
+func TestParseResponseSignedAssertion(t *testing.T) {
+	idp := newMockIdP(t)
+	sp := newTestServiceProvider(idp)
+	opts := defaultResponseOpts(sp)
+
+	doc := idp.sign(t, idp.response(opts, idp.assertion(opts)), "assertion-1")
+
+	identity, err := sp.ParseResponse(encode(doc), "id-123")
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", identity.Subject)
+	assert.Equal(t, "jane@example.com", identity.Email)
+	assert.Equal(t, "Jane", identity.FirstName)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, []string{"porter-admins", "engineering"}, identity.Groups)
+}
+
+func TestParseResponseSignedResponse(t *testing.T) {
+	idp := newMockIdP(t)
+	sp := newTestServiceProvider(idp)
+	opts := defaultResponseOpts(sp)
+
+	doc := idp.sign(t, idp.response(opts, idp.assertion(opts)), "response-1")
+
+	identity, err := sp.ParseResponse(encode(doc), "id-123")
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", identity.Email)
+}
+
+func TestParseResponseRejectsInvalidResponses(t *testing.T) {
+	idp := newMockIdP(t)
+	sp := newTestServiceProvider(idp)
+	opts := defaultResponseOpts(sp)
+
+	signed := idp.sign(t, idp.response(opts, idp.assertion(opts)), "assertion-1")
+
+	otherIdP := newMockIdP(t)
+	expiredOpts := opts
+	expiredOpts.notOnOrAfter = time.Now().Add(-10 * time.Minute)
+	otherAudienceOpts := opts
+	otherAudienceOpts.audience = "https://other.example.com"
+
+	tests := map[string]struct {
+		doc       string
+		requestID string
+	}{
+		"unsigned": {
+			doc:       idp.response(opts, idp.assertion(opts)),
+			requestID: "id-123",
+		},
+		"tampered": {
+			doc:       strings.Replace(signed, "jane@example.com", "admin@example.com", 1),
+			requestID: "id-123",
+		},
+		"signed by another identity provider": {
+			doc:       otherIdP.sign(t, idp.response(opts, idp.assertion(opts)), "assertion-1"),
+			requestID: "id-123",
+		},
+		"different request": {
+			doc:       signed,
+			requestID: "id-456",
+		},
+		"no request in progress": {
+			doc:       signed,
+			requestID: "",
+		},
+		"expired": {
+			doc:       idp.sign(t, idp.response(expiredOpts, idp.assertion(expiredOpts)), "assertion-1"),
+			requestID: "id-123",
+		},
+		"different audience": {
+			doc:       idp.sign(t, idp.response(otherAudienceOpts, idp.assertion(otherAudienceOpts)), "assertion-1"),
+			requestID: "id-123",
+		},
+		"wrapped assertion": {
+			// a signed assertion is moved out of the way and an unsigned assertion is read instead
+			doc:       strings.Replace(signed, "<saml:Assertion ", idp.assertion(opts)+"<saml:Assertion ", 1),
+			requestID: "id-123",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := sp.ParseResponse(encode(test.doc), test.requestID)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAuthnRequestURL(t *testing.T) {
+	idp := newMockIdP(t)
+	sp := newTestServiceProvider(idp)
+
+	loginURL, requestID, err := sp.AuthnRequestURL("state")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(loginURL, "https://idp.example.com/sso?"))
+	assert.Contains(t, loginURL, "SAMLRequest=")
+	assert.Contains(t, loginURL, "RelayState=state")
+	assert.True(t, strings.HasPrefix(requestID, "id-"))
+}
+
+func TestParseIdPMetadata(t *testing.T) {
+	idp := newMockIdP(t)
+
+	doc := `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com">` +
+		`<md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">` +
+		`<md:KeyDescriptor use="signing"><ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data><ds:X509Certificate>` +
+		base64.StdEncoding.EncodeToString(idp.cert.Raw) +
+		`</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>` +
+		`<md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/post"/>` +
+		`<md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>` +
+		`</md:IDPSSODescriptor>` +
+		`</md:EntityDescriptor>`
+
+	metadata, err := ParseIdPMetadata([]byte(doc))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com", metadata.EntityID)
+	assert.Equal(t, "https://idp.example.com/sso", metadata.SSOURL)
+	assert.Len(t, metadata.Certificates, 1)
+}
@@ -0,0 +1,210 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlNamespace is the namespace bound to the reserved "xml" prefix
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// element is an XML element which keeps the prefixes and namespace declarations of the document, which
+// encoding/xml discards but which are needed to resolve the namespaces of elements and attribute values
+type element struct {
+	parent *element
+
+	prefix string
+	local  string
+
+	// attrs holds the attributes of the element, including its namespace declarations
+	attrs []xml.Attr
+
+	// children are *element or xml.CharData
+	children []interface{}
+}
+
+// parseXML parses a document into a tree of elements. Documents with a DTD are rejected.
+func parseXML(doc []byte) (*element, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(doc))
+
+	var root, current *element
+
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid xml: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			el := &element{
+				parent: current,
+				prefix: t.Name.Space,
+				local:  t.Name.Local,
+				attrs:  append([]xml.Attr{}, t.Attr...),
+			}
+
+			if current == nil {
+				if root != nil {
+					return nil, errors.New("invalid xml: multiple root elements")
+				}
+				root = el
+			} else {
+				current.children = append(current.children, el)
+			}
+
+			current = el
+		case xml.EndElement:
+			if current == nil || t.Name.Space != current.prefix || t.Name.Local != current.local {
+				return nil, errors.New("invalid xml: mismatched end element")
+			}
+
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, t.Copy())
+			}
+		case xml.Directive:
+			return nil, errors.New("invalid xml: document type declarations are not allowed")
+		}
+	}
+
+	if root == nil || current != nil {
+		return nil, errors.New("invalid xml: incomplete document")
+	}
+
+	return root, nil
+}
+
+// isNamespaceDecl returns true if the attribute declares a namespace, and the prefix which it declares
+func isNamespaceDecl(attr xml.Attr) (string, bool) {
+	if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+		return "", true
+	}
+
+	if attr.Name.Space == "xmlns" {
+		return attr.Name.Local, true
+	}
+
+	return "", false
+}
+
+// lookupNamespace returns the namespace bound to the prefix in the scope of the element
+func (e *element) lookupNamespace(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespace, true
+	}
+
+	for el := e; el != nil; el = el.parent {
+		for _, attr := range el.attrs {
+			if declared, ok := isNamespaceDecl(attr); ok && declared == prefix {
+				return attr.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// namespace returns the namespace of the element
+func (e *element) namespace() string {
+	ns, _ := e.lookupNamespace(e.prefix)
+	return ns
+}
+
+// is returns true if the element has the namespace and local name
+func (e *element) is(namespace, local string) bool {
+	return e.local == local && e.namespace() == namespace
+}
+
+// attr returns the value of the unqualified attribute with the name
+func (e *element) attr(name string) string {
+	for _, attr := range e.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// childElements returns the child elements with the namespace and local name
+func (e *element) childElements(namespace, local string) []*element {
+	var res []*element
+
+	for _, child := range e.children {
+		if el, ok := child.(*element); ok && el.is(namespace, local) {
+			res = append(res, el)
+		}
+	}
+
+	return res
+}
+
+// child returns the first child element with the namespace and local name, or nil
+func (e *element) child(namespace, local string) *element {
+	children := e.childElements(namespace, local)
+	if len(children) == 0 {
+		return nil
+	}
+
+	return children[0]
+}
+
+// text returns the concatenated character data of the element and its descendants, with surrounding whitespace trimmed
+func (e *element) text() string {
+	var sb strings.Builder
+
+	var walk func(el *element)
+	walk = func(el *element) {
+		for _, child := range el.children {
+			switch c := child.(type) {
+			case xml.CharData:
+				sb.Write(c)
+			case *element:
+				walk(c)
+			}
+		}
+	}
+
+	walk(e)
+
+	return strings.TrimSpace(sb.String())
+}
+
+// decodeBase64 decodes standard base64, ignoring the line breaks and whitespace which identity providers insert
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
+
+var textEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r", "&#xD;",
+)
+
+var attrEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	`"`, "&quot;",
+	"\t", "&#x9;",
+	"\n", "&#xA;",
+	"\r", "&#xD;",
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
@@ -0,0 +1,149 @@
+package sso
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// Identity is a user identity asserted by an identity provider
+type Identity struct {
+	// Subject is the stable id of the user at the identity provider
+	Subject string
+
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+
+	// Groups are the identity provider groups which the user is a member of
+	Groups []string
+}
+
+// GroupRoleMapping grants a role in a project to the members of an identity provider group
+type GroupRoleMapping struct {
+	Group string `json:"group"`
+
+	// ProjectID is the project to grant the role in. It is ignored for connections which belong to a project.
+	ProjectID uint `json:"project_id,omitempty"`
+
+	Role types.RoleKind `json:"role"`
+}
+
+// rolePrecedence orders the roles which can be granted through groups, so that the most privileged role wins
+var rolePrecedence = map[types.RoleKind]int{
+	types.RoleViewer:    1,
+	types.RoleDeveloper: 2,
+	types.RoleAdmin:     3,
+}
+
+// IsMappableRole returns true if the role can be granted through an identity provider group
+func IsMappableRole(role types.RoleKind) bool {
+	_, ok := rolePrecedence[role]
+	return ok
+}
+
+// ProjectRoles returns the most privileged role in each project granted to the groups. Group names are
+// compared case-insensitively.
+func ProjectRoles(mappings []GroupRoleMapping, groups []string) map[uint]types.RoleKind {
+	memberOf := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		memberOf[strings.ToLower(group)] = true
+	}
+
+	res := make(map[uint]types.RoleKind)
+
+	for _, mapping := range mappings {
+		if !memberOf[strings.ToLower(mapping.Group)] || !IsMappableRole(mapping.Role) {
+			continue
+		}
+
+		if current, ok := res[mapping.ProjectID]; !ok || rolePrecedence[mapping.Role] > rolePrecedence[current] {
+			res[mapping.ProjectID] = mapping.Role
+		}
+	}
+
+	return res
+}
+
+// ParseGroupRoleMappings parses a comma-separated list of mappings in the form "group=project_id:role",
+// such as "porter-admins=1:admin,engineering=1:developer"
+func ParseGroupRoleMappings(value string) ([]GroupRoleMapping, error) {
+	res := make([]GroupRoleMapping, 0)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, grant, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid group mapping %q: expected group=project_id:role", entry)
+		}
+
+		projectID, role, ok := strings.Cut(grant, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid group mapping %q: expected group=project_id:role", entry)
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSpace(projectID), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project id in group mapping %q: %w", entry, err)
+		}
+
+		mapping := GroupRoleMapping{
+			Group:     strings.TrimSpace(group),
+			ProjectID: uint(id),
+			Role:      types.RoleKind(strings.TrimSpace(role)),
+		}
+
+		if !IsMappableRole(mapping.Role) {
+			return nil, fmt.Errorf("invalid role in group mapping %q: must be admin, developer or viewer", entry)
+		}
+
+		res = append(res, mapping)
+	}
+
+	return res, nil
+}
+
+// LoginURL returns the url which starts a login through the sso connection. The connection is either
+// the unique id of a project connection, or "oidc" or "saml" for the sso login of the whole instance.
+func LoginURL(serverURL, connection string) string {
+	return connectionURL(serverURL, connection, "start")
+}
+
+// OIDCRedirectURL returns the redirect url of an OIDC connection, which is registered with the identity provider
+func OIDCRedirectURL(serverURL, connection string) string {
+	return connectionURL(serverURL, connection, "oidc/callback")
+}
+
+// SAMLACSURL returns the assertion consumer service url of a SAML connection, which receives the responses
+// of the identity provider
+func SAMLACSURL(serverURL, connection string) string {
+	return connectionURL(serverURL, connection, "saml/acs")
+}
+
+// SAMLMetadataURL returns the url of the service provider metadata of a SAML connection, which is also
+// its entity id
+func SAMLMetadataURL(serverURL, connection string) string {
+	return connectionURL(serverURL, connection, "saml/metadata")
+}
+
+func connectionURL(serverURL, connection, path string) string {
+	return fmt.Sprintf("%s/api/sso/%s/%s", strings.TrimSuffix(serverURL, "/"), url.PathEscape(connection), path)
+}
+
+// EmailDomain returns the lower-cased domain of an email address, or an empty string if the address has no domain
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return ""
+	}
+
+	return strings.ToLower(email[at+1:])
+}
@@ -0,0 +1,60 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := f[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	return records, nil
+}
+
+func TestVerifyDomain(t *testing.T) {
+	token, err := NewDomainVerificationToken()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "porter-verification="))
+
+	resolver := fakeResolver{
+		"_porter-verification.example.com": {"v=spf1 -all", token},
+		"_porter-verification.other.com":   {"porter-verification=other"},
+	}
+
+	assert.NoError(t, VerifyDomain(context.Background(), resolver, "example.com", token))
+	assert.ErrorIs(t, VerifyDomain(context.Background(), resolver, "other.com", token), ErrDomainNotVerified)
+	assert.ErrorIs(t, VerifyDomain(context.Background(), resolver, "missing.com", token), ErrDomainNotVerified)
+
+	// the token must be in the verification record, not the domain itself
+	resolver = fakeResolver{"example.com": {token}}
+	assert.Error(t, VerifyDomain(context.Background(), resolver, "example.com", token))
+}
+
+func TestIdPHTTPClientRejectsPrivateAddresses(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewIdPHTTPClient(false).Get(server.URL)
+	assert.ErrorContains(t, err, "is not a public address")
+
+	_, err = NewIdPHTTPClient(false).Get("http://example.com")
+	assert.ErrorContains(t, err, "must use https")
+
+	// instances with identity providers on an internal network can allow them
+	res, err := NewIdPHTTPClient(true).Get(strings.Replace(server.URL, "https://", "http://", 1))
+	assert.NoError(t, err)
+	res.Body.Close()
+}
@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes the bounds of one field of a cron expression
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var (
+	cronMinute     = cronField{"minute", 0, 59}
+	cronHour       = cronField{"hour", 0, 23}
+	cronDayOfMonth = cronField{"day of month", 1, 31}
+	cronMonth      = cronField{"month", 1, 12}
+	cronDayOfWeek  = cronField{"day of week", 0, 6}
+)
+
+// cronDescriptors are the supported shorthands for common cron expressions
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronSchedule is a parsed cron expression. All times are evaluated in UTC.
+type CronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// dayOfMonthAny and dayOfWeekAny record whether the day fields were "*". As in standard cron, if both
+	// day fields are restricted, a day matches if either of them matches.
+	dayOfMonthAny, dayOfWeekAny bool
+
+	// every is set for "@every <duration>" expressions, which run at a fixed interval instead
+	every time.Duration
+}
+
+// ParseCronSchedule parses a standard five-field cron expression (minute, hour, day of month, month, day of week),
+// one of the descriptors @yearly, @monthly, @weekly, @daily or @hourly, or "@every <duration>".
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration in '%s': %w", expr, err)
+		}
+		if every < time.Minute {
+			return nil, fmt.Errorf("interval in '%s' must be at least one minute", expr)
+		}
+
+		return &CronSchedule{every: every}, nil
+	}
+
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression '%s' must have 5 fields, found %d", expr, len(fields))
+	}
+
+	schedule := &CronSchedule{
+		dayOfMonthAny: fields[2] == "*",
+		dayOfWeekAny:  fields[4] == "*",
+	}
+
+	var err error
+	for i, f := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{cronMinute, &schedule.minute},
+		{cronHour, &schedule.hour},
+		{cronDayOfMonth, &schedule.dayOfMonth},
+		{cronMonth, &schedule.month},
+		{cronDayOfWeek, &schedule.dayOfWeek},
+	} {
+		*f.bits, err = parseCronField(fields[i], f.field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schedule, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps into a bitset
+func parseCronField(value string, field cronField) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(value, ",") {
+		rangePart, step := part, 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field '%s'", field.name, part)
+			}
+			rangePart = part[:i]
+		}
+
+		start, end := field.min, field.max
+
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+
+			var err error
+			start, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid range in %s field '%s'", field.name, part)
+			}
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return 0, fmt.Errorf("invalid range in %s field '%s'", field.name, part)
+			}
+		default:
+			var err error
+			start, err = strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %s field '%s'", field.name, part)
+			}
+
+			// a single value with a step, such as "5/15", runs from the value to the end of the range
+			if step == 1 {
+				end = start
+			}
+		}
+
+		// 7 is accepted as an alias for Sunday in the day of week field
+		if field == cronDayOfWeek && end == 7 {
+			if start == 7 {
+				start, end = 0, 0
+			} else {
+				if (7-start)%step == 0 {
+					set |= 1
+				}
+				end = 6
+			}
+		}
+
+		if start < field.min || end > field.max || start > end {
+			return 0, fmt.Errorf("%s field '%s' is out of range %d-%d", field.name, part, field.min, field.max)
+		}
+
+		for v := start; v <= end; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+// Next returns the first time after t at which the schedule fires
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC()
+
+	if s.every > 0 {
+		return t.Truncate(time.Minute).Add(s.every)
+	}
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// a schedule which never matches (such as February 31st) gives up after a few years
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+			continue
+		}
+
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,365 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// JobFactory builds the job with the given ID from the input it was enqueued with
+type JobFactory func(ctx context.Context, jobID string, input map[string]interface{}) (Job, error)
+
+// PersistentQueueOpts configures the retry and polling behaviour of a PersistentQueue
+type PersistentQueueOpts struct {
+	// MaxAttempts is the default number of attempts after which a failing run is moved to the dead-letter list. It defaults
+	// to 1, since jobs are not necessarily safe to retry.
+	MaxAttempts int
+
+	// MaxAttemptsByJob overrides MaxAttempts for jobs which are safe to retry, keyed by job ID
+	MaxAttemptsByJob map[string]int
+
+	// PollInterval is how often the store is polled for runs which are due
+	PollInterval time.Duration
+
+	// LockDuration is how long a claimed run is locked to this queue. The lock is extended while the run's job is running,
+	// so runs still marked as running after their lock expires, for example because the process restarted, are claimed again.
+	LockDuration time.Duration
+
+	// RetryBackoff is the delay before the first retry, which doubles on every subsequent attempt
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the delay between retries
+	MaxRetryBackoff time.Duration
+}
+
+// PersistentQueue stores enqueued jobs in the database, and feeds them into the job queue of a Dispatcher
+// as workers become available. Failing jobs are retried with exponential backoff until they run out of
+// attempts, at which point they are kept on a dead-letter list.
+type PersistentQueue struct {
+	repo     repository.WorkerJobRunRepository
+	jobQueue chan Job
+	newJob   JobFactory
+	opts     PersistentQueueOpts
+	workerID string
+	exitChan chan bool
+}
+
+// NewPersistentQueue creates a new PersistentQueue which feeds claimed jobs into the given buffered job queue
+func NewPersistentQueue(repo repository.WorkerJobRunRepository, jobQueue chan Job, newJob JobFactory, opts PersistentQueueOpts) (*PersistentQueue, error) {
+	if repo == nil {
+		return nil, errors.New("worker job run repository is nil")
+	}
+	if newJob == nil {
+		return nil, errors.New("job factory is nil")
+	}
+	if cap(jobQueue) == 0 {
+		return nil, errors.New("job queue must be buffered")
+	}
+
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	if opts.LockDuration <= 0 {
+		opts.LockDuration = time.Hour
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 30 * time.Second
+	}
+	if opts.MaxRetryBackoff < opts.RetryBackoff {
+		opts.MaxRetryBackoff = opts.RetryBackoff
+	}
+
+	workerID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("error creating UUID for queue: %w", err)
+	}
+
+	return &PersistentQueue{
+		repo:     repo,
+		jobQueue: jobQueue,
+		newJob:   newJob,
+		opts:     opts,
+		workerID: workerID.String(),
+		exitChan: make(chan bool),
+	}, nil
+}
+
+// Enqueue stores a new run of the given job, which is picked up by the next poll
+func (q *PersistentQueue) Enqueue(ctx context.Context, jobID string, input map[string]interface{}) (*models.WorkerJobRun, error) {
+	return EnqueueJob(ctx, q.repo, EnqueueJobInput{
+		JobID:       jobID,
+		Input:       input,
+		MaxAttempts: q.maxAttempts(jobID),
+	})
+}
+
+// EnqueueJobInput is the input struct for EnqueueJob
+type EnqueueJobInput struct {
+	JobID string
+	Input map[string]interface{}
+
+	// ConcurrencyKey serializes runs: runs with the same non-empty key are run one at a time, in the order they were enqueued
+	ConcurrencyKey string
+
+	// MaxAttempts is the number of attempts of the run. Defaults to 1
+	MaxAttempts int
+}
+
+// EnqueueJob stores a new run of a job in the store of the persistent queue, so that services other than the workers
+// can enqueue jobs. The run is picked up by the next poll of a worker.
+func EnqueueJob(ctx context.Context, repo repository.WorkerJobRunRepository, inp EnqueueJobInput) (*models.WorkerJobRun, error) {
+	inputBytes, err := json.Marshal(inp.Input)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling job input: %w", err)
+	}
+
+	if inp.MaxAttempts <= 0 {
+		inp.MaxAttempts = 1
+	}
+
+	return repo.CreateWorkerJobRun(ctx, &models.WorkerJobRun{
+		JobID:          inp.JobID,
+		Input:          inputBytes,
+		ConcurrencyKey: inp.ConcurrencyKey,
+		Status:         models.WorkerJobRunStatus_Queued,
+		MaxAttempts:    inp.MaxAttempts,
+		NextRunAt:      time.Now().UTC(),
+	})
+}
+
+// Requeue moves a run on the dead-letter list back onto the queue with a fresh set of attempts
+func (q *PersistentQueue) Requeue(ctx context.Context, runID uint) (*models.WorkerJobRun, error) {
+	run, err := q.repo.ReadWorkerJobRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if run.Status != models.WorkerJobRunStatus_Dead {
+		return nil, fmt.Errorf("only dead runs can be requeued, run %d is %s", run.ID, run.Status)
+	}
+
+	run.Status = models.WorkerJobRunStatus_Queued
+	run.Attempts = 0
+	run.MaxAttempts = q.maxAttempts(run.JobID)
+	run.NextRunAt = time.Now().UTC()
+	run.CompletedAt = nil
+
+	return q.repo.UpdateWorkerJobRun(ctx, run)
+}
+
+// Run spawns a goroutine which polls the store for due runs until Exit is called
+func (q *PersistentQueue) Run(ctx context.Context) error {
+	go func() {
+		ticker := time.NewTicker(q.opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			q.poll(ctx)
+
+			select {
+			case <-ticker.C:
+			case <-q.exitChan:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Exit instructs the queue to stop polling for runs
+func (q *PersistentQueue) Exit() {
+	q.exitChan <- true
+}
+
+// poll claims due runs for as long as there is room in the job queue, so that runs are not locked
+// while they wait for a worker
+func (q *PersistentQueue) poll(ctx context.Context) {
+	for len(q.jobQueue) < cap(q.jobQueue) {
+		now := time.Now().UTC()
+
+		run, err := q.repo.ClaimWorkerJobRun(ctx, q.workerID, now, now.Add(q.opts.LockDuration))
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("error claiming worker job run: %v", err)
+			}
+			return
+		}
+
+		input := make(map[string]interface{})
+		if len(run.Input) > 0 {
+			if err := json.Unmarshal(run.Input, &input); err != nil {
+				q.recordFailure(ctx, run, fmt.Errorf("error unmarshaling job input: %w", err))
+				continue
+			}
+		}
+
+		job, err := q.newJob(ctx, run.JobID, input)
+		if err != nil {
+			q.recordFailure(ctx, run, fmt.Errorf("error creating job: %w", err))
+			continue
+		}
+
+		q.jobQueue <- &persistentJob{Job: job, run: run, queue: q}
+	}
+}
+
+// maxAttempts returns the number of attempts of a run of the given job
+func (q *PersistentQueue) maxAttempts(jobID string) int {
+	if maxAttempts, ok := q.opts.MaxAttemptsByJob[jobID]; ok && maxAttempts > 0 {
+		return maxAttempts
+	}
+
+	return q.opts.MaxAttempts
+}
+
+// heartbeat extends the lock of a run until the returned function is called, so that the run is not claimed by another
+// worker while its job is still running. The returned context is cancelled if the lock is lost.
+func (q *PersistentQueue) heartbeat(ctx context.Context, run *models.WorkerJobRun) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(q.opts.LockDuration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := q.repo.ExtendWorkerJobRunLock(ctx, run.ID, q.workerID, time.Now().UTC().Add(q.opts.LockDuration))
+				if err == nil {
+					continue
+				}
+
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					log.Printf("lost lock of worker job run %d of job '%s', cancelling job", run.ID, run.JobID)
+					cancel()
+					return
+				}
+
+				// the lock is only lost once it expires, so transient errors are retried on the next tick
+				log.Printf("error extending lock of worker job run %d: %v", run.ID, err)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return ctx, func() {
+		close(done)
+		<-stopped
+		cancel()
+	}
+}
+
+// backoff returns the delay before the next attempt of a run which has failed the given number of times
+func (q *PersistentQueue) backoff(attempts int) time.Duration {
+	delay := q.opts.RetryBackoff
+
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= q.opts.MaxRetryBackoff {
+			return q.opts.MaxRetryBackoff
+		}
+	}
+
+	return delay
+}
+
+func (q *PersistentQueue) recordSuccess(ctx context.Context, run *models.WorkerJobRun, result []byte) {
+	now := time.Now().UTC()
+
+	run.Status = models.WorkerJobRunStatus_Succeeded
+	run.LastError = ""
+	run.Result = result
+	run.CompletedAt = &now
+	run.LockedBy = ""
+	run.LockedUntil = nil
+
+	if err := q.repo.FinishWorkerJobRun(ctx, run, q.workerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("lost lock of worker job run %d of job '%s', not recording its success", run.ID, run.JobID)
+			return
+		}
+		log.Printf("error recording success of worker job run %d: %v", run.ID, err)
+	}
+}
+
+func (q *PersistentQueue) recordFailure(ctx context.Context, run *models.WorkerJobRun, runErr error) {
+	now := time.Now().UTC()
+
+	run.LastError = runErr.Error()
+	run.LockedBy = ""
+	run.LockedUntil = nil
+
+	if run.Attempts >= run.MaxAttempts {
+		run.Status = models.WorkerJobRunStatus_Dead
+		run.CompletedAt = &now
+
+		log.Printf("worker job run %d of job '%s' failed after %d attempts, moving to dead-letter list", run.ID, run.JobID, run.Attempts)
+	} else {
+		run.Status = models.WorkerJobRunStatus_Retrying
+		run.NextRunAt = now.Add(q.backoff(run.Attempts))
+	}
+
+	if err := q.repo.FinishWorkerJobRun(ctx, run, q.workerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("lost lock of worker job run %d of job '%s', not recording its failure", run.ID, run.JobID)
+			return
+		}
+		log.Printf("error recording failure of worker job run %d: %v", run.ID, err)
+	}
+}
+
+// ResultReporter is implemented by jobs which report a result once they have run successfully, such as the
+// resources a job has cleaned up. The result is stored on the run.
+type ResultReporter interface {
+	// Result returns the JSON-encoded result of the job
+	Result() ([]byte, error)
+}
+
+// persistentJob wraps a job claimed from the store, recording its outcome once it has run
+type persistentJob struct {
+	Job
+
+	run   *models.WorkerJobRun
+	queue *PersistentQueue
+}
+
+// Run runs the wrapped job, holding the run's lock until it finishes, and records its outcome
+func (j *persistentJob) Run(ctx context.Context) error {
+	jobCtx, stop := j.queue.heartbeat(ctx, j.run)
+	err := j.Job.Run(jobCtx)
+	stop()
+
+	if err != nil {
+		j.queue.recordFailure(ctx, j.run, err)
+		return err
+	}
+
+	var result []byte
+	if reporter, ok := j.Job.(ResultReporter); ok {
+		result, err = reporter.Result()
+		if err != nil {
+			log.Printf("error encoding result of worker job run %d: %v", j.run.ID, err)
+		}
+	}
+
+	j.queue.recordSuccess(ctx, j.run, result)
+
+	return nil
+}
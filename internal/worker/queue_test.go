@@ -0,0 +1,317 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"go.uber.org/goleak"
+)
+
+type testJob struct {
+	err error
+}
+
+func (j *testJob) ID() string                    { return "test-job" }
+func (j *testJob) EnqueueTime() time.Time        { return time.Now().UTC() }
+func (j *testJob) Run(ctx context.Context) error { return j.err }
+func (j *testJob) SetData([]byte)                {}
+
+type testReportingJob struct {
+	testJob
+}
+
+func (j *testReportingJob) Result() ([]byte, error) { return []byte(`{"removed":1}`), nil }
+
+func newTestQueue(t *testing.T, jobErr error, maxAttempts int) *PersistentQueue {
+	t.Helper()
+
+	q, err := NewPersistentQueue(test.NewWorkerJobRunRepository(true), make(chan Job, 10), func(ctx context.Context, jobID string, input map[string]interface{}) (Job, error) {
+		return &testJob{err: jobErr}, nil
+	}, PersistentQueueOpts{
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return q
+}
+
+// runNext claims the next due run and runs it, returning the run's stored state afterwards
+func runNext(ctx context.Context, t *testing.T, q *PersistentQueue) *models.WorkerJobRun {
+	t.Helper()
+
+	q.poll(ctx)
+
+	if len(q.jobQueue) != 1 {
+		t.Fatalf("expected 1 claimed job, got %d", len(q.jobQueue))
+	}
+
+	job := (<-q.jobQueue).(*persistentJob)
+	_ = job.Run(ctx)
+
+	run, err := q.repo.ReadWorkerJobRun(ctx, job.run.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return run
+}
+
+func TestPersistentQueueSuccess(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, nil, 3)
+
+	if _, err := q.Enqueue(ctx, "test-job", map[string]interface{}{"key": "value"}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	run := runNext(ctx, t, q)
+
+	if run.Status != models.WorkerJobRunStatus_Succeeded {
+		t.Errorf("expected status %s, got %s", models.WorkerJobRunStatus_Succeeded, run.Status)
+	}
+	if run.Attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", run.Attempts)
+	}
+}
+
+func TestPersistentQueueResult(t *testing.T) {
+	ctx := context.Background()
+
+	q, err := NewPersistentQueue(test.NewWorkerJobRunRepository(true), make(chan Job, 10), func(ctx context.Context, jobID string, input map[string]interface{}) (Job, error) {
+		return &testReportingJob{}, nil
+	}, PersistentQueueOpts{
+		MaxAttempts: 1,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := q.Enqueue(ctx, "test-job", nil); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	run := runNext(ctx, t, q)
+
+	if string(run.Result) != `{"removed":1}` {
+		t.Errorf("expected result to be recorded, got %q", string(run.Result))
+	}
+}
+
+func TestPersistentQueueConcurrencyKey(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, nil, 1)
+
+	for i := 0; i < 2; i++ {
+		if _, err := EnqueueJob(ctx, q.repo, EnqueueJobInput{JobID: "test-job", ConcurrencyKey: "env-group-1"}); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+	if _, err := EnqueueJob(ctx, q.repo, EnqueueJobInput{JobID: "test-job", ConcurrencyKey: "env-group-2"}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// only the oldest run of each key is claimed
+	q.poll(ctx)
+
+	if len(q.jobQueue) != 2 {
+		t.Fatalf("expected 2 claimed jobs, got %d", len(q.jobQueue))
+	}
+
+	first := (<-q.jobQueue).(*persistentJob)
+	second := (<-q.jobQueue).(*persistentJob)
+	if first.run.ID != 1 || second.run.ID != 3 {
+		t.Fatalf("expected runs 1 and 3 to be claimed, got %d and %d", first.run.ID, second.run.ID)
+	}
+
+	// the next run of a key is claimed once the previous one has finished
+	_ = first.Run(ctx)
+
+	run := runNext(ctx, t, q)
+	if run.ID != 2 || run.ConcurrencyKey != "env-group-1" {
+		t.Errorf("expected run 2 of env-group-1 to be claimed, got run %d of %s", run.ID, run.ConcurrencyKey)
+	}
+}
+
+func TestPersistentQueueRetryAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, errors.New("job failed"), 2)
+
+	if _, err := q.Enqueue(ctx, "test-job", nil); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	run := runNext(ctx, t, q)
+
+	if run.Status != models.WorkerJobRunStatus_Retrying {
+		t.Fatalf("expected status %s, got %s", models.WorkerJobRunStatus_Retrying, run.Status)
+	}
+	if run.LastError != "job failed" {
+		t.Errorf("expected last error to be recorded, got %q", run.LastError)
+	}
+	if !run.NextRunAt.After(time.Now().UTC()) {
+		t.Errorf("expected retry to be scheduled in the future")
+	}
+
+	// the retry is not due yet, so nothing should be claimed
+	q.poll(ctx)
+	if len(q.jobQueue) != 0 {
+		t.Fatalf("expected no claimed jobs before backoff elapsed, got %d", len(q.jobQueue))
+	}
+
+	run.NextRunAt = time.Now().UTC()
+	if _, err := q.repo.UpdateWorkerJobRun(ctx, run); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	run = runNext(ctx, t, q)
+
+	if run.Status != models.WorkerJobRunStatus_Dead {
+		t.Fatalf("expected status %s, got %s", models.WorkerJobRunStatus_Dead, run.Status)
+	}
+	if run.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", run.Attempts)
+	}
+
+	run, err := q.Requeue(ctx, run.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if run.Status != models.WorkerJobRunStatus_Queued || run.Attempts != 0 {
+		t.Errorf("expected requeued run to be queued with no attempts, got %s with %d attempts", run.Status, run.Attempts)
+	}
+}
+
+func TestPersistentQueueMaxAttemptsByJob(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, nil, 1)
+	q.opts.MaxAttemptsByJob = map[string]int{"retryable-job": 3}
+
+	retryable, err := q.Enqueue(ctx, "retryable-job", nil)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	oneShot, err := q.Enqueue(ctx, "test-job", nil)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if retryable.MaxAttempts != 3 || oneShot.MaxAttempts != 1 {
+		t.Errorf("expected 3 attempts for retryable job and 1 for other jobs, got %d and %d", retryable.MaxAttempts, oneShot.MaxAttempts)
+	}
+}
+
+type blockingJob struct {
+	testJob
+
+	release chan struct{}
+	ctxErr  error
+}
+
+func (j *blockingJob) Run(ctx context.Context) error {
+	select {
+	case <-j.release:
+	case <-ctx.Done():
+		j.ctxErr = ctx.Err()
+	}
+	return nil
+}
+
+func TestPersistentQueueHeartbeat(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctx := context.Background()
+	job := &blockingJob{release: make(chan struct{})}
+
+	q, err := NewPersistentQueue(test.NewWorkerJobRunRepository(true), make(chan Job, 10), func(ctx context.Context, jobID string, input map[string]interface{}) (Job, error) {
+		return job, nil
+	}, PersistentQueueOpts{
+		LockDuration: 30 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	enqueued, err := q.Enqueue(ctx, "test-job", nil)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	q.poll(ctx)
+	claimed := (<-q.jobQueue).(*persistentJob)
+	initialLock := *claimed.run.LockedUntil
+
+	finished := make(chan struct{})
+	go func() {
+		_ = claimed.Run(ctx)
+		close(finished)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	run, err := q.repo.ReadWorkerJobRun(ctx, enqueued.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if !run.LockedUntil.After(initialLock) {
+		t.Errorf("expected lock to be extended past %s while the job is running, got %s", initialLock, run.LockedUntil)
+	}
+
+	// another worker claiming the run means the lock was lost, so the job is cancelled
+	run.LockedBy = "other-worker"
+	if _, err := q.repo.UpdateWorkerJobRun(ctx, run); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		close(job.release)
+		<-finished
+		t.Fatal("expected job to be cancelled after losing its lock")
+	}
+
+	if !errors.Is(job.ctxErr, context.Canceled) {
+		t.Errorf("expected job context to be cancelled, got %v", job.ctxErr)
+	}
+
+	// the worker which lost the lock does not overwrite the run of its new claimant
+	run, err = q.repo.ReadWorkerJobRun(ctx, enqueued.ID)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if run.Status != models.WorkerJobRunStatus_Running || run.LockedBy != "other-worker" {
+		t.Errorf("expected run to stay running and locked by other-worker, got %s locked by %s", run.Status, run.LockedBy)
+	}
+}
+
+func TestPersistentQueueBackoff(t *testing.T) {
+	q := newTestQueue(t, nil, 1)
+	q.opts.RetryBackoff = time.Second
+	q.opts.MaxRetryBackoff = 10 * time.Second
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+
+	for i, want := range expected {
+		if got := q.backoff(i + 1); got != want {
+			t.Errorf("attempt %d: expected backoff %s, got %s", i+1, want, got)
+		}
+	}
+}
+
+func TestPersistentQueueRunExit(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	q := newTestQueue(t, nil, 1)
+
+	if err := q.Run(context.Background()); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	q.Exit()
+}
@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Enqueuer enqueues a run of a job. It is implemented by PersistentQueue.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, jobID string, input map[string]interface{}) (*models.WorkerJobRun, error)
+}
+
+// ScheduledJob is a job which is enqueued periodically according to a cron expression
+type ScheduledJob struct {
+	JobID      string
+	Expression string
+
+	schedule *CronSchedule
+}
+
+// ParseScheduledJob parses a job schedule of the form "<job-id>=<cron expression>", such as "recommender=0 * * * *"
+func ParseScheduledJob(spec string) (ScheduledJob, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return ScheduledJob{}, fmt.Errorf("job schedule '%s' must be of the form <job-id>=<cron expression>", spec)
+	}
+
+	jobID, expression := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	schedule, err := ParseCronSchedule(expression)
+	if err != nil {
+		return ScheduledJob{}, fmt.Errorf("invalid schedule for job '%s': %w", jobID, err)
+	}
+
+	return ScheduledJob{
+		JobID:      jobID,
+		Expression: expression,
+		schedule:   schedule,
+	}, nil
+}
+
+// Scheduler enqueues scheduled jobs when they are due. Every replica of the workers service can run a scheduler: each
+// scheduled run is claimed in the database before it is enqueued, so only one replica enqueues it.
+type Scheduler struct {
+	repo         repository.WorkerJobScheduleRepository
+	queue        Enqueuer
+	jobs         []ScheduledJob
+	tickInterval time.Duration
+	exitChan     chan bool
+}
+
+// NewScheduler creates a new Scheduler which checks for due jobs every tickInterval
+func NewScheduler(repo repository.WorkerJobScheduleRepository, queue Enqueuer, jobs []ScheduledJob, tickInterval time.Duration) (*Scheduler, error) {
+	if repo == nil {
+		return nil, errors.New("worker job schedule repository is nil")
+	}
+	if queue == nil {
+		return nil, errors.New("queue is nil")
+	}
+
+	seen := make(map[string]bool)
+	for _, job := range jobs {
+		if job.schedule == nil {
+			return nil, fmt.Errorf("job '%s' was not parsed with ParseScheduledJob", job.JobID)
+		}
+		if seen[job.JobID] {
+			return nil, fmt.Errorf("job '%s' is scheduled more than once", job.JobID)
+		}
+		seen[job.JobID] = true
+	}
+
+	if tickInterval <= 0 {
+		tickInterval = 30 * time.Second
+	}
+
+	return &Scheduler{
+		repo:         repo,
+		queue:        queue,
+		jobs:         jobs,
+		tickInterval: tickInterval,
+		exitChan:     make(chan bool),
+	}, nil
+}
+
+// Run stores the schedule of each job and spawns a goroutine which enqueues due jobs until Exit is called
+func (s *Scheduler) Run(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	for _, job := range s.jobs {
+		if err := s.syncSchedule(ctx, job, now); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.tickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case t := <-ticker.C:
+				s.tick(ctx, t.UTC())
+			case <-s.exitChan:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Exit instructs the scheduler to stop enqueueing jobs
+func (s *Scheduler) Exit() {
+	s.exitChan <- true
+}
+
+// Schedules returns the stored schedule of each scheduled job, including its last and next run
+func (s *Scheduler) Schedules(ctx context.Context) ([]*models.WorkerJobSchedule, error) {
+	var res []*models.WorkerJobSchedule
+
+	for _, job := range s.jobs {
+		schedule, err := s.repo.ReadWorkerJobSchedule(ctx, job.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("error reading schedule for job '%s': %w", job.JobID, err)
+		}
+
+		res = append(res, schedule)
+	}
+
+	return res, nil
+}
+
+// syncSchedule creates the stored schedule of a job, or updates it if its cron expression has changed
+func (s *Scheduler) syncSchedule(ctx context.Context, job ScheduledJob, now time.Time) error {
+	schedule, err := s.repo.ReadWorkerJobSchedule(ctx, job.JobID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error reading schedule for job '%s': %w", job.JobID, err)
+		}
+
+		_, err = s.repo.CreateWorkerJobSchedule(ctx, &models.WorkerJobSchedule{
+			JobID:     job.JobID,
+			Schedule:  job.Expression,
+			NextRunAt: job.schedule.Next(now),
+		})
+		if err != nil {
+			// another replica may have created the schedule at the same time
+			if _, readErr := s.repo.ReadWorkerJobSchedule(ctx, job.JobID); readErr != nil {
+				return fmt.Errorf("error creating schedule for job '%s': %w", job.JobID, err)
+			}
+		}
+
+		return nil
+	}
+
+	if schedule.Schedule == job.Expression {
+		return nil
+	}
+
+	schedule.Schedule = job.Expression
+	schedule.NextRunAt = job.schedule.Next(now)
+
+	if _, err := s.repo.UpdateWorkerJobSchedule(ctx, schedule); err != nil {
+		return fmt.Errorf("error updating schedule for job '%s': %w", job.JobID, err)
+	}
+
+	return nil
+}
+
+// tick enqueues each job which is due at the given time. A job which was due several times since the last tick,
+// for example because no replica was running, is only enqueued once.
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	for _, job := range s.jobs {
+		claimed, err := s.repo.ClaimWorkerJobSchedule(ctx, job.JobID, now, job.schedule.Next(now))
+		if err != nil {
+			log.Printf("error claiming scheduled run of job '%s': %v", job.JobID, err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		log.Printf("enqueueing scheduled run of job '%s'", job.JobID)
+
+		run, err := s.queue.Enqueue(ctx, job.JobID, map[string]interface{}{})
+		if err != nil {
+			log.Printf("error enqueueing scheduled run of job '%s': %v", job.JobID, err)
+			continue
+		}
+
+		schedule, err := s.repo.ReadWorkerJobSchedule(ctx, job.JobID)
+		if err != nil {
+			log.Printf("error reading schedule for job '%s': %v", job.JobID, err)
+			continue
+		}
+
+		schedule.LastRunID = run.ID
+
+		if _, err := s.repo.UpdateWorkerJobSchedule(ctx, schedule); err != nil {
+			log.Printf("error recording scheduled run of job '%s': %v", job.JobID, err)
+		}
+	}
+}
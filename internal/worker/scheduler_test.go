@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"go.uber.org/goleak"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, time.February, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * 6", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 2h", time.Date(2024, time.January, 31, 12, 17, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseCronSchedule(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%s: expected next run at %s, got %s", tt.expr, tt.want, got)
+		}
+	}
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@every 10s", "@sometimes"} {
+		if _, err := ParseCronSchedule(expr); err == nil {
+			t.Errorf("expected error parsing '%s'", expr)
+		}
+	}
+}
+
+type testEnqueuer struct {
+	enqueued []string
+}
+
+func (e *testEnqueuer) Enqueue(ctx context.Context, jobID string, input map[string]interface{}) (*models.WorkerJobRun, error) {
+	e.enqueued = append(e.enqueued, jobID)
+	return &models.WorkerJobRun{JobID: jobID}, nil
+}
+
+func TestSchedulerTick(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx := context.Background()
+
+	job, err := ParseScheduledJob("recommender=@hourly")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	repo := test.NewWorkerJobScheduleRepository(true)
+	enqueuer := &testEnqueuer{}
+
+	// two schedulers sharing the same store act as two replicas
+	replicas := make([]*Scheduler, 2)
+	for i := range replicas {
+		replicas[i], err = NewScheduler(repo, enqueuer, []ScheduledJob{job}, time.Hour)
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+
+		if err := replicas[i].Run(ctx); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		defer replicas[i].Exit()
+	}
+
+	schedules, err := replicas[0].Schedules(ctx)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(schedules) != 1 || schedules[0].LastRunAt != nil {
+		t.Fatalf("expected 1 schedule which has not run yet")
+	}
+
+	nextRunAt := schedules[0].NextRunAt
+
+	for _, replica := range replicas {
+		replica.tick(ctx, nextRunAt.Add(-time.Second))
+	}
+	if len(enqueuer.enqueued) != 0 {
+		t.Fatalf("expected no jobs to be enqueued before the next run, got %d", len(enqueuer.enqueued))
+	}
+
+	for _, replica := range replicas {
+		replica.tick(ctx, nextRunAt)
+	}
+	if len(enqueuer.enqueued) != 1 {
+		t.Fatalf("expected exactly 1 job to be enqueued across replicas, got %d", len(enqueuer.enqueued))
+	}
+
+	schedules, err = replicas[1].Schedules(ctx)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if schedules[0].LastRunAt == nil || !schedules[0].NextRunAt.Equal(nextRunAt.Add(time.Hour)) {
+		t.Errorf("expected last run to be recorded and next run to be an hour later")
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+// Plan initiates a new plan operation for infra, which computes the changes an apply
+// would make without changing any resource
+func (c *Client) Plan(
+	ctx context.Context,
+	projID, infraID uint,
+	req *ptypes.PlanBaseRequest,
+) (*types.Operation, error) {
+	resp := &types.Operation{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/infras/%d/plan",
+			projID,
+			infraID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetPlan returns the plan computed by a plan operation
+func (c *Client) GetPlan(
+	ctx context.Context,
+	workspaceID string,
+) (*ptypes.GetPlanResponse, error) {
+	resp := &ptypes.GetPlanResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/%s/plan",
+			workspaceID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ApprovePlan approves the plan computed by a plan operation, and returns the apply
+// operation which was started
+func (c *Client) ApprovePlan(
+	ctx context.Context,
+	workspaceID string,
+) (*types.Operation, error) {
+	resp := &types.Operation{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/%s/plan/approve",
+			workspaceID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ReportPlan reports the plan computed by a plan operation to the provisioner service
+func (c *Client) ReportPlan(
+	ctx context.Context,
+	workspaceID string,
+	req *ptypes.ReportPlanRequest,
+) error {
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/%s/plan",
+			workspaceID,
+		),
+		req,
+		nil,
+	)
+
+	return err
+}
@@ -159,5 +159,13 @@ func (k *KubernetesProvisioner) getTFEnv(opts *provisioner.ProvisionOpts) ([]v1.
 		Value: opts.Kind,
 	})
 
+	// an apply with a plan file applies the plan as is, rather than computing a new plan from the values
+	if opts.PlanFile != nil {
+		env = append(env, v1.EnvVar{
+			Name:  "TF_PLAN_FILE_ENDPOINT",
+			Value: opts.PlanFile.PlanFileEndpoint,
+		})
+	}
+
 	return env, nil
 }
@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProvisionPlanFile(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	k := NewKubernetesProvisioner(client, &KubernetesProvisionerConfig{
+		ProvisionerJobNamespace: "provisioner",
+	})
+
+	opts := &provisioner.ProvisionOpts{
+		Infra:              &models.Infra{Kind: types.InfraEKS, ProjectID: 1, Suffix: "abcdef"},
+		Operation:          &models.Operation{UID: "0123456789abcdef0123"},
+		CredentialExchange: &provisioner.ProvisionCredentialExchange{},
+		OperationKind:      provisioner.Apply,
+		PlanFile: &provisioner.ProvisionPlanFile{
+			PlanFileEndpoint: "http://provisioner/api/v1/workspace/planfile",
+		},
+	}
+
+	if err := k.Provision(opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jobs, err := client.BatchV1().Jobs("provisioner").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs.Items))
+	}
+
+	container := jobs.Items[0].Spec.Template.Spec.Containers[0]
+
+	found := false
+	for _, env := range container.Env {
+		if env.Name == "TF_PLAN_FILE_ENDPOINT" {
+			found = env.Value == "http://provisioner/api/v1/workspace/planfile"
+		}
+	}
+	if !found {
+		t.Errorf("expected the plan file endpoint to be passed to the provisioner, got %+v", container.Env)
+	}
+
+	// an apply without a plan file computes its own plan
+	opts.PlanFile = nil
+
+	env, err := k.getTFEnv(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range env {
+		if e.Name == "TF_PLAN_FILE_ENDPOINT" {
+			t.Errorf("expected no plan file endpoint, got %s", e.Value)
+		}
+	}
+}
@@ -67,5 +67,9 @@ func (l *LocalProvisioner) getEnv(opts *provisioner.ProvisionOpts) ([]string, er
 	env = append(env, fmt.Sprintf("TF_VALUES=%s", base64.StdEncoding.EncodeToString(valBytes)))
 	env = append(env, fmt.Sprintf("TF_KIND=%s", opts.Kind))
 
+	if opts.PlanFile != nil {
+		env = append(env, fmt.Sprintf("TF_PLAN_FILE_ENDPOINT=%s", opts.PlanFile.PlanFileEndpoint))
+	}
+
 	return env, nil
 }
@@ -9,6 +9,9 @@ type ProvisionerOperation string
 const (
 	Apply   ProvisionerOperation = "apply"
 	Destroy ProvisionerOperation = "destroy"
+
+	// Plan computes the changes an apply would make, without changing any resource
+	Plan ProvisionerOperation = "plan"
 )
 
 type ProvisionCredentialExchange struct {
@@ -19,10 +22,17 @@ type ProvisionCredentialExchange struct {
 	VaultToken string
 }
 
+type ProvisionPlanFile struct {
+	// PlanFileEndpoint is the endpoint to which a plan operation uploads the binary plan file written by
+	// terraform plan, and from which an apply operation downloads the plan file to apply
+	PlanFileEndpoint string
+}
+
 type ProvisionOpts struct {
 	Infra              *models.Infra
 	Operation          *models.Operation
 	CredentialExchange *ProvisionCredentialExchange
+	PlanFile           *ProvisionPlanFile
 	OperationKind      ProvisionerOperation
 	Kind               string
 	Values             map[string]interface{}
@@ -167,7 +167,7 @@ func GlobalStreamListener(
 			config.Logger.Debug().Msg(fmt.Sprintf("pushing state and log file for %s with status %v", workspaceID, statusVal))
 
 			switch fmt.Sprintf("%v", statusVal) {
-			case "created", "error", "destroyed":
+			case "created", "error", "destroyed", types.OperationStatusPlanned:
 				err := cleanupOperation(config, client, infra, operation, workspaceID)
 				if err != nil {
 					config.Alerter.SendAlert(context.Background(), err, map[string]interface{}{
@@ -181,16 +181,19 @@ func GlobalStreamListener(
 
 func cleanupOperation(config *config.Config, client *redis.Client, infra *models.Infra, operation *models.Operation, workspaceID string) error {
 	l := config.Logger
-	l.Debug().Msg(fmt.Sprintf("pushing state for %s", workspaceID))
+	// plan operations do not change any resource, so the current state is kept
+	if operation.Type != types.OperationTypePlan {
+		l.Debug().Msg(fmt.Sprintf("pushing state for %s", workspaceID))
 
-	err := pushNewStateToStorage(config, client, infra, operation, workspaceID)
-	if err != nil {
-		return err
+		err := pushNewStateToStorage(config, client, infra, operation, workspaceID)
+		if err != nil {
+			return err
+		}
 	}
 
 	l.Debug().Msg(fmt.Sprintf("cleaning state stream for %s", workspaceID))
 
-	err = cleanupStateStream(config, client, workspaceID)
+	err := cleanupStateStream(config, client, workspaceID)
 
 	if err != nil {
 		return nil
@@ -13,6 +13,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/analytics"
+	"github.com/porter-dev/porter/internal/encryption/kms"
 	"github.com/porter-dev/porter/internal/features"
 	"github.com/porter-dev/porter/internal/kubernetes"
 	klocal "github.com/porter-dev/porter/internal/kubernetes/local"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	"github.com/porter-dev/porter/internal/repository/gorm"
 	"github.com/porter-dev/porter/pkg/logger"
 	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
@@ -49,6 +51,15 @@ func sharedInit() {
 		DBConf:          &envDecoderConf.DBConf,
 		RedisConf:       envDecoderConf.RedisConf,
 	}
+
+	if InstanceEnvConf.DBConf.CredentialStorageBackend != "" {
+		var err error
+
+		InstanceCredentialBackend, err = secretstore.NewCredentialStorage(context.Background(), InstanceEnvConf.DBConf)
+		if err != nil {
+			log.Fatalf("Failed to create credential storage backend: %s", err)
+		}
+	}
 }
 
 type Config struct {
@@ -173,6 +184,10 @@ func GetConfig(envConf *EnvConf) (*Config, error) {
 		key[i] = b
 	}
 
+	if err := kms.SetKeyProviderFromConf(ctx, envConf.DBConf); err != nil {
+		return nil, err
+	}
+
 	res.Repo = gorm.NewRepository(db, &key, InstanceCredentialBackend)
 
 	launchDarklyClient, err := features.GetClient(envConf.FeatureFlagClient, envConf.LaunchDarklySDKKey)
@@ -16,7 +16,7 @@ func init() {
 		key[i] = b
 	}
 
-	if InstanceEnvConf.DBConf.VaultAPIKey != "" && InstanceEnvConf.DBConf.VaultServerURL != "" && InstanceEnvConf.DBConf.VaultPrefix != "" {
+	if InstanceCredentialBackend == nil && InstanceEnvConf.DBConf.VaultAPIKey != "" && InstanceEnvConf.DBConf.VaultServerURL != "" && InstanceEnvConf.DBConf.VaultPrefix != "" {
 		InstanceCredentialBackend = vault.NewClient(
 			InstanceEnvConf.DBConf.VaultServerURL,
 			InstanceEnvConf.DBConf.VaultAPIKey,
@@ -5,6 +5,8 @@ import (
 
 	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
 	"github.com/porter-dev/porter/provisioner/pb"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
 )
 
 func (s *ProvisionerServer) GetLog(infra *pb.Infra, server pb.Provisioner_GetLogServer) error {
@@ -24,8 +26,9 @@ func (s *ProvisionerServer) GetLog(infra *pb.Infra, server pb.Provisioner_GetLog
 		return err
 	}
 
-	// if the operation is completed, close the connection
-	if operation.Status == "completed" {
+	// if the operation is completed, or is a plan which was computed, close the connection
+	switch operation.Status {
+	case "completed", ptypes.OperationStatusPlanned, ptypes.OperationStatusApproved:
 		return nil
 	}
 
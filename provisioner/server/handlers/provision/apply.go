@@ -1,19 +1,15 @@
 package provision
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/types"
-	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/random"
 	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
-	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
 	"github.com/porter-dev/porter/provisioner/server/config"
 	"golang.org/x/crypto/bcrypt"
 
@@ -47,84 +43,19 @@ func (c *ProvisionApplyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// create a new operation and write it to the database
-	operationUID, err := models.GetOperationID()
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
-	// parse values to JSON to store in the operation
-	valuesJSON, err := json.Marshal(req.Values)
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
-	operation := &models.Operation{
-		UID:             operationUID,
-		InfraID:         infra.ID,
-		Type:            req.OperationKind,
-		Status:          "starting",
-		LastApplied:     valuesJSON,
-		TemplateVersion: "v0.1.0",
-	}
-
-	operation, err = c.Config.Repo.Infra().AddOperation(infra, operation)
-
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
-	ceToken, rawToken, err := createCredentialsExchangeToken(c.Config, infra)
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
-	// push a first message to the operation stream
-	err = redis_stream.PushToOperationStream(c.Config.RedisClient, infra, operation, &ptypes.TFResourceState{
-		Status: "OPERATION_STARTED",
-	})
-
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
-	}
-
-	// spawn a new provisioning process
-	err = c.Config.Provisioner.Provision(&provisioner.ProvisionOpts{
-		Infra:         infra,
-		Operation:     operation,
+	operation, err := startOperation(c.Config, infra, &startOperationOpts{
+		OperationType: req.OperationKind,
 		OperationKind: provisioner.Apply,
 		Kind:          req.Kind,
 		Values:        req.Values,
-		CredentialExchange: &provisioner.ProvisionCredentialExchange{
-			CredExchangeEndpoint: fmt.Sprintf(
-				"%s/api/v1/%s/credentials",
-				c.Config.ProvisionerConf.ProvisionerCredExchangeURL,
-				models.GetWorkspaceID(infra, operation),
-			),
-			CredExchangeToken: rawToken,
-			CredExchangeID:    ceToken.ID,
-		},
 	})
-
 	if err != nil {
 		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
 		return
 	}
 
 	// update the infrastructure as either "updating" or "creating"
-	if req.OperationKind == "create" || req.OperationKind == "retry_create" {
-		infra.Status = types.InfraStatus("creating")
-	} else if req.OperationKind == "update" {
-		infra.Status = types.InfraStatus("updating")
-	}
-
-	infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
-
+	infra, err = setApplyInfraStatus(c.Config, infra, req.OperationKind)
 	if err != nil {
 		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
 		return
@@ -139,25 +70,7 @@ func (c *ProvisionApplyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	// return the operation response type to the server
 	c.resultWriter.WriteResult(w, r, op)
 
-	// if this is a cluster or registry infra type, send to analytics client
-	switch infra.Kind {
-	case types.InfraDOKS, types.InfraEKS, types.InfraGKE, types.InfraAKS:
-		c.Config.AnalyticsClient.Track(analytics.ClusterProvisioningStartTrack(
-			&analytics.ClusterProvisioningStartTrackOpts{
-				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
-				ClusterType:            infra.Kind,
-				InfraID:                infra.ID,
-			},
-		))
-	case types.InfraDOCR, types.InfraECR, types.InfraGCR, types.InfraGAR, types.InfraACR:
-		c.Config.AnalyticsClient.Track(analytics.RegistryProvisioningStartTrack(
-			&analytics.RegistryProvisioningStartTrackOpts{
-				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
-				RegistryType:           infra.Kind,
-				InfraID:                infra.ID,
-			},
-		))
-	}
+	trackProvisioningStart(c.Config, infra)
 }
 
 func createCredentialsExchangeToken(conf *config.Config, infra *models.Infra) (*models.CredentialsExchangeToken, string, error) {
@@ -0,0 +1,177 @@
+package provision
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type ProvisionApprovePlanHandler struct {
+	Config *config.Config
+
+	resultWriter shared.ResultWriter
+}
+
+func NewProvisionApprovePlanHandler(
+	config *config.Config,
+) *ProvisionApprovePlanHandler {
+	return &ProvisionApprovePlanHandler{
+		Config:       config,
+		resultWriter: shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	}
+}
+
+// ServeHTTP approves the plan of a plan operation, starting an apply operation which applies the binary plan
+// file written by terraform plan, so that only the reviewed changes are made. A plan can only be approved once,
+// and only if no other operation was started on the infra since.
+func (c *ProvisionApprovePlanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra and operation from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	if operation.Type != ptypes.OperationTypePlan {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is not a plan", operation.UID),
+			http.StatusBadRequest,
+		), true)
+		return
+	}
+
+	if operation.Status != ptypes.OperationStatusPlanned {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("plan cannot be approved as its operation has status %s", operation.Status),
+			http.StatusBadRequest,
+		), true)
+		return
+	}
+
+	// the plan is stale if the infra was changed after it was computed
+	latestOperation, err := c.Config.Repo.Infra().GetLatestOperation(infra)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	if latestOperation.UID != operation.UID {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("plan is stale as operation %s was started after it, please create a new plan", latestOperation.UID),
+			http.StatusBadRequest,
+		), true)
+		return
+	}
+
+	workspaceID := models.GetWorkspaceID(infra, operation)
+
+	planBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.GetPlanFileName(workspaceID), true)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	plan := &ptypes.TFPlan{}
+
+	if err := json.Unmarshal(planBytes, plan); err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	planFile, err := c.Config.StorageManager.ReadFile(infra, ptypes.GetPlanBinaryFileName(workspaceID), true)
+	if err != nil {
+		if errors.Is(err, storage.FileDoesNotExist) {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("plan file of operation %s was not uploaded, please create a new plan", operation.UID),
+				http.StatusBadRequest,
+			), true)
+			return
+		}
+
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	values := make(map[string]interface{})
+
+	if err := json.Unmarshal(operation.LastApplied, &values); err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// mark the plan as approved before starting the apply. The status is only updated if the plan was not
+	// approved in the meantime, so that concurrent approvals cannot start two applies.
+	approved, err := c.Config.Repo.Infra().UpdateOperationStatus(operation, ptypes.OperationStatusPlanned, ptypes.OperationStatusApproved)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	if !approved {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("plan of operation %s was already approved", operation.UID),
+			http.StatusConflict,
+		), true)
+		return
+	}
+
+	// the values are stored on the apply operation, but the provisioner process applies the plan file
+	applyOperation, err := startOperation(c.Config, infra, &startOperationOpts{
+		OperationType: plan.OperationKind,
+		OperationKind: provisioner.Apply,
+		Kind:          string(infra.Kind),
+		Values:        values,
+		UsePlanFile:   true,
+		BeforeProvision: func(applyOperation *models.Operation) error {
+			return c.Config.StorageManager.WriteFile(
+				infra,
+				ptypes.GetPlanBinaryFileName(models.GetWorkspaceID(infra, applyOperation)),
+				planFile,
+				true,
+			)
+		},
+	})
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	infra, err = setApplyInfraStatus(c.Config, infra, plan.OperationKind)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// link the plan to the apply operation
+	plan.ApplyOperationID = applyOperation.UID
+
+	planBytes, err = json.Marshal(plan)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	err = c.Config.StorageManager.WriteFile(infra, ptypes.GetPlanFileName(workspaceID), planBytes, true)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	op, err := applyOperation.ToOperationType()
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	c.resultWriter.WriteResult(w, r, op)
+
+	trackProvisioningStart(c.Config, infra)
+}
@@ -0,0 +1,198 @@
+package provision_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors/alerter"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	"github.com/porter-dev/porter/provisioner/server/handlers/provision"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type memoryStorageManager struct {
+	files map[string][]byte
+}
+
+func (s *memoryStorageManager) WriteFile(infra *models.Infra, name string, bytes []byte, shouldEncrypt bool) error {
+	s.files[name] = bytes
+	return nil
+}
+
+func (s *memoryStorageManager) ReadFile(infra *models.Infra, name string, shouldDecrypt bool) ([]byte, error) {
+	bytes, ok := s.files[name]
+	if !ok {
+		return nil, storage.FileDoesNotExist
+	}
+
+	return bytes, nil
+}
+
+func (s *memoryStorageManager) DeleteFile(infra *models.Infra, name string) error {
+	delete(s.files, name)
+	return nil
+}
+
+type recordingProvisioner struct {
+	provisioned []*provisioner.ProvisionOpts
+}
+
+func (p *recordingProvisioner) Provision(opts *provisioner.ProvisionOpts) error {
+	p.provisioned = append(p.provisioned, opts)
+	return nil
+}
+
+type approvePlanTest struct {
+	conf        *config.Config
+	storage     *memoryStorageManager
+	provisioner *recordingProvisioner
+
+	infra     *models.Infra
+	operation *models.Operation
+}
+
+// newApprovePlanTest returns an infra with a plan operation whose plan is ready to be reviewed
+func newApprovePlanTest(t *testing.T) *approvePlanTest {
+	t.Helper()
+
+	tt := &approvePlanTest{
+		storage:     &memoryStorageManager{files: make(map[string][]byte)},
+		provisioner: &recordingProvisioner{},
+	}
+
+	tt.conf = &config.Config{
+		Logger:         logger.New(true, os.Stdout),
+		Alerter:        alerter.NoOpAlerter{},
+		Repo:           test.NewRepository(true),
+		StorageManager: tt.storage,
+		Provisioner:    tt.provisioner,
+	}
+
+	infra, err := tt.conf.Repo.Infra().CreateInfra(&models.Infra{
+		Kind:      types.InfraEKS,
+		ProjectID: 1,
+		Suffix:    "abcdef",
+	})
+	if err != nil {
+		t.Fatalf("error creating infra: %v", err)
+	}
+	tt.infra = infra
+
+	operationUID, err := models.GetOperationID()
+	if err != nil {
+		t.Fatalf("error generating operation id: %v", err)
+	}
+
+	operation, err := tt.conf.Repo.Infra().AddOperation(infra, &models.Operation{
+		UID:         operationUID,
+		Type:        ptypes.OperationTypePlan,
+		Status:      ptypes.OperationStatusPlanned,
+		LastApplied: []byte(`{"cluster_name":"test"}`),
+	})
+	if err != nil {
+		t.Fatalf("error adding operation: %v", err)
+	}
+	tt.operation = operation
+
+	planBytes, err := json.Marshal(&ptypes.TFPlan{
+		OperationID:   operationUID,
+		OperationKind: "update",
+	})
+	if err != nil {
+		t.Fatalf("error encoding plan: %v", err)
+	}
+
+	tt.storage.files[ptypes.GetPlanFileName(models.GetWorkspaceID(infra, operation))] = planBytes
+
+	return tt
+}
+
+// approve approves the plan, with the operation read from the request scope
+func (tt *approvePlanTest) approve(operation *models.Operation) *httptest.ResponseRecorder {
+	ctx := context.WithValue(context.Background(), types.InfraScope, tt.infra)
+	ctx = context.WithValue(ctx, types.OperationScope, operation)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/workspace/plan/approve", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	provision.NewProvisionApprovePlanHandler(tt.conf).ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestApprovePlanWithoutPlanFile(t *testing.T) {
+	tt := newApprovePlanTest(t)
+
+	rr := tt.approve(tt.operation)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if tt.operation.Status != ptypes.OperationStatusPlanned {
+		t.Errorf("expected plan to stay %s, got %s", ptypes.OperationStatusPlanned, tt.operation.Status)
+	}
+	if len(tt.provisioner.provisioned) != 0 {
+		t.Errorf("expected no apply to be started, got %d", len(tt.provisioner.provisioned))
+	}
+}
+
+func TestApprovePlanAlreadyApproved(t *testing.T) {
+	tt := newApprovePlanTest(t)
+	tt.storage.files[ptypes.GetPlanBinaryFileName(models.GetWorkspaceID(tt.infra, tt.operation))] = []byte("plan")
+
+	// the request read the operation before a concurrent approval updated it
+	scoped := *tt.operation
+
+	approved, err := tt.conf.Repo.Infra().UpdateOperationStatus(tt.operation, ptypes.OperationStatusPlanned, ptypes.OperationStatusApproved)
+	if err != nil || !approved {
+		t.Fatalf("expected the first approval to succeed, got %t, %v", approved, err)
+	}
+
+	rr := tt.approve(&scoped)
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+	}
+	if len(tt.provisioner.provisioned) != 0 {
+		t.Errorf("expected no apply to be started, got %d", len(tt.provisioner.provisioned))
+	}
+}
+
+func TestApprovePlanStale(t *testing.T) {
+	tt := newApprovePlanTest(t)
+	tt.storage.files[ptypes.GetPlanBinaryFileName(models.GetWorkspaceID(tt.infra, tt.operation))] = []byte("plan")
+
+	operationUID, err := models.GetOperationID()
+	if err != nil {
+		t.Fatalf("error generating operation id: %v", err)
+	}
+
+	_, err = tt.conf.Repo.Infra().AddOperation(tt.infra, &models.Operation{
+		UID:    operationUID,
+		Type:   "update",
+		Status: "starting",
+	})
+	if err != nil {
+		t.Fatalf("error adding operation: %v", err)
+	}
+
+	rr := tt.approve(tt.operation)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if tt.operation.Status != ptypes.OperationStatusPlanned {
+		t.Errorf("expected plan to stay %s, got %s", ptypes.OperationStatusPlanned, tt.operation.Status)
+	}
+}
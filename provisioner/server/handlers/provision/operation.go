@@ -0,0 +1,153 @@
+package provision
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/analytics"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type startOperationOpts struct {
+	// OperationType is the type of the operation stored in the database, such as "create" or "plan"
+	OperationType string
+
+	// OperationKind is the operation run by the provisioner process
+	OperationKind provisioner.ProvisionerOperation
+
+	Kind   string
+	Values map[string]interface{}
+
+	// UsePlanFile passes the endpoint of the binary plan file of the operation to the provisioning process.
+	// A plan operation uploads the plan to it, and an apply operation applies the plan downloaded from it.
+	UsePlanFile bool
+
+	// BeforeProvision is called with the new operation before the provisioning process is spawned, so that
+	// the files read by the process can be written first
+	BeforeProvision func(operation *models.Operation) error
+}
+
+// startOperation writes a new operation for the infra to the database and spawns a provisioning
+// process which runs it
+func startOperation(conf *config.Config, infra *models.Infra, opts *startOperationOpts) (*models.Operation, error) {
+	operationUID, err := models.GetOperationID()
+	if err != nil {
+		return nil, err
+	}
+
+	// parse values to JSON to store in the operation
+	valuesJSON, err := json.Marshal(opts.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &models.Operation{
+		UID:             operationUID,
+		InfraID:         infra.ID,
+		Type:            opts.OperationType,
+		Status:          "starting",
+		LastApplied:     valuesJSON,
+		TemplateVersion: "v0.1.0",
+	}
+
+	operation, err = conf.Repo.Infra().AddOperation(infra, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.BeforeProvision != nil {
+		if err := opts.BeforeProvision(operation); err != nil {
+			return nil, err
+		}
+	}
+
+	ceToken, rawToken, err := createCredentialsExchangeToken(conf, infra)
+	if err != nil {
+		return nil, err
+	}
+
+	// push a first message to the operation stream
+	err = redis_stream.PushToOperationStream(conf.RedisClient, infra, operation, &ptypes.TFResourceState{
+		Status: "OPERATION_STARTED",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var planFile *provisioner.ProvisionPlanFile
+
+	if opts.UsePlanFile {
+		planFile = &provisioner.ProvisionPlanFile{
+			PlanFileEndpoint: fmt.Sprintf(
+				"%s/api/v1/%s/planfile",
+				conf.ProvisionerConf.ProvisionerCredExchangeURL,
+				models.GetWorkspaceID(infra, operation),
+			),
+		}
+	}
+
+	// spawn a new provisioning process
+	err = conf.Provisioner.Provision(&provisioner.ProvisionOpts{
+		Infra:         infra,
+		Operation:     operation,
+		OperationKind: opts.OperationKind,
+		Kind:          opts.Kind,
+		Values:        opts.Values,
+		CredentialExchange: &provisioner.ProvisionCredentialExchange{
+			CredExchangeEndpoint: fmt.Sprintf(
+				"%s/api/v1/%s/credentials",
+				conf.ProvisionerConf.ProvisionerCredExchangeURL,
+				models.GetWorkspaceID(infra, operation),
+			),
+			CredExchangeToken: rawToken,
+			CredExchangeID:    ceToken.ID,
+		},
+		PlanFile: planFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+// setApplyInfraStatus updates the infrastructure as either "updating" or "creating", depending on the
+// kind of the apply operation
+func setApplyInfraStatus(conf *config.Config, infra *models.Infra, operationKind string) (*models.Infra, error) {
+	if operationKind == "create" || operationKind == "retry_create" {
+		infra.Status = types.InfraStatus("creating")
+	} else if operationKind == "update" {
+		infra.Status = types.InfraStatus("updating")
+	}
+
+	return conf.Repo.Infra().UpdateInfra(infra)
+}
+
+// trackProvisioningStart sends the start of a provisioning operation to analytics, if this is a
+// cluster or registry infra type
+func trackProvisioningStart(conf *config.Config, infra *models.Infra) {
+	switch infra.Kind {
+	case types.InfraDOKS, types.InfraEKS, types.InfraGKE, types.InfraAKS:
+		conf.AnalyticsClient.Track(analytics.ClusterProvisioningStartTrack(
+			&analytics.ClusterProvisioningStartTrackOpts{
+				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
+				ClusterType:            infra.Kind,
+				InfraID:                infra.ID,
+			},
+		))
+	case types.InfraDOCR, types.InfraECR, types.InfraGCR, types.InfraGAR, types.InfraACR:
+		conf.AnalyticsClient.Track(analytics.RegistryProvisioningStartTrack(
+			&analytics.RegistryProvisioningStartTrackOpts{
+				ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(0, infra.ProjectID),
+				RegistryType:           infra.Kind,
+				InfraID:                infra.ID,
+			},
+		))
+	}
+}
@@ -0,0 +1,84 @@
+package provision
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/provisioner"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type ProvisionPlanHandler struct {
+	Config *config.Config
+
+	decoderValidator shared.RequestDecoderValidator
+	resultWriter     shared.ResultWriter
+}
+
+func NewProvisionPlanHandler(
+	config *config.Config,
+) *ProvisionPlanHandler {
+	return &ProvisionPlanHandler{
+		Config:           config,
+		decoderValidator: shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+		resultWriter:     shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	}
+}
+
+// ServeHTTP starts a plan operation, which computes the changes an apply of the values would make
+// without changing any resource. The status of the infra is not changed.
+func (c *ProvisionPlanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	req := &ptypes.PlanBaseRequest{}
+
+	if ok := c.decoderValidator.DecodeAndValidate(w, r, req); !ok {
+		return
+	}
+
+	operation, err := startOperation(c.Config, infra, &startOperationOpts{
+		OperationType: ptypes.OperationTypePlan,
+		OperationKind: provisioner.Plan,
+		Kind:          req.Kind,
+		Values:        req.Values,
+		UsePlanFile:   true,
+		BeforeProvision: func(operation *models.Operation) error {
+			// store the kind of the apply operation to start once the plan is approved. The plan is written
+			// before the provisioner process is spawned, so that it cannot overwrite the planned resources
+			// which the process reports.
+			plan := &ptypes.TFPlan{
+				OperationID:   operation.UID,
+				OperationKind: req.OperationKind,
+				CreatedAt:     time.Now(),
+				Resources:     []ptypes.TFPlannedResource{},
+			}
+
+			planBytes, err := json.Marshal(plan)
+			if err != nil {
+				return err
+			}
+
+			return c.Config.StorageManager.WriteFile(infra, ptypes.GetPlanFileName(models.GetWorkspaceID(infra, operation)), planBytes, true)
+		},
+	})
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	op, err := operation.ToOperationType()
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	c.resultWriter.WriteResult(w, r, op)
+}
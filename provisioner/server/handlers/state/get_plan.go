@@ -0,0 +1,81 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type PlanGetHandler struct {
+	Config       *config.Config
+	resultWriter shared.ResultWriter
+}
+
+func NewPlanGetHandler(
+	config *config.Config,
+) *PlanGetHandler {
+	return &PlanGetHandler{
+		Config:       config,
+		resultWriter: shared.NewDefaultResultWriter(config.Logger, config.Alerter),
+	}
+}
+
+func (c *PlanGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	if operation.Type != ptypes.OperationTypePlan {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is not a plan", operation.UID),
+			http.StatusBadRequest,
+		), true)
+		return
+	}
+
+	workspaceID := models.GetWorkspaceID(infra, operation)
+
+	planBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.GetPlanFileName(workspaceID), true)
+	if err != nil {
+		if errors.Is(err, storage.FileDoesNotExist) {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("plan file does not exist"),
+				http.StatusNotFound,
+			), true)
+
+			return
+		}
+
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	plan := &ptypes.TFPlan{}
+
+	if err := json.Unmarshal(planBytes, plan); err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// the output only exists once the plan was reported
+	outputBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.GetPlanOutputFileName(workspaceID), true)
+	if err != nil && !errors.Is(err, storage.FileDoesNotExist) {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	c.resultWriter.WriteResult(w, r, &ptypes.GetPlanResponse{
+		TFPlan: plan,
+		Status: operation.Status,
+		Output: string(outputBytes),
+	})
+}
@@ -0,0 +1,54 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type PlanFileGetHandler struct {
+	Config *config.Config
+}
+
+func NewPlanFileGetHandler(
+	config *config.Config,
+) *PlanFileGetHandler {
+	return &PlanFileGetHandler{
+		Config: config,
+	}
+}
+
+// ServeHTTP returns the binary plan file of an operation: the plan written by a plan operation, or the
+// approved plan which an apply operation applies
+func (c *PlanFileGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra and operation from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	fileBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.GetPlanBinaryFileName(models.GetWorkspaceID(infra, operation)), true)
+	if err != nil {
+		if errors.Is(err, storage.FileDoesNotExist) {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("operation %s has no plan file", operation.UID),
+				http.StatusNotFound,
+			), true)
+			return
+		}
+
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	if _, err = w.Write(fileBytes); err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+}
@@ -0,0 +1,114 @@
+package state_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors/alerter"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/pkg/logger"
+	"github.com/porter-dev/porter/provisioner/integrations/storage"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	"github.com/porter-dev/porter/provisioner/server/handlers/state"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type memoryStorageManager struct {
+	files map[string][]byte
+}
+
+func (s *memoryStorageManager) WriteFile(infra *models.Infra, name string, bytes []byte, shouldEncrypt bool) error {
+	s.files[name] = bytes
+	return nil
+}
+
+func (s *memoryStorageManager) ReadFile(infra *models.Infra, name string, shouldDecrypt bool) ([]byte, error) {
+	bytes, ok := s.files[name]
+	if !ok {
+		return nil, storage.FileDoesNotExist
+	}
+
+	return bytes, nil
+}
+
+func (s *memoryStorageManager) DeleteFile(infra *models.Infra, name string) error {
+	delete(s.files, name)
+	return nil
+}
+
+func servePlanFile(conf *config.Config, handler http.Handler, method string, infra *models.Infra, operation *models.Operation, body []byte) *httptest.ResponseRecorder {
+	ctx := context.WithValue(context.Background(), types.InfraScope, infra)
+	ctx = context.WithValue(ctx, types.OperationScope, operation)
+
+	req := httptest.NewRequest(method, "/api/v1/workspace/planfile", bytes.NewReader(body)).WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestPlanFile(t *testing.T) {
+	conf := &config.Config{
+		Logger:         logger.New(true, os.Stdout),
+		Alerter:        alerter.NoOpAlerter{},
+		StorageManager: &memoryStorageManager{files: make(map[string][]byte)},
+	}
+
+	infra := &models.Infra{Kind: types.InfraEKS, ProjectID: 1, Suffix: "abcdef"}
+	infra.ID = 1
+
+	operation := &models.Operation{UID: "0123456789abcdef0123", Type: ptypes.OperationTypePlan, Status: "starting"}
+
+	rr := servePlanFile(conf, state.NewPlanFileGetHandler(conf), http.MethodGet, infra, operation, nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status %d before the plan file is uploaded, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	rr = servePlanFile(conf, state.NewPlanFileUpdateHandler(conf), http.MethodPost, infra, operation, []byte("plan"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	rr = servePlanFile(conf, state.NewPlanFileGetHandler(conf), http.MethodGet, infra, operation, nil)
+	if rr.Code != http.StatusOK || rr.Body.String() != "plan" {
+		t.Errorf("expected the uploaded plan file, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// the plan file cannot be replaced once the plan was reported for review
+	operation.Status = ptypes.OperationStatusPlanned
+
+	rr = servePlanFile(conf, state.NewPlanFileUpdateHandler(conf), http.MethodPost, infra, operation, []byte("other plan"))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	rr = servePlanFile(conf, state.NewPlanFileGetHandler(conf), http.MethodGet, infra, operation, nil)
+	if rr.Body.String() != "plan" {
+		t.Errorf("expected the reviewed plan file to be kept, got %s", rr.Body.String())
+	}
+}
+
+func TestPlanFileUpdateRequiresPlanOperation(t *testing.T) {
+	conf := &config.Config{
+		Logger:         logger.New(true, os.Stdout),
+		Alerter:        alerter.NoOpAlerter{},
+		StorageManager: &memoryStorageManager{files: make(map[string][]byte)},
+	}
+
+	infra := &models.Infra{Kind: types.InfraEKS, ProjectID: 1, Suffix: "abcdef"}
+	infra.ID = 1
+
+	operation := &models.Operation{UID: "0123456789abcdef0123", Type: "update", Status: "starting"}
+
+	rr := servePlanFile(conf, state.NewPlanFileUpdateHandler(conf), http.MethodPost, infra, operation, []byte("plan"))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -39,13 +39,18 @@ func (c *ReportErrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// update the infra to indicate error
-	infra.Status = "errored"
+	var err error
 
-	infra, err := c.Config.Repo.Infra().UpdateInfra(infra)
-	if err != nil {
-		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
-		return
+	// update the infra to indicate error. Plan operations do not change any resource, so the
+	// status of the infra is kept.
+	if operation.Type != ptypes.OperationTypePlan {
+		infra.Status = "errored"
+
+		infra, err = c.Config.Repo.Infra().UpdateInfra(infra)
+		if err != nil {
+			apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+			return
+		}
 	}
 
 	// update the operation with the error
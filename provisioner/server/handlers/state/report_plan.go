@@ -0,0 +1,109 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/integrations/redis_stream"
+	"github.com/porter-dev/porter/provisioner/server/config"
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type ReportPlanHandler struct {
+	Config           *config.Config
+	decoderValidator shared.RequestDecoderValidator
+}
+
+func NewReportPlanHandler(
+	config *config.Config,
+) *ReportPlanHandler {
+	return &ReportPlanHandler{
+		Config:           config,
+		decoderValidator: shared.NewDefaultRequestDecoderValidator(config.Logger, config.Alerter),
+	}
+}
+
+// ServeHTTP stores the plan computed by a plan operation, and marks the plan as ready to be reviewed
+func (c *ReportPlanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	req := &ptypes.ReportPlanRequest{}
+
+	if ok := c.decoderValidator.DecodeAndValidate(w, r, req); !ok {
+		return
+	}
+
+	if operation.Type != ptypes.OperationTypePlan {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is not a plan", operation.UID),
+			http.StatusBadRequest,
+		), true)
+		return
+	}
+
+	workspaceID := models.GetWorkspaceID(infra, operation)
+
+	// read the plan written when the operation was started
+	planBytes, err := c.Config.StorageManager.ReadFile(infra, ptypes.GetPlanFileName(workspaceID), true)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	plan := &ptypes.TFPlan{}
+
+	if err := json.Unmarshal(planBytes, plan); err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	plan.SetResources(req.Resources)
+
+	planBytes, err = json.Marshal(plan)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	err = c.Config.StorageManager.WriteFile(infra, ptypes.GetPlanFileName(workspaceID), planBytes, true)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	err = c.Config.StorageManager.WriteFile(infra, ptypes.GetPlanOutputFileName(workspaceID), []byte(req.Output), true)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// update the operation to indicate that the plan can be reviewed
+	operation.Status = ptypes.OperationStatusPlanned
+
+	operation, err = c.Config.Repo.Infra().UpdateOperation(operation)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// push to the operation stream
+	err = redis_stream.SendOperationCompleted(c.Config.RedisClient, infra, operation)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	// push to the global stream
+	err = redis_stream.PushToGlobalStream(c.Config.RedisClient, infra, operation, ptypes.OperationStatusPlanned)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+}
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/provisioner/server/config"
+
+	ptypes "github.com/porter-dev/porter/provisioner/types"
+)
+
+type PlanFileUpdateHandler struct {
+	Config *config.Config
+}
+
+func NewPlanFileUpdateHandler(
+	config *config.Config,
+) *PlanFileUpdateHandler {
+	return &PlanFileUpdateHandler{
+		Config: config,
+	}
+}
+
+// ServeHTTP stores the binary plan file written by terraform plan for a plan operation. The plan file
+// cannot be replaced once the plan was reported, so that the reviewed plan is the one which is applied.
+func (c *PlanFileUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// read the infra and operation from the attached scope
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+	operation, _ := r.Context().Value(types.OperationScope).(*models.Operation)
+
+	if operation.Type != ptypes.OperationTypePlan {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("operation %s is not a plan", operation.UID),
+			http.StatusBadRequest,
+		), true)
+		return
+	}
+
+	if operation.Status == ptypes.OperationStatusPlanned || operation.Status == ptypes.OperationStatusApproved {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("plan file cannot be replaced as its operation has status %s", operation.Status),
+			http.StatusBadRequest,
+		), true)
+		return
+	}
+
+	fileBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+
+	err = c.Config.StorageManager.WriteFile(infra, ptypes.GetPlanBinaryFileName(models.GetWorkspaceID(infra, operation)), fileBytes, true)
+	if err != nil {
+		apierrors.HandleAPIError(c.Config.Logger, c.Config.Alerter, w, r, apierrors.NewErrInternal(err), true)
+		return
+	}
+}
@@ -37,6 +37,9 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 				r.Method("POST", "/{workspace_id}/resource", state.NewCreateResourceHandler(config))
 				r.Method("DELETE", "/{workspace_id}/resource", state.NewDeleteResourceHandler(config))
 				r.Method("POST", "/{workspace_id}/error", state.NewReportErrorHandler(config))
+				r.Method("POST", "/{workspace_id}/plan", state.NewReportPlanHandler(config))
+				r.Method("GET", "/{workspace_id}/planfile", state.NewPlanFileGetHandler(config))
+				r.Method("POST", "/{workspace_id}/planfile", state.NewPlanFileUpdateHandler(config))
 				r.Method("GET", "/{workspace_id}/credentials", credentials.NewCredentialsGetHandler(config))
 			})
 
@@ -59,6 +62,8 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 				// HTTP backend.
 				r.Method("GET", "/{workspace_id}/tfstate/raw", state.NewRawStateGetHandler(config))
 				r.Method("GET", "/{workspace_id}/logs", state.NewLogsGetHandler(config))
+				r.Method("GET", "/{workspace_id}/plan", state.NewPlanGetHandler(config))
+				r.Method("POST", "/{workspace_id}/plan/approve", provision.NewProvisionApprovePlanHandler(config))
 			})
 		})
 
@@ -74,6 +79,7 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 
 			r.Method("GET", "/projects/{project_id}/infras/{infra_id}/state", state.NewStateGetHandler(config))
 			r.Method("POST", "/projects/{project_id}/infras/{infra_id}/apply", provision.NewProvisionApplyHandler(config))
+			r.Method("POST", "/projects/{project_id}/infras/{infra_id}/plan", provision.NewProvisionPlanHandler(config))
 			r.Method("DELETE", "/projects/{project_id}/infras/{infra_id}", provision.NewProvisionDestroyHandler(config))
 		})
 	})
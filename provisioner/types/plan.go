@@ -0,0 +1,95 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// OperationTypePlan is the type of the operations which compute a plan, without changing any resource
+const OperationTypePlan = "plan"
+
+const (
+	// OperationStatusPlanned is the status of a plan operation whose plan is ready to be reviewed
+	OperationStatusPlanned = "planned"
+
+	// OperationStatusApproved is the status of a plan operation whose plan was approved into an apply
+	OperationStatusApproved = "approved"
+)
+
+type PlanBaseRequest struct {
+	Kind   string                 `json:"kind"`
+	Values map[string]interface{} `json:"values"`
+
+	// OperationKind is the kind of the apply operation started when the plan is approved
+	OperationKind string `json:"operation_kind" form:"oneof=create retry_create update"`
+}
+
+type TFPlannedResource struct {
+	ID     string           `json:"id" form:"required"`
+	Status TFResourceStatus `json:"status" form:"oneof=planned_create planned_delete planned_update"`
+}
+
+type ReportPlanRequest struct {
+	// Output is the human-readable output of terraform plan
+	Output string `json:"output"`
+
+	Resources []TFPlannedResource `json:"resources" form:"dive"`
+}
+
+type TFPlanSummary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+type TFPlan struct {
+	OperationID   string              `json:"operation_id"`
+	OperationKind string              `json:"operation_kind"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Summary       TFPlanSummary       `json:"summary"`
+	Resources     []TFPlannedResource `json:"resources"`
+
+	// ApplyOperationID is the ID of the apply operation started when the plan was approved
+	ApplyOperationID string `json:"apply_operation_id,omitempty"`
+}
+
+// SetResources sets the planned resources of the plan and computes its summary
+func (p *TFPlan) SetResources(resources []TFPlannedResource) {
+	p.Resources = resources
+	p.Summary = TFPlanSummary{}
+
+	for _, resource := range resources {
+		switch resource.Status {
+		case TFResourcePlannedCreate:
+			p.Summary.Create++
+		case TFResourcePlannedUpdate:
+			p.Summary.Update++
+		case TFResourcePlannedDelete:
+			p.Summary.Delete++
+		}
+	}
+}
+
+type GetPlanResponse struct {
+	*TFPlan
+
+	// Status is the status of the plan operation
+	Status string `json:"status"`
+	Output string `json:"output"`
+}
+
+// GetPlanFileName returns the name of the file storing the plan of a plan operation
+func GetPlanFileName(workspaceID string) string {
+	return fmt.Sprintf("%s-plan.json", workspaceID)
+}
+
+// GetPlanBinaryFileName returns the name of the file storing the binary plan file written by terraform plan,
+// which is applied as is once the plan is approved
+func GetPlanBinaryFileName(workspaceID string) string {
+	return fmt.Sprintf("%s-plan.tfplan", workspaceID)
+}
+
+// GetPlanOutputFileName returns the name of the file storing the output of terraform plan
+func GetPlanOutputFileName(workspaceID string) string {
+	return fmt.Sprintf("%s-plan.txt", workspaceID)
+}
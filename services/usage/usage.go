@@ -4,14 +4,15 @@
 package usage
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/api/types"
-	"github.com/porter-dev/porter/ee/integrations/vault"
 	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/encryption/kms"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository"
@@ -19,7 +20,7 @@ import (
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
 
-	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
 )
 
@@ -47,14 +48,9 @@ func NewUsageTracker(opts *UsageTrackerOpts) (*UsageTracker, error) {
 		return nil, err
 	}
 
-	var credBackend credentials.CredentialStorage
-
-	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
-		credBackend = vault.NewClient(
-			opts.DBConf.VaultServerURL,
-			opts.DBConf.VaultAPIKey,
-			opts.DBConf.VaultPrefix,
-		)
+	credBackend, err := secretstore.NewCredentialStorage(context.Background(), opts.DBConf)
+	if err != nil {
+		return nil, err
 	}
 
 	var key [32]byte
@@ -63,6 +59,10 @@ func NewUsageTracker(opts *UsageTrackerOpts) (*UsageTracker, error) {
 		key[i] = b
 	}
 
+	if err := kms.SetKeyProviderFromConf(context.Background(), opts.DBConf); err != nil {
+		return nil, err
+	}
+
 	repo := rgorm.NewRepository(db, &key, credBackend)
 
 	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
@@ -21,7 +21,16 @@ ARCHITECTURE
     to correctly relay execution information to the correct job.
   - The worker pool has an exposed HTTP POST endpoint to enqueue jobs with their IDs. Depending on the kind of job,
     a job can expect to receive a body of JSON data in the HTTP request.
-  - By exposing an HTTP endpoint, the worker pool can be called to enqueue jobs using crontab and other sources.
+  - By exposing an HTTP endpoint, the worker pool can be called to enqueue jobs from other sources.
+  - Enqueued jobs are stored in the database before they run, so that they survive restarts. A failing job is
+    retried with exponential backoff (`JOB_RETRY_BACKOFF`, `JOB_MAX_RETRY_BACKOFF`) up to `JOB_MAX_ATTEMPTS` times,
+    after which it is moved to a dead-letter list.
+  - The status and history of jobs can be checked with the HTTP GET endpoints `/runs/{run_id}`, `/jobs/{id}/runs`
+    and `/runs/dead-letter`. A dead job can be retried with the HTTP POST endpoint `/runs/{run_id}/requeue`.
+  - Jobs can also be enqueued periodically by the built-in scheduler, configured with cron expressions in
+    `JOB_SCHEDULES`. Each scheduled run is claimed in the database before it is enqueued, so that only one replica
+    enqueues it. The last and next run of each scheduled job is returned by the HTTP GET endpoint `/schedules`.
+  - Jobs which produce a report, such as `dns-records-gc`, store it as the `result` of their run.
 
 */
 
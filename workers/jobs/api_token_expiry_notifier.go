@@ -0,0 +1,207 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/notifier"
+	"github.com/porter-dev/porter/internal/notifier/sendgrid"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"gorm.io/gorm"
+)
+
+/*
+
+                         === API Token Expiry Notifier Job ===
+
+   This job emails the creators of the API tokens which expire within the notice period, so that the
+   tokens can be rotated before they stop working. Each token is only notified once: the notification
+   time is stored on the token, and is reset when a rotation sets a new expiry.
+
+   The notice period defaults to API_TOKEN_EXPIRY_NOTICE, and can be overridden with the "notice" input
+   (a duration such as "72h") when the job is enqueued.
+
+*/
+
+type apiTokenExpiryNotifier struct {
+	enqueueTime  time.Time
+	db           *gorm.DB
+	repo         repository.Repository
+	userNotifier notifier.UserNotifier
+	serverURL    string
+	notice       time.Duration
+
+	report *APITokenExpiryNotifierReport
+}
+
+// APITokenExpiryNotifierOpts holds the options required to run this job
+type APITokenExpiryNotifierOpts struct {
+	DBConf    *env.DBConf
+	ServerURL string
+
+	SendgridAPIKey      string
+	SendgridSenderEmail string
+	SendgridTemplateID  string
+
+	// Notice is the default notice period of the job, if the input does not set "notice"
+	Notice time.Duration
+
+	Input map[string]interface{}
+}
+
+type apiTokenExpiryNotifierInput struct {
+	Notice string `mapstructure:"notice"`
+}
+
+// APITokenExpiryNotifierReport is the result of a run of the api token expiry notifier
+type APITokenExpiryNotifierReport struct {
+	// Notified are the unique ids of the tokens whose creators were emailed
+	Notified []string `json:"notified"`
+
+	// Failed are the unique ids of the tokens whose creators could not be emailed
+	Failed []string `json:"failed"`
+}
+
+// NewAPITokenExpiryNotifier creates a new api token expiry notifier job
+func NewAPITokenExpiryNotifier(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *APITokenExpiryNotifierOpts,
+) (*apiTokenExpiryNotifier, error) {
+	credBackend, err := secretstore.NewCredentialStorage(context.Background(), opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
+	}
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	parsedInput := &apiTokenExpiryNotifierInput{}
+	if err := mapstructure.Decode(opts.Input, parsedInput); err != nil {
+		return nil, err
+	}
+
+	notice := opts.Notice
+	if parsedInput.Notice != "" {
+		var err error
+
+		notice, err = time.ParseDuration(parsedInput.Notice)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notice %s: %w", parsedInput.Notice, err)
+		}
+	}
+
+	if notice <= 0 {
+		return nil, fmt.Errorf("the notice period must be positive")
+	}
+
+	if opts.SendgridAPIKey == "" || opts.SendgridSenderEmail == "" || opts.SendgridTemplateID == "" {
+		return nil, fmt.Errorf("sendgrid must be configured to notify api token creators")
+	}
+
+	userNotifier := sendgrid.NewUserNotifier(&sendgrid.UserNotifierOpts{
+		SharedOpts: &sendgrid.SharedOpts{
+			APIKey:      opts.SendgridAPIKey,
+			SenderEmail: opts.SendgridSenderEmail,
+		},
+		APITokenExpiryTemplateID: opts.SendgridTemplateID,
+	})
+
+	return &apiTokenExpiryNotifier{
+		enqueueTime:  enqueueTime,
+		db:           db,
+		repo:         repo,
+		userNotifier: userNotifier,
+		serverURL:    opts.ServerURL,
+		notice:       notice,
+	}, nil
+}
+
+func (n *apiTokenExpiryNotifier) ID() string {
+	return "api-token-expiry-notifier"
+}
+
+func (n *apiTokenExpiryNotifier) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *apiTokenExpiryNotifier) Run(ctx context.Context) error {
+	n.report = &APITokenExpiryNotifierReport{
+		Notified: []string{},
+		Failed:   []string{},
+	}
+
+	tokens, err := n.repo.APIToken().ListAPITokensExpiringBefore(time.Now().Add(n.notice))
+	if err != nil {
+		return fmt.Errorf("error listing expiring api tokens: %w", err)
+	}
+
+	for _, token := range tokens {
+		user, err := n.repo.User().ReadUser(token.CreatedByUserID)
+		if err != nil {
+			log.Printf("error reading creator of api token %s: %v", token.UniqueID, err)
+			n.report.Failed = append(n.report.Failed, token.UniqueID)
+			continue
+		}
+
+		project, err := n.repo.Project().ReadProject(token.ProjectID)
+		if err != nil {
+			log.Printf("error reading project of api token %s: %v", token.UniqueID, err)
+			n.report.Failed = append(n.report.Failed, token.UniqueID)
+			continue
+		}
+
+		err = n.userNotifier.SendAPITokenExpiryEmail(&notifier.SendAPITokenExpiryEmailOpts{
+			Email:     user.Email,
+			Project:   project.Name,
+			TokenName: token.Name,
+			ExpiresAt: *token.Expiry,
+			URL:       fmt.Sprintf("%s/project-settings", n.serverURL),
+		})
+		if err != nil {
+			log.Printf("error emailing creator of api token %s: %v", token.UniqueID, err)
+			n.report.Failed = append(n.report.Failed, token.UniqueID)
+			continue
+		}
+
+		notifiedAt := time.Now()
+		token.ExpiryNotifiedAt = &notifiedAt
+
+		if _, err := n.repo.APIToken().UpdateAPIToken(token); err != nil {
+			log.Printf("error storing notification time of api token %s: %v", token.UniqueID, err)
+			n.report.Failed = append(n.report.Failed, token.UniqueID)
+			continue
+		}
+
+		n.report.Notified = append(n.report.Notified, token.UniqueID)
+	}
+
+	log.Printf("api token expiry notifier: notified %d, failed %d", len(n.report.Notified), len(n.report.Failed))
+
+	return nil
+}
+
+func (n *apiTokenExpiryNotifier) SetData([]byte) {}
+
+// Result returns the JSON-encoded report of the last run
+func (n *apiTokenExpiryNotifier) Result() ([]byte, error) {
+	if n.report == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(n.report)
+}
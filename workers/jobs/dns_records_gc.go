@@ -0,0 +1,391 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/integrations/dns"
+	"github.com/porter-dev/porter/internal/integrations/dnsprovider"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/domain"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/*
+
+                         === DNS Records Garbage Collector Job ===
+
+   This job cross-checks the Porter subdomains stored in the database against the hosts of the ingresses in
+   every connected cluster, and deletes the records which are no longer served by any ingress from the DNS
+   provider and the database. Ingresses belonging to deleted deployment targets are not counted as live.
+
+   Records created less than DNS_RECORDS_GC_MIN_AGE (24 hours by default) ago are skipped, since their
+   ingress may not have been created yet. Records of clusters which could not be reached are skipped. Records
+   created before the cluster of a record was stored are only deleted if every cluster could be reached.
+
+   In dry-run mode, the stale records are only reported. The mode defaults to DNS_RECORDS_GC_DRY_RUN, and can
+   be overridden with the "dry_run" input when the job is enqueued.
+
+*/
+
+// defaultDNSRecordsGCMinAge is the minimum age of a record before it is garbage collected, if none is set
+const defaultDNSRecordsGCMinAge = 24 * time.Hour
+
+type dnsRecordsGC struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	doConf      *oauth2.Config
+	repo        repository.Repository
+	dnsClient   *dns.Client
+	dryRun      bool
+	minAge      time.Duration
+
+	report *DNSRecordsGCReport
+}
+
+// DNSRecordsGCOpts holds the options required to run this job
+type DNSRecordsGCOpts struct {
+	DBConf         *env.DBConf
+	DNSConf        env.DNSConf
+	AppRootDomain  string
+	ServerURL      string
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+
+	// DryRun is the default mode of the job, if the input does not set "dry_run"
+	DryRun bool
+
+	// MinAge is the minimum age of a record before it is garbage collected, and defaults to 24 hours
+	MinAge time.Duration
+
+	Input map[string]interface{}
+}
+
+type dnsRecordsGCInput struct {
+	DryRun *bool `mapstructure:"dry_run"`
+}
+
+// DNSRecordsGCReport is the result of a run of the dns records garbage collector
+type DNSRecordsGCReport struct {
+	DryRun bool `json:"dry_run"`
+
+	// Removed are the stale records, which were deleted unless this is a dry run
+	Removed []DNSRecordsGCEntry `json:"removed"`
+
+	// Failed are the stale records which could not be deleted
+	Failed []DNSRecordsGCEntry `json:"failed"`
+
+	// Skipped are the records which could not be checked because their cluster was unreachable
+	Skipped []DNSRecordsGCEntry `json:"skipped"`
+}
+
+// DNSRecordsGCEntry describes a dns record handled by the garbage collector
+type DNSRecordsGCEntry struct {
+	Hostname  string `json:"hostname"`
+	Endpoint  string `json:"endpoint"`
+	ClusterID uint   `json:"cluster_id"`
+	Reason    string `json:"reason"`
+}
+
+// NewDNSRecordsGC creates a new dns records garbage collector job
+func NewDNSRecordsGC(
+	ctx context.Context,
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *DNSRecordsGCOpts,
+) (*dnsRecordsGC, error) {
+	credBackend, err := secretstore.NewCredentialStorage(ctx, opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	parsedInput := &dnsRecordsGCInput{}
+	if err := mapstructure.Decode(opts.Input, parsedInput); err != nil {
+		return nil, err
+	}
+
+	dryRun := opts.DryRun
+	if parsedInput.DryRun != nil {
+		dryRun = *parsedInput.DryRun
+	}
+
+	dnsClient, err := dnsprovider.NewClient(ctx, opts.DNSConf, opts.AppRootDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	if dnsClient == nil && !dryRun {
+		return nil, fmt.Errorf("a dns provider must be configured to delete dns records")
+	}
+
+	minAge := opts.MinAge
+	if minAge <= 0 {
+		minAge = defaultDNSRecordsGCMinAge
+	}
+
+	return &dnsRecordsGC{
+		enqueueTime: enqueueTime,
+		db:          db,
+		doConf:      doConf,
+		repo:        repo,
+		dnsClient:   dnsClient,
+		dryRun:      dryRun,
+		minAge:      minAge,
+	}, nil
+}
+
+func (n *dnsRecordsGC) ID() string {
+	return "dns-records-gc"
+}
+
+func (n *dnsRecordsGC) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+// clusterHosts holds the hosts served by the ingresses of a cluster
+type clusterHosts struct {
+	reachable bool
+	hosts     map[string]bool
+}
+
+func (n *dnsRecordsGC) Run(ctx context.Context) error {
+	n.report = &DNSRecordsGCReport{
+		DryRun:  n.dryRun,
+		Removed: []DNSRecordsGCEntry{},
+		Failed:  []DNSRecordsGCEntry{},
+		Skipped: []DNSRecordsGCEntry{},
+	}
+
+	records, err := n.repo.DNSRecord().ListDNSRecords()
+	if err != nil {
+		return fmt.Errorf("error listing dns records: %w", err)
+	}
+
+	log.Printf("found %d dns records", len(records))
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	var count int64
+
+	if err := n.db.Model(&models.Cluster{}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	clusters := make(map[uint]*clusterHosts)
+	allReachable := true
+
+	for i := 0; i < (int(count)/stepSize)+1; i++ {
+		var page []*models.Cluster
+
+		if err := n.db.Order("id asc").Offset(i * stepSize).Limit(stepSize).Find(&page).
+			Error; err != nil {
+			return err
+		}
+
+		for _, cluster := range page {
+			hosts, err := n.liveHosts(ctx, cluster)
+			if err != nil {
+				log.Printf("error listing ingress hosts for cluster %d: %v. skipping its dns records ...", cluster.ID, err)
+
+				clusters[cluster.ID] = &clusterHosts{reachable: false}
+				allReachable = false
+
+				continue
+			}
+
+			clusters[cluster.ID] = &clusterHosts{reachable: true, hosts: hosts}
+		}
+	}
+
+	for _, record := range records {
+		hostname := strings.ToLower(record.Hostname)
+
+		entry := DNSRecordsGCEntry{
+			Hostname:  record.Hostname,
+			Endpoint:  record.Endpoint,
+			ClusterID: record.ClusterID,
+		}
+
+		if record.CreatedAt.After(n.enqueueTime.Add(-n.minAge)) {
+			entry.Reason = fmt.Sprintf("record was created less than %s ago", n.minAge)
+			n.report.Skipped = append(n.report.Skipped, entry)
+			continue
+		}
+
+		if record.ClusterID != 0 {
+			cluster, ok := clusters[record.ClusterID]
+
+			switch {
+			case !ok:
+				entry.Reason = "cluster has been deleted"
+			case !cluster.reachable:
+				entry.Reason = "cluster is unreachable"
+				n.report.Skipped = append(n.report.Skipped, entry)
+				continue
+			case cluster.hosts[hostname]:
+				continue
+			default:
+				entry.Reason = "no ingress in the cluster serves the hostname"
+			}
+		} else {
+			if !allReachable {
+				entry.Reason = "record has no cluster and some clusters are unreachable"
+				n.report.Skipped = append(n.report.Skipped, entry)
+				continue
+			}
+
+			live := false
+			for _, cluster := range clusters {
+				if cluster.hosts[hostname] {
+					live = true
+					break
+				}
+			}
+
+			if live {
+				continue
+			}
+
+			entry.Reason = "no ingress in any cluster serves the hostname"
+		}
+
+		if n.dryRun {
+			log.Printf("[dry run] would delete dns record %s -> %s: %s", record.Hostname, record.Endpoint, entry.Reason)
+			n.report.Removed = append(n.report.Removed, entry)
+			continue
+		}
+
+		if err := n.deleteRecord(record); err != nil {
+			log.Printf("error deleting dns record %s: %v", record.Hostname, err)
+
+			entry.Reason = fmt.Sprintf("%s, but deletion failed: %v", entry.Reason, err)
+			n.report.Failed = append(n.report.Failed, entry)
+
+			continue
+		}
+
+		log.Printf("deleted dns record %s -> %s: %s", record.Hostname, record.Endpoint, entry.Reason)
+		n.report.Removed = append(n.report.Removed, entry)
+	}
+
+	log.Printf("finished garbage collection of dns records: %d removed, %d failed, %d skipped (dry run: %t)",
+		len(n.report.Removed), len(n.report.Failed), len(n.report.Skipped), n.dryRun)
+
+	return nil
+}
+
+// liveHosts returns the hosts of the ingresses in the cluster, excluding the ingresses of deleted deployment targets
+func (n *dnsRecordsGC) liveHosts(ctx context.Context, cluster *models.Cluster) (map[string]bool, error) {
+	// read the cluster through the repository so that its credentials are decrypted
+	cluster, err := n.repo.Cluster().ReadCluster(cluster.ProjectID, cluster.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cluster: %w", err)
+	}
+
+	deploymentTargets := make(map[string]bool)
+
+	for _, preview := range []bool{false, true} {
+		targets, err := n.repo.DeploymentTarget().ListForCluster(cluster.ProjectID, cluster.ID, preview)
+		if err != nil {
+			return nil, fmt.Errorf("error listing deployment targets: %w", err)
+		}
+
+		for _, target := range targets {
+			deploymentTargets[target.ID.String()] = true
+		}
+	}
+
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting k8s agent: %w", err)
+	}
+
+	ingresses, err := k8sAgent.Clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing ingresses: %w", err)
+	}
+
+	hosts := make(map[string]bool)
+
+	for _, ingress := range ingresses.Items {
+		if targetID, ok := ingress.Labels[porter_app.LabelKey_DeploymentTargetID]; ok && !deploymentTargets[targetID] {
+			continue
+		}
+
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				hosts[strings.ToLower(rule.Host)] = true
+			}
+		}
+
+		for _, tls := range ingress.Spec.TLS {
+			for _, host := range tls.Hosts {
+				hosts[strings.ToLower(host)] = true
+			}
+		}
+	}
+
+	return hosts, nil
+}
+
+// deleteRecord deletes the record from the dns provider, and then from the database
+func (n *dnsRecordsGC) deleteRecord(record *models.DNSRecord) error {
+	_record := domain.DNSRecord(*record)
+
+	if err := _record.DeleteDomain(n.dnsClient); err != nil {
+		return err
+	}
+
+	return n.repo.DNSRecord().DeleteDNSRecord(record)
+}
+
+func (n *dnsRecordsGC) SetData([]byte) {}
+
+// Result returns the JSON-encoded report of the last run
+func (n *dnsRecordsGC) Result() ([]byte, error) {
+	if n.report == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(n.report)
+}
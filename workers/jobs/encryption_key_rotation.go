@@ -0,0 +1,730 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	eemodels "github.com/porter-dev/porter/ee/models"
+	"github.com/porter-dev/porter/internal/encryption"
+	"github.com/porter-dev/porter/internal/models"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+
+                         === Encryption Key Rotation Job ===
+
+   This job re-encrypts the encrypted columns of every table under the current key, which is the current
+   key of the key provider if envelope encryption is enabled, or the static ENCRYPTION_KEY otherwise. It
+   replaces the offline cmd/migrate/keyrotate tool, so that keys can be rotated while the servers run:
+
+   1. Configure the API server, the provisioner and the workers with the new key. When rotating the
+      static key, also set OLD_ENCRYPTION_KEY on each of them to the previous key, so that values
+      encrypted under either key can be read while the rotation runs. When rotating the key of the key
+      provider, the provider keeps unwrapping data keys wrapped by previous keys.
+   2. Enqueue this job. Tables are processed in batches of rows ordered by id, and the progress of each
+      table is stored in the database after every batch, so an interrupted run resumes after the last
+      processed row. Values which are already encrypted under the current key are skipped. The ids of
+      rows which cannot be re-encrypted are stored with the progress of their table and retried by the
+      next run, and the run fails so that the queue retries it.
+   3. Follow the progress with GET /encryption-key-rotation on the workers service. A table is completed
+      once every row has been re-encrypted, including the rows which failed before. Once every table is
+      completed, the previous key can be removed.
+
+   Integrations whose credentials are kept in a credential storage backend (Vault, AWS Secrets Manager
+   or GCP Secret Manager) store them encrypted under the same key, so the credentials of every such
+   integration are re-encrypted in the backend as well, with their progress tracked like a table.
+
+   Enqueueing the job with the "restart" input processes every table again from the start.
+
+*/
+
+// encryptedTable lists the struct fields of a model which are encrypted before storage
+type encryptedTable struct {
+	model  interface{}
+	fields []string
+}
+
+var encryptedTables = []encryptedTable{
+	{&models.Cluster{}, []string{"CertificateAuthorityData"}},
+	{&models.ClusterCandidate{}, []string{"AWSClusterIDGuess", "Kubeconfig"}},
+	{&models.Infra{}, []string{"LastApplied"}},
+	{&models.Operation{}, []string{"LastApplied"}},
+	{&models.SSOConnection{}, []string{"ClientSecret"}},
+	{&ints.ClusterTokenCache{}, []string{"Token"}},
+	{&ints.RegTokenCache{}, []string{"Token"}},
+	{&ints.HelmRepoTokenCache{}, []string{"Token"}},
+	{&ints.KubeIntegration{}, []string{"ClientCertificateData", "ClientKeyData", "Token", "Username", "Password", "Kubeconfig"}},
+	{&ints.BasicIntegration{}, []string{"Username", "Password"}},
+	{&ints.OIDCIntegration{}, []string{"IssuerURL", "ClientID", "ClientSecret", "CertificateAuthorityData", "IDToken", "RefreshToken"}},
+	{&ints.OAuthIntegration{}, []string{"ClientID", "AccessToken", "RefreshToken"}},
+	{&ints.GCPIntegration{}, []string{"GCPKeyData"}},
+	{&ints.AWSIntegration{}, []string{"AWSClusterID", "AWSAccessKeyID", "AWSSecretAccessKey", "AWSSessionToken"}},
+	{&ints.AzureIntegration{}, []string{"ServicePrincipalSecret", "ACRPassword1", "ACRPassword2", "AKSPassword"}},
+	{&ints.GitlabIntegration{}, []string{"AppClientID", "AppClientSecret"}},
+	{&ints.SlackIntegration{}, []string{"ClientID", "AccessToken", "RefreshToken", "Webhook"}},
+	{&ints.NotifierIntegration{}, []string{"URL", "Secret"}},
+	{&eemodels.UserBilling{}, []string{"Token"}},
+}
+
+// storedCredential re-encrypts the credentials of a kind of integration which are kept in the credential
+// storage backend rather than on the integration
+type storedCredential struct {
+	// name identifies the progress of the credentials in the rotation status
+	name  string
+	model interface{}
+
+	// rotate re-encrypts the credential of an integration in the backend with reencrypt, and returns false if
+	// it was already encrypted under the current key
+	rotate func(backend credentials.CredentialStorage, projectID, id uint, reencrypt valuesReencrypter) (bool, error)
+}
+
+// valuesReencrypter re-encrypts the values which are not encrypted under the current key in place, and returns
+// false if none were
+type valuesReencrypter func(values ...*[]byte) (bool, error)
+
+var storedCredentials = []storedCredential{
+	{
+		name:  "credential_storage/oauth",
+		model: &ints.OAuthIntegration{},
+		rotate: func(backend credentials.CredentialStorage, projectID, id uint, reencrypt valuesReencrypter) (bool, error) {
+			integration := &ints.OAuthIntegration{ProjectID: projectID}
+			integration.ID = id
+
+			credential, err := backend.GetOAuthCredential(integration)
+			if err != nil {
+				return false, err
+			}
+
+			rotated, err := reencrypt(&credential.ClientID, &credential.AccessToken, &credential.RefreshToken)
+			if err != nil || !rotated {
+				return false, err
+			}
+
+			return true, backend.WriteOAuthCredential(integration, credential)
+		},
+	},
+	{
+		name:  "credential_storage/gcp",
+		model: &ints.GCPIntegration{},
+		rotate: func(backend credentials.CredentialStorage, projectID, id uint, reencrypt valuesReencrypter) (bool, error) {
+			integration := &ints.GCPIntegration{ProjectID: projectID}
+			integration.ID = id
+
+			credential, err := backend.GetGCPCredential(integration)
+			if err != nil {
+				return false, err
+			}
+
+			rotated, err := reencrypt(&credential.GCPKeyData)
+			if err != nil || !rotated {
+				return false, err
+			}
+
+			return true, backend.WriteGCPCredential(integration, credential)
+		},
+	},
+	{
+		name:  "credential_storage/aws",
+		model: &ints.AWSIntegration{},
+		rotate: func(backend credentials.CredentialStorage, projectID, id uint, reencrypt valuesReencrypter) (bool, error) {
+			integration := &ints.AWSIntegration{ProjectID: projectID}
+			integration.ID = id
+
+			credential, err := backend.GetAWSCredential(integration)
+			if err != nil {
+				return false, err
+			}
+
+			rotated, err := reencrypt(
+				&credential.AWSClusterID,
+				&credential.AWSAccessKeyID,
+				&credential.AWSSecretAccessKey,
+				&credential.AWSSessionToken,
+			)
+			if err != nil || !rotated {
+				return false, err
+			}
+
+			return true, backend.WriteAWSCredential(integration, credential)
+		},
+	},
+	{
+		name:  "credential_storage/azure",
+		model: &ints.AzureIntegration{},
+		rotate: func(backend credentials.CredentialStorage, projectID, id uint, reencrypt valuesReencrypter) (bool, error) {
+			integration := &ints.AzureIntegration{ProjectID: projectID}
+			integration.ID = id
+
+			credential, err := backend.GetAzureCredential(integration)
+			if err != nil {
+				return false, err
+			}
+
+			rotated, err := reencrypt(
+				&credential.ServicePrincipalSecret,
+				&credential.ACRPassword1,
+				&credential.ACRPassword2,
+				&credential.AKSPassword,
+			)
+			if err != nil || !rotated {
+				return false, err
+			}
+
+			return true, backend.WriteAzureCredential(integration, credential)
+		},
+	},
+	{
+		name:  "credential_storage/gitlab",
+		model: &ints.GitlabIntegration{},
+		rotate: func(backend credentials.CredentialStorage, projectID, id uint, reencrypt valuesReencrypter) (bool, error) {
+			integration := &ints.GitlabIntegration{ProjectID: projectID}
+			integration.ID = id
+
+			credential, err := backend.GetGitlabCredential(integration)
+			if err != nil {
+				return false, err
+			}
+
+			rotated, err := reencrypt(&credential.AppClientID, &credential.AppClientSecret)
+			if err != nil || !rotated {
+				return false, err
+			}
+
+			return true, backend.WriteGitlabCredential(integration, credential)
+		},
+	},
+}
+
+// rowRotator re-encrypts a row of a table, and returns false if it was already encrypted under the current key
+type rowRotator func(tableName string, columns []string, id uint, row map[string]interface{}) (bool, error)
+
+// maxRowRotationAttempts bounds how often a row which changes while it is re-encrypted is read again
+const maxRowRotationAttempts = 3
+
+type encryptionKeyRotation struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	repo        repository.Repository
+	credBackend credentials.CredentialStorage
+	newKey      *[32]byte
+	keyID       string
+	batchSize   int
+	restart     bool
+
+	status *types.EncryptionKeyRotationStatus
+}
+
+// EncryptionKeyRotationOpts holds the options required to run this job
+type EncryptionKeyRotationOpts struct {
+	DBConf *env.DBConf
+
+	// BatchSize is the number of rows re-encrypted between two updates of the progress of a table
+	BatchSize int
+
+	Input map[string]interface{}
+}
+
+type encryptionKeyRotationInput struct {
+	Restart bool `mapstructure:"restart"`
+}
+
+// NewEncryptionKeyRotation creates a new encryption key rotation job
+func NewEncryptionKeyRotation(
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *EncryptionKeyRotationOpts,
+) (*encryptionKeyRotation, error) {
+	credBackend, err := secretstore.NewCredentialStorage(context.Background(), opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
+	}
+
+	newKey := keyFromString(opts.DBConf.EncryptionKey)
+
+	repo := rgorm.NewRepository(db, newKey, credBackend)
+
+	parsedInput := &encryptionKeyRotationInput{}
+	if err := mapstructure.Decode(opts.Input, parsedInput); err != nil {
+		return nil, err
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &encryptionKeyRotation{
+		enqueueTime: enqueueTime,
+		db:          db,
+		repo:        repo,
+		credBackend: credBackend,
+		newKey:      newKey,
+		keyID:       EncryptionKeyRotationKeyID(newKey),
+		batchSize:   batchSize,
+		restart:     parsedInput.Restart,
+	}, nil
+}
+
+// EncryptionKeyRotationKeyID returns the id of the key which values are re-encrypted under: the current key
+// of the key provider, or the given static key if no key provider is set
+func EncryptionKeyRotationKeyID(staticKey *[32]byte) string {
+	if keyID := encryption.CurrentKeyID(); keyID != "" {
+		return keyID
+	}
+
+	return encryption.StaticKeyID(staticKey)
+}
+
+// GetEncryptionKeyRotationStatus returns the progress of the re-encryption of every table under a key, and of
+// the credentials kept in the credential storage backend if one is configured
+func GetEncryptionKeyRotationStatus(
+	ctx context.Context,
+	repo repository.Repository,
+	keyID string,
+	credBackend credentials.CredentialStorage,
+) (*types.EncryptionKeyRotationStatus, error) {
+	rotations, err := repo.EncryptionKeyRotation().ListEncryptionKeyRotations(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.EncryptionKeyRotationStatus{
+		KeyID:  keyID,
+		Tables: make([]*types.EncryptionKeyRotationTable, 0, len(rotations)),
+	}
+
+	completed := 0
+
+	for _, rotation := range rotations {
+		res.Tables = append(res.Tables, rotation.ToEncryptionKeyRotationTableType())
+
+		if rotation.CompletedAt != nil {
+			completed++
+		}
+	}
+
+	expected := len(encryptedTables)
+	if credBackend != nil {
+		expected += len(storedCredentials)
+	}
+
+	res.Completed = completed >= expected
+
+	return res, nil
+}
+
+func (n *encryptionKeyRotation) ID() string {
+	return "encryption-key-rotation"
+}
+
+func (n *encryptionKeyRotation) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *encryptionKeyRotation) Run(ctx context.Context) error {
+	log.Printf("rotating encrypted values to key %s", n.keyID)
+
+	for _, table := range encryptedTables {
+		if err := n.rotateTable(ctx, "", table.model, table.fields, n.rotateRow); err != nil {
+			return err
+		}
+	}
+
+	if n.credBackend != nil {
+		for _, credential := range storedCredentials {
+			if err := n.rotateTable(ctx, credential.name, credential.model, []string{"ProjectID"}, n.storedCredentialRotator(credential)); err != nil {
+				return err
+			}
+		}
+	}
+
+	status, err := GetEncryptionKeyRotationStatus(ctx, n.repo, n.keyID, n.credBackend)
+	if err != nil {
+		return fmt.Errorf("error reading encryption key rotation status: %w", err)
+	}
+
+	n.status = status
+
+	var failed uint
+	for _, table := range status.Tables {
+		failed += table.Failed
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d rows could not be re-encrypted under key %s and are retried by the next run", failed, n.keyID)
+	}
+
+	return nil
+}
+
+// rotateTable re-encrypts the rows of the table of a model with rotate, reading the given fields of each row.
+// Its progress is stored under the name of the table, or under progressName if it is set.
+func (n *encryptionKeyRotation) rotateTable(
+	ctx context.Context,
+	progressName string,
+	model interface{},
+	fields []string,
+	rotate rowRotator,
+) error {
+	stmt := &gorm.Statement{DB: n.db}
+
+	if err := stmt.Parse(model); err != nil {
+		return fmt.Errorf("error parsing model schema: %w", err)
+	}
+
+	tableName := stmt.Schema.Table
+	columns := []string{"id"}
+
+	if progressName == "" {
+		progressName = tableName
+	}
+
+	for _, fieldName := range fields {
+		field := stmt.Schema.LookUpField(fieldName)
+		if field == nil {
+			return fmt.Errorf("field %s not found in table %s", fieldName, tableName)
+		}
+
+		columns = append(columns, field.DBName)
+	}
+
+	rotation, err := n.repo.EncryptionKeyRotation().ReadEncryptionKeyRotation(ctx, n.keyID, progressName)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error reading rotation progress of %s: %w", progressName, err)
+		}
+
+		rotation, err = n.repo.EncryptionKeyRotation().CreateEncryptionKeyRotation(ctx, &models.EncryptionKeyRotation{
+			KeyID:      n.keyID,
+			ModelTable: progressName,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating rotation progress of %s: %w", progressName, err)
+		}
+	} else if n.restart {
+		rotation.LastID = 0
+		rotation.Rotated = 0
+		rotation.Skipped = 0
+		rotation.SetFailedIDs(nil)
+		rotation.LastError = ""
+		rotation.CompletedAt = nil
+	}
+
+	if rotation.CompletedAt != nil {
+		return nil
+	}
+
+	if err := n.retryFailedRows(ctx, tableName, columns, rotation, rotate); err != nil {
+		return err
+	}
+
+	failedIDs := rotation.FailedIDList()
+
+	for {
+		rows := []map[string]interface{}{}
+
+		err := n.db.Table(tableName).
+			Select(columns).
+			Where("id > ?", rotation.LastID).
+			Order("id asc").
+			Limit(n.batchSize).
+			Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("error reading rows of table %s: %w", tableName, err)
+		}
+
+		for _, row := range rows {
+			id, err := rowID(row["id"])
+			if err != nil {
+				return fmt.Errorf("error reading row id of table %s: %w", tableName, err)
+			}
+
+			rotated, err := rotate(tableName, columns[1:], id, row)
+			failedIDs = countRotation(rotation, id, rotated, err, failedIDs)
+
+			rotation.LastID = id
+		}
+
+		rotation.SetFailedIDs(failedIDs)
+
+		// the table is only completed once no rows are left to retry
+		scanned := len(rows) < n.batchSize
+		if scanned && len(failedIDs) == 0 {
+			completedAt := time.Now().UTC()
+			rotation.CompletedAt = &completedAt
+		}
+
+		if _, err := n.repo.EncryptionKeyRotation().UpdateEncryptionKeyRotation(ctx, rotation); err != nil {
+			return fmt.Errorf("error storing rotation progress of table %s: %w", tableName, err)
+		}
+
+		if scanned {
+			log.Printf("rotated %s: %d rotated, %d skipped, %d failed", progressName, rotation.Rotated, rotation.Skipped, rotation.Failed)
+			return nil
+		}
+	}
+}
+
+// retryFailedRows re-encrypts the rows of a table which could not be re-encrypted by previous runs, and keeps
+// the ids of the rows which fail again. Rows which were deleted since are dropped.
+func (n *encryptionKeyRotation) retryFailedRows(
+	ctx context.Context,
+	tableName string,
+	columns []string,
+	rotation *models.EncryptionKeyRotation,
+	rotate rowRotator,
+) error {
+	failedIDs := rotation.FailedIDList()
+	if len(failedIDs) == 0 {
+		return nil
+	}
+
+	rows := []map[string]interface{}{}
+
+	err := n.db.Table(tableName).
+		Select(columns).
+		Where("id IN ?", failedIDs).
+		Order("id asc").
+		Find(&rows).Error
+	if err != nil {
+		return fmt.Errorf("error reading failed rows of table %s: %w", tableName, err)
+	}
+
+	stillFailed := []uint{}
+
+	for _, row := range rows {
+		id, err := rowID(row["id"])
+		if err != nil {
+			return fmt.Errorf("error reading row id of table %s: %w", tableName, err)
+		}
+
+		rotated, err := rotate(tableName, columns[1:], id, row)
+		stillFailed = countRotation(rotation, id, rotated, err, stillFailed)
+	}
+
+	rotation.SetFailedIDs(stillFailed)
+
+	if _, err := n.repo.EncryptionKeyRotation().UpdateEncryptionKeyRotation(ctx, rotation); err != nil {
+		return fmt.Errorf("error storing rotation progress of table %s: %w", tableName, err)
+	}
+
+	return nil
+}
+
+// countRotation counts the result of re-encrypting a row as rotated or skipped, or adds the row to the failed ids
+func countRotation(rotation *models.EncryptionKeyRotation, id uint, rotated bool, err error, failedIDs []uint) []uint {
+	switch {
+	case err != nil:
+		rotation.LastError = err.Error()
+		return append(failedIDs, id)
+	case rotated:
+		rotation.Rotated++
+	default:
+		rotation.Skipped++
+	}
+
+	return failedIDs
+}
+
+// rotateRow re-encrypts the columns of a row which are not encrypted under the current key. The row is only
+// updated if its columns still hold the values which were re-encrypted, so that values written since the row
+// was read are not overwritten: the row is then read again and re-encrypted from its new values. Rows which
+// cannot be re-encrypted are left unchanged, and an error is returned.
+func (n *encryptionKeyRotation) rotateRow(tableName string, columns []string, id uint, row map[string]interface{}) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		updates := make(map[string]interface{})
+		query := n.db.Table(tableName).Where("id = ?", id)
+
+		for _, column := range columns {
+			ciphertext := columnBytes(row[column])
+
+			if len(ciphertext) == 0 || !n.needsRotation(ciphertext) {
+				continue
+			}
+
+			rotated, err := n.reencrypt(ciphertext)
+			if err != nil {
+				return false, fmt.Errorf("error re-encrypting column %s of row %d: %w", column, id, err)
+			}
+
+			updates[column] = rotated
+			query = query.Where(clause.Eq{Column: clause.Column{Name: column}, Value: row[column]})
+		}
+
+		if len(updates) == 0 {
+			return false, nil
+		}
+
+		res := query.UpdateColumns(updates)
+		if res.Error != nil {
+			return false, fmt.Errorf("error updating row %d: %w", id, res.Error)
+		}
+
+		if res.RowsAffected > 0 {
+			return true, nil
+		}
+
+		if attempt >= maxRowRotationAttempts {
+			return false, fmt.Errorf("row %d kept changing while it was re-encrypted", id)
+		}
+
+		rows := []map[string]interface{}{}
+
+		err := n.db.Table(tableName).
+			Select(append([]string{"id"}, columns...)).
+			Where("id = ?", id).
+			Find(&rows).Error
+		if err != nil {
+			return false, fmt.Errorf("error reading row %d again: %w", id, err)
+		}
+
+		// rows which were deleted since they were read are skipped
+		if len(rows) == 0 {
+			return false, nil
+		}
+
+		row = rows[0]
+	}
+}
+
+// storedCredentialRotator returns a rowRotator which re-encrypts the credential of each integration in the
+// credential storage backend. Integrations without a credential in the backend are skipped.
+func (n *encryptionKeyRotation) storedCredentialRotator(credential storedCredential) rowRotator {
+	return func(_ string, _ []string, id uint, row map[string]interface{}) (bool, error) {
+		projectID, err := rowID(row["project_id"])
+		if err != nil {
+			return false, fmt.Errorf("error reading project id of integration %d: %w", id, err)
+		}
+
+		rotated, err := credential.rotate(n.credBackend, projectID, id, n.reencryptValues)
+		if err != nil {
+			if errors.Is(err, secretstore.ErrSecretNotFound) {
+				return false, nil
+			}
+
+			return false, fmt.Errorf("error re-encrypting stored credential of integration %d: %w", id, err)
+		}
+
+		return rotated, nil
+	}
+}
+
+// reencrypt decrypts a value, with OLD_ENCRYPTION_KEY if it is encrypted under the previous static key, and
+// encrypts it under the current key
+func (n *encryptionKeyRotation) reencrypt(ciphertext []byte) ([]byte, error) {
+	plaintext, err := encryption.Decrypt(ciphertext, n.newKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting: %w", err)
+	}
+
+	rotated, err := encryption.Encrypt(plaintext, n.newKey)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting: %w", err)
+	}
+
+	return rotated, nil
+}
+
+// reencryptValues re-encrypts the values which are not encrypted under the current key in place, and returns
+// false if none were
+func (n *encryptionKeyRotation) reencryptValues(values ...*[]byte) (bool, error) {
+	rotated := false
+
+	for _, value := range values {
+		if len(*value) == 0 || !n.needsRotation(*value) {
+			continue
+		}
+
+		reencrypted, err := n.reencrypt(*value)
+		if err != nil {
+			return false, err
+		}
+
+		*value = reencrypted
+		rotated = true
+	}
+
+	return rotated, nil
+}
+
+// needsRotation returns true if a value is not encrypted under the current key
+func (n *encryptionKeyRotation) needsRotation(ciphertext []byte) bool {
+	if encryption.CurrentKeyID() != "" {
+		return encryption.NeedsReencryption(ciphertext)
+	}
+
+	if _, isEnvelope := encryption.EnvelopeKeyID(ciphertext); isEnvelope {
+		return true
+	}
+
+	_, err := encryption.DecryptWithKey(ciphertext, n.newKey)
+
+	return err != nil
+}
+
+func (n *encryptionKeyRotation) SetData([]byte) {}
+
+// Result returns the JSON-encoded rotation status after the last run
+func (n *encryptionKeyRotation) Result() ([]byte, error) {
+	if n.status == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(n.status)
+}
+
+func keyFromString(value string) *[32]byte {
+	var key [32]byte
+
+	for i, b := range []byte(value) {
+		key[i] = b
+	}
+
+	return &key
+}
+
+func columnBytes(value interface{}) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	}
+
+	return nil
+}
+
+func rowID(value interface{}) (uint, error) {
+	switch v := value.(type) {
+	case int64:
+		return uint(v), nil
+	case int32:
+		return uint(v), nil
+	case int:
+		return uint(v), nil
+	case uint64:
+		return uint(v), nil
+	case uint32:
+		return uint(v), nil
+	case uint:
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("unexpected id type %T", value)
+}
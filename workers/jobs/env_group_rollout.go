@@ -0,0 +1,168 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/porter-dev/api-contracts/generated/go/porter/v1/porterv1connect"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/environment_groups"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+/*
+
+                         === Env Group Rollout Job ===
+
+   This job redeploys the apps linked to an environment group one by one, after the environment group was updated
+   or rolled back with auto rollout enabled. It is enqueued by the server with the "project_id", "cluster_id" and
+   "env_group_name" inputs, and rollouts of the same environment group are run one at a time.
+
+   The linked apps are read when the job runs, so that a rollout which was queued behind another one deploys the
+   latest version of the environment group. Progress is reported as app events.
+
+*/
+
+type envGroupRollout struct {
+	enqueueTime time.Time
+	repo        repository.Repository
+	doConf      *oauth2.Config
+	ccpClient   porterv1connect.ClusterControlPlaneServiceClient
+
+	projectID    uint
+	clusterID    uint
+	envGroupName string
+}
+
+// EnvGroupRolloutOpts holds the options required to run this job
+type EnvGroupRolloutOpts struct {
+	DBConf         *env.DBConf
+	ServerURL      string
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+
+	// ClusterControlPlaneAddress is the address of the cluster control plane which deploys the apps
+	ClusterControlPlaneAddress string
+
+	Input map[string]interface{}
+}
+
+type envGroupRolloutInput struct {
+	ProjectID    uint   `mapstructure:"project_id"`
+	ClusterID    uint   `mapstructure:"cluster_id"`
+	EnvGroupName string `mapstructure:"env_group_name"`
+}
+
+// NewEnvGroupRollout creates a new env group rollout job
+func NewEnvGroupRollout(
+	ctx context.Context,
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *EnvGroupRolloutOpts,
+) (*envGroupRollout, error) {
+	parsedInput := &envGroupRolloutInput{}
+	if err := mapstructure.Decode(opts.Input, parsedInput); err != nil {
+		return nil, err
+	}
+
+	if parsedInput.ProjectID == 0 || parsedInput.ClusterID == 0 || parsedInput.EnvGroupName == "" {
+		return nil, fmt.Errorf("project_id, cluster_id and env_group_name are required")
+	}
+
+	if opts.ClusterControlPlaneAddress == "" {
+		return nil, fmt.Errorf("cluster control plane address is required")
+	}
+
+	credBackend, err := secretstore.NewCredentialStorage(ctx, opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	return &envGroupRollout{
+		enqueueTime:  enqueueTime,
+		repo:         rgorm.NewRepository(db, &key, credBackend),
+		doConf:       doConf,
+		ccpClient:    porterv1connect.NewClusterControlPlaneServiceClient(http.DefaultClient, opts.ClusterControlPlaneAddress),
+		projectID:    parsedInput.ProjectID,
+		clusterID:    parsedInput.ClusterID,
+		envGroupName: parsedInput.EnvGroupName,
+	}, nil
+}
+
+func (n *envGroupRollout) ID() string {
+	return porter_app.EnvGroupRolloutJobID
+}
+
+func (n *envGroupRollout) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *envGroupRollout) Run(ctx context.Context) error {
+	cluster, err := n.repo.Cluster().ReadCluster(n.projectID, n.clusterID)
+	if err != nil {
+		return fmt.Errorf("error reading cluster: %w", err)
+	}
+
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting k8s agent: %w", err)
+	}
+
+	linkedApps, err := environment_groups.LinkedApplications(ctx, k8sAgent, n.envGroupName, false)
+	if err != nil {
+		return fmt.Errorf("error listing apps linked to env group: %w", err)
+	}
+
+	log.Printf("rolling out env group %s of cluster %d to %d apps", n.envGroupName, n.clusterID, len(linkedApps))
+
+	if len(linkedApps) == 0 {
+		return nil
+	}
+
+	return porter_app.RolloutEnvGroup(ctx, porter_app.RolloutEnvGroupInput{
+		ProjectID:                  n.projectID,
+		ClusterID:                  n.clusterID,
+		EnvGroupName:               n.envGroupName,
+		LinkedApps:                 linkedApps,
+		CCPClient:                  n.ccpClient,
+		PorterAppRepository:        n.repo.PorterApp(),
+		DeploymentTargetRepository: n.repo.DeploymentTarget(),
+		PorterAppEventRepository:   n.repo.PorterAppEvent(),
+		OPAPolicyBundleRepository:  n.repo.OPAPolicyBundle(),
+	})
+}
+
+func (n *envGroupRollout) SetData([]byte) {}
@@ -34,13 +34,12 @@ import (
 	"github.com/porter-dev/porter/provisioner/integrations/storage/s3"
 	"github.com/porter-dev/porter/workers/utils"
 
-	"github.com/porter-dev/porter/ee/integrations/vault"
 	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository"
-	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
 	"github.com/stefanmcshane/helm/pkg/releaseutil"
 	"golang.org/x/oauth2"
@@ -81,14 +80,9 @@ func NewHelmRevisionsCountTracker(
 	enqueueTime time.Time,
 	opts *HelmRevisionsCountTrackerOpts,
 ) (*helmRevisionsCountTracker, error) {
-	var credBackend rcreds.CredentialStorage
-
-	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
-		credBackend = vault.NewClient(
-			opts.DBConf.VaultServerURL,
-			opts.DBConf.VaultAPIKey,
-			opts.DBConf.VaultPrefix,
-		)
+	credBackend, err := secretstore.NewCredentialStorage(ctx, opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
 	}
 
 	var key [32]byte
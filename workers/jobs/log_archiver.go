@@ -0,0 +1,364 @@
+//go:build ee
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	porter_agent "github.com/porter-dev/porter/internal/kubernetes/porter_agent/v2"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/porter_app"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
+	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+/*
+
+                         === Log Archiver Job ===
+
+   This job exports the app logs of every project with an enabled log archive setting to S3-compatible storage,
+   before they expire from the Loki instance of the porter agent. Logs are archived in hourly windows, as one
+   gzip-compressed newline-delimited JSON object per deployment target, app, service and window, partitioned by
+   deployment target, app, service and day, so that apps with the same name in several targets, such as preview
+   environments, are archived separately.
+
+   Each cluster has a checkpoint: the end of the last window for which the logs of every app were archived. A run
+   archives the windows after the checkpoint which ended at least LOG_ARCHIVER_DELAY ago, so that late logs have
+   been ingested, and at most LOG_ARCHIVER_MAX_WINDOWS of them per cluster. Since objects are keyed by their window,
+   a window which is archived again, for instance after a failure, overwrites its objects. If archival was disabled
+   and enabled again since the checkpoint, archival restarts from the window in which it was enabled.
+
+   Clusters which cannot be reached are skipped, and retried on the next run. The job can be restricted to a
+   single project with the "project_id" input when it is enqueued.
+
+*/
+
+type logArchiver struct {
+	enqueueTime time.Time
+	db          *gorm.DB
+	doConf      *oauth2.Config
+	repo        repository.Repository
+	delay       time.Duration
+	maxWindows  int
+	projectID   uint
+
+	report *LogArchiverReport
+}
+
+// LogArchiverOpts holds the options required to run this job
+type LogArchiverOpts struct {
+	DBConf         *env.DBConf
+	ServerURL      string
+	DOClientID     string
+	DOClientSecret string
+	DOScopes       []string
+
+	// Delay is how long after the end of a window its logs are archived
+	Delay time.Duration
+
+	// MaxWindows is the maximum number of windows archived per cluster in a run
+	MaxWindows int
+
+	Input map[string]interface{}
+}
+
+type logArchiverInput struct {
+	ProjectID uint `mapstructure:"project_id"`
+}
+
+// LogArchiverReport is the result of a run of the log archiver
+type LogArchiverReport struct {
+	Clusters []LogArchiverClusterReport `json:"clusters"`
+}
+
+// LogArchiverClusterReport describes the logs archived for a cluster
+type LogArchiverClusterReport struct {
+	ProjectID uint `json:"project_id"`
+	ClusterID uint `json:"cluster_id"`
+
+	// ArchivedUntil is the checkpoint of the cluster at the end of the run
+	ArchivedUntil time.Time `json:"archived_until"`
+
+	Windows int `json:"windows"`
+	Objects int `json:"objects"`
+	Lines   int `json:"lines"`
+
+	// Error is set if the logs of the cluster could not be archived until the latest window
+	Error string `json:"error,omitempty"`
+}
+
+// NewLogArchiver creates a new log archiver job
+func NewLogArchiver(
+	ctx context.Context,
+	db *gorm.DB,
+	enqueueTime time.Time,
+	opts *LogArchiverOpts,
+) (*logArchiver, error) {
+	credBackend, err := secretstore.NewCredentialStorage(ctx, opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
+	}
+
+	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
+		ClientID:     opts.DOClientID,
+		ClientSecret: opts.DOClientSecret,
+		Scopes:       opts.DOScopes,
+		BaseURL:      opts.ServerURL,
+	})
+
+	var key [32]byte
+
+	for i, b := range []byte(opts.DBConf.EncryptionKey) {
+		key[i] = b
+	}
+
+	repo := rgorm.NewRepository(db, &key, credBackend)
+
+	parsedInput := &logArchiverInput{}
+	if err := mapstructure.Decode(opts.Input, parsedInput); err != nil {
+		return nil, err
+	}
+
+	if opts.MaxWindows <= 0 {
+		return nil, fmt.Errorf("max windows must be positive")
+	}
+
+	return &logArchiver{
+		enqueueTime: enqueueTime,
+		db:          db,
+		doConf:      doConf,
+		repo:        repo,
+		delay:       opts.Delay,
+		maxWindows:  opts.MaxWindows,
+		projectID:   parsedInput.ProjectID,
+	}, nil
+}
+
+func (n *logArchiver) ID() string {
+	return "log-archiver"
+}
+
+func (n *logArchiver) EnqueueTime() time.Time {
+	return n.enqueueTime
+}
+
+func (n *logArchiver) Run(ctx context.Context) error {
+	n.report = &LogArchiverReport{
+		Clusters: []LogArchiverClusterReport{},
+	}
+
+	settings, err := n.repo.LogArchive().ListEnabledLogArchiveSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing log archive settings: %w", err)
+	}
+
+	log.Printf("found %d projects with log archival enabled", len(settings))
+
+	for _, setting := range settings {
+		if n.projectID != 0 && setting.ProjectID != n.projectID {
+			continue
+		}
+
+		if err := n.archiveProject(ctx, setting); err != nil {
+			log.Printf("error archiving logs of project %d: %v. skipping ...", setting.ProjectID, err)
+			n.report.Clusters = append(n.report.Clusters, LogArchiverClusterReport{
+				ProjectID: setting.ProjectID,
+				Error:     err.Error(),
+			})
+		}
+	}
+
+	log.Printf("finished archiving logs of %d clusters", len(n.report.Clusters))
+
+	return nil
+}
+
+// archiveProject archives the logs of every cluster of a project
+func (n *logArchiver) archiveProject(ctx context.Context, setting *models.LogArchiveSetting) error {
+	awsIntegration, err := n.repo.AWSIntegration().ReadAWSIntegration(setting.ProjectID, setting.AWSIntegrationID)
+	if err != nil {
+		return fmt.Errorf("error reading aws integration: %w", err)
+	}
+
+	store, err := porter_app.NewS3LogArchiveStore(setting, awsIntegration)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := n.repo.Cluster().ListClustersByProjectID(setting.ProjectID)
+	if err != nil {
+		return fmt.Errorf("error listing clusters: %w", err)
+	}
+
+	for _, cluster := range clusters {
+		report := n.archiveCluster(ctx, setting, store, cluster)
+		if report.Error != "" {
+			log.Printf("error archiving logs of cluster %d: %s", cluster.ID, report.Error)
+		}
+
+		log.Printf("archived %d windows of logs of cluster %d (%d objects, %d lines), until %s",
+			report.Windows, cluster.ID, report.Objects, report.Lines, report.ArchivedUntil.Format(time.RFC3339))
+
+		n.report.Clusters = append(n.report.Clusters, report)
+	}
+
+	return nil
+}
+
+// archiveCluster archives the windows of logs of a cluster after its checkpoint, moving the checkpoint after each window
+func (n *logArchiver) archiveCluster(
+	ctx context.Context,
+	setting *models.LogArchiveSetting,
+	store porter_app.LogArchiveStore,
+	cluster *models.Cluster,
+) LogArchiverClusterReport {
+	report := LogArchiverClusterReport{
+		ProjectID: setting.ProjectID,
+		ClusterID: cluster.ID,
+	}
+
+	checkpoint, err := n.repo.LogArchive().ReadLogArchiveCheckpoint(ctx, cluster.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		report.Error = fmt.Sprintf("error reading checkpoint: %v", err)
+		return report
+	}
+
+	// logs are archived from the window in which archival was last enabled
+	enabledAt := setting.EnabledAt
+	if enabledAt.IsZero() {
+		enabledAt = setting.CreatedAt
+	}
+	enabledWindow := enabledAt.UTC().Truncate(porter_app.LogArchiveWindow)
+
+	if checkpoint == nil {
+		checkpoint = &models.LogArchiveCheckpoint{
+			ProjectID: setting.ProjectID,
+			ClusterID: cluster.ID,
+		}
+	}
+	if checkpoint.ArchivedUntil.Before(enabledWindow) {
+		checkpoint.ArchivedUntil = enabledWindow
+	}
+
+	report.ArchivedUntil = checkpoint.ArchivedUntil
+
+	latestWindowEnd := time.Now().UTC().Add(-n.delay)
+	if checkpoint.ArchivedUntil.Add(porter_app.LogArchiveWindow).After(latestWindowEnd) {
+		return report
+	}
+
+	// read the cluster through the repository so that its credentials are decrypted
+	cluster, err = n.repo.Cluster().ReadCluster(cluster.ProjectID, cluster.ID)
+	if err != nil {
+		report.Error = fmt.Sprintf("error reading cluster: %v", err)
+		return report
+	}
+
+	k8sAgent, err := kubernetes.GetAgentOutOfClusterConfig(ctx, &kubernetes.OutOfClusterConfig{
+		Cluster:                   cluster,
+		Repo:                      n.repo,
+		DigitalOceanOAuth:         n.doConf,
+		AllowInClusterConnections: false,
+		Timeout:                   10 * time.Second,
+	})
+	if err != nil {
+		report.Error = fmt.Sprintf("error getting k8s agent: %v", err)
+		return report
+	}
+
+	agentSvc, err := porter_agent.GetAgentService(k8sAgent.Clientset)
+	if err != nil {
+		report.Error = fmt.Sprintf("error getting agent service: %v", err)
+		return report
+	}
+
+	logs := func(ctx context.Context, req *types.LogRequest) (*types.GetLogResponse, error) {
+		return porter_agent.Logs(ctx, k8sAgent.Clientset, agentSvc, req)
+	}
+
+	apps, err := n.repo.PorterApp().ListPorterAppByClusterID(cluster.ID)
+	if err != nil {
+		report.Error = fmt.Sprintf("error listing apps: %v", err)
+		return report
+	}
+
+	var deploymentTargets []*models.DeploymentTarget
+	for _, preview := range []bool{false, true} {
+		targets, err := n.repo.DeploymentTarget().ListForCluster(cluster.ProjectID, cluster.ID, preview)
+		if err != nil {
+			report.Error = fmt.Sprintf("error listing deployment targets: %v", err)
+			return report
+		}
+		deploymentTargets = append(deploymentTargets, targets...)
+	}
+
+	for report.Windows < n.maxWindows {
+		windowStart := checkpoint.ArchivedUntil
+		if windowStart.Add(porter_app.LogArchiveWindow).After(latestWindowEnd) {
+			break
+		}
+
+		for _, deploymentTarget := range deploymentTargets {
+			for _, app := range apps {
+				archives, err := porter_app.ArchiveAppLogs(ctx, porter_app.ArchiveAppLogsInput{
+					ProjectID:            setting.ProjectID,
+					ClusterID:            cluster.ID,
+					AppName:              app.Name,
+					DeploymentTargetID:   deploymentTarget.ID,
+					Setting:              setting,
+					WindowStart:          windowStart,
+					Logs:                 logs,
+					Store:                store,
+					LogArchiveRepository: n.repo.LogArchive(),
+				})
+				if err != nil {
+					report.Error = fmt.Sprintf("error archiving logs of app %s in deployment target %s from %s: %v",
+						app.Name, deploymentTarget.ID, windowStart.Format(time.RFC3339), err)
+					return report
+				}
+
+				for _, archive := range archives {
+					report.Objects++
+					report.Lines += archive.LineCount
+				}
+			}
+		}
+
+		checkpoint.ArchivedUntil = windowStart.Add(porter_app.LogArchiveWindow)
+
+		checkpoint, err = n.repo.LogArchive().UpsertLogArchiveCheckpoint(ctx, checkpoint)
+		if err != nil {
+			report.Error = fmt.Sprintf("error saving checkpoint: %v", err)
+			return report
+		}
+
+		report.Windows++
+		report.ArchivedUntil = checkpoint.ArchivedUntil
+	}
+
+	return report
+}
+
+func (n *logArchiver) SetData([]byte) {}
+
+// Result returns the JSON-encoded report of the last run
+func (n *logArchiver) Result() ([]byte, error) {
+	if n.report == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(n.report)
+}
@@ -4,17 +4,17 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/porter-dev/porter/api/server/shared/config/env"
-	"github.com/porter-dev/porter/ee/integrations/vault"
 	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository"
-	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
@@ -57,14 +57,9 @@ func NewPreviewDeploymentsTTLDeleter(
 	enqueueTime time.Time,
 	opts *PreviewDeploymentsTTLDeleterOpts,
 ) (*previewDeploymentsTTLDeleter, error) {
-	var credBackend rcreds.CredentialStorage
-
-	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
-		credBackend = vault.NewClient(
-			opts.DBConf.VaultServerURL,
-			opts.DBConf.VaultAPIKey,
-			opts.DBConf.VaultPrefix,
-		)
+	credBackend, err := secretstore.NewCredentialStorage(context.Background(), opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
 	}
 
 	doConf := oauth.NewDigitalOceanClient(&oauth.Config{
@@ -4,7 +4,8 @@
 
                             === Recommender Job ===
 
-This job checks to see if a cluster matches policies set by the OPA config file.
+This job checks to see if a cluster matches policies set by the OPA config file, as well as the
+policy bundles uploaded to the cluster's project.
 
 */
 
@@ -22,14 +23,13 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
 
-	"github.com/porter-dev/porter/ee/integrations/vault"
 	"github.com/porter-dev/porter/internal/encryption"
 	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/opa"
 	"github.com/porter-dev/porter/internal/repository"
-	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	rgorm "github.com/porter-dev/porter/internal/repository/gorm"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
@@ -79,14 +79,9 @@ func NewRecommender(
 	opts *RecommenderOpts,
 	opaPolicies *opa.KubernetesPolicies,
 ) (*recommender, error) {
-	var credBackend rcreds.CredentialStorage
-
-	if opts.DBConf.VaultAPIKey != "" && opts.DBConf.VaultServerURL != "" && opts.DBConf.VaultPrefix != "" {
-		credBackend = vault.NewClient(
-			opts.DBConf.VaultServerURL,
-			opts.DBConf.VaultAPIKey,
-			opts.DBConf.VaultPrefix,
-		)
+	credBackend, err := secretstore.NewCredentialStorage(context.Background(), opts.DBConf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential storage backend: %w", err)
 	}
 
 	var key [32]byte
@@ -106,7 +101,7 @@ func NewRecommender(
 
 	// parse input
 	parsedInput := &recommenderInput{}
-	err := mapstructure.Decode(opts.Input, parsedInput)
+	err = mapstructure.Decode(opts.Input, parsedInput)
 	if err != nil {
 		return nil, err
 	}
@@ -186,6 +181,8 @@ func (n *recommender) EnqueueTime() time.Time {
 }
 
 func (n *recommender) Run(ctx context.Context) error {
+	projectPolicies := make(map[uint]*opa.KubernetesPolicies)
+
 	for _, ids := range n.clusterAndProjectIDs {
 		fmt.Println(ids.projectID, ids.clusterID)
 
@@ -218,7 +215,13 @@ func (n *recommender) Run(ctx context.Context) error {
 			continue
 		}
 
-		runner := opa.NewRunner(n.policies, cluster, k8sAgent, dynamicClient)
+		policies, ok := projectPolicies[ids.projectID]
+		if !ok {
+			policies = n.getProjectPolicies(ctx, ids.projectID)
+			projectPolicies[ids.projectID] = policies
+		}
+
+		runner := opa.NewRunner(policies, cluster, k8sAgent, dynamicClient)
 
 		queryResults, err := runner.GetRecommendations(n.categories)
 		if err != nil {
@@ -264,6 +267,28 @@ func (n *recommender) Run(ctx context.Context) error {
 	return nil
 }
 
+// getProjectPolicies returns the built-in policies merged with the policy bundles of the project. If the bundles cannot
+// be loaded, only the built-in policies are returned, so that a broken bundle does not block the built-in checks.
+func (n *recommender) getProjectPolicies(ctx context.Context, projectID uint) *opa.KubernetesPolicies {
+	bundles, err := n.repo.OPAPolicyBundle().ListOPAPolicyBundlesByProjectID(ctx, projectID)
+	if err != nil {
+		log.Printf("error listing policy bundles for project ID %d: %v. using built-in policies ...", projectID, err)
+		return n.policies
+	}
+
+	if len(bundles) == 0 {
+		return n.policies
+	}
+
+	policies, err := n.policies.WithPolicyBundles(ctx, bundles)
+	if err != nil {
+		log.Printf("error loading policy bundles for project ID %d: %v. using built-in policies ...", projectID, err)
+		return n.policies
+	}
+
+	return policies
+}
+
 func (n *recommender) getMonitorTestResultFromQueryResult(cluster *models.Cluster, queryRes *opa.OPARecommenderQueryResult, recommenderID string) *models.MonitorTestResult {
 	runResult := types.MonitorTestStatusSuccess
 
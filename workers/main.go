@@ -5,11 +5,13 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -17,23 +19,31 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/joeshaw/envdecode"
 	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/encryption/kms"
+	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/opa"
+	"github.com/porter-dev/porter/internal/porter_app"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/worker"
 	"github.com/porter-dev/porter/workers/jobs"
 	"gorm.io/gorm"
 
-	"github.com/porter-dev/porter/ee/integrations/vault"
-	rcreds "github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/repository/credentials/secretstore"
 	pgorm "github.com/porter-dev/porter/internal/repository/gorm"
 )
 
 var (
 	jobQueue    chan worker.Job
+	queue       *worker.PersistentQueue
+	scheduler   *worker.Scheduler
 	envDecoder  = EnvConf{}
 	dbConn      *gorm.DB
 	repo        repository.Repository
+	staticKey   *[32]byte
+	credBackend credentials.CredentialStorage
 	opaPolicies *opa.KubernetesPolicies
 )
 
@@ -54,6 +64,18 @@ type EnvConf struct {
 	MaxQueue   uint `env:"MAX_QUEUE,default=100"`
 	Port       uint `env:"PORT,default=3000"`
 
+	// Persistent queue configuration. JobMaxAttempts only applies to jobs which are safe to retry, other jobs are attempted once.
+	JobMaxAttempts     int           `env:"JOB_MAX_ATTEMPTS,default=5"`
+	JobPollInterval    time.Duration `env:"JOB_POLL_INTERVAL,default=5s"`
+	JobLockDuration    time.Duration `env:"JOB_LOCK_DURATION,default=1h"`
+	JobRetryBackoff    time.Duration `env:"JOB_RETRY_BACKOFF,default=30s"`
+	JobMaxRetryBackoff time.Duration `env:"JOB_MAX_RETRY_BACKOFF,default=1h"`
+
+	// Scheduler configuration. Each schedule is of the form "<job-id>=<cron expression>",
+	// separated by semicolons, such as "recommender=0 * * * *;preview-deployments-ttl-deleter=@daily"
+	JobSchedules          []string      `env:"JOB_SCHEDULES"`
+	SchedulerTickInterval time.Duration `env:"SCHEDULER_TICK_INTERVAL,default=30s"`
+
 	/**
 	 * Job-specific configuration
 	 */
@@ -72,6 +94,28 @@ type EnvConf struct {
 
 	// "preview-deployments-ttl-deleter"
 	PreviewDeploymentsTTL string `env:"PREVIEW_DEPLOYMENTS_TTL"`
+
+	// "dns-records-gc"
+	DNSConf            env.DNSConf
+	AppRootDomain      string        `env:"APP_ROOT_DOMAIN,default=porter.run"`
+	DNSRecordsGCDryRun bool          `env:"DNS_RECORDS_GC_DRY_RUN,default=true"`
+	DNSRecordsGCMinAge time.Duration `env:"DNS_RECORDS_GC_MIN_AGE,default=24h"`
+
+	// "api-token-expiry-notifier"
+	SendgridAPIKey                   string        `env:"SENDGRID_API_KEY"`
+	SendgridSenderEmail              string        `env:"SENDGRID_SENDER_EMAIL"`
+	SendgridAPITokenExpiryTemplateID string        `env:"SENDGRID_API_TOKEN_EXPIRY_TEMPLATE_ID"`
+	APITokenExpiryNotice             time.Duration `env:"API_TOKEN_EXPIRY_NOTICE,default=168h"`
+
+	// "encryption-key-rotation"
+	EncryptionKeyRotationBatchSize int `env:"ENCRYPTION_KEY_ROTATION_BATCH_SIZE,default=100"`
+
+	// "log-archiver"
+	LogArchiverDelay      time.Duration `env:"LOG_ARCHIVER_DELAY,default=10m"`
+	LogArchiverMaxWindows int           `env:"LOG_ARCHIVER_MAX_WINDOWS,default=24"`
+
+	// "env-group-rollout"
+	ClusterControlPlaneAddress string `env:"CLUSTER_CONTROL_PLANE_ADDRESS"`
 }
 
 func main() {
@@ -93,14 +137,9 @@ func main() {
 
 	dbConn = db
 
-	var credBackend rcreds.CredentialStorage
-
-	if envDecoder.DBConf.VaultAPIKey != "" && envDecoder.DBConf.VaultServerURL != "" && envDecoder.DBConf.VaultPrefix != "" {
-		credBackend = vault.NewClient(
-			envDecoder.DBConf.VaultServerURL,
-			envDecoder.DBConf.VaultAPIKey,
-			envDecoder.DBConf.VaultPrefix,
-		)
+	credBackend, err = secretstore.NewCredentialStorage(ctx, &envDecoder.DBConf)
+	if err != nil {
+		log.Fatalf("error creating credential storage backend: %v", err)
 	}
 
 	var key [32]byte
@@ -109,6 +148,11 @@ func main() {
 		key[i] = b
 	}
 
+	if err := kms.SetKeyProviderFromConf(ctx, &envDecoder.DBConf); err != nil {
+		log.Fatalf("error setting encryption key provider: %v", err)
+	}
+
+	staticKey = &key
 	repo = pgorm.NewRepository(db, &key, credBackend)
 
 	opaPolicies, err = opa.LoadPolicies(envDecoder.OPAConfigFileDir)
@@ -128,6 +172,54 @@ func main() {
 		log.Fatalln(err)
 	}
 
+	queue, err = worker.NewPersistentQueue(repo.WorkerJobRun(), jobQueue, getJob, worker.PersistentQueueOpts{
+		MaxAttempts:      1,
+		MaxAttemptsByJob: retryableJobMaxAttempts(envDecoder.JobMaxAttempts),
+		PollInterval:     envDecoder.JobPollInterval,
+		LockDuration:     envDecoder.JobLockDuration,
+		RetryBackoff:     envDecoder.JobRetryBackoff,
+		MaxRetryBackoff:  envDecoder.JobMaxRetryBackoff,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Println("starting persistent job queue")
+
+	err = queue.Run(ctx)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var scheduledJobs []worker.ScheduledJob
+
+	for _, spec := range envDecoder.JobSchedules {
+		scheduledJob, err := worker.ParseScheduledJob(spec)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if !isKnownJob(scheduledJob.JobID) {
+			log.Fatalf("cannot schedule unknown job with ID: %s", scheduledJob.JobID)
+		}
+
+		log.Printf("scheduling job with ID: %s at: %s", scheduledJob.JobID, scheduledJob.Expression)
+
+		scheduledJobs = append(scheduledJobs, scheduledJob)
+	}
+
+	scheduler, err = worker.NewScheduler(repo.WorkerJobSchedule(), queue, scheduledJobs, envDecoder.SchedulerTickInterval)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Println("starting job scheduler")
+
+	err = scheduler.Run(ctx)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	server := &http.Server{Addr: fmt.Sprintf(":%d", envDecoder.Port), Handler: httpService(ctx)}
 
 	serverCtx, serverStopCtx := context.WithCancel(context.Background())
@@ -170,6 +262,8 @@ func main() {
 	// Wait for server context to be stopped
 	<-serverCtx.Done()
 
+	scheduler.Exit()
+	queue.Exit()
 	d.Exit()
 }
 
@@ -187,80 +281,348 @@ func httpService(ctx context.Context) http.Handler {
 	log.Println("setting up HTTP POST endpoint to enqueue jobs")
 
 	r.Post("/enqueue/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+
+		if !isKnownJob(id) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
 		req := make(map[string]interface{})
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Printf("error converting body to json: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		run, err := queue.Enqueue(r.Context(), id, req)
+		if err != nil {
+			log.Printf("error enqueueing job with ID: %s. Error: %v", id, err)
+			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		job := getJob(ctx, chi.URLParam(r, "id"), req)
+		writeJSON(w, http.StatusCreated, run.ToWorkerJobRunType())
+	})
 
-		if job == nil {
-			w.WriteHeader(http.StatusNotFound)
+	log.Println("setting up HTTP endpoints to check job status and history")
+
+	r.Get("/jobs/{id}/runs", func(w http.ResponseWriter, r *http.Request) {
+		runs, err := repo.WorkerJobRun().ListWorkerJobRunsByJobID(r.Context(), chi.URLParam(r, "id"), listLimit(r))
+		if err != nil {
+			log.Printf("error listing job runs: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		jobQueue <- job
-		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, http.StatusOK, toWorkerJobRunTypes(runs))
 	})
 
-	return r
-}
+	r.Get("/runs/dead-letter", func(w http.ResponseWriter, r *http.Request) {
+		runs, err := repo.WorkerJobRun().ListWorkerJobRunsByStatus(r.Context(), models.WorkerJobRunStatus_Dead, listLimit(r))
+		if err != nil {
+			log.Printf("error listing dead job runs: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, toWorkerJobRunTypes(runs))
+	})
 
-func getJob(ctx context.Context, id string, input map[string]interface{}) worker.Job {
-	if id == "helm-revisions-count-tracker" {
-		newJob, err := jobs.NewHelmRevisionsCountTracker(ctx, dbConn, time.Now().UTC(), &jobs.HelmRevisionsCountTrackerOpts{
-			DBConf:             &envDecoder.DBConf,
-			DOClientID:         envDecoder.DOClientID,
-			DOClientSecret:     envDecoder.DOClientSecret,
-			DOScopes:           []string{"read", "write"},
-			ServerURL:          envDecoder.ServerURL,
-			AWSAccessKeyID:     envDecoder.AWSAccessKeyID,
-			AWSSecretAccessKey: envDecoder.AWSSecretAccessKey,
-			AWSRegion:          envDecoder.AWSRegion,
-			S3BucketName:       envDecoder.S3BucketName,
-			EncryptionKey:      envDecoder.EncryptionKey,
-			RevisionsCount:     envDecoder.RevisionsCount,
-		})
+	r.Get("/runs/{run_id}", func(w http.ResponseWriter, r *http.Request) {
+		runID, err := strconv.ParseUint(chi.URLParam(r, "run_id"), 10, 64)
 		if err != nil {
-			log.Printf("error creating job with ID: helm-revisions-count-tracker. Error: %v", err)
-			return nil
+			w.WriteHeader(http.StatusBadRequest)
+			return
 		}
 
-		return newJob
-	} else if id == "recommender" {
-		newJob, err := jobs.NewRecommender(dbConn, time.Now().UTC(), &jobs.RecommenderOpts{
-			DBConf:           &envDecoder.DBConf,
-			DOClientID:       envDecoder.DOClientID,
-			DOClientSecret:   envDecoder.DOClientSecret,
-			DOScopes:         []string{"read", "write"},
-			ServerURL:        envDecoder.ServerURL,
-			Input:            input,
-			LegacyProjectIDs: envDecoder.LegacyProjectIDs,
-		}, opaPolicies)
+		run, err := repo.WorkerJobRun().ReadWorkerJobRun(r.Context(), uint(runID))
 		if err != nil {
-			log.Printf("error creating job with ID: recommender. Error: %v", err)
-			return nil
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			log.Printf("error reading job run: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
 		}
 
-		return newJob
-	} else if id == "preview-deployments-ttl-deleter" {
-		newJob, err := jobs.NewPreviewDeploymentsTTLDeleter(dbConn, time.Now().UTC(), &jobs.PreviewDeploymentsTTLDeleterOpts{
-			DBConf:                &envDecoder.DBConf,
-			ServerURL:             envDecoder.ServerURL,
-			DOClientID:            envDecoder.DOClientID,
-			DOClientSecret:        envDecoder.DOClientSecret,
-			DOScopes:              []string{"read", "write"},
-			PreviewDeploymentsTTL: envDecoder.PreviewDeploymentsTTL,
-		})
+		writeJSON(w, http.StatusOK, run.ToWorkerJobRunType())
+	})
+
+	r.Post("/runs/{run_id}/requeue", func(w http.ResponseWriter, r *http.Request) {
+		runID, err := strconv.ParseUint(chi.URLParam(r, "run_id"), 10, 64)
 		if err != nil {
-			log.Printf("error creating job with ID: preview-deployments-ttl-deleter. Error: %v", err)
-			return nil
+			w.WriteHeader(http.StatusBadRequest)
+			return
 		}
 
-		return newJob
+		run, err := queue.Requeue(r.Context(), uint(runID))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			log.Printf("error requeueing job run: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, run.ToWorkerJobRunType())
+	})
+
+	r.Get("/encryption-key-rotation", func(w http.ResponseWriter, r *http.Request) {
+		status, err := jobs.GetEncryptionKeyRotationStatus(r.Context(), repo, jobs.EncryptionKeyRotationKeyID(staticKey), credBackend)
+		if err != nil {
+			log.Printf("error reading encryption key rotation status: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, status)
+	})
+
+	r.Get("/schedules", func(w http.ResponseWriter, r *http.Request) {
+		schedules, err := scheduler.Schedules(r.Context())
+		if err != nil {
+			log.Printf("error listing job schedules: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		res := make([]*types.WorkerJobSchedule, 0, len(schedules))
+		for _, schedule := range schedules {
+			res = append(res, schedule.ToWorkerJobScheduleType())
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	})
+
+	return r
+}
+
+// defaultListLimit is the number of runs returned by the list endpoints if no limit is specified
+const defaultListLimit = 50
+
+func listLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return defaultListLimit
+	}
+
+	return limit
+}
+
+func toWorkerJobRunTypes(runs []*models.WorkerJobRun) []*types.WorkerJobRun {
+	res := make([]*types.WorkerJobRun, 0, len(runs))
+	for _, run := range runs {
+		res = append(res, run.ToWorkerJobRunType())
+	}
+
+	return res
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("error writing json response: %v", err)
+	}
+}
+
+// workerJob is a job which can be enqueued on the worker
+type workerJob struct {
+	// retryable is true if the job is safe to run more than once, in which case failed runs are attempted up to JOB_MAX_ATTEMPTS times
+	retryable bool
+
+	// newJob builds the job from the input it was enqueued with
+	newJob func(ctx context.Context, input map[string]interface{}) (worker.Job, error)
+}
+
+// workerJobs are the jobs which can be enqueued on the worker, keyed by job ID
+var workerJobs = map[string]workerJob{
+	"helm-revisions-count-tracker": {
+		retryable: true,
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewHelmRevisionsCountTracker(ctx, dbConn, time.Now().UTC(), &jobs.HelmRevisionsCountTrackerOpts{
+				DBConf:             &envDecoder.DBConf,
+				DOClientID:         envDecoder.DOClientID,
+				DOClientSecret:     envDecoder.DOClientSecret,
+				DOScopes:           []string{"read", "write"},
+				ServerURL:          envDecoder.ServerURL,
+				AWSAccessKeyID:     envDecoder.AWSAccessKeyID,
+				AWSSecretAccessKey: envDecoder.AWSSecretAccessKey,
+				AWSRegion:          envDecoder.AWSRegion,
+				S3BucketName:       envDecoder.S3BucketName,
+				EncryptionKey:      envDecoder.EncryptionKey,
+				RevisionsCount:     envDecoder.RevisionsCount,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: helm-revisions-count-tracker. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+	"recommender": {
+		retryable: true,
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewRecommender(dbConn, time.Now().UTC(), &jobs.RecommenderOpts{
+				DBConf:           &envDecoder.DBConf,
+				DOClientID:       envDecoder.DOClientID,
+				DOClientSecret:   envDecoder.DOClientSecret,
+				DOScopes:         []string{"read", "write"},
+				ServerURL:        envDecoder.ServerURL,
+				Input:            input,
+				LegacyProjectIDs: envDecoder.LegacyProjectIDs,
+			}, opaPolicies)
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: recommender. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+	"preview-deployments-ttl-deleter": {
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewPreviewDeploymentsTTLDeleter(dbConn, time.Now().UTC(), &jobs.PreviewDeploymentsTTLDeleterOpts{
+				DBConf:                &envDecoder.DBConf,
+				ServerURL:             envDecoder.ServerURL,
+				DOClientID:            envDecoder.DOClientID,
+				DOClientSecret:        envDecoder.DOClientSecret,
+				DOScopes:              []string{"read", "write"},
+				PreviewDeploymentsTTL: envDecoder.PreviewDeploymentsTTL,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: preview-deployments-ttl-deleter. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+	"dns-records-gc": {
+		retryable: true,
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewDNSRecordsGC(ctx, dbConn, time.Now().UTC(), &jobs.DNSRecordsGCOpts{
+				DBConf:         &envDecoder.DBConf,
+				DNSConf:        envDecoder.DNSConf,
+				AppRootDomain:  envDecoder.AppRootDomain,
+				ServerURL:      envDecoder.ServerURL,
+				DOClientID:     envDecoder.DOClientID,
+				DOClientSecret: envDecoder.DOClientSecret,
+				DOScopes:       []string{"read", "write"},
+				DryRun:         envDecoder.DNSRecordsGCDryRun,
+				MinAge:         envDecoder.DNSRecordsGCMinAge,
+				Input:          input,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: dns-records-gc. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+	"api-token-expiry-notifier": {
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewAPITokenExpiryNotifier(dbConn, time.Now().UTC(), &jobs.APITokenExpiryNotifierOpts{
+				DBConf:              &envDecoder.DBConf,
+				ServerURL:           envDecoder.ServerURL,
+				SendgridAPIKey:      envDecoder.SendgridAPIKey,
+				SendgridSenderEmail: envDecoder.SendgridSenderEmail,
+				SendgridTemplateID:  envDecoder.SendgridAPITokenExpiryTemplateID,
+				Notice:              envDecoder.APITokenExpiryNotice,
+				Input:               input,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: api-token-expiry-notifier. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+	"encryption-key-rotation": {
+		retryable: true,
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewEncryptionKeyRotation(dbConn, time.Now().UTC(), &jobs.EncryptionKeyRotationOpts{
+				DBConf:    &envDecoder.DBConf,
+				BatchSize: envDecoder.EncryptionKeyRotationBatchSize,
+				Input:     input,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: encryption-key-rotation. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+	"log-archiver": {
+		retryable: true,
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewLogArchiver(ctx, dbConn, time.Now().UTC(), &jobs.LogArchiverOpts{
+				DBConf:         &envDecoder.DBConf,
+				ServerURL:      envDecoder.ServerURL,
+				DOClientID:     envDecoder.DOClientID,
+				DOClientSecret: envDecoder.DOClientSecret,
+				DOScopes:       []string{"read", "write"},
+				Delay:          envDecoder.LogArchiverDelay,
+				MaxWindows:     envDecoder.LogArchiverMaxWindows,
+				Input:          input,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: log-archiver. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+	porter_app.EnvGroupRolloutJobID: {
+		newJob: func(ctx context.Context, input map[string]interface{}) (worker.Job, error) {
+			newJob, err := jobs.NewEnvGroupRollout(ctx, dbConn, time.Now().UTC(), &jobs.EnvGroupRolloutOpts{
+				DBConf:                     &envDecoder.DBConf,
+				ServerURL:                  envDecoder.ServerURL,
+				DOClientID:                 envDecoder.DOClientID,
+				DOClientSecret:             envDecoder.DOClientSecret,
+				DOScopes:                   []string{"read", "write"},
+				ClusterControlPlaneAddress: envDecoder.ClusterControlPlaneAddress,
+				Input:                      input,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating job with ID: env-group-rollout. Error: %w", err)
+			}
+
+			return newJob, nil
+		},
+	},
+}
+
+// isKnownJob returns true if a job with the given ID can be enqueued
+func isKnownJob(id string) bool {
+	_, ok := workerJobs[id]
+	return ok
+}
+
+// retryableJobMaxAttempts returns the number of attempts of each job which is safe to retry
+func retryableJobMaxAttempts(maxAttempts int) map[string]int {
+	res := make(map[string]int)
+	for id, job := range workerJobs {
+		if job.retryable {
+			res[id] = maxAttempts
+		}
+	}
+
+	return res
+}
+
+// getJob builds the job with the given ID. It is used by the persistent queue each time a run is attempted.
+func getJob(ctx context.Context, id string, input map[string]interface{}) (worker.Job, error) {
+	job, ok := workerJobs[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown job ID: %s", id)
 	}
 
-	return nil
+	return job.newJob(ctx, input)
 }